@@ -0,0 +1,48 @@
+package action
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// AnsibleClientSuffix is the suffix ansible requires for the names of
+	// vault password client scripts. gopass acts as one if its binary is
+	// named like that, e.g. by using a symlink.
+	AnsibleClientSuffix = "-client"
+
+	ansibleDefaultVaultID = "default"
+)
+
+// AnsibleVaultClient implements the ansible vault password client script
+// protocol. It prints the password of the secret for the requested vault id.
+func (s *Action) AnsibleVaultClient(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	id := c.String("vault-id")
+	if id == "" {
+		id = ansibleDefaultVaultID
+	}
+	name := path.Join(c.String("prefix"), id)
+	debug.Log("looking up vault id %q in %s", id, name)
+
+	// ansible expects exit code 2 (ExitUsage) if the vault id is unknown
+	if !s.Store.Exists(ctx, name) {
+		return ExitError(ExitUsage, nil, "no vault password for vault id %q (%s)", id, name)
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+	}
+	if sec.Password() == "" {
+		return ExitError(ExitNotFound, nil, "%s has an empty password", name)
+	}
+
+	fmt.Fprintln(stdout, sec.Password())
+	return nil
+}
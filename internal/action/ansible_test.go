@@ -0,0 +1,71 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestAnsibleVaultClient(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	require.NoError(t, act.Store.Set(ctx, "ansible/vault/dev", secrets.NewKVWithData("devpw", nil, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "ansible/vault/default", secrets.NewKVWithData("defaultpw", nil, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "infra/vault/prod", secrets.NewKVWithData("prodpw", nil, "", false)))
+
+	t.Run("vault id", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.AnsibleVaultClient(gptest.CliCtxWithFlags(ctx, t, map[string]string{"vault-id": "dev", "prefix": "ansible/vault"})))
+		assert.Equal(t, "devpw\n", buf.String())
+	})
+
+	t.Run("default vault id", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.AnsibleVaultClient(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "ansible/vault"})))
+		assert.Equal(t, "defaultpw\n", buf.String())
+	})
+
+	t.Run("custom prefix", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.AnsibleVaultClient(gptest.CliCtxWithFlags(ctx, t, map[string]string{"vault-id": "prod", "prefix": "infra/vault"})))
+		assert.Equal(t, "prodpw\n", buf.String())
+	})
+
+	t.Run("unknown vault id", func(t *testing.T) {
+		err := act.AnsibleVaultClient(gptest.CliCtxWithFlags(ctx, t, map[string]string{"vault-id": "nope", "prefix": "ansible/vault"}))
+		require.Error(t, err)
+
+		var ec cli.ExitCoder
+		require.ErrorAs(t, err, &ec)
+		assert.Equal(t, 2, ec.ExitCode())
+	})
+}
@@ -41,5 +41,7 @@ func (s *Action) Audit(c *cli.Context) error {
 		return nil
 	}
 
+	s.Store.TryUnlock(ctx)
+
 	return audit.Batch(ctx, list, s.Store, expiry)
 }
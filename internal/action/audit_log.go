@@ -0,0 +1,139 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/auditlog"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// withAuditLog wraps the actions of all commands so that every invocation is
+// recorded in the audit log, if enabled.
+func (s *Action) withAuditLog(prefix string, cmds []*cli.Command) []*cli.Command {
+	for _, cmd := range cmds {
+		name := strings.TrimSpace(prefix + " " + cmd.Name)
+		if len(cmd.Subcommands) > 0 {
+			s.withAuditLog(name, cmd.Subcommands)
+		}
+		if cmd.Action == nil {
+			continue
+		}
+
+		cmd.Action = s.AuditLogged(name, cmd.Action)
+	}
+
+	return cmds
+}
+
+// AuditLogged wraps an action so that its invocations are recorded in the
+// audit log as command, if enabled. It's used for actions that are not
+// commands, e.g. the default action of gopass <name>.
+func (s *Action) AuditLogged(command string, action cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if !s.cfg.AuditLog {
+			return action(c)
+		}
+
+		ctx := ctxutil.WithGlobalFlags(c)
+		secret := s.auditLogName(ctx, c.Args().First())
+		err := action(c)
+		if secret == "" {
+			// the secret might have been created by this command.
+			secret = s.auditLogName(ctx, c.Args().First())
+		}
+
+		if lerr := auditlog.Append(auditlog.Path(), command, secret, err != nil); lerr != nil {
+			out.Warningf(ctx, "Failed to write audit log: %s", lerr)
+		}
+		return err
+	}
+}
+
+// auditLogName returns the argument if it is a secret or folder. Other
+// arguments are never logged, they might contain sensitive data.
+func (s *Action) auditLogName(ctx context.Context, arg string) string {
+	if arg == "" {
+		return ""
+	}
+	if s.Store.Exists(ctx, arg) || s.Store.IsDir(ctx, arg) {
+		return arg
+	}
+	return ""
+}
+
+// AuditLogShow prints all entries of the audit log.
+func (s *Action) AuditLogShow(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	entries, err := s.readAuditLog(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		line := e.Time.Local().Format("2006-01-02 15:04:05") + " " + e.Command
+		if e.Name != "" {
+			line += " " + e.Name
+		}
+		if e.Failed {
+			line += " (failed)"
+		}
+		out.Printf(ctx, "%s", line)
+	}
+
+	return nil
+}
+
+// AuditLogVerify checks that the audit log has not been tampered with.
+func (s *Action) AuditLogVerify(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	entries, err := s.readAuditLog(ctx)
+	if err != nil {
+		return err
+	}
+
+	n, err := auditlog.Verify(entries)
+	if err != nil {
+		return ExitError(ExitAudit, err, "Audit log is broken after %d intact entries: %s", n, err)
+	}
+	if n < 1 {
+		return nil
+	}
+
+	out.OKf(ctx, "All %d entries are intact", n)
+	out.Printf(ctx, "Last hash: %s", entries[n-1].Hash)
+
+	return nil
+}
+
+func (s *Action) readAuditLog(ctx context.Context) ([]auditlog.Entry, error) {
+	fn := auditlog.Path()
+
+	fh, err := os.Open(filepath.Clean(fn))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			if !s.cfg.AuditLog {
+				out.Noticef(ctx, "The audit log is disabled. Run '%s config auditlog true' to enable it", s.Name)
+			} else {
+				out.Noticef(ctx, "The audit log is empty")
+			}
+			return nil, nil
+		}
+		return nil, ExitError(ExitIO, err, "failed to open audit log %s: %s", fn, err)
+	}
+	defer fh.Close() //nolint:errcheck
+
+	entries, err := auditlog.Read(fh)
+	if err != nil {
+		return nil, ExitError(ExitAudit, err, "failed to read audit log %s: %s", fn, err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,106 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/auditlog"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestAuditLog(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+	}()
+
+	fn := filepath.Join(t.TempDir(), "audit.log")
+	t.Setenv("GOPASS_AUDIT_LOG", fn)
+
+	find := func(cmds []*cli.Command, names ...string) *cli.Command {
+		var cmd *cli.Command
+		for _, name := range names {
+			for _, c := range cmds {
+				if c.Name == name {
+					cmd = c
+					break
+				}
+			}
+			require.NotNil(t, cmd, name)
+			cmds = cmd.Subcommands
+		}
+		return cmd
+	}
+	cmds := act.GetCommands()
+
+	t.Run("disabled", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, find(cmds, "show").Action(gptest.CliCtx(ctx, t, "foo")))
+		assert.NoFileExists(t, fn)
+
+		require.NoError(t, find(cmds, "audit-log", "show").Action(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "disabled")
+	})
+
+	act.cfg.AuditLog = true
+
+	t.Run("log", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, find(cmds, "show").Action(gptest.CliCtx(ctx, t, "foo")))
+		assert.Error(t, find(cmds, "show").Action(gptest.CliCtx(ctx, t, "not-there")))
+		require.NoError(t, find(cmds, "recipients", "migrate").Action(gptest.CliCtx(ctx, t)))
+		// the default action, i.e. gopass foo
+		require.NoError(t, act.AuditLogged("show", act.Show)(gptest.CliCtx(ctx, t, "foo")))
+
+		buf.Reset()
+		require.NoError(t, find(cmds, "audit-log", "show").Action(gptest.CliCtx(ctx, t)))
+		assert.Equal(t, 2, strings.Count(buf.String(), " show foo\n"))
+		assert.Contains(t, buf.String(), " show (failed)\n")
+		assert.Contains(t, buf.String(), " recipients migrate\n")
+		assert.NotContains(t, buf.String(), "not-there")
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.AuditLogVerify(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "entries are intact")
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		defer buf.Reset()
+		content, err := os.ReadFile(fn)
+		require.NoError(t, err)
+		content = bytes.Replace(content, []byte(`"name":"foo"`), []byte(`"name":"bar"`), 1)
+		require.NoError(t, os.WriteFile(fn, content, 0o600))
+
+		assert.Error(t, act.AuditLogVerify(gptest.CliCtx(ctx, t)))
+		entries, err := auditlog.Read(bytes.NewReader(content))
+		require.NoError(t, err)
+		_, err = auditlog.Verify(entries)
+		assert.ErrorIs(t, err, auditlog.ErrTampered)
+	})
+}
@@ -0,0 +1,106 @@
+package action
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/diff"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// AuditRecipients prints which recipients can read the secrets in each folder
+// and reports secrets whose ciphertext doesn't match the recipients file,
+// e.g. because they are still encrypted for removed team members.
+func (s *Action) AuditRecipients(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	type folder struct {
+		recipients []string
+		secrets    int
+	}
+	folders := make(map[string]*folder)
+	var stale, hidden int
+
+	mps := append([]string{""}, s.Store.MountPoints()...)
+	for _, mp := range mps {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil {
+			return ExitError(ExitMount, err, "failed to get store %s: %s", mp, err)
+		}
+		crypto := sub.Crypto()
+		if crypto == nil {
+			continue
+		}
+
+		acc, err := sub.Access(ctx, "")
+		if err != nil {
+			return ExitError(ExitRecipients, err, "failed to read recipients of %s: %s", mp, err)
+		}
+
+		for _, a := range acc {
+			fn := path.Join(mp, a.Folder) + "/"
+			f, found := folders[fn]
+			if !found {
+				f = &folder{}
+				for _, r := range a.Recipients {
+					f.recipients = append(f.recipients, crypto.FormatKey(ctx, r, ""))
+				}
+				folders[fn] = f
+			}
+			f.secrets++
+
+			if a.Hidden {
+				hidden++
+				continue
+			}
+
+			extra, missing := diff.List(a.Recipients, a.Readers)
+			if len(extra) > 0 {
+				out.Warningf(ctx, "%s is still readable by removed recipients: %s", a.Name, formatKeys(ctx, crypto, extra))
+			}
+			if len(missing) > 0 {
+				out.Warningf(ctx, "%s is not readable by: %s", a.Name, formatKeys(ctx, crypto, missing))
+			}
+			if len(extra) > 0 || len(missing) > 0 {
+				stale++
+			}
+		}
+	}
+
+	names := make([]string, 0, len(folders))
+	for fn := range folders {
+		names = append(names, fn)
+	}
+	sort.Strings(names)
+
+	for _, fn := range names {
+		f := folders[fn]
+		out.Printf(ctx, "%s (%d secrets)", fn, f.secrets)
+		for _, r := range f.recipients {
+			out.Printf(ctx, "  - %s", r)
+		}
+	}
+
+	if hidden > 0 {
+		out.Noticef(ctx, "%d secrets hide their recipients and were not checked", hidden)
+	}
+	if stale > 0 {
+		return ExitError(ExitAudit, nil, "%d secrets need to be re-encrypted. Run 'gopass fsck --decrypt' to fix them", stale)
+	}
+
+	return nil
+}
+
+func formatKeys(ctx context.Context, crypto backend.Crypto, ids []string) string {
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, crypto.FormatKey(ctx, id, ""))
+	}
+
+	return strings.Join(res, ", ")
+}
@@ -58,3 +58,43 @@ func TestAudit(t *testing.T) {
 		buf.Reset()
 	})
 }
+
+func TestAuditRecipients(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	sub, err := act.Store.GetSubStore("")
+	require.NoError(t, err)
+	idf := sub.Crypto().IDFile()
+
+	t.Run("removed recipient", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, sub.Storage().Set(ctx, idf, []byte("0xDEADBEEF\n")))
+
+		assert.Error(t, act.AuditRecipients(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "/ (1 secrets)")
+		assert.Contains(t, buf.String(), "foo is still readable by removed recipients: 0xFEEDBEEF")
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, sub.Storage().Set(ctx, idf, []byte("0xDEADBEEF\n0xFEEDBEEF\n")))
+
+		assert.NoError(t, act.AuditRecipients(gptest.CliCtx(ctx, t)))
+		assert.NotContains(t, buf.String(), "readable")
+	})
+}
@@ -16,13 +16,34 @@ import (
 	"github.com/gopasspw/gopass/pkg/fsutil"
 	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/klauspost/compress/zstd"
 	"github.com/urfave/cli/v2"
 )
 
+const (
+	cteBase64           = "Base64"
+	cteChunked          = "Chunked"
+	contentEncodingZstd = "zstd"
+)
+
 var (
 	binstdin = os.Stdin
 )
 
+// binaryOpts controls how binary content is written to the store. Reading
+// detects the format from the headers of the secret.
+type binaryOpts struct {
+	compress bool
+	dedup    bool
+}
+
+func binaryOptsFromFlags(c *cli.Context) binaryOpts {
+	return binaryOpts{
+		compress: c.Bool("compress"),
+		dedup:    c.Bool("dedup"),
+	}
+}
+
 // Cat prints to or reads from STDIN/STDOUT.
 func (s *Action) Cat(c *cli.Context) error {
 	ctx := ctxutil.WithGlobalFlags(c)
@@ -46,11 +67,10 @@ func (s *Action) Cat(c *cli.Context) error {
 			return ExitError(ExitIO, err, "Failed to copy after %d bytes: %s", written, err)
 		}
 
-		return s.Store.Set(
-			ctxutil.WithCommitMessage(ctx, "Read secret from STDIN"),
-			name,
-			secFromBytes(name, "STDIN", content.Bytes()),
-		)
+		if err := s.binarySet(ctxutil.WithCommitMessage(ctx, "Read secret from STDIN"), name, "STDIN", content.Bytes(), binaryOptsFromFlags(c)); err != nil {
+			return ExitError(ExitEncrypt, err, "failed to save secret: %s", err)
+		}
+		return nil
 	}
 
 	buf, err := s.binaryGet(ctx, name)
@@ -66,18 +86,93 @@ func secFromBytes(dst, src string, in []byte) gopass.Secret {
 	debug.Log("Read %d bytes from %s to %s", len(in), src, dst)
 
 	sec := secrets.NewKV()
-	if err := sec.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filepath.Base(src))); err != nil {
-		debug.Log("Failed to set Content-Disposition: %q", err)
-	}
+	setBinaryHeader(sec, "Content-Disposition", contentDisposition(src))
 
 	sec.Write([]byte(base64.StdEncoding.EncodeToString(in)))
-	if err := sec.Set("Content-Transfer-Encoding", "Base64"); err != nil {
-		debug.Log("Failed to set Content-Transfer-Encoding: %q", err)
-	}
+	setBinaryHeader(sec, "Content-Transfer-Encoding", cteBase64)
 
 	return sec
 }
 
+func contentDisposition(src string) string {
+	return fmt.Sprintf("attachment; filename=\"%s\"", filepath.Base(src))
+}
+
+func setBinaryHeader(sec gopass.Secret, key, value string) {
+	if err := sec.Set(key, value); err != nil {
+		debug.Log("Failed to set %s: %q", key, err)
+	}
+}
+
+// binarySet writes the content to the store. Depending on the options it is
+// compressed and / or split into deduplicated chunks first.
+func (s *Action) binarySet(ctx context.Context, name, src string, buf []byte, opts binaryOpts) error {
+	if opts.dedup {
+		return s.binarySetChunked(ctx, name, src, buf, opts.compress)
+	}
+	if !opts.compress {
+		return s.Store.Set(ctx, name, secFromBytes(name, src, buf))
+	}
+
+	debug.Log("Read %d bytes from %s to %s", len(buf), src, name)
+	sec, err := binaryEncode(buf, true)
+	if err != nil {
+		return err
+	}
+	setBinaryHeader(sec, "Content-Disposition", contentDisposition(src))
+	return s.Store.Set(ctx, name, sec)
+}
+
+// binaryEncode returns a secret with the Base64 encoded and optionally
+// compressed content.
+func binaryEncode(buf []byte, compress bool) (gopass.Secret, error) {
+	sec := secrets.NewKV()
+	if compress {
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize compression: %w", err)
+		}
+		buf = enc.EncodeAll(buf, nil)
+		_ = enc.Close()
+		setBinaryHeader(sec, "Content-Encoding", contentEncodingZstd)
+	}
+	sec.Write([]byte(base64.StdEncoding.EncodeToString(buf)))
+	setBinaryHeader(sec, "Content-Transfer-Encoding", cteBase64)
+	return sec, nil
+}
+
+// binaryDecode returns the decoded content of a secret written by
+// binaryEncode or secFromBytes. Secrets without a known transfer encoding
+// are returned as is.
+func binaryDecode(sec gopass.Secret) ([]byte, error) {
+	if cte, _ := sec.Get("content-transfer-encoding"); cte != cteBase64 {
+		return []byte(sec.Body()), nil
+	}
+
+	buf, err := base64.StdEncoding.DecodeString(sec.Body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode to base64: %w", err)
+	}
+
+	switch ce, _ := sec.Get("content-encoding"); ce {
+	case "":
+		return buf, nil
+	case contentEncodingZstd:
+		dec, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize decompression: %w", err)
+		}
+		defer dec.Close()
+		buf, err = dec.DecodeAll(buf, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", ce)
+	}
+}
+
 // BinaryCopy copies either from the filesystem to the store or from the store.
 // to the filesystem.
 func (s *Action) BinaryCopy(c *cli.Context) error {
@@ -86,7 +181,7 @@ func (s *Action) BinaryCopy(c *cli.Context) error {
 	to := c.Args().Get(1)
 
 	// argument checking is in s.binaryCopy.
-	if err := s.binaryCopy(ctx, c, from, to, false); err != nil {
+	if err := s.binaryCopy(ctx, c, from, to, false, binaryOptsFromFlags(c)); err != nil {
 		return ExitError(ExitUnknown, err, "%s", err)
 	}
 	return nil
@@ -101,7 +196,7 @@ func (s *Action) BinaryMove(c *cli.Context) error {
 	to := c.Args().Get(1)
 
 	// argument checking is in s.binaryCopy.
-	if err := s.binaryCopy(ctx, c, from, to, true); err != nil {
+	if err := s.binaryCopy(ctx, c, from, to, true, binaryOptsFromFlags(c)); err != nil {
 		return ExitError(ExitUnknown, err, "%s", err)
 	}
 	return nil
@@ -113,7 +208,7 @@ func (s *Action) BinaryMove(c *cli.Context) error {
 // 2. From the store to the filesystem.
 //
 // Copying secrets in the store must be done through the regular copy command.
-func (s *Action) binaryCopy(ctx context.Context, c *cli.Context, from, to string, deleteSource bool) error {
+func (s *Action) binaryCopy(ctx context.Context, c *cli.Context, from, to string, deleteSource bool, opts binaryOpts) error {
 	if from == "" || to == "" {
 		op := "copy"
 		if deleteSource {
@@ -130,7 +225,7 @@ func (s *Action) binaryCopy(ctx context.Context, c *cli.Context, from, to string
 		// copying from one secret to another secret is not supported.
 		return fmt.Errorf("ambiguity detected. Either from or to must be a file")
 	case fsutil.IsFile(from) && !fsutil.IsFile(to):
-		return s.binaryCopyFromFileToStore(ctx, from, to, deleteSource)
+		return s.binaryCopyFromFileToStore(ctx, from, to, deleteSource, opts)
 	case !fsutil.IsFile(from):
 		return s.binaryCopyFromStoreToFile(ctx, from, to, deleteSource)
 	default:
@@ -138,7 +233,7 @@ func (s *Action) binaryCopy(ctx context.Context, c *cli.Context, from, to string
 	}
 }
 
-func (s *Action) binaryCopyFromFileToStore(ctx context.Context, from, to string, deleteSource bool) error {
+func (s *Action) binaryCopyFromFileToStore(ctx context.Context, from, to string, deleteSource bool, opts binaryOpts) error {
 	// if the source is a file the destination must not to avoid ambiguities.
 	// if necessary this can be resolved by using a absolute path for the file
 	// and a relative one for the secret.
@@ -149,8 +244,8 @@ func (s *Action) binaryCopyFromFileToStore(ctx context.Context, from, to string,
 		return fmt.Errorf("failed to read file from %q: %w", from, err)
 	}
 
-	if err := s.Store.Set(
-		ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Copied data from %s to %s", from, to)), to, secFromBytes(to, from, buf)); err != nil {
+	if err := s.binarySet(
+		ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Copied data from %s to %s", from, to)), to, from, buf, opts); err != nil {
 		return fmt.Errorf("failed to save buffer to store: %w", err)
 	}
 
@@ -194,6 +289,11 @@ func (s *Action) binaryCopyFromStoreToFile(ctx context.Context, from, to string,
 	if err := s.Store.Delete(ctx, from); err != nil {
 		return fmt.Errorf("failed to delete %q from the store: %w", from, err)
 	}
+	if s.Store.IsDir(ctx, from+chunkDirSuffix) {
+		if err := s.Store.Prune(ctx, from+chunkDirSuffix); err != nil {
+			return fmt.Errorf("failed to delete the chunks of %q from the store: %w", from, err)
+		}
+	}
 	return nil
 }
 
@@ -227,15 +327,11 @@ func (s *Action) binaryGet(ctx context.Context, name string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to read %q from the store: %w", name, err)
 	}
 
-	if cte, _ := sec.Get("content-transfer-encoding"); cte != "Base64" {
-		return []byte(sec.Body()), nil
+	if cte, _ := sec.Get("content-transfer-encoding"); cte == cteChunked {
+		return s.binaryGetChunked(ctx, sec)
 	}
 
-	buf, err := base64.StdEncoding.DecodeString(sec.Body())
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode to base64: %w", err)
-	}
-	return buf, nil
+	return binaryDecode(sec)
 }
 
 // Sum decodes binary content and computes the SHA256 checksum.
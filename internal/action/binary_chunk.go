@@ -0,0 +1,238 @@
+package action
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+)
+
+const (
+	// chunkMin, chunkMax and chunkMask control the content defined chunking.
+	// The mask yields an average chunk size of 64 KiB.
+	chunkMin  = 16 * 1024
+	chunkMax  = 256 * 1024
+	chunkMask = 1<<16 - 1
+
+	// chunkSaltLen is the length of the per-secret key used to derive the
+	// chunk IDs.
+	chunkSaltLen = 32
+	// chunkIDLen is the length of the hex encoded chunk IDs.
+	chunkIDLen = 32
+
+	chunkDirSuffix = ".chunks"
+)
+
+// chunkGear is the lookup table for the gear rolling hash. It must never
+// change since that would change all chunk boundaries and defeat the
+// deduplication of existing secrets.
+var chunkGear = func() [256]uint64 {
+	var tbl [256]uint64
+	// splitmix64 with a fixed seed.
+	x := uint64(0x676f70617373) // "gopass"
+	for i := range tbl {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		tbl[i] = z ^ (z >> 31)
+	}
+	return tbl
+}()
+
+// chunkData splits buf into content defined chunks. Inserting or removing
+// data only changes the chunks around the modification, so most chunks of a
+// slightly modified file stay the same.
+func chunkData(buf []byte) [][]byte {
+	var chunks [][]byte
+	for len(buf) > 0 {
+		n := chunkBoundary(buf)
+		chunks = append(chunks, buf[:n])
+		buf = buf[n:]
+	}
+	return chunks
+}
+
+// chunkBoundary returns the length of the first chunk of buf.
+func chunkBoundary(buf []byte) int {
+	if len(buf) <= chunkMin {
+		return len(buf)
+	}
+	end := len(buf)
+	if end > chunkMax {
+		end = chunkMax
+	}
+
+	var h uint64
+	for i := chunkMin; i < end; i++ {
+		h = (h << 1) + chunkGear[buf[i]]
+		if h&chunkMask == 0 {
+			return i + 1
+		}
+	}
+	return end
+}
+
+// chunkID returns the keyed hash of a chunk. The key is random per secret so
+// the IDs do not reveal whether a secret contains some known content.
+func chunkID(salt, chunk []byte) string {
+	mac := hmac.New(sha256.New, salt)
+	_, _ = mac.Write(chunk)
+	return hex.EncodeToString(mac.Sum(nil))[:chunkIDLen]
+}
+
+// chunkManifest describes a secret that was split into chunks.
+type chunkManifest struct {
+	dir      string
+	salt     []byte
+	compress bool
+	ids      []string
+}
+
+// parseChunkManifest reads the manifest from a secret written by
+// binarySetChunked.
+func parseChunkManifest(sec gopass.Secret) (chunkManifest, error) {
+	m := chunkManifest{}
+
+	dir, _ := sec.Get("chunk-dir")
+	if dir == "" {
+		return m, fmt.Errorf("missing chunk directory")
+	}
+	m.dir = dir
+
+	salt, _ := sec.Get("chunk-salt")
+	buf, err := hex.DecodeString(salt)
+	if err != nil || len(buf) != chunkSaltLen {
+		return m, fmt.Errorf("invalid chunk salt")
+	}
+	m.salt = buf
+
+	if ce, _ := sec.Get("content-encoding"); ce == contentEncodingZstd {
+		m.compress = true
+	}
+
+	for _, line := range strings.Split(sec.Body(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		m.ids = append(m.ids, line)
+	}
+	return m, nil
+}
+
+// binarySetChunked splits buf into chunks and stores every chunk as a secret
+// of its own below <name>.chunks. Chunks that already exist are not written
+// again so updating a large secret only adds the changed chunks to the
+// history. The secret itself only lists the chunks.
+func (s *Action) binarySetChunked(ctx context.Context, name, src string, buf []byte, compress bool) error {
+	m := chunkManifest{
+		dir:      name + chunkDirSuffix,
+		compress: compress,
+	}
+
+	// keep the key of an existing secret, otherwise no chunk could be reused.
+	var oldIDs []string
+	if sec, err := s.Store.Get(ctx, name); err == nil {
+		if cte, _ := sec.Get("content-transfer-encoding"); cte == cteChunked {
+			if old, err := parseChunkManifest(sec); err == nil && old.dir == m.dir {
+				m.salt = old.salt
+				oldIDs = old.ids
+			}
+		}
+	}
+	if m.salt == nil {
+		m.salt = make([]byte, chunkSaltLen)
+		if _, err := rand.Read(m.salt); err != nil {
+			return fmt.Errorf("failed to generate chunk salt: %w", err)
+		}
+	}
+
+	// all changes are committed together with the manifest.
+	cctx := ctxutil.WithGitCommit(ctx, false)
+
+	var written int
+	for _, chunk := range chunkData(buf) {
+		id := chunkID(m.salt, chunk)
+		m.ids = append(m.ids, id)
+
+		cn := m.dir + "/" + id
+		if s.Store.Exists(cctx, cn) {
+			continue
+		}
+		sec, err := binaryEncode(chunk, compress)
+		if err != nil {
+			return err
+		}
+		if err := s.Store.Set(cctx, cn, sec); err != nil {
+			return fmt.Errorf("failed to write chunk %q: %w", cn, err)
+		}
+		written++
+	}
+	debug.Log("Wrote %d of %d chunks of %s", written, len(m.ids), name)
+
+	used := make(map[string]bool, len(m.ids))
+	for _, id := range m.ids {
+		used[id] = true
+	}
+	for _, id := range oldIDs {
+		if used[id] {
+			continue
+		}
+		used[id] = true
+		if err := s.Store.Delete(cctx, m.dir+"/"+id); err != nil {
+			debug.Log("Failed to remove unused chunk %s: %s", id, err)
+		}
+	}
+
+	return s.Store.Set(ctx, name, m.secret(src))
+}
+
+// secret returns the secret holding the manifest.
+func (m chunkManifest) secret(src string) gopass.Secret {
+	sec := secrets.NewKV()
+	setBinaryHeader(sec, "Content-Disposition", contentDisposition(src))
+	setBinaryHeader(sec, "Content-Transfer-Encoding", cteChunked)
+	if m.compress {
+		setBinaryHeader(sec, "Content-Encoding", contentEncodingZstd)
+	}
+	setBinaryHeader(sec, "Chunk-Dir", m.dir)
+	setBinaryHeader(sec, "Chunk-Salt", hex.EncodeToString(m.salt))
+	sec.Write([]byte(strings.Join(m.ids, "\n")))
+	return sec
+}
+
+// binaryGetChunked reassembles the content from the chunks listed in the
+// manifest.
+func (s *Action) binaryGetChunked(ctx context.Context, sec gopass.Secret) ([]byte, error) {
+	m, err := parseChunkManifest(sec)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	for _, id := range m.ids {
+		cn := m.dir + "/" + id
+		csec, err := s.Store.Get(ctx, cn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chunk %q: %w", cn, err)
+		}
+		chunk, err := binaryDecode(csec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode chunk %q: %w", cn, err)
+		}
+		if chunkID(m.salt, chunk) != id {
+			return nil, fmt.Errorf("chunk %q is corrupted", cn)
+		}
+		buf = append(buf, chunk...)
+	}
+	return buf, nil
+}
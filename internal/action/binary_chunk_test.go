@@ -0,0 +1,141 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkData(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(42))
+	buf := make([]byte, 2*1024*1024)
+	_, _ = rnd.Read(buf)
+
+	chunks := chunkData(buf)
+	require.Greater(t, len(chunks), 1)
+	assert.Equal(t, buf, bytes.Join(chunks, nil))
+	for i, c := range chunks {
+		assert.LessOrEqual(t, len(c), chunkMax)
+		if i < len(chunks)-1 {
+			assert.GreaterOrEqual(t, len(c), chunkMin)
+		}
+	}
+
+	// inserting data must only change the chunks around the insertion.
+	mod := append(append(append([]byte{}, buf[:1024*1024]...), []byte("inserted")...), buf[1024*1024:]...)
+	before := make(map[string]bool, len(chunks))
+	for _, c := range chunks {
+		before[string(c)] = true
+	}
+	var changed int
+	for _, c := range chunkData(mod) {
+		if !before[string(c)] {
+			changed++
+		}
+	}
+	assert.LessOrEqual(t, changed, 2)
+
+	assert.Nil(t, chunkData(nil))
+	assert.Equal(t, [][]byte{[]byte("foo")}, chunkData([]byte("foo")))
+}
+
+func TestChunkID(t *testing.T) {
+	t.Parallel()
+
+	a := chunkID([]byte("salt"), []byte("chunk"))
+	assert.Len(t, a, chunkIDLen)
+	assert.Equal(t, a, chunkID([]byte("salt"), []byte("chunk")))
+	assert.NotEqual(t, a, chunkID([]byte("other"), []byte("chunk")))
+}
+
+func TestBinaryDedup(t *testing.T) { //nolint:paralleltest
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	rnd := rand.New(rand.NewSource(42))
+	data := make([]byte, 1024*1024)
+	_, _ = rnd.Read(data)
+
+	chunkNames := func() []string {
+		names, err := act.Store.List(ctx, tree.INF)
+		require.NoError(t, err)
+		var cn []string
+		for _, n := range names {
+			if strings.HasPrefix(n, "bundle"+chunkDirSuffix+"/") {
+				cn = append(cn, n)
+			}
+		}
+		return cn
+	}
+
+	for _, compress := range []bool{false, true} {
+		require.NoError(t, act.binarySet(ctx, "bundle", "bundle.p12", data, binaryOpts{dedup: true, compress: compress}))
+
+		got, err := act.binaryGet(ctx, "bundle")
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	}
+
+	sec, err := act.Store.Get(ctx, "bundle")
+	require.NoError(t, err)
+	m, err := parseChunkManifest(sec)
+	require.NoError(t, err)
+	assert.True(t, m.compress)
+	assert.Len(t, chunkNames(), len(m.ids))
+
+	// a small modification keeps the salt and most of the chunks.
+	mod := append([]byte{}, data...)
+	copy(mod[512*1024:], "modified")
+	require.NoError(t, act.binarySet(ctx, "bundle", "bundle.p12", mod, binaryOpts{dedup: true, compress: true}))
+
+	sec, err = act.Store.Get(ctx, "bundle")
+	require.NoError(t, err)
+	m2, err := parseChunkManifest(sec)
+	require.NoError(t, err)
+	assert.Equal(t, m.salt, m2.salt)
+	assert.Len(t, chunkNames(), len(m2.ids), "unused chunks are removed")
+
+	var reused int
+	for i := range m2.ids {
+		if i < len(m.ids) && m.ids[i] == m2.ids[i] {
+			reused++
+		}
+	}
+	assert.GreaterOrEqual(t, reused, len(m2.ids)-1)
+
+	got, err := act.binaryGet(ctx, "bundle")
+	require.NoError(t, err)
+	assert.Equal(t, mod, got)
+
+	// moving the secret out of the store removes the chunks as well.
+	outfile := u.Dir + "/bundle.p12"
+	require.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), "bundle", outfile, true, binaryOpts{}))
+	assert.False(t, act.Store.Exists(ctx, "bundle"))
+	assert.Len(t, chunkNames(), 0)
+}
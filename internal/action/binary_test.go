@@ -63,7 +63,7 @@ func TestBinaryCat(t *testing.T) {
 	writeBinfile(t, infile)
 
 	t.Run("populate store", func(t *testing.T) {
-		assert.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), infile, "bar", true))
+		assert.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), infile, "bar", true, binaryOpts{}))
 	})
 
 	t.Run("binary cat bar", func(t *testing.T) {
@@ -117,7 +117,7 @@ func TestBinaryCopy(t *testing.T) {
 
 		infile := filepath.Join(u.Dir, "input.txt")
 		assert.NoError(t, os.WriteFile(infile, []byte("0xDEADBEEF\n"), 0644))
-		assert.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), infile, "txt", true))
+		assert.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), infile, "txt", true, binaryOpts{}))
 	})
 
 	infile := filepath.Join(u.Dir, "input.raw")
@@ -126,7 +126,7 @@ func TestBinaryCopy(t *testing.T) {
 		defer buf.Reset()
 
 		writeBinfile(t, infile)
-		assert.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), infile, "bar", true))
+		assert.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), infile, "bar", true, binaryOpts{}))
 	})
 
 	t.Run("binary copy bar tempdir/bar", func(t *testing.T) {
@@ -178,7 +178,7 @@ func TestBinarySum(t *testing.T) {
 
 	t.Run("populate store", func(t *testing.T) {
 		writeBinfile(t, infile)
-		assert.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), infile, "bar", true))
+		assert.NoError(t, act.binaryCopy(ctx, gptest.CliCtx(ctx, t), infile, "bar", true, binaryOpts{}))
 	})
 
 	t.Run("binary sum bar", func(t *testing.T) {
@@ -187,6 +187,43 @@ func TestBinarySum(t *testing.T) {
 	})
 }
 
+func TestBinaryCompress(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	data := bytes.Repeat([]byte("apiVersion: v1\nkind: Config\n"), 1024)
+	require.NoError(t, act.binarySet(ctx, "kubeconfig", "config", data, binaryOpts{compress: true}))
+
+	sec, err := act.Store.Get(ctx, "kubeconfig")
+	require.NoError(t, err)
+	ce, _ := sec.Get("content-encoding")
+	assert.Equal(t, contentEncodingZstd, ce)
+	assert.Less(t, len(sec.Body()), len(data)/10)
+
+	got, err := act.binaryGet(ctx, "kubeconfig")
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	require.NoError(t, sec.Set("content-encoding", "lz4"))
+	require.NoError(t, act.Store.Set(ctx, "kubeconfig", sec))
+	_, err = act.binaryGet(ctx, "kubeconfig")
+	assert.Error(t, err)
+}
+
 func writeBinfile(t *testing.T, fn string) {
 	// tests should be predicable
 	rand.Seed(42)
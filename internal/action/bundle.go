@@ -0,0 +1,221 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/bundle"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+type keyImporter interface {
+	ImportPublicKey(ctx context.Context, buf []byte) error
+}
+
+// BundleCreate writes the given secrets and the public keys of their
+// recipients to a signed bundle that is encrypted for the given recipients.
+func (s *Action) BundleCreate(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	if !c.Args().Present() {
+		return ExitError(ExitUsage, nil, "Usage: %s bundle create --recipient <key> <secret|folder>...", s.Name)
+	}
+	recipients := c.StringSlice("recipient")
+	if len(recipients) < 1 {
+		return ExitError(ExitUsage, nil, "At least one --recipient is required")
+	}
+
+	crypto := s.Store.Crypto(ctx, "")
+	signer, ok := crypto.(backend.Signer)
+	if !ok {
+		return ExitError(ExitUnsupported, nil, "Crypto backend %s can not sign bundles", crypto.Name())
+	}
+
+	rs, err := crypto.FindRecipients(ctx, recipients...)
+	if err != nil {
+		return ExitError(ExitRecipients, err, "failed to find recipients: %s", err)
+	}
+	if len(rs) < len(recipients) {
+		return ExitError(ExitRecipients, nil, "failed to find all recipients of %s", strings.Join(recipients, ", "))
+	}
+
+	b := bundle.New()
+	for _, arg := range c.Args().Slice() {
+		names, err := s.expandSecrets(ctx, arg)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			sec, err := s.Store.Get(ctx, name)
+			if err != nil {
+				return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+			}
+			b.Secrets[name] = sec.Bytes()
+
+			if err := s.bundleKeys(ctx, b, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	buf, err := bundle.Seal(ctx, crypto, signer, b, rs)
+	if err != nil {
+		return ExitError(ExitEncrypt, err, "failed to create bundle: %s", err)
+	}
+
+	fn := c.String("output")
+	if fn == "" || fn == "-" {
+		if _, err := stdout.Write(buf); err != nil {
+			return ExitError(ExitIO, err, "failed to write bundle: %s", err)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(fn, buf, 0o600); err != nil {
+		return ExitError(ExitIO, err, "failed to write bundle to %s: %s", fn, err)
+	}
+	out.OKf(ctx, "Wrote %d secrets and %d keys to %s", len(b.Secrets), len(b.Keys), fn)
+
+	return nil
+}
+
+// bundleKeys adds the public keys of all recipients of the given secret.
+func (s *Action) bundleKeys(ctx context.Context, b *bundle.Bundle, name string) error {
+	mp := s.Store.MountPoint(name)
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil {
+		return ExitError(ExitMount, err, "failed to get store %s: %s", mp, err)
+	}
+
+	exp, ok := sub.Crypto().(keyExporter)
+	if !ok {
+		debug.Log("crypto backend %T can not export public keys", sub.Crypto())
+		return nil
+	}
+
+	rs, err := sub.GetRecipients(ctx, strings.TrimPrefix(strings.TrimPrefix(name, mp), "/"))
+	if err != nil {
+		return ExitError(ExitRecipients, err, "failed to read recipients of %s: %s", name, err)
+	}
+
+	for _, r := range rs {
+		if _, found := b.Keys[r]; found {
+			continue
+		}
+		pk, err := exp.ExportPublicKey(ctx, r)
+		if err != nil {
+			return ExitError(ExitRecipients, err, "failed to export public key %s: %s", r, err)
+		}
+		b.Keys[r] = pk
+	}
+
+	return nil
+}
+
+// BundleApply verifies a bundle, imports the public keys and writes the
+// secrets to the local store.
+func (s *Action) BundleApply(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	force := c.Bool("force")
+
+	fn := c.Args().First()
+	if fn == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s bundle apply <file|->", s.Name)
+	}
+
+	var buf []byte
+	var err error
+	if fn == "-" {
+		buf, err = io.ReadAll(stdin)
+	} else {
+		buf, err = os.ReadFile(filepath.Clean(fn))
+	}
+	if err != nil {
+		return ExitError(ExitIO, err, "failed to read bundle from %s: %s", fn, err)
+	}
+
+	crypto := s.Store.Crypto(ctx, "")
+	signer, ok := crypto.(backend.Signer)
+	if !ok {
+		return ExitError(ExitUnsupported, nil, "Crypto backend %s can not verify bundles", crypto.Name())
+	}
+
+	b, fp, err := bundle.Open(ctx, crypto, signer, buf)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to open bundle: %s", err)
+	}
+
+	out.Noticef(ctx, "Bundle with %d secrets signed by %s", len(b.Secrets), crypto.FormatKey(ctx, fp, ""))
+	if !force && !termio.AskForConfirmation(ctx, "Do you trust the signer and want to apply this bundle?") {
+		return ExitError(ExitAborted, nil, "user aborted")
+	}
+
+	if err := s.bundleImportKeys(ctx, crypto, b); err != nil {
+		return err
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Applied bundle signed by %s", fp))
+
+	var written int
+	for _, name := range b.Names() {
+		content := b.Secrets[name]
+		if s.Store.Exists(ctx, name) && !force {
+			cur, err := s.Store.Get(ctx, name)
+			if err != nil {
+				return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+			}
+			if string(cur.Bytes()) == string(content) {
+				continue
+			}
+			out.Warningf(ctx, "Not overwriting %s. Use --force to replace it", name)
+			continue
+		}
+
+		sec := &secrets.Plain{}
+		if _, err := sec.Write(content); err != nil {
+			return ExitError(ExitUnknown, err, "failed to parse %s: %s", name, err)
+		}
+		if err := s.Store.Set(ctx, name, sec); err != nil {
+			return ExitError(ExitEncrypt, err, "failed to write %s: %s", name, err)
+		}
+		written++
+	}
+
+	out.OKf(ctx, "Applied %d of %d secrets", written, len(b.Secrets))
+
+	return nil
+}
+
+// bundleImportKeys imports all public keys that are not yet in the keyring.
+func (s *Action) bundleImportKeys(ctx context.Context, crypto backend.Crypto, b *bundle.Bundle) error {
+	im, ok := crypto.(keyImporter)
+	if !ok {
+		debug.Log("crypto backend %T can not import public keys", crypto)
+		return nil
+	}
+
+	for id, pk := range b.Keys {
+		if len(pk) < 1 {
+			continue
+		}
+		if rs, err := crypto.FindRecipients(ctx, id); err == nil && len(rs) > 0 {
+			continue
+		}
+		if err := im.ImportPublicKey(ctx, pk); err != nil {
+			return ExitError(ExitRecipients, err, "failed to import public key %s: %s", id, err)
+		}
+		out.Printf(ctx, "Imported public key %s", crypto.FormatKey(ctx, id, ""))
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestBundle(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	require.NoError(t, act.Store.Set(ctx, "db/prod", secrets.NewKVWithData("s3cret", map[string][]string{"user": {"admin"}}, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "db/dev", secrets.NewKVWithData("dev", nil, "", false)))
+
+	fn := filepath.Join(t.TempDir(), "secrets.bundle")
+
+	bundleCtx := func(recipients []string, output string, args ...string) *cli.Context {
+		fs := flag.NewFlagSet("default", flag.ContinueOnError)
+		rf := cli.StringSliceFlag{Name: "recipient"}
+		require.NoError(t, rf.Apply(fs))
+		of := cli.StringFlag{Name: "output"}
+		require.NoError(t, of.Apply(fs))
+		argl := []string{"--output=" + output}
+		for _, r := range recipients {
+			argl = append(argl, "--recipient="+r)
+		}
+		require.NoError(t, fs.Parse(append(argl, args...)))
+		c := cli.NewContext(cli.NewApp(), fs, nil)
+		c.Context = ctx
+
+		return c
+	}
+
+	t.Run("create without args", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.BundleCreate(bundleCtx([]string{"0xDEADBEEF"}, fn)))
+	})
+
+	t.Run("create without recipient", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.BundleCreate(bundleCtx(nil, fn, "db")))
+	})
+
+	t.Run("create unknown recipient", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.BundleCreate(bundleCtx([]string{"0xCAFEBABE"}, fn, "db")))
+	})
+
+	t.Run("create", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.BundleCreate(bundleCtx([]string{"0xDEADBEEF"}, fn, "db")))
+		assert.Contains(t, buf.String(), "Wrote 2 secrets")
+	})
+
+	t.Run("apply keeps changed secrets", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Store.Delete(ctx, "db/dev"))
+		require.NoError(t, act.Store.Set(ctx, "db/prod", secrets.NewKVWithData("changed", nil, "", false)))
+
+		require.NoError(t, act.BundleApply(gptest.CliCtx(ctx, t, fn)))
+		assert.Contains(t, buf.String(), "Not overwriting db/prod")
+		assert.Contains(t, buf.String(), "Applied 1 of 2 secrets")
+
+		sec, err := act.Store.Get(ctx, "db/dev")
+		require.NoError(t, err)
+		assert.Equal(t, "dev", sec.Password())
+		sec, err = act.Store.Get(ctx, "db/prod")
+		require.NoError(t, err)
+		assert.Equal(t, "changed", sec.Password())
+	})
+
+	t.Run("apply with force", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.BundleApply(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, fn)))
+
+		sec, err := act.Store.Get(ctx, "db/prod")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", sec.Password())
+		user, _ := sec.Get("user")
+		assert.Equal(t, "admin", user)
+	})
+
+	t.Run("apply tampered", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, os.WriteFile(fn, []byte("garbage"), 0o600))
+		assert.Error(t, act.BundleApply(gptest.CliCtx(ctx, t, fn)))
+	})
+}
@@ -0,0 +1,20 @@
+package action
+
+import (
+	gpgcli "github.com/gopasspw/gopass/internal/backend/crypto/gpg/cli"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// CacheClear purges all persistent caches.
+func (s *Action) CacheClear(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	if err := gpgcli.PurgeKeyCache(); err != nil {
+		return ExitError(ExitIO, err, "failed to clear key cache: %s", err)
+	}
+
+	out.OKf(ctx, "Caches cleared")
+	return nil
+}
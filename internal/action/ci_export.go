@@ -0,0 +1,116 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	ciFormatGitHub = "github"
+	ciFormatShell  = "shell"
+)
+
+var invalidEnvChars = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+// CIExport emits masking directives and environment assignments for a secret
+// or all secrets below a folder, for use in CI pipelines.
+func (s *Action) CIExport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := strings.TrimSuffix(c.Args().First(), "/")
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s ci-export <SECRET|FOLDER> [--format github|shell]", s.Name)
+	}
+
+	format := c.String("format")
+	switch format {
+	case ciFormatGitHub, ciFormatShell:
+	default:
+		return ExitError(ExitUsage, nil, "unsupported format %q. Use %s or %s", format, ciFormatGitHub, ciFormatShell)
+	}
+
+	names, err := s.expandSecrets(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	vars := make([][2]string, 0, len(names))
+	for _, name := range names {
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+		vars = append(vars, [2]string{envName(name), sec.Password()})
+	}
+
+	if format == ciFormatShell {
+		for _, v := range vars {
+			fmt.Fprintf(stdout, "export %s=%s\n", v[0], shellQuote(v[1]))
+		}
+		return nil
+	}
+
+	fn := os.Getenv("GITHUB_ENV")
+	if fn == "" {
+		return ExitError(ExitUnsupported, nil, "GITHUB_ENV is not set. Not running in GitHub Actions?")
+	}
+
+	// register all masks before any value could be printed
+	for _, v := range vars {
+		for _, line := range strings.Split(v[1], "\n") {
+			if strings.TrimSpace(line) != "" {
+				fmt.Fprintf(stdout, "::add-mask::%s\n", line)
+			}
+		}
+	}
+
+	fh, err := os.OpenFile(fn, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return ExitError(ExitIO, err, "failed to open %s: %s", fn, err)
+	}
+	defer fh.Close()
+
+	for _, v := range vars {
+		debug.Log("exporting %s", v[0])
+		if err := writeGitHubEnv(fh, v[0], v[1]); err != nil {
+			return ExitError(ExitIO, err, "failed to write %s: %s", fn, err)
+		}
+	}
+
+	return nil
+}
+
+// envName turns a secret name into an environment variable name, e.g.
+// "ci/db-password" becomes "DB_PASSWORD".
+func envName(name string) string {
+	return invalidEnvChars.ReplaceAllString(strings.ToUpper(path.Base(name)), "_")
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// writeGitHubEnv writes a variable to the GITHUB_ENV file. Values with
+// newlines use the heredoc style syntax.
+func writeGitHubEnv(w io.Writer, name, value string) error {
+	if !strings.Contains(value, "\n") {
+		_, err := fmt.Fprintf(w, "%s=%s\n", name, value)
+		return err
+	}
+
+	delim := "GOPASS_EOF"
+	for strings.Contains(value, delim) {
+		delim += "_"
+	}
+	_, err := fmt.Fprintf(w, "%s<<%s\n%s\n%s\n", name, delim, value, delim)
+
+	return err
+}
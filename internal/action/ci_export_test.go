@@ -0,0 +1,87 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIExport(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	require.NoError(t, act.Store.Set(ctx, "ci/db-password", secrets.NewKVWithData("it's s3cret", nil, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "ci/api.token", secrets.NewKVWithData("t0ken", nil, "", false)))
+
+	t.Run("w/o args", func(t *testing.T) {
+		assert.Error(t, act.CIExport(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		assert.Error(t, act.CIExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "jenkins"}, "ci")))
+	})
+
+	t.Run("shell", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.CIExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "shell"}, "ci")))
+		assert.Equal(t, "export API_TOKEN='t0ken'\nexport DB_PASSWORD='it'\\''s s3cret'\n", buf.String())
+	})
+
+	t.Run("github w/o GITHUB_ENV", func(t *testing.T) {
+		defer buf.Reset()
+
+		t.Setenv("GITHUB_ENV", "")
+		assert.Error(t, act.CIExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "github"}, "ci")))
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("github", func(t *testing.T) {
+		defer buf.Reset()
+
+		fn := filepath.Join(t.TempDir(), "github_env")
+		t.Setenv("GITHUB_ENV", fn)
+		require.NoError(t, act.CIExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"format": "github"}, "ci/db-password")))
+		assert.Equal(t, "::add-mask::it's s3cret\n", buf.String())
+
+		env, err := os.ReadFile(fn)
+		require.NoError(t, err)
+		assert.Equal(t, "DB_PASSWORD=it's s3cret\n", string(env))
+	})
+}
+
+func TestWriteGitHubEnv(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	require.NoError(t, writeGitHubEnv(buf, "FOO", "bar"))
+	assert.Equal(t, "FOO=bar\n", buf.String())
+	buf.Reset()
+
+	require.NoError(t, writeGitHubEnv(buf, "KEY", "line1\nGOPASS_EOF\nline3"))
+	assert.Equal(t, "KEY<<GOPASS_EOF_\nline1\nGOPASS_EOF\nline3\nGOPASS_EOF_\n", buf.String())
+}
@@ -1,6 +1,7 @@
 package action
 
 import (
+	"context"
 	"strings"
 
 	"github.com/urfave/cli/v2"
@@ -44,3 +45,17 @@ OUTER:
 	}
 	return args, kvps
 }
+
+// splitNameKey splits an argument of the form name:key into the name of an
+// existing secret and a key. Anything else, e.g. the name of a new secret
+// that contains a colon like example.com:8080, is left alone.
+func (s *Action) splitNameKey(ctx context.Context, arg string) (string, string, bool) {
+	idx := strings.LastIndex(arg, ":")
+	if idx < 1 || idx == len(arg)-1 {
+		return arg, "", false
+	}
+	if s.Store.Exists(ctx, arg) || !s.Store.Exists(ctx, arg[:idx]) {
+		return arg, "", false
+	}
+	return arg[:idx], arg[idx+1:], true
+}
@@ -22,13 +22,16 @@ func (s *Action) Clone(c *cli.Context) error {
 	if c.IsSet("crypto") {
 		ctx = backend.WithCryptoBackendString(ctx, c.String("crypto"))
 	}
+	if c.IsSet("sparse") {
+		ctx = backend.WithSparse(ctx, c.StringSlice("sparse"))
+	}
 	path := c.String("path")
 
 	if c.Args().Len() < 1 {
 		return ExitError(ExitUsage, nil, "Usage: %s clone repo [mount]", s.Name)
 	}
 
-	// gopass clone [--crypto=foo] [--path=/some/store] git://foo/bar team0.
+	// gopass clone [--crypto=foo] [--path=/some/store] [--sparse=prefix] git://foo/bar team0.
 	repo := c.Args().Get(0)
 	mount := ""
 	if c.Args().Len() > 1 {
@@ -0,0 +1,199 @@
+package action
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/cloud"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+// newAWSProvider is a variable so tests can replace the aws CLI.
+var newAWSProvider = func(service, region string) (cloud.Provider, error) {
+	return cloud.NewAWS(service, region)
+}
+
+// CloudSyncAWS pushes the passwords of a secret or all secrets below a folder
+// to AWS Secrets Manager or SSM Parameter Store. With --pull the values are
+// copied from AWS into the store instead.
+func (s *Action) CloudSyncAWS(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	folder := strings.TrimSuffix(c.Args().First(), "/")
+	prefix := c.String("prefix")
+	if folder == "" || prefix == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s cloud sync aws <SECRET|FOLDER> --prefix <PREFIX>", s.Name)
+	}
+
+	p, err := newAWSProvider(c.String("service"), c.String("region"))
+	if err != nil {
+		return ExitError(ExitUsage, err, "%s", err)
+	}
+
+	remote, err := p.List(ctx, prefix)
+	if err != nil {
+		return ExitError(ExitUnknown, err, "failed to list %s in %s: %s", prefix, p.Name(), err)
+	}
+
+	if c.Bool("pull") {
+		return s.cloudPull(ctx, p, folder, prefix, remote, c.Bool("dry-run"))
+	}
+
+	return s.cloudPush(ctx, p, folder, prefix, remote, c.Bool("dry-run"))
+}
+
+func (s *Action) cloudPush(ctx context.Context, p cloud.Provider, folder, prefix string, remote map[string]string, dryRun bool) error {
+	names, err := s.expandSecrets(ctx, folder)
+	if err != nil {
+		return err
+	}
+
+	local := make(map[string]string, len(names))
+	for _, name := range names {
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+		if sec.Password() == "" {
+			out.Warningf(ctx, "Skipping %s. It has no password.", name)
+			continue
+		}
+
+		// the remote name is relative to the folder
+		rel := path.Base(name)
+		if name != folder {
+			rel = strings.TrimPrefix(name, folder+"/")
+		}
+		local[prefix+rel] = sec.Password()
+	}
+
+	changes := cloud.Diff(local, remote)
+	if !printChanges(ctx, changes, p.Name(), dryRun) {
+		return nil
+	}
+
+	for _, ch := range changes {
+		if ch.Op == cloud.Unchanged {
+			continue
+		}
+		if err := p.Put(ctx, ch.Name, ch.Value, ch.Op == cloud.Create); err != nil {
+			return ExitError(ExitUnknown, err, "failed to write %s: %s", ch.Name, err)
+		}
+	}
+
+	out.OKf(ctx, "Pushed %d secrets to %s", countChanges(changes), p.Name())
+	return nil
+}
+
+func (s *Action) cloudPull(ctx context.Context, p cloud.Provider, folder, prefix string, remote map[string]string, dryRun bool) error {
+	// map gopass names to the remote values and collect the current values
+	src := make(map[string]string, len(remote))
+	local := make(map[string]string, len(remote))
+	for name, val := range remote {
+		// the remote names are untrusted, they must not leave the folder
+		rel := strings.TrimPrefix(name, prefix)
+		if err := store.ValidateName(rel); err != nil {
+			return ExitError(ExitUnsupported, err, "Can not pull %s: %s", name, err)
+		}
+		dst := path.Join(folder, rel)
+		src[dst] = val
+
+		if !s.Store.Exists(ctx, dst) {
+			continue
+		}
+		sec, err := s.Store.Get(ctx, dst)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", dst, err)
+		}
+		local[dst] = sec.Password()
+	}
+
+	changes := cloud.Diff(src, local)
+	if !printChanges(ctx, changes, p.Name(), dryRun) {
+		return nil
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, "Pulled from "+p.Name())
+	for _, ch := range changes {
+		if ch.Op == cloud.Unchanged {
+			continue
+		}
+
+		// keep any other content of existing secrets
+		var sec gopass.Secret = secrets.New()
+		if ch.Op == cloud.Update {
+			var err error
+			sec, err = s.Store.Get(ctx, ch.Name)
+			if err != nil {
+				return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", ch.Name, err)
+			}
+		}
+		sec.SetPassword(ch.Value)
+
+		if err := s.Store.Set(ctx, ch.Name, sec); err != nil {
+			return ExitError(ExitEncrypt, err, "failed to write %s: %s", ch.Name, err)
+		}
+	}
+
+	out.OKf(ctx, "Pulled %d secrets from %s", countChanges(changes), p.Name())
+	return nil
+}
+
+// expandSecrets returns the secret or, if it is a folder, all secrets below it.
+func (s *Action) expandSecrets(ctx context.Context, folder string) ([]string, error) {
+	if !s.Store.IsDir(ctx, folder) {
+		if !s.Store.Exists(ctx, folder) {
+			return nil, ExitError(ExitNotFound, nil, "Secret %s not found", folder)
+		}
+		return []string{folder}, nil
+	}
+
+	l, err := s.Store.Tree(ctx)
+	if err != nil {
+		return nil, ExitError(ExitList, err, "failed to list store: %s", err)
+	}
+	subtree, err := l.FindFolder(folder)
+	if err != nil {
+		return nil, ExitError(ExitNotFound, nil, "Entry %q not found", folder)
+	}
+
+	return subtree.List(tree.INF), nil
+}
+
+// printChanges prints the diff and returns true if there is anything to do.
+func printChanges(ctx context.Context, changes []cloud.Change, dst string, dryRun bool) bool {
+	n := countChanges(changes)
+	for _, ch := range changes {
+		if ch.Op != cloud.Unchanged {
+			out.Printf(ctx, "%s %s", ch.Op, ch.Name)
+		}
+	}
+	if n == 0 {
+		out.OKf(ctx, "Everything up to date with %s", dst)
+		return false
+	}
+	if dryRun {
+		out.Noticef(ctx, "Dry run. Not writing %d changes.", n)
+		return false
+	}
+
+	return true
+}
+
+func countChanges(changes []cloud.Change) int {
+	var n int
+	for _, ch := range changes {
+		if ch.Op != cloud.Unchanged {
+			n++
+		}
+	}
+
+	return n
+}
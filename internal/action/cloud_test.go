@@ -0,0 +1,136 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/cloud"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	vals map[string]string
+}
+
+func (f *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (f *fakeProvider) List(_ context.Context, prefix string) (map[string]string, error) {
+	vals := map[string]string{}
+	for k, v := range f.vals {
+		if strings.HasPrefix(k, prefix) {
+			vals[k] = v
+		}
+	}
+	return vals, nil
+}
+
+func (f *fakeProvider) Put(_ context.Context, name, value string, _ bool) error {
+	f.vals[name] = value
+	return nil
+}
+
+func TestCloudSyncAWS(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	fp := &fakeProvider{vals: map[string]string{
+		"/app/db":    "old",
+		"/app/extra": "remote only",
+	}}
+	oldProvider := newAWSProvider
+	newAWSProvider = func(string, string) (cloud.Provider, error) {
+		return fp, nil
+	}
+	defer func() {
+		newAWSProvider = oldProvider
+	}()
+
+	require.NoError(t, act.Store.Set(ctx, "infra/app/db", secrets.NewKVWithData("new", map[string][]string{"user": {"admin"}}, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "infra/app/api/token", secrets.NewKVWithData("t0ken", nil, "", false)))
+
+	t.Run("w/o prefix", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.CloudSyncAWS(gptest.CliCtx(ctx, t, "infra/app")))
+	})
+
+	t.Run("push dry-run", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.CloudSyncAWS(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "/app/", "dry-run": "true"}, "infra/app")))
+		assert.Contains(t, buf.String(), "+ /app/api/token")
+		assert.Contains(t, buf.String(), "~ /app/db")
+		assert.NotContains(t, buf.String(), "/app/extra")
+		assert.Equal(t, "old", fp.vals["/app/db"])
+	})
+
+	t.Run("push", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.CloudSyncAWS(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "/app/"}, "infra/app")))
+		assert.Equal(t, map[string]string{
+			"/app/db":        "new",
+			"/app/api/token": "t0ken",
+			"/app/extra":     "remote only",
+		}, fp.vals)
+	})
+
+	t.Run("push up to date", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.CloudSyncAWS(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "/app/"}, "infra/app")))
+		assert.Contains(t, buf.String(), "Everything up to date")
+	})
+
+	t.Run("pull", func(t *testing.T) {
+		defer buf.Reset()
+
+		fp.vals["/app/db"] = "rotated"
+		require.NoError(t, act.CloudSyncAWS(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "/app/", "pull": "true"}, "infra/app")))
+		assert.Contains(t, buf.String(), "+ infra/app/extra")
+		assert.Contains(t, buf.String(), "~ infra/app/db")
+
+		sec, err := act.Store.Get(ctx, "infra/app/db")
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", sec.Password())
+		v, _ := sec.Get("user")
+		assert.Equal(t, "admin", v)
+
+		sec, err = act.Store.Get(ctx, "infra/app/extra")
+		require.NoError(t, err)
+		assert.Equal(t, "remote only", sec.Password())
+	})
+	t.Run("pull outside of the folder", func(t *testing.T) {
+		defer buf.Reset()
+
+		fp.vals["/app/../../escaped"] = "evil"
+		defer delete(fp.vals, "/app/../../escaped")
+		assert.Error(t, act.CloudSyncAWS(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "/app/", "pull": "true"}, "infra/app")))
+		assert.False(t, act.Store.Exists(ctx, "escaped"))
+	})
+}
@@ -2,8 +2,10 @@ package action
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/cloud"
 	"github.com/urfave/cli/v2"
 )
 
@@ -26,6 +28,10 @@ func ShowFlags() []cli.Flag {
 			Aliases: []string{"C"},
 			Usage:   "Copy the password and show everything",
 		},
+		&cli.BoolFlag{
+			Name:  "print",
+			Usage: "Print the secret even if showaction is set to clip",
+		},
 		&cli.BoolFlag{
 			Name:  "qr",
 			Usage: "Print the password as a QR Code",
@@ -49,12 +55,20 @@ func ShowFlags() []cli.Flag {
 			Aliases: []string{"n"},
 			Usage:   "Do not parse the output.",
 		},
+		&cli.BoolFlag{
+			Name:  "cert-info",
+			Usage: "Display subject, SANs and validity of any X.509 certificates in the secret instead of its content",
+		},
+		&cli.StringFlag{
+			Name:  "reason",
+			Usage: "Reason for accessing a break-glass secret. Recorded in the store before it is shown",
+		},
 	}
 }
 
 // GetCommands returns the cli commands exported by this module.
 func (s *Action) GetCommands() []*cli.Command {
-	return []*cli.Command{
+	return s.withAuditLog("", []*cli.Command{
 		{
 			Name:        "alias",
 			Usage:       "Manage domain aliases",
@@ -85,6 +99,37 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:  "ansible",
+			Usage: "Ansible integration",
+			Description: "" +
+				"These commands let ansible retrieve vault passwords from gopass.",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "vault-client",
+					Usage: "Ansible vault password client",
+					Description: "" +
+						"Implements the ansible vault password client script protocol and prints " +
+						"the password of <prefix>/<vault-id>. Ansible requires client scripts to be " +
+						"named *-client, so this command is also run if the gopass binary is invoked " +
+						"through a symlink named e.g. gopass-client.",
+					Before: s.IsInitialized,
+					Action: s.AnsibleVaultClient,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "vault-id",
+							Usage: "The vault id to look up",
+							Value: ansibleDefaultVaultID,
+						},
+						&cli.StringFlag{
+							Name:  "prefix",
+							Usage: "The folder containing the vault passwords",
+							Value: "ansible/vault",
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:      "audit",
 			Usage:     "Decrypt all secrets and scan for weak or leaked passwords",
@@ -100,6 +145,129 @@ func (s *Action) GetCommands() []*cli.Command {
 					Usage: "Age in days before a password is considered expired. Setting this will only check expiration.",
 				},
 			},
+			Subcommands: []*cli.Command{
+				{
+					Name:  "recipients",
+					Usage: "Show who can read which folder",
+					Description: "" +
+						"Cross-references the recipients files of all stores with the recipients " +
+						"each secret is actually encrypted for. Prints the recipients of each folder " +
+						"and flags secrets that are still readable by removed recipients or not " +
+						"readable by new ones. Doesn't decrypt any secret.",
+					Before: s.IsInitialized,
+					Action: s.AuditRecipients,
+				},
+				{
+					Name:      "unused",
+					Usage:     "List secrets that haven't been used for a while",
+					ArgsUsage: "[filter]",
+					Description: "" +
+						"Lists all secrets that haven't been shown or copied within the given time, " +
+						"according to the local usage log (see the usagelog option). These are " +
+						"candidates for rotation or removal. Reading the usage log requires your passphrase.",
+					Before: s.IsInitialized,
+					Action: s.AuditUnused,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "older-than",
+							Usage: "Minimum time since the last use, e.g. 1y, 6w or 90d",
+							Value: defaultUnusedAge,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "audit-log",
+			Usage: "Show and verify the local audit log",
+			Description: "" +
+				"If the auditlog option is enabled every gopass command is recorded in a local, " +
+				"append-only log together with the affected secret and a timestamp. Each entry " +
+				"contains the hash of the previous one so changes to the log can be detected. " +
+				"The log never contains any secret content.",
+			Subcommands: []*cli.Command{
+				{
+					Name:        "show",
+					Usage:       "Print all entries of the audit log",
+					Description: "Prints the time, command and secret of every logged operation.",
+					Action:      s.AuditLogShow,
+				},
+				{
+					Name:  "verify",
+					Usage: "Check the audit log for tampering",
+					Description: "" +
+						"Recomputes the hash chain of the audit log and fails if any entry was " +
+						"changed, removed or reordered. Prints the hash of the last entry, which " +
+						"can be recorded elsewhere to detect changes to the end of the log.",
+					Action: s.AuditLogVerify,
+				},
+			},
+		},
+		{
+			Name:  "bundle",
+			Usage: "Transfer secrets to air-gapped hosts",
+			Description: "" +
+				"Bundles are single files that contain a selection of secrets and the public keys " +
+				"of their recipients. They are signed by the creator and encrypted for the target " +
+				"host so they can be carried to an air-gapped machine and applied to its store.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "create",
+					Usage:     "Create a signed and encrypted bundle",
+					ArgsUsage: "[secret|folder]...",
+					Description: "" +
+						"Decrypts the given secrets, or all secrets below the given folders, and writes " +
+						"them together with the public keys of their recipients to a bundle. The bundle " +
+						"is signed with your key and encrypted for the given recipients.",
+					Before:       s.IsInitialized,
+					Action:       s.BundleCreate,
+					BashComplete: s.Complete,
+					Flags: []cli.Flag{
+						&cli.StringSliceFlag{
+							Name:  "recipient",
+							Usage: "Encrypt the bundle for this key. Can be given multiple times",
+						},
+						&cli.StringFlag{
+							Name:    "output",
+							Aliases: []string{"o"},
+							Usage:   "Write the bundle to this file instead of stdout",
+						},
+					},
+				},
+				{
+					Name:      "apply",
+					Usage:     "Apply a bundle to the local store",
+					ArgsUsage: "[file|-]",
+					Description: "" +
+						"Decrypts the bundle and verifies its signature. After confirming the signer " +
+						"the public keys are imported and the secrets are written to the local store. " +
+						"Existing secrets with a different content are only replaced with --force.",
+					Before: s.IsInitialized,
+					Action: s.BundleApply,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Don't ask for confirmation and overwrite existing secrets",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "cache",
+			Usage: "Manage local caches",
+			Description: "" +
+				"gopass keeps some data, e.g. GPG key listings (see the keycache " +
+				"config option), in local caches. This command can be used to " +
+				"manage these.",
+			Subcommands: []*cli.Command{
+				{
+					Name:        "clear",
+					Usage:       "Remove all cached data",
+					Description: "Purges all local caches. They will be rebuilt on demand.",
+					Action:      s.CacheClear,
+				},
+			},
 		},
 		{
 			Name:      "cat",
@@ -113,6 +281,36 @@ func (s *Action) GetCommands() []*cli.Command {
 			Before:       s.IsInitialized,
 			Action:       s.Cat,
 			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "compress",
+					Usage: "Compress the content with zstd before encrypting it",
+				},
+				&cli.BoolFlag{
+					Name:  "dedup",
+					Usage: "Split the content into deduplicated chunks, each stored as a separate secret",
+				},
+			},
+		},
+		{
+			Name:      "ci-export",
+			Usage:     "Export secrets to a CI pipeline with masking",
+			ArgsUsage: "[secret|folder]",
+			Description: "" +
+				"Exports the password of the secret or all secrets below the folder as environment " +
+				"variables named after the last element of the secret name. With the github format " +
+				"the values are masked with '::add-mask::' and written to $GITHUB_ENV. With the " +
+				"shell format export statements are printed, e.g. for eval in GitLab CI jobs.",
+			Before:       s.IsInitialized,
+			Action:       s.CIExport,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "Output format: github or shell",
+					Value: ciFormatGitHub,
+				},
+			},
 		},
 		{
 			Name:      "clone",
@@ -136,6 +334,81 @@ func (s *Action) GetCommands() []*cli.Command {
 					Name:  "crypto",
 					Usage: fmt.Sprintf("Select crypto backend %v", backend.CryptoRegistry.Backends()),
 				},
+				&cli.StringSliceFlag{
+					Name:  "sparse",
+					Usage: "Only check out secrets below this prefix. Other secrets are fetched on demand. Can be given multiple times",
+				},
+			},
+		},
+		{
+			Name:  "cloud",
+			Usage: "Sync secrets with cloud secret stores",
+			Description: "" +
+				"These commands keep the secret stores of cloud providers in sync with " +
+				"the values maintained in gopass.",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "sync",
+					Usage: "Sync secrets with a cloud provider",
+					Description: "" +
+						"Compares secrets in gopass with the values stored by a cloud provider " +
+						"and writes the differences.",
+					Subcommands: []*cli.Command{
+						{
+							Name:      "aws",
+							Usage:     "Sync with AWS Secrets Manager or SSM Parameter Store",
+							ArgsUsage: "[secret|folder]",
+							Description: "" +
+								"Pushes the passwords of the secret or all secrets below the folder to AWS. " +
+								"The remote name is --prefix followed by the name relative to the folder. " +
+								"Only new and changed values are written. Values that only exist in AWS " +
+								"are left untouched. Requires the aws CLI.",
+							Before: s.IsInitialized,
+							Action: s.CloudSyncAWS,
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "prefix",
+									Usage: "Prefix of the names in AWS, e.g. /app/",
+								},
+								&cli.StringFlag{
+									Name:  "service",
+									Usage: "AWS service: secretsmanager or ssm",
+									Value: cloud.SecretsManager,
+								},
+								&cli.StringFlag{
+									Name:  "region",
+									Usage: "AWS region (default: region of the aws CLI profile)",
+								},
+								&cli.BoolFlag{
+									Name:  "pull",
+									Usage: "Copy the values from AWS into gopass instead",
+								},
+								&cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "Only show the changes",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "combine",
+			Usage:     "Recover a secret that was split into shares",
+			ArgsUsage: "[secret]",
+			Description: "" +
+				"Decrypts all shares of the secret you hold and asks for the missing ones until " +
+				"enough shares are given. Then prints the recovered secret. Other keyholders can " +
+				"print their shares with --export.",
+			Before:       s.IsInitialized,
+			Action:       s.Combine,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "export",
+					Usage: "Only print the shares you hold",
+				},
 			},
 		},
 		{
@@ -146,9 +419,16 @@ func (s *Action) GetCommands() []*cli.Command {
 				"This command allows for easy printing and editing of the configuration. " +
 				"Without argument, the entire config is printed. " +
 				"With a single argument, a single key can be printed. " +
-				"With two arguments a setting specified by key can be set to value.",
+				"With two arguments a setting specified by key can be set to value. " +
+				"With --store the options of a single store are printed or set instead.",
 			Action:       s.Config,
 			BashComplete: s.ConfigComplete,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "store",
+					Usage: "Print or set the options of this store. Use an empty name for the root store",
+				},
+			},
 		},
 		{
 			Name:        "convert",
@@ -246,6 +526,52 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:      "diff",
+			Usage:     "Show the changes of a secret or the differences between two stores",
+			ArgsUsage: "[secret [revision [revision]]]",
+			Description: "" +
+				"Shows a field level diff of the decrypted content of a secret between two " +
+				"revisions. Without revisions the previous revision is compared to the current " +
+				"content, with one revision that revision is compared to the current content. " +
+				"With --stores two mounts are compared by the names and content hashes of their secrets.",
+			Before:       s.IsInitialized,
+			Action:       s.Diff,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "stores",
+					Usage: "Compare two mounts instead of two revisions of a secret",
+				},
+			},
+		},
+		{
+			Name:  "doctor",
+			Usage: "Check the environment for common problems",
+			Description: "" +
+				"Checks the gpg and git binaries, gpg-agent, the expiry of your private keys, " +
+				"the config file, the permissions and health of all mounted stores and the " +
+				"clipboard tooling. Prints how to fix every problem found.",
+			Action: s.Doctor,
+		},
+		{
+			Name:      "dotenv",
+			Usage:     "Print or write secrets in the dotenv format",
+			ArgsUsage: "[secret|folder]",
+			Description: "" +
+				"Renders the password of the secret or all secrets below the folder as a " +
+				".env file. Variables are named after the last element of the secret name. " +
+				"Use 'gopass import dotenv' to import an existing .env file.",
+			Before:       s.IsInitialized,
+			Action:       s.Dotenv,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "file",
+					Usage: "Write to this file (mode 0600) instead of stdout",
+				},
+			},
+		},
 		{
 			Name:      "edit",
 			Usage:     "Edit new or existing secrets",
@@ -286,6 +612,42 @@ func (s *Action) GetCommands() []*cli.Command {
 			BashComplete: s.Complete,
 			Hidden:       true,
 		},
+		{
+			Name:  "fido2",
+			Usage: "Protect stores with FIDO2 authenticators",
+			Description: "" +
+				"Protects the secrets of a store with a key that is wrapped by a FIDO2 " +
+				"authenticator (hmac-secret), so reading them requires a touch even if " +
+				"gpg-agent has cached your passphrase. Requires the libfido2 tools.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "enroll",
+					Usage:     "Enroll an authenticator for a store",
+					ArgsUsage: "[store]",
+					Description: "" +
+						"The first authenticator protects all existing secrets of the store. " +
+						"Enroll a second one as a backup, losing all authenticators means losing " +
+						"access to the secrets.",
+					Before:       s.IsInitialized,
+					Action:       s.FIDO2Enroll,
+					BashComplete: s.MountsComplete,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "name",
+							Usage: "Name of the authenticator (default: hostname)",
+						},
+						&cli.StringFlag{
+							Name:  "device",
+							Usage: "Path of the authenticator to enroll, e.g. /dev/hidraw0 (default: the first one found)",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Enroll even if the store has other recipients, they'll lose access to the secrets",
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:      "find",
 			Usage:     "Search for secrets",
@@ -342,6 +704,16 @@ func (s *Action) GetCommands() []*cli.Command {
 			Action:       s.BinaryCopy,
 			BashComplete: s.Complete,
 			Hidden:       true,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "compress",
+					Usage: "Compress the content with zstd before encrypting it",
+				},
+				&cli.BoolFlag{
+					Name:  "dedup",
+					Usage: "Split the content into deduplicated chunks, each stored as a separate secret",
+				},
+			},
 		},
 		{
 			Name:      "fsmove",
@@ -359,6 +731,16 @@ func (s *Action) GetCommands() []*cli.Command {
 			Action:       s.BinaryMove,
 			BashComplete: s.Complete,
 			Hidden:       true,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "compress",
+					Usage: "Compress the content with zstd before encrypting it",
+				},
+				&cli.BoolFlag{
+					Name:  "dedup",
+					Usage: "Split the content into deduplicated chunks, each stored as a separate secret",
+				},
+			},
 		},
 		{
 			Name:      "generate",
@@ -461,6 +843,63 @@ func (s *Action) GetCommands() []*cli.Command {
 						},
 					},
 				},
+				{
+					Name:  "gc",
+					Usage: "Compact the git repository or purge deleted secrets from the history",
+					Description: "" +
+						"Runs git gc. With --purge the given secrets, which must have been deleted " +
+						"already, are removed from every revision of the store and the rewritten " +
+						"history is force pushed. All other clones have to be cloned again afterwards.",
+					Before: s.IsInitialized,
+					Action: s.GitGC,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+						&cli.StringSliceFlag{
+							Name:  "purge",
+							Usage: "Deleted secret to remove from the history. Can be given multiple times",
+						},
+					},
+				},
+				{
+					Name:   "hook",
+					Usage:  "Run a hook installed by install-hooks",
+					Hidden: true,
+					Description: "" +
+						"Checks the staged secrets (pre-commit) or the commits to be pushed (pre-push). " +
+						"Invoked by git.",
+					Before: s.IsInitialized,
+					Action: s.GitHook,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+					},
+				},
+				{
+					Name:  "install-hooks",
+					Usage: "Install git hooks enforcing the store policy",
+					Description: "" +
+						"Installs pre-commit and pre-push hooks into the git repository of the store. " +
+						"They reject secrets that are not encrypted for exactly the recipients listed " +
+						"in the applicable recipients file. The pre-push hook also rejects commits " +
+						"without a valid signature.",
+					Before: s.IsInitialized,
+					Action: s.GitInstallHooks,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Replace existing hooks",
+						},
+					},
+				},
 				{
 					Name:        "remote",
 					Usage:       "Manage git remotes",
@@ -470,7 +909,7 @@ func (s *Action) GetCommands() []*cli.Command {
 						{
 							Name:        "add",
 							Usage:       "Add git remote",
-							Description: "Add a new git remote",
+							Description: "Add a new git remote. With --mirror gopass sync also pushes to it",
 							Before:      s.IsInitialized,
 							Action:      s.RCSAddRemote,
 							Flags: []cli.Flag{
@@ -478,6 +917,10 @@ func (s *Action) GetCommands() []*cli.Command {
 									Name:  "store",
 									Usage: "Store to operate on",
 								},
+								&cli.BoolFlag{
+									Name:  "mirror",
+									Usage: "Replicate the store to this remote on every sync. gopass only pushes to mirrors",
+								},
 							},
 						},
 						{
@@ -571,6 +1014,32 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:  "import",
+			Usage: "Import secrets from other formats",
+			Description: "" +
+				"These commands import secrets from files in other formats into the store.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "dotenv",
+					Usage:     "Import a .env file",
+					ArgsUsage: "[file] [folder]",
+					Description: "" +
+						"Stores every variable of the .env file as a secret below the folder. " +
+						"The variable name becomes the secret name and the value its password. " +
+						"Use '-' to read from stdin.",
+					Before: s.IsInitialized,
+					Action: s.DotenvImport,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:    "force",
+							Aliases: []string{"f"},
+							Usage:   "Overwrite existing secrets",
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:      "init",
 			Usage:     "Initialize new password store.",
@@ -604,11 +1073,12 @@ func (s *Action) GetCommands() []*cli.Command {
 		{
 			Name:      "insert",
 			Usage:     "Insert a new secret",
-			ArgsUsage: "[secret]",
+			ArgsUsage: "[secret] | [secret]:[key] [value]",
 			Description: "" +
 				"Insert a new secret. Optionally, echo the secret back to the console during entry. " +
 				"Or, optionally, the entry may be multiline. " +
-				"Prompt before overwriting existing secret unless forced.",
+				"Prompt before overwriting existing secret unless forced. " +
+				"Use 'insert secret:key value' to only set a single key of a secret.",
 			Before:       s.IsInitialized,
 			Action:       s.Insert,
 			BashComplete: s.Complete,
@@ -631,30 +1101,119 @@ func (s *Action) GetCommands() []*cli.Command {
 				&cli.BoolFlag{
 					Name:    "append",
 					Aliases: []string{"a"},
-					Usage:   "Append data read from STDIN to existing data",
+					Usage:   "Append data read from STDIN or the prompt to existing data",
+				},
+				&cli.StringFlag{
+					Name:  "expires",
+					Usage: "Mark the secret as valid until the given date (YYYY-MM-DD) or for a duration (e.g. 90d)",
 				},
 			},
 		},
 		{
-			Name:      "link",
-			Usage:     "Create a symlink",
-			ArgsUsage: "[from] [to]",
+			Name:  "keys",
+			Usage: "Inspect the keys of the recipients",
 			Description: "" +
-				"This command creates a symlink from one entry in a mounted store to another entry." +
-				"Important: Does not cross mounts!",
-			Aliases:      []string{"ln", "symlink"},
-			Hidden:       true,
-			Before:       s.IsInitialized,
-			Action:       s.Link,
-			BashComplete: s.Complete,
+				"These commands show the keys in your keyring, so recipients can be vetted " +
+				"without using the crypto backend directly.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "show",
+					Usage:     "Show the details of a key and what it can decrypt",
+					ArgsUsage: "[key]",
+					Description: "" +
+						"Prints the fingerprint, identities, subkeys, capabilities, expiry and trust " +
+						"of the key as well as the folders of all stores that list it as a recipient.",
+					Before:       s.IsInitialized,
+					Action:       s.KeysShow,
+					BashComplete: s.RecipientsComplete,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "store",
+							Aliases: []string{"s"},
+							Usage:   "Use the crypto backend of this store",
+						},
+					},
+				},
+			},
 		},
 		{
-			Name:      "list",
-			Usage:     "List existing secrets",
-			ArgsUsage: "[prefix]",
+			Name:  "kubectl",
+			Usage: "Sync secrets to Kubernetes",
 			Description: "" +
-				"This command will list all existing secrets. Provide a folder prefix to list " +
-				"only certain subfolders of the store.",
+				"These commands render secrets as Kubernetes Secret manifests so the " +
+				"source of truth stays in gopass.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "apply",
+					Usage:     "Apply secrets as Kubernetes Secrets",
+					ArgsUsage: "[secret|prefix]",
+					Description: "" +
+						"Renders the secret or every secret below the prefix as Kubernetes Secret " +
+						"and applies them with 'kubectl apply'. Secrets containing a PEM encoded " +
+						"certificate and private key become secrets of type kubernetes.io/tls, all " +
+						"others Opaque secrets holding the password, all keys and the body.",
+					Before:       s.IsInitialized,
+					Action:       s.KubectlApply,
+					BashComplete: s.Complete,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "namespace",
+							Aliases: []string{"n"},
+							Usage:   "Kubernetes namespace of the secrets",
+						},
+						&cli.BoolFlag{
+							Name:  "print",
+							Usage: "Print the manifests instead of applying them",
+						},
+						&cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "Let kubectl validate the manifests without persisting them",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "link",
+			Usage:     "Create a symlink",
+			ArgsUsage: "[from] [to]",
+			Description: "" +
+				"This command creates a symlink from one entry in a mounted store to another entry." +
+				"Important: Does not cross mounts!",
+			Aliases:      []string{"ln", "symlink"},
+			Hidden:       true,
+			Before:       s.IsInitialized,
+			Action:       s.Link,
+			BashComplete: s.Complete,
+		},
+		{
+			Name:  "lint",
+			Usage: "Check secrets against the naming policy",
+			Description: "" +
+				"This command checks the names of all secrets for upper case letters, spaces and " +
+				"likely duplicates (e.g. github.com and github). If the store contains a " +
+				"policy file (.gopass-lint.yml) it also reports secrets outside of the approved " +
+				"top level folders and secrets without a username in the folders that require one.",
+			Before: s.IsInitialized,
+			Action: s.Lint,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "store",
+					Usage: "Only lint this store",
+				},
+				&cli.BoolFlag{
+					Name:  "no-decrypt",
+					Usage: "Only check the names, skip the username check",
+				},
+			},
+		},
+		{
+			Name:      "list",
+			Usage:     "List existing secrets",
+			ArgsUsage: "[prefix]",
+			Description: "" +
+				"This command will list all existing secrets. Provide a folder prefix to list " +
+				"only certain subfolders of the store.",
 			Aliases:      []string{"ls"},
 			Before:       s.IsInitialized,
 			Action:       s.List,
@@ -680,6 +1239,10 @@ func (s *Action) GetCommands() []*cli.Command {
 					Aliases: []string{"s"},
 					Usage:   "Strip this prefix from filtered entries",
 				},
+				&cli.BoolFlag{
+					Name:  "expired",
+					Usage: "Mark expired secrets. This needs to decrypt all listed secrets",
+				},
 			},
 		},
 		{
@@ -718,7 +1281,8 @@ func (s *Action) GetCommands() []*cli.Command {
 				"This command moves a secret from one path to another. This also works " +
 				"across different sub-stores. If the source is a directory, the source directory " +
 				"is re-created at the destination if no trailing slash is found, otherwise the " +
-				"contents are flattened (similar to rsync).",
+				"contents are flattened (similar to rsync). With --pattern or --regex all matching " +
+				"secrets are renamed in a single commit.",
 			Before:       s.IsInitialized,
 			Action:       s.Move,
 			BashComplete: s.Complete,
@@ -728,6 +1292,45 @@ func (s *Action) GetCommands() []*cli.Command {
 					Aliases: []string{"f"},
 					Usage:   "Force to move the secret and overwrite existing one",
 				},
+				&cli.BoolFlag{
+					Name:  "pattern",
+					Usage: "Treat from and to as patterns, e.g. 'old/*' 'new/*'. * matches within a folder, ** across folders",
+				},
+				&cli.BoolFlag{
+					Name:  "regex",
+					Usage: "Treat from as a regular expression and to as its replacement, e.g. '(.*)/login' '$1/user'",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only show the changes (with --pattern or --regex)",
+				},
+			},
+		},
+		{
+			Name:      "migrate",
+			Usage:     "Re-encrypt a store with a different crypto backend",
+			ArgsUsage: "[mount]",
+			Description: "" +
+				"Decrypts every secret of the store with its current crypto backend and encrypts " +
+				"it with the new one. The new files are verified before the old ones are removed " +
+				"and everything is committed at once. An interrupted migration is resumed by " +
+				"running the same command again.",
+			Before:       s.IsInitialized,
+			Action:       s.Migrate,
+			BashComplete: s.MountsComplete,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "from",
+					Usage: fmt.Sprintf("The current crypto backend, checked before migrating. %v", backend.CryptoRegistry.Backends()),
+				},
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: fmt.Sprintf("The new crypto backend. %v", backend.CryptoRegistry.Backends()),
+				},
+				&cli.StringSliceFlag{
+					Name:  "recipient",
+					Usage: "Recipient of the migrated store. Defaults to your own key of the new backend",
+				},
 			},
 		},
 		{
@@ -749,6 +1352,40 @@ func (s *Action) GetCommands() []*cli.Command {
 					Before: s.IsInitialized,
 					Action: s.MountAdd,
 				},
+				{
+					Name:      "init",
+					Usage:     "Initialize and mount a new store from a layout",
+					ArgsUsage: "<alias> [gpg-id ...]",
+					Description: "" +
+						"This command initializes a new password store and mounts it at alias. " +
+						"With --layout the store is seeded with the folders, templates, recipients, " +
+						"store options and git hooks of a layout definition, so all stores of a " +
+						"team are set up the same way. Layouts are read from the layouts folder " +
+						"next to the config file or from the given file.",
+					Before: s.IsInitialized,
+					Action: s.MountsInit,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "layout",
+							Usage: "Name of a layout in the layouts folder, or the path to a layout file",
+						},
+						&cli.StringFlag{
+							Name:    "path",
+							Aliases: []string{"p"},
+							Usage:   "Path of the new store",
+						},
+						&cli.StringFlag{
+							Name:  "crypto",
+							Usage: fmt.Sprintf("Select crypto backend %v", backend.CryptoRegistry.Backends()),
+							Value: "gpgcli",
+						},
+						&cli.StringFlag{
+							Name:  "storage",
+							Usage: fmt.Sprintf("Select storage backend %v", backend.StorageRegistry.Backends()),
+							Value: "gitfs",
+						},
+					},
+				},
 				{
 					Name:    "remove",
 					Aliases: []string{"rm", "unmount", "umount"},
@@ -773,6 +1410,20 @@ func (s *Action) GetCommands() []*cli.Command {
 			Before:       s.IsInitialized,
 			Action:       s.OTP,
 			BashComplete: s.Complete,
+			Subcommands: []*cli.Command{
+				{
+					Name:      "import",
+					Usage:     "Import an OTP URL from a QR code or an export",
+					ArgsUsage: "[secret] [image|uri]",
+					Description: "" +
+						"Reads an OTP URL from an image of a QR code (PNG or JPEG), an otpauth:// URL " +
+						"or an otpauth-migration:// URI as exported by Google Authenticator and " +
+						"appends it to the secret. The secret is created if it doesn't exist.",
+					Before:       s.IsInitialized,
+					Action:       s.OTPImport,
+					BashComplete: s.Complete,
+				},
+			},
 			Flags: []cli.Flag{
 				&cli.BoolFlag{
 					Name:    "clip",
@@ -791,6 +1442,25 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:  "pinentry",
+			Usage: "Minimal pinentry program for gpg-agent",
+			Description: "" +
+				"This command implements the pinentry protocol so gopass can be used " +
+				"as pinentry-program by gpg-agent. This is useful on servers and in " +
+				"minimal containers that don't ship a pinentry. It is usually not invoked " +
+				"directly. Use 'gopass pinentry install' to configure gpg-agent.",
+			Action: s.Pinentry,
+			Hidden: true,
+			Subcommands: []*cli.Command{
+				{
+					Name:        "install",
+					Usage:       "Configure gpg-agent to use the gopass pinentry",
+					Description: "Writes a small wrapper script and sets it as pinentry-program in gpg-agent.conf.",
+					Action:      s.PinentryInstall,
+				},
+			},
+		},
 		{
 			Name:  "process",
 			Usage: "Process a template file",
@@ -832,9 +1502,46 @@ func (s *Action) GetCommands() []*cli.Command {
 						},
 					},
 				},
+				{
+					Name:      "deauthorize",
+					Usage:     "Revoke the access of a departed team member",
+					ArgsUsage: "[key]",
+					Description: "" +
+						"This command removes the key from every recipients file of every store, " +
+						"re-encrypts all secrets it could read and marks the key as untrusted in the " +
+						"local keyring. Afterwards it prints a checklist of the secrets the key could " +
+						"read. Since the former member might have copies of them they should be rotated.",
+					Before:       s.IsInitialized,
+					Action:       s.RecipientsDeauthorize,
+					BashComplete: s.RecipientsComplete,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Don't ask for confirmation",
+						},
+					},
+				},
+				{
+					Name:  "migrate",
+					Usage: "Replace key IDs and emails with fingerprints",
+					Description: "" +
+						"This command replaces any recipient that is not a full fingerprint, " +
+						"e.g. a short key ID, an email or a name, with the fingerprint of the " +
+						"matching key. Short key IDs are trivial to collide and emails might match " +
+						"different keys later. Recipients matching multiple or no keys are left as is. " +
+						"If no store is given all stores are migrated.",
+					Before: s.IsInitialized,
+					Action: s.RecipientsMigrate,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Store to operate on",
+						},
+					},
+				},
 				{
 					Name:    "remove",
-					Aliases: []string{"rm", "deauthorize"},
+					Aliases: []string{"rm"},
 					Usage:   "Remove any number of Recipients from any store",
 					Description: "" +
 						"This command removes any number of recipients from any existing store. " +
@@ -861,6 +1568,142 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:  "recovery",
+			Usage: "Manage one-time recovery codes",
+			Description: "" +
+				"Store one-time recovery codes, e.g. 2FA backup codes, in a secret and " +
+				"hand them out one by one.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "add",
+					Usage:     "Add recovery codes to a secret",
+					ArgsUsage: "[secret]",
+					Description: "" +
+						"Reads recovery codes from STDIN, one per line, or asks for them " +
+						"interactively and adds them to the secret. The secret is created " +
+						"if it doesn't exist.",
+					Before:       s.IsInitialized,
+					Action:       s.RecoveryAdd,
+					BashComplete: s.Complete,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "replace",
+							Usage: "Remove all existing recovery codes first",
+						},
+					},
+				},
+				{
+					Name:      "use",
+					Usage:     "Print the next unused recovery code and mark it as used",
+					ArgsUsage: "[secret]",
+					Description: "" +
+						"Prints the next unused recovery code of the secret and marks " +
+						"it as used so it won't be handed out again.",
+					Before:       s.IsInitialized,
+					Action:       s.RecoveryUse,
+					BashComplete: s.Complete,
+				},
+			},
+		},
+		{
+			Name:      "render",
+			Usage:     "Render a template file with secrets filled in",
+			ArgsUsage: "[template]",
+			Description: "" +
+				"This command renders a template file, e.g. a config file, using Go's " +
+				"text/template. Secrets are inserted with template functions like " +
+				"'{{ secret \"db/prod\" \"password\" }}'. The result is written to stdout " +
+				"or to the file given with --output, which is only readable by the current user.",
+			Before: s.IsInitialized,
+			Action: s.Render,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "output",
+					Aliases: []string{"o"},
+					Usage:   "Write the result to this file (mode 0600) instead of stdout",
+				},
+			},
+		},
+		{
+			Name:      "rotate",
+			Usage:     "Rotate a credential at its provider",
+			ArgsUsage: "[secret]",
+			Description: "" +
+				"Generates a new password and invokes the rotator named in the 'rotate' key " +
+				"of the secret to change the credential at the provider, e.g. a database. " +
+				"A rotator is an executable called gopass-rotate-<name> in the PATH. " +
+				"The secret is only updated if the rotator succeeds.",
+			Before:       s.IsInitialized,
+			Action:       s.Rotate,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:    "length",
+					Aliases: []string{"l"},
+					Usage:   "Length of the new password",
+					Value:   defaultLength,
+				},
+				&cli.BoolFlag{
+					Name:    "symbols",
+					Aliases: []string{"s"},
+					Usage:   "Use symbols in the new password",
+				},
+			},
+		},
+		{
+			Name:  "rpc",
+			Usage: "Serve JSON-RPC on stdin and stdout for editor plugins",
+			Description: "" +
+				"Reads newline delimited JSON-RPC 2.0 requests from stdin and writes responses " +
+				"to stdout. Supported methods are list, get and set. Added, removed and updated " +
+				"secrets are announced with 'changed' notifications. It is usually not invoked " +
+				"directly but by editor plugins.",
+			Before: s.IsInitialized,
+			Action: s.RPC,
+			Flags: []cli.Flag{
+				&cli.DurationFlag{
+					Name:  "poll",
+					Usage: "Interval to check the store for changes made by other processes. 0 disables polling.",
+					Value: 5 * time.Second,
+				},
+			},
+		},
+		{
+			Name:      "scan",
+			Usage:     "Look for stored secrets in files or a git diff",
+			ArgsUsage: "[path ...]",
+			Description: "" +
+				"Decrypts all secrets and looks for their passwords and the values of their " +
+				"unsafe-keys in the given files, directories or stdin ('-'). Only keyed hashes " +
+				"of the values are compared and nothing but the names of the secrets is printed. " +
+				"Exits with a non-zero status if any secret is found, so it can be used in a " +
+				"pre-commit hook of a code repository with --staged.",
+			Before: s.IsInitialized,
+			Action: s.Scan,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "staged",
+					Usage: "Scan the lines added by the staged changes of the git repository in the current directory",
+				},
+				&cli.BoolFlag{
+					Name:  "diff",
+					Usage: "The input is a unified diff, only scan the added lines",
+				},
+			},
+		},
+		{
+			Name:      "seal",
+			Usage:     "Record a signed snapshot of the store",
+			ArgsUsage: "[store]",
+			Description: "" +
+				"Commits a manifest with the hashes of the encrypted content of all secrets, " +
+				"the recipients and the public keys of the store, signed with your key. " +
+				"Use 'gopass verify-seal' to detect any modification made since.",
+			Before:       s.IsInitialized,
+			Action:       s.Seal,
+			BashComplete: s.MountsComplete,
+		},
 		{
 			Name:  "setup",
 			Usage: "Initialize a new password store",
@@ -912,6 +1755,150 @@ func (s *Action) GetCommands() []*cli.Command {
 			BashComplete: s.Complete,
 			Flags:        ShowFlags(),
 		},
+		{
+			Name:  "snapshot",
+			Usage: "Save and restore the state of the stores",
+			Description: "" +
+				"These commands mark the current state of all stores, so a risky bulk operation " +
+				"(e.g. an import, re-encryption or mass rename) can be rolled back in one command. " +
+				"Snapshots are git tags and require the git storage backend.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "create",
+					Usage:     "Save the current state of all stores",
+					ArgsUsage: "[name]",
+					Description: "" +
+						"Creates a snapshot of every store that uses git. The name defaults to " +
+						"the current date and time.",
+					Before: s.IsInitialized,
+					Action: s.SnapshotCreate,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Only snapshot this store",
+						},
+						&cli.StringFlag{
+							Name:    "message",
+							Aliases: []string{"m"},
+							Usage:   "Describe the snapshot",
+						},
+					},
+				},
+				{
+					Name:  "list",
+					Usage: "List all snapshots",
+					Description: "" +
+						"Lists the snapshots of all stores, newest first. Snapshots of several " +
+						"stores that were created together are shown once.",
+					Aliases: []string{"ls"},
+					Before:  s.IsInitialized,
+					Action:  s.SnapshotList,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Only list the snapshots of this store",
+						},
+					},
+				},
+				{
+					Name:      "rollback",
+					Usage:     "Restore the state of a snapshot",
+					ArgsUsage: "[name]",
+					Description: "" +
+						"Restores every store that has the snapshot to its state and commits the " +
+						"result. The current state is saved as another snapshot first, so the " +
+						"rollback can be undone, too.",
+					Before: s.IsInitialized,
+					Action: s.SnapshotRollback,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "store",
+							Usage: "Only roll back this store",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "split",
+			Usage:     "Split a secret into shares so that several keyholders are needed to read it",
+			ArgsUsage: "[secret] [recipient]...",
+			Description: "" +
+				"Splits the secret with Shamir's Secret Sharing into one share per recipient. Each " +
+				"share is encrypted for its recipient only. Any --threshold of them recover the " +
+				"secret with 'gopass combine'. Afterwards the original secret is removed unless " +
+				"--keep is given.",
+			Before:       s.IsInitialized,
+			Action:       s.Split,
+			BashComplete: s.Complete,
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:    "threshold",
+					Aliases: []string{"t"},
+					Usage:   "Number of shares needed to recover the secret",
+					Value:   2,
+				},
+				&cli.BoolFlag{
+					Name:  "keep",
+					Usage: "Keep the original secret",
+				},
+			},
+		},
+		{
+			Name:  "ssh",
+			Usage: "Use SSH keys stored in gopass",
+			Description: "" +
+				"These commands make SSH key material stored in gopass available to " +
+				"ssh without writing it to disk.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "add",
+					Usage:     "Add a private key to the running ssh-agent",
+					ArgsUsage: "[secret]",
+					Description: "" +
+						"Pipes the first PEM encoded private key found in the secret into ssh-add. " +
+						"If the secret has a 'passphrase' key it is handed to ssh-add using " +
+						"gopass as SSH_ASKPASS program.",
+					Before:       s.IsInitialized,
+					Action:       s.SSHAdd,
+					BashComplete: s.Complete,
+					Flags: []cli.Flag{
+						&cli.IntFlag{
+							Name:    "lifetime",
+							Aliases: []string{"t"},
+							Usage:   "Maximum lifetime of the key in the agent in seconds",
+						},
+						&cli.BoolFlag{
+							Name:    "confirm",
+							Aliases: []string{"c"},
+							Usage:   "Require confirmation before the agent uses the key",
+						},
+					},
+				},
+				{
+					Name:      "known-hosts",
+					Usage:     "Print known_hosts lines for stored host keys",
+					ArgsUsage: "[secret|folder]",
+					Description: "" +
+						"Prints one known_hosts line for each 'hostkey' entry of the secret or of all " +
+						"secrets below the folder. The host name is taken from the 'host' key or " +
+						"the name of the secret. Suitable for ssh's KnownHostsCommand.",
+					Before:       s.IsInitialized,
+					Action:       s.SSHKnownHosts,
+					BashComplete: s.Complete,
+				},
+				{
+					Name:  "askpass",
+					Usage: "SSH_ASKPASS helper used by 'gopass ssh add'",
+					Description: "" +
+						"Prints the passphrase of the key that is being added by 'gopass ssh add'. " +
+						"Not meant to be invoked directly.",
+					Hidden: true,
+					Before: s.IsInitialized,
+					Action: s.SSHAskPass,
+				},
+			},
+		},
 		{
 			Name:      "sum",
 			Usage:     "Compute the SHA256 checksum",
@@ -939,6 +1926,10 @@ func (s *Action) GetCommands() []*cli.Command {
 					Aliases: []string{"s"},
 					Usage:   "Select the store to sync",
 				},
+				&cli.BoolFlag{
+					Name:  "status",
+					Usage: "Only show how far each store is ahead of or behind its remote, when it was last synced and if it has uncommitted changes",
+				},
 			},
 		},
 		{
@@ -979,6 +1970,35 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:      "trust",
+			Usage:     "Make a public key valid after verifying its fingerprint",
+			ArgsUsage: "[key]",
+			Description: "" +
+				"This command shows the fingerprint of the key and asks you to confirm that " +
+				"you verified it with its owner. Afterwards it makes the key valid in the " +
+				"trust model of the store: it signs the key locally for the pgp models and sets " +
+				"its TOFU policy to good for the tofu models. The trust model of a store is " +
+				"set with the trustmodel store option.",
+			Before:       s.IsInitialized,
+			Action:       s.Trust,
+			BashComplete: s.RecipientsComplete,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "store",
+					Aliases: []string{"s"},
+					Usage:   "Use the trust model of this store",
+				},
+				&cli.StringFlag{
+					Name:  "ownertrust",
+					Usage: "Also set the owner trust of the key: unknown, never, marginal, full or ultimate",
+				},
+				&cli.BoolFlag{
+					Name:  "no-sign",
+					Usage: "Don't sign the key",
+				},
+			},
+		},
 		{
 			Name:        "unclip",
 			Usage:       "Internal command to clear clipboard",
@@ -996,6 +2016,18 @@ func (s *Action) GetCommands() []*cli.Command {
 				},
 			},
 		},
+		{
+			Name:      "unset",
+			Usage:     "Remove a single key from a secret",
+			ArgsUsage: "[secret]:[key]",
+			Description: "" +
+				"Remove a single key from an existing secret without touching the " +
+				"password or the rest of its content. Use 'insert secret:key value' to " +
+				"set a single key.",
+			Before:       s.IsInitialized,
+			Action:       s.Unset,
+			BashComplete: s.Complete,
+		},
 		{
 			Name:  "update",
 			Usage: "Check for updates",
@@ -1004,6 +2036,57 @@ func (s *Action) GetCommands() []*cli.Command {
 				"downloads and installs any missing update.",
 			Action: s.Update,
 		},
+		{
+			Name:  "vault",
+			Usage: "Import from or export to HashiCorp Vault",
+			Description: "" +
+				"These commands copy secrets between gopass and a KV version 2 secrets engine " +
+				"of HashiCorp Vault. The connection is configured with the usual VAULT_ADDR, " +
+				"VAULT_TOKEN and VAULT_NAMESPACE environment variables. Without a token " +
+				"VAULT_ROLE_ID and VAULT_SECRET_ID are used for an AppRole login.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "import",
+					Usage:     "Import secrets from Vault",
+					ArgsUsage: "[mount]",
+					Description: "" +
+						"Copies all secrets below --path of the KV mount into the store below --prefix. " +
+						"The 'password' and 'body' keys become the password and body of the secret, " +
+						"all other keys are stored as key-value pairs.",
+					Before: s.IsInitialized,
+					Action: s.VaultImport,
+					Flags: append(vaultFlags(),
+						&cli.BoolFlag{
+							Name:    "force",
+							Aliases: []string{"f"},
+							Usage:   "Overwrite existing secrets",
+						},
+					),
+				},
+				{
+					Name:      "export",
+					Usage:     "Export secrets to Vault",
+					ArgsUsage: "[mount]",
+					Description: "" +
+						"Copies the secret or all secrets below --prefix to --path of the KV mount. " +
+						"Existing secrets in Vault get a new version.",
+					Before: s.IsInitialized,
+					Action: s.VaultExport,
+					Flags:  vaultFlags(),
+				},
+			},
+		},
+		{
+			Name:      "verify-seal",
+			Usage:     "Check a store for changes since it was sealed",
+			ArgsUsage: "[store]",
+			Description: "" +
+				"Verifies the signature of the seal recorded by 'gopass seal' and lists all " +
+				"entries that were added, removed or modified since. Fails if anything changed.",
+			Before:       s.IsInitialized,
+			Action:       s.VerifySeal,
+			BashComplete: s.MountsComplete,
+		},
 		{
 			Name:  "version",
 			Usage: "Display version",
@@ -1013,5 +2096,37 @@ func (s *Action) GetCommands() []*cli.Command {
 				"Please provide the output when reporting issues.",
 			Action: s.Version,
 		},
-	}
+		{
+			Name:  "watch",
+			Usage: "Keep stores in sync with their remotes in the background",
+			Description: "" +
+				"Runs until interrupted and keeps all stores with a git remote in sync. " +
+				"Local changes are pushed as soon as they are detected, the remotes are " +
+				"checked for incoming changes every --interval seconds. Incoming changes " +
+				"trigger a desktop notification.",
+			Before: s.IsInitialized,
+			Action: s.Watch,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "store",
+					Aliases: []string{"s"},
+					Usage:   "Select the store to watch",
+				},
+				&cli.IntFlag{
+					Name:  "interval",
+					Usage: "Seconds between checks of the remotes",
+					Value: watchDefaultInterval,
+				},
+				&cli.IntFlag{
+					Name:  "poll",
+					Usage: "Seconds between checks for local changes",
+					Value: watchDefaultPoll,
+				},
+				&cli.StringFlag{
+					Name:  "status-addr",
+					Usage: "Serve a read-only JSON status page on this loopback address, e.g. 127.0.0.1:7777",
+				},
+			},
+		},
+	})
 }
@@ -1,6 +1,7 @@
 package action
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"runtime"
@@ -9,8 +10,8 @@ import (
 	fishcomp "github.com/gopasspw/gopass/internal/completion/fish"
 	zshcomp "github.com/gopasspw/gopass/internal/completion/zsh"
 	"github.com/gopasspw/gopass/internal/out"
-	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/urfave/cli/v2"
 )
 
@@ -34,14 +35,47 @@ func (s *Action) Complete(c *cli.Context) {
 		out.Errorf(ctx, "Store not initialized: %s", err)
 		return
 	}
-	list, err := s.Store.List(ctx, tree.INF)
+	// name:key completes the keys of a single secret.
+	if args := c.Args(); args.Len() > 0 && strings.Contains(args.Get(args.Len()-1), ":") {
+		if s.completeKeys(ctx, args.Get(args.Len()-1)) {
+			return
+		}
+	}
+
+	// print entries as they are found, large stores take a while to list
+	entries, err := s.Store.ListStream(ctx, "")
 	if err != nil {
 		return
 	}
 
-	for _, v := range list {
-		fmt.Fprintln(stdout, bashEscape(v))
+	for e := range entries {
+		if e.Err != nil {
+			out.Errorf(ctx, "Failed to list store: %s", e.Err)
+			return
+		}
+		fmt.Fprintln(stdout, bashEscape(e.Name))
+	}
+}
+
+// completeKeys prints name:key for every key of the secret in word, which is
+// of the form name:[prefix]. The secret must be decrypted to list its keys,
+// so this only works if the key is already unlocked, e.g. by the gpg-agent.
+func (s *Action) completeKeys(ctx context.Context, word string) bool {
+	name := word[:strings.LastIndex(word, ":")]
+	if name == "" || !s.Store.Exists(ctx, name) {
+		return false
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		debug.Log("Failed to decrypt %s: %s", name, err)
+		return false
+	}
+
+	for _, key := range sec.Keys() {
+		fmt.Fprintln(stdout, bashEscape(name+":"+key))
 	}
+	return true
 }
 
 // CompletionOpenBSDKsh returns an OpenBSD ksh script used for auto completion.
@@ -73,6 +107,17 @@ func (s *Action) CompletionBash(c *cli.Context) error {
      local cur opts base
      COMPREPLY=()
      cur="${COMP_WORDS[COMP_CWORD]}"
+     # bash splits name:key into several words, complete the whole word.
+     base="${COMP_LINE:0:$COMP_POINT}"
+     if [[ "${base##* }" == *:* ]]; then
+          cur="${base##* }"
+          opts=$( ${base% *} "${cur}" --generate-bash-completion )
+          local IFS=$'\n'
+          COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+          # only the part after the last colon is replaced.
+          COMPREPLY=( "${COMPREPLY[@]#"${cur%"${cur##*:}"}"}" )
+          return 0
+     fi
      opts=$( ${COMP_WORDS[@]:0:$COMP_CWORD} --generate-bash-completion )
      local IFS=$'\n'
      COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/gopasspw/gopass/tests/gptest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,6 +55,23 @@ func TestComplete(t *testing.T) {
 		assert.Equal(t, "foo\n", buf.String())
 	})
 
+	t.Run("complete keys", func(t *testing.T) {
+		defer buf.Reset()
+
+		sec := secrets.NewKV()
+		sec.SetPassword("secret")
+		require.NoError(t, sec.Set("user", "alice"))
+		require.NoError(t, act.Store.Set(ctx, "web", sec))
+
+		act.Complete(gptest.CliCtx(ctx, t, "web:"))
+		assert.Equal(t, "web:user\n", buf.String())
+		buf.Reset()
+
+		// unknown secrets complete the names.
+		act.Complete(gptest.CliCtx(ctx, t, "missing:"))
+		assert.Equal(t, "foo\nweb\n", buf.String())
+	})
+
 	t.Run("bash completion", func(t *testing.T) {
 		defer buf.Reset()
 
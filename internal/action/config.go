@@ -13,6 +13,10 @@ import (
 // Config handles changes to the gopass configuration.
 func (s *Action) Config(c *cli.Context) error {
 	ctx := ctxutil.WithGlobalFlags(c)
+	if c.IsSet("store") {
+		return s.storeConfig(ctx, c.String("store"), c.Args())
+	}
+
 	if c.Args().Len() < 1 {
 		s.printConfigValues(ctx)
 		return nil
@@ -51,6 +55,41 @@ func (s *Action) printConfigValues(ctx context.Context, needles ...string) {
 	}
 }
 
+// storeConfig prints or sets the options of a single store.
+func (s *Action) storeConfig(ctx context.Context, store string, args cli.Args) error {
+	if _, found := s.cfg.Mounts[store]; store != "" && !found {
+		return ExitError(ExitMount, nil, "store %q is not mounted", store)
+	}
+
+	switch args.Len() {
+	case 0:
+		s.printStoreConfigValues(ctx, store)
+		return nil
+	case 1:
+		s.printStoreConfigValues(ctx, store, args.Get(0))
+		return nil
+	case 2:
+		if err := s.cfg.SetStoreConfigValue(store, args.Get(0), args.Get(1)); err != nil {
+			return ExitError(ExitUnknown, err, "Error setting config value")
+		}
+		s.printStoreConfigValues(ctx, store, args.Get(0))
+		return nil
+	default:
+		return ExitError(ExitUsage, nil, "Usage: %s config --store name key value", s.Name)
+	}
+}
+
+func (s *Action) printStoreConfigValues(ctx context.Context, store string, needles ...string) {
+	m := s.cfg.StoreConfig(store).ConfigMap()
+	for _, k := range filterMap(m, needles) {
+		if len(needles) == 1 {
+			out.Printf(ctx, "%s", m[k])
+			continue
+		}
+		out.Printf(ctx, "%s: %s", k, m[k])
+	}
+}
+
 func filterMap(haystack map[string]string, needles []string) []string {
 	out := make([]string, 0, len(haystack))
 	for k := range haystack {
@@ -37,16 +37,23 @@ func TestConfig(t *testing.T) {
 
 		c := gptest.CliCtx(ctx, t)
 		assert.NoError(t, act.Config(c))
-		want := `autoclip: true
+		want := `auditlog: false
+autoclip: true
 autoimport: true
 cliptimeout: 45
+decoy: 
 exportkeys: true
+gpgtimeout: 0
+keycache: false
 nopager: false
 notifications: true
 parsing: true
 `
 		want += "path: " + u.StoreDir("") + "\n"
-		want += `safecontent: false
+		want += `pinentrytimeout: 0
+safecontent: false
+showaction: print
+usagelog: false
 `
 		assert.Equal(t, want, buf.String())
 	})
@@ -77,16 +84,23 @@ parsing: true
 		defer buf.Reset()
 
 		act.printConfigValues(ctx)
-		want := `autoclip: true
+		want := `auditlog: false
+autoclip: true
 autoimport: true
 cliptimeout: 45
+decoy: 
 exportkeys: true
+gpgtimeout: 0
+keycache: false
 nopager: true
 notifications: true
 parsing: true
 `
 		want += "path: " + u.StoreDir("") + "\n"
-		want += `safecontent: false`
+		want += `pinentrytimeout: 0
+safecontent: false
+showaction: print
+usagelog: false`
 		assert.Equal(t, want, strings.TrimSpace(buf.String()), "action.printConfigValues")
 
 		delete(act.cfg.Mounts, "foo")
@@ -112,20 +126,43 @@ parsing: true
 		defer buf.Reset()
 
 		act.ConfigComplete(gptest.CliCtx(ctx, t))
-		want := `autoclip
+		want := `auditlog
+autoclip
 autoimport
 cliptimeout
+decoy
 exportkeys
+gpgtimeout
+keycache
 nopager
 notifications
 parsing
 path
+pinentrytimeout
 remote
 safecontent
+showaction
+usagelog
 `
 		assert.Equal(t, want, buf.String())
 	})
 
+	t.Run("set store option", func(t *testing.T) {
+		defer buf.Reset()
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"store": ""}, "hiddenrecipients", "true")
+		assert.NoError(t, act.Config(c))
+		assert.Equal(t, "true", strings.TrimSpace(buf.String()))
+		assert.True(t, act.cfg.StoreConfig("").HiddenRecipients)
+	})
+
+	t.Run("set option of unknown store", func(t *testing.T) {
+		defer buf.Reset()
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"store": "foo"}, "hiddenrecipients", "true")
+		assert.Error(t, act.Config(c))
+	})
+
 	t.Run("set autoimport to invalid value", func(t *testing.T) {
 		defer buf.Reset()
 
@@ -12,6 +12,7 @@ const (
 	ctxKeyKey
 	ctxKeyOnlyClip
 	ctxKeyAlsoClip
+	ctxKeyCertInfo
 )
 
 // WithClip returns a context with the value for clip (for copy to clipboard)
@@ -126,3 +127,17 @@ func GetKey(ctx context.Context) string {
 	}
 	return sv
 }
+
+// WithCertInfo returns a context with the value of cert info set.
+func WithCertInfo(ctx context.Context, bv bool) context.Context {
+	return context.WithValue(ctx, ctxKeyCertInfo, bv)
+}
+
+// IsCertInfo returns the value of cert info or the default (false).
+func IsCertInfo(ctx context.Context) bool {
+	bv, ok := ctx.Value(ctxKeyCertInfo).(bool)
+	if !ok {
+		return false
+	}
+	return bv
+}
@@ -60,8 +60,8 @@ func TestCopy(t *testing.T) {
 	buf.Reset()
 
 	// insert bam/baz
-	assert.NoError(t, act.insertStdin(ctx, "bam/baz", []byte("foobar"), false))
-	assert.NoError(t, act.insertStdin(ctx, "bam/zab", []byte("barfoo"), false))
+	assert.NoError(t, act.insertStdin(ctx, "bam/baz", []byte("foobar"), false, nil))
+	assert.NoError(t, act.insertStdin(ctx, "bam/zab", []byte("barfoo"), false, nil))
 
 	// recursive copy: bam/ -> zab
 	c = gptest.CliCtx(ctx, t, "bam", "zab")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/termio"
@@ -42,6 +43,10 @@ func (s *Action) Delete(c *cli.Context) error {
 		}
 	}
 
+	if err := s.preHook(ctx, config.HookPreDelete, name, nil); err != nil {
+		return err
+	}
+
 	if recursive && key == "" {
 		debug.Log("pruning %q", name)
 		if err := s.Store.Prune(ctx, name); err != nil {
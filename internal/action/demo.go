@@ -0,0 +1,96 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/store/root"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+)
+
+// demoSecrets are the entries of the demo store.
+var demoSecrets = map[string]string{
+	"email/example.com/john":  "8bzq3tZVN6DeS8rp\nusername: john\nurl: https://mail.example.com\n",
+	"email/example.org/jane":  "correct horse battery staple\nusername: jane@example.org\n",
+	"social/mastodon/john":    "Vqf5e9ypk7Hu4Ttn\nusername: @john@example.social\n",
+	"banking/acme-bank/login": "M4tKb5nQzX2wL9vR\nusername: 12345678\npin: 4711\n",
+	"servers/web01/root":      "Xa7EekahngeeK9ai\nurl: ssh://web01.example.com\n",
+	"misc/wifi/home":          "two-little-penguins-dance\nssid: home\n",
+}
+
+// InitDemo switches gopass to a temporary store that uses the plaintext
+// mock crypto backend and is populated with some example secrets. Nothing
+// outside of the temporary directory is touched. The returned func removes
+// the demo store again.
+func (s *Action) InitDemo(ctx context.Context) (context.Context, func(), error) {
+	td, err := os.MkdirTemp("", "gopass-demo-")
+	if err != nil {
+		return ctx, nil, fmt.Errorf("failed to create demo dir: %w", err)
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(td); err != nil {
+			debug.Log("failed to remove demo dir %s: %s", td, err)
+		}
+	}
+
+	// make sure neither we nor any child processes pick up the users
+	// config or keys.
+	for k, v := range map[string]string{
+		"GOPASS_HOMEDIR":            td,
+		"GOPASS_CONFIG":             filepath.Join(td, "config.yml"),
+		"GOPASS_DISABLE_ENCRYPTION": "true",
+		"GNUPGHOME":                 filepath.Join(td, ".gnupg"),
+	} {
+		if err := os.Setenv(k, v); err != nil {
+			cleanup()
+			return ctx, nil, fmt.Errorf("failed to set %s: %w", k, err)
+		}
+	}
+
+	cfg := config.New()
+	cfg.Path = filepath.Join(td, "store")
+	// the mock backend has no real keys to export
+	cfg.ExportKeys = false
+	s.cfg = cfg
+	s.Store = root.New(cfg)
+
+	ctx = cfg.WithContext(ctx)
+	ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+	ctx = backend.WithStorageBackend(ctx, backend.FS)
+	ctx = ctxutil.WithGitCommit(ctx, false)
+
+	// don't clutter the output of the actual command
+	initCtx := ctxutil.WithHidden(ctx, true)
+	if _, err := s.Store.IsInitialized(initCtx); err != nil {
+		cleanup()
+		return ctx, nil, fmt.Errorf("failed to initialize demo store: %w", err)
+	}
+	ids, _ := s.Store.Crypto(ctx, "").ListIdentities(initCtx)
+	if err := s.Store.Init(initCtx, "", cfg.Path, ids...); err != nil {
+		cleanup()
+		return ctx, nil, fmt.Errorf("failed to initialize demo store: %w", err)
+	}
+
+	names := make([]string, 0, len(demoSecrets))
+	for name := range demoSecrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sec := secrets.ParsePlain([]byte(demoSecrets[name]))
+		if err := s.Store.Set(ctxutil.WithCommitMessage(initCtx, "Demo secret"), name, sec); err != nil {
+			cleanup()
+			return ctx, nil, fmt.Errorf("failed to write demo secret %s: %w", name, err)
+		}
+	}
+
+	debug.Log("demo store initialized at %s", cfg.Path)
+	return ctx, cleanup, nil
+}
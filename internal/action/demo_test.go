@@ -0,0 +1,55 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	_ "github.com/gopasspw/gopass/internal/backend/crypto"
+	_ "github.com/gopasspw/gopass/internal/backend/storage"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitDemo(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	// InitDemo modifies the environment, make sure it's restored
+	for _, k := range []string{"GOPASS_HOMEDIR", "GOPASS_CONFIG", "GOPASS_DISABLE_ENCRYPTION", "GNUPGHOME"} {
+		t.Setenv(k, os.Getenv(k))
+	}
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	ctx := context.Background()
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	ctx, cleanup, err := act.InitDemo(ctx)
+	require.NoError(t, err)
+
+	dir := os.Getenv("GOPASS_HOMEDIR")
+	assert.NotEqual(t, u.Dir, dir)
+	assert.DirExists(t, dir)
+
+	entries, err := act.Store.List(ctx, tree.INF)
+	require.NoError(t, err)
+	assert.Len(t, entries, len(demoSecrets))
+
+	sec, err := act.Store.Get(ctx, "email/example.org/jane")
+	require.NoError(t, err)
+	assert.Equal(t, "correct horse battery staple", sec.Password())
+
+	cleanup()
+	assert.NoDirExists(t, dir)
+}
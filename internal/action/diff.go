@@ -0,0 +1,307 @@
+package action
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/diff"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/urfave/cli/v2"
+)
+
+const diffCurrent = "current"
+
+// Diff shows the changes of a secret between two revisions or, with --stores,
+// the differences between two mounts.
+func (s *Action) Diff(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	if c.Bool("stores") {
+		if c.Args().Len() != 2 {
+			return ExitError(ExitUsage, nil, "Usage: %s diff --stores <MOUNT> <MOUNT>", s.Name)
+		}
+		return s.diffStores(ctx, c.Args().Get(0), c.Args().Get(1))
+	}
+
+	name := c.Args().First()
+	if name == "" || c.Args().Len() > 3 {
+		return ExitError(ExitUsage, nil, "Usage: %s diff <NAME> [REVISION [REVISION]]", s.Name)
+	}
+	if !s.Store.Exists(ctx, name) {
+		return ExitError(ExitNotFound, nil, "Secret %s not found", name)
+	}
+
+	oldRev, newRev, err := s.diffRevisions(ctx, name, c.Args().Get(1), c.Args().Get(2))
+	if err != nil {
+		return ExitError(ExitUnknown, err, "Failed to get revisions: %s", err)
+	}
+
+	oldSec, err := s.diffGet(ctx, name, oldRev)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "Failed to read %s@%s: %s", name, oldRev, err)
+	}
+	newSec, err := s.diffGet(ctx, name, newRev)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "Failed to read %s@%s: %s", name, newRev, err)
+	}
+
+	lines := diffSecrets(oldSec, newSec, ctxutil.IsShowSafeContent(ctx) && !ctxutil.IsForce(ctx))
+	if len(lines) < 1 {
+		out.OKf(ctx, "No changes between %s and %s", diffLabel(oldRev), diffLabel(newRev))
+		return nil
+	}
+
+	out.Printf(ctx, "--- %s@%s", name, diffLabel(oldRev))
+	out.Printf(ctx, "+++ %s@%s", name, diffLabel(newRev))
+	for _, line := range lines {
+		switch line[0] {
+		case '-':
+			line = color.RedString(line)
+		case '+':
+			line = color.GreenString(line)
+		}
+		out.Printf(ctx, "%s", line)
+	}
+
+	return nil
+}
+
+// diffRevisions resolves the revisions to compare. Without any revision the
+// previous revision is compared to the current content, a single revision is
+// compared to the current content.
+func (s *Action) diffRevisions(ctx context.Context, name, revA, revB string) (string, string, error) {
+	if revB == "" {
+		revB = diffCurrent
+	}
+
+	if revA == "" {
+		revs, err := s.Store.ListRevisions(ctx, name)
+		if err != nil {
+			return "", "", err
+		}
+		if len(revs) < 2 {
+			// nothing to compare with, show everything as added.
+			return "", revB, nil
+		}
+		// revisions are sorted from newest to oldest.
+		return revs[1].Hash, revB, nil
+	}
+
+	var err error
+	revA, err = s.parseRevision(ctx, name, revA)
+	if err != nil {
+		return "", "", err
+	}
+	if revB != diffCurrent {
+		revB, err = s.parseRevision(ctx, name, revB)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return revA, revB, nil
+}
+
+// diffGet returns the secret at the given revision. An empty revision yields
+// no secret.
+func (s *Action) diffGet(ctx context.Context, name, revision string) (gopass.Secret, error) {
+	switch revision {
+	case "":
+		return nil, nil
+	case diffCurrent:
+		return s.Store.Get(ctx, name)
+	default:
+		_, sec, err := s.Store.GetRevision(ctx, name, revision)
+		return sec, err
+	}
+}
+
+func diffLabel(revision string) string {
+	if revision == "" {
+		return "(none)"
+	}
+	if len(revision) > 8 && revision != diffCurrent {
+		return revision[:8]
+	}
+	return revision
+}
+
+// diffSecrets returns the changed fields of two secrets, one line per removed
+// or added value. Either secret can be nil.
+func diffSecrets(a, b gopass.Secret, mask bool) []string {
+	var lines []string
+
+	pwA, pwB := diffPassword(a), diffPassword(b)
+	if pwA != pwB {
+		if mask {
+			pwA, pwB = maskPassword(pwA), maskPassword(pwB)
+		}
+		if a != nil {
+			lines = append(lines, "- password: "+pwA)
+		}
+		if b != nil {
+			lines = append(lines, "+ password: "+pwB)
+		}
+	}
+
+	keys := set.Sorted(append(diffKeys(a), diffKeys(b)...))
+	for _, key := range keys {
+		va, vb := diffValues(a, key), diffValues(b, key)
+		if strings.Join(va, "\n") == strings.Join(vb, "\n") {
+			continue
+		}
+		for _, v := range va {
+			lines = append(lines, fmt.Sprintf("- %s: %s", key, v))
+		}
+		for _, v := range vb {
+			lines = append(lines, fmt.Sprintf("+ %s: %s", key, v))
+		}
+	}
+
+	var changed bool
+	var body []string
+	for _, l := range diff.Lines(diffBody(a), diffBody(b)) {
+		switch l.Op {
+		case diff.Removed:
+			changed = true
+			body = append(body, "-   "+l.Text)
+		case diff.Added:
+			changed = true
+			body = append(body, "+   "+l.Text)
+		}
+	}
+	if changed {
+		lines = append(lines, "  body:")
+		lines = append(lines, body...)
+	}
+
+	return lines
+}
+
+func maskPassword(pw string) string {
+	if pw == "" {
+		return ""
+	}
+	return "*****"
+}
+
+func diffPassword(sec gopass.Secret) string {
+	if sec == nil {
+		return ""
+	}
+	return sec.Password()
+}
+
+func diffKeys(sec gopass.Secret) []string {
+	if sec == nil {
+		return nil
+	}
+	return sec.Keys()
+}
+
+func diffValues(sec gopass.Secret, key string) []string {
+	if sec == nil {
+		return nil
+	}
+	vs, _ := sec.Values(key)
+	return vs
+}
+
+func diffBody(sec gopass.Secret) []string {
+	if sec == nil {
+		return nil
+	}
+	body := strings.TrimRight(sec.Body(), "\n")
+	if body == "" {
+		return nil
+	}
+	return strings.Split(body, "\n")
+}
+
+// diffStores compares two mounts by the names of their secrets and the hash
+// of the decrypted content.
+func (s *Action) diffStores(ctx context.Context, a, b string) error {
+	if a == "root" {
+		a = ""
+	}
+	if b == "root" {
+		b = ""
+	}
+
+	hashA, err := s.diffStoreHashes(ctx, a)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "Failed to read store %s: %s", watchName(a), err)
+	}
+	hashB, err := s.diffStoreHashes(ctx, b)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "Failed to read store %s: %s", watchName(b), err)
+	}
+
+	names := make([]string, 0, len(hashA)+len(hashB))
+	for name := range hashA {
+		names = append(names, name)
+	}
+	for name := range hashB {
+		names = append(names, name)
+	}
+	names = set.Sorted(names)
+
+	var onlyA, onlyB, changed int
+	for _, name := range names {
+		ha, inA := hashA[name]
+		hb, inB := hashB[name]
+		switch {
+		case !inB:
+			onlyA++
+			out.Printf(ctx, "%s", color.RedString("- %s", name))
+		case !inA:
+			onlyB++
+			out.Printf(ctx, "%s", color.GreenString("+ %s", name))
+		case ha != hb:
+			changed++
+			out.Printf(ctx, "%s", color.YellowString("~ %s", name))
+		}
+	}
+
+	if onlyA+onlyB+changed == 0 {
+		out.OKf(ctx, "%s and %s contain the same %d secrets", watchName(a), watchName(b), len(names))
+		return nil
+	}
+	out.Printf(ctx, "%d only in %s, %d only in %s, %d differ", onlyA, watchName(a), onlyB, watchName(b), changed)
+
+	return nil
+}
+
+// diffStoreHashes returns the SHA256 of the decrypted content of every secret
+// in the given mount, keyed by the name relative to the mount.
+func (s *Action) diffStoreHashes(ctx context.Context, mp string) (map[string]string, error) {
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := sub.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]string, len(names))
+	for _, name := range names {
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", name, err)
+		}
+		rel := strings.TrimPrefix(name, mp+"/")
+		hashes[rel] = fmt.Sprintf("%x", sha256.Sum256(sec.Bytes()))
+		debug.Log("hashed %s as %s", name, rel)
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,151 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	git "github.com/gopasspw/gopass/internal/backend/storage/gitfs"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSecrets(t *testing.T) {
+	t.Parallel()
+
+	a := secrets.NewKV()
+	a.SetPassword("old")
+	require.NoError(t, a.Set("user", "admin"))
+	require.NoError(t, a.Set("url", "https://example.org"))
+	a.Write([]byte("line1\nline2\n"))
+
+	b := secrets.NewKV()
+	b.SetPassword("new")
+	require.NoError(t, b.Set("user", "admin"))
+	require.NoError(t, b.Set("url", "https://example.com"))
+	b.Write([]byte("line1\nline3\n"))
+
+	assert.Equal(t, []string{
+		"- password: old",
+		"+ password: new",
+		"- url: https://example.org",
+		"+ url: https://example.com",
+		"  body:",
+		"-   line2",
+		"+   line3",
+	}, diffSecrets(a, b, false))
+
+	assert.Equal(t, []string{
+		"- password: *****",
+		"+ password: *****",
+	}, diffSecrets(a, b, true)[:2])
+
+	assert.Empty(t, diffSecrets(a, a, false))
+	assert.Equal(t, []string{
+		"+ password: new",
+		"+ url: https://example.com",
+		"+ user: admin",
+		"  body:",
+		"+   line1",
+		"+   line3",
+	}, diffSecrets(nil, b, false))
+}
+
+func TestDiff(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	_, err := git.Init(ctx, u.StoreDir(""), "Nobody", "foo.bar@example.org")
+	require.NoError(t, err)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	for _, pw := range []string{"first", "second"} {
+		sec := secrets.New()
+		sec.SetPassword(pw)
+		require.NoError(t, sec.Set("user", "admin"))
+		require.NoError(t, act.Store.Set(ctx, "db/prod", sec))
+	}
+
+	t.Run("no argument", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Diff(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Diff(gptest.CliCtx(ctx, t, "db/missing")))
+	})
+
+	t.Run("last change", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Diff(gptest.CliCtx(ctx, t, "db/prod")))
+		assert.Contains(t, buf.String(), "- password: first\n+ password: second\n")
+		assert.NotContains(t, buf.String(), "user")
+	})
+
+	t.Run("revisions", func(t *testing.T) {
+		defer buf.Reset()
+
+		revs, err := act.Store.ListRevisions(ctx, "db/prod")
+		require.NoError(t, err)
+		require.Len(t, revs, 2)
+
+		require.NoError(t, act.Diff(gptest.CliCtx(ctx, t, "db/prod", revs[0].Hash, revs[1].Hash)))
+		assert.Contains(t, buf.String(), "- password: second\n+ password: first\n")
+
+		buf.Reset()
+		require.NoError(t, act.Diff(gptest.CliCtx(ctx, t, "db/prod", revs[0].Hash)))
+		assert.Contains(t, buf.String(), "No changes")
+	})
+
+	t.Run("stores", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, u.InitStore("replica"))
+		require.NoError(t, act.Store.AddMount(ctx, "replica", u.StoreDir("replica")))
+		buf.Reset()
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"stores": "true"}, "root")
+		assert.Error(t, act.Diff(c))
+
+		sec, err := act.Store.Get(ctx, "db/prod")
+		require.NoError(t, err)
+		require.NoError(t, act.Store.Set(ctx, "replica/db/prod", sec))
+
+		c = gptest.CliCtxWithFlags(ctx, t, map[string]string{"stores": "true"}, "root", "replica")
+		require.NoError(t, act.Diff(c))
+		assert.Contains(t, buf.String(), "<root> and replica contain the same")
+
+		buf.Reset()
+		sec.SetPassword("third")
+		require.NoError(t, act.Store.Set(ctx, "replica/db/prod", sec))
+		require.NoError(t, act.Store.Set(ctx, "replica/only", sec))
+		require.NoError(t, act.Diff(c))
+		assert.Contains(t, buf.String(), "~ db/prod\n")
+		assert.Contains(t, buf.String(), "+ only\n")
+		assert.Contains(t, buf.String(), "0 only in <root>, 1 only in replica, 1 differ")
+	})
+}
@@ -0,0 +1,213 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/clipboard"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// keyExpiryWarning is how long before the expiry of a key doctor warns.
+const keyExpiryWarning = 30 * 24 * time.Hour
+
+type keyExpirer interface {
+	ExpirationDate(ctx context.Context, id string) time.Time
+}
+
+type binaryer interface {
+	Binary() string
+}
+
+type keyringChecker interface {
+	Keyring(ctx context.Context) gpgconf.Keyring
+	CheckKeyring(ctx context.Context) error
+}
+
+// doctor collects the results of the checks.
+type doctor struct {
+	problems int
+}
+
+func (d *doctor) ok(ctx context.Context, format string, args ...any) {
+	out.OKf(ctx, format, args...)
+}
+
+func (d *doctor) fail(ctx context.Context, fix, format string, args ...any) {
+	d.problems++
+	out.Warningf(ctx, format, args...)
+	out.Printf(ctx, "  Fix: %s", fix)
+}
+
+// Doctor checks the environment gopass depends on and prints how to fix any
+// problems found.
+func (s *Action) Doctor(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	d := &doctor{}
+
+	s.doctorConfig(ctx, d)
+
+	mps := append([]string{""}, s.Store.MountPoints()...)
+	stores := make(map[string]*leaf.Store, len(mps))
+	for _, mp := range mps {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil || sub == nil {
+			d.fail(ctx, fmt.Sprintf("Run '%s mounts remove %s' or fix the path in %s", s.Name, mp, s.cfg.ConfigPath), "Mount %q can not be loaded: %s", mp, err)
+			continue
+		}
+		stores[mp] = sub
+	}
+
+	s.doctorBinaries(ctx, d, stores)
+	for _, mp := range mps {
+		if sub, found := stores[mp]; found {
+			s.doctorStore(ctx, d, mp, sub)
+		}
+	}
+	s.doctorKeys(ctx, d, stores)
+
+	switch err := clipboard.Check(); {
+	case errors.Is(err, clipboard.ErrX11Only):
+		d.fail(ctx, "Install wl-clipboard", "Clipboard is X11 only, Wayland applications can't access copied secrets")
+	case err != nil:
+		d.fail(ctx, "Install xclip, xsel or wl-clipboard", "No clipboard tool found")
+	default:
+		d.ok(ctx, "Clipboard is available")
+	}
+
+	if d.problems > 0 {
+		return ExitError(ExitUnknown, nil, "Found %d problems", d.problems)
+	}
+	out.OKf(ctx, "Everything looks fine")
+
+	return nil
+}
+
+func (s *Action) doctorConfig(ctx context.Context, d *doctor) {
+	if err := s.cfg.CheckOverflow(); err != nil {
+		d.fail(ctx, fmt.Sprintf("Remove the unknown options from %s", s.cfg.ConfigPath), "Config is invalid: %s", err)
+		return
+	}
+	d.ok(ctx, "Config is valid")
+}
+
+// doctorBinaries checks the external binaries used by any of the stores.
+func (s *Action) doctorBinaries(ctx context.Context, d *doctor, stores map[string]*leaf.Store) {
+	var gpgChecked, gitChecked bool
+	for _, sub := range stores {
+		if crypto := sub.Crypto(); crypto != nil && crypto.Name() == "gpg" && !gpgChecked {
+			gpgChecked = true
+			bin := "gpg"
+			if b, ok := crypto.(binaryer); ok {
+				bin = b.Binary()
+			}
+			if v := crypto.Version(ctx); v.Major < 2 {
+				d.fail(ctx, "Install GnuPG 2.2 or newer", "%s is too old or not working (version %s)", bin, v)
+			} else {
+				d.ok(ctx, "Using %s %s", bin, v)
+			}
+
+			s.doctorKeyring(ctx, d, crypto)
+
+			if err := gpgconf.PingAgent(ctx); err != nil {
+				d.fail(ctx, "Run 'gpgconf --launch gpg-agent' and check its log", "gpg-agent is not reachable: %s", err)
+			} else {
+				d.ok(ctx, "gpg-agent is running")
+			}
+		}
+
+		if storage := sub.Storage(); storage != nil && storage.Name() == "git" && !gitChecked {
+			gitChecked = true
+			if _, err := exec.LookPath("git"); err != nil {
+				d.fail(ctx, "Install git", "git not found: %s", err)
+				continue
+			}
+			d.ok(ctx, "Using git %s", storage.Version(ctx))
+		}
+	}
+}
+
+// doctorKeyring checks that gpg can use the format of the keyring.
+func (s *Action) doctorKeyring(ctx context.Context, d *doctor, crypto any) {
+	kc, ok := crypto.(keyringChecker)
+	if !ok {
+		return
+	}
+	k := kc.Keyring(ctx)
+	if err := kc.CheckKeyring(ctx); err != nil {
+		d.fail(ctx, "Install GnuPG 2.2 or newer", "Keyring in %s can not be used: %s", k.Dir, err)
+		return
+	}
+	d.ok(ctx, "Keyring in %s uses the %s format", k.Dir, k.Format)
+}
+
+// doctorStore checks that a store exists, is initialized and private.
+func (s *Action) doctorStore(ctx context.Context, d *doctor, mp string, sub *leaf.Store) {
+	name := mp
+	if name == "" {
+		name = "<root>"
+	}
+
+	fi, err := os.Stat(sub.Path())
+	if err != nil {
+		d.fail(ctx, fmt.Sprintf("Run '%s clone' or '%s init --store %s'", s.Name, s.Name, mp), "Store %s at %s is missing: %s", name, sub.Path(), err)
+		return
+	}
+	if !sub.IsInitialized(ctx) {
+		d.fail(ctx, fmt.Sprintf("Run '%s init --store %s'", s.Name, mp), "Store %s at %s has no recipients", name, sub.Path())
+		return
+	}
+	if fi.Mode().Perm()&0o077 != 0 {
+		d.fail(ctx, fmt.Sprintf("Run 'chmod -R go-rwx %s'", sub.Path()), "Store %s at %s is accessible by other users (%s)", name, sub.Path(), fi.Mode().Perm())
+		return
+	}
+	d.ok(ctx, "Store %s at %s is healthy", name, sub.Path())
+}
+
+// doctorKeys checks that none of the private keys expired or expire soon.
+func (s *Action) doctorKeys(ctx context.Context, d *doctor, stores map[string]*leaf.Store) {
+	seen := make(map[string]bool)
+	for _, sub := range stores {
+		crypto := sub.Crypto()
+		if crypto == nil || seen[crypto.Name()] {
+			continue
+		}
+		seen[crypto.Name()] = true
+
+		ids, err := crypto.ListIdentities(ctx)
+		if err != nil {
+			d.fail(ctx, "Check your keyring", "Failed to list private keys: %s", err)
+			continue
+		}
+		if len(ids) < 1 {
+			d.fail(ctx, fmt.Sprintf("Run '%s setup' to create a key", s.Name), "No usable private key found for %s", crypto.Name())
+			continue
+		}
+
+		exp, ok := crypto.(keyExpirer)
+		if !ok {
+			continue
+		}
+		for _, id := range ids {
+			t := exp.ExpirationDate(ctx, id)
+			switch {
+			case t.IsZero():
+				d.ok(ctx, "Key %s never expires", crypto.FormatKey(ctx, id, ""))
+			case time.Until(t) < 0:
+				d.fail(ctx, fmt.Sprintf("Run 'gpg --quick-set-expire %s 1y'", id), "Key %s expired on %s", crypto.FormatKey(ctx, id, ""), t.Format("2006-01-02"))
+			case time.Until(t) < keyExpiryWarning:
+				d.fail(ctx, fmt.Sprintf("Run 'gpg --quick-set-expire %s 1y'", id), "Key %s expires on %s", crypto.FormatKey(ctx, id, ""), t.Format("2006-01-02"))
+			default:
+				d.ok(ctx, "Key %s expires on %s", crypto.FormatKey(ctx, id, ""), t.Format("2006-01-02"))
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/atotto/clipboard"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoctor(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		clipboard.Unsupported = false
+	}()
+
+	require.NoError(t, os.Chmod(u.StoreDir(""), 0o700))
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("XDG_SESSION_TYPE", "")
+
+	t.Run("healthy", func(t *testing.T) {
+		defer buf.Reset()
+		clipboard.Unsupported = false
+		require.NoError(t, act.Doctor(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "Config is valid")
+		assert.Contains(t, buf.String(), "Store <root> at "+u.StoreDir("")+" is healthy")
+		assert.Contains(t, buf.String(), "Everything looks fine")
+	})
+
+	t.Run("problems", func(t *testing.T) {
+		defer buf.Reset()
+		clipboard.Unsupported = true
+		require.NoError(t, os.Chmod(u.StoreDir(""), 0o755))
+		act.cfg.XXX = map[string]any{"foo": "bar"}
+		defer func() {
+			act.cfg.XXX = nil
+		}()
+
+		assert.Error(t, act.Doctor(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "Config is invalid")
+		assert.Contains(t, buf.String(), "is accessible by other users")
+		assert.Contains(t, buf.String(), "Fix: Run 'chmod -R go-rwx "+u.StoreDir("")+"'")
+		assert.Contains(t, buf.String(), "No clipboard tool found")
+	})
+}
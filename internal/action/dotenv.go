@@ -0,0 +1,115 @@
+package action
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/dotenv"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+// Dotenv prints the passwords of a secret or all secrets below a folder in
+// the dotenv format or writes them to a file.
+func (s *Action) Dotenv(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := strings.TrimSuffix(c.Args().First(), "/")
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s dotenv <SECRET|FOLDER> [--file .env]", s.Name)
+	}
+
+	names, err := s.expandSecrets(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	vars := make([]dotenv.Var, 0, len(names))
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		vn := envName(name)
+		if other, found := seen[vn]; found {
+			return ExitError(ExitUsage, nil, "%s and %s both map to %s", other, name, vn)
+		}
+		seen[vn] = name
+
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+		vars = append(vars, dotenv.Var{Name: vn, Value: sec.Password()})
+	}
+
+	buf := &bytes.Buffer{}
+	if err := dotenv.Write(buf, vars); err != nil {
+		return ExitError(ExitUnknown, err, "failed to format variables: %s", err)
+	}
+
+	fn := c.String("file")
+	if fn == "" || fn == "-" {
+		_, _ = stdout.Write(buf.Bytes())
+		return nil
+	}
+
+	if err := writeSecretFile(fn, buf.Bytes()); err != nil {
+		return ExitError(ExitIO, err, "Failed to write %s: %s", fn, err)
+	}
+	out.OKf(ctx, "Wrote %d variables to %s", len(vars), fn)
+
+	return nil
+}
+
+// DotenvImport stores every variable of a dotenv file as a secret below the
+// given folder. The variable name becomes the name of the secret and the
+// value its password, so exporting the folder with dotenv yields the same
+// file again.
+func (s *Action) DotenvImport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	fn := c.Args().Get(0)
+	prefix := strings.Trim(c.Args().Get(1), "/")
+	if fn == "" || prefix == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s import dotenv <FILE> <FOLDER>", s.Name)
+	}
+
+	var r io.Reader = stdin
+	if fn != "-" {
+		fh, err := os.Open(fn)
+		if err != nil {
+			return ExitError(ExitIO, err, "Failed to open %s: %s", fn, err)
+		}
+		defer fh.Close()
+		r = fh
+	}
+
+	vars, err := dotenv.Parse(r)
+	if err != nil {
+		return ExitError(ExitIO, err, "Failed to parse %s: %s", fn, err)
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, "Imported from "+path.Base(fn))
+	var n int
+	for _, v := range vars {
+		dst := path.Join(prefix, v.Name)
+		if s.Store.Exists(ctx, dst) && !c.Bool("force") {
+			out.Warningf(ctx, "Not overwriting existing secret %s. Use --force to overwrite.", dst)
+			continue
+		}
+
+		sec := secrets.New()
+		sec.SetPassword(v.Value)
+
+		debug.Log("importing %s to %s", v.Name, dst)
+		if err := s.Store.Set(ctx, dst, sec); err != nil {
+			return ExitError(ExitEncrypt, err, "failed to write %s: %s", dst, err)
+		}
+		n++
+	}
+
+	out.OKf(ctx, "Imported %d secrets from %s", n, fn)
+	return nil
+}
@@ -0,0 +1,111 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotenv(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	for name, pw := range map[string]string{
+		"app/db-password": "hunter 2",
+		"app/api_key":     "abc123",
+	} {
+		sec := secrets.New()
+		sec.SetPassword(pw)
+		require.NoError(t, act.Store.Set(ctx, name, sec))
+	}
+	want := "API_KEY=abc123\nDB_PASSWORD='hunter 2'\n"
+
+	t.Run("no argument", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Dotenv(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("print folder", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Dotenv(gptest.CliCtx(ctx, t, "app")))
+		assert.Equal(t, want, buf.String())
+	})
+
+	envfile := filepath.Join(u.Dir, ".env")
+	t.Run("write file", func(t *testing.T) {
+		defer buf.Reset()
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"file": envfile}, "app/")
+		require.NoError(t, act.Dotenv(c))
+
+		got, err := os.ReadFile(envfile)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+
+		fi, err := os.Stat(envfile)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	})
+
+	t.Run("import", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.DotenvImport(gptest.CliCtx(ctx, t, envfile, "imported")))
+
+		sec, err := act.Store.Get(ctx, "imported/DB_PASSWORD")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter 2", sec.Password())
+
+		buf.Reset()
+		require.NoError(t, act.Dotenv(gptest.CliCtx(ctx, t, "imported")))
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("import does not overwrite", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, os.WriteFile(envfile, []byte("API_KEY=changed\n"), 0o600))
+		require.NoError(t, act.DotenvImport(gptest.CliCtx(ctx, t, envfile, "imported")))
+		sec, err := act.Store.Get(ctx, "imported/API_KEY")
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", sec.Password())
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, envfile, "imported")
+		require.NoError(t, act.DotenvImport(c))
+		sec, err = act.Store.Get(ctx, "imported/API_KEY")
+		require.NoError(t, err)
+		assert.Equal(t, "changed", sec.Password())
+	})
+
+	t.Run("import invalid file", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, os.WriteFile(envfile, []byte("not a dotenv file\n"), 0o600))
+		assert.Error(t, act.DotenvImport(gptest.CliCtx(ctx, t, envfile, "imported")))
+		assert.Error(t, act.DotenvImport(gptest.CliCtx(ctx, t, envfile)))
+	})
+}
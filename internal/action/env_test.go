@@ -41,7 +41,7 @@ func TestEnvLeafHappyPath(t *testing.T) {
 	// secret with value "secret". We expect to see the key/value in the output
 	// of the /usr/bin/env utility in the form "BAZ=secret".
 	pw := pwgen.GeneratePassword(24, false)
-	assert.NoError(t, act.insertStdin(ctx, "baz", []byte(pw), false))
+	assert.NoError(t, act.insertStdin(ctx, "baz", []byte(pw), false, nil))
 	buf.Reset()
 
 	assert.NoError(t, act.Env(gptest.CliCtx(ctx, t, "baz", "env")))
@@ -1,60 +1,122 @@
 package action
 
 import (
-	"fmt"
+	"context"
+	"errors"
 
+	"github.com/gopasspw/gopass/internal/i18n"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/urfave/cli/v2"
 )
 
+// Exit codes of gopass. They are part of the interface for scripts and
+// must never change. New codes are only ever added at the end.
 const (
 	// ExitOK means no error (status code 0).
-	ExitOK = iota
+	ExitOK = 0
 	// ExitUnknown is used if we can't determine the exact exit cause.
-	ExitUnknown
+	ExitUnknown = 1
 	// ExitUsage is used if there was some kind of invocation error.
-	ExitUsage
+	ExitUsage = 2
 	// ExitAborted is used if the user willingly aborted an action.
-	ExitAborted
+	ExitAborted = 3
 	// ExitUnsupported is used if an operation is not supported by gopass.
-	ExitUnsupported
+	ExitUnsupported = 4
 	// ExitAlreadyInitialized is used if someone is trying to initialize.
 	// an already initialized store.
-	ExitAlreadyInitialized
+	ExitAlreadyInitialized = 5
 	// ExitNotInitialized is used if someone is trying to use an unitialized.
 	// store.
-	ExitNotInitialized
+	ExitNotInitialized = 6
 	// ExitGit is used if any git errors are encountered.
-	ExitGit
+	ExitGit = 7
 	// ExitMount is used if a substore mount operation fails.
-	ExitMount
+	ExitMount = 8
 	// ExitNoName is used when no name was provided for a named entry.
-	ExitNoName
+	ExitNoName = 9
 	// ExitNotFound is used if a requested secret is not found.
-	ExitNotFound
+	ExitNotFound = 10
 	// ExitDecrypt is used when reading/decrypting a secret failed.
-	ExitDecrypt
+	ExitDecrypt = 11
 	// ExitEncrypt is used when writing/encrypting of a secret fails.
-	ExitEncrypt
+	ExitEncrypt = 12
 	// ExitList is used when listing the store content fails.
-	ExitList
+	ExitList = 13
 	// ExitAudit is used when audit report possible issues.
-	ExitAudit
+	ExitAudit = 14
 	// ExitFsck is used when the integrity check fails.
-	ExitFsck
+	ExitFsck = 15
 	// ExitConfig is used when config errors occur.
-	ExitConfig
+	ExitConfig = 16
 	// ExitRecipients is used when a recipient operation fails.
-	ExitRecipients
+	ExitRecipients = 17
 	// ExitIO is used for misc. I/O errors.
-	ExitIO
+	ExitIO = 18
 	// ExitGPG is used for misc. gpg errors.
-	ExitGPG
+	ExitGPG = 19
+	// ExitSyncConflict is used if syncing with a remote left merge conflicts.
+	ExitSyncConflict = 20
 )
 
+// exitNames are the stable names of the exit codes, used in the errors
+// printed with --porcelain.
+var exitNames = map[int]string{
+	ExitOK:                 "ok",
+	ExitUnknown:            "unknown",
+	ExitUsage:              "usage",
+	ExitAborted:            "aborted",
+	ExitUnsupported:        "unsupported",
+	ExitAlreadyInitialized: "already-initialized",
+	ExitNotInitialized:     "not-initialized",
+	ExitGit:                "git",
+	ExitMount:              "mount",
+	ExitNoName:             "no-name",
+	ExitNotFound:           "not-found",
+	ExitDecrypt:            "decrypt-failed",
+	ExitEncrypt:            "encrypt-failed",
+	ExitList:               "list-failed",
+	ExitAudit:              "audit",
+	ExitFsck:               "fsck",
+	ExitConfig:             "config",
+	ExitRecipients:         "recipients",
+	ExitIO:                 "io",
+	ExitGPG:                "gpg",
+	ExitSyncConflict:       "sync-conflict",
+}
+
+// ExitName returns the stable name of an exit code.
+func ExitName(code int) string {
+	if name, found := exitNames[code]; found {
+		return name
+	}
+	return exitNames[ExitUnknown]
+}
+
+// PorcelainError is written to stderr as JSON if gopass fails and was
+// invoked with --porcelain.
+type PorcelainError struct {
+	Code    int    `json:"code"`
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// NewPorcelainError returns the machine readable form of err.
+func NewPorcelainError(err error) PorcelainError {
+	code := ExitUnknown
+	var ec cli.ExitCoder
+	if errors.As(err, &ec) {
+		code = ec.ExitCode()
+	}
+	return PorcelainError{
+		Code:    code,
+		Error:   ExitName(code),
+		Message: err.Error(),
+	}
+}
+
 // ExitError returns a user friendly CLI error.
 func ExitError(exitCode int, err error, format string, args ...any) error {
-	msg := fmt.Sprintf(format, args...)
+	msg := i18n.Sprintf(context.Background(), format, args...)
 	if err != nil {
 		debug.LogN(1, "%s - stacktrace: %+v", msg, err)
 	}
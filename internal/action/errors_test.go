@@ -20,3 +20,21 @@ func TestExitError(t *testing.T) {
 	assert.Error(t, ExitError(ExitUnknown, fmt.Errorf("test"), "test"))
 	assert.NotContains(t, buf.String(), "Stacktrace")
 }
+
+func TestExitCodes(t *testing.T) {
+	// the exit codes are used by scripts and must never change
+	assert.Equal(t, 10, ExitNotFound)
+	assert.Equal(t, 11, ExitDecrypt)
+	assert.Equal(t, 20, ExitSyncConflict)
+
+	for code := ExitOK; code <= ExitSyncConflict; code++ {
+		assert.Contains(t, exitNames, code)
+	}
+	assert.Equal(t, "sync-conflict", ExitName(ExitSyncConflict))
+	assert.Equal(t, "unknown", ExitName(255))
+
+	perr := NewPorcelainError(ExitError(ExitDecrypt, nil, "failed"))
+	assert.Equal(t, PorcelainError{Code: 11, Error: "decrypt-failed", Message: "failed"}, perr)
+	perr = NewPorcelainError(fmt.Errorf("test"))
+	assert.Equal(t, ExitUnknown, perr.Code)
+}
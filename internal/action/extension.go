@@ -0,0 +1,82 @@
+package action
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// ExtensionPrefix is the prefix of the executables that unknown commands are
+// dispatched to, e.g. gopass foo runs gopass-foo.
+const ExtensionPrefix = "gopass-"
+
+var extensionName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// LookupExtension returns the path of the extension for the command name.
+func LookupExtension(name string) (string, bool) {
+	if !extensionName.MatchString(name) {
+		return "", false
+	}
+	p, err := exec.LookPath(ExtensionPrefix + name)
+	if err != nil {
+		return "", false
+	}
+	return p, true
+}
+
+// RunExtension runs the extension named by the first argument with the
+// remaining arguments. Secrets take precedence over extensions, so it returns
+// false if a secret of that name exists or there is no such extension.
+func (s *Action) RunExtension(c *cli.Context) (bool, error) {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if s.Store.Exists(ctx, name) {
+		return false, nil
+	}
+	p, found := LookupExtension(name)
+	if !found {
+		return false, nil
+	}
+
+	return true, s.AuditLogged(name, func(c *cli.Context) error {
+		return s.runExtension(c, name, p)
+	})(c)
+}
+
+func (s *Action) runExtension(c *cli.Context, name, p string) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	debug.Log("running extension %s: %s %v", name, p, c.Args().Tail())
+
+	binary, err := os.Executable()
+	if err != nil {
+		binary = s.Name
+	}
+
+	cmd := exec.CommandContext(ctx, p, c.Args().Tail()...)
+	cmd.Env = append(os.Environ(),
+		"GOPASS_BINARY="+binary,
+		"GOPASS_VERSION="+s.version.String(),
+		"GOPASS_EXTENSION="+name,
+		"GOPASS_STORE_DIR="+s.Store.Path(),
+		"PASSWORD_STORE_DIR="+s.Store.Path(),
+	)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			// the extension already reported the error.
+			return cli.Exit("", ee.ExitCode())
+		}
+		return ExitError(ExitUnknown, err, "failed to run extension %s: %s", name, err)
+	}
+
+	return nil
+}
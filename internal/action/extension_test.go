@@ -0,0 +1,109 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/auditlog"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestRunExtension(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("extensions are shell scripts")
+	}
+
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	script := "#!/bin/sh\necho \"$GOPASS_EXTENSION $PASSWORD_STORE_DIR $*\"\nexit $1\n"
+	for _, name := range []string{"hello", "foo"} {
+		require.NoError(t, os.WriteFile(filepath.Join(bin, ExtensionPrefix+name), []byte(script), 0o755))
+	}
+
+	p, found := LookupExtension("hello")
+	assert.True(t, found)
+	assert.Equal(t, filepath.Join(bin, "gopass-hello"), p)
+	for _, name := range []string{"missing", "../hello", "-hello", ""} {
+		_, found := LookupExtension(name)
+		assert.False(t, found, name)
+	}
+
+	t.Run("run", func(t *testing.T) {
+		defer buf.Reset()
+		found, err := act.RunExtension(gptest.CliCtx(ctx, t, "hello", "0", "world"))
+		assert.True(t, found)
+		require.NoError(t, err)
+		assert.Equal(t, "hello "+act.Store.Path()+" 0 world\n", buf.String())
+	})
+
+	t.Run("exit code", func(t *testing.T) {
+		defer buf.Reset()
+		found, err := act.RunExtension(gptest.CliCtx(ctx, t, "hello", "3"))
+		assert.True(t, found)
+		var ec cli.ExitCoder
+		require.ErrorAs(t, err, &ec)
+		assert.Equal(t, 3, ec.ExitCode())
+	})
+
+	t.Run("secrets take precedence", func(t *testing.T) {
+		defer buf.Reset()
+		found, err := act.RunExtension(gptest.CliCtx(ctx, t, "foo"))
+		assert.False(t, found)
+		assert.NoError(t, err)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("audit log", func(t *testing.T) {
+		defer buf.Reset()
+		fn := filepath.Join(t.TempDir(), "audit.log")
+		t.Setenv("GOPASS_AUDIT_LOG", fn)
+		act.cfg.AuditLog = true
+		defer func() {
+			act.cfg.AuditLog = false
+		}()
+
+		found, err := act.RunExtension(gptest.CliCtx(ctx, t, "hello", "0"))
+		assert.True(t, found)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(fn)
+		require.NoError(t, err)
+		entries, err := auditlog.Read(bytes.NewReader(content))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "hello", entries[0].Command)
+	})
+
+	t.Run("no extension", func(t *testing.T) {
+		found, err := act.RunExtension(gptest.CliCtx(ctx, t, "missing"))
+		assert.False(t, found)
+		assert.NoError(t, err)
+	})
+}
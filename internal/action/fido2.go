@@ -0,0 +1,42 @@
+package action
+
+import (
+	"os"
+
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// FIDO2Enroll protects the secrets of a store with a FIDO2 authenticator.
+func (s *Action) FIDO2Enroll(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	ctx = ctxutil.WithForce(ctx, c.Bool("force"))
+	mp := sealMount(c.Args().First())
+
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil {
+		return ExitError(ExitMount, err, "Store %s not found: %s", watchName(mp), err)
+	}
+
+	name := c.String("name")
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+
+	a := fido2.GetAuthenticator(ctx)
+	if dev := c.String("device"); dev != "" {
+		a = &fido2.CLI{Device: dev}
+	}
+
+	if sub.HasFIDO2(ctx) {
+		out.Noticef(ctx, "Unlock %s with an authenticator that is already enrolled first.", watchName(mp))
+	}
+	if err := sub.EnrollFIDO2(ctx, a, name); err != nil {
+		return ExitError(ExitUnknown, err, "Failed to enroll FIDO2 authenticator for %s: %s", watchName(mp), err)
+	}
+
+	out.OKf(ctx, "Enrolled FIDO2 authenticator %s for %s. Reading its secrets requires a touch now.", name, watchName(mp))
+	return nil
+}
@@ -0,0 +1,69 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fido2Mocker struct{}
+
+func (fido2Mocker) MakeCredential(ctx context.Context, user string) ([]byte, error) {
+	return []byte(user), nil
+}
+
+func (fido2Mocker) HMACSecret(ctx context.Context, credential, salt []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, credential)
+	_, _ = mac.Write(salt)
+	return mac.Sum(nil), nil
+}
+
+func TestFIDO2Enroll(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = fido2.WithAuthenticator(ctx, fido2Mocker{})
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	t.Run("unknown store", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.FIDO2Enroll(gptest.CliCtx(ctx, t, "nosuchmount")))
+	})
+
+	t.Run("enroll", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.FIDO2Enroll(gptest.CliCtxWithFlags(ctx, t, map[string]string{"name": "yubikey"})))
+		assert.Contains(t, buf.String(), "Enrolled FIDO2 authenticator yubikey for <root>")
+
+		sec, err := act.Store.Get(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", sec.Password())
+	})
+
+	t.Run("backup", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.FIDO2Enroll(gptest.CliCtxWithFlags(ctx, t, map[string]string{"name": "backup"}, "root")))
+		assert.Contains(t, buf.String(), "already enrolled")
+	})
+}
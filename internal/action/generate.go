@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/clipboard"
@@ -77,6 +78,7 @@ func (s *Action) Generate(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	s.postHook(ctx, config.HookPostGenerate, name)
 
 	// if requested launch editor to add more data to the generated secret.
 	if edit && termio.AskForConfirmation(ctx, fmt.Sprintf("Do you want to add more data for %s?", name)) {
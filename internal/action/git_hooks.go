@@ -0,0 +1,71 @@
+package action
+
+import (
+	"os"
+
+	"github.com/gopasspw/gopass/internal/githooks"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// GitInstallHooks installs git hooks that verify commit signatures and the
+// recipients of changed secrets before they are committed or pushed.
+func (s *Action) GitInstallHooks(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	store := c.String("store")
+
+	if name := s.Store.Storage(ctx, store).Name(); name != "git" {
+		return ExitError(ExitUnsupported, nil, "Store %q uses %s. Git hooks require the gitfs storage backend", store, name)
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		binary = s.Name
+	}
+
+	written, err := githooks.Install(ctx, s.Store.Storage(ctx, store).Path(), binary, store, c.Bool("force"))
+	for _, fn := range written {
+		out.OKf(ctx, "Installed %s", fn)
+	}
+	if err != nil {
+		return ExitError(ExitGit, err, "failed to install hooks: %s", err)
+	}
+
+	return nil
+}
+
+// GitHook is invoked by the hooks installed with GitInstallHooks.
+func (s *Action) GitHook(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	store := c.String("store")
+	dir := s.Store.Storage(ctx, store).Path()
+	crypto := s.Store.Crypto(ctx, store)
+
+	var unsigned []string
+	var violations []githooks.Violation
+	var err error
+	switch hook := c.Args().First(); hook {
+	case githooks.PreCommit:
+		violations, err = githooks.CheckStaged(ctx, dir, crypto)
+	case githooks.PrePush:
+		unsigned, violations, err = githooks.CheckPush(ctx, dir, crypto, stdin)
+	default:
+		return ExitError(ExitUsage, nil, "Usage: %s git hook [%s|%s]", s.Name, githooks.PreCommit, githooks.PrePush)
+	}
+	if err != nil {
+		return ExitError(ExitGit, err, "failed to run hook: %s", err)
+	}
+
+	for _, c := range unsigned {
+		out.Errorf(ctx, "Commit %s has no valid signature", c)
+	}
+	for _, v := range violations {
+		out.Errorf(ctx, "Secret %s is not encrypted for the approved recipients", v)
+	}
+	if len(unsigned) > 0 || len(violations) > 0 {
+		return ExitError(ExitAborted, nil, "Rejected by policy. Run 'gopass fsck --decrypt' to re-encrypt the secrets and sign your commits")
+	}
+
+	return nil
+}
@@ -38,6 +38,8 @@ func (s *Action) Grep(c *cli.Context) error {
 		matchFn = re.MatchString
 	}
 
+	s.Store.TryUnlock(ctx)
+
 	var matches int
 	var errors int
 	for _, v := range haystack {
@@ -0,0 +1,74 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	shellquote "github.com/kballard/go-shellquote"
+)
+
+// preHook runs the hook configured for event before a secret is changed.
+// A failing hook aborts the operation.
+func (s *Action) preHook(ctx context.Context, event, name string, sec gopass.Secret) error {
+	if err := s.runHook(ctx, event, name, sec); err != nil {
+		return ExitError(ExitAborted, err, "%s hook rejected %s: %s", event, name, err)
+	}
+	return nil
+}
+
+// postHook runs the hook configured for event after the store was changed.
+// Failures are only reported since the change can't be undone anymore.
+func (s *Action) postHook(ctx context.Context, event, name string) {
+	if err := s.runHook(ctx, event, name, nil); err != nil {
+		out.Warningf(ctx, "%s hook failed: %s", event, err)
+	}
+}
+
+// runHook executes the hook command for event, if any. The hook receives the
+// secret name and the names of its keys in the environment. The content is
+// only passed on stdin if the hook explicitly allows plaintext. If sec is nil
+// the secret is read from the store.
+func (s *Action) runHook(ctx context.Context, event, name string, sec gopass.Secret) error {
+	hook, found := s.cfg.Hook(event)
+	if !found {
+		return nil
+	}
+
+	if sec == nil && name != "" && s.Store.Exists(ctx, name) {
+		var err error
+		sec, err = s.Store.Get(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", name, err)
+		}
+	}
+
+	args, err := shellquote.Split(hook.Command)
+	if err != nil || len(args) < 1 {
+		return fmt.Errorf("invalid command %q", hook.Command)
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = out.Stderr
+	cmd.Stderr = out.Stderr
+	cmd.Env = append(os.Environ(),
+		"GOPASS_HOOK="+event,
+		"GOPASS_SECRET="+name,
+	)
+	if sec != nil {
+		cmd.Env = append(cmd.Env, "GOPASS_KEYS="+strings.Join(sec.Keys(), ","))
+		if hook.Plaintext {
+			cmd.Stdin = bytes.NewReader(sec.Bytes())
+		}
+	}
+
+	debug.Log("running %s hook %q for %q", event, hook.Command, name)
+
+	return cmd.Run()
+}
@@ -0,0 +1,92 @@
+//go:build !windows
+// +build !windows
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	script := filepath.Join(dir, "hook.sh")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+echo "$GOPASS_HOOK $GOPASS_SECRET $GOPASS_KEYS" >> `+log+`
+if [ -n "$HOOK_STDIN" ]; then cat >> `+log+`; fi
+case "$GOPASS_SECRET" in
+  forbidden/*) exit 1 ;;
+esac
+`), 0o700))
+
+	act.cfg.Hooks = map[string]config.HookConfig{
+		config.HookPreInsert: {Command: script},
+		config.HookPreDelete: {Command: script},
+	}
+
+	t.Run("pre-insert passes name and keys", func(t *testing.T) {
+		defer buf.Reset()
+		c := gptest.CliCtx(ctx, t, "team/db", "user=admin")
+		require.NoError(t, act.Insert(c))
+
+		logged, err := os.ReadFile(log)
+		require.NoError(t, err)
+		assert.Contains(t, string(logged), "pre-insert team/db user")
+		assert.NotContains(t, string(logged), "admin")
+		require.NoError(t, os.Remove(log))
+	})
+
+	t.Run("pre-insert rejects", func(t *testing.T) {
+		defer buf.Reset()
+		c := gptest.CliCtx(ctx, t, "forbidden/db", "user=admin")
+		assert.Error(t, act.Insert(c))
+		assert.False(t, act.Store.Exists(ctx, "forbidden/db"))
+		require.NoError(t, os.Remove(log))
+	})
+
+	t.Run("plaintext on stdin", func(t *testing.T) {
+		defer buf.Reset()
+		t.Setenv("HOOK_STDIN", "1")
+		act.cfg.Hooks[config.HookPreDelete] = config.HookConfig{Command: script, Plaintext: true}
+
+		require.NoError(t, act.Store.Set(ctx, "team/web", secrets.NewKVWithData("s3cret", map[string][]string{"url": {"https://example.com"}}, "", false)))
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, "team/web")
+		require.NoError(t, act.Delete(c))
+		assert.False(t, act.Store.Exists(ctx, "team/web"))
+
+		logged, err := os.ReadFile(log)
+		require.NoError(t, err)
+		assert.Contains(t, string(logged), "pre-delete team/web url")
+		assert.Contains(t, string(logged), "s3cret")
+	})
+}
@@ -5,14 +5,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/gopasspw/gopass/internal/audit"
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/editor"
+	"github.com/gopasspw/gopass/internal/expiry"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/gopass"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets/secparse"
 	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/urfave/cli/v2"
 )
@@ -25,7 +29,27 @@ func (s *Action) Insert(c *cli.Context) error {
 	force := c.Bool("force")
 	appending := c.Bool("append")
 
-	args, kvps := parseArgs(c)
+	kvps := make(map[string]string, 1)
+	if c.IsSet("expires") {
+		t, err := expiry.Parse(c.String("expires"), time.Now())
+		if err != nil {
+			return ExitError(ExitUsage, err, "%s", err)
+		}
+		kvps[expiry.Key] = expiry.Format(t)
+	}
+
+	// gopass insert name:key [value] only updates a single field.
+	if name, key, found := s.splitNameKey(ctx, c.Args().First()); found {
+		if c.Args().Len() > 2 {
+			return ExitError(ExitUsage, nil, "Usage: %s insert name:key [value]", s.Name)
+		}
+		if c.Args().Len() == 2 {
+			return s.insertYAML(ctx, name, key, []byte(c.Args().Get(1)), kvps)
+		}
+		return s.insert(ctx, c, name, key, echo, multiline, force, appending, kvps)
+	}
+
+	args, akvps := parseArgs(c)
 	name := args.Get(0)
 	key := args.Get(1)
 
@@ -33,6 +57,12 @@ func (s *Action) Insert(c *cli.Context) error {
 		return ExitError(ExitNoName, nil, "Usage: %s insert name", s.Name)
 	}
 
+	for k, v := range akvps {
+		if _, found := kvps[k]; !found {
+			kvps[k] = v
+		}
+	}
+
 	return s.insert(ctx, c, name, key, echo, multiline, force, appending, kvps)
 }
 
@@ -59,7 +89,18 @@ func (s *Action) insert(ctx context.Context, c *cli.Context, name, key string, e
 		if !force && !appending && s.Store.Exists(ctx, name) {
 			return ExitError(ExitAborted, nil, "not overwriting your current secret")
 		}
-		return s.insertStdin(ctx, name, content, appending)
+		return s.insertStdin(ctx, name, content, appending, kvps)
+	}
+
+	// if echo mode is requested use a simple string input function.
+	if echo {
+		ctx = termio.WithPassPromptFunc(ctx, func(ctx context.Context, prompt string) (string, error) {
+			return termio.AskForString(ctx, prompt, "")
+		})
+	}
+
+	if appending {
+		return s.insertAppend(ctx, name, kvps)
 	}
 
 	// don't check if it's force anyway.
@@ -72,13 +113,6 @@ func (s *Action) insert(ctx context.Context, c *cli.Context, name, key string, e
 		return s.insertMultiline(ctx, c, name)
 	}
 
-	// if echo mode is requested use a simple string input function.
-	if echo {
-		ctx = termio.WithPassPromptFunc(ctx, func(ctx context.Context, prompt string) (string, error) {
-			return termio.AskForString(ctx, prompt, "")
-		})
-	}
-
 	pw, err := termio.AskForPassword(ctx, fmt.Sprintf("password for %s", name), true)
 	if err != nil {
 		return ExitError(ExitIO, err, "failed to ask for password: %s", err)
@@ -87,7 +121,25 @@ func (s *Action) insert(ctx context.Context, c *cli.Context, name, key string, e
 	return s.insertSingle(ctx, name, pw, kvps)
 }
 
-func (s *Action) insertStdin(ctx context.Context, name string, content []byte, appendTo bool) error {
+// insertAppend asks for a line and appends it to the body of an existing
+// secret, e.g. to add recovery codes without starting an editor.
+func (s *Action) insertAppend(ctx context.Context, name string, kvps map[string]string) error {
+	if !s.Store.Exists(ctx, name) {
+		return ExitError(ExitNotFound, nil, "Secret %q not found. Can only append to existing secrets.", name)
+	}
+
+	line, err := termio.AskForPassword(ctx, fmt.Sprintf("text to append to %s", name), false)
+	if err != nil {
+		return ExitError(ExitIO, err, "failed to ask for input: %s", err)
+	}
+	if line == "" {
+		return ExitError(ExitAborted, nil, "nothing to append")
+	}
+
+	return s.insertStdin(ctx, name, []byte(line+"\n"), true, kvps)
+}
+
+func (s *Action) insertStdin(ctx context.Context, name string, content []byte, appendTo bool, kvps map[string]string) error {
 	var sec gopass.Secret
 	if appendTo && s.Store.Exists(ctx, name) {
 		var err error
@@ -95,6 +147,14 @@ func (s *Action) insertStdin(ctx context.Context, name string, content []byte, a
 		if err != nil {
 			return err
 		}
+	} else if len(kvps) > 0 {
+		// metadata can only be added to a parsed secret
+		var err error
+		sec, err = secparse.Parse(content)
+		if err != nil {
+			return ExitError(ExitAborted, err, "failed to parse secret from stdin: %s", err)
+		}
+		debug.Log("Created new parsed secret with input")
 	} else {
 		plain := &secrets.Plain{}
 		if n, err := plain.Write(content); err != nil || n < 0 {
@@ -104,8 +164,16 @@ func (s *Action) insertStdin(ctx context.Context, name string, content []byte, a
 		sec = plain
 		debug.Log("Created new plain secret with input")
 	}
+	setMetadata(sec, kvps)
 
-	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Read secret from STDIN"), name, sec); err != nil {
+	if err := s.preHook(ctx, config.HookPreInsert, name, sec); err != nil {
+		return err
+	}
+	msg := "Read secret from STDIN"
+	if appendTo {
+		msg = "Appended to secret"
+	}
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, msg), name, sec); err != nil {
 		return ExitError(ExitEncrypt, err, "failed to set %q: %s", name, err)
 	}
 	return nil
@@ -121,6 +189,10 @@ func (s *Action) insertStdinAppend(ctx context.Context, name string, content []b
 	if !ok {
 		return nil, fmt.Errorf("%T is not an io.Writer", eSec)
 	}
+	// make sure the new content starts on a line of its own.
+	if b := eSec.Bytes(); len(b) > 0 && !bytes.HasSuffix(b, []byte("\n")) {
+		content = append([]byte("\n"), content...)
+	}
 	if _, err := secW.Write(content); err != nil {
 		return nil, ExitError(ExitEncrypt, err, "failed to write %q: %q", content, err)
 	}
@@ -143,6 +215,9 @@ func (s *Action) insertSingle(ctx context.Context, name, pw string, kvps map[str
 		audit.Single(ctx, pw)
 	}
 
+	if err := s.preHook(ctx, config.HookPreInsert, name, sec); err != nil {
+		return err
+	}
 	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Inserted user supplied password"), name, sec); err != nil {
 		return ExitError(ExitEncrypt, err, "failed to write secret %q: %s", name, err)
 	}
@@ -175,7 +250,7 @@ func (s *Action) insertGetSecret(ctx context.Context, name, pw string) (gopass.S
 }
 
 func (s *Action) insertYAML(ctx context.Context, name, key string, content []byte, kvps map[string]string) error {
-	if ctxutil.IsInteractive(ctx) {
+	if len(content) == 0 && ctxutil.IsInteractive(ctx) {
 		pw, err := termio.AskForString(ctx, name+":"+key, "")
 		if err != nil {
 			return ExitError(ExitIO, err, "failed to ask for user input: %s", err)
@@ -197,7 +272,10 @@ func (s *Action) insertYAML(ctx context.Context, name, key string, content []byt
 	if err := sec.Set(key, string(content)); err != nil {
 		return ExitError(ExitUsage, err, "failed set key %q of %q: %q", key, name, err)
 	}
-	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Inserted YAML value from STDIN"), name, sec); err != nil {
+	if err := s.preHook(ctx, config.HookPreInsert, name, sec); err != nil {
+		return err
+	}
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Set key %s", key)), name, sec); err != nil {
 		return ExitError(ExitEncrypt, err, "failed to set key %q of %q: %s", key, name, err)
 	}
 	return nil
@@ -223,6 +301,9 @@ func (s *Action) insertMultiline(ctx context.Context, c *cli.Context, name strin
 	if err != nil || n < 0 {
 		out.Errorf(ctx, "WARNING: Invalid secret: %s of len %d", err, n)
 	}
+	if err := s.preHook(ctx, config.HookPreInsert, name, sec); err != nil {
+		return err
+	}
 	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Inserted user supplied password with %s", ed)), name, sec); err != nil {
 		return ExitError(ExitEncrypt, err, "failed to store secret %q: %s", name, err)
 	}
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/fatih/color"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/gopasspw/gopass/tests/gptest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,7 +50,7 @@ func TestInsert(t *testing.T) {
 	})
 
 	t.Run("insert baz via stdin w/o newline", func(t *testing.T) {
-		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar"), false))
+		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar"), false, nil))
 		buf.Reset()
 
 		assert.NoError(t, act.show(ctx, gptest.CliCtx(ctx, t), "baz", false))
@@ -56,7 +59,7 @@ func TestInsert(t *testing.T) {
 	})
 
 	t.Run("insert baz via stdin w/ newline", func(t *testing.T) {
-		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n"), false))
+		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n"), false, nil))
 		buf.Reset()
 
 		assert.NoError(t, act.show(ctx, gptest.CliCtx(ctx, t), "baz", false))
@@ -64,8 +67,25 @@ func TestInsert(t *testing.T) {
 		buf.Reset()
 	})
 
+	t.Run("insert baz via stdin w/ expiry", func(t *testing.T) {
+		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\nbody text"), false, map[string]string{"expires": "2022-12-31"}))
+		buf.Reset()
+
+		sec, err := act.Store.Get(ctx, "baz")
+		require.NoError(t, err)
+		assert.Equal(t, "foobar", sec.Password())
+		v, found := sec.Get("expires")
+		assert.True(t, found)
+		assert.Equal(t, "2022-12-31", v)
+	})
+
+	t.Run("insert with invalid expiry", func(t *testing.T) {
+		assert.Error(t, act.Insert(gptest.CliCtxWithFlags(ctx, t, map[string]string{"expires": "someday"}, "bar")))
+		buf.Reset()
+	})
+
 	t.Run("insert baz via stdin w/ yaml", func(t *testing.T) {
-		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n---\nuser: name\nother: meh"), false))
+		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n---\nuser: name\nother: meh"), false, nil))
 		buf.Reset()
 
 		assert.NoError(t, act.show(ctx, gptest.CliCtx(ctx, t), "baz", false))
@@ -74,7 +94,7 @@ func TestInsert(t *testing.T) {
 	})
 
 	t.Run("insert baz via stdin w/ k-v", func(t *testing.T) {
-		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\ninvalid key-value\nOther: meh\nUser: name\nbody text"), false))
+		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\ninvalid key-value\nOther: meh\nUser: name\nbody text"), false, nil))
 		buf.Reset()
 
 		assert.NoError(t, act.show(ctx, gptest.CliCtx(ctx, t), "baz", false))
@@ -107,8 +127,30 @@ func TestInsert(t *testing.T) {
 		buf.Reset()
 	})
 
+	t.Run("insert name:key value", func(t *testing.T) {
+		assert.NoError(t, act.Insert(gptest.CliCtx(ctx, t, "keyvaltest:user", "john")))
+		sec, err := act.Store.Get(ctx, "keyvaltest")
+		require.NoError(t, err)
+		v, found := sec.Get("user")
+		assert.True(t, found)
+		assert.Equal(t, "john", v)
+		v, found = sec.Get("baz")
+		assert.True(t, found)
+		assert.Equal(t, "val", v)
+		buf.Reset()
+
+		assert.Error(t, act.Insert(gptest.CliCtx(ctx, t, "keyvaltest:user", "john", "doe")))
+	})
+
+	t.Run("insert new secret with a colon", func(t *testing.T) {
+		assert.NoError(t, act.Insert(gptest.CliCtx(ctx, t, "example.com:8080")))
+		assert.True(t, act.Store.Exists(ctx, "example.com:8080"))
+		assert.False(t, act.Store.Exists(ctx, "example.com"))
+		buf.Reset()
+	})
+
 	t.Run("insert baz via stdin w/ yaml and input parsing and safecontent", func(t *testing.T) {
-		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n---\nuser: name\nother: 0123"), false))
+		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n---\nuser: name\nother: 0123"), false, nil))
 		buf.Reset()
 
 		assert.NoError(t, act.show(ctx, gptest.CliCtx(ctx, t), "baz", false))
@@ -119,7 +161,7 @@ func TestInsert(t *testing.T) {
 	t.Run("insert baz via stdin w/ yaml and no input parsing", func(t *testing.T) {
 		ctx = ctxutil.WithShowParsing(ctx, false)
 		ctx = ctxutil.WithShowSafeContent(ctx, false)
-		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n---\nuser: name\nother: 0123"), false))
+		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\n---\nuser: name\nother: 0123"), false, nil))
 		buf.Reset()
 
 		assert.NoError(t, act.show(ctx, gptest.CliCtx(ctx, t), "baz", false))
@@ -172,6 +214,10 @@ func TestInsertStdin(t *testing.T) {
 	ibuf.Reset()
 	buf.Reset()
 
+	sec, err := act.Store.Get(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "foobar\nfoobar", string(sec.Bytes()))
+
 	// echo
 	ibuf.WriteString("foobar")
 	assert.NoError(t, act.insert(ctx, gptest.CliCtx(ctx, t), "bar", "", true, false, false, false, nil))
@@ -184,3 +230,49 @@ func TestInsertStdin(t *testing.T) {
 	ibuf.Reset()
 	buf.Reset()
 }
+
+func TestInsertAppend(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+		termio.Stdin = os.Stdin
+	}()
+
+	sec := secrets.New()
+	sec.SetPassword("secret")
+	require.NoError(t, act.Store.Set(ctx, "web", sec))
+
+	t.Run("append to missing secret", func(t *testing.T) {
+		termio.Stdin = strings.NewReader("code1\n")
+		assert.Error(t, act.insert(ctx, gptest.CliCtx(ctx, t), "nope", "", true, false, false, true, nil))
+	})
+
+	t.Run("append nothing", func(t *testing.T) {
+		termio.Stdin = strings.NewReader("\n")
+		assert.Error(t, act.insert(ctx, gptest.CliCtx(ctx, t), "web", "", true, false, false, true, nil))
+	})
+
+	t.Run("append two lines", func(t *testing.T) {
+		termio.Stdin = strings.NewReader("code1\n")
+		assert.NoError(t, act.insert(ctx, gptest.CliCtx(ctx, t), "web", "", true, false, false, true, nil))
+		termio.Stdin = strings.NewReader("code2\n")
+		assert.NoError(t, act.insert(ctx, gptest.CliCtx(ctx, t), "web", "", true, false, false, true, nil))
+
+		sec, err := act.Store.Get(ctx, "web")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", sec.Password())
+		assert.Equal(t, "code1\ncode2\n", sec.Body())
+	})
+}
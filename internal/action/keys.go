@@ -0,0 +1,123 @@
+package action
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+type keyInspector interface {
+	KeyInfo(ctx context.Context, id string) (gpg.Key, bool, error)
+}
+
+// KeysShow prints the details of a public key and the folders of all stores
+// it can decrypt, so recipients can be vetted without using gpg directly.
+func (s *Action) KeysShow(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	id := c.Args().First()
+	if id == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s keys show [--store STORE] <KEY>", s.Name)
+	}
+
+	store := c.String("store")
+	crypto := s.Store.Crypto(ctx, store)
+	if crypto == nil {
+		return ExitError(ExitNotFound, nil, "store %q not found", store)
+	}
+	ki, ok := crypto.(keyInspector)
+	if !ok {
+		return ExitError(ExitUnsupported, nil, "%s does not support inspecting keys", crypto.Name())
+	}
+
+	k, secret, err := ki.KeyInfo(ctx, id)
+	if err != nil {
+		return ExitError(ExitNotFound, err, "failed to look up %s: %s", id, err)
+	}
+
+	out.Printf(ctx, "Key:         %s %s%d", k.ID(), k.Algorithm, k.KeyLength)
+	out.Printf(ctx, "Fingerprint: %s", formatFingerprint(k.Fingerprint))
+	out.Printf(ctx, "Created:     %s", k.CreationDate.Format("2006-01-02"))
+	out.Printf(ctx, "Expires:     %s", formatExpiry(k.ExpirationDate))
+	out.Printf(ctx, "Validity:    %s", gpg.TrustName(k.Validity))
+	out.Printf(ctx, "Owner trust: %s", gpg.TrustName(k.Ownertrust))
+	out.Printf(ctx, "Usage:       %s", k.Usage)
+	if secret {
+		out.Printf(ctx, "Secret key:  available")
+	} else {
+		out.Printf(ctx, "Secret key:  not available")
+	}
+
+	out.Printf(ctx, "Identities:")
+	for _, uid := range k.Identities() {
+		out.Printf(ctx, "  - %s", uid.ID())
+	}
+
+	if sks := k.SortedSubKeys(); len(sks) > 0 {
+		out.Printf(ctx, "Subkeys:")
+		for _, sk := range sks {
+			// expired subkeys are already marked by formatExpiry.
+			state := ""
+			if sk.Validity != "e" && !sk.IsValid() && (sk.ExpirationDate.IsZero() || sk.ExpirationDate.After(time.Now())) {
+				state = " [" + gpg.TrustName(sk.Validity) + "]"
+			}
+			out.Printf(ctx, "  - %s %s%d %s, created %s, expires %s%s", sk.ID(), sk.Algorithm, sk.KeyLength, sk.Usage, sk.CreationDate.Format("2006-01-02"), formatExpiry(sk.ExpirationDate), state)
+		}
+	}
+
+	folders := s.keyFolders(ctx, k)
+	if len(folders) < 1 {
+		out.Printf(ctx, "Can decrypt: nothing, the key is no recipient of any store")
+		return nil
+	}
+	out.Printf(ctx, "Can decrypt:")
+	for _, f := range folders {
+		out.Printf(ctx, "  - %s", f)
+	}
+
+	return nil
+}
+
+// keyFolders returns the folders of all stores whose recipients include the
+// key.
+func (s *Action) keyFolders(ctx context.Context, k gpg.Key) []string {
+	var folders []string
+	for _, mp := range append([]string{""}, s.Store.MountPoints()...) {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil || sub == nil {
+			continue
+		}
+		for dir, rs := range sub.RecipientsTree(ctx) {
+			for _, r := range rs {
+				if !k.Matches(r) {
+					continue
+				}
+				name := "<root>"
+				if fn := path.Join(mp, filepath.ToSlash(dir)); fn != "" {
+					name = fn + "/"
+				}
+				folders = append(folders, name)
+				break
+			}
+		}
+	}
+	sort.Strings(folders)
+	return folders
+}
+
+func formatExpiry(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return "never"
+	case t.Before(time.Now()):
+		return t.Format("2006-01-02") + " (expired)"
+	default:
+		return t.Format("2006-01-02")
+	}
+}
@@ -0,0 +1,51 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysShow(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	assert.Error(t, act.KeysShow(gptest.CliCtx(ctx, t)))
+	// the plain backend of the mock store has no key details
+	assert.Error(t, act.KeysShow(gptest.CliCtx(ctx, t, "0xDEADBEEF")))
+
+	// the recipients of the mock store are plain ids
+	k := gpg.Key{Fingerprint: u.Recipients[0]}
+	assert.Equal(t, []string{"<root>"}, act.keyFolders(ctx, k))
+	assert.Empty(t, act.keyFolders(ctx, gpg.Key{Fingerprint: "0123456789ABCDEF"}))
+}
+
+func TestFormatExpiry(t *testing.T) {
+	assert.Equal(t, "never", formatExpiry(time.Time{}))
+	assert.Equal(t, "2001-02-03 (expired)", formatExpiry(time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "2201-02-03", formatExpiry(time.Date(2201, 2, 3, 0, 0, 0, 0, time.UTC)))
+}
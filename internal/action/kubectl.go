@@ -0,0 +1,103 @@
+package action
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/kube"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// KubectlApply renders a secret or all secrets below a folder as Kubernetes
+// Secrets and applies them using kubectl or prints them.
+func (s *Action) KubectlApply(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	prefix := strings.TrimSuffix(c.Args().First(), "/")
+	if prefix == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s kubectl apply <PREFIX> [--namespace NS]", s.Name)
+	}
+
+	if !s.Store.Exists(ctx, prefix) && !s.Store.IsDir(ctx, prefix) {
+		return ExitError(ExitNotFound, nil, "Secret %s not found", prefix)
+	}
+
+	names := []string{prefix}
+	if s.Store.IsDir(ctx, prefix) {
+		l, err := s.Store.Tree(ctx)
+		if err != nil {
+			return ExitError(ExitList, err, "failed to list store: %s", err)
+		}
+
+		subtree, err := l.FindFolder(prefix)
+		if err != nil {
+			return ExitError(ExitNotFound, nil, "Entry %q not found", prefix)
+		}
+		names = subtree.List(tree.INF)
+	}
+
+	ns := c.String("namespace")
+	secs := make([]*kube.Secret, 0, len(names))
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		// the Kubernetes name is relative to the prefix
+		kname := path.Base(name)
+		if name != prefix {
+			kname = strings.TrimPrefix(name, prefix+"/")
+		}
+
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+
+		ks, err := kube.NewSecret(kname, ns, sec)
+		if err != nil {
+			return ExitError(ExitUnsupported, err, "failed to convert %s: %s", name, err)
+		}
+		if other, found := seen[ks.Metadata.Name]; found {
+			return ExitError(ExitUnsupported, nil, "%s and %s both map to the Kubernetes Secret %s", other, name, ks.Metadata.Name)
+		}
+		seen[ks.Metadata.Name] = name
+		debug.Log("rendered %s as %s (%s)", name, ks.Metadata.Name, ks.Type)
+
+		secs = append(secs, ks)
+	}
+
+	buf, err := kube.Render(secs)
+	if err != nil {
+		return ExitError(ExitUnknown, err, "failed to render manifests: %s", err)
+	}
+
+	if c.Bool("print") {
+		_, err := stdout.Write(buf)
+		return err
+	}
+
+	args := []string{"apply", "-f", "-"}
+	if ns != "" {
+		args = append(args, "--namespace", ns)
+	}
+	if c.Bool("dry-run") {
+		args = append(args, "--dry-run=server")
+	}
+
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Stdin = bytes.NewReader(buf)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+
+	debug.Log("running kubectl %+v", args)
+	if err := cmd.Run(); err != nil {
+		return ExitError(ExitUnknown, err, "kubectl failed: %s", err)
+	}
+
+	out.OKf(ctx, "Applied %d secrets", len(secs))
+	return nil
+}
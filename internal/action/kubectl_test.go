@@ -0,0 +1,66 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubectlApply(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	require.NoError(t, act.Store.Set(ctx, "k8s/db/prod", secrets.NewKVWithData("s3cret", map[string][]string{"user": {"admin"}}, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "k8s/api", secrets.NewKVWithData("token", nil, "", false)))
+
+	t.Run("w/o args", func(t *testing.T) {
+		assert.Error(t, act.KubectlApply(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		assert.Error(t, act.KubectlApply(gptest.CliCtxWithFlags(ctx, t, map[string]string{"print": "true"}, "k8s/nope")))
+	})
+
+	t.Run("print folder", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.KubectlApply(gptest.CliCtxWithFlags(ctx, t, map[string]string{"print": "true", "namespace": "apps"}, "k8s/")))
+		assert.Contains(t, buf.String(), "name: api\n")
+		assert.Contains(t, buf.String(), "name: db-prod\n")
+		assert.Contains(t, buf.String(), "namespace: apps\n")
+		assert.Contains(t, buf.String(), "user: YWRtaW4=\n")
+		assert.Contains(t, buf.String(), "\n---\n")
+	})
+
+	t.Run("print secret", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.KubectlApply(gptest.CliCtxWithFlags(ctx, t, map[string]string{"print": "true"}, "k8s/db/prod")))
+		assert.Contains(t, buf.String(), "name: prod\n")
+		assert.NotContains(t, buf.String(), "namespace:")
+	})
+}
@@ -0,0 +1,133 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/githooks"
+	"github.com/gopasspw/gopass/internal/layout"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// MountsInit initializes a new store, mounts it and seeds it from a layout.
+func (s *Action) MountsInit(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	alias := c.Args().First()
+	if alias == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s mounts init [--layout <name>] <alias> [gpg-id ...]", s.Name)
+	}
+	if _, found := s.Store.Mounts()[alias]; found {
+		return ExitError(ExitMount, nil, "%s is already mounted", alias)
+	}
+
+	name := c.String("layout")
+	l := &layout.Layout{}
+	if name != "" {
+		var err error
+		l, err = layout.Load(name)
+		if err != nil {
+			return ExitError(ExitConfig, err, "%s", err)
+		}
+	}
+
+	keys := c.Args().Tail()
+	for _, r := range l.Recipients {
+		if !contains(keys, r) {
+			keys = append(keys, r)
+		}
+	}
+
+	ctx = initParseContext(ctx, c)
+	if err := s.init(ctx, alias, c.String("path"), keys...); err != nil {
+		return ExitError(ExitUnknown, err, "Failed to initialize store: %s", err)
+	}
+
+	if name == "" {
+		return nil
+	}
+	if err := s.applyLayout(ctx, alias, name, l); err != nil {
+		return ExitError(ExitUnknown, err, "Failed to apply layout %s: %s", name, err)
+	}
+
+	out.OKf(ctx, "Applied layout %s to %s", name, alias)
+	return nil
+}
+
+// applyLayout writes the folders, templates, break-glass folders and options
+// of the layout to the store mounted at alias. The content is committed at once.
+func (s *Action) applyLayout(ctx context.Context, alias, name string, l *layout.Layout) error {
+	opts := make([]string, 0, len(l.Options))
+	for k := range l.Options {
+		opts = append(opts, k)
+	}
+	sort.Strings(opts)
+	for _, k := range opts {
+		if err := s.cfg.SetStoreConfigValue(alias, k, l.Options[k]); err != nil {
+			return fmt.Errorf("failed to set option %s: %w", k, err)
+		}
+	}
+
+	ctx = ctxutil.WithGitCommit(ctx, false)
+	storage := s.Store.Storage(ctx, alias)
+	for _, fn := range l.Files() {
+		if err := storage.Set(ctx, fn, []byte{}); err != nil {
+			return fmt.Errorf("failed to create %s: %w", path.Dir(fn), err)
+		}
+		if err := storage.Add(ctx, fn); err != nil && !errors.Is(err, store.ErrGitNotInit) {
+			return fmt.Errorf("failed to add %s to git: %w", fn, err)
+		}
+	}
+
+	if len(l.BreakGlass) > 0 {
+		buf := []byte(strings.Join(l.BreakGlass, "\n") + "\n")
+		if err := storage.Set(ctx, leaf.BreakGlassFile, buf); err != nil {
+			return fmt.Errorf("failed to write %s: %w", leaf.BreakGlassFile, err)
+		}
+		if err := storage.Add(ctx, leaf.BreakGlassFile); err != nil && !errors.Is(err, store.ErrGitNotInit) {
+			return fmt.Errorf("failed to add %s to git: %w", leaf.BreakGlassFile, err)
+		}
+	}
+
+	folders := make([]string, 0, len(l.Templates))
+	for f := range l.Templates {
+		folders = append(folders, f)
+	}
+	sort.Strings(folders)
+	for _, f := range folders {
+		if err := s.Store.SetTemplate(ctx, path.Join(alias, f), []byte(l.Templates[f])); err != nil {
+			return fmt.Errorf("failed to write template for %s: %w", f, err)
+		}
+	}
+
+	if err := storage.Commit(ctx, fmt.Sprintf("Applied layout %s", name)); err != nil {
+		if !errors.Is(err, store.ErrGitNotInit) && !errors.Is(err, store.ErrGitNothingToCommit) {
+			return fmt.Errorf("failed to commit changes to git: %w", err)
+		}
+	}
+
+	if !l.GitHooks {
+		return nil
+	}
+	if storage.Name() != "git" {
+		out.Warningf(ctx, "Not installing git hooks. %s uses %s", alias, storage.Name())
+		return nil
+	}
+	binary, err := os.Executable()
+	if err != nil {
+		binary = s.Name
+	}
+	written, err := githooks.Install(ctx, storage.Path(), binary, alias, false)
+	for _, fn := range written {
+		out.OKf(ctx, "Installed %s", fn)
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/layout"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMountsInit(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+	ctx = backend.WithStorageBackend(ctx, backend.FS)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	fn := filepath.Join(t.TempDir(), "team.yml")
+	require.NoError(t, os.WriteFile(fn, []byte(`folders:
+  - prod
+  - staging/db
+templates:
+  prod: |
+    {{ .Content }}
+    user: admin
+breakglass:
+  - prod
+options:
+  hiddenrecipients: "true"
+githooks: true
+`), 0o600))
+
+	t.Run("missing alias", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.MountsInit(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("unknown layout", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.MountsInit(gptest.CliCtxWithFlags(ctx, t, map[string]string{"layout": "nope"}, "team")))
+	})
+
+	t.Run("layout", func(t *testing.T) {
+		defer buf.Reset()
+		path := u.StoreDir("team")
+		require.NoError(t, act.MountsInit(gptest.CliCtxWithFlags(ctx, t, map[string]string{
+			"layout":  fn,
+			"path":    path,
+			"crypto":  "plain",
+			"storage": "fs",
+		}, "team")))
+
+		assert.Contains(t, act.Store.Mounts(), "team")
+		assert.FileExists(t, filepath.Join(path, "prod", layout.KeepFile))
+		assert.FileExists(t, filepath.Join(path, "staging", "db", layout.KeepFile))
+		assert.True(t, act.Store.HasTemplate(ctx, "team/prod"))
+		assert.True(t, act.cfg.StoreConfig("team").HiddenRecipients)
+		assert.FileExists(t, filepath.Join(path, leaf.BreakGlassFile))
+		assert.Contains(t, buf.String(), "Not installing git hooks")
+	})
+
+	t.Run("already mounted", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.MountsInit(gptest.CliCtx(ctx, t, "team")))
+	})
+}
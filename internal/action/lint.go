@@ -0,0 +1,98 @@
+package action
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/lint"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// Lint checks the names and content of all secrets against the naming policy
+// of their store (see lint.PolicyFile).
+func (s *Action) Lint(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	mps := append([]string{""}, s.Store.MountPoints()...)
+	if c.IsSet("store") {
+		mps = []string{c.String("store")}
+	}
+
+	var problems int
+	for _, mp := range mps {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil || sub == nil {
+			return ExitError(ExitMount, err, "failed to get store %q: %s", mp, err)
+		}
+
+		ps, err := s.lintStore(ctx, sub, mp, !c.Bool("no-decrypt"))
+		if err != nil {
+			return err
+		}
+		for _, p := range ps {
+			p.Name = path.Join(mp, p.Name)
+			out.Warningf(ctx, "%s", p)
+		}
+		problems += len(ps)
+	}
+
+	if problems > 0 {
+		return ExitError(ExitAudit, nil, "found %d problems", problems)
+	}
+	out.OKf(ctx, "No problems found")
+
+	return nil
+}
+
+func (s *Action) lintStore(ctx context.Context, sub *leaf.Store, mp string, decrypt bool) ([]lint.Problem, error) {
+	policy := &lint.Policy{}
+	if buf, err := sub.Storage().Get(ctx, lint.PolicyFile); err == nil {
+		policy, err = lint.Parse(buf)
+		if err != nil {
+			return nil, ExitError(ExitConfig, err, "invalid lint policy in %q: %s", watchName(mp), err)
+		}
+	} else {
+		debug.Log("no lint policy in %q: %s", mp, err)
+	}
+
+	l, err := sub.List(ctx, "")
+	if err != nil {
+		return nil, ExitError(ExitList, err, "failed to list store %q: %s", watchName(mp), err)
+	}
+	names := make([]string, 0, len(l))
+	for _, name := range l {
+		if mp != "" {
+			name = strings.TrimPrefix(name, mp+"/")
+		}
+		names = append(names, name)
+	}
+
+	problems := policy.Names(names)
+	if !decrypt {
+		return problems, nil
+	}
+
+	for _, name := range names {
+		if !policy.NeedsUsername(name) {
+			continue
+		}
+		sec, err := sub.Get(ctx, name)
+		if err != nil {
+			return nil, ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", path.Join(mp, name), err)
+		}
+		if !lint.HasUsername(name, sec.Keys()) {
+			problems = append(problems, lint.Problem{
+				Name:    name,
+				Rule:    lint.RuleUsername,
+				Message: "has no username",
+			})
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,84 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/lint"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLint(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	t.Run("default policy", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Lint(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "No problems found")
+	})
+
+	require.NoError(t, act.Store.Set(ctx, "web/github.com/jane", secrets.NewKVWithData("pw", nil, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "web/GitHub", secrets.NewKVWithData("pw", map[string][]string{"login": {"jane"}}, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "web/gitlab", secrets.NewKVWithData("pw", nil, "", false)))
+
+	sub, err := act.Store.GetSubStore("")
+	require.NoError(t, err)
+	require.NoError(t, sub.Storage().Set(ctx, lint.PolicyFile, []byte("folders: [web]\nusernames: [web]\n")))
+
+	t.Run("policy", func(t *testing.T) {
+		defer buf.Reset()
+		err := act.Lint(gptest.CliCtx(ctx, t))
+		require.Error(t, err)
+		var ec cli.ExitCoder
+		require.ErrorAs(t, err, &ec)
+		assert.Equal(t, ExitAudit, ec.ExitCode())
+
+		o := buf.String()
+		assert.Contains(t, o, "foo: is outside of the approved folders web (folder)")
+		assert.Contains(t, o, "web/GitHub: contains upper case letters (case)")
+		assert.Contains(t, o, "web/github.com: looks like a duplicate of web/GitHub (duplicate)")
+		assert.Contains(t, o, "web/gitlab: has no username (username)")
+		assert.NotContains(t, o, "web/github.com/jane:")
+	})
+
+	t.Run("no decrypt", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Lint(gptest.CliCtxWithFlags(ctx, t, map[string]string{"no-decrypt": "true"})))
+		assert.NotContains(t, buf.String(), "(username)")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, sub.Storage().Set(ctx, lint.PolicyFile, []byte("folders: [../x]\n")))
+		err := act.Lint(gptest.CliCtx(ctx, t))
+		var ec cli.ExitCoder
+		require.ErrorAs(t, err, &ec)
+		assert.Equal(t, ExitConfig, ec.ExitCode())
+	})
+}
@@ -8,11 +8,15 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/expiry"
 	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
 	shellquote "github.com/kballard/go-shellquote"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/term"
@@ -26,6 +30,7 @@ func (s *Action) List(c *cli.Context) error {
 	flat := c.Bool("flat")
 	stripPrefix := c.Bool("strip-prefix")
 	folders := c.Bool("folders")
+	expired := c.Bool("expired")
 
 	// print the path if the argument is a direct hit.
 	if s.Store.Exists(ctx, filter) && !s.Store.IsDir(ctx, filter) {
@@ -49,10 +54,10 @@ func (s *Action) List(c *cli.Context) error {
 		limit = c.Int("limit")
 	}
 
-	return s.listFiltered(ctx, l, limit, flat, folders, stripPrefix, filter)
+	return s.listFiltered(ctx, l, limit, flat, folders, stripPrefix, expired, filter)
 }
 
-func (s *Action) listFiltered(ctx context.Context, l *tree.Root, limit int, flat, folders, stripPrefix bool, filter string) error {
+func (s *Action) listFiltered(ctx context.Context, l *tree.Root, limit int, flat, folders, stripPrefix, expired bool, filter string) error {
 
 	sep := string(leaf.Sep)
 
@@ -74,16 +79,25 @@ func (s *Action) listFiltered(ctx context.Context, l *tree.Root, limit int, flat
 		l.SetName(filter + sep)
 	}
 
+	exp := map[string]bool{}
+	if expired && !folders {
+		exp = s.listExpired(ctx, l, limit)
+	}
+
 	if flat {
 		listOver := l.List
 		if folders {
 			listOver = l.ListFolders
 		}
 		for _, e := range listOver(limit) {
+			suffix := ""
+			if exp[e] {
+				suffix = " (expired)"
+			}
 			if stripPrefix {
 				e = strings.TrimPrefix(e, filter+sep)
 			}
-			fmt.Fprintln(stdout, e)
+			fmt.Fprintln(stdout, e+suffix)
 		}
 		return nil
 	}
@@ -91,7 +105,9 @@ func (s *Action) listFiltered(ctx context.Context, l *tree.Root, limit int, flat
 	// we may need to redirect stdout for the pager support.
 	so, buf := redirectPager(ctx, l)
 
+	done := timing.Start(ctx, timing.Render)
 	fmt.Fprintln(so, l.Format(limit))
+	done()
 	if buf != nil {
 		if err := s.pager(ctx, buf); err != nil {
 			return ExitError(ExitUnknown, err, "failed to invoke pager: %s", err)
@@ -100,6 +116,28 @@ func (s *Action) listFiltered(ctx context.Context, l *tree.Root, limit int, flat
 	return nil
 }
 
+// listExpired decrypts all secrets in the tree, marks those that have expired
+// and returns their names.
+func (s *Action) listExpired(ctx context.Context, l *tree.Root, limit int) map[string]bool {
+	exp := map[string]bool{}
+	now := time.Now()
+	for _, name := range l.List(limit) {
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			debug.Log("failed to decrypt %s: %s", name, err)
+			continue
+		}
+		if t, found := expiry.Get(sec); !found || t.After(now) {
+			continue
+		}
+		exp[name] = true
+		if err := l.SetExpired(name); err != nil {
+			debug.Log("failed to mark %s as expired: %s", name, err)
+		}
+	}
+	return exp
+}
+
 // redirectPager returns a redirected io.Writer if the output would exceed
 // the terminal size.
 func redirectPager(ctx context.Context, subtree *tree.Root) (io.Writer, *bytes.Buffer) {
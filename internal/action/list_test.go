@@ -87,6 +87,29 @@ foo2/
 	// list not-present
 	assert.Error(t, act.List(gptest.CliCtx(ctx, t, "not-present")))
 	buf.Reset()
+
+	// list --expired
+	sec = &secrets.Plain{}
+	sec.SetPassword("123")
+	sec.WriteString("expires: 2000-01-01\n")
+	assert.NoError(t, act.Store.Set(ctx, "foo/zen/old", sec))
+	buf.Reset()
+
+	assert.NoError(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"expired": "true"}, "foo/zen")))
+	want = `foo/zen/
+├── bar
+└── old (expired)
+
+`
+	assert.Equal(t, want, buf.String())
+	buf.Reset()
+
+	assert.NoError(t, act.List(gptest.CliCtxWithFlags(ctx, t, map[string]string{"expired": "true", "flat": "true"}, "foo/zen")))
+	want = `foo/zen/bar
+foo/zen/old (expired)
+`
+	assert.Equal(t, want, buf.String())
+	buf.Reset()
 }
 
 func TestListLimit(t *testing.T) {
@@ -0,0 +1,72 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/cui"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// Migrate re-encrypts a store with a different crypto backend.
+func (s *Action) Migrate(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	store := c.Args().First()
+
+	if c.String("to") == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s migrate [--from <backend>] --to <backend> [--recipient <id> ...] [mount]", s.Name)
+	}
+	toBe, err := backend.CryptoRegistry.Backend(c.String("to"))
+	if err != nil {
+		return ExitError(ExitUsage, err, "unknown crypto backend %q. Available: %v", c.String("to"), backend.CryptoRegistry.Backends())
+	}
+	to, err := backend.NewCrypto(ctx, toBe)
+	if err != nil {
+		return ExitError(ExitUnsupported, err, "failed to initialize %s: %s", c.String("to"), err)
+	}
+
+	current := s.Store.Crypto(ctx, store)
+	if current == nil {
+		return ExitError(ExitNotInitialized, nil, "store %q has no crypto backend", store)
+	}
+	if name := c.String("from"); name != "" {
+		fromBe, err := backend.CryptoRegistry.Backend(name)
+		if err != nil {
+			return ExitError(ExitUsage, err, "unknown crypto backend %q. Available: %v", name, backend.CryptoRegistry.Backends())
+		}
+		from, err := backend.NewCrypto(ctx, fromBe)
+		if err != nil {
+			return ExitError(ExitUnsupported, err, "failed to initialize %s: %s", name, err)
+		}
+		if from.Name() != current.Name() {
+			return ExitError(ExitUsage, nil, "store %q uses %s, not %s", store, current.Name(), from.Name())
+		}
+	}
+
+	rs := c.StringSlice("recipient")
+	if len(rs) < 1 {
+		key, err := cui.AskForPrivateKey(ctx, to, fmt.Sprintf("Please select a %s key to encrypt the store for", to.Name()))
+		if err != nil {
+			return ExitError(ExitRecipients, err, "failed to select a key: %s. Use --recipient", err)
+		}
+		rs = []string{key}
+	}
+
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("Re-encrypt all secrets of %q with %s for %v?", store, to.Name(), rs)) {
+		return ExitError(ExitAborted, nil, "user aborted")
+	}
+
+	res, err := s.Store.Migrate(ctx, store, to, rs)
+	if err != nil {
+		return ExitError(ExitEncrypt, err, "Failed to migrate %q: %s. Run the command again to resume.", store, err)
+	}
+
+	if res.Resumed > 0 {
+		out.Noticef(ctx, "Resumed the migration of %d secrets", res.Resumed)
+	}
+	out.OKf(ctx, "Migrated %d secrets of %q from %s to %s", res.Migrated, store, res.From, res.To)
+	return nil
+}
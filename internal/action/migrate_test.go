@@ -0,0 +1,55 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	t.Run("no backend", func(t *testing.T) {
+		assert.Error(t, act.Migrate(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		assert.Error(t, act.Migrate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"to": "rot13"})))
+	})
+
+	t.Run("wrong source backend", func(t *testing.T) {
+		err := act.Migrate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"from": "age", "to": "plain"}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "uses plain")
+	})
+
+	t.Run("same backend", func(t *testing.T) {
+		assert.Error(t, act.Migrate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"to": "plain", "recipient": "0xDEADBEEF"})))
+		sec, err := act.Store.Get(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", sec.Password())
+	})
+}
@@ -1,6 +1,7 @@
 package action
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -43,7 +44,11 @@ func (s *Action) MountsPrint(c *cli.Context) error {
 		return nil
 	}
 
-	root := tree.New(color.GreenString(fmt.Sprintf("gopass (%s)", s.Store.Path())))
+	name := color.GreenString(fmt.Sprintf("gopass (%s)", s.Store.Path()))
+	if st := s.mountStatus(ctx, ""); st != "" {
+		name += " [" + st + "]"
+	}
+	root := tree.New(name)
 	mounts := s.Store.Mounts()
 	mps := s.Store.MountPoints()
 	sort.Sort(store.ByPathLen(mps))
@@ -53,12 +58,28 @@ func (s *Action) MountsPrint(c *cli.Context) error {
 			out.Errorf(ctx, "Failed to add mount to tree: %s", err)
 		}
 	}
+	for _, alias := range mps {
+		if err := root.SetStatus(alias, s.mountStatus(ctx, alias)); err != nil {
+			debug.Log("Failed to set status of %s: %s", alias, err)
+		}
+	}
 	debug.Log("MountsPrint - %+v - %+v", mounts, mps)
 
 	fmt.Fprintln(stdout, root.Format(tree.INF))
 	return nil
 }
 
+// mountStatus returns the sync status of a store as of the last sync. It
+// doesn't contact the remote.
+func (s *Action) mountStatus(ctx context.Context, mp string) string {
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil {
+		debug.Log("Failed to get sub store %q: %s", mp, err)
+		return ""
+	}
+	return syncStatusString(sub.SyncStatus(ctx, false))
+}
+
 // MountsComplete will print a list of existings mount points for bash
 // completion.
 func (s *Action) MountsComplete(*cli.Context) {
@@ -1,8 +1,16 @@
 package action
 
 import (
+	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/gopasspw/gopass/internal/i18n"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/urfave/cli/v2"
@@ -20,8 +28,15 @@ func (s *Action) Move(c *cli.Context) error {
 	from := c.Args().Get(0)
 	to := c.Args().Get(1)
 
+	if c.Bool("pattern") && c.Bool("regex") {
+		return ExitError(ExitUsage, nil, "--pattern and --regex can't be used together")
+	}
+	if c.Bool("pattern") || c.Bool("regex") {
+		return s.moveMatching(ctx, from, to, c.Bool("regex"), c.Bool("dry-run"), force)
+	}
+
 	if !force {
-		if s.Store.Exists(ctx, to) && !termio.AskForConfirmation(ctx, fmt.Sprintf("%s already exists. Overwrite it?", to)) {
+		if s.Store.Exists(ctx, to) && !termio.AskForConfirmation(ctx, i18n.Sprintf(ctx, "%s already exists. Overwrite it?", to)) {
 			return ExitError(ExitAborted, nil, "not overwriting your current secret")
 		}
 	}
@@ -32,3 +47,152 @@ func (s *Action) Move(c *cli.Context) error {
 
 	return nil
 }
+
+// moveMatching moves all secrets matching from to the name built from to.
+// Either from is a pattern and the wildcards in to are replaced with what
+// they matched, or from is a regular expression and to may reference its
+// groups (e.g. $1).
+func (s *Action) moveMatching(ctx context.Context, from, to string, regex, dryRun, force bool) error {
+	re, tpl, err := renamePattern(from, to, regex)
+	if err != nil {
+		return ExitError(ExitUsage, err, "%s", err)
+	}
+
+	names, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return ExitError(ExitList, err, "failed to list store: %s", err)
+	}
+
+	renames, err := renameAll(names, re, tpl)
+	if err != nil {
+		return ExitError(ExitUsage, err, "%s", err)
+	}
+	if len(renames) < 1 {
+		return ExitError(ExitNotFound, nil, "no secret matches %s", from)
+	}
+
+	srcs := make([]string, 0, len(renames))
+	for src := range renames {
+		srcs = append(srcs, src)
+	}
+	sort.Strings(srcs)
+
+	var overwrites []string
+	for _, src := range srcs {
+		dst := renames[src]
+		out.Printf(ctx, "%s -> %s", src, dst)
+		if _, moved := renames[dst]; !moved && s.Store.Exists(ctx, dst) {
+			overwrites = append(overwrites, dst)
+		}
+	}
+
+	if dryRun {
+		out.Noticef(ctx, "Would move %d secrets", len(renames))
+		return nil
+	}
+
+	if len(overwrites) > 0 && !force {
+		if !termio.AskForConfirmation(ctx, fmt.Sprintf("This overwrites %s. Continue?", strings.Join(overwrites, ", "))) {
+			return ExitError(ExitAborted, nil, "not overwriting your current secrets")
+		}
+	}
+
+	msg := fmt.Sprintf("Move %d secrets from %s to %s", len(renames), from, to)
+	if err := s.Store.MoveMany(ctx, renames, msg); err != nil {
+		return ExitError(ExitUnknown, err, "%s", err)
+	}
+
+	out.OKf(ctx, "Moved %d secrets", len(renames))
+	return nil
+}
+
+// renamePattern returns the regular expression that matches the secrets to
+// move and the template for their new names. In patterns * matches anything
+// but a slash and ** matches anything.
+func renamePattern(from, to string, regex bool) (*regexp.Regexp, string, error) {
+	if regex {
+		re, err := regexp.Compile("^(?:" + from + ")$")
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid regular expression %q: %w", from, err)
+		}
+		return re, to, nil
+	}
+
+	expr, n := globToRegexp(from)
+	tpl, m := globToTemplate(to)
+	if n != m {
+		return nil, "", fmt.Errorf("%s has %d wildcards but %s has %d", from, n, to, m)
+	}
+
+	return regexp.MustCompile(expr), tpl, nil
+}
+
+// globToRegexp translates a pattern and returns the number of wildcards.
+func globToRegexp(glob string) (string, int) {
+	var sb strings.Builder
+	n := 0
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		if glob[i] != '*' {
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			continue
+		}
+		n++
+		if i+1 < len(glob) && glob[i+1] == '*' {
+			i++
+			sb.WriteString("(.*)")
+			continue
+		}
+		sb.WriteString("([^/]*)")
+	}
+	sb.WriteString("$")
+	return sb.String(), n
+}
+
+// globToTemplate replaces the wildcards with references to the groups of
+// the regexp built by globToRegexp.
+func globToTemplate(glob string) (string, int) {
+	var sb strings.Builder
+	n := 0
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '*':
+			n++
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+			}
+			sb.WriteString("${" + strconv.Itoa(n) + "}")
+		case '$':
+			sb.WriteString("$$")
+		default:
+			sb.WriteByte(glob[i])
+		}
+	}
+	return sb.String(), n
+}
+
+// renameAll returns the new name of every matching secret. Secrets that
+// keep their name are skipped.
+func renameAll(names []string, re *regexp.Regexp, tpl string) (map[string]string, error) {
+	renames := make(map[string]string, len(names))
+	srcOf := make(map[string]string, len(names))
+	for _, name := range names {
+		m := re.FindStringSubmatchIndex(name)
+		if m == nil {
+			continue
+		}
+		dst := strings.Trim(string(re.ExpandString(nil, tpl, name, m)), "/")
+		if dst == "" {
+			return nil, fmt.Errorf("%s would be moved to an empty name", name)
+		}
+		if dst == name {
+			continue
+		}
+		if other, found := srcOf[dst]; found {
+			return nil, fmt.Errorf("%s and %s would both be moved to %s", other, name, dst)
+		}
+		srcOf[dst] = name
+		renames[name] = dst
+	}
+	return renames, nil
+}
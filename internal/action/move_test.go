@@ -38,3 +38,94 @@ func TestMove(t *testing.T) {
 		assert.NoError(t, act.Move(gptest.CliCtx(ctx, t, "foo", "bar")))
 	})
 }
+
+func TestMovePattern(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	for _, name := range []string{"old/a", "old/b", "old/sub/c", "web/x/login", "web/y/login"} {
+		require.NoError(t, act.insertStdin(ctx, name, []byte("secret"), false, nil))
+	}
+	buf.Reset()
+
+	t.Run("dry run", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Move(gptest.CliCtxWithFlags(ctx, t, map[string]string{"pattern": "true", "dry-run": "true"}, "old/*", "new/*")))
+		assert.Contains(t, buf.String(), "old/a -> new/a")
+		assert.Contains(t, buf.String(), "old/b -> new/b")
+		assert.NotContains(t, buf.String(), "old/sub/c")
+		assert.True(t, act.Store.Exists(ctx, "old/a"))
+		assert.False(t, act.Store.Exists(ctx, "new/a"))
+	})
+
+	t.Run("pattern", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Move(gptest.CliCtxWithFlags(ctx, t, map[string]string{"pattern": "true"}, "old/**", "new/**")))
+		for _, name := range []string{"new/a", "new/b", "new/sub/c"} {
+			assert.True(t, act.Store.Exists(ctx, name), name)
+		}
+		assert.False(t, act.Store.IsDir(ctx, "old"))
+	})
+
+	t.Run("regex", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Move(gptest.CliCtxWithFlags(ctx, t, map[string]string{"regex": "true"}, `web/(\w+)/login`, "login/$1")))
+		assert.True(t, act.Store.Exists(ctx, "login/x"))
+		assert.True(t, act.Store.Exists(ctx, "login/y"))
+		assert.False(t, act.Store.Exists(ctx, "web/x/login"))
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Move(gptest.CliCtxWithFlags(ctx, t, map[string]string{"pattern": "true"}, "nope/*", "new/*")))
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Move(gptest.CliCtxWithFlags(ctx, t, map[string]string{"regex": "true"}, "login/.*", "single")))
+		assert.True(t, act.Store.Exists(ctx, "login/x"))
+	})
+}
+
+func TestRenamePattern(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"old/a", "old/sub/b", "other/c"}
+	for _, tc := range []struct {
+		from, to string
+		regex    bool
+		want     map[string]string
+	}{
+		{from: "old/*", to: "new/*", want: map[string]string{"old/a": "new/a"}},
+		{from: "old/**", to: "new/**", want: map[string]string{"old/a": "new/a", "old/sub/b": "new/sub/b"}},
+		{from: "*/*", to: "*/renamed-*", want: map[string]string{"old/a": "old/renamed-a", "other/c": "other/renamed-c"}},
+		{from: "(old|other)/(.*)", to: "${2}-$1", regex: true, want: map[string]string{"old/a": "a-old", "old/sub/b": "sub/b-old", "other/c": "c-other"}},
+	} {
+		re, tpl, err := renamePattern(tc.from, tc.to, tc.regex)
+		require.NoError(t, err, tc.from)
+		got, err := renameAll(names, re, tpl)
+		require.NoError(t, err, tc.from)
+		assert.Equal(t, tc.want, got, tc.from)
+	}
+
+	_, _, err := renamePattern("old/*", "new", false)
+	assert.Error(t, err)
+	_, _, err = renamePattern("old/(", "new", true)
+	assert.Error(t, err)
+}
@@ -121,7 +121,7 @@ func (s *Action) otp(ctx context.Context, name, qrf string, clip, pw, recurse bo
 			cancel()
 		} else { // if not then we want to print a progress bar with the expiry time.
 			out.Printf(ctx, "%s", token)
-			out.Warningf(ctx, "([q] to stop. -o flag to avoid.) This OTP password still lasts for:", nil)
+			out.Warningf(ctx, "([q] to stop. -o flag to avoid.) This OTP password still lasts for:")
 
 			if bar.Hidden {
 				cancel()
@@ -0,0 +1,59 @@
+package action
+
+import (
+	"io"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/otp"
+	"github.com/urfave/cli/v2"
+)
+
+// OTPImport reads an OTP URL from a QR code image or a Google Authenticator
+// export (otpauth-migration://) and appends it to the given secret.
+func (s *Action) OTPImport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().Get(0)
+	src := c.Args().Get(1)
+	if name == "" || src == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s otp import <NAME> <IMAGE|URI>", s.Name)
+	}
+
+	urls, err := otp.Import(src)
+	if err != nil {
+		return ExitError(ExitUsage, err, "failed to read OTP from %s: %s", src, err)
+	}
+	if len(urls) > 1 {
+		return ExitError(ExitUsage, nil, "%s contains %d accounts. Please export one account at a time.", src, len(urls))
+	}
+
+	var sec gopass.Secret = secrets.New()
+	if s.Store.Exists(ctx, name) {
+		sec, err = s.Store.Get(ctx, name)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+	}
+
+	w, ok := sec.(io.Writer)
+	if !ok {
+		return ExitError(ExitUnsupported, nil, "can not append to %s (%T)", name, sec)
+	}
+	line := urls[0] + "\n"
+	if body := sec.Body(); body != "" && !strings.HasSuffix(body, "\n") {
+		line = "\n" + line
+	}
+	if _, err := w.Write([]byte(line)); err != nil {
+		return ExitError(ExitUnknown, err, "failed to append OTP URL: %s", err)
+	}
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Imported OTP"), name, sec); err != nil {
+		return ExitError(ExitEncrypt, err, "failed to write %s: %s", name, err)
+	}
+
+	out.OKf(ctx, "Imported OTP to %s", name)
+	return nil
+}
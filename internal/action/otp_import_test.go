@@ -0,0 +1,80 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/otp"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTPImport(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	otpURL := "otpauth://totp/Example:alice@google.com?issuer=Example&secret=JBSWY3DPEHPK3PXP"
+
+	t.Run("missing arguments", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.OTPImport(gptest.CliCtx(ctx, t, "foo")))
+	})
+
+	t.Run("append migration URI to existing secret", func(t *testing.T) {
+		defer buf.Reset()
+		sec := secrets.New()
+		sec.SetPassword("secret")
+		require.NoError(t, act.Store.Set(ctx, "otp/existing", sec))
+
+		uri := "otpauth-migration://offline?data=CjEKCkhlbGxvId6tvu8SGEV4YW1wbGU6YWxpY2VAZ29vZ2xlLmNvbRoHRXhhbXBsZSABKAEwAhABGAEgACgA"
+		require.NoError(t, act.OTPImport(gptest.CliCtx(ctx, t, "otp/existing", uri)))
+
+		sec, err := act.Store.Get(ctx, "otp/existing")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", sec.Password())
+
+		_, label, err := otp.Calculate("otp/existing", sec)
+		require.NoError(t, err)
+		assert.Equal(t, "Example:alice@google.com", label)
+	})
+
+	t.Run("import QR code to new secret", func(t *testing.T) {
+		defer buf.Reset()
+		fn := filepath.Join(u.Dir, "qr.png")
+		require.NoError(t, qrcode.WriteFile(otpURL, qrcode.Medium, 256, fn))
+
+		require.NoError(t, act.OTPImport(gptest.CliCtx(ctx, t, "otp/new", fn)))
+
+		sec, err := act.Store.Get(ctx, "otp/new")
+		require.NoError(t, err)
+		_, _, err = otp.Calculate("otp/new", sec)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid source", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.OTPImport(gptest.CliCtx(ctx, t, "otp/new", "https://example.com")))
+	})
+}
@@ -0,0 +1,72 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/pinentry/server"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// Pinentry speaks the pinentry protocol on stdin and stdout. It's meant to
+// be invoked by gpg-agent, not by users.
+func (s *Action) Pinentry(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	if err := server.Serve(ctx, stdin, stdout, server.TTY{}); err != nil {
+		return ExitError(ExitIO, err, "pinentry failed: %s", err)
+	}
+	return nil
+}
+
+// PinentryInstall configures gpg-agent to use gopass as its pinentry program.
+func (s *Action) PinentryInstall(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	if runtime.GOOS == "windows" {
+		return ExitError(ExitUnsupported, nil, "installing the gopass pinentry is not supported on Windows")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return ExitError(ExitUnknown, err, "failed to determine gopass binary: %s", err)
+	}
+
+	// gpg-agent does not accept arguments for the pinentry program so
+	// we need a small wrapper.
+	wrapper := filepath.Join(appdir.UserConfig(), "pinentry-gopass")
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("Do you want to configure gpg-agent (%s) to use %s as pinentry?", gpgconf.AgentConfigLoc(), wrapper)) {
+		return ExitError(ExitAborted, nil, "user aborted")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(wrapper), 0700); err != nil {
+		return ExitError(ExitIO, err, "failed to create %s: %s", filepath.Dir(wrapper), err)
+	}
+	if err := os.WriteFile(wrapper, []byte(pinentryWrapper(exe)), 0700); err != nil {
+		return ExitError(ExitIO, err, "failed to write %s: %s", wrapper, err)
+	}
+
+	if err := gpgconf.SetAgentOption("pinentry-program", wrapper); err != nil {
+		return ExitError(ExitIO, err, "failed to update gpg-agent config: %s", err)
+	}
+
+	if err := gpgconf.ReloadAgent(ctx); err != nil {
+		out.Warningf(ctx, "Failed to reload gpg-agent. Please restart it manually: %s", err)
+	}
+
+	out.OKf(ctx, "gpg-agent will now use %s to ask for passphrases", wrapper)
+	return nil
+}
+
+// pinentryWrapper returns a shell script that runs the pinentry of the gopass
+// binary exe.
+func pinentryWrapper(exe string) string {
+	return "#!/bin/sh\nexec " + shellQuote(exe) + " pinentry \"$@\"\n"
+}
@@ -0,0 +1,33 @@
+package action
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinentryWrapper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no shell scripts on windows")
+	}
+
+	td := t.TempDir()
+	exe := filepath.Join(td, `it's a "$(touch pwned)" dir`, "gopass")
+	require.NoError(t, os.MkdirAll(filepath.Dir(exe), 0700))
+	require.NoError(t, os.WriteFile(exe, []byte("#!/bin/sh\necho \"$@\"\n"), 0700))
+
+	wrapper := filepath.Join(td, "pinentry-gopass")
+	require.NoError(t, os.WriteFile(wrapper, []byte(pinentryWrapper(exe)), 0700))
+
+	cmd := exec.Command(wrapper, "--display", ":0")
+	cmd.Dir = td
+	buf, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "pinentry --display :0\n", string(buf))
+	assert.NoFileExists(t, filepath.Join(td, "pwned"))
+}
@@ -0,0 +1,143 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+	"github.com/gopasspw/gopass/pkg/protect"
+)
+
+// unveilCommands only work with the stores, so they can run with the file
+// system restricted to the paths returned by unveilPaths. Other commands
+// take arbitrary paths, e.g. init, clone, fscopy, insert (from a file) or
+// otp import (QR code images).
+var unveilCommands = map[string]bool{
+	"audit":      true,
+	"cat":        true,
+	"copy":       true,
+	"delete":     true,
+	"edit":       true,
+	"find":       true,
+	"fsck":       true,
+	"generate":   true,
+	"git":        true,
+	"grep":       true,
+	"history":    true,
+	"list":       true,
+	"move":       true,
+	"recipients": true,
+	"show":       true,
+	"sync":       true,
+	"templates":  true,
+	"unclip":     true,
+}
+
+// networkCommands talk to remote servers, e.g. git remotes, vault or the
+// status endpoint of watch. Other commands only need the network through the
+// programs they start, which are not pledged.
+var networkCommands = map[string]bool{
+	"clone": true,
+	"sync":  true,
+	"vault": true,
+	"watch": true,
+}
+
+// Pledge drops the promises the given command doesn't need on OpenBSD, see
+// pledge(2). args are the arguments of the command, e.g. the subcommand of
+// git.
+func (s *Action) Pledge(ctx context.Context, cmd string, args []string) error {
+	if !protect.ProtectEnabled {
+		return nil
+	}
+
+	promises := pledgePromises(cmd, args)
+	debug.Log("pledge(%q)", promises)
+	return protect.Pledge(promises)
+}
+
+func pledgePromises(cmd string, args []string) string {
+	promises := "stdio rpath wpath cpath tty proc exec unix"
+	if networkCommands[cmd] || (cmd == "git" && len(args) > 0 && (args[0] == "push" || args[0] == "pull")) {
+		promises += " inet dns"
+	}
+	return promises
+}
+
+// Unveil restricts the file system access of gopass on OpenBSD to what the
+// given command needs, see unveil(2). Programs started by gopass, e.g. gpg,
+// git or the editor, are not affected.
+func (s *Action) Unveil(ctx context.Context, cmd string) error {
+	if !protect.ProtectEnabled || !unveilCommands[cmd] {
+		return nil
+	}
+
+	paths := s.unveilPaths()
+	keys := make([]string, 0, len(paths))
+	for p := range paths {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+
+	for _, p := range keys {
+		debug.Log("unveil(%q, %q)", p, paths[p])
+		if err := protect.Unveil(p, paths[p]); err != nil {
+			return fmt.Errorf("failed to unveil %s: %w", p, err)
+		}
+	}
+
+	return protect.UnveilBlock()
+}
+
+// unveilPaths returns the paths gopass needs to access, and the unveil(2)
+// permissions for them.
+func (s *Action) unveilPaths() map[string]string {
+	paths := map[string]string{
+		// secrets, keyrings and git repos.
+		fsutil.CleanPath(s.cfg.Path): "rwc",
+		// config, caches, reminders and the audit log.
+		config.Directory():  "rwc",
+		appdir.UserConfig(): "rwc",
+		appdir.UserCache():  "rwc",
+		appdir.UserData():   "rwc",
+		// editor temp files and the dbus session bus.
+		os.TempDir(): "rwc",
+		// connecting to the gpg-agent socket requires write access.
+		gpgconf.Home(""): "rwc",
+		"/dev/null":      "rw",
+		"/dev/tty":       "rw",
+		// e.g. /etc/passwd to find the home dir.
+		"/etc":               "r",
+		"/usr/libexec/ld.so": "rx",
+	}
+	if s.cfg.Decoy != "" {
+		paths[fsutil.CleanPath(s.cfg.Decoy)] = "rwc"
+	}
+	for _, p := range s.cfg.Mounts {
+		paths[fsutil.CleanPath(p)] = "rwc"
+	}
+	for _, sc := range s.cfg.Stores {
+		if sc.GnupgHome != "" {
+			paths[fsutil.CleanPath(sc.GnupgHome)] = "rwc"
+		}
+	}
+
+	// gpg, git and friends as well as gopass itself (for unclip).
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir != "" {
+			paths[dir] = "rx"
+		}
+	}
+	if exe, err := os.Executable(); err == nil {
+		paths[exe] = "rx"
+	}
+
+	return paths
+}
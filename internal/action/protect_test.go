@@ -0,0 +1,73 @@
+package action
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/protect"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnveilPaths(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+
+	td := t.TempDir()
+	t.Setenv("PATH", filepath.Join(td, "bin")+string(os.PathListSeparator)+"/usr/bin")
+	act.cfg.Mounts = map[string]string{"work": filepath.Join(td, "work")}
+	act.cfg.Stores = map[string]config.StoreConfig{"work": {GnupgHome: filepath.Join(td, "gnupg")}}
+
+	paths := act.unveilPaths()
+	assert.Equal(t, "rwc", paths[u.StoreDir("")])
+	assert.Equal(t, "rwc", paths[filepath.Join(td, "work")])
+	assert.Equal(t, "rwc", paths[filepath.Join(td, "gnupg")])
+	assert.Equal(t, "rwc", paths[os.TempDir()])
+	assert.Equal(t, "rx", paths[filepath.Join(td, "bin")])
+	assert.Equal(t, "rx", paths["/usr/bin"])
+	assert.Equal(t, "r", paths["/etc"])
+
+	if !protect.ProtectEnabled {
+		assert.NoError(t, act.Unveil(ctx, "show"))
+	}
+}
+
+func TestUnveilCommands(t *testing.T) {
+	// these commands read files given on the command line
+	for _, cmd := range []string{"init", "clone", "fscopy", "insert", "otp"} {
+		assert.False(t, unveilCommands[cmd], cmd)
+	}
+}
+
+func TestPledgePromises(t *testing.T) {
+	for _, tc := range []struct {
+		cmd  string
+		args []string
+		net  bool
+	}{
+		{cmd: "show", args: []string{"foo"}},
+		{cmd: "git", args: []string{"status"}},
+		{cmd: "git", args: []string{"push"}, net: true},
+		{cmd: "git", args: []string{"pull", "origin"}, net: true},
+		{cmd: "sync", net: true},
+		{cmd: "clone", net: true},
+		{cmd: "watch", net: true},
+	} {
+		promises := pledgePromises(tc.cmd, tc.args)
+		assert.Contains(t, promises, "proc exec", tc.cmd)
+		assert.NotContains(t, promises, "unveil", tc.cmd)
+		if tc.net {
+			assert.Contains(t, promises, "inet dns", tc.cmd)
+		} else {
+			assert.NotContains(t, promises, "inet", tc.cmd)
+		}
+	}
+}
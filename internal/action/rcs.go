@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/gopasspw/gopass/internal/backend"
@@ -105,10 +106,24 @@ func (s *Action) RCSAddRemote(c *cli.Context) error {
 	url := c.Args().Get(1)
 
 	if remote == "" || url == "" {
-		return ExitError(ExitUsage, nil, "Usage: %s git remote add <REMOTE> <URL>", s.Name)
+		return ExitError(ExitUsage, nil, "Usage: %s git remote add [--mirror] <REMOTE> <URL>", s.Name)
 	}
 
-	return s.Store.RCSAddRemote(ctx, store, remote, url)
+	if err := s.Store.RCSAddRemote(ctx, store, remote, url); err != nil {
+		return err
+	}
+
+	if !c.Bool("mirror") {
+		return nil
+	}
+
+	mirrors := append(withoutRemote(strings.Fields(s.cfg.StoreConfig(store).Mirrors), remote), remote)
+	if err := s.cfg.SetStoreConfigValue(store, "mirrors", strings.Join(mirrors, " ")); err != nil {
+		return ExitError(ExitConfig, err, "Failed to add mirror: %s", err)
+	}
+	out.OKf(ctx, "Added mirror %s. gopass sync will push to it", remote)
+
+	return nil
 }
 
 // RCSRemoveRemote removes a git remote.
@@ -121,7 +136,31 @@ func (s *Action) RCSRemoveRemote(c *cli.Context) error {
 		return ExitError(ExitUsage, nil, "Usage: %s git remote rm <REMOTE>", s.Name)
 	}
 
-	return s.Store.RCSRemoveRemote(ctx, store, remote)
+	if err := s.Store.RCSRemoveRemote(ctx, store, remote); err != nil {
+		return err
+	}
+
+	mirrors := strings.Fields(s.cfg.StoreConfig(store).Mirrors)
+	kept := withoutRemote(mirrors, remote)
+	if len(kept) == len(mirrors) {
+		return nil
+	}
+	if err := s.cfg.SetStoreConfigValue(store, "mirrors", strings.Join(kept, " ")); err != nil {
+		return ExitError(ExitConfig, err, "Failed to remove mirror: %s", err)
+	}
+
+	return nil
+}
+
+// withoutRemote returns the mirrors except remote.
+func withoutRemote(mirrors []string, remote string) []string {
+	kept := make([]string, 0, len(mirrors))
+	for _, m := range mirrors {
+		if m != remote {
+			kept = append(kept, m)
+		}
+	}
+	return kept
 }
 
 // RCSPull pulls from a git remote.
@@ -159,3 +198,59 @@ func (s *Action) RCSStatus(c *cli.Context) error {
 
 	return s.Store.RCSStatus(ctx, store)
 }
+
+// GitGC compacts the git repository of a store. With --purge it removes the
+// given deleted secrets from the whole history first.
+func (s *Action) GitGC(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	names := c.StringSlice("purge")
+
+	if len(names) < 1 {
+		store := c.String("store")
+		if err := s.Store.Storage(ctx, store).Compact(ctx); err != nil {
+			return ExitError(ExitGit, err, "Failed to compact %s: %s", watchName(store), err)
+		}
+		out.OKf(ctx, "Compacted %s", watchName(store))
+		return nil
+	}
+
+	for _, name := range names {
+		if s.Store.Exists(ctx, name) {
+			return ExitError(ExitUsage, nil, "%s still exists. Delete it first with 'gopass rm %s'", name, name)
+		}
+	}
+
+	out.Warningf(ctx, "This rewrites the whole git history. All other clones of the store will have to be deleted and cloned again.")
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("Remove %d secrets from all revisions?", len(names))) {
+		return ExitError(ExitAborted, nil, "user aborted")
+	}
+
+	if err := s.Store.Purge(ctx, names...); err != nil {
+		return ExitError(ExitGit, err, "Failed to purge: %s", err)
+	}
+	out.OKf(ctx, "Removed %d secrets from the history", len(names))
+
+	mps := make(map[string]bool, 1)
+	for _, name := range names {
+		mps[s.Store.MountPoint(name)] = true
+	}
+	for mp := range mps {
+		if !termio.AskForConfirmation(ctx, fmt.Sprintf("Force push the rewritten history of %s to its remote?", watchName(mp))) {
+			out.Noticef(ctx, "Not pushing. Run 'gopass git gc --purge' again or push with --force manually.")
+			continue
+		}
+		if err := s.Store.ForcePush(ctx, mp); err != nil {
+			if errors.Is(err, si.ErrGitNoRemote) {
+				out.Noticef(ctx, "No Git remote. Not pushing")
+				continue
+			}
+			return ExitError(ExitGit, err, "Failed to push %s: %s", watchName(mp), err)
+		}
+		out.OKf(ctx, "Pushed the rewritten history of %s", watchName(mp))
+	}
+
+	out.Noticef(ctx, "Other clones refuse to pull now. Delete them and run 'gopass clone' again. "+
+		"The secrets can still be recovered from clones, backups and forks that were made before.")
+
+	return nil
+}
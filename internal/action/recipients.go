@@ -2,10 +2,14 @@ package action
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/gopasspw/gopass/internal/cui"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/internal/tree"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
@@ -108,7 +112,17 @@ func (s *Action) RecipientsAdd(c *cli.Context) error {
 			continue
 		}
 
+		if len(keys) > 1 && !force {
+			out.Warningf(ctx, "%q matches multiple keys (%s). Please specify the fingerprint of the key to add.", r, strings.Join(keys, ", "))
+			continue
+		}
+
+		// always store the full fingerprint, short key IDs are easy to collide
+		// and emails or names might match other keys later.
 		recp := r
+		if len(keys) == 1 {
+			recp = crypto.Fingerprint(ctx, keys[0])
+		}
 		debug.Log("found recipients for %q: %+v", r, keys)
 
 		if !termio.AskForConfirmation(ctx, fmt.Sprintf("Do you want to add %q (key %q) as a recipient to the store %q?", crypto.FormatKey(ctx, recp, ""), recp, store)) {
@@ -200,6 +214,105 @@ func (s *Action) RecipientsRemove(c *cli.Context) error {
 	return nil
 }
 
+// RecipientsMigrate replaces short key IDs, emails and names in the recipient
+// lists with full fingerprints.
+func (s *Action) RecipientsMigrate(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	stores := []string{c.String("store")}
+	if !c.IsSet("store") {
+		stores = append([]string{""}, s.Store.MountPoints()...)
+	}
+
+	migrated := 0
+	for _, store := range stores {
+		if name := s.Store.Crypto(ctx, store).Name(); name != "gpg" {
+			debug.Log("not migrating recipients of store %q using %s", store, name)
+			continue
+		}
+		n, err := s.Store.MigrateRecipients(ctx, store)
+		if err != nil {
+			return ExitError(ExitRecipients, err, "failed to migrate recipients of store %q: %s", store, err)
+		}
+		migrated += n
+	}
+
+	if migrated < 1 {
+		out.Printf(ctx, "All recipients are already using fingerprints")
+		return nil
+	}
+
+	out.Printf(ctx, "\nMigrated %d recipients", migrated)
+	out.Printf(ctx, "You need to run 'gopass sync' to push these changes")
+	return nil
+}
+
+type keyUntruster interface {
+	UntrustKey(ctx context.Context, id string) error
+}
+
+// RecipientsDeauthorize removes a recipient from all stores, re-encrypts the
+// secrets it could read and prints them so they can be rotated.
+func (s *Action) RecipientsDeauthorize(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	id := c.Args().First()
+	if id == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s recipients deauthorize <KEY>", s.Name)
+	}
+
+	crypto := s.Store.Crypto(ctx, "")
+	if !c.Bool("force") {
+		if kl, err := crypto.FindIdentities(ctx, id); err == nil && len(kl) > 0 {
+			if !termio.AskForConfirmation(ctx, fmt.Sprintf("Do you want to remove yourself (%s) from the recipients?", id)) {
+				return ExitError(ExitAborted, nil, "user aborted")
+			}
+		}
+		if !termio.AskForConfirmation(ctx, fmt.Sprintf("Do you want to remove %s from all stores and re-encrypt the secrets it can read?", id)) {
+			return ExitError(ExitAborted, nil, "user aborted")
+		}
+	}
+
+	var found bool
+	var exposed []string
+	for _, mp := range append([]string{""}, s.Store.MountPoints()...) {
+		names, err := s.Store.DeauthorizeRecipient(ctx, mp, id)
+		if errors.Is(err, store.ErrRecipientNotFound) {
+			debug.Log("%s is not a recipient of store %q", id, mp)
+			continue
+		}
+		if err != nil {
+			return ExitError(ExitRecipients, err, "failed to deauthorize %s in store %q: %s", id, mp, err)
+		}
+		found = true
+		exposed = append(exposed, names...)
+	}
+	if !found {
+		return ExitError(ExitNotFound, nil, "%s is not a recipient of any store", id)
+	}
+
+	if u, ok := crypto.(keyUntruster); ok {
+		if err := u.UntrustKey(ctx, id); err != nil {
+			out.Warningf(ctx, "Failed to mark %s as untrusted: %s", id, err)
+		} else {
+			out.OKf(ctx, "Marked %s as untrusted", id)
+		}
+	}
+
+	fmt.Fprintf(stdout, removalWarning, id)
+	if len(exposed) < 1 {
+		out.Printf(ctx, "%s could not read any secret", id)
+		return nil
+	}
+
+	sort.Strings(exposed)
+	out.Printf(ctx, "%s could read these secrets. Rotate them:", id)
+	for _, name := range exposed {
+		fmt.Fprintf(stdout, "[ ] %s\n", name)
+	}
+
+	return nil
+}
+
 func (s *Action) recipientsSelectForRemoval(ctx context.Context, store string) ([]string, error) {
 	crypto := s.Store.Crypto(ctx, store)
 
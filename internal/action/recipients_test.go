@@ -80,4 +80,21 @@ func TestRecipients(t *testing.T) {
 		defer buf.Reset()
 		assert.NoError(t, act.RecipientsRemove(gptest.CliCtx(ctx, t, "0xDEADBEEF")))
 	})
+
+	t.Run("deauthorize recipient w/o args", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.RecipientsDeauthorize(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("deauthorize recipient 0xFEEDBEEF", func(t *testing.T) {
+		defer buf.Reset()
+		assert.NoError(t, act.RecipientsDeauthorize(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, "0xFEEDBEEF")))
+		assert.Contains(t, buf.String(), "[ ] foo\n")
+		assert.NotContains(t, act.Store.ListRecipients(ctx, ""), "0xFEEDBEEF")
+	})
+
+	t.Run("deauthorize unknown recipient", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.RecipientsDeauthorize(gptest.CliCtxWithFlags(ctx, t, map[string]string{"force": "true"}, "0x12345678")))
+	})
 }
@@ -0,0 +1,150 @@
+package action
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// recoveryKey holds the unused recovery codes, one value per code.
+	recoveryKey = "recovery"
+	// recoveryUsedKey holds the recovery codes that were already handed out.
+	recoveryUsedKey = "recovery-used"
+	// recoveryLowMark is the number of unused codes below which we remind
+	// the user to generate new ones.
+	recoveryLowMark = 2
+)
+
+// RecoveryAdd stores one-time recovery codes (e.g. 2FA backup codes) in a
+// secret. The codes are read from stdin, one per line, or asked for
+// interactively.
+func (s *Action) RecoveryAdd(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s recovery add <NAME>", s.Name)
+	}
+
+	codes, err := s.recoveryReadCodes(ctx)
+	if err != nil {
+		return ExitError(ExitIO, err, "failed to read recovery codes: %s", err)
+	}
+	if len(codes) < 1 {
+		return ExitError(ExitAborted, nil, "no recovery codes given")
+	}
+
+	sec := secrets.New()
+	if s.Store.Exists(ctx, name) {
+		sec, err = s.Store.Get(ctx, name)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+	}
+
+	if c.Bool("replace") {
+		sec.Del(recoveryKey)
+		sec.Del(recoveryUsedKey)
+	}
+	for _, code := range codes {
+		if err := sec.Add(recoveryKey, code); err != nil {
+			return ExitError(ExitUnsupported, err, "failed to add recovery codes to %s: %s", name, err)
+		}
+	}
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Added %d recovery codes", len(codes))), name, sec); err != nil {
+		return ExitError(ExitEncrypt, err, "failed to save %s: %s", name, err)
+	}
+
+	out.OKf(ctx, "Added %d recovery codes to %s", len(codes), name)
+	return nil
+}
+
+// RecoveryUse prints the next unused recovery code of a secret and marks it
+// as used.
+func (s *Action) RecoveryUse(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s recovery use <NAME>", s.Name)
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+	}
+
+	code, left, err := recoveryNext(sec)
+	if err != nil {
+		return ExitError(ExitNotFound, err, "%s has no unused recovery codes left", name)
+	}
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Used recovery code"), name, sec); err != nil {
+		return ExitError(ExitEncrypt, err, "failed to save %s: %s", name, err)
+	}
+
+	out.Printf(ctx, "%s", code)
+	if left <= recoveryLowMark {
+		out.Warningf(ctx, "Only %d unused recovery codes left in %s. Consider generating new ones.", left, name)
+	}
+	return nil
+}
+
+// recoveryNext moves the first unused recovery code to the used ones and
+// returns it together with the number of unused codes left.
+func recoveryNext(sec gopass.Secret) (string, int, error) {
+	vs, found := sec.Values(recoveryKey)
+	if !found || len(vs) < 1 {
+		return "", 0, fmt.Errorf("no unused recovery codes")
+	}
+	codes := make([]string, len(vs))
+	copy(codes, vs)
+
+	sec.Del(recoveryKey)
+	for _, code := range codes[1:] {
+		if err := sec.Add(recoveryKey, code); err != nil {
+			return "", 0, err
+		}
+	}
+	if err := sec.Add(recoveryUsedKey, codes[0]); err != nil {
+		return "", 0, err
+	}
+
+	return codes[0], len(codes) - 1, nil
+}
+
+// recoveryReadCodes reads recovery codes from stdin or asks for them until
+// an empty code is entered.
+func (s *Action) recoveryReadCodes(ctx context.Context) ([]string, error) {
+	var codes []string
+
+	if ctxutil.IsStdin(ctx) {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			if code := strings.TrimSpace(scanner.Text()); code != "" {
+				codes = append(codes, code)
+			}
+		}
+		return codes, scanner.Err()
+	}
+
+	for {
+		code, err := termio.AskForString(ctx, fmt.Sprintf("Recovery code #%d (empty to finish)", len(codes)+1), "")
+		if err != nil {
+			return nil, err
+		}
+		code = strings.TrimSpace(code)
+		if code == "" {
+			return codes, nil
+		}
+		codes = append(codes, code)
+	}
+}
@@ -0,0 +1,95 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	ibuf := &bytes.Buffer{}
+	stdin = ibuf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+		stdin = os.Stdin
+	}()
+
+	sec := secrets.New()
+	sec.SetPassword("secret")
+	require.NoError(t, act.Store.Set(ctx, "web", sec))
+
+	t.Run("add without codes", func(t *testing.T) {
+		assert.Error(t, act.RecoveryAdd(gptest.CliCtx(ctx, t, "web")))
+	})
+
+	t.Run("add codes from stdin", func(t *testing.T) {
+		ctx := ctxutil.WithStdin(ctx, true)
+		ibuf.WriteString("aaaa-1111\n\n  bbbb-2222 \ncccc-3333\n")
+		assert.NoError(t, act.RecoveryAdd(gptest.CliCtx(ctx, t, "web")))
+		buf.Reset()
+
+		sec, err := act.Store.Get(ctx, "web")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", sec.Password())
+		codes, found := sec.Values(recoveryKey)
+		assert.True(t, found)
+		assert.Equal(t, []string{"aaaa-1111", "bbbb-2222", "cccc-3333"}, codes)
+	})
+
+	t.Run("use codes", func(t *testing.T) {
+		for _, want := range []string{"aaaa-1111", "bbbb-2222", "cccc-3333"} {
+			assert.NoError(t, act.RecoveryUse(gptest.CliCtx(ctx, t, "web")))
+			assert.Equal(t, want+"\n", buf.String())
+			buf.Reset()
+		}
+		assert.Error(t, act.RecoveryUse(gptest.CliCtx(ctx, t, "web")))
+
+		sec, err := act.Store.Get(ctx, "web")
+		require.NoError(t, err)
+		used, found := sec.Values(recoveryUsedKey)
+		assert.True(t, found)
+		assert.Equal(t, []string{"aaaa-1111", "bbbb-2222", "cccc-3333"}, used)
+	})
+
+	t.Run("replace codes", func(t *testing.T) {
+		ctx := ctxutil.WithStdin(ctx, true)
+		ibuf.WriteString("dddd-4444\n")
+		assert.NoError(t, act.RecoveryAdd(gptest.CliCtxWithFlags(ctx, t, map[string]string{"replace": "true"}, "web")))
+		buf.Reset()
+
+		sec, err := act.Store.Get(ctx, "web")
+		require.NoError(t, err)
+		codes, _ := sec.Values(recoveryKey)
+		assert.Equal(t, []string{"dddd-4444"}, codes)
+		_, found := sec.Values(recoveryUsedKey)
+		assert.False(t, found)
+	})
+
+	t.Run("no name", func(t *testing.T) {
+		assert.Error(t, act.RecoveryAdd(gptest.CliCtx(ctx, t)))
+		assert.Error(t, act.RecoveryUse(gptest.CliCtx(ctx, t)))
+	})
+}
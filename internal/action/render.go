@@ -0,0 +1,64 @@
+package action
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tpl"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Render processes a template file, e.g. a config file, and writes the
+// result with all secrets filled in to stdout or to a file that is only
+// readable by the current user.
+func (s *Action) Render(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	file := c.Args().First()
+	if file == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s render [--output FILE] <TEMPLATE>", s.Name)
+	}
+
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return ExitError(ExitIO, err, "Failed to read template %s: %s", file, err)
+	}
+
+	obuf, err := tpl.Execute(ctx, string(buf), file, nil, s.Store)
+	if err != nil {
+		return ExitError(ExitIO, err, "Failed to render template %s: %s", file, err)
+	}
+
+	dst := c.String("output")
+	if dst == "" || dst == "-" {
+		fmt.Fprint(stdout, string(obuf))
+		return nil
+	}
+
+	if err := writeSecretFile(dst, obuf); err != nil {
+		return ExitError(ExitIO, err, "Failed to write %s: %s", dst, err)
+	}
+	out.OKf(ctx, "Rendered %s to %s", file, dst)
+
+	return nil
+}
+
+// writeSecretFile writes buf to the named file and makes sure that only the
+// current user can read it, even if the file already existed with more
+// permissive permissions.
+func writeSecretFile(fn string, buf []byte) error {
+	fh, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := fh.Chmod(0o600); err != nil {
+		_ = fh.Close()
+		return err
+	}
+	if _, err := fh.Write(buf); err != nil {
+		_ = fh.Close()
+		return err
+	}
+	return fh.Close()
+}
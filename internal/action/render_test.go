@@ -0,0 +1,85 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		stdout = os.Stdout
+	}()
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	sec := secrets.New()
+	sec.SetPassword("hunter2")
+	require.NoError(t, sec.Set("user", "admin"))
+	require.NoError(t, act.Store.Set(ctx, "db/prod", sec))
+
+	infile := filepath.Join(u.Dir, "app.conf.tmpl")
+	require.NoError(t, os.WriteFile(infile, []byte(`user={{ secret "db/prod" "user" }}
+password={{ secret "db/prod" "password" }}
+`), 0o644))
+	want := "user=admin\npassword=hunter2\n"
+
+	t.Run("no template", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Render(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("render to stdout", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Render(gptest.CliCtx(ctx, t, infile)))
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("render to file", func(t *testing.T) {
+		defer buf.Reset()
+
+		outfile := filepath.Join(u.Dir, "app.conf")
+		require.NoError(t, os.WriteFile(outfile, []byte("old content that is longer"), 0o644))
+
+		c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"output": outfile}, infile)
+		require.NoError(t, act.Render(c))
+
+		got, err := os.ReadFile(outfile)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got))
+
+		fi, err := os.Stat(outfile)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		defer buf.Reset()
+
+		badfile := filepath.Join(u.Dir, "bad.tmpl")
+		require.NoError(t, os.WriteFile(badfile, []byte(`{{ secret "db/missing" }}`), 0o644))
+		assert.Error(t, act.Render(gptest.CliCtx(ctx, t, badfile)))
+		assert.Equal(t, "", buf.String())
+	})
+}
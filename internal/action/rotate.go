@@ -0,0 +1,83 @@
+package action
+
+import (
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/rotate"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/pwgen"
+	"github.com/urfave/cli/v2"
+)
+
+// rotateKey is the key of the secret that names the rotator.
+const rotateKey = "rotate"
+
+// Rotate changes a credential at the provider using the rotator named in the
+// rotate key of the secret and stores the new password.
+func (s *Action) Rotate(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s rotate <NAME>", s.Name)
+	}
+
+	if !s.Store.Exists(ctx, name) {
+		return ExitError(ExitNotFound, nil, "Secret %s not found", name)
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+	}
+
+	spec, found := sec.Get(rotateKey)
+	if !found || spec == "" {
+		return ExitError(ExitUsage, nil, "%s has no %s key. Add one naming the rotator, e.g. '%s: postgres'", name, rotateKey, rotateKey)
+	}
+
+	length := c.Int("length")
+	if length < 1 {
+		return ExitError(ExitUsage, nil, "password length must not be zero")
+	}
+	pw, err := pwgen.GeneratePasswordWithAllClasses(length, c.Bool("symbols"))
+	if err != nil {
+		return ExitError(ExitUnknown, err, "failed to generate password: %s", err)
+	}
+
+	req := rotate.Request{
+		Name:        name,
+		Password:    sec.Password(),
+		NewPassword: pw,
+		Values:      make(map[string]string, len(sec.Keys())),
+	}
+	for _, k := range sec.Keys() {
+		if k == rotateKey {
+			continue
+		}
+		req.Values[k], _ = sec.Get(k)
+	}
+
+	resp, err := rotate.Run(ctx, spec, req)
+	if err != nil {
+		return ExitError(ExitUnknown, err, "failed to rotate %s: %s", name, err)
+	}
+
+	if resp.Password != "" {
+		pw = resp.Password
+	}
+	sec.SetPassword(pw)
+	for k, v := range resp.Values {
+		if err := sec.Set(k, v); err != nil {
+			out.Errorf(ctx, "The credential was changed but %s can not be updated. The new password is: %s", name, pw)
+			return ExitError(ExitUnknown, err, "failed to set %s of %s: %s", k, name, err)
+		}
+	}
+
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, "Rotated password"), name, sec); err != nil {
+		// the old password is gone already, don't lose the new one as well
+		out.Errorf(ctx, "The credential was changed but %s can not be updated. The new password is: %s", name, pw)
+		return ExitError(ExitEncrypt, err, "failed to write %s: %s", name, err)
+	}
+
+	out.OKf(ctx, "Rotated %s", name)
+	return nil
+}
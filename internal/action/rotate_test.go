@@ -0,0 +1,89 @@
+//go:build !windows
+// +build !windows
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/rotate"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotate(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	dir := t.TempDir()
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, rotate.Prefix+"db"), []byte(`#!/bin/sh
+grep -q '"password":"old"' || exit 1
+echo '{"values":{"rotated-by":"'$GOPASS_SECRET'"}}'
+`), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, rotate.Prefix+"fail"), []byte("#!/bin/sh\nexit 1\n"), 0o700))
+
+	t.Run("no name", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Rotate(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("no rotator", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Store.Set(ctx, "plain", secrets.NewKVWithData("old", nil, "", false)))
+		assert.Error(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"length": "24"}, "plain")))
+	})
+
+	t.Run("rotator fails", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Store.Set(ctx, "broken", secrets.NewKVWithData("old", map[string][]string{"rotate": {"fail"}}, "", false)))
+		assert.Error(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"length": "24"}, "broken")))
+
+		sec, err := act.Store.Get(ctx, "broken")
+		require.NoError(t, err)
+		assert.Equal(t, "old", sec.Password())
+	})
+
+	t.Run("rotator with arguments", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Store.Set(ctx, "args", secrets.NewKVWithData("old", map[string][]string{"rotate": {"db --host db1"}}, "", false)))
+		assert.Error(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"length": "24"}, "args")))
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Store.Set(ctx, "db/prod", secrets.NewKVWithData("old", map[string][]string{"rotate": {"db"}, "user": {"admin"}}, "", false)))
+		require.NoError(t, act.Rotate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"length": "32"}, "db/prod")))
+
+		sec, err := act.Store.Get(ctx, "db/prod")
+		require.NoError(t, err)
+		assert.Len(t, sec.Password(), 32)
+		v, _ := sec.Get("rotated-by")
+		assert.Equal(t, "db/prod", v)
+		v, _ = sec.Get("user")
+		assert.Equal(t, "admin", v)
+	})
+}
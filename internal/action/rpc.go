@@ -0,0 +1,25 @@
+package action
+
+import (
+	"github.com/gopasspw/gopass/internal/rpc"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// RPC serves JSON-RPC requests on stdin and stdout. It's meant to be
+// invoked by editor plugins, not by users.
+func (s *Action) RPC(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	srv := rpc.New(s.Store)
+	if c.IsSet("poll") {
+		srv.Poll = c.Duration("poll")
+	}
+
+	if err := srv.Serve(ctx, stdin, stdout); err != nil {
+		return ExitError(ExitIO, err, "rpc failed: %s", err)
+	}
+
+	return nil
+}
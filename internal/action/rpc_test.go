@@ -0,0 +1,37 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPC(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	stdout = buf
+	stdin = bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"get","params":{"name":"foo"}}` + "\n")
+	defer func() {
+		stdout = os.Stdout
+		stdin = os.Stdin
+	}()
+
+	require.NoError(t, act.RPC(gptest.CliCtxWithFlags(ctx, t, map[string]string{"poll": "0s"})))
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"result":{"password":"secret","body":"second\nthird"}}`+"\n", buf.String())
+}
@@ -0,0 +1,136 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/scan"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Scan looks for stored secrets in files, stdin or the staged changes of a
+// git repository.
+func (s *Action) Scan(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	staged := c.Bool("staged")
+	if !staged && !c.Args().Present() {
+		return ExitError(ExitUsage, nil, "Usage: %s scan [--staged] [--diff] [path ...]", s.Name)
+	}
+
+	ix, err := s.scanIndex(ctx)
+	if err != nil {
+		return err
+	}
+	if ix.Len() < 1 {
+		out.Warningf(ctx, "No secrets to look for")
+		return nil
+	}
+
+	var findings []scan.Finding
+	if staged {
+		f, err := scanStaged(ctx, ix)
+		if err != nil {
+			return ExitError(ExitGit, err, "failed to scan staged changes: %s", err)
+		}
+		findings = append(findings, f...)
+	}
+
+	for _, p := range c.Args().Slice() {
+		var f []scan.Finding
+		switch {
+		case p == "-" && c.Bool("diff"):
+			f, err = ix.Diff(stdin)
+		case p == "-":
+			f, err = ix.Reader(stdin, "<stdin>")
+		default:
+			f, err = scanPath(ix, p, c.Bool("diff"))
+		}
+		if err != nil {
+			return ExitError(ExitIO, err, "failed to scan %s: %s", p, err)
+		}
+		findings = append(findings, f...)
+	}
+
+	if len(findings) < 1 {
+		out.OKf(ctx, "No stored secrets found")
+		return nil
+	}
+
+	for _, f := range findings {
+		out.Errorf(ctx, "%s", f)
+	}
+	return ExitError(ExitAudit, nil, "Found stored secrets in %d places", len(findings))
+}
+
+// scanIndex decrypts all secrets and adds their password and the values of
+// their unsafe-keys to the index.
+func (s *Action) scanIndex(ctx context.Context) (*scan.Index, error) {
+	ix, err := scan.New()
+	if err != nil {
+		return nil, ExitError(ExitUnknown, err, "%s", err)
+	}
+
+	names, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return nil, ExitError(ExitList, err, "failed to list store: %s", err)
+	}
+
+	s.Store.TryUnlock(ctx)
+
+	var errs int
+	for _, name := range names {
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			out.Errorf(ctx, "failed to decrypt %s: %v", name, err)
+			errs++
+			continue
+		}
+
+		ix.Add(name, sec.Password())
+		for _, k := range sec.Keys() {
+			if !isUnsafeKey(k, sec) {
+				continue
+			}
+			vs, _ := sec.Values(k)
+			for _, v := range vs {
+				ix.Add(name, v)
+			}
+		}
+	}
+	if errs > 0 {
+		out.Warningf(ctx, "%d secrets failed to decrypt and are not looked for", errs)
+	}
+
+	return ix, nil
+}
+
+func scanPath(ix *scan.Index, p string, diff bool) ([]scan.Finding, error) {
+	if !diff {
+		return ix.Path(p)
+	}
+	buf, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	return ix.Diff(bytes.NewReader(buf))
+}
+
+// scanStaged scans the lines added by the staged changes of the git
+// repository in the current directory.
+func scanStaged(ctx context.Context, ix *scan.Index) ([]scan.Finding, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--cached", "--no-color", "--no-ext-diff", "-U0")
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	buf, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return ix.Diff(bytes.NewReader(buf))
+}
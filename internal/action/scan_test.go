@@ -0,0 +1,78 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestScan(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdin = os.Stdin
+	}()
+
+	require.NoError(t, act.insertStdin(ctx, "db/prod", []byte("hunter2-prod\nunsafe-keys: token\ntoken: tok-123456\nuser: admin"), false, nil))
+	buf.Reset()
+
+	td := t.TempDir()
+	clean := filepath.Join(td, "clean.env")
+	leaked := filepath.Join(td, "leaked.env")
+	require.NoError(t, os.WriteFile(clean, []byte("DB_USER=admin\n"), 0o644))
+	require.NoError(t, os.WriteFile(leaked, []byte("DB_USER=admin\nDB_TOKEN=tok-123456\n"), 0o644))
+
+	t.Run("no args", func(t *testing.T) {
+		assert.Error(t, act.Scan(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Scan(gptest.CliCtx(ctx, t, clean)))
+		assert.Contains(t, buf.String(), "No stored secrets found")
+	})
+
+	t.Run("leaked", func(t *testing.T) {
+		defer buf.Reset()
+		err := act.Scan(gptest.CliCtx(ctx, t, td))
+		require.Error(t, err)
+		var ec cli.ExitCoder
+		require.ErrorAs(t, err, &ec)
+		assert.Equal(t, ExitAudit, ec.ExitCode())
+		assert.Contains(t, buf.String(), leaked+":2 contains db/prod")
+		assert.NotContains(t, buf.String(), "tok-123456")
+	})
+
+	t.Run("diff on stdin", func(t *testing.T) {
+		defer buf.Reset()
+		stdin = strings.NewReader("+++ b/app.yml\n@@ -1 +1 @@\n-password: hunter2-prod\n+password: ${DB_PASS}\n")
+		require.NoError(t, act.Scan(gptest.CliCtxWithFlags(ctx, t, map[string]string{"diff": "true"}, "-")))
+
+		stdin = strings.NewReader("password: hunter2-prod\n")
+		assert.Error(t, act.Scan(gptest.CliCtx(ctx, t, "-")))
+		assert.Contains(t, buf.String(), "<stdin>:1 contains db/prod")
+	})
+}
@@ -0,0 +1,75 @@
+package action
+
+import (
+	"errors"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Seal records a signed manifest of the hashes of all entries of a store.
+func (s *Action) Seal(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	mp := sealMount(c.Args().First())
+
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil {
+		return ExitError(ExitMount, err, "Store %s not found: %s", watchName(mp), err)
+	}
+
+	if err := sub.Seal(ctx); err != nil {
+		return ExitError(ExitUnknown, err, "Failed to seal %s: %s", watchName(mp), err)
+	}
+
+	out.OKf(ctx, "Sealed %s. Run 'gopass verify-seal' to check for changes.", watchName(mp))
+	return nil
+}
+
+// VerifySeal checks the seal of a store and reports all entries that were
+// added, removed or modified since.
+func (s *Action) VerifySeal(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	mp := sealMount(c.Args().First())
+
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil {
+		return ExitError(ExitMount, err, "Store %s not found: %s", watchName(mp), err)
+	}
+
+	r, err := sub.VerifySeal(ctx)
+	if errors.Is(err, leaf.ErrNotSealed) {
+		return ExitError(ExitNotFound, err, "Store %s is not sealed. Run 'gopass seal' first.", watchName(mp))
+	}
+	if err != nil {
+		return ExitError(ExitFsck, err, "Failed to verify the seal of %s: %s", watchName(mp), err)
+	}
+
+	crypto := s.Store.Crypto(ctx, mp)
+	out.Noticef(ctx, "%s was sealed at %s by %s", watchName(mp), r.Time.Local().Format("2006-01-02 15:04:05"), crypto.FormatKey(ctx, r.Signer, ""))
+
+	if !r.Changed() {
+		out.OKf(ctx, "No changes since the store was sealed")
+		return nil
+	}
+
+	for _, fn := range r.Added {
+		out.Printf(ctx, "+ %s", fn)
+	}
+	for _, fn := range r.Removed {
+		out.Printf(ctx, "- %s", fn)
+	}
+	for _, fn := range r.Modified {
+		out.Printf(ctx, "~ %s", fn)
+	}
+
+	return ExitError(ExitFsck, nil, "%d entries changed since the store was sealed", len(r.Added)+len(r.Removed)+len(r.Modified))
+}
+
+func sealMount(name string) string {
+	if name == "root" {
+		return ""
+	}
+	return name
+}
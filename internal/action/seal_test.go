@@ -0,0 +1,69 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeal(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	t.Run("not sealed", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.VerifySeal(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("unknown store", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Seal(gptest.CliCtx(ctx, t, "nosuchmount")))
+		assert.Error(t, act.VerifySeal(gptest.CliCtx(ctx, t, "nosuchmount")))
+	})
+
+	t.Run("seal", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Seal(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "Sealed <root>")
+	})
+
+	t.Run("verify unchanged", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.VerifySeal(gptest.CliCtx(ctx, t, "root")))
+		assert.Contains(t, buf.String(), "No changes")
+	})
+
+	t.Run("verify modified", func(t *testing.T) {
+		defer buf.Reset()
+
+		sec := secrets.New()
+		sec.SetPassword("changed")
+		require.NoError(t, act.Store.Set(ctx, "foo", sec))
+		require.NoError(t, act.Store.Set(ctx, "added", sec))
+
+		assert.Error(t, act.VerifySeal(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "+ added.")
+		assert.Contains(t, buf.String(), "~ foo.")
+	})
+}
@@ -2,14 +2,21 @@ package action
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gopasspw/gopass/internal/cert"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/expiry"
 	"github.com/gopasspw/gopass/internal/notify"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/clipboard"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
@@ -43,6 +50,12 @@ func showParseArgs(c *cli.Context) context.Context {
 	if c.IsSet("noparsing") {
 		ctx = ctxutil.WithShowParsing(ctx, !c.Bool("noparsing"))
 	}
+	if c.IsSet("cert-info") {
+		ctx = WithCertInfo(ctx, c.Bool("cert-info"))
+	}
+	if c.IsSet("reason") {
+		ctx = leaf.WithBreakGlassReason(ctx, c.String("reason"))
+	}
 	ctx = WithClip(ctx, IsOnlyClip(ctx) || IsAlsoClip(ctx))
 	return ctx
 }
@@ -52,6 +65,7 @@ func (s *Action) Show(c *cli.Context) error {
 	name := c.Args().First()
 
 	ctx := showParseArgs(c)
+	ctx = s.showDefaultAction(ctx, c)
 
 	if key := c.Args().Get(1); key != "" {
 		debug.Log("Adding key to ctx: %s", key)
@@ -59,11 +73,43 @@ func (s *Action) Show(c *cli.Context) error {
 	}
 
 	if err := s.show(ctx, c, name, true); err != nil {
+		var ec cli.ExitCoder
+		if errors.As(err, &ec) {
+			return err
+		}
+		if errors.Is(err, store.ErrNotFound) {
+			return ExitError(ExitNotFound, err, "%s", err)
+		}
 		return ExitError(ExitDecrypt, err, "%s", err)
 	}
 	return nil
 }
 
+// showDefaultAction applies the configured showaction unless the user
+// explicitly asked for an output mode. It only applies to terminals so
+// scripts reading from gopass show keep working.
+func (s *Action) showDefaultAction(ctx context.Context, c *cli.Context) context.Context {
+	for _, flag := range []string{"clip", "alsoclip", "print", "password", "qr"} {
+		if c.IsSet(flag) {
+			return ctx
+		}
+	}
+	if !ctxutil.IsTerminal(ctx) {
+		return ctx
+	}
+
+	switch s.cfg.ShowAction {
+	case config.ShowActionClip:
+		ctx = WithOnlyClip(ctx, true)
+	case config.ShowActionBoth:
+		ctx = WithAlsoClip(ctx, true)
+	default:
+		return ctx
+	}
+	debug.Log("using configured show action %q", s.cfg.ShowAction)
+	return WithClip(ctx, true)
+}
+
 // show displays the given secret/key.
 func (s *Action) show(ctx context.Context, c *cli.Context, name string, recurse bool) error {
 	if name == "" {
@@ -86,7 +132,12 @@ func (s *Action) show(ctx context.Context, c *cli.Context, name string, recurse
 		return s.showHandleError(ctx, c, name, recurse, err)
 	}
 
-	return s.showHandleOutput(ctx, name, sec)
+	if err := s.showHandleOutput(ctx, name, sec); err != nil {
+		return err
+	}
+
+	s.recordUsage(ctx, name)
+	return nil
 }
 
 // showHandleRevision displays a single revision.
@@ -134,7 +185,13 @@ func (s *Action) parseRevision(ctx context.Context, name, revision string) (stri
 
 // showHandleOutput displays a secret.
 func (s *Action) showHandleOutput(ctx context.Context, name string, sec gopass.Secret) error {
+	if IsCertInfo(ctx) {
+		return showCertInfo(ctx, name, sec)
+	}
+
+	done := timing.Start(ctx, timing.Render)
 	pw, body, err := s.showGetContent(ctx, sec)
+	done()
 	if err != nil {
 		return err
 	}
@@ -146,6 +203,10 @@ func (s *Action) showHandleOutput(ctx context.Context, name string, sec gopass.S
 		return ExitError(ExitNotFound, store.ErrEmptySecret, store.ErrEmptySecret.Error())
 	}
 
+	if ctxutil.IsTerminal(ctx) && !IsPasswordOnly(ctx) {
+		showExpiry(ctx, sec)
+	}
+
 	if IsPrintQR(ctx) && pw != "" {
 		if err := s.showPrintQR(name, pw); err != nil {
 			return err
@@ -179,6 +240,40 @@ func (s *Action) showHandleOutput(ctx context.Context, name string, sec gopass.S
 	return nil
 }
 
+// showCertInfo displays a summary of all certificates in the secret.
+func showCertInfo(ctx context.Context, name string, sec gopass.Secret) error {
+	certs := cert.Parse(sec.Bytes())
+	if len(certs) < 1 {
+		return ExitError(ExitNotFound, nil, "no certificate found in %s", name)
+	}
+
+	now := time.Now()
+	for i, c := range certs {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprint(stdout, cert.Info(c, now))
+	}
+	return nil
+}
+
+// showExpiry prints the time left until the secret expires, if it has an
+// expiry date.
+func showExpiry(ctx context.Context, sec gopass.Secret) {
+	t, found := expiry.Get(sec)
+	if !found {
+		return
+	}
+
+	now := time.Now()
+	msg := fmt.Sprintf("This secret %s (%s)", expiry.Describe(t, now), expiry.Format(t))
+	if t.Sub(now) < expiry.Soon {
+		out.Warning(ctx, msg)
+		return
+	}
+	out.Notice(ctx, msg)
+}
+
 func (s *Action) showGetContent(ctx context.Context, sec gopass.Secret) (string, string, error) {
 	// YAML key.
 	if HasKey(ctx) && ctxutil.IsShowParsing(ctx) {
@@ -3,13 +3,25 @@ package action
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/gopasspw/gopass/tests/gptest"
@@ -175,7 +187,7 @@ func TestShowMulti(t *testing.T) {
 	t.Run("show keys with mixed case", func(t *testing.T) {
 		ctx := ctxutil.WithShowParsing(ctx, true)
 
-		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\nOther: meh\nuser: name\nbody text"), false))
+		assert.NoError(t, act.insertStdin(ctx, "baz", []byte("foobar\nOther: meh\nuser: name\nbody text"), false, nil))
 		buf.Reset()
 
 		c := gptest.CliCtx(ctx, t, "baz", "Other")
@@ -189,7 +201,7 @@ func TestShowMulti(t *testing.T) {
 
 		pw := "some-chars-are-odd-%s-%p-%q"
 
-		assert.NoError(t, act.insertStdin(ctx, "printf", []byte(pw), false))
+		assert.NoError(t, act.insertStdin(ctx, "printf", []byte(pw), false, nil))
 		buf.Reset()
 
 		c := gptest.CliCtx(ctx, t, "printf")
@@ -323,6 +335,54 @@ func TestShowAutoClip(t *testing.T) {
 	})
 }
 
+func TestShowDefaultAction(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	tctx := ctxutil.WithTerminal(ctx, true)
+
+	t.Run("print by default", func(t *testing.T) {
+		act.cfg.ShowAction = config.ShowActionPrint
+		sctx := act.showDefaultAction(tctx, gptest.CliCtx(tctx, t, "foo"))
+		assert.False(t, IsClip(sctx))
+	})
+
+	t.Run("clip", func(t *testing.T) {
+		act.cfg.ShowAction = config.ShowActionClip
+		sctx := act.showDefaultAction(tctx, gptest.CliCtx(tctx, t, "foo"))
+		assert.True(t, IsClip(sctx))
+		assert.True(t, IsOnlyClip(sctx))
+	})
+
+	t.Run("both", func(t *testing.T) {
+		act.cfg.ShowAction = config.ShowActionBoth
+		sctx := act.showDefaultAction(tctx, gptest.CliCtx(tctx, t, "foo"))
+		assert.True(t, IsClip(sctx))
+		assert.True(t, IsAlsoClip(sctx))
+		assert.False(t, IsOnlyClip(sctx))
+	})
+
+	t.Run("print overrides clip", func(t *testing.T) {
+		act.cfg.ShowAction = config.ShowActionClip
+		sctx := act.showDefaultAction(tctx, gptest.CliCtxWithFlags(tctx, t, map[string]string{"print": "true"}, "foo"))
+		assert.False(t, IsClip(sctx))
+	})
+
+	t.Run("no clip if not a terminal", func(t *testing.T) {
+		act.cfg.ShowAction = config.ShowActionClip
+		nctx := ctxutil.WithTerminal(ctx, false)
+		sctx := act.showDefaultAction(nctx, gptest.CliCtx(nctx, t, "foo"))
+		assert.False(t, IsClip(sctx))
+	})
+}
+
 func TestShowHandleRevision(t *testing.T) {
 	u := gptest.NewUnitTester(t)
 	defer u.Remove()
@@ -403,3 +463,118 @@ func TestShowPrintQR(t *testing.T) {
 	assert.NoError(t, act.showPrintQR("foo", "bar"))
 	buf.Reset()
 }
+
+func TestShowExpiry(t *testing.T) {
+	ctx := context.Background()
+
+	color.NoColor = true
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	sec := secrets.New()
+	showExpiry(ctx, sec)
+	assert.Equal(t, "", buf.String())
+
+	require.NoError(t, sec.Set("expires", "2000-01-01"))
+	showExpiry(ctx, sec)
+	assert.Contains(t, buf.String(), "This secret expired")
+	assert.Contains(t, buf.String(), "(2000-01-01)")
+	buf.Reset()
+
+	require.NoError(t, sec.Set("expires", time.Now().Add(100*24*time.Hour).Format("2006-01-02")))
+	showExpiry(ctx, sec)
+	assert.Contains(t, buf.String(), "This secret expires in")
+	buf.Reset()
+}
+
+func TestShowCertInfo(t *testing.T) {
+	ctx := context.Background()
+
+	buf := &bytes.Buffer{}
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+	}()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(10 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	assert.Error(t, showCertInfo(ctx, "foo", secrets.New()))
+
+	sec := secrets.NewKVWithData("", nil, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), false)
+	require.NoError(t, showCertInfo(ctx, "foo", sec))
+	assert.Contains(t, buf.String(), "CN=example.com")
+	assert.Contains(t, buf.String(), "expires in")
+}
+
+func TestShowBreakGlass(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	color.NoColor = true
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		stdout = os.Stdout
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	require.NoError(t, act.Store.Set(ctx, "prod/db", secrets.NewKVWithData("s3cret", nil, "", false)))
+	require.NoError(t, act.Store.Storage(ctx, "").Set(ctx, leaf.BreakGlassFile, []byte("# emergency access only\nprod\n")))
+
+	t.Run("no reason", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Show(gptest.CliCtx(ctx, t, "prod/db")))
+		assert.NotContains(t, buf.String(), "s3cret")
+	})
+
+	t.Run("other commands", func(t *testing.T) {
+		defer buf.Reset()
+		_, err := act.Store.Get(ctx, "prod/db")
+		assert.ErrorIs(t, err, store.ErrBreakGlassReason)
+		assert.Error(t, act.Edit(gptest.CliCtx(ctx, t, "prod/db")))
+	})
+
+	t.Run("with reason", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Show(gptest.CliCtxWithFlags(ctx, t, map[string]string{"reason": "INC-42"}, "prod/db")))
+		assert.Contains(t, buf.String(), "Access to prod/db has been recorded")
+		assert.Contains(t, buf.String(), "s3cret")
+
+		lst, err := filepath.Glob(filepath.Join(u.StoreDir(""), ".breakglass", "prod", "db", "*.sig"))
+		require.NoError(t, err)
+		assert.Len(t, lst, 1)
+	})
+
+	t.Run("other folder", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Show(gptest.CliCtx(ctx, t, "foo")))
+		assert.NotContains(t, buf.String(), "recorded")
+	})
+}
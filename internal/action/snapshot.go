@@ -0,0 +1,219 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/out"
+	si "github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// snapshotTimeFormat is used for the names of snapshots that were not named
+// by the user.
+const snapshotTimeFormat = "20060102-150405"
+
+// snapshotStores returns the mount points the snapshot commands work on.
+func (s *Action) snapshotStores(c *cli.Context) []string {
+	if c.IsSet("store") {
+		return []string{c.String("store")}
+	}
+	return append([]string{""}, s.Store.MountPoints()...)
+}
+
+// SnapshotCreate marks the current state of all stores, so a risky bulk
+// operation can be undone with SnapshotRollback.
+func (s *Action) SnapshotCreate(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	name := c.Args().First()
+	if name == "" {
+		name = time.Now().Format(snapshotTimeFormat)
+	}
+	msg := c.String("message")
+	if msg == "" {
+		msg = "Snapshot " + name
+	}
+
+	var created []string
+	for _, mp := range s.snapshotStores(c) {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil || sub == nil {
+			return ExitError(ExitMount, err, "failed to get store %q: %s", mp, err)
+		}
+		if err := sub.CreateSnapshot(ctx, name, msg); err != nil {
+			unsupported := errors.Is(err, backend.ErrNotSupported) || errors.Is(err, si.ErrGitNotInit)
+			if unsupported && !c.IsSet("store") {
+				out.Noticef(ctx, "Skipping %s: %s", watchName(mp), err)
+				continue
+			}
+			if unsupported {
+				return ExitError(ExitUnsupported, err, "Failed to create snapshot of %s: %s", watchName(mp), err)
+			}
+			return ExitError(ExitGit, err, "Failed to create snapshot of %s: %s", watchName(mp), err)
+		}
+		created = append(created, watchName(mp))
+	}
+
+	if len(created) < 1 {
+		return ExitError(ExitUnsupported, nil, "No store supports snapshots. Run 'gopass git init' first")
+	}
+	out.OKf(ctx, "Created snapshot %s of %s", name, strings.Join(created, ", "))
+	out.Printf(ctx, "Undo all changes made after now with 'gopass snapshot rollback %s'", name)
+
+	return nil
+}
+
+// snapshotInfo is a snapshot and the stores it was taken of.
+type snapshotInfo struct {
+	backend.Snapshot
+	stores []string
+}
+
+// snapshots returns the snapshots of the given stores, newest first.
+// Snapshots of several stores with the same name are merged.
+func (s *Action) snapshots(ctx context.Context, mps []string) ([]*snapshotInfo, error) {
+	byName := make(map[string]*snapshotInfo)
+	for _, mp := range mps {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil || sub == nil {
+			return nil, ExitError(ExitMount, err, "failed to get store %q: %s", mp, err)
+		}
+		snaps, err := sub.Snapshots(ctx)
+		if err != nil {
+			if errors.Is(err, backend.ErrNotSupported) || errors.Is(err, si.ErrGitNotInit) {
+				continue
+			}
+			return nil, ExitError(ExitGit, err, "Failed to list snapshots of %s: %s", watchName(mp), err)
+		}
+		for _, sn := range snaps {
+			info, found := byName[sn.Name]
+			if !found {
+				info = &snapshotInfo{Snapshot: sn}
+				byName[sn.Name] = info
+			}
+			if sn.Date.After(info.Date) {
+				info.Date = sn.Date
+			}
+			info.stores = append(info.stores, mp)
+		}
+	}
+
+	infos := make([]*snapshotInfo, 0, len(byName))
+	for _, info := range byName {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Date.Equal(infos[j].Date) {
+			return infos[i].Name > infos[j].Name
+		}
+		return infos[i].Date.After(infos[j].Date)
+	})
+
+	return infos, nil
+}
+
+// SnapshotList prints all snapshots, newest first.
+func (s *Action) SnapshotList(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	infos, err := s.snapshots(ctx, s.snapshotStores(c))
+	if err != nil {
+		return err
+	}
+	if len(infos) < 1 {
+		out.Printf(ctx, "No snapshots. Create one with 'gopass snapshot create'")
+		return nil
+	}
+
+	for _, info := range infos {
+		stores := make([]string, 0, len(info.stores))
+		for _, mp := range info.stores {
+			stores = append(stores, watchName(mp))
+		}
+		out.Printf(ctx, "%s - %s - %s (%s)", info.Name, info.Date.Format("2006-01-02 15:04:05"), info.Message, strings.Join(stores, ", "))
+	}
+
+	return nil
+}
+
+// SnapshotRollback restores all stores that have the snapshot to its state.
+// The current state is saved as another snapshot first, so the rollback can
+// be undone, too.
+func (s *Action) SnapshotRollback(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	name := c.Args().First()
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s snapshot rollback [--store STORE] <SNAPSHOT>", s.Name)
+	}
+
+	infos, err := s.snapshots(ctx, s.snapshotStores(c))
+	if err != nil {
+		return err
+	}
+	var snap *snapshotInfo
+	for _, info := range infos {
+		if info.Name == name {
+			snap = info
+			break
+		}
+	}
+	if snap == nil {
+		return ExitError(ExitNotFound, nil, "Snapshot %s not found. Run 'gopass snapshot list' to show all snapshots", name)
+	}
+
+	stores := make([]string, 0, len(snap.stores))
+	for _, mp := range snap.stores {
+		stores = append(stores, watchName(mp))
+	}
+	out.Warningf(ctx, "This reverts all changes to %s made after %s.", strings.Join(stores, ", "), snap.Date.Format("2006-01-02 15:04:05"))
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("Roll back to snapshot %s?", name)) {
+		return ExitError(ExitAborted, nil, "user aborted")
+	}
+
+	undo := uniqueSnapshotName(infos, "before-rollback-"+time.Now().Format(snapshotTimeFormat))
+	for _, mp := range snap.stores {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil || sub == nil {
+			return ExitError(ExitMount, err, "failed to get store %q: %s", mp, err)
+		}
+		if err := sub.CreateSnapshot(ctx, undo, fmt.Sprintf("Before rollback to snapshot %s", name)); err != nil {
+			return ExitError(ExitGit, err, "Failed to save the current state of %s: %s", watchName(mp), err)
+		}
+		if err := sub.Rollback(ctx, name); err != nil {
+			if errors.Is(err, si.ErrGitNothingToCommit) {
+				out.Noticef(ctx, "%s is unchanged since snapshot %s", watchName(mp), name)
+				continue
+			}
+			return ExitError(ExitGit, err, "Failed to roll back %s: %s", watchName(mp), err)
+		}
+		out.OKf(ctx, "Rolled back %s to snapshot %s", watchName(mp), name)
+	}
+
+	out.Printf(ctx, "Undo the rollback with 'gopass snapshot rollback %s'. Run 'gopass sync' to share it with your team", undo)
+
+	return nil
+}
+
+// uniqueSnapshotName appends a counter to name if a snapshot with that name
+// exists already.
+func uniqueSnapshotName(infos []*snapshotInfo, name string) string {
+	taken := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		taken[info.Name] = true
+	}
+
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	return unique
+}
@@ -0,0 +1,114 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestSnapshot(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	r1 := gptest.UnsetVars(termio.NameVars...)
+	r2 := gptest.UnsetVars(termio.EmailVars...)
+	defer r1()
+	defer r2()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+	ctx = backend.WithStorageBackend(ctx, backend.GitFS)
+
+	cfg := config.New()
+	cfg.Path = u.StoreDir("")
+	act, err := newAction(cfg, semver.Version{}, false)
+	require.NoError(t, err)
+	require.NoError(t, act.IsInitialized(gptest.CliCtx(ctx, t)))
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	t.Run("without git", func(t *testing.T) {
+		defer buf.Reset()
+		err := act.SnapshotCreate(gptest.CliCtx(ctx, t))
+		var ec cli.ExitCoder
+		require.ErrorAs(t, err, &ec)
+		assert.Equal(t, ExitUnsupported, ec.ExitCode())
+	})
+
+	require.NoError(t, act.Store.RCSInit(ctx, "", "foo bar", "foo.bar@example.org"))
+	require.NoError(t, act.Store.Set(ctx, "web/a", secrets.NewKVWithData("a1", nil, "", false)))
+	buf.Reset()
+
+	t.Run("create", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.SnapshotCreate(gptest.CliCtxWithFlags(ctx, t, map[string]string{"message": "before import"}, "before-import")))
+		assert.Contains(t, buf.String(), "Created snapshot before-import of <root>")
+		assert.Error(t, act.SnapshotCreate(gptest.CliCtx(ctx, t, "before-import")))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.SnapshotList(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "before-import - ")
+		assert.Contains(t, buf.String(), " - before import (<root>)")
+	})
+
+	require.NoError(t, act.Store.Set(ctx, "web/a", secrets.NewKVWithData("a2", nil, "", false)))
+	require.NoError(t, act.Store.Set(ctx, "web/b", secrets.NewKVWithData("b2", nil, "", false)))
+
+	t.Run("rollback", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.SnapshotRollback(gptest.CliCtx(ctx, t)))
+		assert.Error(t, act.SnapshotRollback(gptest.CliCtx(ctx, t, "unknown")))
+
+		require.NoError(t, act.SnapshotRollback(gptest.CliCtx(ctx, t, "before-import")))
+		assert.Contains(t, buf.String(), "Rolled back <root> to snapshot before-import")
+
+		sec, err := act.Store.Get(ctx, "web/a")
+		require.NoError(t, err)
+		assert.Equal(t, "a1", sec.Password())
+		assert.False(t, act.Store.Exists(ctx, "web/b"))
+	})
+
+	t.Run("undo rollback", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.SnapshotList(gptest.CliCtx(ctx, t)))
+		var undo string
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if strings.HasPrefix(line, "before-rollback-") {
+				undo = strings.Fields(line)[0]
+			}
+		}
+		require.NotEmpty(t, undo)
+
+		require.NoError(t, act.SnapshotRollback(gptest.CliCtx(ctx, t, undo)))
+		sec, err := act.Store.Get(ctx, "web/a")
+		require.NoError(t, err)
+		assert.Equal(t, "a2", sec.Password())
+		assert.True(t, act.Store.Exists(ctx, "web/b"))
+	})
+}
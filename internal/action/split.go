@@ -0,0 +1,205 @@
+package action
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/shamir"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+// sharePrefix marks encoded shares so they can be told apart from other input.
+const sharePrefix = "gopass-share"
+
+// Split divides a secret into shares using Shamir's Secret Sharing. Each share
+// is encrypted for a single recipient and a threshold of them is needed to
+// recover the secret.
+func (s *Action) Split(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	threshold := c.Int("threshold")
+
+	name := c.Args().First()
+	keys := c.Args().Tail()
+	if name == "" || len(keys) < 2 {
+		return ExitError(ExitUsage, nil, "Usage: %s split [--threshold N] <secret> <recipient> <recipient>...", s.Name)
+	}
+	if threshold < 2 || threshold > len(keys) {
+		return ExitError(ExitUsage, nil, "threshold must be between 2 and %d", len(keys))
+	}
+
+	crypto := s.Store.Crypto(ctx, name)
+	ids := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		kl, err := crypto.FindRecipients(ctx, key)
+		if err != nil || len(kl) != 1 {
+			return ExitError(ExitRecipients, err, "%s must match exactly one public key", key)
+		}
+		if seen[kl[0]] {
+			return ExitError(ExitUsage, nil, "%s was given more than once", kl[0])
+		}
+		seen[kl[0]] = true
+		ids = append(ids, kl[0])
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+	}
+
+	parts, err := shamir.Split(sec.Bytes(), len(ids), threshold)
+	if err != nil {
+		return ExitError(ExitUnknown, err, "failed to split %s: %s", name, err)
+	}
+	shares := make(map[string][]byte, len(ids))
+	for i, id := range ids {
+		shares[id] = []byte(encodeShare(threshold, parts[i]))
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Split into %d shares, %d needed", len(ids), threshold))
+	if err := s.Store.SetShares(ctx, name, shares); err != nil {
+		return ExitError(ExitEncrypt, err, "failed to write shares of %s: %s", name, err)
+	}
+	out.OKf(ctx, "Split %s into %d shares. %d of them are needed to combine it", name, len(ids), threshold)
+
+	if c.Bool("keep") {
+		return nil
+	}
+	if !termio.AskForConfirmation(ctx, fmt.Sprintf("Remove %s so it can only be recovered from its shares?", name)) {
+		out.Warningf(ctx, "%s is still readable by every recipient of the store", name)
+		return nil
+	}
+	if err := s.Store.Delete(ctx, name); err != nil {
+		return ExitError(ExitIO, err, "failed to remove %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// Combine recovers a split secret. It decrypts all shares the local user
+// holds and asks for the missing ones. With --export it only prints the local
+// shares so they can be handed to whoever combines them.
+func (s *Action) Combine(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	name := c.Args().First()
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s combine [--export] <secret>", s.Name)
+	}
+
+	ids, err := s.Store.Shares(ctx, name)
+	if err != nil {
+		return ExitError(ExitList, err, "failed to list shares of %s: %s", name, err)
+	}
+	if len(ids) < 1 {
+		return ExitError(ExitNotFound, nil, "%s has no shares", name)
+	}
+
+	crypto := s.Store.Crypto(ctx, name)
+	var local []string
+	for _, id := range ids {
+		if kl, err := crypto.FindIdentities(ctx, id); err != nil || len(kl) < 1 {
+			debug.Log("no private key for share %s", id)
+			continue
+		}
+		share, err := s.Store.GetShare(ctx, name, id)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt share of %s: %s", id, err)
+		}
+		local = append(local, string(share))
+	}
+
+	if c.Bool("export") {
+		if len(local) < 1 {
+			return ExitError(ExitNotFound, nil, "You don't hold a share of %s", name)
+		}
+		for _, share := range local {
+			fmt.Fprintln(stdout, share)
+		}
+		return nil
+	}
+
+	threshold := 0
+	parts := make([][]byte, 0, len(ids))
+	add := func(in string) error {
+		t, part, err := decodeShare(in)
+		if err != nil {
+			return err
+		}
+		if threshold > 0 && t != threshold {
+			return fmt.Errorf("share belongs to a different split")
+		}
+		for _, p := range parts {
+			if string(p) == string(part) {
+				return fmt.Errorf("share was already given")
+			}
+		}
+		threshold = t
+		parts = append(parts, part)
+		return nil
+	}
+
+	for _, share := range local {
+		if err := add(share); err != nil {
+			return ExitError(ExitDecrypt, err, "invalid share: %s", err)
+		}
+	}
+	if len(local) > 0 {
+		out.Noticef(ctx, "Decrypted %d local shares of %s", len(local), name)
+	}
+
+	for threshold == 0 || len(parts) < threshold {
+		need := "more"
+		if threshold > 0 {
+			need = strconv.Itoa(threshold - len(parts))
+		}
+		in, err := termio.AskForString(ctx, fmt.Sprintf("Enter a share of %s (%s needed)", name, need), "")
+		if err != nil {
+			return ExitError(ExitAborted, err, "failed to read share: %s", err)
+		}
+		if in == "" {
+			return ExitError(ExitAborted, nil, "not enough shares to combine %s", name)
+		}
+		if err := add(in); err != nil {
+			out.Errorf(ctx, "Invalid share: %s", err)
+		}
+	}
+
+	secret, err := shamir.Combine(parts)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to combine shares of %s: %s", name, err)
+	}
+
+	fmt.Fprint(stdout, string(secret))
+
+	return nil
+}
+
+func encodeShare(threshold int, share []byte) string {
+	return fmt.Sprintf("%s:%d:%s", sharePrefix, threshold, base64.StdEncoding.EncodeToString(share))
+}
+
+func decodeShare(in string) (int, []byte, error) {
+	p := strings.SplitN(strings.TrimSpace(in), ":", 3)
+	if len(p) != 3 || p[0] != sharePrefix {
+		return 0, nil, fmt.Errorf("not a share")
+	}
+
+	threshold, err := strconv.Atoi(p[1])
+	if err != nil || threshold < 2 {
+		return 0, nil, fmt.Errorf("invalid threshold %q", p[1])
+	}
+
+	share, err := base64.StdEncoding.DecodeString(p[2])
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to decode share: %w", err)
+	}
+
+	return threshold, share, nil
+}
@@ -0,0 +1,146 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/shamir"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCombine(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+	}()
+
+	require.NoError(t, act.Store.Set(ctx, "breakglass", secrets.NewKVWithData("s3cret", map[string][]string{"user": {"root"}}, "", false)))
+
+	t.Run("split without recipients", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Split(gptest.CliCtxWithFlags(ctx, t, map[string]string{"threshold": "2"}, "breakglass", "0xDEADBEEF")))
+	})
+
+	t.Run("split with invalid threshold", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Split(gptest.CliCtxWithFlags(ctx, t, map[string]string{"threshold": "3"}, "breakglass", "0xDEADBEEF", "0xFEEDBEEF")))
+	})
+
+	t.Run("split with unknown recipient", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Split(gptest.CliCtxWithFlags(ctx, t, map[string]string{"threshold": "2"}, "breakglass", "0xDEADBEEF", "0xCAFEBABE")))
+	})
+
+	t.Run("split", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Split(gptest.CliCtxWithFlags(ctx, t, map[string]string{"threshold": "2"}, "breakglass", "0xDEADBEEF", "0xFEEDBEEF")))
+		assert.Contains(t, buf.String(), "Split breakglass into 2 shares")
+		assert.False(t, act.Store.Exists(ctx, "breakglass"))
+
+		lst, err := act.Store.List(ctx, 0)
+		require.NoError(t, err)
+		assert.NotContains(t, strings.Join(lst, "\n"), "share")
+	})
+
+	var shares []string
+	t.Run("export", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Combine(gptest.CliCtxWithFlags(ctx, t, map[string]string{"export": "true"}, "breakglass")))
+		shares = strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, shares, 2)
+		for _, share := range shares {
+			assert.True(t, strings.HasPrefix(share, sharePrefix+":2:"), share)
+		}
+	})
+
+	t.Run("combine", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Combine(gptest.CliCtx(ctx, t, "breakglass")))
+		assert.Contains(t, buf.String(), "s3cret\nuser: root")
+	})
+
+	t.Run("combine unknown", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.Combine(gptest.CliCtx(ctx, t, "foo")))
+	})
+}
+
+func TestCombinePrompt(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, true)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	termio.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+		termio.Stderr = os.Stderr
+		termio.Stdin = os.Stdin
+	}()
+
+	// shares held by keyholders that have no private key on this machine
+	secret := []byte("s3cret")
+	require.NoError(t, act.Store.SetShares(ctx, "breakglass", map[string][]byte{
+		"0xCAFEBABE": []byte("unused"),
+	}))
+
+	raw, err := shamir.Split(secret, 3, 2)
+	require.NoError(t, err)
+	parts := make([]string, 0, len(raw))
+	for _, r := range raw {
+		parts = append(parts, encodeShare(2, r))
+	}
+	termio.Stdin = strings.NewReader("garbage\n" + parts[0] + "\n" + parts[0] + "\n" + parts[2] + "\n")
+	require.NoError(t, act.Combine(gptest.CliCtx(ctx, t, "breakglass")))
+	assert.Contains(t, buf.String(), "Invalid share")
+	assert.True(t, strings.HasSuffix(buf.String(), "s3cret"), buf.String())
+
+	buf.Reset()
+	termio.Stdin = strings.NewReader(parts[1] + "\n\n")
+	assert.Error(t, act.Combine(gptest.CliCtx(ctx, t, "breakglass")))
+}
+
+func TestDecodeShare(t *testing.T) {
+	for _, in := range []string{"", "foo", "gopass-share:1:AAAA", "gopass-share:x:AAAA", "gopass-share:2:!!!", "other:2:AAAA"} {
+		_, _, err := decodeShare(in)
+		assert.Error(t, err, in)
+	}
+
+	threshold, share, err := decodeShare(" " + encodeShare(3, []byte{1, 2, 3}) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, 3, threshold)
+	assert.Equal(t, []byte{1, 2, 3}, share)
+}
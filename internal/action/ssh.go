@@ -0,0 +1,215 @@
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets/secparse"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// SSHAskPassEnv is set when gopass invokes ssh-add. It contains the
+	// name of the secret holding the passphrase and tells gopass to act
+	// as SSH_ASKPASS program.
+	SSHAskPassEnv = "GOPASS_SSH_ASKPASS"
+
+	sshPassphraseKey = "passphrase"
+	sshHostKey       = "hostkey"
+	sshHostKeyHost   = "host"
+)
+
+// SSHAdd adds the private key stored in a secret to the running ssh-agent.
+// The key is passed to ssh-add on stdin and never written to disk. If the
+// secret has a passphrase key ssh-add will obtain the passphrase by invoking
+// gopass as its askpass program.
+func (s *Action) SSHAdd(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s ssh add <NAME>", s.Name)
+	}
+
+	if os.Getenv("SSH_AUTH_SOCK") == "" {
+		return ExitError(ExitUnsupported, nil, "SSH_AUTH_SOCK is not set. Is ssh-agent running?")
+	}
+
+	// read the secret unparsed so that the key is passed on verbatim
+	sec, err := s.Store.Get(ctxutil.WithShowParsing(ctx, false), name)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+	}
+
+	key := sshPrivateKey(sec.Bytes())
+	if key == nil {
+		return ExitError(ExitNotFound, nil, "no private key found in %s", name)
+	}
+
+	args := []string{}
+	if c.IsSet("lifetime") {
+		args = append(args, "-t", strconv.Itoa(c.Int("lifetime")))
+	}
+	if c.Bool("confirm") {
+		args = append(args, "-c")
+	}
+	args = append(args, "-")
+
+	cmd := exec.CommandContext(ctx, "ssh-add", args...)
+	cmd.Stdin = bytes.NewReader(key)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+
+	if psec, err := secparse.Parse(sec.Bytes()); err == nil {
+		if _, found := psec.Get(sshPassphraseKey); found {
+			env, err := sshAskPassEnv(name)
+			if err != nil {
+				return ExitError(ExitUnknown, err, "failed to determine gopass binary: %s", err)
+			}
+			cmd.Env = append(cmd.Env, env...)
+			out.Noticef(ctx, "Using passphrase from %s", name)
+		}
+	}
+
+	debug.Log("running ssh-add %+v", args)
+	if err := cmd.Run(); err != nil {
+		return ExitError(ExitUnknown, err, "ssh-add failed: %s", err)
+	}
+
+	return nil
+}
+
+// SSHAskPass prints the passphrase of the secret named in GOPASS_SSH_ASKPASS.
+// It's meant to be invoked by ssh-add, not by users.
+func (s *Action) SSHAskPass(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := os.Getenv(SSHAskPassEnv)
+	if name == "" {
+		return ExitError(ExitUsage, nil, "%s is not set", SSHAskPassEnv)
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+	}
+
+	pw, found := sec.Get(sshPassphraseKey)
+	if !found {
+		return ExitError(ExitNotFound, nil, "%s has no %s", name, sshPassphraseKey)
+	}
+
+	fmt.Fprintln(stdout, pw)
+	return nil
+}
+
+// SSHKnownHosts prints known_hosts lines for the host keys stored in a
+// secret or all secrets below a folder. It can be used as KnownHostsCommand.
+func (s *Action) SSHKnownHosts(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	name := c.Args().First()
+	if name == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s ssh known-hosts <NAME>", s.Name)
+	}
+
+	if !s.Store.Exists(ctx, name) && !s.Store.IsDir(ctx, name) {
+		return ExitError(ExitNotFound, nil, "Secret %s not found", name)
+	}
+
+	names := []string{name}
+	if s.Store.IsDir(ctx, name) {
+		l, err := s.Store.Tree(ctx)
+		if err != nil {
+			return ExitError(ExitList, err, "failed to list store: %s", err)
+		}
+
+		subtree, err := l.FindFolder(name)
+		if err != nil {
+			return ExitError(ExitNotFound, nil, "Entry %q not found", name)
+		}
+		names = subtree.List(tree.INF)
+	}
+
+	for _, name := range names {
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+
+		keys, found := sec.Values(sshHostKey)
+		if !found {
+			debug.Log("%s has no %s", name, sshHostKey)
+			continue
+		}
+
+		host, found := sec.Get(sshHostKeyHost)
+		if !found {
+			host = path.Base(name)
+		}
+		for _, key := range keys {
+			fmt.Fprintf(stdout, "%s %s\n", host, strings.TrimSpace(key))
+		}
+	}
+
+	return nil
+}
+
+// sshAskPassEnv returns the environment to make ssh-add invoke gopass to
+// obtain the passphrase from the named secret.
+func sshAskPassEnv(name string) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	env := []string{
+		"SSH_ASKPASS=" + exe,
+		"SSH_ASKPASS_REQUIRE=force",
+		SSHAskPassEnv + "=" + name,
+	}
+	// older versions of ssh-add only use askpass if DISPLAY is set
+	if os.Getenv("DISPLAY") == "" {
+		env = append(env, "DISPLAY=:0")
+	}
+
+	return env, nil
+}
+
+// sshPrivateKey returns the first PEM encoded private key in buf. It's
+// returned as is, i.e. including any headers of legacy encrypted keys.
+func sshPrivateKey(buf []byte) []byte {
+	start := bytes.Index(buf, []byte("-----BEGIN "))
+	for start >= 0 {
+		buf = buf[start:]
+		eol := bytes.IndexByte(buf, '\n')
+		if eol < 0 {
+			return nil
+		}
+		typ := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(string(buf[:eol])), "-----BEGIN "), "-----")
+		if strings.HasSuffix(typ, "PRIVATE KEY") {
+			footer := []byte("-----END " + typ + "-----")
+			end := bytes.Index(buf, footer)
+			if end < 0 {
+				return nil
+			}
+			key := append([]byte{}, buf[:end+len(footer)]...)
+			return append(key, '\n')
+		}
+
+		next := bytes.Index(buf[eol:], []byte("-----BEGIN "))
+		if next < 0 {
+			return nil
+		}
+		start = eol + next
+	}
+
+	return nil
+}
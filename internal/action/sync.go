@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/diff"
 	"github.com/gopasspw/gopass/internal/notify"
 	"github.com/gopasspw/gopass/internal/out"
@@ -20,7 +23,28 @@ import (
 
 // Sync all stores with their remotes.
 func (s *Action) Sync(c *cli.Context) error {
-	return s.sync(ctxutil.WithGlobalFlags(c), c.String("store"))
+	ctx := ctxutil.WithGlobalFlags(c)
+	if c.Bool("status") {
+		return s.syncStatus(ctx, c.String("store"))
+	}
+	return s.sync(ctx, c.String("store"))
+}
+
+// syncMountPoints returns the mount points selected by the store flag. The
+// root store is selected with "root".
+func (s *Action) syncMountPoints(store string) []string {
+	mps := append([]string{""}, s.Store.MountPoints()...)
+	if store == "" {
+		return mps
+	}
+
+	sel := make([]string, 0, 1)
+	for _, mp := range mps {
+		if (store == "root" && mp == "") || (store != "root" && mp == store) {
+			sel = append(sel, mp)
+		}
+	}
+	return sel
 }
 
 func (s *Action) sync(ctx context.Context, store string) error {
@@ -31,25 +55,17 @@ func (s *Action) sync(ctx context.Context, store string) error {
 		numEntries = len(l.List(tree.INF))
 	}
 	numMPs := 0
-
-	mps := s.Store.MountPoints()
-	mps = append([]string{""}, mps...)
+	var conflicts []string
 
 	// sync all stores (root and all mounted sub stores).
-	for _, mp := range mps {
-		if store != "" {
-			if store != "root" && mp != store {
-				continue
-			}
-			if store == "root" && mp != "" {
-				continue
-			}
-		}
-
+	for _, mp := range s.syncMountPoints(store) {
 		numMPs++
-		_ = s.syncMount(ctx, mp)
+		if err := s.syncMount(ctx, mp); isConflict(err) {
+			conflicts = append(conflicts, watchName(mp))
+		}
 	}
 	out.OKf(ctx, "All done")
+	s.postHook(ctx, config.HookPostSync, "")
 
 	// Calculate number of changed entries.
 	// This is a rough estimate as additions and deletions.
@@ -65,9 +81,16 @@ func (s *Action) sync(ctx context.Context, store string) error {
 	}
 	_ = notify.Notify(ctx, "gopass - sync", fmt.Sprintf("Finished. Synced %d remotes.%s", numMPs, diff))
 
+	if len(conflicts) > 0 {
+		return ExitError(ExitSyncConflict, nil, "Merge conflicts in %s. Resolve them with git and sync again", strings.Join(conflicts, ", "))
+	}
 	return nil
 }
 
+func isConflict(err error) bool {
+	return errors.Is(err, store.ErrGitConflict)
+}
+
 // syncMount syncs a single mount.
 func (s *Action) syncMount(ctx context.Context, mp string) error {
 	ctxno := out.WithNewline(ctx, false)
@@ -93,6 +116,15 @@ func (s *Action) syncMount(ctx context.Context, mp string) error {
 		out.Errorf(ctx, "Failed to list store: %s", err)
 	}
 
+	// keep other gopass processes from changing the store while syncing
+	ctx, unlock, err := sub.Acquire(ctx)
+	if err != nil {
+		out.Errorf(ctx, "Failed to lock store %q: %s", name, err)
+		return err
+	}
+	defer unlock()
+	ctxno = out.WithNewline(ctx, false)
+
 	out.Printf(ctxno, "\n   "+color.GreenString("git pull and push ... "))
 	err = sub.Storage().Push(ctx, "", "")
 	switch {
@@ -101,11 +133,16 @@ func (s *Action) syncMount(ctx context.Context, mp string) error {
 	case errors.Is(err, store.ErrGitNoRemote):
 		out.Printf(ctxno, "Skipped (no remote)")
 		debug.Log("Failed to push %q to its remote: %s", name, err)
-		return err
+		if len(sub.Mirrors()) < 1 {
+			return err
+		}
 	case errors.Is(err, backend.ErrNotSupported):
 		out.Printf(ctxno, "Skipped (not supported)")
 	case errors.Is(err, store.ErrGitNotInit):
 		out.Printf(ctxno, "Skipped (no Git repo)")
+	case errors.Is(err, store.ErrGitConflict):
+		out.Errorf(ctxno, "Merge conflicts in %q: %s", name, err)
+		return err
 	default: // any other error
 		out.Errorf(ctxno, "Failed to push %q to its remote: %s", name, err)
 		return err
@@ -123,10 +160,92 @@ func (s *Action) syncMount(ctx context.Context, mp string) error {
 			return err
 		}
 	}
+	if err := syncMirrors(ctxno, sub); err != nil {
+		return err
+	}
 	out.Printf(ctx, "\n   "+color.GreenString("done"))
 	return nil
 }
 
+// syncMirrors pushes to all mirrors of a store and reports the status of
+// each one.
+func syncMirrors(ctx context.Context, sub *leaf.Store) error {
+	var failed int
+	for _, r := range sub.PushMirrors(ctx) {
+		out.Printf(ctx, "\n   "+color.GreenString("git push to mirror %s ... ", r.Remote))
+		switch {
+		case r.Err == nil:
+			out.Printf(ctx, color.GreenString("OK"))
+		case errors.Is(r.Err, store.ErrGitNoRemote):
+			out.Printf(ctx, "Skipped (remote does not exist)")
+		case errors.Is(r.Err, backend.ErrNotSupported):
+			out.Printf(ctx, "Skipped (not supported)")
+		case errors.Is(r.Err, store.ErrGitNotInit):
+			out.Printf(ctx, "Skipped (no Git repo)")
+		default:
+			failed++
+			out.Printf(ctx, color.RedString("Failed: %s", r.Err))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to push to %d mirrors", failed)
+	}
+	return nil
+}
+
+// syncStatus prints how each store relates to its remote without changing
+// anything but the remote tracking branches.
+func (s *Action) syncStatus(ctx context.Context, store string) error {
+	for _, mp := range s.syncMountPoints(store) {
+		sub, err := s.Store.GetSubStore(mp)
+		if err != nil {
+			out.Errorf(ctx, "Failed to get sub store %q: %s", watchName(mp), err)
+			continue
+		}
+
+		st, err := sub.SyncStatus(ctx, true)
+		out.Printf(ctx, "%s %s", color.GreenString("[%s]", watchName(mp)), syncStatusString(st, err))
+	}
+	return nil
+}
+
+// syncStatusString summarizes the sync status of a store, e.g.
+// "origin/main: 2 ahead, 1 behind, synced 3 hours ago, dirty".
+func syncStatusString(st backend.SyncStatus, err error) string {
+	var parts []string
+	switch {
+	case err == nil:
+		switch {
+		case !st.Tracking:
+			parts = append(parts, "not pushed yet")
+		case st.Ahead == 0 && st.Behind == 0:
+			parts = append(parts, "up to date")
+		default:
+			parts = append(parts, fmt.Sprintf("%d ahead, %d behind", st.Ahead, st.Behind))
+		}
+		if st.LastSync.IsZero() {
+			parts = append(parts, "never synced")
+		} else {
+			parts = append(parts, "synced "+humanize.Time(st.LastSync))
+		}
+	case errors.Is(err, store.ErrGitNoRemote):
+		parts = append(parts, "no remote")
+	case errors.Is(err, store.ErrGitNotInit), errors.Is(err, backend.ErrNotSupported):
+		return "no Git repo"
+	default:
+		return "unknown: " + err.Error()
+	}
+	if st.Dirty {
+		parts = append(parts, "dirty")
+	}
+
+	res := strings.Join(parts, ", ")
+	if st.Remote != "" {
+		res = st.Remote + "/" + st.Branch + ": " + res
+	}
+	return res
+}
+
 func syncExportKeys(ctx context.Context, sub *leaf.Store, name string) error {
 	// import keys.
 	out.Printf(ctx, "\n   "+color.GreenString("importing missing keys ... "))
@@ -155,7 +274,7 @@ func syncExportKeys(ctx context.Context, sub *leaf.Store, name string) error {
 		return nil
 	}
 
-	if err := sub.Storage().Push(ctx, "", ""); err != nil {
+	if err := sub.Storage().Push(ctx, "", ""); err != nil && !errors.Is(err, store.ErrGitNoRemote) {
 		out.Errorf(ctx, "Failed to push %q to its remote: %s", name, err)
 		return err
 	}
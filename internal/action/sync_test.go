@@ -4,9 +4,16 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gopasspw/gopass/internal/backend"
+	git "github.com/gopasspw/gopass/internal/backend/storage/gitfs"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/tests/gptest"
 	"github.com/stretchr/testify/assert"
@@ -40,4 +47,110 @@ func TestSync(t *testing.T) {
 		defer buf.Reset()
 		assert.NoError(t, act.Sync(gptest.CliCtxWithFlags(ctx, t, map[string]string{"store": "root"})))
 	})
+
+	t.Run("sync --status", func(t *testing.T) {
+		defer buf.Reset()
+		assert.NoError(t, act.Sync(gptest.CliCtxWithFlags(ctx, t, map[string]string{"status": "true"})))
+		assert.Contains(t, buf.String(), "[<root>]")
+		assert.NotContains(t, buf.String(), "Syncing")
+	})
+}
+
+func TestSyncMirrors(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	ctx = ctxutil.WithExportKeys(ctx, false)
+
+	_, err := git.Init(ctx, u.StoreDir(""), "Nobody", "foo.bar@example.org")
+	require.NoError(t, err)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	mirror := filepath.Join(t.TempDir(), "mirror.git")
+	require.NoError(t, exec.Command("git", "init", "--bare", mirror).Run())
+
+	c := gptest.CliCtxWithFlags(ctx, t, map[string]string{"mirror": "true"}, "offsite", "file://"+mirror)
+	require.NoError(t, act.RCSAddRemote(c))
+	assert.Equal(t, "offsite", act.cfg.StoreConfig("").Mirrors)
+
+	// the config is read when the store is mounted.
+	sub, err := act.Store.GetSubStore("")
+	require.NoError(t, err)
+	sub.SetConfig(act.cfg.StoreConfig(""))
+
+	// the mirror is pushed to even though there is no origin.
+	buf.Reset()
+	require.NoError(t, act.syncMount(ctx, ""))
+	assert.Contains(t, buf.String(), "git push to mirror offsite ... OK")
+
+	cmd := exec.Command("git", "log", "--all", "--format=%s")
+	cmd.Dir = mirror
+	log, err := cmd.Output()
+	require.NoError(t, err)
+	assert.NotEmpty(t, strings.TrimSpace(string(log)))
+
+	require.NoError(t, act.RCSRemoveRemote(gptest.CliCtx(ctx, t, "offsite")))
+	assert.Equal(t, "", act.cfg.StoreConfig("").Mirrors)
+}
+
+func TestSyncStatusString(t *testing.T) {
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name string
+		st   backend.SyncStatus
+		err  error
+		want string
+	}{
+		{
+			name: "up to date",
+			st:   backend.SyncStatus{Remote: "origin", Branch: "main", Tracking: true, LastSync: now},
+			want: "origin/main: up to date, synced now",
+		},
+		{
+			name: "diverged",
+			st:   backend.SyncStatus{Remote: "origin", Branch: "main", Tracking: true, Ahead: 2, Behind: 1, Dirty: true},
+			want: "origin/main: 2 ahead, 1 behind, never synced, dirty",
+		},
+		{
+			name: "not pushed",
+			st:   backend.SyncStatus{Remote: "origin", Branch: "main", LastSync: now.Add(-3 * time.Hour)},
+			want: "origin/main: not pushed yet, synced 3 hours ago",
+		},
+		{
+			name: "no remote",
+			st:   backend.SyncStatus{Dirty: true},
+			err:  store.ErrGitNoRemote,
+			want: "no remote, dirty",
+		},
+		{
+			name: "no git",
+			err:  store.ErrGitNotInit,
+			want: "no Git repo",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, syncStatusString(tc.st, tc.err))
+		})
+	}
 }
@@ -0,0 +1,100 @@
+package action
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/urfave/cli/v2"
+)
+
+type keyTruster interface {
+	SignKey(ctx context.Context, id string) error
+	SetOwnerTrust(ctx context.Context, id, level string) error
+	SetTOFUPolicy(ctx context.Context, id, policy string) error
+}
+
+// Trust makes a public key valid in the trust model of a store after the
+// user confirmed its fingerprint. Depending on the trust model the key is
+// signed locally and/or its TOFU policy is set to good.
+func (s *Action) Trust(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+	id := c.Args().First()
+	if id == "" {
+		return ExitError(ExitUsage, nil, "Usage: %s trust [--store STORE] [--ownertrust LEVEL] <KEY>", s.Name)
+	}
+	level := c.String("ownertrust")
+	if _, found := gpg.OwnerTrustLevels[level]; level != "" && !found {
+		return ExitError(ExitUsage, nil, "unknown owner trust level %q. Must be one of unknown, never, marginal, full or ultimate", level)
+	}
+
+	store := c.String("store")
+	crypto := s.Store.Crypto(ctx, store)
+	if crypto == nil {
+		return ExitError(ExitNotFound, nil, "store %q not found", store)
+	}
+	kt, ok := crypto.(keyTruster)
+	if !ok {
+		return ExitError(ExitUnsupported, nil, "%s does not support trusting keys", crypto.Name())
+	}
+	ctx = s.cfg.StoreConfig(store).WithContext(ctx)
+	tm := gpg.GetTrustModel(ctx)
+
+	kl, err := crypto.FindRecipients(gpg.WithAlwaysTrust(ctx, true), id)
+	if err != nil {
+		return ExitError(ExitGPG, err, "failed to look up %s: %s", id, err)
+	}
+	switch len(kl) {
+	case 0:
+		return ExitError(ExitNotFound, nil, "no public key found for %s. Import it first", id)
+	case 1:
+	default:
+		return ExitError(ExitUsage, nil, "%s matches %d keys (%s). Please use the fingerprint", id, len(kl), strings.Join(kl, ", "))
+	}
+	fp := kl[0]
+
+	out.Printf(ctx, "%s", crypto.FormatKey(ctx, fp, ""))
+	out.Printf(ctx, "Fingerprint: %s", formatFingerprint(fp))
+	if !termio.AskForConfirmation(ctx, "Did you verify this fingerprint with the owner of the key?") {
+		return ExitError(ExitAborted, nil, "user aborted")
+	}
+
+	if gpg.UsesTOFU(tm) {
+		if err := kt.SetTOFUPolicy(ctx, fp, "good"); err != nil {
+			return ExitError(ExitGPG, err, "failed to set the TOFU policy of %s: %s", fp, err)
+		}
+		out.OKf(ctx, "Set the TOFU policy of %s to good", fp)
+	}
+	if tm != gpg.TrustTOFU && !c.Bool("no-sign") {
+		if err := kt.SignKey(ctx, fp); err != nil {
+			return ExitError(ExitGPG, err, "failed to sign %s: %s", fp, err)
+		}
+		out.OKf(ctx, "Signed %s locally", fp)
+	}
+	if level != "" {
+		if err := kt.SetOwnerTrust(ctx, fp, level); err != nil {
+			return ExitError(ExitGPG, err, "failed to set the owner trust of %s: %s", fp, err)
+		}
+		out.OKf(ctx, "Set the owner trust of %s to %s", fp, level)
+	}
+
+	if tm == gpg.TrustAlways {
+		out.Noticef(ctx, "The store uses the trust model always, so the trust is only used by other trust models. Run '%s config --store %q trustmodel pgp' to change it", s.Name, store)
+	}
+
+	return nil
+}
+
+// formatFingerprint splits the fingerprint into groups of four characters
+// like gpg does, so it's easier to compare.
+func formatFingerprint(fp string) string {
+	groups := make([]string, 0, len(fp)/4+1)
+	for len(fp) > 4 {
+		groups = append(groups, fp[:4])
+		fp = fp[4:]
+	}
+	return strings.Join(append(groups, fp), " ")
+}
@@ -0,0 +1,46 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrust(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	assert.Error(t, act.Trust(gptest.CliCtx(ctx, t)))
+	assert.Error(t, act.Trust(gptest.CliCtxWithFlags(ctx, t, map[string]string{"ownertrust": "some"}, "0xDEADBEEF")))
+	// the plain backend of the mock store has no trust
+	assert.Error(t, act.Trust(gptest.CliCtx(ctx, t, "0xDEADBEEF")))
+}
+
+func TestFormatFingerprint(t *testing.T) {
+	assert.Equal(t, "", formatFingerprint(""))
+	assert.Equal(t, "ABCD", formatFingerprint("ABCD"))
+	assert.Equal(t, "ABCD EF", formatFingerprint("ABCDEF"))
+	assert.Equal(t, "0123 4567 89AB CDEF 0123 4567 89AB CDEF 0123 4567", formatFingerprint("0123456789ABCDEF0123456789ABCDEF01234567"))
+}
@@ -0,0 +1,41 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/urfave/cli/v2"
+)
+
+// Unset removes a single key from an existing secret.
+func (s *Action) Unset(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	arg := c.Args().First()
+	name, key, found := s.splitNameKey(ctx, arg)
+	if c.Args().Len() > 1 || !strings.Contains(arg, ":") {
+		return ExitError(ExitUsage, nil, "Usage: %s unset name:key", s.Name)
+	}
+	if !found {
+		return ExitError(ExitNotFound, nil, "Secret %q not found", arg[:strings.LastIndex(arg, ":")])
+	}
+
+	sec, err := s.Store.Get(ctx, name)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to decrypt %q: %s", name, err)
+	}
+
+	if !sec.Del(key) {
+		return ExitError(ExitNotFound, nil, "key %q not found in %q", key, name)
+	}
+
+	if err := s.preHook(ctx, config.HookPreInsert, name, sec); err != nil {
+		return err
+	}
+	if err := s.Store.Set(ctxutil.WithCommitMessage(ctx, fmt.Sprintf("Removed key %s", key)), name, sec); err != nil {
+		return ExitError(ExitEncrypt, err, "failed to remove key %q from %q: %s", key, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnset(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	sec := secrets.New()
+	sec.SetPassword("secret")
+	require.NoError(t, sec.Set("user", "john"))
+	require.NoError(t, sec.Set("url", "example.org"))
+	require.NoError(t, act.Store.Set(ctx, "web", sec))
+
+	t.Run("no args", func(t *testing.T) {
+		assert.Error(t, act.Unset(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		assert.Error(t, act.Unset(gptest.CliCtx(ctx, t, "web")))
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		assert.Error(t, act.Unset(gptest.CliCtx(ctx, t, "nope:user")))
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		assert.Error(t, act.Unset(gptest.CliCtx(ctx, t, "web:nope")))
+	})
+
+	t.Run("remove key", func(t *testing.T) {
+		assert.NoError(t, act.Unset(gptest.CliCtx(ctx, t, "web:user")))
+
+		sec, err := act.Store.Get(ctx, "web")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", sec.Password())
+		_, found := sec.Get("user")
+		assert.False(t, found)
+		v, found := sec.Get("url")
+		assert.True(t, found)
+		assert.Equal(t, "example.org", v)
+	})
+}
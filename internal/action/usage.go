@@ -0,0 +1,107 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/internal/usage"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// defaultUnusedAge is the default of audit unused --older-than.
+const defaultUnusedAge = "1y"
+
+// recordUsage remembers that the secret was shown or copied, if the usage
+// log is enabled. Failing to do so never fails the command.
+func (s *Action) recordUsage(ctx context.Context, name string) {
+	if !s.cfg.UsageLog {
+		return
+	}
+
+	ids, err := s.usageRecipients(ctx)
+	if err == nil {
+		err = usage.Record(ctx, usage.Path(), s.Store.Crypto(ctx, ""), ids, name, time.Now())
+	}
+	if err != nil {
+		out.Warningf(ctx, "Failed to write usage log: %s", err)
+	}
+}
+
+// usageRecipients returns our identities that can read the root store. The
+// usage log is encrypted for them.
+func (s *Action) usageRecipients(ctx context.Context) ([]string, error) {
+	ids, err := s.Store.Crypto(ctx, "").FindIdentities(ctx, s.Store.ListRecipients(ctx, "")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find identities: %w", err)
+	}
+	if len(ids) < 1 {
+		return nil, fmt.Errorf("none of your identities can read the root store")
+	}
+	return ids, nil
+}
+
+// AuditUnused lists secrets that haven't been shown or copied for a while.
+func (s *Action) AuditUnused(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	olderThan := c.String("older-than")
+	if olderThan == "" {
+		olderThan = defaultUnusedAge
+	}
+	age, err := usage.ParseAge(olderThan)
+	if err != nil {
+		return ExitError(ExitUsage, err, "%s", err)
+	}
+	if !s.cfg.UsageLog {
+		out.Warning(ctx, "The usage log is disabled, so no secret has been used. Enable it with 'gopass config usagelog true'.")
+	}
+
+	names, err := s.Store.List(ctx, tree.INF)
+	if err != nil {
+		return ExitError(ExitList, err, "failed to list store: %s", err)
+	}
+	if filter := strings.TrimSuffix(c.Args().First(), "/"); filter != "" {
+		filtered := make([]string, 0, len(names))
+		for _, name := range names {
+			if strings.HasPrefix(name, filter+"/") {
+				filtered = append(filtered, name)
+			}
+		}
+		names = filtered
+	}
+
+	crypto := s.Store.Crypto(ctx, "")
+	l, err := usage.Read(ctx, usage.Path(), crypto)
+	if err != nil {
+		return ExitError(ExitDecrypt, err, "failed to read usage log: %s", err)
+	}
+	if len(l) > 0 {
+		// later reads only need to decrypt one entry.
+		if ids, err := s.usageRecipients(ctx); err == nil {
+			if err := usage.Compact(ctx, usage.Path(), crypto, ids, l); err != nil {
+				debug.Log("failed to compact usage log: %s", err)
+			}
+		}
+	}
+
+	unused := l.Unused(names, time.Now().Add(-age))
+	for _, name := range unused {
+		last := "never used"
+		if t, found := l[name]; found {
+			last = "last used " + t.Local().Format("2006-01-02")
+		}
+		out.Printf(ctx, "%s (%s)", name, last)
+	}
+
+	if len(unused) > 0 {
+		out.Noticef(ctx, "%d of %d secrets are unused. Consider rotating or removing them.", len(unused), len(names))
+	}
+
+	return nil
+}
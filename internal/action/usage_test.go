@@ -0,0 +1,77 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditUnused(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithInteractive(ctx, false)
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+		stdout = os.Stdout
+	}()
+
+	fn := filepath.Join(t.TempDir(), "usage.log")
+	t.Setenv("GOPASS_USAGE_LOG", fn)
+
+	require.NoError(t, act.insertStdin(ctx, "bar", []byte("secret"), false, nil))
+	buf.Reset()
+
+	t.Run("disabled", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Show(gptest.CliCtx(ctx, t, "foo")))
+		assert.NoFileExists(t, fn)
+
+		require.NoError(t, act.AuditUnused(gptest.CliCtx(ctx, t)))
+		assert.Contains(t, buf.String(), "disabled")
+		assert.Contains(t, buf.String(), "foo (never used)")
+	})
+
+	act.cfg.UsageLog = true
+
+	t.Run("show", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.Show(gptest.CliCtx(ctx, t, "foo")))
+		assert.FileExists(t, fn)
+
+		buf.Reset()
+		require.NoError(t, act.AuditUnused(gptest.CliCtx(ctx, t)))
+		assert.NotContains(t, buf.String(), "foo")
+		assert.Contains(t, buf.String(), "bar (never used)")
+	})
+
+	t.Run("older than", func(t *testing.T) {
+		defer buf.Reset()
+		require.NoError(t, act.AuditUnused(gptest.CliCtxWithFlags(ctx, t, map[string]string{"older-than": "0d"})))
+		assert.Contains(t, buf.String(), "foo (last used ")
+	})
+
+	t.Run("invalid age", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.AuditUnused(gptest.CliCtxWithFlags(ctx, t, map[string]string{"older-than": "soon"})))
+	})
+}
@@ -0,0 +1,149 @@
+package action
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/internal/vault"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+// VaultImport copies all secrets below a path of a HashiCorp Vault KV v2
+// mount into the store.
+func (s *Action) VaultImport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	vc, err := s.vaultClient(ctx, c, "import")
+	if err != nil {
+		return err
+	}
+
+	vpath := strings.Trim(c.String("path"), "/")
+	names, err := vc.List(ctx, vpath)
+	if err != nil {
+		return ExitError(ExitUnknown, err, "failed to list %s: %s", vpath, err)
+	}
+
+	ctx = ctxutil.WithCommitMessage(ctx, "Imported from Vault")
+	var n int
+	for _, name := range names {
+		dst := path.Join(c.String("prefix"), name)
+		if s.Store.Exists(ctx, dst) && !c.Bool("force") {
+			out.Warningf(ctx, "Not overwriting existing secret %s. Use --force to overwrite.", dst)
+			continue
+		}
+
+		data, err := vc.Read(ctx, path.Join(vpath, name))
+		if err != nil {
+			return ExitError(ExitUnknown, err, "failed to read %s: %s", name, err)
+		}
+		sec, err := vault.ToSecret(data)
+		if err != nil {
+			return ExitError(ExitUnknown, err, "failed to convert %s: %s", name, err)
+		}
+
+		debug.Log("importing %s to %s", name, dst)
+		if err := s.Store.Set(ctx, dst, sec); err != nil {
+			return ExitError(ExitEncrypt, err, "failed to write %s: %s", dst, err)
+		}
+		n++
+	}
+
+	out.OKf(ctx, "Imported %d secrets from Vault", n)
+	return nil
+}
+
+// VaultExport copies a secret or all secrets below a folder to a path of a
+// HashiCorp Vault KV v2 mount.
+func (s *Action) VaultExport(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	prefix := strings.TrimSuffix(c.String("prefix"), "/")
+	names := []string{prefix}
+	if prefix == "" || s.Store.IsDir(ctx, prefix) {
+		l, err := s.Store.Tree(ctx)
+		if err != nil {
+			return ExitError(ExitList, err, "failed to list store: %s", err)
+		}
+		if prefix != "" {
+			l, err = l.FindFolder(prefix)
+			if err != nil {
+				return ExitError(ExitNotFound, nil, "Entry %q not found", prefix)
+			}
+		}
+		names = l.List(tree.INF)
+	} else if !s.Store.Exists(ctx, prefix) {
+		return ExitError(ExitNotFound, nil, "Secret %s not found", prefix)
+	}
+
+	vc, err := s.vaultClient(ctx, c, "export")
+	if err != nil {
+		return err
+	}
+
+	vpath := strings.Trim(c.String("path"), "/")
+	for _, name := range names {
+		// the Vault name is relative to the prefix
+		rel := path.Base(name)
+		if name != prefix {
+			rel = strings.TrimPrefix(name, prefix+"/")
+		}
+
+		sec, err := s.Store.Get(ctx, name)
+		if err != nil {
+			return ExitError(ExitDecrypt, err, "failed to decrypt %s: %s", name, err)
+		}
+
+		dst := path.Join(vpath, rel)
+		debug.Log("exporting %s to %s", name, dst)
+		if err := vc.Write(ctx, dst, vault.FromSecret(sec)); err != nil {
+			return ExitError(ExitUnknown, err, "failed to write %s: %s", dst, err)
+		}
+	}
+
+	out.OKf(ctx, "Exported %d secrets to Vault", len(names))
+	return nil
+}
+
+func (s *Action) vaultClient(ctx context.Context, c *cli.Context, cmd string) (*vault.Client, error) {
+	mount := c.Args().First()
+	if mount == "" {
+		return nil, ExitError(ExitUsage, nil, "Usage: %s vault %s <MOUNT>", s.Name, cmd)
+	}
+
+	vc, err := vault.New(ctx, mount, vault.Config{
+		Addr:      c.String("addr"),
+		Namespace: c.String("namespace"),
+	})
+	if err != nil {
+		return nil, ExitError(ExitUnknown, err, "failed to connect to Vault: %s", err)
+	}
+
+	return vc, nil
+}
+
+func vaultFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "addr",
+			Usage: "Address of the Vault server (default: $VAULT_ADDR)",
+		},
+		&cli.StringFlag{
+			Name:  "namespace",
+			Usage: "Vault Enterprise namespace (default: $VAULT_NAMESPACE)",
+		},
+		&cli.StringFlag{
+			Name:  "path",
+			Usage: "Path inside the Vault mount",
+		},
+		&cli.StringFlag{
+			Name:  "prefix",
+			Usage: "Secret or folder in gopass",
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVault(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithTerminal(ctx, false)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	// a flat KV v2 mount "kv" without any folders
+	remote := map[string]map[string]any{
+		"app/db": {"password": "s3cret", "user": "admin"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/v1/kv/data/")
+		switch {
+		case r.Method == "LIST" && r.URL.Path == "/v1/kv/metadata/app":
+			_, _ = w.Write([]byte(`{"data":{"keys":["db"]}}`))
+		case r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": remote[name]}})
+		case r.Method == http.MethodPost:
+			var req struct {
+				Data map[string]any `json:"data"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			remote[name] = req.Data
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("VAULT_ADDR", srv.URL)
+	t.Setenv("VAULT_TOKEN", "s.test")
+
+	t.Run("import w/o mount", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.VaultImport(gptest.CliCtx(ctx, t)))
+	})
+
+	t.Run("import", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.VaultImport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"path": "app", "prefix": "imported"}, "kv")))
+		sec, err := act.Store.Get(ctx, "imported/db")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", sec.Password())
+		v, _ := sec.Get("user")
+		assert.Equal(t, "admin", v)
+	})
+
+	t.Run("import does not overwrite", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.Store.Set(ctx, "imported/db", secrets.NewKVWithData("local", nil, "", false)))
+		require.NoError(t, act.VaultImport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"path": "app", "prefix": "imported"}, "kv")))
+		assert.Contains(t, buf.String(), "Not overwriting")
+
+		sec, err := act.Store.Get(ctx, "imported/db")
+		require.NoError(t, err)
+		assert.Equal(t, "local", sec.Password())
+	})
+
+	t.Run("export", func(t *testing.T) {
+		defer buf.Reset()
+
+		require.NoError(t, act.VaultExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"path": "backup", "prefix": "imported"}, "kv")))
+		assert.Equal(t, map[string]any{"password": "local"}, remote["backup/db"])
+	})
+
+	t.Run("export not found", func(t *testing.T) {
+		defer buf.Reset()
+		assert.Error(t, act.VaultExport(gptest.CliCtxWithFlags(ctx, t, map[string]string{"prefix": "nope"}, "kv")))
+	})
+}
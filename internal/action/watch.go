@@ -0,0 +1,255 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/notify"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	watchDefaultInterval = 300
+	watchDefaultPoll     = 5
+)
+
+// fileStamp identifies a version of a file in the store.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// storeSnapshot maps the files of a store to their stamps.
+type storeSnapshot map[string]fileStamp
+
+// Watch keeps all stores with a remote in sync until it is interrupted.
+// Local changes are pushed as soon as they are detected and the remotes are
+// checked for incoming changes periodically.
+func (s *Action) Watch(c *cli.Context) error {
+	ctx := ctxutil.WithGlobalFlags(c)
+
+	interval := watchDefaultInterval
+	if c.IsSet("interval") {
+		interval = c.Int("interval")
+	}
+	poll := watchDefaultPoll
+	if c.IsSet("poll") {
+		poll = c.Int("poll")
+	}
+	if interval < 1 || poll < 1 {
+		return ExitError(ExitUsage, nil, "interval and poll must be positive")
+	}
+
+	var status *watchStatus
+	if c.String("status-addr") != "" {
+		status = newWatchStatus()
+	}
+
+	snaps := make(map[string]storeSnapshot, 1)
+	for _, mp := range s.watchMounts(c.String("store")) {
+		if err := s.watchSync(ctx, mp); err != nil {
+			debug.Log("not watching %q: %s", mp, err)
+			continue
+		}
+		status.record(mp, nil, 0)
+		snap, err := s.watchSnapshot(mp)
+		if err != nil {
+			out.Errorf(ctx, "Failed to read store %q: %s", watchName(mp), err)
+			continue
+		}
+		snaps[mp] = snap
+		out.Printf(ctx, "👀 Watching %s", watchName(mp))
+	}
+	if len(snaps) < 1 {
+		return ExitError(ExitGit, nil, "No store with a remote to watch")
+	}
+	if status != nil {
+		if err := s.serveWatchStatus(ctx, c.String("status-addr"), status); err != nil {
+			return ExitError(ExitUsage, err, "Failed to serve status: %s", err)
+		}
+	}
+
+	pollTicker := time.NewTicker(time.Duration(poll) * time.Second)
+	defer pollTicker.Stop()
+	fetchTicker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer fetchTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			out.Noticef(ctx, "Stopped watching")
+			return nil
+		case <-pollTicker.C:
+			for mp, snap := range snaps {
+				cur, err := s.watchSnapshot(mp)
+				if err != nil {
+					debug.Log("failed to read store %q: %s", mp, err)
+					continue
+				}
+				if len(watchChanges(snap, cur)) < 1 {
+					continue
+				}
+				debug.Log("local changes in %q", mp)
+				snaps[mp] = s.watchUpdate(ctx, mp, cur, status)
+			}
+		case <-fetchTicker.C:
+			for mp, snap := range snaps {
+				snaps[mp] = s.watchUpdate(ctx, mp, snap, status)
+			}
+		}
+	}
+}
+
+// watchUpdate syncs a single store and returns the new snapshot. Any change
+// compared to the given snapshot after the sync was made by someone else.
+// The result is recorded in status.
+func (s *Action) watchUpdate(ctx context.Context, mp string, snap storeSnapshot, status *watchStatus) storeSnapshot {
+	if err := s.watchSync(ctx, mp); err != nil {
+		out.Errorf(ctx, "Failed to sync %s: %s", watchName(mp), err)
+		status.record(mp, err, 0)
+		return snap
+	}
+
+	cur, err := s.watchSnapshot(mp)
+	if err != nil {
+		debug.Log("failed to read store %q: %s", mp, err)
+		status.record(mp, err, 0)
+		return snap
+	}
+
+	changed := watchChanges(snap, cur)
+	status.record(mp, nil, len(changed))
+	if len(changed) > 0 {
+		msg := fmt.Sprintf("%d incoming changes in %s: %s", len(changed), watchName(mp), strings.Join(changed, ", "))
+		out.Printf(ctx, "%s %s", time.Now().Format("15:04:05"), msg)
+		_ = notify.Notify(ctx, "gopass - watch", msg)
+	}
+
+	return cur
+}
+
+// watchSync pulls and pushes a single store. Stores without a remote return
+// an error.
+func (s *Action) watchSync(ctx context.Context, mp string) error {
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil {
+		return err
+	}
+
+	ctx, unlock, err := sub.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = sub.Storage().Push(ctx, "", "")
+	switch {
+	case err == nil:
+		for _, r := range sub.PushMirrors(ctx) {
+			if r.Err != nil {
+				out.Warningf(ctx, "Failed to push %s to mirror %s: %s", watchName(mp), r.Remote, r.Err)
+			}
+		}
+		return nil
+	case errors.Is(err, store.ErrGitNoRemote), errors.Is(err, store.ErrGitNotInit), errors.Is(err, backend.ErrNotSupported):
+		return fmt.Errorf("nothing to sync: %w", err)
+	default:
+		return err
+	}
+}
+
+// watchSnapshot records the stamps of all secrets and recipients files of a
+// store.
+func (s *Action) watchSnapshot(mp string) (storeSnapshot, error) {
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshotDir(sub.Storage().Path())
+}
+
+// snapshotDir records the stamps of all files below dir, ignoring the git
+// metadata.
+func snapshotDir(dir string) (storeSnapshot, error) {
+	snap := make(storeSnapshot, 64)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		snap[filepath.ToSlash(rel)] = fileStamp{
+			size:    fi.Size(),
+			modTime: fi.ModTime(),
+		}
+		return nil
+	})
+	return snap, err
+}
+
+// watchChanges returns the sorted names of all files that were added, removed
+// or modified between the two snapshots.
+func watchChanges(before, after storeSnapshot) []string {
+	var changed []string
+	for name, st := range after {
+		if bst, found := before[name]; !found || bst != st {
+			changed = append(changed, name)
+		}
+	}
+	for name := range before {
+		if _, found := after[name]; !found {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// watchMounts returns the mount points to watch. Like sync it accepts a
+// single mount point or root.
+func (s *Action) watchMounts(name string) []string {
+	mps := append([]string{""}, s.Store.MountPoints()...)
+	if name == "" {
+		return mps
+	}
+	if name == "root" {
+		return []string{""}
+	}
+	for _, mp := range mps {
+		if mp == name {
+			return []string{mp}
+		}
+	}
+	return nil
+}
+
+func watchName(mp string) string {
+	if mp == "" {
+		return "<root>"
+	}
+	return mp
+}
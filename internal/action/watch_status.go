@@ -0,0 +1,267 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
+	"github.com/gopasspw/gopass/internal/cache"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// watchStatus records the result of the syncs done by watch. It's safe for
+// concurrent use and a nil *watchStatus records nothing.
+type watchStatus struct {
+	mu      sync.Mutex
+	started time.Time
+	mounts  map[string]watchMountStatus
+}
+
+// watchMountStatus is the result of the last sync of a store.
+type watchMountStatus struct {
+	LastSync  time.Time
+	LastError string
+	Incoming  int
+}
+
+func newWatchStatus() *watchStatus {
+	return &watchStatus{
+		started: time.Now(),
+		mounts:  map[string]watchMountStatus{},
+	}
+}
+
+func (w *watchStatus) record(mp string, err error, incoming int) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ms := w.mounts[mp]
+	ms.LastSync = time.Now()
+	ms.LastError = ""
+	if err != nil {
+		ms.LastError = err.Error()
+	}
+	ms.Incoming += incoming
+	w.mounts[mp] = ms
+}
+
+func (w *watchStatus) get(mp string) (watchMountStatus, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ms, found := w.mounts[mp]
+	return ms, found
+}
+
+// statusReport is served by the status endpoint of watch. It must never
+// contain any secret or secret name.
+type statusReport struct {
+	Version string        `json:"version"`
+	Started time.Time     `json:"started"`
+	Mounts  []mountStatus `json:"mounts"`
+	Caches  []cache.Stat  `json:"caches"`
+	Agent   *agentStatus  `json:"agent,omitempty"`
+}
+
+type mountStatus struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Crypto  string `json:"crypto"`
+	Storage string `json:"storage"`
+	Secrets int    `json:"secrets"`
+	// Unlocked is set if the passphrases of all keys of the store are
+	// cached. It's missing if the crypto backend can't tell.
+	Unlocked *bool `json:"unlocked,omitempty"`
+
+	Watched   bool       `json:"watched"`
+	LastSync  *time.Time `json:"last_sync,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	Incoming  int        `json:"incoming_changes"`
+
+	Remote    string     `json:"remote,omitempty"`
+	Branch    string     `json:"branch,omitempty"`
+	Tracking  bool       `json:"tracking"`
+	Ahead     int        `json:"ahead"`
+	Behind    int        `json:"behind"`
+	Dirty     bool       `json:"dirty"`
+	LastFetch *time.Time `json:"last_fetch,omitempty"`
+}
+
+type agentStatus struct {
+	Running bool   `json:"running"`
+	Error   string `json:"error,omitempty"`
+}
+
+// checkStatusAddr makes sure the status endpoint is only reachable from the
+// local machine.
+func checkStatusAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if !isLoopback(host) {
+		return fmt.Errorf("%q is not a loopback address", host)
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveWatchStatus serves the status report on addr until ctx is canceled.
+func (s *Action) serveWatchStatus(ctx context.Context, addr string, st *watchStatus) error {
+	if err := checkStatusAddr(addr); err != nil {
+		return err
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           s.watchStatusHandler(st),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			out.Errorf(ctx, "Status endpoint failed: %s", err)
+		}
+	}()
+
+	out.Printf(ctx, "📊 Serving status on http://%s/status", l.Addr())
+	return nil
+}
+
+func (s *Action) watchStatusHandler(st *watchStatus) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		// reject requests for other host names, e.g. by DNS rebinding.
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if !isLoopback(host) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(s.statusReport(r.Context(), st)); err != nil {
+			debug.Log("failed to write status: %s", err)
+		}
+	})
+	return mux
+}
+
+// statusReport collects the state of all stores.
+func (s *Action) statusReport(ctx context.Context, st *watchStatus) statusReport {
+	rep := statusReport{
+		Version: s.version.String(),
+		Started: st.started,
+	}
+
+	usesGPG := false
+	mps := append([]string{""}, s.Store.MountPoints()...)
+	sort.Strings(mps)
+	for _, mp := range mps {
+		ms := s.watchMountReport(ctx, mp, st)
+		if ms.Crypto == "gpg" {
+			usesGPG = true
+		}
+		rep.Mounts = append(rep.Mounts, ms)
+	}
+
+	caches, err := cache.Stats()
+	if err != nil {
+		debug.Log("failed to read cache stats: %s", err)
+	}
+	rep.Caches = caches
+
+	if usesGPG {
+		rep.Agent = &agentStatus{Running: true}
+		if err := gpgconf.PingAgent(ctx); err != nil {
+			rep.Agent = &agentStatus{Error: err.Error()}
+		}
+	}
+
+	return rep
+}
+
+func (s *Action) watchMountReport(ctx context.Context, mp string, st *watchStatus) mountStatus {
+	ms := mountStatus{Name: watchName(mp)}
+
+	sub, err := s.Store.GetSubStore(mp)
+	if err != nil || sub == nil {
+		ms.LastError = fmt.Sprintf("failed to open store: %s", err)
+		return ms
+	}
+	ms.Path = sub.Path()
+	ms.Storage = sub.Storage().Name()
+	if crypto := sub.Crypto(); crypto != nil {
+		ms.Crypto = crypto.Name()
+		if pu, ok := crypto.(backend.PassphraseUnlocker); ok {
+			unlocked := false
+			if ids, err := sub.Identities(ctx); err == nil && len(ids) > 0 {
+				unlocked = true
+				for _, id := range ids {
+					unlocked = unlocked && pu.IsUnlocked(ctx, id)
+				}
+			}
+			ms.Unlocked = &unlocked
+		}
+	}
+	if l, err := sub.List(ctx, ""); err == nil {
+		ms.Secrets = len(l)
+	}
+
+	if ws, found := st.get(mp); found {
+		ms.Watched = true
+		ms.LastSync = &ws.LastSync
+		ms.LastError = ws.LastError
+		ms.Incoming = ws.Incoming
+	}
+
+	ss, err := sub.SyncStatus(ctx, false)
+	if err != nil {
+		debug.Log("no sync status for %q: %s", mp, err)
+		return ms
+	}
+	ms.Remote = ss.Remote
+	ms.Branch = ss.Branch
+	ms.Tracking = ss.Tracking
+	ms.Ahead = ss.Ahead
+	ms.Behind = ss.Behind
+	ms.Dirty = ss.Dirty
+	if !ss.LastSync.IsZero() {
+		ms.LastFetch = &ss.LastSync
+	}
+
+	return ms
+}
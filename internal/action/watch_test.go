@@ -0,0 +1,137 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	color.NoColor = true
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	// the mock store has no remote
+	assert.Error(t, act.Watch(gptest.CliCtx(ctx, t)))
+	assert.Error(t, act.Watch(gptest.CliCtxWithFlags(ctx, t, map[string]string{"interval": "0"})))
+
+	assert.Equal(t, []string{""}, act.watchMounts(""))
+	assert.Equal(t, []string{""}, act.watchMounts("root"))
+	assert.Nil(t, act.watchMounts("nope"))
+}
+
+func TestWatchChanges(t *testing.T) {
+	td := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(td, ".git"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(td, ".git", "HEAD"), []byte("ref"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(td, "foo"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "foo", "bar.gpg"), []byte("bar"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "baz.gpg"), []byte("baz"), 0o600))
+
+	before, err := snapshotDir(td)
+	require.NoError(t, err)
+	assert.Len(t, before, 2)
+	assert.Empty(t, watchChanges(before, before))
+
+	// modify, add and remove a secret
+	mtime := time.Now().Add(time.Minute)
+	require.NoError(t, os.WriteFile(filepath.Join(td, "foo", "bar.gpg"), []byte("bar2"), 0o600))
+	require.NoError(t, os.Chtimes(filepath.Join(td, "foo", "bar.gpg"), mtime, mtime))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "new.gpg"), []byte("new"), 0o600))
+	require.NoError(t, os.Remove(filepath.Join(td, "baz.gpg")))
+	require.NoError(t, os.WriteFile(filepath.Join(td, ".git", "HEAD"), []byte("other ref"), 0o600))
+
+	after, err := snapshotDir(td)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"baz.gpg", "foo/bar.gpg", "new.gpg"}, watchChanges(before, after))
+}
+
+func TestWatchStatus(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+
+	act, err := newMock(ctx, u)
+	require.NoError(t, err)
+	require.NotNil(t, act)
+
+	assert.NoError(t, checkStatusAddr("127.0.0.1:7777"))
+	assert.NoError(t, checkStatusAddr("[::1]:7777"))
+	assert.NoError(t, checkStatusAddr("localhost:7777"))
+	assert.Error(t, checkStatusAddr("0.0.0.0:7777"))
+	assert.Error(t, checkStatusAddr(":7777"))
+	assert.Error(t, checkStatusAddr("127.0.0.1"))
+
+	var nilStatus *watchStatus
+	nilStatus.record("", nil, 1)
+
+	st := newWatchStatus()
+	st.record("", nil, 2)
+	st.record("", errors.New("boom"), 1)
+
+	h := act.watchStatusHandler(st)
+
+	t.Run("report", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://127.0.0.1:7777/status", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var rep statusReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rep))
+		require.Len(t, rep.Mounts, 1)
+		ms := rep.Mounts[0]
+		assert.Equal(t, "<root>", ms.Name)
+		assert.Equal(t, u.StoreDir(""), ms.Path)
+		assert.Equal(t, len(u.Entries), ms.Secrets)
+		assert.True(t, ms.Watched)
+		assert.Equal(t, "boom", ms.LastError)
+		assert.Equal(t, 3, ms.Incoming)
+
+		// no secret names
+		for _, e := range u.Entries {
+			assert.NotContains(t, rec.Body.String(), e)
+		}
+	})
+
+	t.Run("other host", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://evil.example.com:7777/status", nil))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("post", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://localhost:7777/status", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/cert"
+	"github.com/gopasspw/gopass/internal/expiry"
 	"github.com/gopasspw/gopass/internal/notify"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
@@ -86,6 +88,28 @@ func Batch(ctx context.Context, secrets []string, secStore secretGetter, expirat
 			}
 			return nil
 		},
+		func(_ string, sec gopass.Secret) error {
+			t, found := expiry.Get(sec)
+			if !found {
+				return nil
+			}
+			if d := time.Until(t); d <= 0 {
+				return fmt.Errorf("secret expired")
+			} else if d < expiry.Soon {
+				return fmt.Errorf("secret expires within %d days", int(expiry.Soon.Hours()/24))
+			}
+			return nil
+		},
+		func(_ string, sec gopass.Secret) error {
+			for _, c := range cert.Parse(sec.Bytes()) {
+				if d := time.Until(c.NotAfter); d <= 0 {
+					return fmt.Errorf("certificate expired")
+				} else if d < expiry.Soon {
+					return fmt.Errorf("certificate expires within %d days", int(expiry.Soon.Hours()/24))
+				}
+			}
+			return nil
+		},
 	}
 	// if expiration is not zero only check for expired secrets
 	if expiration > 0 {
@@ -0,0 +1,161 @@
+// Package auditlog implements an append-only, hash-chained log of gopass
+// operations. Every entry contains the hash of its predecessor so removing or
+// changing entries breaks the chain. It never contains any secret content.
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/flock"
+	"github.com/gopasspw/gopass/pkg/appdir"
+)
+
+// Entry is a single logged operation.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Name    string    `json:"name,omitempty"`
+	Failed  bool      `json:"failed,omitempty"`
+	Prev    string    `json:"prev"`
+	Hash    string    `json:"hash"`
+}
+
+// sum returns the hash of the entry, chained to the previous one.
+func (e Entry) sum() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n%t\n", e.Prev, e.Time.UTC().Format(time.RFC3339Nano), e.Command, e.Name, e.Failed)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Path returns the location of the log.
+func Path() string {
+	if p := os.Getenv("GOPASS_AUDIT_LOG"); p != "" {
+		return p
+	}
+	return filepath.Join(appdir.UserData(), "audit.log")
+}
+
+// lockTimeout is the maximum time to wait for another gopass process to
+// finish appending to the log.
+var lockTimeout = 10 * time.Second
+
+// Append adds an entry for the given command and secret to the log at path.
+// Concurrent gopass processes are serialized by a lock file next to the log,
+// otherwise they could chain their entries to the same predecessor.
+func Append(path, command, name string, failed bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create log dir: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	l, err := flock.Acquire(ctx, path+".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock log: %w", err)
+	}
+	defer l.Release() //nolint:errcheck
+
+	fh, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer fh.Close() //nolint:errcheck
+
+	prev, err := lastHash(fh)
+	if err != nil {
+		return err
+	}
+
+	e := Entry{
+		Time:    time.Now().UTC(),
+		Command: command,
+		Name:    name,
+		Failed:  failed,
+		Prev:    prev,
+	}
+	e.Hash = e.sum()
+
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+	if _, err := fh.Write(append(buf, '\n')); err != nil {
+		return fmt.Errorf("failed to write log: %w", err)
+	}
+
+	return nil
+}
+
+// lastHash returns the hash of the last entry or an empty string.
+func lastHash(r io.Reader) (string, error) {
+	var last []byte
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if line := bytes.TrimSpace(s.Bytes()); len(line) > 0 {
+			last = append(last[:0], line...)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return "", fmt.Errorf("failed to read log: %w", err)
+	}
+	if last == nil {
+		return "", nil
+	}
+
+	var e Entry
+	if err := json.Unmarshal(last, &e); err != nil {
+		return "", fmt.Errorf("failed to decode last entry: %w", err)
+	}
+	return e.Hash, nil
+}
+
+// ErrTampered is returned by Verify if the chain is broken.
+var ErrTampered = errors.New("audit log has been tampered with")
+
+// Read decodes all entries of the log.
+func Read(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) < 1 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return entries, fmt.Errorf("line %d: failed to decode entry: %w", n, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := s.Err(); err != nil {
+		return entries, fmt.Errorf("failed to read log: %w", err)
+	}
+	return entries, nil
+}
+
+// Verify checks that all entries are intact and properly chained. It returns
+// the number of valid entries before the first broken one.
+func Verify(entries []Entry) (int, error) {
+	prev := ""
+	for i, e := range entries {
+		if e.Prev != prev {
+			return i, fmt.Errorf("entry %d doesn't follow entry %d: %w", i+1, i, ErrTampered)
+		}
+		if e.sum() != e.Hash {
+			return i, fmt.Errorf("entry %d has been modified: %w", i+1, ErrTampered)
+		}
+		prev = e.Hash
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,101 @@
+package auditlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendVerify(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "sub", "audit.log")
+
+	require.NoError(t, Append(fn, "show", "foo/bar", false))
+	require.NoError(t, Append(fn, "insert", "foo/baz", true))
+	require.NoError(t, Append(fn, "audit", "", false))
+
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+
+	entries, err := Read(bytes.NewReader(buf))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "", entries[0].Prev)
+	assert.Equal(t, entries[0].Hash, entries[1].Prev)
+	assert.Equal(t, "insert", entries[1].Command)
+	assert.True(t, entries[1].Failed)
+
+	n, err := Verify(entries)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestAppendConcurrent(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "audit.log")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, Append(fn, "show", fmt.Sprintf("secret%d", i), false))
+		}(i)
+	}
+	wg.Wait()
+
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	entries, err := Read(bytes.NewReader(buf))
+	require.NoError(t, err)
+
+	n, err := Verify(entries)
+	require.NoError(t, err)
+	assert.Equal(t, 20, n)
+}
+
+func TestVerifyTampered(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "audit.log")
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, Append(fn, "show", name, false))
+	}
+
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	entries, err := Read(bytes.NewReader(buf))
+	require.NoError(t, err)
+
+	t.Run("modified", func(t *testing.T) {
+		mod := append([]Entry{}, entries...)
+		mod[1].Name = "x"
+		n, err := Verify(mod)
+		assert.ErrorIs(t, err, ErrTampered)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		n, err := Verify([]Entry{entries[0], entries[2]})
+		assert.ErrorIs(t, err, ErrTampered)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("truncated head", func(t *testing.T) {
+		_, err := Verify(entries[1:])
+		assert.ErrorIs(t, err, ErrTampered)
+	})
+
+}
+
+func TestReadInvalid(t *testing.T) {
+	e := Entry{Command: "show"}
+	buf, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	_, err = Read(bytes.NewReader(append(append(buf, '\n'), []byte("garbage\n")...)))
+	assert.Error(t, err)
+}
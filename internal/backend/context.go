@@ -8,6 +8,7 @@ const (
 	ctxKeyCryptoBackend contextKey = iota
 	ctxKeyRCSBackend
 	ctxKeyStorageBackend
+	ctxKeySparse
 )
 
 // CryptoBackendName returns the name of the given backend.
@@ -81,3 +82,18 @@ func StorageBackendName(sb StorageBackend) string {
 	}
 	return ""
 }
+
+// WithSparse returns a context with the prefixes to check out when cloning
+// a store. Other prefixes are only fetched on demand.
+func WithSparse(ctx context.Context, prefixes []string) context.Context {
+	return context.WithValue(ctx, ctxKeySparse, prefixes)
+}
+
+// GetSparse returns the prefixes to check out or nil for a full checkout.
+func GetSparse(ctx context.Context) []string {
+	sv, ok := ctx.Value(ctxKeySparse).([]string)
+	if !ok {
+		return nil
+	}
+	return sv
+}
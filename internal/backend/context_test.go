@@ -34,3 +34,10 @@ func TestComposite(t *testing.T) {
 	assert.Equal(t, Age, GetCryptoBackend(ctx))
 	assert.Equal(t, FS, GetStorageBackend(ctx))
 }
+
+func TestSparse(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Nil(t, GetSparse(ctx))
+	assert.Equal(t, []string{"foo", "bar/baz"}, GetSparse(WithSparse(ctx, []string{"foo", "bar/baz"})))
+}
@@ -27,6 +27,10 @@ func (c CryptoBackend) String() string {
 	return ""
 }
 
+// ErrHiddenRecipients is returned by RecipientIDs if the ciphertext doesn't
+// reveal (all of) its recipients.
+var ErrHiddenRecipients = fmt.Errorf("recipients are hidden")
+
 // Keyring is a public/private key manager.
 type Keyring interface {
 	ListRecipients(ctx context.Context) ([]string, error)
@@ -58,6 +62,37 @@ type Crypto interface {
 	Concurrency() int
 }
 
+// BatchEncrypter is implemented by crypto backends that can encrypt many
+// plaintexts for the same recipients more efficiently than one at a time.
+type BatchEncrypter interface {
+	EncryptBatch(ctx context.Context, plaintexts map[string][]byte, recipients []string) (map[string][]byte, error)
+}
+
+// Unlocker is implemented by crypto backends that can ask for the
+// passphrases of the given identities upfront, e.g. before a bulk operation
+// that would otherwise prompt for every secret.
+type Unlocker interface {
+	Unlock(ctx context.Context, ids ...string) error
+}
+
+// PassphraseUnlocker is implemented by crypto backends that can tell if the
+// passphrase of an identity is cached and that can check a passphrase
+// entered in gopass instead of the usual prompt.
+type PassphraseUnlocker interface {
+	IsUnlocked(ctx context.Context, id string) bool
+	// UnlockWithPassphrase caches the passphrase if it's the right one for
+	// the identity.
+	UnlockWithPassphrase(ctx context.Context, id string, passphrase []byte) error
+}
+
+// Signer is implemented by crypto backends that can create and verify
+// detached signatures.
+type Signer interface {
+	Sign(ctx context.Context, data []byte) ([]byte, error)
+	// Verify returns the fingerprint of the key that made the signature.
+	Verify(ctx context.Context, data, sig []byte) (string, error)
+}
+
 // NewCrypto instantiates a new crypto backend.
 func NewCrypto(ctx context.Context, id CryptoBackend) (Crypto, error) {
 	if be, err := CryptoRegistry.Get(id); err == nil {
@@ -7,12 +7,14 @@ import (
 	"os"
 
 	"filippo.io/age"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
 // Encrypt will encrypt the given payload.
 func (a *Age) Encrypt(ctx context.Context, plaintext []byte, recipients []string) ([]byte, error) {
+	defer timing.Start(ctx, timing.Encrypt)()
 	// add our own public keys to the recipients to ensure we can decrypt it later.
 	idRecps, err := a.IdentityRecipients(ctx)
 	if err != nil {
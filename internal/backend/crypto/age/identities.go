@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"filippo.io/age"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -100,6 +101,7 @@ func (a *Age) ListIdentities(ctx context.Context) ([]string, error) {
 
 // FindIdentities returns all usable identities (native only).
 func (a *Age) FindIdentities(ctx context.Context, keys ...string) ([]string, error) {
+	defer timing.Start(ctx, timing.Keys)()
 	ids, err := a.IdentityRecipients(ctx)
 	if err != nil {
 		return nil, err
@@ -6,6 +6,7 @@ import (
 
 	"filippo.io/age"
 	"filippo.io/age/agessh"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
@@ -13,6 +14,7 @@ import (
 // For native age keys this is a no-op since they are self-contained (i.e. the ID is the full key already).
 // But for SSH keys, especially GitHub indirections, an extra step is necessary.
 func (a *Age) FindRecipients(ctx context.Context, search ...string) ([]string, error) {
+	defer timing.Start(ctx, timing.Keys)()
 	remote := make([]string, 0, len(search))
 	local := make([]string, 0, len(search))
 	for _, key := range search {
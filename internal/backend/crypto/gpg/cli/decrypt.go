@@ -3,19 +3,39 @@ package cli
 import (
 	"bytes"
 	"context"
-	"os"
 	"os/exec"
 
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
 // Decrypt will try to decrypt the given file.
 func (g *GPG) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	defer timing.Start(ctx, timing.Decrypt)()
 	args := append(g.args, "--decrypt")
-	cmd := exec.CommandContext(ctx, g.binary, args...)
-	cmd.Stdin = bytes.NewReader(ciphertext)
-	cmd.Stderr = os.Stderr
+	if g.hidesRecipients(ctx) {
+		// gpg can't tell which key to use and would otherwise try all of
+		// them, possibly asking for many passphrases.
+		for _, k := range gpg.GetTrySecretKeys(ctx) {
+			args = append(args, "--try-secret-key", k)
+		}
+	}
 
-	debug.Log("%s %+v", cmd.Path, cmd.Args)
-	return cmd.Output()
+	ctx, cancel := g.timeout(ctx, true)
+	defer cancel()
+
+	var plaintext []byte
+	err := retry(ctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		cmd.Stdin = bytes.NewReader(ciphertext)
+		cmd.Stderr = stderr
+
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+		var err error
+		plaintext, err = cmd.Output()
+		return err
+	})
+	return plaintext, err
 }
@@ -3,44 +3,162 @@ package cli
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/tempfile"
 )
 
-// Encrypt will encrypt the given content for the recipients. If alwaysTrust is true
-// the trust-model will be set to always as to avoid (annoying) "unusable public key"
-// errors when encrypting.
+// Encrypt will encrypt the given content for the recipients using the trust
+// model from the context. With the trust model always there are no (annoying)
+// "unusable public key" errors when encrypting.
 func (g *GPG) Encrypt(ctx context.Context, plaintext []byte, recipients []string) ([]byte, error) {
-	args := append(g.args, "--encrypt")
-	if gpg.IsAlwaysTrust(ctx) {
+	defer timing.Start(ctx, timing.Encrypt)()
+	args := g.encryptArgs(ctx, recipients)
+
+	buf := &bytes.Buffer{}
+
+	ctx, cancel := g.timeout(ctx, false)
+	defer cancel()
+
+	err := retry(ctx, func(stderr *bytes.Buffer) error {
+		buf.Reset()
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		cmd.Stdin = bytes.NewReader(plaintext)
+		// the encrypted blob is written to stdout
+		cmd.Stdout = buf
+		cmd.Stderr = stderr
+
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+		return cmd.Run()
+	})
+	return buf.Bytes(), err
+}
+
+// EncryptBatch encrypts many plaintexts for the same recipients with a
+// single gpg invocation. The plaintexts are written to a temporary
+// directory (on a ramdisk, if possible) and encrypted with --multifile.
+// The result is keyed by the same names as the input.
+func (g *GPG) EncryptBatch(ctx context.Context, plaintexts map[string][]byte, recipients []string) (map[string][]byte, error) {
+	if len(plaintexts) < 1 {
+		return map[string][]byte{}, nil
+	}
+	defer timing.Start(ctx, timing.Encrypt)()
+
+	tf, err := tempfile.New(ctx, "gopass-batch-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tempdir: %w", err)
+	}
+	defer func() {
+		_ = tf.Remove(ctx)
+	}()
+	_ = tf.Close()
+	dir := filepath.Dir(tf.Name())
+
+	// the names are arbitrary secret names that may contain slashes,
+	// so we use numbered files instead.
+	names := make([]string, 0, len(plaintexts))
+	for name := range plaintexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := append(g.encryptArgs(ctx, recipients), "--multifile")
+	for i, name := range names {
+		fn := filepath.Join(dir, strconv.Itoa(i))
+		if err := os.WriteFile(fn, plaintexts[name], 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", fn, err)
+		}
+		args = append(args, fn)
+	}
+
+	// the timeout applies to the whole batch, so it scales with its size
+	ctx, cancel := withTimeout(ctx, time.Duration(len(names))*gpg.GetCommandTimeout(ctx))
+	defer cancel()
+
+	if err := retry(ctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		cmd.Stderr = stderr
+
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run command '%s %+v': %w", cmd.Path, cmd.Args, err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string][]byte, len(names))
+	for i, name := range names {
+		fn := filepath.Join(dir, strconv.Itoa(i)+"."+Ext)
+		buf, err := os.ReadFile(fn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ciphertext for %s: %w", name, err)
+		}
+		res[name] = buf
+	}
+
+	return res, nil
+}
+
+// encryptArgs returns the arguments for encrypting to the useable subset of
+// the given recipients.
+func (g *GPG) encryptArgs(ctx context.Context, recipients []string) []string {
+	args := make([]string, 0, len(g.args)+2+2*len(recipients))
+	args = append(args, g.args...)
+	args = append(args, g.keyringArgs(ctx)...)
+	args = append(args, "--encrypt")
+	if tm := gpg.GetTrustModel(ctx); tm != gpg.TrustAuto {
 		// changing the trustmodel is possibly dangerous. A user should always
 		// explicitly opt-in to do this
-		args = append(args, "--trust-model=always")
+		args = append(args, "--trust-model="+tm)
+	}
+	if g.hidesRecipients(ctx) {
+		args = append(args, "--throw-keyids")
+	}
+	// these override the defaults since gpg uses the last value given
+	if algo := gpg.GetCompression(ctx); algo != "" {
+		args = append(args, "--compress-algo="+algo)
+	}
+	if prefs := gpg.GetCipherPrefs(ctx); prefs != "" {
+		args = append(args, "--personal-cipher-preferences="+prefs)
+	}
+	if prefs := gpg.GetDigestPrefs(ctx); prefs != "" {
+		args = append(args, "--personal-digest-preferences="+prefs)
 	}
+	keys := g.lookupKeys(ctx, "public", recipients...)
 	for _, r := range recipients {
-		kl, err := g.listKeys(ctx, "public", r)
-		if err != nil {
-			debug.Log("Failed to check key %s. Adding anyway. %s", err)
-		} else if len(kl.UseableKeys(gpg.IsAlwaysTrust(ctx))) < 1 {
+		kl, found := keys[r]
+		if !found {
+			debug.Log("Failed to check key %s. Adding anyway.", r)
+			args = append(args, "--recipient", r)
+			continue
+		}
+		uk := kl.UseableKeys(gpg.IsAlwaysTrust(ctx))
+		if len(uk) < 1 {
 			out.Printf(ctx, "Not using invalid key %s for encryption. (Check its expiration date or its encryption capabilities.)", r)
 			continue
 		}
+		// Explicitly select the encryption subkey. Some setups fail to
+		// pick the right one, e.g. with sign-only primary keys.
+		if ek := uk[0].EncryptionKey(); len(uk) == 1 && ek != "" {
+			debug.Log("Using encryption key %s for %s", ek, r)
+			args = append(args, "--recipient", ek+"!")
+			continue
+		}
 		args = append(args, "--recipient", r)
 	}
-
-	buf := &bytes.Buffer{}
-
-	cmd := exec.CommandContext(ctx, g.binary, args...)
-	cmd.Stdin = bytes.NewReader(plaintext)
-	// the encrypted blob is written to stdout
-	cmd.Stdout = buf
-	cmd.Stderr = os.Stderr
-
-	debug.Log("%s %+v", cmd.Path, cmd.Args)
-	err := cmd.Run()
-	return buf.Bytes(), err
+	return args
 }
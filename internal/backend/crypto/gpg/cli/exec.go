@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+var (
+	// maxAttempts is the number of times a gpg invocation is tried if it
+	// fails due to a transient error.
+	maxAttempts = 3
+	// retryDelay is multiplied by the number of the attempt to get the
+	// time to wait before the next one.
+	retryDelay = 500 * time.Millisecond
+
+	// transientErrors are messages printed by gpg if it failed to talk to
+	// the gpg-agent, e.g. because it was still starting or restarted
+	// concurrently. Trying again usually helps.
+	transientErrors = []string{
+		"can't connect to the agent",
+		"IPC connect call failed",
+		"IPC read error",
+		"connection to agent lost",
+		"Resource temporarily unavailable",
+	}
+)
+
+// homedir returns the GNUPGHOME to use. A homedir set in the context takes
+// precedence over the one of the store this instance was created for.
+func (g *GPG) homedir(ctx context.Context) string {
+	if home := gpg.GetHomedir(ctx); home != "" {
+		return home
+	}
+	return g.home
+}
+
+// env returns the environment for a gpg child process. extra variables,
+// e.g. "LC_ALL=C", override the ones inherited from gopass. It returns nil,
+// i.e. the unchanged environment of gopass, if there is nothing to override.
+// gopass must never change its own environment for this, since concurrent
+// invocations (or tests) would interfere with each other.
+func (g *GPG) env(ctx context.Context, extra ...string) []string {
+	if home := g.homedir(ctx); home != "" {
+		extra = append(extra, "GNUPGHOME="+home)
+	}
+	if len(extra) < 1 {
+		return nil
+	}
+	return append(os.Environ(), extra...)
+}
+
+// timeout returns a context that is canceled after the configured timeout.
+// Operations that might ask for a passphrase (interactive) get a more generous
+// timeout than ones that should be done quickly. Otherwise a hung gpg or
+// pinentry would block gopass forever.
+func (g *GPG) timeout(ctx context.Context, interactive bool) (context.Context, context.CancelFunc) {
+	d := gpg.GetCommandTimeout(ctx)
+	if interactive {
+		d = gpg.GetPinentryTimeout(ctx)
+	}
+	return withTimeout(ctx, d)
+}
+
+// withTimeout is like context.WithTimeout but a negative duration disables
+// the timeout.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d < 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// retry calls fn until it succeeds or fails for a reason that is not likely
+// to go away by itself. fn must write the stderr of gpg to the given buffer
+// so the failure can be classified. Errors are returned as *gpg.Error
+// including the stderr of the last attempt.
+func retry(ctx context.Context, fn func(stderr *bytes.Buffer) error) error {
+	for i := 1; ; i++ {
+		stderr := &bytes.Buffer{}
+		err := fn(stderr)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("gpg did not finish in time: %w", ctx.Err())
+		}
+		if ctx.Err() != nil || !isTransient(stderr.String()) || i >= maxAttempts {
+			return &gpg.Error{Err: err, Stderr: stderr.String()}
+		}
+
+		debug.Log("gpg failed with transient error (attempt %d of %d): %s - %s", i, maxAttempts, err, stderr.String())
+		select {
+		case <-ctx.Done():
+			return &gpg.Error{Err: err, Stderr: stderr.String()}
+		case <-time.After(time.Duration(i) * retryDelay):
+		}
+	}
+}
+
+func isTransient(stderr string) bool {
+	for _, msg := range transientErrors {
+		if strings.Contains(stderr, msg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+//go:build !windows
+// +build !windows
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyGPG writes a shell script that fails with a gpg-agent error for the
+// first fails invocations and echos its input afterwards. Every invocation is
+// recorded in the returned log file.
+func flakyGPG(t *testing.T, fails int, msg string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls.log")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$*" >> %[1]s
+if [ "$(wc -l < %[1]s)" -le %[2]d ]; then
+  echo "gpg: %[3]s" >&2
+  exit 2
+fi
+cat
+`, log, fails, msg)
+	bin := filepath.Join(dir, "gpg")
+	require.NoError(t, os.WriteFile(bin, []byte(script), 0700))
+
+	return bin, log
+}
+
+func countCalls(t *testing.T, log string) int {
+	t.Helper()
+
+	buf, err := os.ReadFile(log)
+	require.NoError(t, err)
+
+	return strings.Count(string(buf), "\n")
+}
+
+func TestRetryTransient(t *testing.T) {
+	ctx := context.Background()
+
+	oldDelay := retryDelay
+	retryDelay = time.Millisecond
+	defer func() {
+		retryDelay = oldDelay
+	}()
+
+	t.Run("agent not ready", func(t *testing.T) {
+		bin, log := flakyGPG(t, 1, "can't connect to the agent: IPC connect call failed")
+		g := &GPG{binary: bin}
+
+		buf, err := g.Decrypt(ctx, []byte("foo"))
+		require.NoError(t, err)
+		assert.Equal(t, "foo", string(buf))
+		assert.Equal(t, 2, countCalls(t, log))
+	})
+
+	t.Run("agent never ready", func(t *testing.T) {
+		bin, log := flakyGPG(t, 10, "can't connect to the agent: IPC connect call failed")
+		g := &GPG{binary: bin}
+
+		_, err := g.Decrypt(ctx, []byte("foo"))
+		assert.Error(t, err)
+		assert.Equal(t, maxAttempts, countCalls(t, log))
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		bin, log := flakyGPG(t, 1, "decryption failed: No secret key")
+		g := &GPG{binary: bin}
+
+		_, err := g.Decrypt(ctx, []byte("foo"))
+		require.Error(t, err)
+		assert.Equal(t, 1, countCalls(t, log))
+
+		var gerr *gpg.Error
+		require.True(t, errors.As(err, &gerr))
+		assert.Equal(t, "gpg: decryption failed: No secret key\n", gerr.Stderr)
+		assert.Contains(t, err.Error(), "No secret key")
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "gpg")
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\nexec sleep 10\n"), 0700))
+	g := &GPG{binary: bin}
+
+	t.Run("batch", func(t *testing.T) {
+		ctx := gpg.WithCommandTimeout(ctx, 50*time.Millisecond)
+
+		start := time.Now()
+		_, err := g.Encrypt(ctx, []byte("foo"), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not finish in time")
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("interactive", func(t *testing.T) {
+		// the batch timeout must not affect operations that might prompt
+		ctx := gpg.WithCommandTimeout(ctx, time.Millisecond)
+		ctx = gpg.WithPinentryTimeout(ctx, 50*time.Millisecond)
+
+		start := time.Now()
+		_, err := g.Decrypt(ctx, []byte("foo"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not finish in time")
+		assert.Greater(t, time.Since(start), 40*time.Millisecond)
+	})
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+
+	oldDelay := retryDelay
+	retryDelay = time.Millisecond
+	defer func() {
+		retryDelay = oldDelay
+	}()
+
+	calls := 0
+	err := retry(ctx, func(stderr *bytes.Buffer) error {
+		calls++
+		if calls < 3 {
+			stderr.WriteString("gpg: connection to agent lost")
+			return fmt.Errorf("exit status 2")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestEnv(t *testing.T) {
+	ctx := context.Background()
+	g := &GPG{}
+
+	assert.Nil(t, g.env(ctx))
+	assert.Contains(t, g.env(ctx, "LC_ALL=C"), "LC_ALL=C")
+
+	// the store's keyring is used by default
+	g.home = "/tmp/work"
+	assert.Contains(t, g.env(ctx), "GNUPGHOME=/tmp/work")
+
+	// but the context takes precedence
+	ctx = gpg.WithHomedir(ctx, "/tmp/gnupg")
+	assert.Contains(t, g.env(ctx), "GNUPGHOME=/tmp/gnupg")
+}
@@ -22,19 +22,41 @@ Expire-Date: 0
 `)
 	_, _ = buf.WriteString("Name-Real: " + name + "\n")
 	_, _ = buf.WriteString("Name-Email: " + email + "\n")
-	_, _ = buf.WriteString("Passphrase: " + passphrase + "\n")
-
 	args := []string{"--batch", "--gen-key"}
-	cmd := exec.CommandContext(ctx, g.binary, args...)
-	cmd.Stdin = bytes.NewReader(buf.Bytes())
+	caps := g.capabilities(ctx)
+	switch {
+	case passphrase == "" && caps.SecretKeysInAgent:
+		// gpg 2.1+ would prompt for a passphrase otherwise
+		_, _ = buf.WriteString("%no-protection\n")
+	case caps.PinentryMode:
+		// gpg 2.1+ ignores the passphrase from the batch file unless
+		// loopback pinentry is used
+		_, _ = buf.WriteString("Passphrase: " + passphrase + "\n")
+		args = append(args, "--pinentry-mode=loopback")
+	default:
+		_, _ = buf.WriteString("Passphrase: " + passphrase + "\n")
+	}
+
+	// generating a key might take a while and ask for a passphrase
+	ctx, cancel := g.timeout(ctx, true)
+	defer cancel()
+
+	if err := retry(ctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		cmd.Stdin = bytes.NewReader(buf.Bytes())
 
-	out := &bytes.Buffer{}
-	cmd.Stdout = out
-	cmd.Stderr = out
+		// gpg reports the progress of the key generation on stdout
+		cmd.Stdout = stderr
+		cmd.Stderr = stderr
 
-	debug.Log("%s %+v", cmd.Path, cmd.Args)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run command: '%s %+v': %q - %w", cmd.Path, cmd.Args, out.String(), err)
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run command: '%s %+v': %w", cmd.Path, cmd.Args, err)
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
 	g.privKeys = nil
 	g.pubKeys = nil
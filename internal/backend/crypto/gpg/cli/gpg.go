@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
@@ -26,10 +27,16 @@ var (
 type GPG struct {
 	binary    string
 	args      []string
+	home      string
 	pubKeys   gpg.KeyList
 	privKeys  gpg.KeyList
 	listCache *lru.TwoQueueCache
+	diskCache *keyCache
 	throwKids bool
+	caps      gpgconf.Capabilities
+	capsOnce  sync.Once
+	// hinted records the GnuPG homes keyring hints were printed for.
+	hinted sync.Map
 }
 
 // Config is the gpg wrapper config.
@@ -52,7 +59,8 @@ func New(ctx context.Context, cfg Config) (*GPG, error) {
 		}
 	}
 
-	gcfg, err := gpgconf.Config()
+	home := gpg.GetHomedir(ctx)
+	gcfg, err := gpgconf.Config(home)
 	if err != nil {
 		debug.Log("failed to read GPG config: %s", err)
 	}
@@ -61,6 +69,7 @@ func New(ctx context.Context, cfg Config) (*GPG, error) {
 	g := &GPG{
 		binary:    "gpg",
 		args:      append(defaultArgs, cfg.Args...),
+		home:      home,
 		throwKids: hasThrowKids,
 	}
 
@@ -69,6 +78,7 @@ func New(ctx context.Context, cfg Config) (*GPG, error) {
 		return nil, fmt.Errorf("failed to initialize the LRU cache: %w", err)
 	}
 	g.listCache = cache
+	g.diskCache = newKeyCache()
 
 	bin, err := gpgconf.Binary(ctx, cfg.Binary)
 	if err != nil {
@@ -113,3 +123,18 @@ func (g *GPG) Binary() string {
 	}
 	return g.binary
 }
+
+// capabilities returns the (cached) capabilities of the gpg binary.
+func (g *GPG) capabilities(ctx context.Context) gpgconf.Capabilities {
+	g.capsOnce.Do(func() {
+		g.caps = gpgconf.Probe(ctx, g.binary)
+	})
+	return g.caps
+}
+
+// hidesRecipients returns true if the key IDs of the recipients are (or
+// should be) removed from encrypted secrets. Either because the user has
+// configured this for the store or globally in the gpg.conf.
+func (g *GPG) hidesRecipients(ctx context.Context) bool {
+	return g.throwKids || gpg.IsThrowKeyIDs(ctx)
+}
@@ -5,9 +5,12 @@ package cli
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEncrypt(t *testing.T) {
@@ -38,3 +41,43 @@ func TestGenerateIdentity(t *testing.T) {
 
 	assert.NoError(t, g.GenerateIdentity(ctx, "foo", "foo@bar.com", "bar"))
 }
+
+func TestEncryptBatch(t *testing.T) {
+	ctx := context.Background()
+
+	// fake gpg that "encrypts" all files given after --multifile by copying
+	// them to <file>.gpg
+	bin := filepath.Join(t.TempDir(), "gpg")
+	script := `#!/bin/sh
+multi=0
+for a in "$@"; do
+  if [ "$a" = "--multifile" ]; then
+    multi=1
+    continue
+  fi
+  if [ $multi -eq 1 ]; then
+    cp "$a" "$a.gpg"
+  fi
+done
+`
+	require.NoError(t, os.WriteFile(bin, []byte(script), 0700))
+
+	g := &GPG{}
+	g.binary = bin
+
+	in := map[string][]byte{
+		"foo/bar": []byte("bar"),
+		"foo/baz": []byte("baz"),
+	}
+	res, err := g.EncryptBatch(ctx, in, nil)
+	require.NoError(t, err)
+	assert.Equal(t, in, res)
+
+	res, err = g.EncryptBatch(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	g.binary = "false"
+	_, err = g.EncryptBatch(ctx, in, nil)
+	assert.Error(t, err)
+}
@@ -38,3 +38,15 @@ func TestGPG(t *testing.T) {
 	assert.Equal(t, "gpg", g.Ext())
 	assert.Equal(t, ".gpg-id", g.IDFile())
 }
+
+func TestParseValidSig(t *testing.T) {
+	fp, err := parseValidSig([]byte(`[GNUPG:] NEWSIG
+[GNUPG:] GOODSIG 62AF4031C82E0039 John Doe <john@example.org>
+[GNUPG:] VALIDSIG 1A2B3C4D5E6F1A2B3C4D5E6F62AF4031C82E0039 2021-01-01 1609459200 0 4 0 1 10 00 25FF1614B8F87B52FFFF99B962AF4031C82E0039
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "25FF1614B8F87B52FFFF99B962AF4031C82E0039", fp)
+
+	_, err = parseValidSig([]byte("[GNUPG:] BADSIG 62AF4031C82E0039 John Doe\n"))
+	assert.Error(t, err)
+}
@@ -2,6 +2,9 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 )
@@ -16,9 +19,9 @@ func (g *GPG) ListIdentities(ctx context.Context) ([]string, error) {
 		g.privKeys = kl
 	}
 	if gpg.IsAlwaysTrust(ctx) {
-		return g.privKeys.Recipients(), nil
+		return g.privKeys.Fingerprints(), nil
 	}
-	return g.privKeys.UseableKeys(gpg.IsAlwaysTrust(ctx)).Recipients(), nil
+	return g.privKeys.UseableKeys(gpg.IsAlwaysTrust(ctx)).Fingerprints(), nil
 }
 
 // FindIdentities searches for the given private keys.
@@ -28,9 +31,9 @@ func (g *GPG) FindIdentities(ctx context.Context, search ...string) ([]string, e
 		return nil, err
 	}
 	if gpg.IsAlwaysTrust(ctx) {
-		return kl.Recipients(), nil
+		return kl.Fingerprints(), nil
 	}
-	return kl.UseableKeys(gpg.IsAlwaysTrust(ctx)).Recipients(), nil
+	return kl.UseableKeys(gpg.IsAlwaysTrust(ctx)).Fingerprints(), nil
 }
 
 func (g *GPG) findKey(ctx context.Context, id string) gpg.Key {
@@ -46,3 +49,28 @@ func (g *GPG) findKey(ctx context.Context, id string) gpg.Key {
 		Fingerprint: id,
 	}
 }
+
+// ExpirationDate returns the expiration date of the given key. It's zero if
+// the key never expires.
+func (g *GPG) ExpirationDate(ctx context.Context, id string) time.Time {
+	return g.findKey(ctx, id).ExpirationDate
+}
+
+// KeyInfo returns the details of the public key matching id and whether its
+// secret key is available.
+func (g *GPG) KeyInfo(ctx context.Context, id string) (gpg.Key, bool, error) {
+	kl, err := g.listKeys(ctx, "public", id)
+	if err != nil {
+		return gpg.Key{}, false, err
+	}
+	switch len(kl) {
+	case 0:
+		return gpg.Key{}, false, fmt.Errorf("no public key found for %s", id)
+	case 1:
+	default:
+		return gpg.Key{}, false, fmt.Errorf("%s matches %d keys (%s). Please use the fingerprint", id, len(kl), strings.Join(kl.Fingerprints(), ", "))
+	}
+
+	sl, err := g.listKeys(ctx, "secret", kl[0].Fingerprint)
+	return kl[0], err == nil && len(sl) > 0, nil
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
+	"github.com/gopasspw/gopass/internal/out"
+)
+
+// Keyring detects the keyring format of the GnuPG home in use.
+func (g *GPG) Keyring(ctx context.Context) gpgconf.Keyring {
+	return gpgconf.DetectKeyring(g.homedir(ctx))
+}
+
+// keyringArgs returns the arguments to make all public keys visible. gpg
+// 2.1+ ignores a legacy pubring.gpg next to a pubring.kbx, so it's added
+// explicitly until the user migrated it.
+func (g *GPG) keyringArgs(ctx context.Context) []string {
+	k := g.Keyring(ctx)
+	if k.Format != gpgconf.KeyringMixed || !g.capabilities(ctx).Keybox {
+		return nil
+	}
+	return []string{"--keyring", k.LegacyKeyring()}
+}
+
+// CheckKeyring returns an error if gpg can't use the keyring, e.g. gpg 1.x
+// with a keybox created by gpg 2.1+. Other differences are printed as
+// migration hints once per GnuPG home.
+func (g *GPG) CheckKeyring(ctx context.Context) error {
+	k := g.Keyring(ctx)
+	c := g.capabilities(ctx)
+	if err := k.Check(c); err != nil {
+		return err
+	}
+	if _, hinted := g.hinted.LoadOrStore(k.Dir, true); !hinted {
+		for _, h := range k.Hints(c) {
+			out.Warningf(ctx, "%s", h)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
+	"github.com/gopasspw/gopass/internal/cache"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	keyCacheName = "gpg-keys"
+	keyCacheTTL  = 24 * time.Hour
+)
+
+// keyCache persists the raw output of key listings across invocations.
+// Entries are invalidated whenever the keyring is modified.
+type keyCache struct {
+	disk *cache.OnDisk
+}
+
+func newKeyCache() *keyCache {
+	d, err := cache.NewOnDisk(keyCacheName, keyCacheTTL)
+	if err != nil {
+		debug.Log("failed to initialize key cache: %s", err)
+		return nil
+	}
+	return &keyCache{disk: d}
+}
+
+func (k *keyCache) key(binary, homedir string, args []string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(binary+","+homedir+","+strings.Join(args, ","))))
+}
+
+func (k *keyCache) stamp(homedir string) string {
+	return "keyring:" + strconv.FormatInt(gpgconf.KeyringModTime(homedir).UnixNano(), 10)
+}
+
+// get returns the cached listing if the keyring hasn't changed since it
+// was stored.
+func (k *keyCache) get(binary, homedir string, args []string) ([]byte, bool) {
+	if k == nil {
+		return nil, false
+	}
+
+	lines, err := k.disk.Get(k.key(binary, homedir, args))
+	if err != nil || len(lines) < 1 {
+		return nil, false
+	}
+	if lines[0] != k.stamp(homedir) {
+		debug.Log("key cache outdated")
+		return nil, false
+	}
+
+	return []byte(strings.Join(lines[1:], "\n")), true
+}
+
+func (k *keyCache) set(binary, homedir string, args []string, buf []byte) {
+	if k == nil {
+		return
+	}
+
+	lines := append([]string{k.stamp(homedir)}, strings.Split(string(buf), "\n")...)
+	if err := k.disk.Set(k.key(binary, homedir, args), lines); err != nil {
+		debug.Log("failed to write key cache: %s", err)
+	}
+}
+
+// PurgeKeyCache removes all persisted key listings.
+func PurgeKeyCache() error {
+	d, err := cache.NewOnDisk(keyCacheName, keyCacheTTL)
+	if err != nil {
+		return err
+	}
+	return d.Purge()
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCache(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GOPASS_HOMEDIR", td)
+	t.Setenv("GNUPGHOME", filepath.Join(td, ".gnupg"))
+	require.NoError(t, os.MkdirAll(filepath.Join(td, ".gnupg"), 0700))
+
+	pubring := filepath.Join(td, ".gnupg", "pubring.kbx")
+	require.NoError(t, os.WriteFile(pubring, []byte("foo"), 0600))
+
+	kc := newKeyCache()
+	require.NotNil(t, kc)
+
+	args := []string{"--list-public-keys", "foo"}
+	_, found := kc.get("gpg", "", args)
+	assert.False(t, found)
+
+	kc.set("gpg", "", args, []byte("pub:u:2048\nfpr:::::::::DEADBEEF:"))
+	buf, found := kc.get("gpg", "", args)
+	assert.True(t, found)
+	assert.Equal(t, "pub:u:2048\nfpr:::::::::DEADBEEF:", string(buf))
+
+	// different args must not match
+	_, found = kc.get("gpg", "", []string{"--list-public-keys"})
+	assert.False(t, found)
+
+	// a different keyring must not match
+	_, found = kc.get("gpg", filepath.Join(td, "other"), args)
+	assert.False(t, found)
+
+	// modifying the keyring invalidates the cache
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(pubring, future, future))
+	_, found = kc.get("gpg", "", args)
+	assert.False(t, found)
+
+	// nil cache is safe to use
+	var nc *keyCache
+	_, found = nc.get("gpg", "", args)
+	assert.False(t, found)
+	nc.set("gpg", "", args, nil)
+
+	assert.NoError(t, PurgeKeyCache())
+}
@@ -4,46 +4,151 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os"
+	"io"
 	"os/exec"
 	"strings"
 	"text/template"
 
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/colons"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/debug"
 
 	//lint:ignore SA1019 we'll try to migrate away later
 	"golang.org/x/crypto/openpgp"
 )
 
+// maxListKeys is the maximum number of ids looked up with a single gpg
+// invocation. It keeps the command line of gpg within the limits of all
+// platforms.
+const maxListKeys = 64
+
+// listArgs returns the arguments to list keys of the given type. They only
+// ask for what the parser needs, listing large keyrings can take seconds.
+func (g *GPG) listArgs(ctx context.Context, typ string) []string {
+	args := []string{
+		"--quiet", "--with-colons", "--fixed-list-mode",
+		// override expensive list options from the gpg.conf
+		"--list-options=no-show-photos,no-show-sig-subpackets",
+	}
+	// gpg 2.1+ always prints the fingerprints of primary keys in this mode.
+	if !g.capabilities(ctx).ColonFingerprints {
+		args = append(args, "--with-fingerprint")
+	}
+	// keygrips are needed to ask gpg-agent about secret keys.
+	if typ == "secret" && g.capabilities(ctx).SecretKeysInAgent {
+		args = append(args, "--with-keygrip")
+	}
+	if typ == "public" {
+		args = append(args, g.keyringArgs(ctx)...)
+	}
+	// the validity of the keys depends on the trust model.
+	if tm := gpg.GetTrustModel(ctx); tm != gpg.TrustAuto && tm != gpg.TrustAlways {
+		args = append(args, "--trust-model="+tm)
+	}
+	return append(args, "--list-"+typ+"-keys")
+}
+
 // listKey lists all keys of the given type and matching the search strings.
 func (g *GPG) listKeys(ctx context.Context, typ string, search ...string) (gpg.KeyList, error) {
-	args := []string{"--with-colons", "--with-fingerprint", "--fixed-list-mode", "--list-" + typ + "-keys"}
-	args = append(args, search...)
-	if e, found := g.listCache.Get(strings.Join(args, ",")); found && gpg.UseCache(ctx) {
+	args := append(g.listArgs(ctx, typ), search...)
+	home := g.homedir(ctx)
+	ckey := home + "," + strings.Join(args, ",")
+	if e, found := g.listCache.Get(ckey); found && gpg.UseCache(ctx) {
 		if ev, ok := e.(gpg.KeyList); ok {
 			return ev, nil
 		}
 	}
-	cmd := exec.CommandContext(ctx, g.binary, args...)
+	if gpg.IsPersistentCache(ctx) {
+		if buf, found := g.diskCache.get(g.binary, home, args); found {
+			kl := colons.Parse(bytes.NewReader(buf))
+			g.listCache.Add(ckey, kl)
+			return kl, nil
+		}
+	}
+
+	if err := g.CheckKeyring(ctx); err != nil {
+		return gpg.KeyList{}, err
+	}
+
+	defer timing.Start(ctx, timing.Keys)()
+	tctx, cancel := g.timeout(ctx, false)
+	defer cancel()
+
+	var cmdout []byte
 	var errBuf = bytes.Buffer{}
-	cmd.Stderr = &errBuf
+	err := retry(tctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(tctx, g.binary, args...)
+		cmd.Env = g.env(tctx)
+		errBuf.Reset()
+		cmd.Stderr = io.MultiWriter(&errBuf, stderr)
 
-	debug.Log("%s %+v\n", cmd.Path, cmd.Args)
-	cmdout, err := cmd.Output()
+		debug.Log("%s %+v\n", cmd.Path, cmd.Args)
+		var err error
+		cmdout, err = cmd.Output()
+		return err
+	})
 	if err != nil {
-		if bytes.Contains(cmdout, []byte("secret key not available")) {
+		if msg := []byte("secret key not available"); bytes.Contains(cmdout, msg) || bytes.Contains(errBuf.Bytes(), msg) {
 			return gpg.KeyList{}, nil
 		}
-		return gpg.KeyList{}, fmt.Errorf("%s: %s|%s", err, cmdout, errBuf.String())
+		// don't include the output, it can be huge on large keyrings.
+		return gpg.KeyList{}, fmt.Errorf("%s: %s", err, strings.TrimSpace(errBuf.String()))
+	}
+
+	if gpg.IsPersistentCache(ctx) {
+		g.diskCache.set(g.binary, home, args, cmdout)
 	}
 
 	kl := colons.Parse(bytes.NewBuffer(cmdout))
-	g.listCache.Add(strings.Join(args, ","), kl)
+	g.listCache.Add(ckey, kl)
+	// also cache single key lookups under the fingerprint since that's what
+	// we hand out as the canonical recipient id.
+	if len(search) == 1 && len(kl) == 1 && kl[0].Fingerprint != "" && kl[0].Fingerprint != search[0] {
+		fargs := append(args[:len(args)-1:len(args)-1], kl[0].Fingerprint)
+		g.listCache.Add(home+","+strings.Join(fargs, ","), kl)
+	}
 	return kl, nil
 }
 
+// lookupKeys resolves the given ids to keys. It tries to resolve the ids
+// with as few gpg invocations as possible (at most maxListKeys ids each)
+// and only falls back to looking up ids one by one if that fails. Spawning
+// gpg is expensive on large keyrings so this matters for stores with many
+// recipients. Ids that could not be looked up are missing from the result.
+func (g *GPG) lookupKeys(ctx context.Context, typ string, ids ...string) map[string]gpg.KeyList {
+	res := make(map[string]gpg.KeyList, len(ids))
+	for i := 0; len(ids) > 1 && i < len(ids); i += maxListKeys {
+		batch := ids[i:]
+		if len(batch) > maxListKeys {
+			batch = batch[:maxListKeys]
+		}
+		kl, err := g.listKeys(ctx, typ, batch...)
+		if err != nil {
+			debug.Log("failed to list keys %q: %s", batch, err)
+		}
+		for _, id := range batch {
+			if k, err := kl.FindKey(id); err == nil {
+				res[id] = gpg.KeyList{k}
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if _, found := res[id]; found {
+			continue
+		}
+		kl, err := g.listKeys(ctx, typ, id)
+		if err != nil {
+			debug.Log("failed to list key %s: %s", id, err)
+			continue
+		}
+		res[id] = kl
+	}
+
+	return res
+}
+
 // Fingerprint returns the fingerprint.
 func (g *GPG) Fingerprint(ctx context.Context, id string) string {
 	return g.findKey(ctx, id).Fingerprint
@@ -93,16 +198,28 @@ func (g *GPG) ImportPublicKey(ctx context.Context, buf []byte) error {
 	if len(buf) < 1 {
 		return fmt.Errorf("empty input")
 	}
+	if err := g.CheckKeyring(ctx); err != nil {
+		return err
+	}
 
 	args := append(g.args, "--import")
-	cmd := exec.CommandContext(ctx, g.binary, args...)
-	cmd.Stdin = bytes.NewReader(buf)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
-	debug.Log("gpg.ImportPublicKey: %s %+v", cmd.Path, cmd.Args)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run command: '%s %+v': %w", cmd.Path, cmd.Args, err)
+	ctx, cancel := g.timeout(ctx, false)
+	defer cancel()
+
+	if err := retry(ctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		cmd.Stdin = bytes.NewReader(buf)
+		cmd.Stderr = stderr
+
+		debug.Log("gpg.ImportPublicKey: %s %+v", cmd.Path, cmd.Args)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run command: '%s %+v': %w", cmd.Path, cmd.Args, err)
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	// clear key cache
@@ -111,19 +228,43 @@ func (g *GPG) ImportPublicKey(ctx context.Context, buf []byte) error {
 	return nil
 }
 
+// UntrustKey sets the owner trust of the given key to never in the local
+// keyring.
+func (g *GPG) UntrustKey(ctx context.Context, id string) error {
+	return g.SetOwnerTrust(ctx, id, "never")
+}
+
 // ExportPublicKey will export the named public key to the location given.
 func (g *GPG) ExportPublicKey(ctx context.Context, id string) ([]byte, error) {
 	if id == "" {
 		return nil, fmt.Errorf("id is empty")
 	}
 
-	args := append(g.args, "--armor", "--export", id)
-	cmd := exec.CommandContext(ctx, g.binary, args...)
+	if err := g.CheckKeyring(ctx); err != nil {
+		return nil, err
+	}
+
+	args := append(g.args, g.keyringArgs(ctx)...)
+	args = append(args, "--armor", "--export", id)
 
-	debug.Log("%s %+v", cmd.Path, cmd.Args)
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to run command '%s %+v': %w", cmd.Path, cmd.Args, err)
+	ctx, cancel := g.timeout(ctx, false)
+	defer cancel()
+
+	var out []byte
+	if err := retry(ctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		cmd.Stderr = stderr
+
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+		var err error
+		out, err = cmd.Output()
+		if err != nil {
+			return fmt.Errorf("failed to run command '%s %+v': %w", cmd.Path, cmd.Args, err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	if len(out) < 1 {
@@ -0,0 +1,372 @@
+//go:build !windows
+// +build !windows
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGPG writes a shell script that mimics gpg --list-keys on a keyring
+// with n keys. Every invocation is recorded in the returned log file.
+func fakeGPG(t testing.TB, n int) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls.log")
+
+	full := &bytes.Buffer{}
+	for i := 0; i < n; i++ {
+		fmt.Fprint(full, fakeKey(i))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "all"), full.Bytes(), 0600))
+	for i := 0; i < n && i < 100; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fakeFP(i)), []byte(fakeKey(i)), 0600))
+	}
+
+	// without any search terms the whole keyring is listed. Otherwise only
+	// the requested (known) keys are printed.
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$*" >> %[1]s
+last=""
+for a in "$@"; do
+  last="$a"
+done
+case "$last" in
+  --list-*) cat %[2]s/all; exit 0 ;;
+esac
+rc=0
+found=0
+for a in "$@"; do
+  case "$a" in
+    --*) continue ;;
+    *!) continue ;;
+  esac
+  if [ -f "%[2]s/$a" ]; then
+    cat "%[2]s/$a"
+    found=1
+  else
+    rc=2
+  fi
+done
+exit $rc
+`, log, dir)
+	bin := filepath.Join(dir, "gpg")
+	require.NoError(t, os.WriteFile(bin, []byte(script), 0700))
+
+	return bin, log
+}
+
+func fakeFP(i int) string {
+	return fmt.Sprintf("%040X", i+1)
+}
+
+func fakeKey(i int) string {
+	fp := fakeFP(i)
+	return fmt.Sprintf(`pub:u:4096:1:%[1]s:1528124458:::u:::scESC::::::23::0:
+fpr:::::::::%[2]s:
+uid:u::::1528124458::%[1]s::User %[3]d <user%[3]d@example.com>::::::::::0:
+sub:u:4096:1:%[4]s:1528124458::::::e::::::23:
+fpr:::::::::%[4]s:
+grp:::::::::%[5]s:
+`, fp[24:], fp, i, strings.Repeat("A", 16), fakeGrip(i))
+}
+
+func fakeGrip(i int) string {
+	return fmt.Sprintf("%040X", i+0x1000)
+}
+
+func calls(t testing.TB, log string) int {
+	t.Helper()
+
+	buf, err := os.ReadFile(log)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	require.NoError(t, err)
+	return strings.Count(string(buf), "\n")
+}
+
+func newFakeGPG(t testing.TB, n int) (*GPG, string) {
+	t.Helper()
+
+	bin, log := fakeGPG(t, n)
+	lc, err := lru.New2Q(16)
+	require.NoError(t, err)
+
+	g := &GPG{binary: bin, listCache: lc}
+	// probing the fake binary would show up in the call log.
+	g.capsOnce.Do(func() {
+		g.caps = gpgconf.Capabilities{PinentryMode: true, SecretKeysInAgent: true, ColonFingerprints: true, Keybox: true, QuickCommands: true}
+	})
+
+	return g, log
+}
+
+func TestLookupKeys(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("single invocation", func(t *testing.T) {
+		g, log := newFakeGPG(t, 10)
+		keys := g.lookupKeys(ctx, "public", fakeFP(1), fakeFP(2), fakeFP(3))
+		assert.Len(t, keys, 3)
+		assert.Equal(t, fakeFP(2), keys[fakeFP(2)][0].Fingerprint)
+		assert.Equal(t, 1, calls(t, log))
+	})
+
+	t.Run("fallback on missing key", func(t *testing.T) {
+		g, log := newFakeGPG(t, 10)
+		keys := g.lookupKeys(ctx, "public", fakeFP(1), "0xDEADBEEF", fakeFP(3))
+		assert.Len(t, keys, 2)
+		assert.NotContains(t, keys, "0xDEADBEEF")
+		assert.Equal(t, fakeFP(3), keys[fakeFP(3)][0].Fingerprint)
+		// one batch lookup plus one lookup per key
+		assert.Equal(t, 4, calls(t, log))
+	})
+
+	t.Run("bounded batches", func(t *testing.T) {
+		g, log := newFakeGPG(t, maxListKeys+6)
+		ids := make([]string, 0, maxListKeys+6)
+		for i := 0; i < maxListKeys+6; i++ {
+			ids = append(ids, fakeFP(i))
+		}
+		keys := g.lookupKeys(ctx, "public", ids...)
+		assert.Len(t, keys, maxListKeys+6)
+		assert.Equal(t, 2, calls(t, log))
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		g, log := newFakeGPG(t, 10)
+		assert.Len(t, g.lookupKeys(ctx, "public"), 0)
+		assert.Equal(t, 0, calls(t, log))
+	})
+}
+
+func TestEncryptChecksRecipientsOnce(t *testing.T) {
+	ctx := context.Background()
+
+	g, log := newFakeGPG(t, 10)
+	_, err := g.Encrypt(ctx, []byte("foo"), []string{fakeFP(1), fakeFP(2), fakeFP(3)})
+	assert.NoError(t, err)
+	// one key lookup and the actual encryption
+	assert.Equal(t, 2, calls(t, log))
+
+	// the encryption subkey is selected explicitly
+	buf, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "--recipient "+strings.Repeat("A", 16)+"!")
+}
+
+func TestEncryptHiddenRecipients(t *testing.T) {
+	ctx := context.Background()
+
+	g, log := newFakeGPG(t, 10)
+	_, err := g.Encrypt(ctx, []byte("foo"), []string{fakeFP(1)})
+	assert.NoError(t, err)
+	buf, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.NotContains(t, string(buf), "--throw-keyids")
+
+	g, log = newFakeGPG(t, 10)
+	_, err = g.Encrypt(gpg.WithThrowKeyIDs(ctx, true), []byte("foo"), []string{fakeFP(1)})
+	assert.NoError(t, err)
+	buf, err = os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "--throw-keyids")
+}
+
+func TestEncryptPreferences(t *testing.T) {
+	ctx := context.Background()
+	ctx = gpg.WithCompression(ctx, "zlib")
+	ctx = gpg.WithCipherPrefs(ctx, "aes256")
+	ctx = gpg.WithDigestPrefs(ctx, "sha512")
+
+	g, log := newFakeGPG(t, 10)
+	_, err := g.Encrypt(ctx, []byte("foo"), []string{fakeFP(1)})
+	assert.NoError(t, err)
+	buf, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "--compress-algo=zlib")
+	assert.Contains(t, string(buf), "--personal-cipher-preferences=aes256")
+	assert.Contains(t, string(buf), "--personal-digest-preferences=sha512")
+}
+
+// BenchmarkListRecipients lists the full keyring, like commands that need
+// to display all available keys do.
+func BenchmarkListRecipients(b *testing.B) {
+	ctx := context.Background()
+	g, _ := newFakeGPG(b, 5000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.listCache.Purge()
+		_, err := g.listKeys(ctx, "public")
+		require.NoError(b, err)
+	}
+}
+
+// BenchmarkFindRecipients only looks up the keys that are actually needed.
+func BenchmarkFindRecipients(b *testing.B) {
+	ctx := context.Background()
+	g, _ := newFakeGPG(b, 5000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.listCache.Purge()
+		_, err := g.FindRecipients(ctx, fakeFP(1), fakeFP(2))
+		require.NoError(b, err)
+	}
+}
+
+func TestListArgs(t *testing.T) {
+	ctx := context.Background()
+
+	g, log := newFakeGPG(t, 1)
+	args := g.listArgs(ctx, "public")
+	assert.NotContains(t, args, "--with-fingerprint")
+	assert.NotContains(t, args, "--with-keygrip")
+	assert.Equal(t, "--list-public-keys", args[len(args)-1])
+	assert.Contains(t, g.listArgs(ctx, "secret"), "--with-keygrip")
+
+	kl, err := g.listKeys(ctx, "public")
+	require.NoError(t, err)
+	assert.Equal(t, fakeFP(0), kl[0].Fingerprint)
+	assert.Equal(t, 1, calls(t, log))
+
+	g = &GPG{}
+	g.capsOnce.Do(func() {
+		g.caps = gpgconf.Capabilities{}
+	})
+	assert.Contains(t, g.listArgs(ctx, "secret"), "--with-fingerprint")
+	assert.NotContains(t, g.listArgs(ctx, "secret"), "--with-keygrip")
+}
+
+func TestUnlock(t *testing.T) {
+	ctx := context.Background()
+
+	// fake gpg-connect-agent that knows key 0 (cached), 1 (locked) and
+	// 2 (no passphrase).
+	dir := t.TempDir()
+	script := fmt.Sprintf(`#!/bin/sh
+echo "S KEYINFO %s D - - 1 P - - -"
+echo "S KEYINFO %s D - - - P - - -"
+echo "S KEYINFO %s D - - - C - - -"
+echo "OK"
+`, fakeGrip(0), fakeGrip(1), fakeGrip(2))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gpg-connect-agent"), []byte(script), 0o700))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for _, tc := range []struct {
+		name  string
+		ids   []string
+		calls int
+	}{
+		{name: "cached", ids: []string{fakeFP(0)}, calls: 1},
+		{name: "no passphrase", ids: []string{fakeFP(2)}, calls: 1},
+		{name: "unknown to agent", ids: []string{fakeFP(3)}, calls: 1},
+		// lookup, recipient check, encrypt and decrypt
+		{name: "locked", ids: []string{fakeFP(1)}, calls: 4},
+		{name: "locked once", ids: []string{fakeFP(1), fakeFP(0), fakeFP(1)}, calls: 4},
+		{name: "none", calls: 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g, log := newFakeGPG(t, 5)
+			require.NoError(t, g.Unlock(ctx, tc.ids...))
+			assert.Equal(t, tc.calls, calls(t, log))
+		})
+	}
+}
+
+func TestKeyringArgs(t *testing.T) {
+	ctx := context.Background()
+	td := t.TempDir()
+	ctx = gpg.WithHomedir(ctx, td)
+
+	g, _ := newFakeGPG(t, 1)
+	assert.Empty(t, g.keyringArgs(ctx))
+	require.NoError(t, g.CheckKeyring(ctx))
+
+	require.NoError(t, os.WriteFile(filepath.Join(td, "pubring.kbx"), []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "pubring.gpg"), []byte("x"), 0o600))
+	assert.Equal(t, []string{"--keyring", filepath.Join(td, "pubring.gpg")}, g.keyringArgs(ctx))
+	assert.Contains(t, g.listArgs(ctx, "public"), "--keyring")
+	assert.NotContains(t, g.listArgs(ctx, "secret"), "--keyring")
+	assert.Contains(t, g.encryptArgs(ctx, nil), "--keyring")
+	require.NoError(t, g.CheckKeyring(ctx))
+
+	// gpg 1.x can't read the keybox
+	require.NoError(t, os.Remove(filepath.Join(td, "pubring.gpg")))
+	g.caps.Keybox = false
+	assert.Empty(t, g.keyringArgs(ctx))
+	assert.Error(t, g.CheckKeyring(ctx))
+	_, err := g.listKeys(ctx, "public")
+	assert.Error(t, err)
+}
+
+func TestTrustKey(t *testing.T) {
+	ctx := context.Background()
+
+	g, log := newFakeGPG(t, 1)
+	fp := fakeFP(0)
+	// the fake treats every argument as a key to look up
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(g.binary), "good"), nil, 0o600))
+
+	require.NoError(t, g.SignKey(ctx, fp))
+	require.NoError(t, g.SetOwnerTrust(ctx, fp, "full"))
+	require.NoError(t, g.SetTOFUPolicy(ctx, fp, "good"))
+	require.NoError(t, g.UntrustKey(ctx, fp))
+	assert.Error(t, g.SetOwnerTrust(ctx, fp, "some"))
+	assert.Error(t, g.SetTOFUPolicy(ctx, fp, "some"))
+
+	buf, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "--quick-lsign-key "+fp)
+	assert.Contains(t, string(buf), "--tofu-policy good "+fp)
+	assert.Equal(t, 2, strings.Count(string(buf), "--import-ownertrust"))
+
+	g.caps.QuickCommands = false
+	assert.Error(t, g.SignKey(ctx, fp))
+}
+
+func TestTrustModelArgs(t *testing.T) {
+	ctx := context.Background()
+
+	g, _ := newFakeGPG(t, 1)
+	assert.NotContains(t, g.listArgs(ctx, "public"), "--trust-model=pgp")
+	assert.NotContains(t, g.encryptArgs(ctx, nil), "--trust-model=always")
+
+	ctx = gpg.WithTrustModel(ctx, gpg.TrustAlways)
+	assert.Contains(t, g.encryptArgs(ctx, nil), "--trust-model=always")
+	assert.NotContains(t, g.listArgs(ctx, "public"), "--trust-model=always")
+
+	ctx = gpg.WithTrustModel(ctx, gpg.TrustTOFUPGP)
+	assert.Contains(t, g.encryptArgs(ctx, nil), "--trust-model=tofu+pgp")
+	assert.Contains(t, g.listArgs(ctx, "public"), "--trust-model=tofu+pgp")
+}
+
+func TestKeyInfo(t *testing.T) {
+	ctx := context.Background()
+
+	g, _ := newFakeGPG(t, 3)
+	k, secret, err := g.KeyInfo(ctx, fakeFP(1))
+	require.NoError(t, err)
+	assert.Equal(t, fakeFP(1), k.Fingerprint)
+	assert.Equal(t, "User 1 <user1@example.com>", k.Identity().ID())
+	assert.True(t, secret)
+
+	_, _, err = g.KeyInfo(ctx, "0xDEADBEEF")
+	assert.Error(t, err)
+}
@@ -4,12 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/gopasspw/gopass/internal/backend"
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
@@ -23,9 +24,9 @@ func (g *GPG) ListRecipients(ctx context.Context) ([]string, error) {
 		g.pubKeys = kl
 	}
 	if gpg.IsAlwaysTrust(ctx) {
-		return g.pubKeys.Recipients(), nil
+		return g.pubKeys.Fingerprints(), nil
 	}
-	return g.pubKeys.UseableKeys(gpg.IsAlwaysTrust(ctx)).Recipients(), nil
+	return g.pubKeys.UseableKeys(gpg.IsAlwaysTrust(ctx)).Fingerprints(), nil
 }
 
 // FindRecipients searches for the given public keys.
@@ -35,33 +36,67 @@ func (g *GPG) FindRecipients(ctx context.Context, search ...string) ([]string, e
 		return nil, err
 	}
 
-	recp := kl.UseableKeys(gpg.IsAlwaysTrust(ctx)).Recipients()
+	warnCollisions(ctx, kl, search)
+
+	recp := kl.UseableKeys(gpg.IsAlwaysTrust(ctx)).Fingerprints()
 	if gpg.IsAlwaysTrust(ctx) {
-		recp = kl.Recipients()
+		recp = kl.Fingerprints()
 	}
 
-	debug.Log("found useable keys for %q: %q (all: %q)", search, recp, kl.Recipients())
+	debug.Log("found useable keys for %q: %q (all: %q)", search, recp, kl.Fingerprints())
 	return recp, nil
 }
 
-// RecipientIDs returns a list of recipient IDs for a given encrypted blob.
-func (g *GPG) RecipientIDs(ctx context.Context, buf []byte) ([]string, error) {
-	// switch to LANG C for more predictable output, switch back later
-	oldLang := os.Getenv("LANGUAGE")
-	if err := os.Setenv("LANGUAGE", "C"); err == nil {
-		defer os.Setenv("LANGUAGE", oldLang)
+// warnCollisions warns if any of the given 32-bit key IDs matches more than
+// one key. Creating a key with a given short key ID is cheap, so gpg might
+// end up encrypting to a key the user never intended to use.
+func warnCollisions(ctx context.Context, kl gpg.KeyList, search []string) {
+	for _, id := range search {
+		if !gpg.IsShortKeyID(id) {
+			continue
+		}
+		var matches []string
+		for _, k := range kl {
+			if gpg.SameKey(k.Fingerprint, id) {
+				matches = append(matches, k.Fingerprint)
+			}
+		}
+		if len(matches) < 2 {
+			continue
+		}
+		out.Warningf(ctx, "Short key ID %s matches %d keys (%s). Please use the full fingerprint instead. Run 'gopass recipients migrate' to update the recipients.", id, len(matches), strings.Join(matches, ", "))
 	}
+}
 
-	recp := make([]string, 0, 5)
+// RecipientIDs returns a list of recipient IDs for a given encrypted blob.
+func (g *GPG) RecipientIDs(ctx context.Context, buf []byte) ([]string, error) {
+	defer timing.Start(ctx, timing.Keys)()
+	kids := make([]string, 0, 5)
+	hidden := 0
 
 	// extract recipients from gpg output
-	args := []string{"--batch", "--list-only", "--list-packets", "--no-default-keyring", "--secret-keyring", "/dev/null"}
-	cmd := exec.CommandContext(ctx, g.binary, args...)
-	cmd.Stdin = bytes.NewReader(buf)
-	debug.Log("%s %+v", cmd.Path, cmd.Args)
-
-	cmdout, err := cmd.CombinedOutput()
-	if err != nil {
+	args := []string{"--batch", "--list-only", "--list-packets"}
+	if !g.capabilities(ctx).SecretKeysInAgent {
+		// gpg < 2.1 would otherwise try to use the secret keys. Newer
+		// versions consider --secret-keyring obsolete.
+		args = append(args, "--no-default-keyring", "--secret-keyring", "/dev/null")
+	}
+	tctx, cancel := g.timeout(ctx, false)
+	defer cancel()
+
+	var cmdout []byte
+	if err := retry(tctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(tctx, g.binary, args...)
+		// the output is parsed, so make sure it's not translated
+		cmd.Env = g.env(tctx, "LANGUAGE=C", "LC_ALL=C")
+		cmd.Stdin = bytes.NewReader(buf)
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+
+		var err error
+		cmd.Stderr = stderr
+		cmdout, err = cmd.Output()
+		return err
+	}); err != nil {
 		return []string{}, err
 	}
 
@@ -80,29 +115,47 @@ func (g *GPG) RecipientIDs(ctx context.Context, buf []byte) ([]string, error) {
 			continue
 		}
 
-		kl, err := g.listKeys(ctx, "public", keyid)
-		if err != nil || len(kl) < 1 {
+		if strings.Trim(keyid, "0") == "" {
+			// anonymous recipient, see --throw-keyids
+			hidden++
 			continue
 		}
 
+		kids = append(kids, keyid)
+	}
+
+	keys := g.lookupKeys(ctx, "public", kids...)
+	recp := make([]string, 0, len(kids))
+	for _, kid := range kids {
+		kl := keys[kid]
+		if len(kl) < 1 {
+			continue
+		}
 		recp = append(recp, kl[0].Fingerprint)
 	}
 
-	if g.throwKids {
-		out.Warningf(ctx, "gpg option throw-keyids is set. some features might not work.")
+	if hidden > 0 {
+		debug.Log("ciphertext has %d hidden recipients", hidden)
+		return recp, backend.ErrHiddenRecipients
 	}
 	return recp, nil
 }
 
+// splitPacket parses the attributes of a packet as printed by
+// gpg --list-packets, e.g.
+// ":pubkey enc packet: version 3, algo 1, keyid 00F0FF00FFC00F0F".
 func splitPacket(in string) map[string]string {
 	m := make(map[string]string, 3)
-	p := strings.Split(in, ":")
+	p := strings.SplitN(in, ":", 3)
 	if len(p) < 3 {
 		return m
 	}
-	p = strings.Split(strings.TrimSpace(p[2]), " ")
-	for i := 0; i+1 < len(p); i += 2 {
-		m[p[i]] = strings.Trim(p[i+1], ",")
+	for _, attr := range strings.Split(p[2], ",") {
+		k, v, found := strings.Cut(strings.TrimSpace(attr), " ")
+		if !found || k == "" {
+			continue
+		}
+		m[k] = strings.TrimSpace(v)
 	}
 	return m
 }
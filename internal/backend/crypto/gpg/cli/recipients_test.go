@@ -1,11 +1,39 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/out"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestWarnCollisions(t *testing.T) {
+	ctx := context.Background()
+
+	buf := &bytes.Buffer{}
+	out.Stderr = buf
+	defer func() {
+		out.Stderr = os.Stderr
+	}()
+
+	kl := gpg.KeyList{
+		{Fingerprint: "25FF1614B8F87B52FFFF99B962AF4031C82E0039"},
+		{Fingerprint: "A5B6C7D8E9F0A1B2C3D4E5F6A7B8C9D0C82E0039"},
+		{Fingerprint: "25FF1614B8F87B52FFFF99B962AF4031DEADBEEF"},
+	}
+
+	warnCollisions(ctx, kl, []string{"0xDEADBEEF", "62AF4031C82E0039", "john@example.org"})
+	assert.Equal(t, "", buf.String())
+
+	warnCollisions(ctx, kl, []string{"0xc82e0039"})
+	assert.Contains(t, buf.String(), "Short key ID 0xc82e0039 matches 2 keys")
+}
+
 func TestSplitPacket(t *testing.T) {
 	for in, out := range map[string]map[string]string{
 		"": {},
@@ -15,7 +43,40 @@ func TestSplitPacket(t *testing.T) {
 			"version": "3",
 		},
 		":encrypted data packet:": {},
+		":pubkey enc packet: version 3, algo 18, keyid 0000000000000000": {
+			"algo":    "18",
+			"keyid":   "0000000000000000",
+			"version": "3",
+		},
+		":pubkey enc packet:version 3,algo 1,  keyid  00F0FF00FFC00F0F ,": {
+			"algo":    "1",
+			"keyid":   "00F0FF00FFC00F0F",
+			"version": "3",
+		},
+		":symkey enc packet: version 4, cipher 9, aead 0, s2k 3, hash 2, seskey 256 bits": {
+			"version": "4",
+			"cipher":  "9",
+			"aead":    "0",
+			"s2k":     "3",
+			"hash":    "2",
+			"seskey":  "256 bits",
+		},
+		"gpg: encrypted with 2048-bit RSA key, ID 00F0FF00FFC00F0F": {},
 	} {
 		assert.Equal(t, out, splitPacket(in))
 	}
 }
+
+func FuzzSplitPacket(f *testing.F) {
+	f.Add(":pubkey enc packet: version 3, algo 1, keyid 00F0FF00FFC00F0F")
+	f.Add(":encrypted data packet:")
+	f.Add(":symkey enc packet: version 4, cipher 9, aead 0, s2k 3, hash 2, seskey 256 bits")
+
+	f.Fuzz(func(t *testing.T, in string) {
+		for k, v := range splitPacket(in) {
+			assert.NotEmpty(t, k)
+			assert.NotContains(t, k, " ")
+			assert.Equal(t, strings.TrimSpace(v), v)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Sign creates a detached signature of data with the default key.
+func (g *GPG) Sign(ctx context.Context, data []byte) ([]byte, error) {
+	args := append(g.args, "--detach-sign")
+
+	ctx, cancel := g.timeout(ctx, true)
+	defer cancel()
+
+	var sig []byte
+	err := retry(ctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Stderr = stderr
+
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+		var err error
+		sig, err = cmd.Output()
+		return err
+	})
+	return sig, err
+}
+
+// Verify checks a detached signature of data and returns the fingerprint of
+// the signing key.
+func (g *GPG) Verify(ctx context.Context, data, sig []byte) (string, error) {
+	// gpg can only read one of data and signature from stdin
+	fh, err := os.CreateTemp("", "gopass-sig-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(fh.Name())
+	}()
+	if _, err := fh.Write(sig); err != nil {
+		_ = fh.Close()
+		return "", fmt.Errorf("failed to write signature: %w", err)
+	}
+	if err := fh.Close(); err != nil {
+		return "", fmt.Errorf("failed to write signature: %w", err)
+	}
+
+	args := append(g.args, "--status-fd", "1", "--verify", fh.Name(), "-")
+
+	ctx, cancel := g.timeout(ctx, false)
+	defer cancel()
+
+	var status []byte
+	if err := retry(ctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Stderr = stderr
+
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+		var err error
+		status, err = cmd.Output()
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	return parseValidSig(status)
+}
+
+// parseValidSig returns the fingerprint from the VALIDSIG status line.
+func parseValidSig(status []byte) (string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(status))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 3 || fields[0] != "[GNUPG:]" || fields[1] != "VALIDSIG" {
+			continue
+		}
+		// the last field is the fingerprint of the primary key
+		return fields[len(fields)-1], nil
+	}
+
+	return "", fmt.Errorf("no valid signature found")
+}
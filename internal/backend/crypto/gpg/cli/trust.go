@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// SignKey certifies the given key with a local (non-exportable) signature,
+// so it's valid in the pgp trust model. gpg might ask for the passphrase of
+// the signing key.
+func (g *GPG) SignKey(ctx context.Context, id string) error {
+	if !g.capabilities(ctx).QuickCommands {
+		return fmt.Errorf("signing keys requires gpg 2.1 or newer")
+	}
+	fp, err := g.keyFingerprint(ctx, id)
+	if err != nil {
+		return err
+	}
+	return g.keyCommand(ctx, true, "", "--quick-lsign-key", fp)
+}
+
+// SetOwnerTrust sets the owner trust of the given key to one of the
+// gpg.OwnerTrustLevels, e.g. full.
+func (g *GPG) SetOwnerTrust(ctx context.Context, id, level string) error {
+	v, found := gpg.OwnerTrustLevels[level]
+	if !found {
+		return fmt.Errorf("unknown owner trust level %q", level)
+	}
+	fp, err := g.keyFingerprint(ctx, id)
+	if err != nil {
+		return err
+	}
+	return g.keyCommand(ctx, false, fmt.Sprintf("%s:%d:\n", fp, v), "--import-ownertrust")
+}
+
+// SetTOFUPolicy sets the TOFU policy of the given key, e.g. good, for the
+// tofu and tofu+pgp trust models.
+func (g *GPG) SetTOFUPolicy(ctx context.Context, id, policy string) error {
+	switch policy {
+	case "auto", "good", "unknown", "bad", "ask":
+	default:
+		return fmt.Errorf("unknown TOFU policy %q", policy)
+	}
+	fp, err := g.keyFingerprint(ctx, id)
+	if err != nil {
+		return err
+	}
+	return g.keyCommand(ctx, false, "", "--tofu-policy", policy, fp)
+}
+
+func (g *GPG) keyFingerprint(ctx context.Context, id string) (string, error) {
+	fp := strings.TrimPrefix(g.Fingerprint(ctx, id), "0x")
+	if fp == "" {
+		return "", fmt.Errorf("key %s not found", id)
+	}
+	return fp, nil
+}
+
+// keyCommand runs a gpg command that modifies the keyring or the trust
+// database and clears the key cache afterwards. stdin is passed to gpg if
+// not empty.
+func (g *GPG) keyCommand(ctx context.Context, interactive bool, stdin string, cmdArgs ...string) error {
+	args := append(g.args, cmdArgs...)
+
+	ctx, cancel := g.timeout(ctx, interactive)
+	defer cancel()
+
+	if err := retry(ctx, func(stderr *bytes.Buffer) error {
+		cmd := exec.CommandContext(ctx, g.binary, args...)
+		cmd.Env = g.env(ctx)
+		if stdin != "" {
+			cmd.Stdin = strings.NewReader(stdin)
+		}
+		cmd.Stderr = stderr
+
+		debug.Log("%s %+v", cmd.Path, cmd.Args)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run command: '%s %+v': %w", cmd.Path, cmd.Args, err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// clear key cache
+	g.privKeys = nil
+	g.pubKeys = nil
+	return nil
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg/gpgconf"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// unlockMessage is encrypted and decrypted again to make gpg-agent ask for
+// a passphrase.
+const unlockMessage = "gopass unlock"
+
+// Unlock makes sure gpg-agent has cached the passphrases of the given
+// identities. It asks once for each key that is still locked, so a following
+// batch of decryptions doesn't prompt for every single secret. Keys without
+// a passphrase or unknown to gpg-agent (e.g. on a smartcard) are skipped.
+// If gpg-agent can't tell which keys are locked nothing is done.
+func (g *GPG) Unlock(ctx context.Context, ids ...string) error {
+	if len(ids) < 1 || !g.capabilities(ctx).SecretKeysInAgent {
+		return nil
+	}
+
+	infos, err := gpgconf.AgentKeyInfo(ctx, g.homedir(ctx))
+	if err != nil {
+		debug.Log("failed to check the passphrase cache: %s", err)
+		return nil
+	}
+
+	keys := g.lookupKeys(ctx, "secret", ids...)
+	done := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		kl := keys[id]
+		if len(kl) < 1 {
+			continue
+		}
+		grip := kl[0].EncryptionKeygrip()
+		if info, found := infos[grip]; !found || !info.Protected || info.Cached || done[grip] {
+			continue
+		}
+		done[grip] = true
+
+		debug.Log("passphrase of %s (keygrip %s) is not cached", id, grip)
+		if err := g.unlock(ctx, kl[0].Fingerprint); err != nil {
+			return fmt.Errorf("failed to unlock %s: %w", kl[0].OneLine(), err)
+		}
+	}
+
+	return nil
+}
+
+// unlock triggers a passphrase prompt for the given key by decrypting a
+// message encrypted for it.
+func (g *GPG) unlock(ctx context.Context, fp string) error {
+	ciphertext, err := g.Encrypt(ctx, []byte(unlockMessage), []string{fp})
+	if err != nil {
+		return err
+	}
+
+	// only try this key, even if the recipients are hidden.
+	_, err = g.Decrypt(gpg.WithTrySecretKeys(ctx, []string{fp}), ciphertext)
+	return err
+}
+
+// IsUnlocked implements backend.PassphraseUnlocker. It returns true if
+// decrypting for the identity doesn't need a passphrase, i.e. the key isn't
+// protected or gpg-agent has cached the passphrase.
+func (g *GPG) IsUnlocked(ctx context.Context, id string) bool {
+	if !g.capabilities(ctx).SecretKeysInAgent {
+		return false
+	}
+
+	infos, err := gpgconf.AgentKeyInfo(ctx, g.homedir(ctx))
+	if err != nil {
+		debug.Log("failed to check the passphrase cache: %s", err)
+		return false
+	}
+
+	kl := g.lookupKeys(ctx, "secret", id)[id]
+	if len(kl) < 1 {
+		return false
+	}
+	info, found := infos[kl[0].EncryptionKeygrip()]
+	return found && (!info.Protected || info.Cached)
+}
+
+// UnlockWithPassphrase implements backend.PassphraseUnlocker. It decrypts a
+// test message with the given passphrase instead of asking pinentry. If the
+// passphrase is right gpg-agent caches it, so the following decryptions
+// don't prompt again.
+func (g *GPG) UnlockWithPassphrase(ctx context.Context, id string, passphrase []byte) error {
+	kl := g.lookupKeys(ctx, "secret", id)[id]
+	if len(kl) < 1 {
+		return fmt.Errorf("no secret key found for %s", id)
+	}
+	fp := kl[0].Fingerprint
+
+	ciphertext, err := g.Encrypt(ctx, []byte(unlockMessage), []string{fp})
+	if err != nil {
+		return err
+	}
+
+	// stdin is used for the passphrase, so the message has to be passed in a
+	// file. It's not secret.
+	fh, err := os.CreateTemp("", "gopass-unlock-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(fh.Name())
+	}()
+	if _, err := fh.Write(ciphertext); err != nil {
+		_ = fh.Close()
+		return err
+	}
+	if err := fh.Close(); err != nil {
+		return err
+	}
+
+	args := append(g.args, "--batch", "--pinentry-mode", "loopback", "--passphrase-fd", "0", "--try-secret-key", fp, "--decrypt", fh.Name())
+
+	ctx, cancel := g.timeout(ctx, false)
+	defer cancel()
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, g.binary, args...)
+	cmd.Env = g.env(ctx)
+	cmd.Stdin = bytes.NewReader(append(append([]byte{}, passphrase...), '\n'))
+	cmd.Stderr = stderr
+
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	if _, err := cmd.Output(); err != nil {
+		return fmt.Errorf("failed to unlock %s: %w: %s", kl[0].OneLine(), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -3,24 +3,12 @@ package colons
 import (
 	"bufio"
 	"io"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 )
 
-var (
-	// nolint:godot
-	// John Doe (user) <john.doe@example.com>
-	reUIDComment = regexp.MustCompile(`([^(<]+)\s+(\([^)]+\))\s+<([^>]+)>`)
-	// nolint:godot
-	// John Doe <john.doe@example.com>
-	reUID = regexp.MustCompile(`([^(<]+)\s+<([^>]+)>`)
-	// nolint:godot
-	// John Doe (user)
-	reUIDNoEmailComment = regexp.MustCompile(`([^(<]+)\s+(\([^)]+\))`)
-)
-
 // http://git.gnupg.org/cgi-bin/gitweb.cgi?p=gnupg.git;a=blob_plain;f=doc/DETAILS
 // Fields:
 // 0 - Type of record
@@ -37,7 +25,7 @@ var (
 //     rev - Revocation Signature
 //     fpr - Fingerprint (field 9)
 //     pkd - Public Key Data
-//     grp - Keygrip
+//     grp - Keygrip (field 9)
 //     rvk - Revocation KEy
 //     tfs - TOFU stats
 //     tru - Trust database info
@@ -59,18 +47,29 @@ var (
 // 14 - S/N of a token
 // 15 - Hash algo (2 - SHA-1, 8 - SHA-256)
 // 16 - Curve Name
+//
+// Records of unknown types or with missing fields are tolerated. Records that
+// are not relevant for gopass (e.g. uat, sig, tru) are skipped.
+
+// minFields is the number of fields the parser accesses. Shorter records,
+// e.g. from older gpg versions, are padded.
+const minFields = 17
 
 // Parse parses the `--with-colons` output format of GPG.
 func Parse(reader io.Reader) gpg.KeyList {
 	kl := make(gpg.KeyList, 0, 100)
 
 	scanner := bufio.NewScanner(reader)
+	// uid records can get very long.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
 	var cur gpg.Key
+	// the most recent pub, sec, sub or ssb record. Only fingerprints and
+	// keygrips of these records are relevant.
+	var last, lastSub string
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		fields := strings.Split(line, ":")
+		fields := splitRecord(scanner.Text())
 
 		switch fields[0] {
 		case "pub":
@@ -83,28 +82,70 @@ func Parse(reader io.Reader) gpg.KeyList {
 			if validity == "" && fields[0] == "sec" {
 				validity = "u"
 			}
+			last = fields[0]
 			cur = gpg.Key{
 				KeyType:        fields[0],
 				Validity:       validity,
 				KeyLength:      parseInt(fields[2]),
+				Algorithm:      algorithm(fields[3], fields[16]),
 				CreationDate:   parseTS(fields[5]),
 				ExpirationDate: parseTS(fields[6]),
 				Ownertrust:     fields[8],
-				Identities:     make(map[string]gpg.Identity, 1),
-				SubKeys:        make(map[string]struct{}, 1),
+				UIDs:           make(map[string]gpg.Identity, 1),
+				SubKeys:        make(map[string]gpg.SubKey, 1),
 				Caps:           parseKeyCaps(fields[11]),
+				Usage:          parseKeyUsage(fields[11]),
 			}
 		case "sub":
 			fallthrough
 		case "ssb":
-			cur.SubKeys[fields[4]] = struct{}{}
+			last = fields[0]
+			lastSub = fields[4]
+			if cur.SubKeys == nil {
+				continue
+			}
+			cur.SubKeys[fields[4]] = gpg.SubKey{
+				KeyLength:      parseInt(fields[2]),
+				Algorithm:      algorithm(fields[3], fields[16]),
+				Validity:       fields[1],
+				CreationDate:   parseTS(fields[5]),
+				ExpirationDate: parseTS(fields[6]),
+				Usage:          parseKeyUsage(fields[11]),
+			}
 		case "fpr":
-			if cur.Fingerprint == "" {
-				cur.Fingerprint = fields[9]
+			switch last {
+			case "pub", "sec":
+				if cur.Fingerprint == "" {
+					cur.Fingerprint = fields[9]
+				}
+			case "sub", "ssb":
+				if sk, found := cur.SubKeys[lastSub]; found && sk.Fingerprint == "" {
+					sk.Fingerprint = fields[9]
+					cur.SubKeys[lastSub] = sk
+				}
+			}
+		case "grp":
+			switch last {
+			case "pub", "sec":
+				if cur.Keygrip == "" {
+					cur.Keygrip = fields[9]
+				}
+			case "sub", "ssb":
+				if sk, found := cur.SubKeys[lastSub]; found && sk.Keygrip == "" {
+					sk.Keygrip = fields[9]
+					cur.SubKeys[lastSub] = sk
+				}
 			}
 		case "uid":
+			if cur.UIDs == nil {
+				continue
+			}
+			// older versions don't print the uid hash
 			sn := fields[7]
-			cur.Identities[sn] = parseColonIdentity(fields)
+			if sn == "" {
+				sn = fields[9]
+			}
+			cur.UIDs[sn] = parseColonIdentity(fields)
 		}
 	}
 
@@ -115,6 +156,49 @@ func Parse(reader io.Reader) gpg.KeyList {
 	return kl
 }
 
+// splitRecord splits a record into its fields. The result has at least
+// minFields fields.
+func splitRecord(line string) []string {
+	fields := strings.Split(strings.TrimSpace(line), ":")
+	if len(fields) < minFields {
+		fields = append(fields, make([]string, minFields-len(fields))...)
+	}
+	return fields
+}
+
+// parseKeyUsage parses the usage flags (lower case) of a single (sub) key.
+func parseKeyUsage(field string) gpg.Capabilities {
+	return gpg.Capabilities{
+		Encrypt:        strings.Contains(field, "e"),
+		Sign:           strings.Contains(field, "s"),
+		Certify:        strings.Contains(field, "c"),
+		Authentication: strings.Contains(field, "a"),
+	}
+}
+
+// algorithm returns a human readable name of the public key algorithm. For
+// ECC keys this is the curve name, e.g. ed25519 or cv25519.
+func algorithm(algo, curve string) string {
+	if curve != "" {
+		return curve
+	}
+	switch algo {
+	case "1", "2", "3":
+		return "rsa"
+	case "16", "20":
+		return "elg"
+	case "17":
+		return "dsa"
+	case "18":
+		return "ecdh"
+	case "19":
+		return "ecdsa"
+	case "22":
+		return "eddsa"
+	}
+	return algo
+}
+
 func parseKeyCaps(field string) gpg.Capabilities {
 	keycaps := gpg.Capabilities{}
 
@@ -138,37 +222,29 @@ func parseKeyCaps(field string) gpg.Capabilities {
 }
 
 func parseColonIdentity(fields []string) gpg.Identity {
-	for i, f := range fields {
-		fields[i] = strings.Replace(f, "\\x3a", ":", -1)
-	}
-	id := fields[9]
-	ni := gpg.Identity{
-		Name:           id,
-		CreationDate:   parseTS(fields[5]),
-		ExpirationDate: parseTS(fields[6]),
-	}
-	if reUIDComment.MatchString(id) {
-		if m := reUIDComment.FindStringSubmatch(id); len(m) > 3 {
-			ni.Name = m[1]
-			ni.Comment = strings.Trim(m[2], "()")
-			ni.Email = m[3]
-			return ni
-		}
-	}
-	if reUIDNoEmailComment.MatchString(id) {
-		if m := reUIDNoEmailComment.FindStringSubmatch(id); len(m) > 2 {
-			ni.Name = m[1]
-			ni.Comment = strings.Trim(m[2], "()")
-		}
-		return ni
+	id := gpg.ParseIdentity(unescape(fields[9]))
+	id.CreationDate = parseTS(fields[5])
+	id.ExpirationDate = parseTS(fields[6])
+	return id
+}
+
+// unescape decodes the C-style \xNN escapes gpg uses for colons, control
+// characters and (depending on the version) non-ASCII bytes.
+func unescape(in string) string {
+	if !strings.Contains(in, "\\x") {
+		return in
 	}
-	if reUID.MatchString(id) {
-		if m := reUID.FindStringSubmatch(id); len(m) > 2 {
-			ni.Name = m[1]
-			ni.Email = m[2]
-			return ni
+
+	out := make([]byte, 0, len(in))
+	for i := 0; i < len(in); i++ {
+		if in[i] == '\\' && i+3 < len(in) && in[i+1] == 'x' {
+			if b, err := strconv.ParseUint(in[i+2:i+4], 16, 8); err == nil {
+				out = append(out, byte(b))
+				i += 3
+				continue
+			}
 		}
+		out = append(out, in[i])
 	}
-
-	return ni
+	return string(out)
 }
@@ -1,12 +1,20 @@
 package colons
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var update = flag.Bool("update", false, "update golden files")
+
 func TestParseColonIdentity(t *testing.T) {
 	for _, tc := range []struct {
 		in      string
@@ -38,10 +46,130 @@ func TestParseColonIdentity(t *testing.T) {
 			comment: "user",
 			email:   "",
 		},
+		{
+			in:    "uid:u::::1792046359::17BE56653B5282F3E38AA23AF2C6C8B85C8D0D6C::<only.email@example.com>::::::::::0:",
+			email: "only.email@example.com",
+		},
+		{
+			in:    "uid:u::::1792046359::17BE56653B5282F3E38AA23AF2C6C8B85C8D0D6C::only.email@example.com::::::::::0:",
+			email: "only.email@example.com",
+		},
+		{
+			in:      "uid:u::::1792046359::2AB6DF5B28C8434F5546BB12FE7CBE2BE929CB06::Jürgen Müller (Büro ☕) <juergen@example.com>::::::::::0:",
+			name:    "Jürgen Müller",
+			comment: "Büro ☕",
+			email:   "juergen@example.com",
+		},
+		{
+			in:      "uid:u::::1792046359::E8044CA8BBE7BACEFEFB2808020969E2CC32B8E1::Colon\\x3a Name (with\\x3a colons) <colon@example.com>::::::::::0:",
+			name:    "Colon: Name",
+			comment: "with: colons",
+			email:   "colon@example.com",
+		},
+		{
+			in:      "uid:u::::1792046359::E8044CA8BBE7BACEFEFB2808020969E2CC32B8E1::J\\xc3\\xbcrgen (a (nested) comment)::::::::::0:",
+			name:    "Jürgen",
+			comment: "a (nested) comment",
+		},
+		{
+			in: "uid:u::::1792046359::",
+		},
 	} {
-		gi := parseColonIdentity(strings.Split(tc.in, ":"))
+		gi := parseColonIdentity(splitRecord(tc.in))
 		assert.Equal(t, tc.name, gi.Name)
 		assert.Equal(t, tc.comment, gi.Comment)
 		assert.Equal(t, tc.email, gi.Email)
 	}
 }
+
+func TestParseGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, fn := range files {
+		fn := fn
+		t.Run(filepath.Base(fn), func(t *testing.T) {
+			buf, err := os.ReadFile(fn)
+			require.NoError(t, err)
+
+			kl := Parse(bytes.NewReader(buf))
+			// the parsed timestamps use the local timezone
+			for i, k := range kl {
+				k.CreationDate = k.CreationDate.UTC()
+				k.ExpirationDate = k.ExpirationDate.UTC()
+				for sn, id := range k.UIDs {
+					id.CreationDate = id.CreationDate.UTC()
+					id.ExpirationDate = id.ExpirationDate.UTC()
+					k.UIDs[sn] = id
+				}
+				kl[i] = k
+			}
+			got, err := json.MarshalIndent(kl, "", "  ")
+			require.NoError(t, err)
+
+			gfn := strings.TrimSuffix(fn, ".txt") + ".golden"
+			if *update {
+				require.NoError(t, os.WriteFile(gfn, got, 0644))
+			}
+			want, err := os.ReadFile(gfn)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(want), string(got))
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, in := range []string{
+		"",
+		":",
+		"pub",
+		"pub:u:2048",
+		"uid:u::::1792046359::hash::John",
+		"sub:u:2048:1:4F8C2E1A9D6B3750",
+		"fpr:::::::::AEFC3F5B6CAD79A946D7F0FF83BB8B7E10B578CA:",
+		"pub:u:2048:1:83BB8B7E10B578CA:1441103821:::u:::scESC:\nfpr",
+		strings.Repeat("uid:", 100000),
+	} {
+		assert.NotPanics(t, func() {
+			Parse(strings.NewReader(in))
+		}, in)
+	}
+
+	kl := Parse(strings.NewReader("pub:u:2048:1:83BB8B7E10B578CA:1441103821:::u:::scESC:\nfpr:::::::::AEFC3F5B6CAD79A946D7F0FF83BB8B7E10B578CA\nuid"))
+	require.Len(t, kl, 1)
+	assert.Equal(t, "AEFC3F5B6CAD79A946D7F0FF83BB8B7E10B578CA", kl[0].Fingerprint)
+}
+
+func TestUnescape(t *testing.T) {
+	for in, out := range map[string]string{
+		"":                    "",
+		"foo":                 "foo",
+		"a\\x3ab":             "a:b",
+		"J\\xc3\\xbcrgen":     "Jürgen",
+		"\\x":                 "\\x",
+		"\\xzz":               "\\xzz",
+		"trailing\\x3":        "trailing\\x3",
+		"back\\x5cslash\\x3a": "back\\slash:",
+	} {
+		assert.Equal(t, out, unescape(in), in)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	require.NoError(f, err)
+	for _, fn := range files {
+		buf, err := os.ReadFile(fn)
+		require.NoError(f, err)
+		f.Add(buf)
+	}
+
+	f.Fuzz(func(t *testing.T, in []byte) {
+		for _, k := range Parse(bytes.NewReader(in)) {
+			// only complete keys must be returned
+			assert.NotEmpty(t, k.Fingerprint)
+			assert.Greater(t, k.KeyLength, 0)
+		}
+	})
+}
@@ -1,27 +1,63 @@
 package gpg
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type contextKey int
 
 const (
-	ctxKeyAlwaysTrust contextKey = iota
+	ctxKeyTrustModel contextKey = iota
 	ctxKeyUseCache
+	ctxKeyPersistentCache
+	ctxKeyThrowKeyIDs
+	ctxKeyTrySecretKeys
+	ctxKeyCompression
+	ctxKeyCipherPrefs
+	ctxKeyDigestPrefs
+	ctxKeyCommandTimeout
+	ctxKeyPinentryTimeout
+	ctxKeyHomedir
 )
 
-// WithAlwaysTrust will return a context with the flag for always trust set.
+const (
+	// DefaultCommandTimeout is the default timeout for gpg invocations that
+	// don't require any user interaction.
+	DefaultCommandTimeout = time.Minute
+	// DefaultPinentryTimeout is the default timeout for gpg invocations that
+	// might ask the user for a passphrase.
+	DefaultPinentryTimeout = 5 * time.Minute
+)
+
+// WithTrustModel returns a context with the trust model used to validate
+// public keys set. See TrustModels.
+func WithTrustModel(ctx context.Context, tm string) context.Context {
+	return context.WithValue(ctx, ctxKeyTrustModel, tm)
+}
+
+// GetTrustModel returns the trust model or TrustAuto if none is set.
+func GetTrustModel(ctx context.Context) string {
+	tm, ok := ctx.Value(ctxKeyTrustModel).(string)
+	if !ok || tm == "" {
+		return TrustAuto
+	}
+	return tm
+}
+
+// WithAlwaysTrust will return a context with the trust model set to always
+// or to the default of gpg.
 func WithAlwaysTrust(ctx context.Context, at bool) context.Context {
-	return context.WithValue(ctx, ctxKeyAlwaysTrust, at)
+	if at {
+		return WithTrustModel(ctx, TrustAlways)
+	}
+	return WithTrustModel(ctx, TrustAuto)
 }
 
-// IsAlwaysTrust will return the value of the always trust flag or the default
-// (false).
+// IsAlwaysTrust returns true if public keys are used without validating
+// them.
 func IsAlwaysTrust(ctx context.Context) bool {
-	bv, ok := ctx.Value(ctxKeyAlwaysTrust).(bool)
-	if !ok {
-		return false
-	}
-	return bv
+	return GetTrustModel(ctx) == TrustAlways
 }
 
 // WithUseCache returns a context with the value of NoCache set.
@@ -37,3 +73,146 @@ func UseCache(ctx context.Context) bool {
 	}
 	return nc
 }
+
+// WithPersistentCache returns a context with the value of persistent cache set.
+func WithPersistentCache(ctx context.Context, pc bool) context.Context {
+	return context.WithValue(ctx, ctxKeyPersistentCache, pc)
+}
+
+// IsPersistentCache returns true if key listings should be cached on disk.
+func IsPersistentCache(ctx context.Context) bool {
+	pc, ok := ctx.Value(ctxKeyPersistentCache).(bool)
+	if !ok {
+		return false
+	}
+	return pc
+}
+
+// WithThrowKeyIDs returns a context with the flag for hidden recipients set.
+func WithThrowKeyIDs(ctx context.Context, tk bool) context.Context {
+	return context.WithValue(ctx, ctxKeyThrowKeyIDs, tk)
+}
+
+// HasThrowKeyIDs returns true if a value for throw key IDs has been set in
+// this context.
+func HasThrowKeyIDs(ctx context.Context) bool {
+	_, ok := ctx.Value(ctxKeyThrowKeyIDs).(bool)
+	return ok
+}
+
+// IsThrowKeyIDs returns true if the key IDs of the recipients should be
+// removed from the ciphertext.
+func IsThrowKeyIDs(ctx context.Context) bool {
+	tk, ok := ctx.Value(ctxKeyThrowKeyIDs).(bool)
+	if !ok {
+		return false
+	}
+	return tk
+}
+
+// WithTrySecretKeys returns a context with the keys that should be used for
+// trial decryption of secrets with hidden recipients.
+func WithTrySecretKeys(ctx context.Context, keys []string) context.Context {
+	return context.WithValue(ctx, ctxKeyTrySecretKeys, keys)
+}
+
+// GetTrySecretKeys returns the keys that should be used for trial decryption.
+func GetTrySecretKeys(ctx context.Context) []string {
+	keys, ok := ctx.Value(ctxKeyTrySecretKeys).([]string)
+	if !ok {
+		return nil
+	}
+	return keys
+}
+
+// WithCompression returns a context with the compression algorithm set.
+func WithCompression(ctx context.Context, algo string) context.Context {
+	return context.WithValue(ctx, ctxKeyCompression, algo)
+}
+
+// GetCompression returns the compression algorithm to use for encryption or
+// an empty string if the default should be used.
+func GetCompression(ctx context.Context) string {
+	algo, ok := ctx.Value(ctxKeyCompression).(string)
+	if !ok {
+		return ""
+	}
+	return algo
+}
+
+// WithCipherPrefs returns a context with the preferred ciphers set.
+func WithCipherPrefs(ctx context.Context, prefs string) context.Context {
+	return context.WithValue(ctx, ctxKeyCipherPrefs, prefs)
+}
+
+// GetCipherPrefs returns the space separated list of preferred ciphers.
+func GetCipherPrefs(ctx context.Context) string {
+	prefs, ok := ctx.Value(ctxKeyCipherPrefs).(string)
+	if !ok {
+		return ""
+	}
+	return prefs
+}
+
+// WithDigestPrefs returns a context with the preferred digests set.
+func WithDigestPrefs(ctx context.Context, prefs string) context.Context {
+	return context.WithValue(ctx, ctxKeyDigestPrefs, prefs)
+}
+
+// GetDigestPrefs returns the space separated list of preferred digests.
+func GetDigestPrefs(ctx context.Context) string {
+	prefs, ok := ctx.Value(ctxKeyDigestPrefs).(string)
+	if !ok {
+		return ""
+	}
+	return prefs
+}
+
+// WithCommandTimeout returns a context with the timeout for non-interactive
+// gpg invocations set. A negative value disables the timeout.
+func WithCommandTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, ctxKeyCommandTimeout, d)
+}
+
+// GetCommandTimeout returns the timeout for non-interactive gpg invocations
+// or the default.
+func GetCommandTimeout(ctx context.Context) time.Duration {
+	d, ok := ctx.Value(ctxKeyCommandTimeout).(time.Duration)
+	if !ok || d == 0 {
+		return DefaultCommandTimeout
+	}
+	return d
+}
+
+// WithPinentryTimeout returns a context with the timeout for gpg invocations
+// that might ask for a passphrase set. A negative value disables the timeout.
+func WithPinentryTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, ctxKeyPinentryTimeout, d)
+}
+
+// GetPinentryTimeout returns the timeout for gpg invocations that might ask
+// for a passphrase or the default.
+func GetPinentryTimeout(ctx context.Context) time.Duration {
+	d, ok := ctx.Value(ctxKeyPinentryTimeout).(time.Duration)
+	if !ok || d == 0 {
+		return DefaultPinentryTimeout
+	}
+	return d
+}
+
+// WithHomedir returns a context with a custom GnuPG home directory set. It's
+// passed to gpg as GNUPGHOME, so different keyrings can be used without
+// changing the environment of the whole process.
+func WithHomedir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, ctxKeyHomedir, dir)
+}
+
+// GetHomedir returns the custom GnuPG home directory or an empty string if the
+// default should be used.
+func GetHomedir(ctx context.Context) string {
+	sv, ok := ctx.Value(ctxKeyHomedir).(string)
+	if !ok {
+		return ""
+	}
+	return sv
+}
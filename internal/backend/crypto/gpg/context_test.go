@@ -16,4 +16,57 @@ func TestAlwaysTrust(t *testing.T) {
 	if !IsAlwaysTrust(WithAlwaysTrust(ctx, true)) {
 		t.Errorf("AlwaysTrust should be true")
 	}
+
+	if IsAlwaysTrust(WithAlwaysTrust(ctx, false)) {
+		t.Errorf("AlwaysTrust should be false")
+	}
+}
+
+func TestTrustModel(t *testing.T) {
+	ctx := context.Background()
+
+	if tm := GetTrustModel(ctx); tm != TrustAuto {
+		t.Errorf("TrustModel should be auto, not %s", tm)
+	}
+
+	if tm := GetTrustModel(WithTrustModel(ctx, TrustTOFU)); tm != TrustTOFU {
+		t.Errorf("TrustModel should be tofu, not %s", tm)
+	}
+
+	if !IsAlwaysTrust(WithTrustModel(ctx, TrustAlways)) {
+		t.Errorf("AlwaysTrust should be true")
+	}
+
+	for _, tm := range TrustModels {
+		if err := ValidateTrustModel(tm); err != nil {
+			t.Errorf("%s should be valid: %s", tm, err)
+		}
+	}
+	if err := ValidateTrustModel("classic"); err == nil {
+		t.Errorf("classic should be invalid")
+	}
+}
+
+func TestPersistentCache(t *testing.T) {
+	ctx := context.Background()
+
+	if IsPersistentCache(ctx) {
+		t.Errorf("PersistentCache should be false")
+	}
+
+	if !IsPersistentCache(WithPersistentCache(ctx, true)) {
+		t.Errorf("PersistentCache should be true")
+	}
+}
+
+func TestHomedir(t *testing.T) {
+	ctx := context.Background()
+
+	if GetHomedir(ctx) != "" {
+		t.Errorf("Homedir should be empty")
+	}
+
+	if GetHomedir(WithHomedir(ctx, "/tmp/gnupg")) != "/tmp/gnupg" {
+		t.Errorf("Homedir should be /tmp/gnupg")
+	}
 }
@@ -0,0 +1,28 @@
+package gpg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is returned if a gpg invocation failed. It carries whatever gpg
+// printed to stderr so the caller can decide if and how to show it, e.g.
+// the CLI prints it as part of the error message while other frontends
+// might not have a terminal at all.
+type Error struct {
+	Err    error
+	Stderr string
+}
+
+func (e *Error) Error() string {
+	msg := strings.TrimSpace(e.Stderr)
+	if msg == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Err, msg)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,27 @@
+package gpg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	err := &Error{Err: fmt.Errorf("exit status 2")}
+	assert.Equal(t, "exit status 2", err.Error())
+
+	err = &Error{
+		Err:    fmt.Errorf("exit status 2"),
+		Stderr: "gpg: decryption failed: No secret key\n",
+	}
+	assert.Equal(t, "exit status 2: gpg: decryption failed: No secret key", err.Error())
+
+	err = &Error{Err: fmt.Errorf("timeout: %w", context.DeadlineExceeded)}
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	var gerr *Error
+	assert.True(t, errors.As(fmt.Errorf("failed: %w", err), &gerr))
+}
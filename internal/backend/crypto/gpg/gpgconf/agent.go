@@ -0,0 +1,123 @@
+package gpgconf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// AgentConfigLoc returns the location of the gpg-agent config file.
+func AgentConfigLoc() string {
+	return filepath.Join(filepath.Dir(gpgConfigLoc("")), "gpg-agent.conf")
+}
+
+// SetAgentOption sets the given option in the gpg-agent config, replacing
+// any existing value. Comments and other options are retained.
+func SetAgentOption(key, value string) error {
+	fn := AgentConfigLoc()
+
+	buf, err := os.ReadFile(fn)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", fn, err)
+	}
+
+	line := key + " " + value
+	out := &bytes.Buffer{}
+	found := false
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		l := scanner.Text()
+		if k, _, _ := strings.Cut(strings.TrimSpace(l), " "); k == key {
+			if found {
+				continue
+			}
+			found = true
+			l = line
+		}
+		fmt.Fprintln(out, l)
+	}
+	if !found {
+		fmt.Fprintln(out, line)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fn), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(fn), err)
+	}
+	return os.WriteFile(fn, out.Bytes(), 0600)
+}
+
+// ReloadAgent asks a running gpg-agent to reload its config.
+func ReloadAgent(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "gpgconf", "--reload", "gpg-agent")
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reload gpg-agent: %w: %s", err, out)
+	}
+	return nil
+}
+
+// PingAgent checks that gpg-agent is running or can be started.
+func PingAgent(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "gpg-connect-agent", "/bye")
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to connect to gpg-agent: %w: %s", err, out)
+	}
+	return nil
+}
+
+// KeyInfo is the status of a secret key in gpg-agent.
+type KeyInfo struct {
+	Keygrip string
+	// Protected is set if the key is protected by a passphrase.
+	Protected bool
+	// Cached is set if gpg-agent has cached the passphrase.
+	Cached bool
+}
+
+// AgentKeyInfo asks gpg-agent for the status of all secret keys it manages.
+// The result is keyed by the keygrip. If home is not empty it's used instead
+// of the default GNUPGHOME.
+func AgentKeyInfo(ctx context.Context, home string) (map[string]KeyInfo, error) {
+	args := []string{"keyinfo --list", "/bye"}
+	if home != "" {
+		args = append([]string{"--homedir", home}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "gpg-connect-agent", args...)
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gpg-agent: %w", err)
+	}
+	return parseKeyInfo(out)
+}
+
+// parseKeyInfo parses the status lines of the keyinfo command, e.g.
+// "S KEYINFO <keygrip> D - - 1 P - - -".
+func parseKeyInfo(buf []byte) (map[string]KeyInfo, error) {
+	infos := make(map[string]KeyInfo, 4)
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "ERR ") {
+			return nil, fmt.Errorf("gpg-agent failed: %s", strings.TrimPrefix(line, "ERR "))
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 8 || fields[0] != "S" || fields[1] != "KEYINFO" {
+			continue
+		}
+		infos[fields[2]] = KeyInfo{
+			Keygrip:   fields[2],
+			Cached:    fields[6] == "1",
+			Protected: fields[7] == "P",
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,48 @@
+package gpgconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetAgentOption(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GNUPGHOME", td)
+
+	fn := filepath.Join(td, "gpg-agent.conf")
+	assert.Equal(t, fn, AgentConfigLoc())
+
+	// new file
+	require.NoError(t, SetAgentOption("pinentry-program", "/usr/bin/pinentry"))
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	assert.Equal(t, "pinentry-program /usr/bin/pinentry\n", string(buf))
+
+	// replace existing value, keep the rest
+	require.NoError(t, os.WriteFile(fn, []byte("# comment\ndefault-cache-ttl 60\npinentry-program /usr/bin/pinentry\n"), 0600))
+	require.NoError(t, SetAgentOption("pinentry-program", "/tmp/pinentry-gopass"))
+	buf, err = os.ReadFile(fn)
+	require.NoError(t, err)
+	assert.Equal(t, "# comment\ndefault-cache-ttl 60\npinentry-program /tmp/pinentry-gopass\n", string(buf))
+}
+
+func TestParseKeyInfo(t *testing.T) {
+	infos, err := parseKeyInfo([]byte(`S KEYINFO 396F6595A6756616B9FA34E521E8460341909378 D - - - C - - -
+S KEYINFO E2A750E583DC9AD354C88FC352D21A7295D00366 D - - 1 P - - -
+S KEYINFO 1F588FF6CC7EAE9BAE4D69ACC5C089D75DEE4342 D - - - P - - -
+OK
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]KeyInfo{
+		"396F6595A6756616B9FA34E521E8460341909378": {Keygrip: "396F6595A6756616B9FA34E521E8460341909378"},
+		"E2A750E583DC9AD354C88FC352D21A7295D00366": {Keygrip: "E2A750E583DC9AD354C88FC352D21A7295D00366", Protected: true, Cached: true},
+		"1F588FF6CC7EAE9BAE4D69ACC5C089D75DEE4342": {Keygrip: "1F588FF6CC7EAE9BAE4D69ACC5C089D75DEE4342", Protected: true},
+	}, infos)
+
+	_, err = parseKeyInfo([]byte("ERR 67125247 Unknown IPC command <GPG Agent>\n"))
+	assert.Error(t, err)
+}
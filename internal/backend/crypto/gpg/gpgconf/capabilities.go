@@ -0,0 +1,95 @@
+package gpgconf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+var modernGPG = semver.Version{Major: 2, Minor: 1}
+
+// Capabilities describes the version specific features of a gpg binary.
+type Capabilities struct {
+	Version semver.Version
+	// PinentryMode is set if gpg supports --pinentry-mode (2.1+).
+	PinentryMode bool
+	// FakedSystemTime is set if gpg supports --faked-system-time.
+	FakedSystemTime bool
+	// ThrowKeyIDs is set if gpg supports --throw-keyids.
+	ThrowKeyIDs bool
+	// SecretKeysInAgent is set if secret keys are managed by gpg-agent
+	// and --secret-keyring is obsolete (2.1+).
+	SecretKeysInAgent bool
+	// ColonFingerprints is set if --with-colons always prints the
+	// fingerprints of primary keys, i.e. --with-fingerprint is not
+	// necessary (2.1+).
+	ColonFingerprints bool
+	// Keybox is set if gpg uses the keybox format (pubring.kbx) for new
+	// keyrings and can read it (2.1+).
+	Keybox bool
+	// QuickCommands is set if gpg supports the --quick-* key management
+	// commands, e.g. --quick-lsign-key (2.1+).
+	QuickCommands bool
+}
+
+// IsGPG1 returns true for the legacy 1.x series.
+func (c Capabilities) IsGPG1() bool {
+	return c.Version.Major == 1
+}
+
+// Probe detects the capabilities of the given gpg binary. It relies on
+// --dump-options if available and falls back to the version number otherwise.
+// Unknown versions are assumed to be modern (2.1+).
+func Probe(ctx context.Context, binary string) Capabilities {
+	v := Version(ctx, binary)
+	modern := v.Major == 0 || v.GTE(modernGPG)
+
+	c := Capabilities{
+		Version:           v,
+		PinentryMode:      modern,
+		FakedSystemTime:   modern,
+		ThrowKeyIDs:       true,
+		SecretKeysInAgent: modern,
+		ColonFingerprints: modern,
+		Keybox:            modern,
+		QuickCommands:     modern,
+	}
+
+	if opts := dumpOptions(ctx, binary); len(opts) > 0 {
+		_, c.PinentryMode = opts["--pinentry-mode"]
+		_, c.FakedSystemTime = opts["--faked-system-time"]
+		_, c.ThrowKeyIDs = opts["--throw-keyids"]
+	}
+
+	debug.Log("gpg capabilities of %s: %+v", binary, c)
+	return c
+}
+
+func dumpOptions(ctx context.Context, binary string) map[string]struct{} {
+	cmd := exec.CommandContext(ctx, binary, "--dump-options")
+	buf, err := cmd.Output()
+	if err != nil {
+		debug.Log("failed to dump options: %s", err)
+		return nil
+	}
+
+	return parseOptions(buf)
+}
+
+func parseOptions(buf []byte) map[string]struct{} {
+	opts := make(map[string]struct{}, 400)
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "--") {
+			continue
+		}
+		opts[line] = struct{}{}
+	}
+	return opts
+}
@@ -0,0 +1,35 @@
+package gpgconf
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOptions(t *testing.T) {
+	opts := parseOptions([]byte("--armor\n--pinentry-mode\n\nfoo\n--throw-keyids\n"))
+	assert.Len(t, opts, 3)
+	assert.Contains(t, opts, "--pinentry-mode")
+	assert.NotContains(t, opts, "foo")
+}
+
+func TestProbeUnknown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no true binary on windows")
+	}
+
+	c := Probe(context.Background(), "true")
+	assert.Equal(t, semver.Version{}, c.Version)
+	assert.False(t, c.IsGPG1())
+	assert.True(t, c.PinentryMode)
+	assert.True(t, c.SecretKeysInAgent)
+	assert.True(t, c.ColonFingerprints)
+}
+
+func TestCapabilities(t *testing.T) {
+	c := Capabilities{Version: semver.Version{Major: 1, Minor: 4, Patch: 23}}
+	assert.True(t, c.IsGPG1())
+}
@@ -0,0 +1,103 @@
+package gpgconf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// KeyringFormat is the format of the public keyring of a GnuPG home
+// directory.
+type KeyringFormat int
+
+const (
+	// KeyringNone is an empty or missing GnuPG home directory.
+	KeyringNone KeyringFormat = iota
+	// KeyringLegacy is a pubring.gpg as created by gpg 1.x and 2.0.
+	KeyringLegacy
+	// KeyringKeybox is a pubring.kbx as created by gpg 2.1+.
+	KeyringKeybox
+	// KeyringMixed has both files. gpg 2.1+ only uses the keybox then, so
+	// keys that were added by an older gpg are not visible to it.
+	KeyringMixed
+)
+
+func (f KeyringFormat) String() string {
+	switch f {
+	case KeyringLegacy:
+		return "legacy"
+	case KeyringKeybox:
+		return "keybox"
+	case KeyringMixed:
+		return "mixed"
+	default:
+		return "none"
+	}
+}
+
+// Keyring describes the files a GnuPG home directory stores its keys in.
+type Keyring struct {
+	Dir    string
+	Format KeyringFormat
+	// LegacySecrets is set if secret keys are stored in a secring.gpg that
+	// gpg 2.1+ did not migrate to gpg-agent yet.
+	LegacySecrets bool
+}
+
+// DetectKeyring detects the keyring format of the given GnuPG home directory
+// or the default one.
+func DetectKeyring(homedir string) Keyring {
+	k := Keyring{Dir: Home(homedir)}
+
+	kbx := exists(filepath.Join(k.Dir, "pubring.kbx"))
+	legacy := exists(filepath.Join(k.Dir, "pubring.gpg"))
+	switch {
+	case kbx && legacy:
+		k.Format = KeyringMixed
+	case kbx:
+		k.Format = KeyringKeybox
+	case legacy:
+		k.Format = KeyringLegacy
+	}
+
+	// gpg 2.1+ creates the marker once it migrated the secring.gpg.
+	k.LegacySecrets = exists(filepath.Join(k.Dir, "secring.gpg")) && !exists(filepath.Join(k.Dir, ".gpg-v21-migrated"))
+
+	return k
+}
+
+// LegacyKeyring returns the path of the legacy public keyring.
+func (k Keyring) LegacyKeyring() string {
+	return filepath.Join(k.Dir, "pubring.gpg")
+}
+
+// Check returns an error describing how to fix the keyring if gpg with the
+// given capabilities can't use it at all.
+func (k Keyring) Check(c Capabilities) error {
+	if !c.Keybox && k.Format == KeyringKeybox {
+		return fmt.Errorf("gpg %s can not read the keybox in %s which was created by gpg 2.1 or newer. Install GnuPG 2.2 or newer or set GOPASS_GPG_BINARY to gpg2", c.Version, k.Dir)
+	}
+	return nil
+}
+
+// Hints returns how to migrate a keyring that gpg with the given
+// capabilities can only partially use.
+func (k Keyring) Hints(c Capabilities) []string {
+	var hints []string
+	if k.Format == KeyringMixed {
+		if c.Keybox {
+			hints = append(hints, fmt.Sprintf("%s is ignored by gpg %s since there is a pubring.kbx, too. Run 'gpg --import %s' to move its keys to the keybox", k.LegacyKeyring(), c.Version, k.LegacyKeyring()))
+		} else {
+			hints = append(hints, fmt.Sprintf("The keys in %s are not visible to gpg %s. Install GnuPG 2.2 or newer", filepath.Join(k.Dir, "pubring.kbx"), c.Version))
+		}
+	}
+	if c.Keybox && k.LegacySecrets {
+		hints = append(hints, fmt.Sprintf("The secret keys in %s have not been migrated to gpg-agent yet. Run 'gpg --list-secret-keys' once to migrate them", filepath.Join(k.Dir, "secring.gpg")))
+	}
+	return hints
+}
+
+func exists(fn string) bool {
+	_, err := os.Stat(fn)
+	return err == nil
+}
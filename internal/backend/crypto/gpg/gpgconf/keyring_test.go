@@ -0,0 +1,71 @@
+package gpgconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectKeyring(t *testing.T) {
+	td := t.TempDir()
+	touch := func(fn string) {
+		require.NoError(t, os.WriteFile(filepath.Join(td, fn), []byte("x"), 0o600))
+	}
+
+	k := DetectKeyring(td)
+	assert.Equal(t, td, k.Dir)
+	assert.Equal(t, KeyringNone, k.Format)
+	assert.Equal(t, "none", k.Format.String())
+
+	touch("pubring.gpg")
+	touch("secring.gpg")
+	k = DetectKeyring(td)
+	assert.Equal(t, KeyringLegacy, k.Format)
+	assert.True(t, k.LegacySecrets)
+
+	touch("pubring.kbx")
+	touch(".gpg-v21-migrated")
+	k = DetectKeyring(td)
+	assert.Equal(t, KeyringMixed, k.Format)
+	assert.False(t, k.LegacySecrets)
+
+	require.NoError(t, os.Remove(filepath.Join(td, "pubring.gpg")))
+	k = DetectKeyring(td)
+	assert.Equal(t, KeyringKeybox, k.Format)
+	assert.Equal(t, filepath.Join(td, "pubring.gpg"), k.LegacyKeyring())
+}
+
+func TestKeyringCheck(t *testing.T) {
+	gpg1 := Capabilities{Version: semver.Version{Major: 1, Minor: 4, Patch: 23}}
+	gpg2 := Capabilities{Version: semver.Version{Major: 2, Minor: 2, Patch: 27}, Keybox: true}
+
+	for _, tc := range []struct {
+		name  string
+		k     Keyring
+		caps  Capabilities
+		err   bool
+		hints int
+	}{
+		{name: "gpg1 legacy", k: Keyring{Format: KeyringLegacy}, caps: gpg1},
+		{name: "gpg1 keybox", k: Keyring{Format: KeyringKeybox}, caps: gpg1, err: true},
+		{name: "gpg1 mixed", k: Keyring{Format: KeyringMixed}, caps: gpg1, hints: 1},
+		{name: "gpg2 legacy", k: Keyring{Format: KeyringLegacy}, caps: gpg2},
+		{name: "gpg2 keybox", k: Keyring{Format: KeyringKeybox}, caps: gpg2},
+		{name: "gpg2 mixed", k: Keyring{Format: KeyringMixed}, caps: gpg2, hints: 1},
+		{name: "gpg2 secring", k: Keyring{Format: KeyringMixed, LegacySecrets: true}, caps: gpg2, hints: 2},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.err {
+				assert.Error(t, tc.k.Check(tc.caps))
+			} else {
+				assert.NoError(t, tc.k.Check(tc.caps))
+			}
+			assert.Len(t, tc.k.Hints(tc.caps), tc.hints)
+		})
+	}
+}
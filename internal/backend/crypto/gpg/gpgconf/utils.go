@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // GPGOpts parses extra GPG options from the environment.
@@ -18,18 +19,44 @@ func GPGOpts() []string {
 	return nil
 }
 
-// gpgConfigLoc returns the location of the GPG config file.
-func gpgConfigLoc() string {
+// Home returns the location of the GnuPG home directory. A non-empty
+// homedir takes precedence over GNUPGHOME.
+func Home(homedir string) string {
+	if homedir != "" {
+		return homedir
+	}
 	if sv := os.Getenv("GNUPGHOME"); sv != "" {
-		return filepath.Join(sv, "gpg.conf")
+		return sv
 	}
 
 	uhd, _ := os.UserHomeDir()
-	return filepath.Join(uhd, ".gnupg", "gpg.conf")
+	return filepath.Join(uhd, ".gnupg")
+}
+
+// gpgConfigLoc returns the location of the GPG config file.
+func gpgConfigLoc(homedir string) string {
+	return filepath.Join(Home(homedir), "gpg.conf")
+}
+
+// KeyringModTime returns the most recent modification time of the files
+// making up the GPG keyring. It returns the zero time if none exists.
+func KeyringModTime(homedir string) time.Time {
+	var mt time.Time
+	for _, fn := range []string{"pubring.kbx", "pubring.gpg", "secring.gpg", "trustdb.gpg", "private-keys-v1.d"} {
+		fi, err := os.Stat(filepath.Join(Home(homedir), fn))
+		if err != nil {
+			continue
+		}
+		if fi.ModTime().After(mt) {
+			mt = fi.ModTime()
+		}
+	}
+	return mt
 }
 
-func Config() (map[string]string, error) {
-	fh, err := os.Open(gpgConfigLoc())
+// Config parses gpg.conf in the given GnuPG home directory or the default one.
+func Config(homedir string) (map[string]string, error) {
+	fh, err := os.Open(gpgConfigLoc(homedir))
 	if err != nil {
 		return nil, err
 	}
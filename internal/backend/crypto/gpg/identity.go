@@ -1,6 +1,11 @@
 package gpg
 
-import "time"
+import (
+	"mime"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
 
 // Identity is a GPG identity, one key can have many IDs.
 type Identity struct {
@@ -13,12 +18,17 @@ type Identity struct {
 
 // ID returns the GPG ID format.
 func (i Identity) ID() string {
-	out := i.Name
+	parts := make([]string, 0, 3)
+	if i.Name != "" {
+		parts = append(parts, i.Name)
+	}
 	if i.Comment != "" {
-		out += " (" + i.Comment + ")"
+		parts = append(parts, "("+i.Comment+")")
+	}
+	if i.Email != "" {
+		parts = append(parts, "<"+i.Email+">")
 	}
-	out += " <" + i.Email + ">"
-	return out
+	return strings.Join(parts, " ")
 }
 
 // String implement fmt.Stringer. This method resembles the output gpg uses
@@ -26,3 +36,75 @@ func (i Identity) ID() string {
 func (i Identity) String() string {
 	return "uid                            " + i.ID()
 }
+
+// ParseIdentity parses an OpenPGP user ID of the form
+// "Name (Comment) <Email>". Every part is optional. Quoted names and
+// RFC 2047 encoded words are decoded.
+func ParseIdentity(uid string) Identity {
+	if dec, err := new(mime.WordDecoder).DecodeHeader(uid); err == nil && utf8.ValidString(dec) {
+		uid = dec
+	}
+
+	var id Identity
+
+	rest := strings.TrimSpace(uid)
+	if strings.HasSuffix(rest, ">") {
+		if i := strings.LastIndex(rest, "<"); i >= 0 {
+			id.Email = strings.TrimSpace(rest[i+1 : len(rest)-1])
+			rest = strings.TrimSpace(rest[:i])
+		}
+	}
+	if strings.HasSuffix(rest, ")") {
+		if i := indexUnquoted(rest, '('); i >= 0 {
+			id.Comment = strings.TrimSpace(rest[i+1 : len(rest)-1])
+			rest = strings.TrimSpace(rest[:i])
+		}
+	}
+	id.Name = unquote(rest)
+
+	// a bare email address
+	if id.Email == "" && id.Comment == "" && !strings.ContainsAny(id.Name, " \t") && strings.Contains(id.Name, "@") {
+		id.Email = id.Name
+		id.Name = ""
+	}
+
+	return id
+}
+
+// indexUnquoted returns the index of the first occurrence of c outside of
+// double quotes or -1.
+func indexUnquoted(s string, c byte) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case c:
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// unquote removes surrounding double quotes and backslash escapes within.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	s = s[1 : len(s)-1]
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
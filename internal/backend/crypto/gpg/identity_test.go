@@ -3,6 +3,7 @@ package gpg
 import (
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -18,4 +19,61 @@ func TestIdentity(t *testing.T) {
 
 	assert.Equal(t, id.ID(), "John Doe (johnny) <john.doe@example.org>")
 	assert.Equal(t, id.String(), "uid                            "+id.ID())
+
+	assert.Equal(t, "John Doe", Identity{Name: "John Doe"}.ID())
+	assert.Equal(t, "<john.doe@example.org>", Identity{Email: "john.doe@example.org"}.ID())
+}
+
+func TestParseIdentity(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		name    string
+		comment string
+		email   string
+	}{
+		{in: ""},
+		{in: "John Doe", name: "John Doe"},
+		{in: "John Doe <john.doe@example.com>", name: "John Doe", email: "john.doe@example.com"},
+		{in: "John Doe (work) <john.doe@example.com>", name: "John Doe", comment: "work", email: "john.doe@example.com"},
+		{in: "John Doe (work)", name: "John Doe", comment: "work"},
+		{in: "<john.doe@example.com>", email: "john.doe@example.com"},
+		{in: "john.doe@example.com", email: "john.doe@example.com"},
+		{in: "  John Doe   <john.doe@example.com>  ", name: "John Doe", email: "john.doe@example.com"},
+		{in: "John (a (nested) comment)", name: "John", comment: "a (nested) comment"},
+		{in: `"Doe, John" <john.doe@example.com>`, name: "Doe, John", email: "john.doe@example.com"},
+		{in: `"John (Johnny) Doe" (work) <john.doe@example.com>`, name: "John (Johnny) Doe", comment: "work", email: "john.doe@example.com"},
+		{in: `"John \"The Man\" Doe"`, name: `John "The Man" Doe`},
+		{in: "=?UTF-8?B?SsO8cmdlbiBNw7xsbGVy?= <juergen@example.com>", name: "Jürgen Müller", email: "juergen@example.com"},
+		{in: "=?ISO-8859-1?Q?J=FCrgen?= (=?UTF-8?Q?B=C3=BCro?=)", name: "Jürgen", comment: "Büro"},
+		{in: "Jürgen Müller (Büro ☕) <juergen@example.com>", name: "Jürgen Müller", comment: "Büro ☕", email: "juergen@example.com"},
+	} {
+		id := ParseIdentity(tc.in)
+		assert.Equal(t, tc.name, id.Name, tc.in)
+		assert.Equal(t, tc.comment, id.Comment, tc.in)
+		assert.Equal(t, tc.email, id.Email, tc.in)
+	}
+}
+
+func FuzzParseIdentity(f *testing.F) {
+	for _, uid := range []string{
+		"John Doe",
+		"John Doe <john.doe@example.com>",
+		"John Doe (user) <john.doe@example.com>",
+		"<only.email@example.com>",
+		`"Doe, John" (work) <john.doe@example.com>`,
+		"=?UTF-8?B?SsO8cmdlbiBNw7xsbGVy?= <juergen@example.com>",
+		"Jürgen Müller (Büro ☕) <juergen@example.com>",
+		"((<<>>))",
+	} {
+		f.Add(uid)
+	}
+
+	f.Fuzz(func(t *testing.T, in string) {
+		id := ParseIdentity(in)
+		if utf8.ValidString(in) {
+			assert.True(t, utf8.ValidString(id.Name))
+			assert.True(t, utf8.ValidString(id.Comment))
+			assert.True(t, utf8.ValidString(id.Email))
+		}
+	})
 }
@@ -3,6 +3,7 @@ package gpg
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -10,14 +11,56 @@ import (
 type Key struct {
 	KeyType        string
 	KeyLength      int
+	Algorithm      string
 	Validity       string
 	CreationDate   time.Time
 	ExpirationDate time.Time
 	Ownertrust     string
 	Fingerprint    string
-	Identities     map[string]Identity
-	SubKeys        map[string]struct{}
-	Caps           Capabilities
+	// Keygrip identifies the key in gpg-agent. It's only known for secret
+	// keys.
+	Keygrip string
+	// UIDs maps the hashes of all user IDs to the parsed identities.
+	UIDs map[string]Identity
+	// SubKeys maps the (long) key IDs of all subkeys to their details.
+	SubKeys map[string]SubKey
+	// Caps are the capabilities of the key as a whole, including subkeys.
+	Caps Capabilities
+	// Usage are the capabilities of the primary key itself.
+	Usage Capabilities
+}
+
+// SubKey is a subkey of a GPG key.
+type SubKey struct {
+	KeyLength      int
+	Algorithm      string
+	Validity       string
+	CreationDate   time.Time
+	ExpirationDate time.Time
+	Fingerprint    string
+	Keygrip        string
+	Usage          Capabilities
+}
+
+// ID returns the long key ID of the subkey.
+func (s SubKey) ID() string {
+	if len(s.Fingerprint) < 25 {
+		return ""
+	}
+	return fmt.Sprintf("0x%s", s.Fingerprint[24:])
+}
+
+// IsValid returns true if the subkey is neither expired nor revoked
+// or otherwise invalid.
+func (s SubKey) IsValid() bool {
+	if !s.ExpirationDate.IsZero() && s.ExpirationDate.Before(time.Now()) {
+		return false
+	}
+	switch s.Validity {
+	case "i", "d", "r", "e", "n":
+		return false
+	}
+	return true
 }
 
 // Capabilities of a Key.
@@ -29,6 +72,30 @@ type Capabilities struct {
 	Deactivated    bool
 }
 
+// String returns the names of the capabilities, e.g. "sign, certify".
+func (c Capabilities) String() string {
+	var caps []string
+	if c.Encrypt {
+		caps = append(caps, "encrypt")
+	}
+	if c.Sign {
+		caps = append(caps, "sign")
+	}
+	if c.Certify {
+		caps = append(caps, "certify")
+	}
+	if c.Authentication {
+		caps = append(caps, "authenticate")
+	}
+	if c.Deactivated {
+		caps = append(caps, "disabled")
+	}
+	if len(caps) < 1 {
+		return "none"
+	}
+	return strings.Join(caps, ", ")
+}
+
 // IsUseable returns true if GPG would assume this key is useable for encryption.
 func (k Key) IsUseable(alwaysTrust bool) bool {
 	if k.Caps.Deactivated {
@@ -66,7 +133,7 @@ func (k Key) String() string {
 		out += fmt.Sprintf(" [expires: %s]", k.ExpirationDate.Format("2006-01-02"))
 	}
 	out += "\n      Key fingerprint = " + k.Fingerprint
-	for _, id := range k.Identities {
+	for _, id := range k.Identities() {
 		out += fmt.Sprintf("\n" + id.String())
 	}
 	return out
@@ -81,21 +148,107 @@ func (k Key) OneLine() string {
 	return fmt.Sprintf("0x%s - %s", k.Fingerprint[24:], k.Identity().ID())
 }
 
-// Identity returns the first identity.
-func (k Key) Identity() Identity {
-	ids := make([]Identity, 0, len(k.Identities))
-	for _, i := range k.Identities {
+// Identities returns all identities of this key, the most recently created
+// one first.
+func (k Key) Identities() []Identity {
+	ids := make([]Identity, 0, len(k.UIDs))
+	for _, i := range k.UIDs {
 		ids = append(ids, i)
 	}
 	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].CreationDate.Equal(ids[j].CreationDate) {
+			return ids[i].ID() < ids[j].ID()
+		}
 		return ids[i].CreationDate.After(ids[j].CreationDate)
 	})
-	for _, i := range ids {
+	return ids
+}
+
+// Identity returns the first identity.
+func (k Key) Identity() Identity {
+	for _, i := range k.Identities() {
 		return i
 	}
 	return Identity{}
 }
 
+// EncryptionKey returns the fingerprint of the (sub) key gpg will use for
+// encryption, i.e. the most recently created valid subkey capable of
+// encryption or the primary key if it's capable of encryption and there
+// are no such subkeys. It returns an empty string if there is no
+// suitable key or if the fingerprints are not known.
+func (k Key) EncryptionKey() string {
+	if sk, found := k.encryptionSubKey(); found {
+		return sk.Fingerprint
+	}
+	if k.Usage.Encrypt {
+		return k.Fingerprint
+	}
+	return ""
+}
+
+// EncryptionKeygrip returns the keygrip of the (sub) key returned by
+// EncryptionKey, i.e. the one gpg-agent needs to decrypt. It returns an
+// empty string if it's not known.
+func (k Key) EncryptionKeygrip() string {
+	if sk, found := k.encryptionSubKey(); found {
+		return sk.Keygrip
+	}
+	if k.Usage.Encrypt {
+		return k.Keygrip
+	}
+	return ""
+}
+
+func (k Key) encryptionSubKey() (SubKey, bool) {
+	var best SubKey
+	for _, sk := range k.SubKeys {
+		if !sk.Usage.Encrypt || !sk.IsValid() || sk.Fingerprint == "" {
+			continue
+		}
+		if best.Fingerprint == "" || sk.CreationDate.After(best.CreationDate) {
+			best = sk
+		}
+	}
+	return best, best.Fingerprint != ""
+}
+
+// SortedSubKeys returns the subkeys, the most recently created one first.
+func (k Key) SortedSubKeys() []SubKey {
+	sks := make([]SubKey, 0, len(k.SubKeys))
+	for _, sk := range k.SubKeys {
+		sks = append(sks, sk)
+	}
+	sort.Slice(sks, func(i, j int) bool {
+		if sks[i].CreationDate.Equal(sks[j].CreationDate) {
+			return sks[i].Fingerprint < sks[j].Fingerprint
+		}
+		return sks[i].CreationDate.After(sks[j].CreationDate)
+	})
+	return sks
+}
+
+// Matches returns true if the recipient id refers to this key, i.e. it's the
+// fingerprint or key ID of the key or one of its subkeys or the email of
+// one of its identities.
+func (k Key) Matches(id string) bool {
+	if SameKey(id, k.Fingerprint) {
+		return true
+	}
+	for _, sk := range k.SubKeys {
+		if SameKey(id, sk.Fingerprint) {
+			return true
+		}
+	}
+	email := strings.Trim(id, "<>")
+	for _, uid := range k.UIDs {
+		if uid.Email != "" && strings.EqualFold(uid.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
 // ID returns the short fingerprint.
 func (k Key) ID() string {
 	if len(k.Fingerprint) < 25 {
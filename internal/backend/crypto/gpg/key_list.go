@@ -24,6 +24,24 @@ func (kl KeyList) Recipients() []string {
 	return l
 }
 
+// Fingerprints returns the sorted and de-duplicated fingerprints of all keys
+// in the KeyList.
+func (kl KeyList) Fingerprints() []string {
+	u := make(map[string]struct{}, len(kl))
+	for _, k := range kl {
+		if k.Fingerprint == "" {
+			continue
+		}
+		u[k.Fingerprint] = struct{}{}
+	}
+	l := make([]string, 0, len(u))
+	for k := range u {
+		l = append(l, k)
+	}
+	sort.Strings(l)
+	return l
+}
+
 // UseableKeys returns the list of useable (valid keys).
 func (kl KeyList) UseableKeys(alwaysTrust bool) KeyList {
 	nkl := make(KeyList, 0, len(kl))
@@ -60,7 +78,7 @@ func (kl KeyList) FindKey(id string) (Key, error) {
 		if strings.HasSuffix(k.Fingerprint, id) {
 			return k, nil
 		}
-		for _, ident := range k.Identities {
+		for _, ident := range k.UIDs {
 			if ident.Name == id {
 				return k, nil
 			}
@@ -68,8 +86,11 @@ func (kl KeyList) FindKey(id string) (Key, error) {
 				return k, nil
 			}
 		}
-		for sk := range k.SubKeys {
-			if strings.HasSuffix(sk, id) {
+		for kid, sk := range k.SubKeys {
+			if strings.HasSuffix(kid, id) {
+				return k, nil
+			}
+			if sk.Fingerprint != "" && strings.HasSuffix(sk.Fingerprint, id) {
 				return k, nil
 			}
 		}
@@ -12,8 +12,10 @@ func TestKeyList(t *testing.T) {
 		genTestKey("Jane", "jane", "Doe", "jane.doe@example.org", "25FF1614B8F87B52FFFF99B962AF4031C82E0019"),
 		genTestKey("Jim", "jimmy", "Doe", "jim.doe@example.org", "25FF1614B8F87B52FFFF99B962AF4031C82E2019", "z", "none"),
 	}
-	kl[2].SubKeys = map[string]struct{}{
-		"0xDEADBEEF": {},
+	kl[2].SubKeys = map[string]SubKey{
+		"0xDEADBEEF": {
+			Fingerprint: "A5B6C7D8E9F0A1B2C3D4E5F6A7B8C9D0DEADBEEF",
+		},
 	}
 
 	assert.Equal(t, []string{
@@ -28,6 +30,11 @@ func TestKeyList(t *testing.T) {
 	assert.Equal(t, []string{
 		"0x62AF4031C82E2019",
 	}, kl.UnusableKeys(false).Recipients())
+	assert.Equal(t, []string{
+		"25FF1614B8F87B52FFFF99B962AF4031C82E0019",
+		"25FF1614B8F87B52FFFF99B962AF4031C82E0039",
+		"25FF1614B8F87B52FFFF99B962AF4031C82E2019",
+	}, append(kl, kl[0]).Fingerprints())
 
 	// search by email
 	k, err := kl.FindKey("jim.doe@example.org")
@@ -58,4 +65,9 @@ func TestKeyList(t *testing.T) {
 	k, err = kl.FindKey("0xDEADBEEF")
 	assert.NoError(t, err)
 	assert.Equal(t, "0x62AF4031C82E2019", k.ID())
+
+	// search by subkey fingerprint
+	k, err = kl.FindKey("A5B6C7D8E9F0A1B2C3D4E5F6A7B8C9D0DEADBEEF")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x62AF4031C82E2019", k.ID())
 }
@@ -48,7 +48,7 @@ func genTestKey(args ...string) Key {
 		ExpirationDate: expiration,
 		Ownertrust:     trust,
 		Fingerprint:    fp,
-		Identities: map[string]Identity{
+		UIDs: map[string]Identity{
 			fmt.Sprintf("%s %s (%s) <%s>", first, last, nick, email): {
 				Name:           fmt.Sprintf("%s %s", first, last),
 				Comment:        nick,
@@ -69,7 +69,7 @@ func genTestKey(args ...string) Key {
 
 func TestKey(t *testing.T) {
 	k := Key{
-		Identities: map[string]Identity{},
+		UIDs: map[string]Identity{},
 	}
 	assert.Equal(t, "(invalid:)", k.OneLine())
 	assert.Equal(t, "", k.Identity().Name)
@@ -85,7 +85,7 @@ func TestIdentitySort(t *testing.T) {
 	expiration := time.Date(2018, 1, 1, 1, 1, 1, 0, time.UTC)
 
 	k := genTestKey()
-	k.Identities["Foo Bar"] = Identity{
+	k.UIDs["Foo Bar"] = Identity{
 		Name:           "Foo Bar",
 		Comment:        "foo",
 		Email:          "foo.bar@example.com",
@@ -140,3 +140,102 @@ func TestUseability(t *testing.T) {
 		assert.True(t, k.IsUseable(false))
 	}
 }
+
+func TestEncryptionKey(t *testing.T) {
+	now := time.Now()
+
+	k := genTestKey()
+	assert.Equal(t, "", k.EncryptionKey())
+
+	k.Usage.Encrypt = true
+	assert.Equal(t, k.Fingerprint, k.EncryptionKey())
+
+	k.Usage.Encrypt = false
+	k.SubKeys = map[string]SubKey{
+		"1111111111111111": {
+			Fingerprint:  "AAAAAAAAAAAAAAAAAAAAAAAA1111111111111111",
+			Validity:     "u",
+			CreationDate: now.Add(-2 * time.Hour),
+			Usage:        Capabilities{Encrypt: true},
+		},
+		"2222222222222222": {
+			Fingerprint:  "AAAAAAAAAAAAAAAAAAAAAAAA2222222222222222",
+			Validity:     "u",
+			CreationDate: now.Add(-1 * time.Hour),
+			Usage:        Capabilities{Sign: true},
+		},
+		"3333333333333333": {
+			Fingerprint:    "AAAAAAAAAAAAAAAAAAAAAAAA3333333333333333",
+			Validity:       "e",
+			CreationDate:   now,
+			ExpirationDate: now.Add(-time.Minute),
+			Usage:          Capabilities{Encrypt: true},
+		},
+		"4444444444444444": {
+			Fingerprint:  "AAAAAAAAAAAAAAAAAAAAAAAA4444444444444444",
+			Validity:     "r",
+			CreationDate: now,
+			Usage:        Capabilities{Encrypt: true},
+		},
+	}
+	assert.Equal(t, "AAAAAAAAAAAAAAAAAAAAAAAA1111111111111111", k.EncryptionKey())
+
+	k.SubKeys["5555555555555555"] = SubKey{
+		Fingerprint:  "AAAAAAAAAAAAAAAAAAAAAAAA5555555555555555",
+		CreationDate: now.Add(-time.Minute),
+		Usage:        Capabilities{Encrypt: true},
+	}
+	assert.Equal(t, "AAAAAAAAAAAAAAAAAAAAAAAA5555555555555555", k.EncryptionKey())
+}
+
+func TestEncryptionKeygrip(t *testing.T) {
+	k := genTestKey()
+	k.Keygrip = "1111111111111111111111111111111111111111"
+	assert.Equal(t, "", k.EncryptionKeygrip())
+
+	k.Usage.Encrypt = true
+	assert.Equal(t, k.Keygrip, k.EncryptionKeygrip())
+
+	k.SubKeys = map[string]SubKey{
+		"2222222222222222": {
+			Fingerprint: "AAAAAAAAAAAAAAAAAAAAAAAA2222222222222222",
+			Keygrip:     "2222222222222222222222222222222222222222",
+			Usage:       Capabilities{Encrypt: true},
+		},
+	}
+	assert.Equal(t, "2222222222222222222222222222222222222222", k.EncryptionKeygrip())
+}
+
+func TestKeyDetails(t *testing.T) {
+	k := genTestKey()
+	k.SubKeys = map[string]SubKey{
+		"AAAAAAAAAAAAAAAA": {
+			Fingerprint:  "111111111111111111111111AAAAAAAAAAAAAAAA",
+			CreationDate: time.Date(2019, 1, 1, 1, 1, 1, 0, time.UTC),
+			Usage:        Capabilities{Encrypt: true},
+		},
+		"BBBBBBBBBBBBBBBB": {
+			Fingerprint:  "222222222222222222222222BBBBBBBBBBBBBBBB",
+			CreationDate: time.Date(2020, 1, 1, 1, 1, 1, 0, time.UTC),
+			Usage:        Capabilities{Sign: true},
+		},
+	}
+
+	sks := k.SortedSubKeys()
+	assert.Len(t, sks, 2)
+	assert.Equal(t, "0xBBBBBBBBBBBBBBBB", sks[0].ID())
+	assert.Equal(t, "sign", sks[0].Usage.String())
+	assert.Equal(t, "encrypt, sign, certify", Capabilities{Encrypt: true, Sign: true, Certify: true}.String())
+	assert.Equal(t, "none", Capabilities{}.String())
+
+	assert.True(t, k.Matches("25FF1614B8F87B52FFFF99B962AF4031C82E0039"))
+	assert.True(t, k.Matches("0x62AF4031C82E0039"))
+	assert.True(t, k.Matches("0xAAAAAAAAAAAAAAAA"))
+	assert.True(t, k.Matches("<John.Doe@example.org>"))
+	assert.False(t, k.Matches("jane.doe@example.org"))
+	assert.False(t, k.Matches("0xDEADBEEFDEADBEEF"))
+
+	assert.Equal(t, "full", TrustName("f"))
+	assert.Equal(t, "unknown", TrustName("-"))
+	assert.Equal(t, "x", TrustName("x"))
+}
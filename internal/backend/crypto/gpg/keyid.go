@@ -0,0 +1,57 @@
+package gpg
+
+import "strings"
+
+// IsFingerprint returns true if the given id looks like a full (v4 or v5)
+// OpenPGP fingerprint.
+func IsFingerprint(id string) bool {
+	id = strings.TrimPrefix(id, "0x")
+	return (len(id) == 40 || len(id) == 64) && isHex(id)
+}
+
+// IsShortKeyID returns true if the given id is a 32-bit key ID. These are
+// trivial to collide and should never be used to select a key.
+func IsShortKeyID(id string) bool {
+	id = strings.TrimPrefix(id, "0x")
+	return len(id) == 8 && isHex(id)
+}
+
+// SameKey returns true if a and b refer to the same key, e.g. because one is
+// a key ID of the other. Only long (64-bit) and short (32-bit) key IDs are
+// matched against the end of a longer ID, shorter fragments never match.
+func SameKey(a, b string) bool {
+	a = strings.ToUpper(strings.TrimPrefix(a, "0x"))
+	b = strings.ToUpper(strings.TrimPrefix(b, "0x"))
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	if !isHex(a) || !isHex(b) {
+		return false
+	}
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	if len(b) != 16 && len(b) != 8 {
+		return false
+	}
+	return strings.HasSuffix(a, b)
+}
+
+func isHex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+		case c >= 'a' && c <= 'f':
+		case c >= 'A' && c <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
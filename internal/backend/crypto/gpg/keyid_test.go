@@ -0,0 +1,46 @@
+package gpg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyIDs(t *testing.T) {
+	for _, tc := range []struct {
+		id    string
+		fp    bool
+		short bool
+	}{
+		{id: ""},
+		{id: "john.doe@example.org"},
+		{id: "C82E0039", short: true},
+		{id: "0xC82E0039", short: true},
+		{id: "0x62AF4031C82E0039"},
+		{id: "25FF1614B8F87B52FFFF99B962AF4031C82E0039", fp: true},
+		{id: "0x25FF1614B8F87B52FFFF99B962AF4031C82E0039", fp: true},
+		{id: "25FF1614B8F87B52FFFF99B962AF4031C82E003Z"},
+	} {
+		assert.Equal(t, tc.fp, IsFingerprint(tc.id), tc.id)
+		assert.Equal(t, tc.short, IsShortKeyID(tc.id), tc.id)
+	}
+}
+
+func TestSameKey(t *testing.T) {
+	fp := "25FF1614B8F87B52FFFF99B962AF4031C82E0039"
+	assert.True(t, SameKey(fp, fp))
+	assert.True(t, SameKey(fp, "0x62AF4031C82E0039"))
+	assert.True(t, SameKey("0x62af4031c82e0039", fp))
+	assert.True(t, SameKey("john.doe@example.org", "john.doe@example.org"))
+	assert.False(t, SameKey(fp, "0x62AF4031C82E0019"))
+	assert.False(t, SameKey(fp, ""))
+	assert.False(t, SameKey("doe@example.org", "john.doe@example.org"))
+
+	assert.True(t, SameKey(fp, "C82E0039"))
+	assert.True(t, SameKey("0x62AF4031C82E0039", "0xC82E0039"))
+	// fragments that aren't a key ID never match
+	assert.False(t, SameKey("A", "1F"))
+	assert.False(t, SameKey(fp, "39"))
+	assert.False(t, SameKey(fp, "0x9"))
+	assert.False(t, SameKey(fp, "31C82E0039"))
+}
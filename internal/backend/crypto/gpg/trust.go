@@ -0,0 +1,73 @@
+package gpg
+
+import "fmt"
+
+// Trust models of gpg that can be used to validate public keys.
+const (
+	// TrustAuto uses the trust model configured in the gpg.conf.
+	TrustAuto = "auto"
+	// TrustAlways uses every public key without validating it.
+	TrustAlways = "always"
+	// TrustPGP uses keys that are signed by keys you (owner) trust.
+	TrustPGP = "pgp"
+	// TrustTOFU uses keys that were seen before for the same user ID.
+	TrustTOFU = "tofu"
+	// TrustTOFUPGP combines TrustTOFU and TrustPGP.
+	TrustTOFUPGP = "tofu+pgp"
+)
+
+// TrustModels are the supported trust models.
+var TrustModels = []string{TrustAuto, TrustAlways, TrustPGP, TrustTOFU, TrustTOFUPGP}
+
+// ValidateTrustModel returns an error if tm is not a supported trust model.
+func ValidateTrustModel(tm string) error {
+	for _, m := range TrustModels {
+		if tm == m {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown trust model %q. Must be one of %v", tm, TrustModels)
+}
+
+// UsesTOFU returns true if the trust model relies on the TOFU policies.
+func UsesTOFU(tm string) bool {
+	return tm == TrustTOFU || tm == TrustTOFUPGP
+}
+
+// OwnerTrustLevels maps the owner trust levels to the values used by
+// gpg --import-ownertrust.
+var OwnerTrustLevels = map[string]int{
+	"unknown":  2,
+	"never":    3,
+	"marginal": 4,
+	"full":     5,
+	"ultimate": 6,
+}
+
+// TrustName returns the name of a validity or owner trust value as printed
+// by gpg --with-colons.
+func TrustName(code string) string {
+	switch code {
+	case "o":
+		return "unknown (new)"
+	case "i":
+		return "invalid"
+	case "d":
+		return "disabled"
+	case "r":
+		return "revoked"
+	case "e":
+		return "expired"
+	case "", "-", "q":
+		return "unknown"
+	case "n":
+		return "never"
+	case "m":
+		return "marginal"
+	case "f":
+		return "full"
+	case "u":
+		return "ultimate"
+	}
+	return code
+}
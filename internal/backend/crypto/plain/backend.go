@@ -3,6 +3,7 @@ package plain
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"runtime"
 	"strings"
@@ -20,7 +21,7 @@ var staticPrivateKeyList = gpg.KeyList{
 		Validity:     "u",
 		CreationDate: time.Now(),
 		Fingerprint:  "000000000000000000000000DEADBEEF",
-		Identities: map[string]gpg.Identity{
+		UIDs: map[string]gpg.Identity{
 			"Dead Beef <dead.beef@example.com>": {
 				Name:         "Dead Beef",
 				Email:        "dead.beef@example.com",
@@ -34,7 +35,7 @@ var staticPrivateKeyList = gpg.KeyList{
 		Validity:     "u",
 		CreationDate: time.Now(),
 		Fingerprint:  "000000000000000000000000FEEDBEEF",
-		Identities: map[string]gpg.Identity{
+		UIDs: map[string]gpg.Identity{
 			"Feed Beef <feed.beef@example.com>": {
 				Name:         "Feed Beef",
 				Email:        "feed.beef@example.com",
@@ -96,6 +97,20 @@ func (m *Mocker) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
 	return ciphertext, nil
 }
 
+// Sign returns a checksum of the data tagged with the first identity.
+func (m *Mocker) Sign(ctx context.Context, data []byte) ([]byte, error) {
+	return []byte(fmt.Sprintf("%s:%x", staticPrivateKeyList.Recipients()[0], sha256.Sum256(data))), nil
+}
+
+// Verify checks the checksum created by Sign.
+func (m *Mocker) Verify(ctx context.Context, data, sig []byte) (string, error) {
+	id, sum, found := strings.Cut(string(sig), ":")
+	if !found || sum != fmt.Sprintf("%x", sha256.Sum256(data)) {
+		return "", fmt.Errorf("invalid signature")
+	}
+	return id, nil
+}
+
 // ExportPublicKey does nothing.
 func (m *Mocker) ExportPublicKey(context.Context, string) ([]byte, error) {
 	return nil, nil
@@ -36,6 +36,25 @@ type Revision struct {
 	Body        string
 }
 
+// SyncStatus describes how a local clone relates to its remote.
+type SyncStatus struct {
+	Remote   string
+	Branch   string
+	Tracking bool // the remote branch is known, i.e. Ahead and Behind are valid
+	Ahead    int
+	Behind   int
+	LastSync time.Time
+	Dirty    bool // there are uncommitted changes
+}
+
+// Snapshot is a named revision of a whole store that it can be rolled back to.
+type Snapshot struct {
+	Name    string
+	Hash    string
+	Date    time.Time
+	Message string
+}
+
 // Revisions implements the sort interface.
 type Revisions []Revision
 
@@ -35,10 +35,15 @@ type Storage interface {
 	fmt.Stringer
 	rcs
 	Get(ctx context.Context, name string) ([]byte, error)
+	// Set must replace the content atomically, i.e. readers never observe
+	// partially written content, even if gopass crashes.
 	Set(ctx context.Context, name string, value []byte) error
 	Delete(ctx context.Context, name string) error
 	Exists(ctx context.Context, name string) bool
 	List(ctx context.Context, prefix string) ([]string, error)
+	// Walk calls fn for every entry below prefix, like List, but without
+	// collecting them first. It stops at the first error returned by fn.
+	Walk(ctx context.Context, prefix string, fn func(name string) error) error
 	IsDir(ctx context.Context, name string) bool
 	Prune(ctx context.Context, prefix string) error
 	Link(ctx context.Context, from, to string) error
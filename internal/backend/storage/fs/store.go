@@ -41,7 +41,8 @@ func (s *Store) Get(ctx context.Context, name string) ([]byte, error) {
 	return os.ReadFile(path)
 }
 
-// Set writes the given content.
+// Set writes the given content. The content is replaced atomically, so a
+// crash never leaves a truncated file behind.
 func (s *Store) Set(ctx context.Context, name string, value []byte) error {
 	if runtime.GOOS == "windows" {
 		name = filepath.FromSlash(name)
@@ -54,7 +55,7 @@ func (s *Store) Set(ctx context.Context, name string, value []byte) error {
 		}
 	}
 	debug.Log("Writing %s to %s", name, filepath.Join(s.path, name))
-	return os.WriteFile(filepath.Join(s.path, name), value, 0644)
+	return fsutil.WriteFileAtomic(filename, value, 0644)
 }
 
 // Delete removes the named entity.
@@ -113,13 +114,30 @@ func (s *Store) Exists(ctx context.Context, name string) bool {
 // e.g. foo, far/bar baz/.bang
 // directory separator are normalized using `/`.
 func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
-	prefix = strings.TrimPrefix(prefix, "/")
-	debug.Log("Listing %s/%s", s.path, prefix)
 	files := make([]string, 0, 100)
-	if err := filepath.Walk(s.path, func(path string, info os.FileInfo, err error) error {
+	if err := s.Walk(ctx, prefix, func(name string) error {
+		files = append(files, name)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Walk calls fn for every entity below prefix, in lexical order of their
+// paths. Entities are named like in List. Walk stops if the context is
+// canceled.
+func (s *Store) Walk(ctx context.Context, prefix string, fn func(string) error) error {
+	prefix = strings.TrimPrefix(prefix, "/")
+	debug.Log("Walking %s/%s", s.path, prefix)
+	return filepath.Walk(s.path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		relPath := strings.TrimPrefix(path, s.path+string(filepath.Separator)) + string(filepath.Separator)
 		if info.IsDir() && strings.HasPrefix(info.Name(), ".") && path != s.path && !strings.HasPrefix(prefix, relPath) {
 			debug.Log("skipping dot dir (relPath: %s, prefix: %s)", relPath, prefix)
@@ -138,13 +156,8 @@ func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
 		if !strings.HasPrefix(name, prefix) {
 			return nil
 		}
-		files = append(files, name)
-		return nil
-	}); err != nil {
-		return nil, err
-	}
-	sort.Strings(files)
-	return files, nil
+		return fn(name)
+	})
 }
 
 // IsDir returns true if the named entity is a directory.
@@ -215,3 +215,34 @@ func newTempDir(t *testing.T) (string, func()) {
 		_ = os.RemoveAll(td)
 	}
 }
+
+func TestWalk(t *testing.T) {
+	ctx := context.Background()
+
+	path, cleanup := newTempDir(t)
+	defer cleanup()
+
+	s := &Store{path}
+	for _, fn := range []string{"foo.gpg", "bar/baz.gpg", "bar/zab.gpg", ".gpg-id"} {
+		assert.NoError(t, s.Set(ctx, fn, []byte(fn)))
+	}
+
+	walked := []string{}
+	assert.NoError(t, s.Walk(ctx, "bar/", func(name string) error {
+		walked = append(walked, name)
+		return nil
+	}))
+	assert.Equal(t, []string{"bar/baz.gpg", "bar/zab.gpg"}, walked)
+
+	// errors stop the walk
+	walked = walked[:0]
+	assert.Error(t, s.Walk(ctx, "", func(name string) error {
+		walked = append(walked, name)
+		return os.ErrClosed
+	}))
+	assert.Len(t, walked, 1)
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Error(t, s.Walk(cctx, "", func(string) error { return nil }))
+}
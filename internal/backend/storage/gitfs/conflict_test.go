@@ -0,0 +1,46 @@
+package gitfs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullConflict(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	ctx := context.Background()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(td, "origin"), 0o700))
+	origin, err := Init(ctx, filepath.Join(td, "origin"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+
+	commit := func(g *Git, name, content string) {
+		require.NoError(t, g.Set(ctx, name, []byte(content)))
+		require.NoError(t, g.Add(ctx, name))
+		require.NoError(t, g.Commit(ctx, "update "+name))
+	}
+	commit(origin, "foo.gpg", "foo")
+
+	clone, err := Clone(ctx, "file://"+origin.Path(), filepath.Join(td, "clone"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+	require.NoError(t, clone.ConfigSet(ctx, "pull.rebase", "false"))
+	assert.Empty(t, clone.ListConflictedFiles(ctx))
+
+	commit(origin, "foo.gpg", "origin")
+	commit(clone, "foo.gpg", "clone")
+
+	err = clone.Pull(ctx, "", "")
+	require.ErrorIs(t, err, store.ErrGitConflict)
+	assert.Contains(t, err.Error(), "foo.gpg")
+	assert.Equal(t, []string{"foo.gpg"}, clone.ListConflictedFiles(ctx))
+}
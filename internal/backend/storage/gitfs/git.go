@@ -4,6 +4,7 @@ package gitfs
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -18,6 +19,7 @@ import (
 	"github.com/gopasspw/gopass/internal/backend/storage/fs"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/fsutil"
@@ -61,7 +63,11 @@ func Clone(ctx context.Context, repo, path, userName, userEmail string) (*Git, e
 	g := &Git{
 		fs: fs.New(path),
 	}
-	if err := g.Cmd(withPathOverride(ctx, filepath.Dir(path)), "Clone", "clone", repo, path); err != nil {
+	if prefixes := backend.GetSparse(ctx); len(prefixes) > 0 {
+		if err := g.cloneSparse(ctx, repo, path, prefixes); err != nil {
+			return nil, err
+		}
+	} else if err := g.Cmd(withPathOverride(ctx, filepath.Dir(path)), "Clone", "clone", repo, path); err != nil {
 		return nil, err
 	}
 
@@ -131,6 +137,7 @@ func (g *Git) captureCmd(ctx context.Context, name string, args ...string) ([]by
 	}
 
 	debug.Log("store.%s: %s %+v (%s)", name, cmd.Path, cmd.Args, g.fs.Path())
+	defer timing.Start(ctx, timing.Git)()
 	err := cmd.Run()
 	return bufOut.Bytes(), bufErr.Bytes(), err
 }
@@ -220,6 +227,22 @@ func (g *Git) ListUntrackedFiles(ctx context.Context) []string {
 	return uf
 }
 
+// ListConflictedFiles lists the files with unresolved merge conflicts.
+func (g *Git) ListConflictedFiles(ctx context.Context) []string {
+	stdout, _, err := g.captureCmd(ctx, "gitDiffConflicts", "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil
+	}
+	cf := []string{}
+	for _, f := range strings.Split(string(stdout), "\n") {
+		if f == "" {
+			continue
+		}
+		cf = append(cf, f)
+	}
+	return cf
+}
+
 // Commit creates a new git commit with the given commit message.
 func (g *Git) Commit(ctx context.Context, msg string) error {
 	if !g.IsInitialized() {
@@ -284,8 +307,10 @@ func (g *Git) PushPull(ctx context.Context, op, remote, branch string) error {
 		return store.ErrGitNoRemote
 	}
 
-	if err := g.Cmd(ctx, "gitPush", "pull", remote, branch); err != nil {
-		if op == "pull" {
+	// fetch and merge separately, so the purge check doesn't need a fetch of
+	// its own.
+	if err := g.pull(ctx, remote, branch); err != nil {
+		if errors.Is(err, store.ErrGitHistoryRewritten) || errors.Is(err, store.ErrGitConflict) || op == "pull" {
 			return err
 		}
 		out.Warningf(ctx, "Failed to pull before git push: %s", err)
@@ -300,6 +325,22 @@ func (g *Git) PushPull(ctx context.Context, op, remote, branch string) error {
 	return g.Cmd(ctx, "gitPush", "push", remote, branch)
 }
 
+func (g *Git) pull(ctx context.Context, remote, branch string) error {
+	if err := g.Cmd(ctx, "gitFetch", "fetch", remote, branch); err != nil {
+		return err
+	}
+	if err := g.checkPurged(ctx); err != nil {
+		return err
+	}
+	if err := g.Cmd(ctx, "gitMerge", "merge", "--no-edit", "FETCH_HEAD"); err != nil {
+		if cf := g.ListConflictedFiles(ctx); len(cf) > 0 {
+			return fmt.Errorf("%w: %s", store.ErrGitConflict, strings.Join(cf, ", "))
+		}
+		return err
+	}
+	return nil
+}
+
 // Push pushes to the git remote.
 func (g *Git) Push(ctx context.Context, remote, branch string) error {
 	if ctxutil.IsNoNetwork(ctx) {
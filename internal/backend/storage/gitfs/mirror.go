@@ -0,0 +1,29 @@
+package gitfs
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// PushMirror pushes the branch to a mirror. Unlike Push it never pulls, a
+// mirror only receives changes.
+func (g *Git) PushMirror(ctx context.Context, remote, branch string) error {
+	if ctxutil.IsNoNetwork(ctx) {
+		debug.Log("Skipping network ops. NoNetwork=true")
+		return nil
+	}
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+	if branch == "" {
+		branch = g.defaultBranch(ctx)
+	}
+	if v, err := g.ConfigGet(ctx, "remote."+remote+".url"); err != nil || v == "" {
+		return store.ErrGitNoRemote
+	}
+
+	return g.Cmd(ctx, "gitPushMirror", "push", remote, branch)
+}
@@ -0,0 +1,46 @@
+package gitfs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushMirror(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	ctx := context.Background()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(td, "store"), 0o700))
+	g, err := Init(ctx, filepath.Join(td, "store"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+	require.NoError(t, g.Set(ctx, "foo.gpg", []byte("foo")))
+	require.NoError(t, g.Add(ctx, "."))
+	require.NoError(t, g.Commit(ctx, "add foo"))
+
+	assert.ErrorIs(t, g.PushMirror(ctx, "offsite", ""), store.ErrGitNoRemote)
+
+	mirror := filepath.Join(td, "mirror.git")
+	cmd := exec.Command("git", "init", "--bare", mirror)
+	require.NoError(t, cmd.Run())
+	require.NoError(t, g.AddRemote(ctx, "offsite", "file://"+mirror))
+
+	require.NoError(t, g.PushMirror(ctx, "offsite", ""))
+
+	cmd = exec.Command("git", "log", "-1", "--format=%s", g.defaultBranch(ctx))
+	cmd.Dir = mirror
+	buf, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "add foo", strings.TrimSpace(string(buf)))
+}
@@ -0,0 +1,161 @@
+package gitfs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// purgeMarker lists the former HEAD commits of every history rewrite. Clones
+// that still contain one of them must not pull, otherwise the purged content
+// would be merged back.
+const purgeMarker = ".gopass-purged"
+
+// Purge removes the given paths from all commits of all branches and drops
+// every unreachable object, so the content can not be recovered from this
+// clone any more. It records the former HEAD in the purge marker and returns
+// it.
+func (g *Git) Purge(ctx context.Context, paths ...string) (string, error) {
+	if !g.IsInitialized() {
+		return "", store.ErrGitNotInit
+	}
+	if len(paths) < 1 {
+		return "", fmt.Errorf("nothing to purge")
+	}
+	if g.HasStagedChanges(ctx) {
+		return "", fmt.Errorf("the store has uncommitted changes")
+	}
+
+	head, err := g.revParse(ctx, "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	quoted := make([]string, 0, len(paths))
+	for _, p := range paths {
+		quoted = append(quoted, shellQuote(p))
+	}
+	filter := "git rm -r --cached --quiet --ignore-unmatch -- " + strings.Join(quoted, " ")
+
+	cmd := exec.CommandContext(ctx, "git", "filter-branch", "--force", "--index-filter", filter, "--prune-empty", "--", "--all")
+	cmd.Dir = g.fs.Path()
+	cmd.Env = append(os.Environ(), "FILTER_BRANCH_SQUELCH_WARNING=1")
+	if buf, err := cmd.CombinedOutput(); err != nil {
+		debug.Log("filter-branch failed: %s", string(buf))
+		return "", fmt.Errorf("failed to rewrite history: %w: %s", err, strings.TrimSpace(string(buf)))
+	}
+
+	// drop the backup refs of filter-branch and all unreachable objects.
+	stdout, _, err := g.captureCmd(ctx, "gitForEachRef", "for-each-ref", "--format=%(refname)", "refs/original/")
+	if err != nil {
+		return "", fmt.Errorf("failed to list backup refs: %w", err)
+	}
+	for _, ref := range strings.Fields(string(stdout)) {
+		if err := g.Cmd(ctx, "gitUpdateRef", "update-ref", "-d", ref); err != nil {
+			return "", err
+		}
+	}
+	if err := g.Cmd(ctx, "gitReflogExpire", "reflog", "expire", "--expire=now", "--all"); err != nil {
+		return "", err
+	}
+	if err := g.Cmd(ctx, "gitGC", "gc", "--prune=now", "--aggressive", "--quiet"); err != nil {
+		return "", err
+	}
+
+	for _, p := range paths {
+		stdout, _, err := g.captureCmd(ctx, "gitLog", "log", "--all", "--format=%H", "--", p)
+		if err != nil {
+			return "", err
+		}
+		if len(strings.TrimSpace(string(stdout))) > 0 {
+			return "", fmt.Errorf("%s is still part of the history", p)
+		}
+	}
+
+	if err := g.recordPurge(ctx, head, paths); err != nil {
+		return "", err
+	}
+
+	return head, nil
+}
+
+// recordPurge appends the former HEAD to the purge marker and commits it.
+func (g *Git) recordPurge(ctx context.Context, head string, paths []string) error {
+	var marker []byte
+	if g.fs.Exists(ctx, purgeMarker) {
+		buf, err := g.fs.Get(ctx, purgeMarker)
+		if err != nil {
+			return err
+		}
+		marker = buf
+	}
+	marker = append(marker, []byte(fmt.Sprintf("%s %s\n", head, time.Now().UTC().Format(time.RFC3339)))...)
+
+	if err := g.fs.Set(ctx, purgeMarker, marker); err != nil {
+		return fmt.Errorf("failed to write purge marker: %w", err)
+	}
+	if err := g.Add(ctx, purgeMarker); err != nil {
+		return err
+	}
+
+	return g.Commit(ctx, fmt.Sprintf("Purged %d paths from the history", len(paths)))
+}
+
+// ForcePush replaces the history of the remote branch with the local one.
+func (g *Git) ForcePush(ctx context.Context, remote, branch string) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+	if branch == "" {
+		branch = g.defaultBranch(ctx)
+	}
+	if remote == "" {
+		remote = g.defaultRemote(ctx, branch)
+	}
+	if v, err := g.ConfigGet(ctx, "remote."+remote+".url"); err != nil || v == "" {
+		return store.ErrGitNoRemote
+	}
+
+	return g.Cmd(ctx, "gitPush", "push", "--force", remote, branch)
+}
+
+// checkPurged returns ErrGitHistoryRewritten if the purge marker of the
+// fetched branch lists a commit that is still part of the local history.
+func (g *Git) checkPurged(ctx context.Context) error {
+	stdout, _, err := g.captureCmd(ctx, "gitShow", "show", "FETCH_HEAD:"+purgeMarker)
+	if err != nil {
+		debug.Log("no purge marker on FETCH_HEAD")
+		return nil
+	}
+
+	for _, line := range strings.Split(string(stdout), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 1 {
+			continue
+		}
+		if err := g.Cmd(ctx, "gitMergeBase", "merge-base", "--is-ancestor", fields[0], "HEAD"); err == nil {
+			debug.Log("purged commit %s is part of the local history", fields[0])
+			return store.ErrGitHistoryRewritten
+		}
+	}
+
+	return nil
+}
+
+func (g *Git) revParse(ctx context.Context, rev string) (string, error) {
+	stdout, _, err := g.captureCmd(ctx, "gitRevParse", "rev-parse", "--verify", rev)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
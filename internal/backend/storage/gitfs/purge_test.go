@@ -0,0 +1,72 @@
+package gitfs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurge(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	ctx := context.Background()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(td, "origin"), 0o700))
+	origin, err := Init(ctx, filepath.Join(td, "origin"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+
+	commit := func(g *Git, msg string) {
+		require.NoError(t, g.Add(ctx, "."))
+		require.NoError(t, g.Commit(ctx, msg))
+	}
+	require.NoError(t, origin.Set(ctx, "keep.gpg", []byte("keep")))
+	require.NoError(t, origin.Set(ctx, "leaked's.gpg", []byte("leaked")))
+	commit(origin, "add secrets")
+	require.NoError(t, origin.Delete(ctx, "leaked's.gpg"))
+	commit(origin, "remove leaked secret")
+
+	clone, err := Clone(ctx, "file://"+origin.Path(), filepath.Join(td, "clone"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+	require.NoError(t, clone.Pull(ctx, "", ""))
+
+	_, err = origin.Purge(ctx)
+	assert.Error(t, err)
+
+	head, err := origin.Purge(ctx, "leaked's.gpg")
+	require.NoError(t, err)
+	assert.NotEmpty(t, head)
+
+	// the content is gone from all revisions and objects.
+	revs, err := origin.Revisions(ctx, "leaked's.gpg")
+	require.NoError(t, err)
+	assert.Empty(t, revs)
+	cmd := exec.Command("git", "rev-list", "--all", "--objects")
+	cmd.Dir = origin.Path()
+	buf, err := cmd.Output()
+	require.NoError(t, err)
+	assert.NotContains(t, string(buf), "leaked")
+	assert.Contains(t, string(buf), "keep.gpg")
+
+	marker, err := origin.Get(ctx, purgeMarker)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(marker), head+" "))
+
+	// the old clone must not merge the purged history back.
+	assert.ErrorIs(t, clone.Pull(ctx, "", ""), store.ErrGitHistoryRewritten)
+
+	// a fresh clone is fine.
+	fresh, err := Clone(ctx, "file://"+origin.Path(), filepath.Join(td, "fresh"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+	assert.NoError(t, fresh.Pull(ctx, "", ""))
+}
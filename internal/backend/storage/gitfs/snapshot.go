@@ -0,0 +1,97 @@
+package gitfs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// snapshotPrefix is the namespace of the tags that mark snapshots.
+const snapshotPrefix = "refs/tags/snapshot/"
+
+// CreateSnapshot marks the current revision with an annotated tag, so the
+// store can be rolled back to it later.
+func (g *Git) CreateSnapshot(ctx context.Context, name, msg string) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+	if err := g.Cmd(ctx, "gitCheckRefFormat", "check-ref-format", snapshotPrefix+name); err != nil {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	if _, err := g.revParse(ctx, snapshotPrefix+name); err == nil {
+		return fmt.Errorf("snapshot %q already exists", name)
+	}
+
+	return g.Cmd(ctx, "gitTag", "tag", "--annotate", "--message", msg, strings.TrimPrefix(snapshotPrefix, "refs/tags/")+name, "HEAD")
+}
+
+// Snapshots returns all snapshots, newest first.
+func (g *Git) Snapshots(ctx context.Context) ([]backend.Snapshot, error) {
+	if !g.IsInitialized() {
+		return nil, store.ErrGitNotInit
+	}
+
+	stdout, stderr, err := g.captureCmd(ctx, "gitForEachRef", "for-each-ref", "--sort=-creatordate",
+		"--format=%(refname)%1f%(*objectname)%1f%(objectname)%1f%(creatordate:unix)%1f%(contents:subject)", snapshotPrefix)
+	if err != nil {
+		debug.Log("Command failed: %s", string(stderr))
+		return nil, err
+	}
+
+	var snaps []backend.Snapshot
+	for _, line := range strings.Split(string(stdout), "\n") {
+		p := strings.Split(line, "\x1f")
+		if len(p) < 5 {
+			continue
+		}
+		s := backend.Snapshot{
+			Name:    strings.TrimPrefix(p[0], snapshotPrefix),
+			Hash:    p[1],
+			Message: p[4],
+		}
+		// %(*objectname) is the commit of annotated tags only.
+		if s.Hash == "" {
+			s.Hash = p[2]
+		}
+		if iv, err := strconv.ParseInt(p[3], 10, 64); err == nil {
+			s.Date = time.Unix(iv, 0)
+		}
+		snaps = append(snaps, s)
+	}
+
+	return snaps, nil
+}
+
+// Rollback restores the content of the store at the given snapshot and
+// commits it. The history is kept, so the rollback can be pushed like any
+// other change and can be undone, too.
+func (g *Git) Rollback(ctx context.Context, name, msg string) error {
+	if !g.IsInitialized() {
+		return store.ErrGitNotInit
+	}
+
+	rev, err := g.revParse(ctx, snapshotPrefix+name+"^{commit}")
+	if err != nil {
+		return fmt.Errorf("snapshot %q not found", name)
+	}
+
+	stdout, _, err := g.captureCmd(ctx, "gitStatus", "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(string(stdout))) > 0 {
+		return fmt.Errorf("the store has uncommitted changes")
+	}
+
+	if err := g.Cmd(ctx, "gitReadTree", "read-tree", "-u", "--reset", rev); err != nil {
+		return fmt.Errorf("failed to restore snapshot %q: %w", name, err)
+	}
+
+	return g.Commit(ctx, msg)
+}
@@ -0,0 +1,73 @@
+package gitfs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	ctx := context.Background()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(td, "store"), 0o700))
+	g, err := Init(ctx, filepath.Join(td, "store"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+
+	commit := func(msg string) {
+		require.NoError(t, g.Add(ctx, "."))
+		require.NoError(t, g.Commit(ctx, msg))
+	}
+	require.NoError(t, g.Set(ctx, "a.gpg", []byte("a1")))
+	require.NoError(t, g.Set(ctx, "b.gpg", []byte("b1")))
+	commit("add secrets")
+
+	require.NoError(t, g.CreateSnapshot(ctx, "before-import", "before import"))
+	assert.Error(t, g.CreateSnapshot(ctx, "before-import", "again"))
+	assert.Error(t, g.CreateSnapshot(ctx, "in..valid", "invalid"))
+
+	snaps, err := g.Snapshots(ctx)
+	require.NoError(t, err)
+	require.Len(t, snaps, 1)
+	assert.Equal(t, "before-import", snaps[0].Name)
+	assert.Equal(t, "before import", snaps[0].Message)
+	head, err := g.revParse(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, head, snaps[0].Hash)
+	assert.False(t, snaps[0].Date.IsZero())
+
+	// the bulk operation changes, adds and removes secrets.
+	require.NoError(t, g.Set(ctx, "a.gpg", []byte("a2")))
+	require.NoError(t, g.Set(ctx, "c.gpg", []byte("c2")))
+	require.NoError(t, g.Delete(ctx, "b.gpg"))
+	commit("import")
+
+	// uncommitted changes are not overwritten.
+	require.NoError(t, g.Set(ctx, "d.gpg", []byte("d")))
+	assert.Error(t, g.Rollback(ctx, "before-import", "rollback"))
+	require.NoError(t, g.Delete(ctx, "d.gpg"))
+
+	assert.Error(t, g.Rollback(ctx, "unknown", "rollback"))
+	require.NoError(t, g.Rollback(ctx, "before-import", "rollback"))
+
+	buf, err := g.Get(ctx, "a.gpg")
+	require.NoError(t, err)
+	assert.Equal(t, "a1", string(buf))
+	assert.True(t, g.Exists(ctx, "b.gpg"))
+	assert.False(t, g.Exists(ctx, "c.gpg"))
+
+	// the history is kept.
+	revs, err := g.Revisions(ctx, "c.gpg")
+	require.NoError(t, err)
+	assert.Len(t, revs, 2)
+	assert.Equal(t, "rollback", revs[0].Subject)
+}
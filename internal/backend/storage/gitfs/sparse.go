@@ -0,0 +1,69 @@
+package gitfs
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+// cloneSparse clones only the given prefixes of repo. The clone doesn't
+// contain any file content outside of these prefixes, that is fetched on
+// demand when a secret outside of them is accessed.
+func (g *Git) cloneSparse(ctx context.Context, repo, path string, prefixes []string) error {
+	if err := g.Cmd(withPathOverride(ctx, filepath.Dir(path)), "Clone", "clone", "--filter=blob:none", "--sparse", repo, path); err != nil {
+		return err
+	}
+
+	// files in the root directory, e.g. the recipients, are always part of
+	// a cone mode sparse checkout. The exported public keys are not.
+	args := []string{"sparse-checkout", "set", ".public-keys"}
+	for _, p := range prefixes {
+		if p = strings.Trim(filepath.ToSlash(p), "/"); p != "" {
+			args = append(args, p)
+		}
+	}
+	if err := g.Cmd(ctx, "SparseCheckout", args...); err != nil {
+		return fmt.Errorf("failed to set up sparse checkout: %w", err)
+	}
+
+	debug.Log("sparse checkout of %q at %q: %q", repo, path, args[3:])
+	return nil
+}
+
+// isSparse returns true if this is a sparse checkout.
+func (g *Git) isSparse(ctx context.Context) bool {
+	if !fsutil.IsFile(filepath.Join(g.fs.Path(), ".git", "info", "sparse-checkout")) {
+		return false
+	}
+	v, err := g.ConfigGet(ctx, "core.sparseCheckout")
+	return err == nil && v == "true"
+}
+
+// fetchOnDemand adds the directory of name to a sparse checkout if name is
+// tracked by git but not checked out. It returns true if the file is
+// available afterwards.
+func (g *Git) fetchOnDemand(ctx context.Context, name string) bool {
+	dir := path.Dir(filepath.ToSlash(name))
+	if dir == "." || !g.isSparse(ctx) {
+		return false
+	}
+
+	// files outside of a sparse checkout are still part of the index.
+	if _, _, err := g.captureCmd(ctx, "LsFiles", "ls-files", "--error-unmatch", "--", name); err != nil {
+		return false
+	}
+
+	if err := g.Cmd(ctx, "SparseCheckout", "sparse-checkout", "add", dir); err != nil {
+		debug.Log("failed to add %q to the sparse checkout: %s", dir, err)
+		return false
+	}
+	out.Noticef(ctx, "Fetched %s on demand", dir)
+
+	return g.fs.Exists(ctx, name)
+}
@@ -0,0 +1,66 @@
+package gitfs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneSparse(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	ctx := context.Background()
+
+	origin := filepath.Join(td, "origin")
+	for _, fn := range []string{".gpg-id", "team/a/foo.gpg", "team/b/bar.gpg", "ops/baz.gpg"} {
+		fp := filepath.Join(origin, filepath.FromSlash(fn))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fp), 0o700))
+		require.NoError(t, os.WriteFile(fp, []byte(fn), 0o600))
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = origin
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	path := filepath.Join(td, "clone")
+	git, err := Clone(backend.WithSparse(ctx, []string{"team/a/"}), "file://"+origin, path, "gopass", "gopass@example.org")
+	require.NoError(t, err)
+	require.NotNil(t, git)
+
+	assert.True(t, git.isSparse(ctx))
+	assert.FileExists(t, filepath.Join(path, ".gpg-id"))
+	assert.FileExists(t, filepath.Join(path, "team", "a", "foo.gpg"))
+	assert.NoFileExists(t, filepath.Join(path, "team", "b", "bar.gpg"))
+	assert.NoFileExists(t, filepath.Join(path, "ops", "baz.gpg"))
+
+	// only checked out secrets are listed
+	l, err := git.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".gitattributes", ".gpg-id", "team/a/foo.gpg"}, l)
+
+	// everything else is fetched on demand
+	assert.True(t, git.Exists(ctx, "team/b/bar.gpg"))
+	assert.FileExists(t, filepath.Join(path, "team", "b", "bar.gpg"))
+
+	buf, err := git.Get(ctx, "ops/baz.gpg")
+	require.NoError(t, err)
+	assert.Equal(t, "ops/baz.gpg", string(buf))
+
+	assert.False(t, git.Exists(ctx, "ops/nope.gpg"))
+}
@@ -0,0 +1,71 @@
+package gitfs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// SyncStatus reports how the local branch relates to its remote. With fetch
+// the remote is queried first, otherwise the state of the last sync is used.
+// Without a remote the status is returned along with ErrGitNoRemote.
+func (g *Git) SyncStatus(ctx context.Context, fetch bool) (backend.SyncStatus, error) {
+	st := backend.SyncStatus{}
+	if !g.IsInitialized() {
+		return st, store.ErrGitNotInit
+	}
+
+	stdout, _, err := g.captureCmd(ctx, "gitStatus", "status", "--porcelain")
+	if err != nil {
+		return st, err
+	}
+	st.Dirty = len(strings.TrimSpace(string(stdout))) > 0
+
+	st.Branch = g.defaultBranch(ctx)
+	st.Remote = g.defaultRemote(ctx, st.Branch)
+	if v, err := g.ConfigGet(ctx, "remote."+st.Remote+".url"); err != nil || v == "" {
+		st.Remote = ""
+		return st, store.ErrGitNoRemote
+	}
+
+	if fetch && !ctxutil.IsNoNetwork(ctx) {
+		if err := g.Cmd(ctx, "gitFetch", "fetch", st.Remote, st.Branch); err != nil {
+			return st, err
+		}
+	}
+
+	// git updates FETCH_HEAD on every fetch and pull.
+	if fi, err := os.Stat(filepath.Join(g.fs.Path(), ".git", "FETCH_HEAD")); err == nil {
+		st.LastSync = fi.ModTime()
+	}
+
+	ref := "refs/remotes/" + st.Remote + "/" + st.Branch
+	stdout, _, err = g.captureCmd(ctx, "gitRevList", "rev-list", "--left-right", "--count", "HEAD..."+ref)
+	if err != nil {
+		// the branch was never pushed or fetched.
+		debug.Log("no remote tracking branch %s: %s", ref, err)
+		return st, nil
+	}
+
+	counts := strings.Fields(string(stdout))
+	if len(counts) != 2 {
+		return st, fmt.Errorf("unexpected rev-list output: %q", string(stdout))
+	}
+	if st.Ahead, err = strconv.Atoi(counts[0]); err != nil {
+		return st, err
+	}
+	if st.Behind, err = strconv.Atoi(counts[1]); err != nil {
+		return st, err
+	}
+	st.Tracking = true
+
+	return st, nil
+}
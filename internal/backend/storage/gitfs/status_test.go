@@ -0,0 +1,65 @@
+package gitfs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncStatus(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	ctx := context.Background()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(td, "origin"), 0o700))
+	origin, err := Init(ctx, filepath.Join(td, "origin"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+
+	commit := func(g *Git, name string) {
+		require.NoError(t, g.Set(ctx, name, []byte(name)))
+		require.NoError(t, g.Add(ctx, name))
+		require.NoError(t, g.Commit(ctx, "add "+name))
+	}
+	commit(origin, "foo.gpg")
+
+	st, err := origin.SyncStatus(ctx, false)
+	assert.ErrorIs(t, err, store.ErrGitNoRemote)
+	assert.False(t, st.Dirty)
+
+	clone, err := Clone(ctx, "file://"+origin.Path(), filepath.Join(td, "clone"), "gopass", "gopass@example.org")
+	require.NoError(t, err)
+	require.NoError(t, clone.Pull(ctx, "", ""))
+
+	st, err = clone.SyncStatus(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, "origin", st.Remote)
+	assert.True(t, st.Tracking)
+	assert.Equal(t, 0, st.Ahead)
+	assert.Equal(t, 0, st.Behind)
+	assert.False(t, st.LastSync.IsZero())
+
+	commit(clone, "bar.gpg")
+	commit(origin, "baz.gpg")
+	require.NoError(t, clone.Set(ctx, "dirty.gpg", []byte("dirty")))
+
+	// without fetching the new commit in origin is unknown.
+	st, err = clone.SyncStatus(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, st.Ahead)
+	assert.Equal(t, 0, st.Behind)
+	assert.True(t, st.Dirty)
+
+	st, err = clone.SyncStatus(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, st.Ahead)
+	assert.Equal(t, 1, st.Behind)
+}
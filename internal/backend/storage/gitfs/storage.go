@@ -5,8 +5,12 @@ import (
 	"fmt"
 )
 
-// Get retrieves the named content.
+// Get retrieves the named content. In a sparse checkout content outside of
+// the checked out prefixes is fetched on demand.
 func (g *Git) Get(ctx context.Context, name string) ([]byte, error) {
+	if !g.fs.Exists(ctx, name) {
+		g.fetchOnDemand(ctx, name)
+	}
 	return g.fs.Get(ctx, name)
 }
 
@@ -20,9 +24,10 @@ func (g *Git) Delete(ctx context.Context, name string) error {
 	return g.fs.Delete(ctx, name)
 }
 
-// Exists checks if the named entity exists.
+// Exists checks if the named entity exists. In a sparse checkout content
+// outside of the checked out prefixes is fetched on demand.
 func (g *Git) Exists(ctx context.Context, name string) bool {
-	return g.fs.Exists(ctx, name)
+	return g.fs.Exists(ctx, name) || g.fetchOnDemand(ctx, name)
 }
 
 // List returns a list of all entities
@@ -32,6 +37,11 @@ func (g *Git) List(ctx context.Context, prefix string) ([]string, error) {
 	return g.fs.List(ctx, prefix)
 }
 
+// Walk calls fn for every entity below prefix.
+func (g *Git) Walk(ctx context.Context, prefix string, fn func(string) error) error {
+	return g.fs.Walk(ctx, prefix, fn)
+}
+
 // IsDir returns true if the named entity is a directory.
 func (g *Git) IsDir(ctx context.Context, name string) bool {
 	return g.fs.IsDir(ctx, name)
@@ -0,0 +1,191 @@
+// Package bundle implements signed and encrypted archives of secrets that can
+// be carried to an air-gapped machine.
+//
+// A bundle is a tar archive containing the secrets and the public keys of
+// their recipients. It's signed with a detached signature. Both are wrapped
+// in another tar archive which is encrypted for the recipients of the
+// bundle. This way the signer is only revealed to them.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+)
+
+const (
+	version     = "gopass-bundle/1"
+	versionFile = "version"
+	payloadFile = "payload.tar"
+	sigFile     = "payload.sig"
+	secretsDir  = "secrets/"
+	keysDir     = "keys/"
+)
+
+// Bundle is the content of a bundle.
+type Bundle struct {
+	// Secrets maps secret names to their plaintext.
+	Secrets map[string][]byte
+	// Keys maps key IDs to their exported public keys.
+	Keys map[string][]byte
+}
+
+// New creates an empty bundle.
+func New() *Bundle {
+	return &Bundle{
+		Secrets: make(map[string][]byte),
+		Keys:    make(map[string][]byte),
+	}
+}
+
+// Names returns the sorted names of all secrets.
+func (b *Bundle) Names() []string {
+	names := make([]string, 0, len(b.Secrets))
+	for name := range b.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Seal signs the bundle and encrypts it for the given recipients.
+func Seal(ctx context.Context, crypto backend.Crypto, signer backend.Signer, b *Bundle, recipients []string) ([]byte, error) {
+	files := make(map[string][]byte, len(b.Secrets)+len(b.Keys))
+	for name, content := range b.Secrets {
+		files[secretsDir+name] = content
+	}
+	for id, key := range b.Keys {
+		files[keysDir+id] = key
+	}
+	payload, err := writeTar(files)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := signer.Sign(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign bundle: %w", err)
+	}
+
+	envelope, err := writeTar(map[string][]byte{
+		versionFile: []byte(version),
+		payloadFile: payload,
+		sigFile:     sig,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := crypto.Encrypt(ctx, envelope, recipients)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt bundle: %w", err)
+	}
+
+	return buf, nil
+}
+
+// Open decrypts a bundle and verifies its signature. It returns the bundle
+// and the fingerprint of the signer.
+func Open(ctx context.Context, crypto backend.Crypto, signer backend.Signer, buf []byte) (*Bundle, string, error) {
+	envelope, err := crypto.Decrypt(ctx, buf)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decrypt bundle: %w", err)
+	}
+
+	files, err := readTar(envelope)
+	if err != nil {
+		return nil, "", err
+	}
+	if v := string(files[versionFile]); v != version {
+		return nil, "", fmt.Errorf("unsupported bundle version %q", v)
+	}
+
+	fp, err := signer.Verify(ctx, files[payloadFile], files[sigFile])
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to verify bundle signature: %w", err)
+	}
+
+	payload, err := readTar(files[payloadFile])
+	if err != nil {
+		return nil, "", err
+	}
+
+	b := New()
+	for fn, content := range payload {
+		switch {
+		case strings.HasPrefix(fn, secretsDir):
+			b.Secrets[strings.TrimPrefix(fn, secretsDir)] = content
+		case strings.HasPrefix(fn, keysDir):
+			b.Keys[strings.TrimPrefix(fn, keysDir)] = content
+		default:
+			return nil, "", fmt.Errorf("unexpected file %q in bundle", fn)
+		}
+	}
+
+	return b, fp, nil
+}
+
+func writeTar(files map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, name := range names {
+		hdr := &tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(files[name])),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func readTar(buf []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(buf))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected entry %q in archive", hdr.Name)
+		}
+
+		// never allow names to escape the store
+		if name := path.Clean(hdr.Name); name != hdr.Name || path.IsAbs(name) || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("invalid name %q in archive", hdr.Name)
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		files[hdr.Name] = content
+	}
+}
@@ -0,0 +1,66 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	ctx := context.Background()
+	crypto := plain.New()
+
+	b := New()
+	b.Secrets["db/prod"] = []byte("s3cret\nuser: admin\n")
+	b.Secrets["api"] = []byte("token")
+	b.Keys["0xDEADBEEF"] = []byte("public key")
+
+	buf, err := Seal(ctx, crypto, crypto, b, []string{"0xDEADBEEF"})
+	require.NoError(t, err)
+
+	got, signer, err := Open(ctx, crypto, crypto, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "0xDEADBEEF", signer)
+	assert.Equal(t, b, got)
+	assert.Equal(t, []string{"api", "db/prod"}, got.Names())
+}
+
+func TestOpenTampered(t *testing.T) {
+	ctx := context.Background()
+	crypto := plain.New()
+
+	b := New()
+	b.Secrets["foo"] = []byte("bar")
+	buf, err := Seal(ctx, crypto, crypto, b, nil)
+	require.NoError(t, err)
+
+	files, err := readTar(buf)
+	require.NoError(t, err)
+	payload, err := writeTar(map[string][]byte{"secrets/foo": []byte("evil")})
+	require.NoError(t, err)
+	files[payloadFile] = payload
+	buf, err = writeTar(files)
+	require.NoError(t, err)
+
+	_, _, err = Open(ctx, crypto, crypto, buf)
+	assert.Error(t, err)
+}
+
+func TestReadTarInvalidName(t *testing.T) {
+	for _, name := range []string{"../etc/passwd", "/etc/passwd", "secrets/../../foo"} {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: 1}))
+		_, err := tw.Write([]byte("x"))
+		require.NoError(t, err)
+		require.NoError(t, tw.Close())
+
+		_, err = readTar(buf.Bytes())
+		assert.Error(t, err, name)
+	}
+}
@@ -19,9 +19,13 @@ type OnDisk struct {
 	dir  string
 }
 
+func diskDir() string {
+	return filepath.Join(appdir.UserCache(), "gopass")
+}
+
 // NewOnDisk creates a new on disk cache.
 func NewOnDisk(name string, ttl time.Duration) (*OnDisk, error) {
-	d := filepath.Join(appdir.UserCache(), "gopass", name)
+	d := filepath.Join(diskDir(), name)
 	debug.Log("New on disk cache %s created at %s", name, d)
 
 	o := &OnDisk{
@@ -98,3 +102,43 @@ func (o *OnDisk) Remove(key string) error {
 func (o *OnDisk) Purge() error {
 	return os.RemoveAll(o.dir)
 }
+
+// Stat is the size of an on disk cache.
+type Stat struct {
+	Name    string `json:"name"`
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// Stats returns the size of all on disk caches.
+func Stats() ([]Stat, error) {
+	dirs, err := os.ReadDir(diskDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stats := make([]Stat, 0, len(dirs))
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(diskDir(), d.Name()))
+		if err != nil {
+			return stats, err
+		}
+		st := Stat{Name: d.Name()}
+		for _, f := range files {
+			fi, err := f.Info()
+			if err != nil || !fi.Mode().IsRegular() {
+				continue
+			}
+			st.Entries++
+			st.Bytes += fi.Size()
+		}
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
@@ -31,3 +31,25 @@ func TestOnDisk(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []string{"bar"}, res)
 }
+
+func TestStats(t *testing.T) {
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	st, err := Stats()
+	require.NoError(t, err)
+	assert.Empty(t, st)
+
+	odc, err := NewOnDisk("test", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, odc.Set("foo", []string{"bar"}))
+	require.NoError(t, odc.Set("baz", []string{"a", "b"}))
+	_, err = NewOnDisk("empty", time.Hour)
+	require.NoError(t, err)
+
+	st, err = Stats()
+	require.NoError(t, err)
+	assert.Equal(t, []Stat{
+		{Name: "empty"},
+		{Name: "test", Entries: 2, Bytes: 6},
+	}, st)
+}
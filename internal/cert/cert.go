@@ -0,0 +1,63 @@
+// Package cert finds and describes X.509 certificates stored in secrets.
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/expiry"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Parse returns all PEM encoded certificates contained in buf. Other PEM
+// blocks (e.g. private keys) and invalid certificates are ignored.
+func Parse(buf []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, buf = pem.Decode(buf)
+		if block == nil {
+			return certs
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			debug.Log("failed to parse certificate: %s", err)
+			continue
+		}
+		certs = append(certs, c)
+	}
+}
+
+// Info returns a human readable summary of the certificate.
+func Info(c *x509.Certificate, now time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Subject:    %s\n", c.Subject)
+	fmt.Fprintf(&sb, "Issuer:     %s\n", c.Issuer)
+	if sans := SANs(c); len(sans) > 0 {
+		fmt.Fprintf(&sb, "SANs:       %s\n", strings.Join(sans, ", "))
+	}
+	fmt.Fprintf(&sb, "Serial:     %s\n", c.SerialNumber.Text(16))
+	fmt.Fprintf(&sb, "Not before: %s\n", c.NotBefore.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Not after:  %s (%s)\n", c.NotAfter.Format(time.RFC3339), expiry.Describe(c.NotAfter, now))
+	return sb.String()
+}
+
+// SANs returns the subject alternative names of the certificate.
+func SANs(c *x509.Certificate) []string {
+	sans := make([]string, 0, len(c.DNSNames)+len(c.IPAddresses)+len(c.EmailAddresses)+len(c.URIs))
+	sans = append(sans, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, c.EmailAddresses...)
+	for _, u := range c.URIs {
+		sans = append(sans, u.String())
+	}
+	return sans
+}
@@ -0,0 +1,68 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCert(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParse(t *testing.T) {
+	notAfter := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Len(t, Parse([]byte("foobar")), 0)
+
+	buf := []byte("password\n")
+	buf = append(buf, testCert(t, notAfter)...)
+	buf = append(buf, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("foo")})...)
+	buf = append(buf, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})...)
+	buf = append(buf, testCert(t, notAfter)...)
+
+	certs := Parse(buf)
+	require.Len(t, certs, 2)
+	assert.Equal(t, "CN=example.com", certs[0].Subject.String())
+}
+
+func TestInfo(t *testing.T) {
+	notAfter := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	certs := Parse(testCert(t, notAfter))
+	require.Len(t, certs, 1)
+
+	assert.Equal(t, []string{"example.com", "www.example.com", "127.0.0.1"}, SANs(certs[0]))
+	assert.Equal(t, `Subject:    CN=example.com
+Issuer:     CN=example.com
+SANs:       example.com, www.example.com, 127.0.0.1
+Serial:     2a
+Not before: 2021-06-01T00:00:00Z
+Not after:  2022-06-01T00:00:00Z (expires in 10 days)
+`, Info(certs[0], notAfter.Add(-10*24*time.Hour)))
+}
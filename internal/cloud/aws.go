@@ -0,0 +1,193 @@
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	// SecretsManager is the AWS Secrets Manager service.
+	SecretsManager = "secretsmanager"
+	// SSM is the AWS Systems Manager Parameter Store.
+	SSM = "ssm"
+)
+
+// runFunc runs the aws CLI with the given arguments and stdin and returns
+// its stdout.
+type runFunc func(ctx context.Context, stdin []byte, args ...string) ([]byte, error)
+
+// AWS stores values in Secrets Manager or SSM Parameter Store using the aws
+// CLI. Values are always passed on stdin so they don't show up in the
+// process list.
+type AWS struct {
+	service string
+	region  string
+	run     runFunc
+}
+
+// NewAWS returns a provider for the given AWS service.
+func NewAWS(service, region string) (*AWS, error) {
+	switch service {
+	case SecretsManager, SSM:
+	default:
+		return nil, fmt.Errorf("unsupported service %q. Use %s or %s", service, SecretsManager, SSM)
+	}
+
+	return &AWS{
+		service: service,
+		region:  region,
+		run:     runAWS,
+	}, nil
+}
+
+// Name returns the name of the service.
+func (a *AWS) Name() string {
+	return "aws " + a.service
+}
+
+// List returns all values whose name starts with prefix.
+func (a *AWS) List(ctx context.Context, prefix string) (map[string]string, error) {
+	if a.service == SSM {
+		return a.listSSM(ctx, prefix)
+	}
+
+	return a.listSecretsManager(ctx, prefix)
+}
+
+// Put creates or updates the value. create must be true if the name doesn't
+// exist yet.
+func (a *AWS) Put(ctx context.Context, name, value string, create bool) error {
+	var (
+		cmd   string
+		input map[string]any
+	)
+	switch {
+	case a.service == SSM:
+		cmd = "put-parameter"
+		input = map[string]any{
+			"Name":      name,
+			"Value":     value,
+			"Type":      "SecureString",
+			"Overwrite": true,
+		}
+	case create:
+		cmd = "create-secret"
+		input = map[string]any{
+			"Name":         name,
+			"SecretString": value,
+		}
+	default:
+		cmd = "put-secret-value"
+		input = map[string]any{
+			"SecretId":     name,
+			"SecretString": value,
+		}
+	}
+
+	buf, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	_, err = a.aws(ctx, buf, cmd, "--cli-input-json", "file:///dev/stdin")
+
+	return err
+}
+
+func (a *AWS) listSSM(ctx context.Context, prefix string) (map[string]string, error) {
+	path := strings.TrimSuffix(prefix, "/")
+	if path == "" {
+		path = "/"
+	}
+	buf, err := a.aws(ctx, nil, "get-parameters-by-path", "--path", path, "--recursive", "--with-decryption")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Parameters []struct {
+			Name  string
+			Value string
+		}
+	}
+	if err := json.Unmarshal(buf, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	vals := make(map[string]string, len(resp.Parameters))
+	for _, p := range resp.Parameters {
+		if strings.HasPrefix(p.Name, prefix) {
+			vals[p.Name] = p.Value
+		}
+	}
+
+	return vals, nil
+}
+
+func (a *AWS) listSecretsManager(ctx context.Context, prefix string) (map[string]string, error) {
+	buf, err := a.aws(ctx, nil, "list-secrets", "--filters", "Key=name,Values="+prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		SecretList []struct {
+			Name string
+		}
+	}
+	if err := json.Unmarshal(buf, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	vals := make(map[string]string, len(resp.SecretList))
+	for _, s := range resp.SecretList {
+		// the name filter also matches in the middle of names
+		if !strings.HasPrefix(s.Name, prefix) {
+			continue
+		}
+
+		buf, err := a.aws(ctx, nil, "get-secret-value", "--secret-id", s.Name)
+		if err != nil {
+			return nil, err
+		}
+		var val struct {
+			SecretString string
+		}
+		if err := json.Unmarshal(buf, &val); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		vals[s.Name] = val.SecretString
+	}
+
+	return vals, nil
+}
+
+func (a *AWS) aws(ctx context.Context, stdin []byte, cmd string, args ...string) ([]byte, error) {
+	args = append([]string{a.service, cmd, "--output", "json"}, args...)
+	if a.region != "" {
+		args = append(args, "--region", a.region)
+	}
+
+	return a.run(ctx, stdin, args...)
+}
+
+func runAWS(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	debug.Log("running aws %+v", args)
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "aws", args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stderr = stderr
+
+	buf, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("aws %s %s failed: %w: %s", args[0], args[1], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,95 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	args  string
+	stdin map[string]any
+}
+
+// fakeRun returns a runFunc that records all calls and answers with the
+// response for the first matching argument prefix.
+func fakeRun(t *testing.T, calls *[]call, responses map[string]string) runFunc {
+	t.Helper()
+
+	return func(_ context.Context, stdin []byte, args ...string) ([]byte, error) {
+		c := call{args: strings.Join(args, " ")}
+		if len(stdin) > 0 {
+			require.NoError(t, json.Unmarshal(stdin, &c.stdin))
+		}
+		*calls = append(*calls, c)
+
+		for prefix, resp := range responses {
+			if strings.HasPrefix(c.args, prefix) {
+				return []byte(resp), nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected call: %s", c.args)
+	}
+}
+
+func TestNewAWS(t *testing.T) {
+	_, err := NewAWS("s3", "")
+	assert.Error(t, err)
+
+	a, err := NewAWS(SSM, "")
+	require.NoError(t, err)
+	assert.Equal(t, "aws ssm", a.Name())
+}
+
+func TestAWSSSM(t *testing.T) {
+	ctx := context.Background()
+
+	var calls []call
+	a, err := NewAWS(SSM, "eu-central-1")
+	require.NoError(t, err)
+	a.run = fakeRun(t, &calls, map[string]string{
+		"ssm get-parameters-by-path": `{"Parameters":[{"Name":"/app/db","Value":"s3cret"},{"Name":"/apple","Value":"x"}]}`,
+		"ssm put-parameter":          `{"Version":2}`,
+	})
+
+	vals, err := a.List(ctx, "/app/")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/app/db": "s3cret"}, vals)
+	assert.Equal(t, "ssm get-parameters-by-path --output json --path /app --recursive --with-decryption --region eu-central-1", calls[0].args)
+
+	require.NoError(t, a.Put(ctx, "/app/db", "new", false))
+	assert.Equal(t, "ssm put-parameter --output json --cli-input-json file:///dev/stdin --region eu-central-1", calls[1].args)
+	assert.Equal(t, map[string]any{"Name": "/app/db", "Value": "new", "Type": "SecureString", "Overwrite": true}, calls[1].stdin)
+}
+
+func TestAWSSecretsManager(t *testing.T) {
+	ctx := context.Background()
+
+	var calls []call
+	a, err := NewAWS(SecretsManager, "")
+	require.NoError(t, err)
+	a.run = fakeRun(t, &calls, map[string]string{
+		"secretsmanager list-secrets":     `{"SecretList":[{"Name":"app/db"},{"Name":"other/app/db"}]}`,
+		"secretsmanager get-secret-value": `{"Name":"app/db","SecretString":"s3cret"}`,
+		"secretsmanager create-secret":    `{}`,
+		"secretsmanager put-secret-value": `{}`,
+	})
+
+	vals, err := a.List(ctx, "app/")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app/db": "s3cret"}, vals)
+	assert.Equal(t, "secretsmanager list-secrets --output json --filters Key=name,Values=app/", calls[0].args)
+	assert.Equal(t, "secretsmanager get-secret-value --output json --secret-id app/db", calls[1].args)
+
+	require.NoError(t, a.Put(ctx, "app/new", "foo", true))
+	assert.Equal(t, map[string]any{"Name": "app/new", "SecretString": "foo"}, calls[2].stdin)
+
+	require.NoError(t, a.Put(ctx, "app/db", "bar", false))
+	assert.Equal(t, map[string]any{"SecretId": "app/db", "SecretString": "bar"}, calls[3].stdin)
+	assert.True(t, strings.HasPrefix(calls[3].args, "secretsmanager put-secret-value"))
+}
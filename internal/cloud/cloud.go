@@ -0,0 +1,69 @@
+// Package cloud synchronizes secrets with secret stores of cloud providers.
+package cloud
+
+import (
+	"context"
+	"sort"
+)
+
+// Provider is a remote key-value store for secrets.
+type Provider interface {
+	// Name returns a human readable name of the provider.
+	Name() string
+	// List returns all values whose name starts with prefix.
+	List(ctx context.Context, prefix string) (map[string]string, error)
+	// Put creates (if create is true) or updates a value.
+	Put(ctx context.Context, name, value string, create bool) error
+}
+
+// Op is the kind of a change.
+type Op int
+
+const (
+	// Unchanged means source and destination are equal.
+	Unchanged Op = iota
+	// Create means the value is missing in the destination.
+	Create
+	// Update means the destination holds a different value.
+	Update
+)
+
+// String returns the diff symbol of the operation.
+func (o Op) String() string {
+	switch o {
+	case Create:
+		return "+"
+	case Update:
+		return "~"
+	default:
+		return "="
+	}
+}
+
+// Change is a single difference between source and destination.
+type Change struct {
+	Name  string
+	Op    Op
+	Value string
+}
+
+// Diff returns the changes required to make dst contain all values of src,
+// sorted by name. Values only present in dst are ignored.
+func Diff(src, dst map[string]string) []Change {
+	changes := make([]Change, 0, len(src))
+	for name, val := range src {
+		c := Change{
+			Name:  name,
+			Value: val,
+		}
+		if old, found := dst[name]; !found {
+			c.Op = Create
+		} else if old != val {
+			c.Op = Update
+		}
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+
+	return changes
+}
@@ -0,0 +1,30 @@
+package cloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	src := map[string]string{
+		"/app/a": "1",
+		"/app/b": "2",
+		"/app/c": "3",
+	}
+	dst := map[string]string{
+		"/app/b": "2",
+		"/app/c": "old",
+		"/app/d": "4",
+	}
+
+	assert.Equal(t, []Change{
+		{Name: "/app/a", Op: Create, Value: "1"},
+		{Name: "/app/b", Op: Unchanged, Value: "2"},
+		{Name: "/app/c", Op: Update, Value: "3"},
+	}, Diff(src, dst))
+
+	assert.Equal(t, "+", Create.String())
+	assert.Equal(t, "~", Update.String())
+	assert.Equal(t, "=", Unchanged.String())
+}
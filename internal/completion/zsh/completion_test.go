@@ -61,6 +61,7 @@ func TestFormatflagFunc(t *testing.T) {
 	ff := formatFlagFunc()
 	for _, flag := range []cli.Flag{
 		&cli.BoolFlag{Name: "foo", Usage: "bar"},
+		&cli.DurationFlag{Name: "foo", Usage: "bar"},
 		&cli.Float64Flag{Name: "foo", Usage: "bar"},
 		&cli.GenericFlag{Name: "foo", Usage: "bar"},
 		&cli.Int64Flag{Name: "foo", Usage: "bar"},
@@ -49,6 +49,10 @@ _{{ $prog }}_complete_passwords () {
     local IFS=$'\n'
     _arguments : \
 	"--clip[Copy the first line of the secret into the clipboard]"
+    if [[ "$PREFIX" == *:* ]]; then
+	compadd -Q -- $({{ $prog }} show "$PREFIX" --generate-bash-completion)
+	return
+    fi
     _values 'passwords' $({{ $prog }} ls --flat)
 }
 
@@ -1,11 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+
+	"golang.org/x/exp/maps"
 )
 
 var (
@@ -15,18 +19,38 @@ var (
 	ErrConfigNotParsed = fmt.Errorf("config not parseable")
 )
 
+// Values for the showaction option.
+const (
+	// ShowActionPrint prints the secret. This is the default.
+	ShowActionPrint = "print"
+	// ShowActionClip copies the password to the clipboard.
+	ShowActionClip = "clip"
+	// ShowActionBoth copies the password to the clipboard and prints the secret.
+	ShowActionBoth = "both"
+)
+
 // Config is the current config struct.
 type Config struct {
-	AutoClip      bool              `yaml:"autoclip"`      // decide whether passwords are automatically copied or not.
-	AutoImport    bool              `yaml:"autoimport"`    // import missing public keys w/o asking.
-	ClipTimeout   int               `yaml:"cliptimeout"`   // clear clipboard after seconds.
-	ExportKeys    bool              `yaml:"exportkeys"`    // automatically export public keys of all recipients.
-	NoPager       bool              `yaml:"nopager"`       // do not invoke a pager to display long lists.
-	Notifications bool              `yaml:"notifications"` // enable desktop notifications.
-	Parsing       bool              `yaml:"parsing"`       // allows to switch off all output parsing.
-	Path          string            `yaml:"path"`
-	SafeContent   bool              `yaml:"safecontent"` // avoid showing passwords in terminal.
-	Mounts        map[string]string `yaml:"mounts"`
+	AuditLog        bool                   `yaml:"auditlog"`        // keep a hash-chained log of all operations.
+	AutoClip        bool                   `yaml:"autoclip"`        // decide whether passwords are automatically copied or not.
+	AutoImport      bool                   `yaml:"autoimport"`      // import missing public keys w/o asking.
+	ClipTimeout     int                    `yaml:"cliptimeout"`     // clear clipboard after seconds.
+	Decoy           string                 `yaml:"decoy,omitempty"` // path to a decoy root store.
+	ExportKeys      bool                   `yaml:"exportkeys"`      // automatically export public keys of all recipients.
+	GPGTimeout      int                    `yaml:"gpgtimeout"`      // abort gpg operations after seconds.
+	KeyCache        bool                   `yaml:"keycache"`        // persist key listings across invocations.
+	NoPager         bool                   `yaml:"nopager"`         // do not invoke a pager to display long lists.
+	Notifications   bool                   `yaml:"notifications"`   // enable desktop notifications.
+	Parsing         bool                   `yaml:"parsing"`         // allows to switch off all output parsing.
+	Path            string                 `yaml:"path"`
+	PinentryTimeout int                    `yaml:"pinentrytimeout"` // abort gpg operations that ask for a passphrase after seconds.
+	SafeContent     bool                   `yaml:"safecontent"`     // avoid showing passwords in terminal.
+	ShowAction      string                 `yaml:"showaction"`      // what gopass show does by default: print, clip or both.
+	UsageLog        bool                   `yaml:"usagelog"`        // record when secrets were last shown or copied.
+	Mounts          map[string]string      `yaml:"mounts"`
+	Stores          map[string]StoreConfig `yaml:"stores,omitempty"` // per-store options, the root store uses the empty alias.
+	Hooks           map[string]HookConfig  `yaml:"hooks,omitempty"`  // commands run on store events, keyed by event name.
+	Version         int                    `yaml:"version"`          // format of the config file, see ConfigVersion.
 
 	ConfigPath string `yaml:"-"`
 
@@ -44,6 +68,8 @@ func New() *Config {
 		Notifications: true,
 		Parsing:       true,
 		Path:          PwStoreDir(""),
+		ShowAction:    ShowActionPrint,
+		Version:       ConfigVersion,
 		ConfigPath:    configLocation(),
 	}
 }
@@ -69,11 +95,63 @@ func (c *Config) SetConfigValue(key, value string) error {
 
 // setConfigValue will try to set the given key to the value in the config struct.
 func (c *Config) setConfigValue(key, value string) error {
+	nc := *c
+	if err := setField(reflect.ValueOf(&nc).Elem(), key, value); err != nil {
+		return err
+	}
+	if err := nc.validate(); err != nil {
+		return err
+	}
+	*c = nc
+	return nil
+}
+
+// validate returns an OptionError for the first invalid option.
+func (c *Config) validate() error {
+	switch c.ShowAction {
+	case "", ShowActionPrint, ShowActionClip, ShowActionBoth:
+	default:
+		return &OptionError{
+			Key: "showaction",
+			Err: fmt.Errorf("unknown show action %q. Must be one of %s, %s or %s", c.ShowAction, ShowActionPrint, ShowActionClip, ShowActionBoth),
+		}
+	}
+
+	events := maps.Keys(c.Hooks)
+	sort.Strings(events)
+	for _, event := range events {
+		switch event {
+		case HookPreInsert, HookPostGenerate, HookPostSync, HookPreDelete:
+		default:
+			return &OptionError{
+				Key: "hooks." + event,
+				Err: fmt.Errorf("unknown event. Must be one of %s, %s, %s or %s", HookPreInsert, HookPostGenerate, HookPostSync, HookPreDelete),
+			}
+		}
+	}
+
+	aliases := maps.Keys(c.Stores)
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		if err := c.Stores[alias].validate(); err != nil {
+			var oe *OptionError
+			if errors.As(err, &oe) {
+				return &OptionError{Key: "stores." + alias + "." + oe.Key, Err: oe.Err}
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setField will try to set the field with the given yaml key of the struct o
+// to value.
+func setField(o reflect.Value, key, value string) error {
 	value = strings.ToLower(value)
-	o := reflect.ValueOf(c).Elem()
 	for i := 0; i < o.NumField(); i++ {
-		jsonArg := o.Type().Field(i).Tag.Get("yaml")
-		if jsonArg == "" || jsonArg == "-" {
+		jsonArg := yamlKey(o.Type().Field(i))
+		if jsonArg == "" || jsonArg == "-" || jsonArg == versionKey {
 			continue
 		}
 		if jsonArg != key {
@@ -108,6 +186,12 @@ func (c *Config) setConfigValue(key, value string) error {
 	return fmt.Errorf("unknown config option %q", key)
 }
 
+// yamlKey returns the name of the yaml key without any options.
+func yamlKey(f reflect.StructField) string {
+	k, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	return k
+}
+
 func (c *Config) String() string {
 	return fmt.Sprintf("%#v", c)
 }
@@ -119,11 +203,16 @@ func (c *Config) Directory() string {
 
 // ConfigMap returns a map of stringified config values for easy printing.
 func (c *Config) ConfigMap() map[string]string {
+	return fieldMap(reflect.ValueOf(c).Elem())
+}
+
+// fieldMap returns a map of stringified values of all (scalar) fields of the
+// struct o.
+func fieldMap(o reflect.Value) map[string]string {
 	m := make(map[string]string, 20)
-	o := reflect.ValueOf(c).Elem()
 	for i := 0; i < o.NumField(); i++ {
-		jsonArg := o.Type().Field(i).Tag.Get("yaml")
-		if jsonArg == "" || jsonArg == "-" {
+		jsonArg := yamlKey(o.Type().Field(i))
+		if jsonArg == "" || jsonArg == "-" || jsonArg == versionKey {
 			continue
 		}
 		f := o.Field(i)
@@ -1,11 +1,13 @@
 package config_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	_ "github.com/gopasspw/gopass/internal/backend/crypto"
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	_ "github.com/gopasspw/gopass/internal/backend/storage"
 	"github.com/gopasspw/gopass/internal/config"
 	"github.com/stretchr/testify/assert"
@@ -20,8 +22,8 @@ func TestNewConfig(t *testing.T) {
 
 	cfg := config.New()
 	cs := cfg.String()
-	assert.Contains(t, cs, `&config.Config{AutoClip:false, AutoImport:true, ClipTimeout:45, ExportKeys:true, NoPager:false, Notifications:true,`)
-	assert.Contains(t, cs, `SafeContent:false, Mounts:map[string]string{},`)
+	assert.Contains(t, cs, `&config.Config{AuditLog:false, AutoClip:false, AutoImport:true, ClipTimeout:45, Decoy:"", ExportKeys:true, GPGTimeout:0, KeyCache:false, NoPager:false, Notifications:true,`)
+	assert.Contains(t, cs, `SafeContent:false, ShowAction:"print", UsageLog:false, Mounts:map[string]string{},`)
 
 	cfg = &config.Config{
 		Mounts: map[string]string{
@@ -30,8 +32,8 @@ func TestNewConfig(t *testing.T) {
 		},
 	}
 	cs = cfg.String()
-	assert.Contains(t, cs, `&config.Config{AutoClip:false, AutoImport:false, ClipTimeout:0, ExportKeys:false, NoPager:false, Notifications:false,`)
-	assert.Contains(t, cs, `SafeContent:false, Mounts:map[string]string{"bar":"", "foo":""},`)
+	assert.Contains(t, cs, `&config.Config{AuditLog:false, AutoClip:false, AutoImport:false, ClipTimeout:0, Decoy:"", ExportKeys:false, GPGTimeout:0, KeyCache:false, NoPager:false, Notifications:false,`)
+	assert.Contains(t, cs, `SafeContent:false, ShowAction:"", UsageLog:false, Mounts:map[string]string{"bar":"", "foo":""},`)
 }
 
 func TestSetConfigValue(t *testing.T) {
@@ -42,4 +44,55 @@ func TestSetConfigValue(t *testing.T) {
 	assert.NoError(t, cfg.SetConfigValue("cliptimeout", "900"))
 	assert.NoError(t, cfg.SetConfigValue("path", "/tmp"))
 	assert.Error(t, cfg.SetConfigValue("autoclip", "yo"))
+
+	assert.NoError(t, cfg.SetConfigValue("showaction", "Clip"))
+	assert.Equal(t, config.ShowActionClip, cfg.ShowAction)
+	assert.Error(t, cfg.SetConfigValue("showaction", "copy"))
+	assert.Equal(t, config.ShowActionClip, cfg.ShowAction)
+}
+
+func TestStoreConfig(t *testing.T) {
+	assert.NoError(t, os.Setenv("GOPASS_CONFIG", filepath.Join(t.TempDir(), ".gopass.yml")))
+
+	cfg := config.New()
+	cfg.Mounts["work"] = "/tmp/work"
+
+	assert.False(t, cfg.StoreConfig("work").HiddenRecipients)
+	assert.NoError(t, cfg.SetStoreConfigValue("work", "hiddenrecipients", "true"))
+	assert.True(t, cfg.StoreConfig("work").HiddenRecipients)
+	assert.False(t, cfg.StoreConfig("").HiddenRecipients)
+	assert.Equal(t, map[string]string{
+		"caseinsensitive":  "false",
+		"cipherprefs":      "",
+		"compression":      "",
+		"digestprefs":      "",
+		"gnupghome":        "",
+		"hiddenrecipients": "true",
+		"mirrors":          "",
+		"trustmodel":       "",
+	}, cfg.StoreConfig("work").ConfigMap())
+
+	assert.Error(t, cfg.SetStoreConfigValue("work", "hiddenrecipients", "yo"))
+	assert.Error(t, cfg.SetStoreConfigValue("work", "foobar", "true"))
+	assert.Error(t, cfg.SetStoreConfigValue("personal", "hiddenrecipients", "true"))
+
+	assert.NoError(t, cfg.SetStoreConfigValue("", "compression", "ZLIB"))
+	assert.Equal(t, "zlib", cfg.StoreConfig("").Compression)
+	assert.Error(t, cfg.SetStoreConfigValue("", "compression", "lzma"))
+	assert.Equal(t, "zlib", cfg.StoreConfig("").Compression)
+
+	cfg = config.Load()
+	assert.True(t, cfg.StoreConfig("work").HiddenRecipients)
+
+	assert.NoError(t, cfg.SetStoreConfigValue("work", "gnupghome", "/tmp/gnupg"))
+	ctx := cfg.StoreConfig("work").WithContext(context.Background())
+	assert.Equal(t, "/tmp/gnupg", gpg.GetHomedir(ctx))
+	assert.Equal(t, "", gpg.GetHomedir(cfg.StoreConfig("").WithContext(context.Background())))
+
+	assert.Error(t, cfg.SetStoreConfigValue("work", "trustmodel", "classic"))
+	assert.NoError(t, cfg.SetStoreConfigValue("work", "trustmodel", "tofu+pgp"))
+	// the store trust model overrides the global default
+	ctx = gpg.WithTrustModel(context.Background(), gpg.TrustAlways)
+	assert.Equal(t, gpg.TrustTOFUPGP, gpg.GetTrustModel(cfg.StoreConfig("work").WithContext(ctx)))
+	assert.Equal(t, gpg.TrustAlways, gpg.GetTrustModel(cfg.StoreConfig("").WithContext(ctx)))
 }
@@ -2,7 +2,9 @@ package config
 
 import (
 	"context"
+	"time"
 
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 )
 
@@ -27,5 +29,14 @@ func (c *Config) WithContext(ctx context.Context) context.Context {
 	if !ctxutil.HasShowParsing(ctx) {
 		ctx = ctxutil.WithShowParsing(ctx, c.Parsing)
 	}
+	if c.GPGTimeout != 0 {
+		ctx = gpg.WithCommandTimeout(ctx, time.Duration(c.GPGTimeout)*time.Second)
+	}
+	if c.PinentryTimeout != 0 {
+		ctx = gpg.WithPinentryTimeout(ctx, time.Duration(c.PinentryTimeout)*time.Second)
+	}
+	if c.KeyCache {
+		ctx = gpg.WithPersistentCache(ctx, true)
+	}
 	return ctx
 }
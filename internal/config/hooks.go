@@ -0,0 +1,25 @@
+package config
+
+// Hook events. Pre hooks can abort the operation by exiting with a non-zero
+// status, the result of post hooks is only reported.
+const (
+	HookPreInsert    = "pre-insert"
+	HookPostGenerate = "post-generate"
+	HookPostSync     = "post-sync"
+	HookPreDelete    = "pre-delete"
+)
+
+// HookConfig configures a command that is run on a store event.
+type HookConfig struct {
+	Command   string `yaml:"command"`             // command line, split like a shell would.
+	Plaintext bool   `yaml:"plaintext,omitempty"` // pass the secret content on stdin.
+}
+
+// Hook returns the hook configured for event, if any.
+func (c *Config) Hook(event string) (HookConfig, bool) {
+	h, found := c.Hooks[event]
+	if !found || h.Command == "" {
+		return HookConfig{}, false
+	}
+	return h, true
+}
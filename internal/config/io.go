@@ -1,10 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/fsutil"
@@ -72,7 +74,7 @@ func load(cf string, relaxed bool) (*Config, error) {
 		return nil, ErrConfigNotFound
 	}
 
-	cfg, err := decode(buf, relaxed)
+	cfg, legacy, err := decodeConfig(buf, relaxed)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading config from %s: %s\n", cf, err)
 		return nil, ErrConfigNotParsed
@@ -81,6 +83,30 @@ func load(cf string, relaxed bool) (*Config, error) {
 		cfg.Mounts = make(map[string]string)
 	}
 	cfg.ConfigPath = cf
+
+	if err := cfg.validate(); err != nil {
+		var oe *OptionError
+		if errors.As(err, &oe) {
+			oe.Line = lineOf(buf, oe.Key)
+		}
+		fmt.Fprintf(os.Stderr, "Invalid option in config %s: %s\n", cf, err)
+	}
+
+	// relaxed loading might have dropped unknown options, never write
+	// them back.
+	if relaxed {
+		return cfg, nil
+	}
+	if cfg.Version > ConfigVersion {
+		debug.Log("Config %s was written by a newer release (version %d)", cf, cfg.Version)
+		return cfg, nil
+	}
+	if legacy || cfg.Version < ConfigVersion {
+		if err := cfg.migrateFile(cf, buf, legacy); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to migrate config %s: %s\n", cf, err)
+		}
+	}
+
 	return cfg, nil
 }
 
@@ -100,6 +126,13 @@ type configer interface {
 }
 
 func decode(buf []byte, relaxed bool) (*Config, error) {
+	cfg, _, err := decodeConfig(buf, relaxed)
+	return cfg, err
+}
+
+// decodeConfig tries the current and all legacy config formats. It also
+// returns whether a legacy format was used.
+func decodeConfig(buf []byte, relaxed bool) (*Config, bool, error) {
 	mostRecent := &Config{
 		AutoImport:    true,
 		ClipTimeout:   45,
@@ -107,6 +140,7 @@ func decode(buf []byte, relaxed bool) (*Config, error) {
 		Notifications: true,
 		Parsing:       true,
 		Path:          PwStoreDir(""),
+		ShowAction:    ShowActionPrint,
 	}
 	cfgs := []configer{
 		// most recent config must come first.
@@ -127,7 +161,6 @@ func decode(buf []byte, relaxed bool) (*Config, error) {
 		// overflow checks.
 		cfgs = append(cfgs, mostRecent)
 	}
-	var warn string
 	for i, cfg := range cfgs {
 		debug.Log("Trying to unmarshal config into %T", cfg)
 		if err := yaml.Unmarshal(buf, cfg); err != nil {
@@ -136,9 +169,6 @@ func decode(buf []byte, relaxed bool) (*Config, error) {
 		}
 		if err := cfg.CheckOverflow(); err != nil {
 			debug.Log("Extra elements in config: %s", err)
-			if i == 0 {
-				warn = fmt.Sprintf("Failed to load config %T. Do you need to remove deprecated fields? %s\n", cfg, err)
-			}
 			// usually we are strict about extra fields, i.e. any field left
 			// unparsed means this config failed and we try the next one.
 			if i < len(cfgs)-1 {
@@ -154,10 +184,11 @@ func decode(buf []byte, relaxed bool) (*Config, error) {
 		}
 		debug.Log("Loaded config: %T: %+v", cfg, cfg)
 		conf := cfg.Config()
-		if i > 0 {
+		legacy := cfg != configer(mostRecent)
+		if legacy {
 			debug.Log("Loaded legacy config. Should rewrite config.")
 		}
-		return conf, nil
+		return conf, legacy, nil
 	}
 	// We try to provide a seamless config upgrade path for users of our
 	// released versions. But some users build gopass from the master branch
@@ -166,14 +197,17 @@ func decode(buf []byte, relaxed bool) (*Config, error) {
 	// unknown config options. If we remove one and the user rebuilds it's
 	// gopass binary without changing the config, it will fail to parse the
 	// current config and the legacy configs will likely fail as well.
-	// But if we always display the warning users with configs from previous
-	// releases will always see the warning. So instead we only display the
-	// warning if parsing of the most up to date config struct fails AND
-	// not other succeeds.
-	if warn != "" {
-		fmt.Fprint(os.Stderr, warn)
-	}
-	return nil, ErrConfigNotParsed
+	// So only if no format matches we report what's wrong with the config
+	// according to the current format.
+	errs := check(buf)
+	if len(errs) < 1 {
+		return nil, false, ErrConfigNotParsed
+	}
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, "  "+err.Error())
+	}
+	return nil, false, fmt.Errorf("%w:\n%s", ErrConfigNotParsed, strings.Join(msgs, "\n"))
 }
 
 // Save saves the config.
@@ -44,6 +44,7 @@ mounts:
 				Parsing:       true,
 				Path:          "/home/johndoe/.password-store",
 				SafeContent:   false,
+				ShowAction:    "print",
 				Mounts: map[string]string{
 					"foo/sub": "/home/johndoe/.password-store-foo-sub",
 					"work":    "/home/johndoe/.password-store-work",
@@ -73,6 +74,7 @@ mounts:
 				Parsing:       true,
 				Path:          "/home/johndoe/.password-store",
 				SafeContent:   false,
+				ShowAction:    "print",
 				Mounts: map[string]string{
 					"foo/sub": "/home/johndoe/.password-store-foo-sub",
 					"work":    "/home/johndoe/.password-store-work",
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/mattn/go-isatty"
+)
+
+// ConfigVersion is the version of the config format written by this release.
+// Configs with a lower version are migrated when they are loaded.
+const ConfigVersion = 1
+
+const versionKey = "version"
+
+var isInteractive = func() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+var askForConfirmation = func(msg string) bool {
+	return termio.AskForConfirmation(context.Background(), msg)
+}
+
+// envMigrations lists environment variables that used to be the only way to
+// change an option. They still apply when they are set, their values are only
+// persisted in the config if the user agrees.
+var envMigrations = []struct {
+	env   string
+	key   string
+	value string
+}{
+	{env: "GOPASS_NO_NOTIFY", key: "notifications", value: "false"},
+}
+
+// migrate upgrades a config loaded from an older format, or without a version,
+// to the current format. It returns a description of every change.
+func (c *Config) migrate(legacy bool) []string {
+	var changes []string
+	if legacy {
+		changes = append(changes, "converted from the format of an older release")
+	}
+
+	// scripts and helpers, e.g. unclip, set some of these variables for a
+	// single invocation. Only a user at a terminal can tell if it's a setting.
+	if isInteractive() {
+		for _, m := range envMigrations {
+			if os.Getenv(m.env) == "" {
+				continue
+			}
+			if !askForConfirmation(fmt.Sprintf("%s is set. Do you want to set %s to %s in your config instead?", m.env, m.key, m.value)) {
+				continue
+			}
+			if err := c.setConfigValue(m.key, m.value); err != nil {
+				debug.Log("failed to migrate %s: %s", m.env, err)
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("set %s to %s because %s is set. The variable can be removed", m.key, m.value, m.env))
+		}
+	}
+
+	c.Version = ConfigVersion
+	return changes
+}
+
+// migrateFile migrates the config loaded from cf and saves it. The old
+// config is kept next to it.
+func (c *Config) migrateFile(cf string, buf []byte, legacy bool) error {
+	from := c.Version
+	changes := c.migrate(legacy)
+
+	backup := fmt.Sprintf("%s.v%d.bak", cf, from)
+	if err := os.WriteFile(backup, buf, 0o600); err != nil {
+		return fmt.Errorf("failed to back up the config to %s: %w", backup, err)
+	}
+	if err := c.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Migrated config to version %d. The old config was saved as %s\n", ConfigVersion, backup)
+	for _, change := range changes {
+		fmt.Fprintf(os.Stderr, "  * %s\n", change)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	td := t.TempDir()
+	gcfg := filepath.Join(td, "config.yml")
+	t.Setenv("GOPASS_CONFIG", gcfg)
+	t.Setenv("GOPASS_HOMEDIR", td)
+	t.Setenv("GOPASS_NO_NOTIFY", "")
+
+	oldInteractive := isInteractive
+	oldConfirm := askForConfirmation
+	defer func() {
+		isInteractive = oldInteractive
+		askForConfirmation = oldConfirm
+	}()
+	isInteractive = func() bool { return true }
+	askForConfirmation = func(string) bool { return true }
+
+	t.Run("unversioned", func(t *testing.T) {
+		old := "autoclip: true\nnotifications: true\npath: /tmp/store\n"
+		require.NoError(t, os.WriteFile(gcfg, []byte(old), 0o600))
+
+		cfg, err := load(gcfg, false)
+		require.NoError(t, err)
+		assert.Equal(t, ConfigVersion, cfg.Version)
+		assert.True(t, cfg.AutoClip)
+
+		buf, err := os.ReadFile(gcfg)
+		require.NoError(t, err)
+		assert.Contains(t, string(buf), "version: 1\n")
+		buf, err = os.ReadFile(gcfg + ".v0.bak")
+		require.NoError(t, err)
+		assert.Equal(t, old, string(buf))
+
+		// the migrated config is loaded as is.
+		require.NoError(t, os.Remove(gcfg+".v0.bak"))
+		_, err = load(gcfg, false)
+		require.NoError(t, err)
+		assert.NoFileExists(t, gcfg+".v0.bak")
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(gcfg, []byte(testConfig), 0o600))
+
+		cfg, err := load(gcfg, false)
+		require.NoError(t, err)
+		assert.Equal(t, ConfigVersion, cfg.Version)
+		assert.True(t, cfg.SafeContent)
+
+		cfg, err = load(gcfg, false)
+		require.NoError(t, err)
+		assert.True(t, cfg.SafeContent)
+		assert.Equal(t, "/home/johndoe/.password-store-work", cfg.Mounts["work"])
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("GOPASS_NO_NOTIFY", "true")
+		require.NoError(t, os.WriteFile(gcfg, []byte("notifications: true\n"), 0o600))
+
+		cfg, err := load(gcfg, false)
+		require.NoError(t, err)
+		assert.False(t, cfg.Notifications)
+	})
+
+	t.Run("environment declined", func(t *testing.T) {
+		askForConfirmation = func(string) bool { return false }
+		defer func() {
+			askForConfirmation = func(string) bool { return true }
+		}()
+		t.Setenv("GOPASS_NO_NOTIFY", "true")
+		require.NoError(t, os.WriteFile(gcfg, []byte("notifications: true\n"), 0o600))
+
+		cfg, err := load(gcfg, false)
+		require.NoError(t, err)
+		assert.True(t, cfg.Notifications)
+		assert.Equal(t, ConfigVersion, cfg.Version)
+	})
+
+	t.Run("non-interactive", func(t *testing.T) {
+		isInteractive = func() bool { return false }
+		defer func() {
+			isInteractive = func() bool { return true }
+		}()
+		t.Setenv("GOPASS_NO_NOTIFY", "true")
+		require.NoError(t, os.WriteFile(gcfg, []byte("notifications: true\n"), 0o600))
+
+		cfg, err := load(gcfg, false)
+		require.NoError(t, err)
+		assert.True(t, cfg.Notifications)
+		assert.Equal(t, ConfigVersion, cfg.Version)
+	})
+
+	t.Run("relaxed", func(t *testing.T) {
+		old := "notifications: true\nfoo: bar\n"
+		require.NoError(t, os.WriteFile(gcfg, []byte(old), 0o600))
+
+		cfg, err := load(gcfg, true)
+		require.NoError(t, err)
+		assert.Equal(t, 0, cfg.Version)
+
+		buf, err := os.ReadFile(gcfg)
+		require.NoError(t, err)
+		assert.Equal(t, old, string(buf))
+	})
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OptionError points at an invalid option in the config file.
+type OptionError struct {
+	Key  string // e.g. "cliptimeout" or "stores.work.compression".
+	Line int    // line in the config file, 0 if unknown.
+	Err  error
+}
+
+func (e *OptionError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s (line %d): %s", e.Key, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Key, e.Err)
+}
+
+// Unwrap implements errors.Unwrap.
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+var typeErrLine = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// check validates buf against the current config format. It returns one
+// error per invalid option, pointing at the offending key.
+func check(buf []byte) []error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return []error{err}
+	}
+	keys, lines := keyLines(&doc)
+
+	var errs []error
+	cfg := &Config{}
+	if err := yaml.Unmarshal(buf, cfg); err != nil {
+		var te *yaml.TypeError
+		if !errors.As(err, &te) {
+			return []error{err}
+		}
+		for _, msg := range te.Errors {
+			errs = append(errs, typeError(msg, lines))
+		}
+	}
+
+	unknown := make([]string, 0, len(cfg.XXX))
+	for k := range cfg.XXX {
+		unknown = append(unknown, k)
+	}
+	sort.Strings(unknown)
+	for _, k := range unknown {
+		msg := "unknown option"
+		if s := suggestOption(k); s != "" {
+			msg += ", did you mean " + s + "?"
+		}
+		errs = append(errs, &OptionError{Key: k, Line: keys[k], Err: fmt.Errorf("%s", msg)})
+	}
+
+	if err := cfg.validate(); err != nil {
+		var oe *OptionError
+		if errors.As(err, &oe) {
+			oe.Line = keys[oe.Key]
+		}
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// keyLines returns the line of every key in the config, nested keys are
+// joined with a dot, and the key that each line belongs to.
+func keyLines(doc *yaml.Node) (map[string]int, map[int]string) {
+	keys := make(map[string]int, 32)
+	lines := make(map[int]string, 32)
+
+	var walk func(prefix string, n *yaml.Node)
+	walk = func(prefix string, n *yaml.Node) {
+		if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+			walk(prefix, n.Content[0])
+			return
+		}
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			key := prefix + k.Value
+			keys[key] = k.Line
+			lines[k.Line] = key
+			if v.Line != k.Line {
+				lines[v.Line] = key
+			}
+			walk(key+".", v)
+		}
+	}
+	walk("", doc)
+
+	return keys, lines
+}
+
+// lineOf returns the line of key in the config buf or 0 if it's not found.
+func lineOf(buf []byte, key string) int {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return 0
+	}
+	keys, _ := keyLines(&doc)
+	return keys[key]
+}
+
+// typeError converts a message of a yaml.TypeError, e.g. "line 3: cannot
+// unmarshal !!str `abc` into int", into an OptionError.
+func typeError(msg string, lines map[int]string) error {
+	m := typeErrLine.FindStringSubmatch(msg)
+	if m == nil {
+		return fmt.Errorf("%s", msg)
+	}
+	line, _ := strconv.Atoi(m[1])
+	key, found := lines[line]
+	if !found {
+		return fmt.Errorf("%s", msg)
+	}
+	return &OptionError{Key: key, Line: line, Err: fmt.Errorf("%s", m[2])}
+}
+
+// suggestOption returns the known option closest to key, if any is close
+// enough to be a typo.
+func suggestOption(key string) string {
+	key = strings.ToLower(key)
+	best, bestDist := "", 3
+	for _, opt := range optionNames() {
+		if d := editDistance(key, opt); d < bestDist {
+			best, bestDist = opt, d
+		}
+	}
+	return best
+}
+
+// optionNames returns the yaml keys of all top level options.
+func optionNames() []string {
+	m := New().ConfigMap()
+	names := make([]string, 0, len(m)+4)
+	for k := range m {
+		names = append(names, k)
+	}
+	names = append(names, "hooks", "mounts", "stores", versionKey)
+	sort.Strings(names)
+	return names
+}
+
+// editDistance returns the Levenshtein distance of a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < cur[j] {
+				cur[j] = d
+			}
+			if d := cur[j-1] + 1; d < cur[j] {
+				cur[j] = d
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  string
+		want []string
+	}{
+		{
+			name: "valid",
+			cfg:  "autoclip: true\ncliptimeout: 45\n",
+		},
+		{
+			name: "typo",
+			cfg:  "autoclip: true\nclipttimeout: 45\n",
+			want: []string{"clipttimeout (line 2): unknown option, did you mean cliptimeout?"},
+		},
+		{
+			name: "unknown",
+			cfg:  "foobar: zab\n",
+			want: []string{"foobar (line 1): unknown option"},
+		},
+		{
+			name: "wrong type",
+			cfg:  "autoclip: true\nmounts: {}\ncliptimeout: soon\n",
+			want: []string{"cliptimeout (line 3): cannot unmarshal !!str `soon` into int"},
+		},
+		{
+			name: "invalid value",
+			cfg:  "autoclip: true\nshowaction: copy\n",
+			want: []string{`showaction (line 2): unknown show action "copy". Must be one of print, clip or both`},
+		},
+		{
+			name: "invalid store option",
+			cfg:  "mounts:\n  work: /tmp/work\nstores:\n  work:\n    compression: lzma\n",
+			want: []string{`stores.work.compression (line 5): unknown compression algorithm "lzma". Must be one of none, zip, zlib or bzip2`},
+		},
+		{
+			name: "unknown hook",
+			cfg:  "hooks:\n  post-insert:\n    command: true\n",
+			want: []string{"hooks.post-insert (line 2): unknown event. Must be one of pre-insert, post-generate, post-sync or pre-delete"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, err := range check([]byte(tc.cfg)) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDecodeReportsOptions(t *testing.T) {
+	_, err := decode([]byte("autoclip: true\nclipttimeout: 45\n"), false)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrConfigNotParsed)
+	assert.Contains(t, err.Error(), "clipttimeout (line 2): unknown option, did you mean cliptimeout?")
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("foo", "foo"))
+	assert.Equal(t, 1, editDistance("cliptimeout", "clipttimeout"))
+	assert.Equal(t, 1, editDistance("nopagr", "nopager"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
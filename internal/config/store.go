@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+// StoreConfig contains options that only apply to a single store. They are
+// local overrides of the current user, other users of a shared store have to
+// set them on their own.
+type StoreConfig struct {
+	CaseInsensitive  bool   `yaml:"caseinsensitive,omitempty"`  // look up secrets ignoring case and unicode normalization.
+	CipherPrefs      string `yaml:"cipherprefs,omitempty"`      // preferred symmetric ciphers, e.g. "AES256 AES192".
+	Compression      string `yaml:"compression,omitempty"`      // compression algorithm, defaults to none.
+	DigestPrefs      string `yaml:"digestprefs,omitempty"`      // preferred digest algorithms, e.g. "SHA512 SHA384".
+	GnupgHome        string `yaml:"gnupghome,omitempty"`        // GNUPGHOME of the keyring used for this store, defaults to the one of gopass.
+	HiddenRecipients bool   `yaml:"hiddenrecipients,omitempty"` // do not reveal the recipients in encrypted secrets.
+	Mirrors          string `yaml:"mirrors,omitempty"`          // git remotes that sync also pushes to, e.g. "gitlab offsite".
+	TrustModel       string `yaml:"trustmodel,omitempty"`       // gpg trust model used to validate public keys, defaults to always.
+}
+
+// StoreConfig returns the options for the store mounted at alias. The root
+// store uses the empty alias.
+func (c *Config) StoreConfig(alias string) StoreConfig {
+	return c.Stores[alias]
+}
+
+// SetStoreConfigValue will try to set the given key to the value in the config
+// of the store mounted at alias.
+func (c *Config) SetStoreConfigValue(alias, key, value string) error {
+	if err := c.setStoreConfigValue(alias, key, value); err != nil {
+		return err
+	}
+	return c.Save()
+}
+
+func (c *Config) setStoreConfigValue(alias, key, value string) error {
+	if _, found := c.Mounts[alias]; alias != "" && !found {
+		return fmt.Errorf("store %q is not mounted", alias)
+	}
+
+	sc := c.Stores[alias]
+	if err := setField(reflect.ValueOf(&sc).Elem(), key, value); err != nil {
+		return err
+	}
+	if err := sc.validate(); err != nil {
+		return err
+	}
+
+	if c.Stores == nil {
+		c.Stores = make(map[string]StoreConfig, 1)
+	}
+	c.Stores[alias] = sc
+	return nil
+}
+
+func (s StoreConfig) validate() error {
+	switch s.Compression {
+	case "", "none", "zip", "zlib", "bzip2":
+	default:
+		return &OptionError{
+			Key: "compression",
+			Err: fmt.Errorf("unknown compression algorithm %q. Must be one of none, zip, zlib or bzip2", s.Compression),
+		}
+	}
+	if s.TrustModel != "" {
+		if err := gpg.ValidateTrustModel(s.TrustModel); err != nil {
+			return &OptionError{Key: "trustmodel", Err: err}
+		}
+	}
+	return nil
+}
+
+// ConfigMap returns a map of stringified config values for easy printing.
+func (s StoreConfig) ConfigMap() map[string]string {
+	return fieldMap(reflect.ValueOf(&s).Elem())
+}
+
+// WithContext returns a context with all options of this store set, iff they
+// have not been already set in the context. The trust model of the store
+// always overrides the global default.
+func (s StoreConfig) WithContext(ctx context.Context) context.Context {
+	if s.TrustModel != "" {
+		ctx = gpg.WithTrustModel(ctx, s.TrustModel)
+	}
+	if !gpg.HasThrowKeyIDs(ctx) {
+		ctx = gpg.WithThrowKeyIDs(ctx, s.HiddenRecipients)
+	}
+	if s.Compression != "" && gpg.GetCompression(ctx) == "" {
+		ctx = gpg.WithCompression(ctx, s.Compression)
+	}
+	if s.CipherPrefs != "" && gpg.GetCipherPrefs(ctx) == "" {
+		ctx = gpg.WithCipherPrefs(ctx, s.CipherPrefs)
+	}
+	if s.DigestPrefs != "" && gpg.GetDigestPrefs(ctx) == "" {
+		ctx = gpg.WithDigestPrefs(ctx, s.DigestPrefs)
+	}
+	if s.GnupgHome != "" && gpg.GetHomedir(ctx) == "" {
+		ctx = gpg.WithHomedir(ctx, fsutil.CleanPath(s.GnupgHome))
+	}
+	return ctx
+}
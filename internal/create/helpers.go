@@ -1,16 +1,22 @@
 package create
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/fsutil"
+	"github.com/gopasspw/gopass/pkg/termio"
 )
 
+// maxTries is the number of times we ask for a value before giving up.
+const maxTries = 3
+
 func fmtfn(d int, n string, t string) string {
 	strlen := 40 - d
 	// indent - [N] - text (trailing spaces)
@@ -39,3 +45,62 @@ func extractHostname(in string) string {
 	}
 	return fsutil.CleanFilename(in)
 }
+
+// checkLength returns an error if the given length is outside of the bounds
+// of the attribute. A zero Min or Max means there is no bound.
+func (a Attribute) checkLength(l int) error {
+	if a.Min > 0 && l < a.Min {
+		return fmt.Errorf("%s must have at least %d characters", a.Name, a.Min)
+	}
+	if a.Max > 0 && l > a.Max {
+		return fmt.Errorf("%s must have at most %d characters", a.Name, a.Max)
+	}
+	return nil
+}
+
+// clampLength limits the given default length to the bounds of the attribute.
+func (a Attribute) clampLength(l int) int {
+	if a.Min > 0 && l < a.Min {
+		return a.Min
+	}
+	if a.Max > 0 && l > a.Max {
+		return a.Max
+	}
+	return l
+}
+
+// askForString asks for a string and retries if it doesn't meet the length
+// constraints of the attribute.
+func askForString(ctx context.Context, prompt string, attr Attribute) (string, error) {
+	var err error
+	for i := 0; i < maxTries; i++ {
+		var sv string
+		sv, err = termio.AskForString(ctx, prompt, "")
+		if err != nil {
+			return "", err
+		}
+		if err = attr.checkLength(len([]rune(sv))); err == nil {
+			return sv, nil
+		}
+		out.Errorf(ctx, "%s", err)
+	}
+	return "", err
+}
+
+// askForLength asks for a password length and retries if it doesn't meet the
+// length constraints of the attribute.
+func askForLength(ctx context.Context, prompt string, def int, attr Attribute) (int, error) {
+	var err error
+	for i := 0; i < maxTries; i++ {
+		var length int
+		length, err = termio.AskForInt(ctx, prompt, def)
+		if err != nil {
+			return 0, err
+		}
+		if err = attr.checkLength(length); err == nil {
+			return length, nil
+		}
+		out.Errorf(ctx, "%s", err)
+	}
+	return 0, err
+}
@@ -125,6 +125,117 @@ func New(ctx context.Context, s backend.Storage) (*Wizard, error) {
 					},
 				},
 			},
+			{
+				Name:     "WiFi",
+				Priority: 2,
+				Prefix:   "wifi",
+				NameFrom: []string{"ssid"},
+				Welcome:  "📶 Creating WiFi credentials",
+				Attributes: []Attribute{
+					{
+						Name:   "ssid",
+						Type:   "string",
+						Prompt: "Network name (SSID)",
+						Min:    1,
+						Max:    32,
+					},
+					{
+						Name:   "password",
+						Type:   "password",
+						Prompt: "WiFi password",
+						Min:    8,
+						Max:    63,
+					},
+					{
+						Name:   "security",
+						Type:   "string",
+						Prompt: "Security (e.g. WPA2, WPA3)",
+					},
+				},
+			},
+			{
+				Name:     "API token",
+				Priority: 3,
+				Prefix:   "tokens",
+				NameFrom: []string{"service", "name"},
+				Welcome:  "🔌 Creating API token",
+				Attributes: []Attribute{
+					{
+						Name:   "service",
+						Type:   "hostname",
+						Prompt: "Service URL",
+						Min:    1,
+						Max:    255,
+					},
+					{
+						Name:   "name",
+						Type:   "string",
+						Prompt: "Token name or purpose",
+						Min:    1,
+					},
+					{
+						Name:   "password",
+						Type:   "secret",
+						Prompt: "Token",
+						Min:    1,
+					},
+					{
+						Name:   "expires",
+						Type:   "string",
+						Prompt: "Expires (e.g. 2022-12-31, leave empty if it doesn't)",
+					},
+				},
+			},
+			{
+				Name:     "Credit card",
+				Priority: 4,
+				Prefix:   "cards",
+				NameFrom: []string{"issuer", "name"},
+				Welcome:  "💳 Creating credit card",
+				Attributes: []Attribute{
+					{
+						Name:   "issuer",
+						Type:   "string",
+						Prompt: "Issuer (e.g. bank)",
+						Min:    1,
+					},
+					{
+						Name:   "name",
+						Type:   "string",
+						Prompt: "Card name (e.g. personal visa)",
+						Min:    1,
+					},
+					{
+						Name:   "number",
+						Type:   "string",
+						Prompt: "Card number",
+						Min:    12,
+						Max:    23,
+					},
+					{
+						Name:   "expiry",
+						Type:   "string",
+						Prompt: "Expiry date (MM/YY)",
+						Min:    5,
+						Max:    7,
+					},
+					{
+						Name:   "cvv",
+						Type:   "string",
+						Prompt: "Security code (CVV)",
+						Min:    3,
+						Max:    4,
+					},
+					{
+						Name:    "password",
+						Type:    "password",
+						Prompt:  "PIN",
+						Min:     4,
+						Max:     12,
+						Charset: "0123456789",
+					},
+				},
+			},
 		},
 	}
 	tpls, err := s.List(ctx, ".gopass/create/")
@@ -208,7 +319,7 @@ func mkActFunc(tpl Template, s *root.Store, cb ActionCallback) func(context.Cont
 
 			switch v.Type {
 			case "string":
-				sv, err := termio.AskForString(ctx, fmtfn(2, strconv.Itoa(step), v.Prompt), "")
+				sv, err := askForString(ctx, fmtfn(2, strconv.Itoa(step), v.Prompt), v)
 				if err != nil {
 					return err
 				}
@@ -217,7 +328,7 @@ func mkActFunc(tpl Template, s *root.Store, cb ActionCallback) func(context.Cont
 				}
 				sec.Set(k, sv)
 			case "hostname":
-				sv, err := termio.AskForString(ctx, fmtfn(2, strconv.Itoa(step), v.Prompt), "")
+				sv, err := askForString(ctx, fmtfn(2, strconv.Itoa(step), v.Prompt), v)
 				if err != nil {
 					return err
 				}
@@ -240,7 +351,7 @@ func mkActFunc(tpl Template, s *root.Store, cb ActionCallback) func(context.Cont
 				}
 
 				if genPw {
-					password, err = generatePassword(ctx, hostname, v.Charset)
+					password, err = generatePassword(ctx, hostname, v)
 					if err != nil {
 						return err
 					}
@@ -249,6 +360,22 @@ func mkActFunc(tpl Template, s *root.Store, cb ActionCallback) func(context.Cont
 					if err != nil {
 						return err
 					}
+					if err := v.checkLength(len(password)); err != nil {
+						return err
+					}
+				}
+
+				sec.SetPassword(password)
+			case "secret":
+				// secrets are issued by someone else, e.g. API tokens, so
+				// there is nothing to generate.
+				var err error
+				password, err = termio.AskForPassword(ctx, v.Prompt, false)
+				if err != nil {
+					return err
+				}
+				if err := v.checkLength(len(password)); err != nil {
+					return err
 				}
 
 				sec.SetPassword(password)
@@ -300,18 +427,19 @@ func mkActFunc(tpl Template, s *root.Store, cb ActionCallback) func(context.Cont
 
 }
 
-// generatePasssword will walk through the password generation steps.
-func generatePassword(ctx context.Context, hostname, charset string) (string, error) {
-	if charset != "" {
-		length, err := termio.AskForInt(ctx, fmtfn(4, "a", "How long?"), 4)
+// generatePasssword will walk through the password generation steps. The
+// length of the password is limited to the bounds of the attribute.
+func generatePassword(ctx context.Context, hostname string, attr Attribute) (string, error) {
+	if attr.Charset != "" {
+		length, err := askForLength(ctx, fmtfn(4, "a", "How long?"), attr.clampLength(4), attr)
 		if err != nil {
 			return "", err
 		}
-		return pwgen.GeneratePasswordCharset(length, charset), nil
+		return pwgen.GeneratePasswordCharset(length, attr.Charset), nil
 	}
 	if _, found := pwrules.LookupRule(hostname); found {
 		out.Noticef(ctx, "Using password rules for %s ...", hostname)
-		length, err := termio.AskForInt(ctx, fmtfn(4, "b", "How long?"), defaultLength)
+		length, err := askForLength(ctx, fmtfn(4, "b", "How long?"), attr.clampLength(defaultLength), attr)
 		if err != nil {
 			return "", err
 		}
@@ -333,7 +461,7 @@ func generatePassword(ctx context.Context, hostname, charset string) (string, er
 		return string(g.GeneratePassword()), nil
 	}
 
-	length, err := termio.AskForInt(ctx, fmtfn(4, "b", "How long?"), defaultLength)
+	length, err := askForLength(ctx, fmtfn(4, "b", "How long?"), attr.clampLength(defaultLength), attr)
 	if err != nil {
 		return "", err
 	}
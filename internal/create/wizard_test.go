@@ -1,10 +1,14 @@
 package create
 
 import (
+	"bytes"
 	"context"
+	"os"
 	"testing"
 
+	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store/mockstore/inmem"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,7 +48,7 @@ attributes:
 	if w.Templates == nil {
 		t.Fatal("no templates")
 	}
-	if len(w.Templates) != 3 {
+	if len(w.Templates) != 6 {
 		t.Fatal("wrong number of templates")
 	}
 	if w.Templates[0].Prefix != "websites" {
@@ -93,3 +97,47 @@ func TestExtractHostname(t *testing.T) {
 		assert.Equal(t, out, extractHostname(in))
 	}
 }
+
+func TestAttributeLength(t *testing.T) {
+	a := Attribute{Name: "password", Min: 8, Max: 63}
+	assert.Error(t, a.checkLength(7))
+	assert.NoError(t, a.checkLength(8))
+	assert.NoError(t, a.checkLength(63))
+	assert.Error(t, a.checkLength(64))
+	assert.Equal(t, 8, a.clampLength(4))
+	assert.Equal(t, 24, a.clampLength(24))
+	assert.Equal(t, 63, a.clampLength(100))
+
+	a = Attribute{Name: "comment"}
+	assert.NoError(t, a.checkLength(0))
+	assert.Equal(t, 4, a.clampLength(4))
+}
+
+func TestAskForString(t *testing.T) {
+	ctx := ctxutil.WithAlwaysYes(context.Background(), true)
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	// non-interactive input is always empty
+	_, err := askForString(ctx, "SSID", Attribute{Name: "ssid", Min: 1})
+	assert.Error(t, err)
+
+	sv, err := askForString(ctx, "Comment", Attribute{Name: "comment"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", sv)
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	ctx := context.Background()
+	w, err := New(ctx, inmem.New())
+	assert.NoError(t, err)
+
+	prefixes := make([]string, 0, len(w.Templates))
+	for _, tpl := range w.Templates {
+		prefixes = append(prefixes, tpl.Prefix)
+	}
+	assert.Equal(t, []string{"websites", "pins", "wifi", "tokens", "cards"}, prefixes)
+}
@@ -13,7 +13,24 @@ import (
 // GetSelection show a navigateable multiple-choice list to the user
 // and returns the selected entry along with the action.
 func GetSelection(ctx context.Context, prompt string, choices []string) (string, int) {
-	if ctxutil.IsAlwaysYes(ctx) || !ctxutil.IsInteractive(ctx) {
+	if ctxutil.IsAlwaysYes(ctx) {
+		return "impossible", 0
+	}
+
+	// an explicitly configured prompter (e.g. zenity) takes precedence
+	// over the terminal based selection.
+	if termio.HasPrompter(ctx) {
+		i, err := termio.GetPrompter(ctx).Select(ctx, prompt, choices)
+		if errors.Is(err, termio.ErrAborted) {
+			return "aborted", 0
+		}
+		if err != nil {
+			return "impossible", 0
+		}
+		return "default", i
+	}
+
+	if !ctxutil.IsInteractive(ctx) {
 		return "impossible", 0
 	}
 
@@ -36,3 +36,67 @@ func listToMap[K comparable](l []K) map[K]struct{} {
 	}
 	return m
 }
+
+// Op is the type of a line in a line diff.
+type Op int
+
+// The possible line types.
+const (
+	Equal Op = iota
+	Added
+	Removed
+)
+
+// Line is a single line of a line diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns a minimal line diff from the first to the second list. It
+// uses the longest common subsequence, so it's only suitable for short
+// inputs like the body of a secret.
+func Lines(l, r []string) []Line {
+	// lcs[i][j] is the length of the LCS of l[i:] and r[j:].
+	lcs := make([][]int, len(l)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(r)+1)
+	}
+	for i := len(l) - 1; i >= 0; i-- {
+		for j := len(r) - 1; j >= 0; j-- {
+			if l[i] == r[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+				continue
+			}
+			lcs[i][j] = lcs[i+1][j]
+			if lcs[i][j+1] > lcs[i][j] {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]Line, 0, len(l)+len(r))
+	i, j := 0, 0
+	for i < len(l) && j < len(r) {
+		switch {
+		case l[i] == r[j]:
+			lines = append(lines, Line{Op: Equal, Text: l[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: Removed, Text: l[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Added, Text: r[j]})
+			j++
+		}
+	}
+	for ; i < len(l); i++ {
+		lines = append(lines, Line{Op: Removed, Text: l[i]})
+	}
+	for ; j < len(r); j++ {
+		lines = append(lines, Line{Op: Added, Text: r[j]})
+	}
+
+	return lines
+}
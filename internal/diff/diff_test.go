@@ -93,3 +93,45 @@ func TestListToMap(t *testing.T) {
 		assert.Equal(t, tc.m, m)
 	}
 }
+
+func TestLines(t *testing.T) {
+	for _, tc := range []struct {
+		l    []string
+		r    []string
+		want []Line
+	}{
+		{},
+		{
+			l: []string{"foo", "bar"},
+			r: []string{"foo", "bar"},
+			want: []Line{
+				{Op: Equal, Text: "foo"},
+				{Op: Equal, Text: "bar"},
+			},
+		},
+		{
+			l: []string{"foo", "bar", "baz"},
+			r: []string{"foo", "zab", "baz", "new"},
+			want: []Line{
+				{Op: Equal, Text: "foo"},
+				{Op: Removed, Text: "bar"},
+				{Op: Added, Text: "zab"},
+				{Op: Equal, Text: "baz"},
+				{Op: Added, Text: "new"},
+			},
+		},
+		{
+			l: []string{"foo"},
+			want: []Line{
+				{Op: Removed, Text: "foo"},
+			},
+		},
+	} {
+		got := Lines(tc.l, tc.r)
+		if tc.want == nil {
+			assert.Empty(t, got)
+			continue
+		}
+		assert.Equal(t, tc.want, got)
+	}
+}
@@ -0,0 +1,161 @@
+// Package dotenv reads and writes the .env file format understood by most
+// dotenv loaders (docker compose, python-dotenv, node dotenv).
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Var is a single variable assignment.
+type Var struct {
+	Name  string
+	Value string
+}
+
+var (
+	validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	plainRe   = regexp.MustCompile(`^[A-Za-z0-9_./:@+,%=-]*$`)
+)
+
+// ValidName returns true if name can be used as a variable name.
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
+// Parse reads all assignments from r. Blank lines, comments and a leading
+// "export" are ignored. Single quoted values are taken literally, double
+// quoted values support the usual escapes and may span multiple lines.
+func Parse(r io.Reader) ([]Var, error) {
+	var vars []Var
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var lineNo int
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		name, value, found := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !found || !ValidName(name) {
+			return nil, fmt.Errorf("line %d: invalid assignment", lineNo)
+		}
+		value = strings.TrimLeft(value, " \t")
+
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quote", lineNo)
+			}
+			value = value[1 : end+1]
+		case strings.HasPrefix(value, `"`):
+			// read continuation lines until the closing quote.
+			raw := value[1:]
+			for {
+				v, ok := unquote(raw)
+				if ok {
+					value = v
+					break
+				}
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated double quote", lineNo)
+				}
+				lineNo++
+				raw += "\n" + scanner.Text()
+			}
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = value[:i]
+			}
+			value = strings.TrimSpace(value)
+		}
+
+		vars = append(vars, Var{Name: name, Value: value})
+	}
+
+	return vars, scanner.Err()
+}
+
+// unquote decodes a double quoted value without the opening quote. It
+// returns false if the closing quote is missing.
+func unquote(s string) (string, bool) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return sb.String(), true
+		case '\\':
+			if i+1 >= len(s) {
+				sb.WriteByte(c)
+				continue
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '"', '\\', '$', '`':
+				sb.WriteByte(s[i])
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(s[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", false
+}
+
+// Write writes the variables in order, quoting values as needed.
+func Write(w io.Writer, vars []Var) error {
+	for _, v := range vars {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", v.Name, Quote(v.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Quote returns value in a form that every dotenv loader reads back
+// unchanged. Simple values are not quoted, values without single quotes and
+// newlines are single quoted to avoid variable expansion and everything else
+// is double quoted with escapes.
+func Quote(value string) string {
+	if plainRe.MatchString(value) {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, c := range value {
+		switch c {
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '"', '\\', '$', '`':
+			sb.WriteByte('\\')
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
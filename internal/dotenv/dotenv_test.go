@@ -0,0 +1,78 @@
+package dotenv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	in := `# database
+DB_USER=admin
+export DB_PASSWORD = 'hunter2 #not a comment'
+DB_HOST=localhost # a comment
+
+API_KEY="line1
+line2\t\"quoted\" \$HOME"
+EMPTY=
+`
+	vars, err := Parse(strings.NewReader(in))
+	require.NoError(t, err)
+	assert.Equal(t, []Var{
+		{Name: "DB_USER", Value: "admin"},
+		{Name: "DB_PASSWORD", Value: "hunter2 #not a comment"},
+		{Name: "DB_HOST", Value: "localhost"},
+		{Name: "API_KEY", Value: "line1\nline2\t\"quoted\" $HOME"},
+		{Name: "EMPTY", Value: ""},
+	}, vars)
+
+	for _, in := range []string{
+		"NOVALUE",
+		"1FOO=bar",
+		"FOO='bar",
+		`FOO="bar`,
+	} {
+		_, err := Parse(strings.NewReader(in))
+		assert.Error(t, err, in)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"":                "",
+		"hunter2":         "hunter2",
+		"postgres://u@h/": "postgres://u@h/",
+		"with space":      "'with space'",
+		"$HOME":           "'$HOME'",
+		"it's":            `"it's"`,
+		"a\nb":            `"a\nb"`,
+		"a\"$b":           `'a"$b'`,
+	} {
+		assert.Equal(t, want, Quote(in), in)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	vars := []Var{
+		{Name: "PLAIN", Value: "foo"},
+		{Name: "SPACES", Value: " foo bar "},
+		{Name: "QUOTES", Value: `it's "quoted"`},
+		{Name: "MULTI", Value: "-----BEGIN KEY-----\nabc\\n$x\n-----END KEY-----"},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Write(buf, vars))
+
+	got, err := Parse(buf)
+	require.NoError(t, err)
+	assert.Equal(t, vars, got)
+}
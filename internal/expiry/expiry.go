@@ -0,0 +1,100 @@
+// Package expiry implements secrets with an explicit validity. A secret can
+// contain an expires key with the point in time when it (e.g. an API token or
+// a certificate) becomes invalid. It is only a reminder, gopass doesn't
+// prevent using expired secrets.
+package expiry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+)
+
+const (
+	// Key is the key holding the expiry date.
+	Key = "expires"
+	// Soon is the time before the expiry when a secret is considered to be
+	// expiring soon.
+	Soon = 30 * 24 * time.Hour
+
+	dateLayout = "2006-01-02"
+	day        = 24 * time.Hour
+)
+
+// layouts are the accepted formats for absolute dates, the first one is
+// preferred.
+var layouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	dateLayout,
+}
+
+// Parse parses an expiry date. Besides absolute dates (e.g. 2022-12-31 or
+// 2022-12-31T18:00:00Z) it accepts durations relative to now, e.g. 90d or 12h.
+// Dates without a time refer to the beginning of that day (local time).
+func Parse(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if d, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && strings.HasSuffix(s, "d") {
+		return now.Add(time.Duration(d) * day), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid expiry %q. Use a date (YYYY-MM-DD) or a duration (e.g. 90d)", s)
+}
+
+// Format returns the representation of t that is stored in a secret.
+func Format(t time.Time) string {
+	if t.Equal(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())) {
+		return t.Format(dateLayout)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// Get returns the expiry date of the secret, if it has a valid one.
+func Get(sec gopass.Secret) (time.Time, bool) {
+	v, found := sec.Get(Key)
+	if !found {
+		return time.Time{}, false
+	}
+
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, strings.TrimSpace(v), time.Local); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Describe returns a human readable description of the time left until t,
+// e.g. "expires in 3 days" or "expired 2 hours ago".
+func Describe(t, now time.Time) string {
+	d := t.Sub(now)
+	if d <= 0 {
+		return "expired " + duration(-d) + " ago"
+	}
+
+	return "expires in " + duration(d)
+}
+
+func duration(d time.Duration) string {
+	switch {
+	case d >= 2*day:
+		return fmt.Sprintf("%d days", d/day)
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	default:
+		return fmt.Sprintf("%d minutes", d/time.Minute)
+	}
+}
@@ -0,0 +1,64 @@
+package expiry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2022, 1, 10, 12, 0, 0, 0, time.Local)
+
+	for _, tc := range []struct {
+		in   string
+		want time.Time
+	}{
+		{"2022-12-31", time.Date(2022, 12, 31, 0, 0, 0, 0, time.Local)},
+		{"2022-12-31 18:30", time.Date(2022, 12, 31, 18, 30, 0, 0, time.Local)},
+		{"2022-12-31T18:30:00Z", time.Date(2022, 12, 31, 18, 30, 0, 0, time.UTC)},
+		{"90d", now.Add(90 * 24 * time.Hour)},
+		{"12h", now.Add(12 * time.Hour)},
+	} {
+		got, err := Parse(tc.in, now)
+		require.NoError(t, err, tc.in)
+		assert.True(t, tc.want.Equal(got), "%s: %s != %s", tc.in, tc.want, got)
+	}
+
+	for _, tc := range []string{"", "tomorrow", "d", "31.12.2022"} {
+		_, err := Parse(tc, now)
+		assert.Error(t, err, tc)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "2022-12-31", Format(time.Date(2022, 12, 31, 0, 0, 0, 0, time.Local)))
+	assert.Equal(t, "2022-12-31T18:30:00Z", Format(time.Date(2022, 12, 31, 18, 30, 0, 0, time.UTC)))
+}
+
+func TestGet(t *testing.T) {
+	sec := secrets.New()
+	_, found := Get(sec)
+	assert.False(t, found)
+
+	require.NoError(t, sec.Set(Key, "2022-12-31"))
+	ts, found := Get(sec)
+	assert.True(t, found)
+	assert.Equal(t, "2022-12-31", Format(ts))
+
+	require.NoError(t, sec.Set(Key, "never"))
+	_, found = Get(sec)
+	assert.False(t, found)
+}
+
+func TestDescribe(t *testing.T) {
+	now := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "expires in 3 days", Describe(now.Add(3*24*time.Hour+time.Hour), now))
+	assert.Equal(t, "expires in 30 hours", Describe(now.Add(30*time.Hour), now))
+	assert.Equal(t, "expires in 5 minutes", Describe(now.Add(5*time.Minute), now))
+	assert.Equal(t, "expired 2 days ago", Describe(now.Add(-50*time.Hour), now))
+	assert.Equal(t, "expired 0 minutes ago", Describe(now, now))
+}
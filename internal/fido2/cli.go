@@ -0,0 +1,149 @@
+package fido2
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// CLI talks to authenticators with the fido2-cred, fido2-assert and
+// fido2-token tools from libfido2.
+type CLI struct {
+	// Device is the path of the authenticator, e.g. /dev/hidraw0. If it's
+	// empty GOPASS_FIDO2_DEVICE or the first device found is used.
+	Device string
+}
+
+// MakeCredential implements Authenticator.
+func (c *CLI) MakeCredential(ctx context.Context, user string) ([]byte, error) {
+	dev, err := c.device(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := make([]byte, 16)
+	if _, err := rand.Read(userID); err != nil {
+		return nil, err
+	}
+	in, err := input(RelyingParty, user, b64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := c.run(ctx, in, true, "fido2-cred", "-M", "-h", "-r", dev)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCredential(buf)
+}
+
+// HMACSecret implements Authenticator.
+func (c *CLI) HMACSecret(ctx context.Context, credential, salt []byte) ([]byte, error) {
+	dev, err := c.device(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := input(RelyingParty, b64(credential), b64(salt))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := c.run(ctx, in, true, "fido2-assert", "-G", "-h", dev)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseHMACSecret(buf)
+}
+
+func (c *CLI) device(ctx context.Context) (string, error) {
+	if c.Device != "" {
+		return c.Device, nil
+	}
+	if dev := os.Getenv("GOPASS_FIDO2_DEVICE"); dev != "" {
+		return dev, nil
+	}
+
+	buf, err := c.run(ctx, nil, false, "fido2-token", "-L")
+	if err != nil {
+		return "", err
+	}
+
+	return parseDevice(buf)
+}
+
+// run runs a libfido2 tool. If the authenticator needs to be touched the
+// user is asked to on stderr, which is never part of the output of gopass.
+func (c *CLI) run(ctx context.Context, in []byte, touch bool, name string, args ...string) ([]byte, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if touch {
+		fmt.Fprintln(os.Stderr, "Please touch your FIDO2 authenticator ...")
+	}
+
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// input returns the input for fido2-cred and fido2-assert. Both expect a
+// random client data hash in the first line, we don't use attestations or
+// assertion signatures.
+func input(lines ...string) ([]byte, error) {
+	cdh := make([]byte, 32)
+	if _, err := rand.Read(cdh); err != nil {
+		return nil, err
+	}
+	return []byte(b64(cdh) + "\n" + strings.Join(lines, "\n") + "\n"), nil
+}
+
+// parseDevice returns the first device listed by fido2-token -L, e.g.
+// "/dev/hidraw4: vendor=0x1050, product=0x0407 (Yubico YubiKey OTP+FIDO+CCID)".
+func parseDevice(buf []byte) (string, error) {
+	for _, line := range strings.Split(string(buf), "\n") {
+		if dev, _, found := strings.Cut(line, ": "); found && dev != "" {
+			return dev, nil
+		}
+	}
+	return "", fmt.Errorf("no FIDO2 authenticator found")
+}
+
+// parseCredential returns the credential id from the output of fido2-cred
+// -M. It's the fifth line after the client data hash, relying party,
+// format and authenticator data.
+func parseCredential(buf []byte) ([]byte, error) {
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	if len(lines) < 5 {
+		return nil, fmt.Errorf("unexpected output of fido2-cred: %d lines", len(lines))
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(lines[4]))
+}
+
+// parseHMACSecret returns the hmac-secret from the output of fido2-assert
+// -G -h. It's always the last line.
+func parseHMACSecret(buf []byte) ([]byte, error) {
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	if len(lines) < 5 {
+		return nil, fmt.Errorf("unexpected output of fido2-assert: %d lines", len(lines))
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(lines[len(lines)-1]))
+}
+
+func b64(buf []byte) string {
+	return base64.StdEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,24 @@
+package fido2
+
+import "context"
+
+type contextKey int
+
+const (
+	ctxKeyAuthenticator contextKey = iota
+)
+
+// WithAuthenticator returns a context with the authenticator set.
+func WithAuthenticator(ctx context.Context, a Authenticator) context.Context {
+	return context.WithValue(ctx, ctxKeyAuthenticator, a)
+}
+
+// GetAuthenticator returns the authenticator from the context. It defaults to
+// the libfido2 command line tools.
+func GetAuthenticator(ctx context.Context) Authenticator {
+	a, ok := ctx.Value(ctxKeyAuthenticator).(Authenticator)
+	if !ok || a == nil {
+		return &CLI{}
+	}
+	return a
+}
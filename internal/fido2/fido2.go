@@ -0,0 +1,185 @@
+// Package fido2 implements an optional layer that protects the secrets of a
+// store with a symmetric key in addition to the crypto backend. The key is
+// wrapped with the hmac-secret of one or more FIDO2 authenticators, so
+// reading a secret requires a touch even if gpg-agent has cached the
+// passphrase. Malware that can use the agent can't decrypt the secrets on
+// its own.
+package fido2
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// KeyringFile holds the wrapped keys of a store.
+	KeyringFile = ".fido2"
+	// RelyingParty is the FIDO2 relying party id of gopass credentials.
+	RelyingParty = "gopass"
+
+	// magic prefixes sealed secrets.
+	magic    = "gopass-fido2/v1\n"
+	saltSize = 32
+)
+
+var (
+	// ErrNotEnrolled is returned if no authenticator is enrolled.
+	ErrNotEnrolled = errors.New("no FIDO2 authenticator enrolled")
+	// ErrUnlock is returned if none of the enrolled authenticators could
+	// unwrap the key.
+	ErrUnlock = errors.New("none of the enrolled FIDO2 authenticators is available")
+)
+
+// Authenticator is a FIDO2 authenticator that supports the hmac-secret
+// extension.
+type Authenticator interface {
+	// MakeCredential creates a new resident credential with the hmac-secret
+	// extension enabled and returns its id.
+	MakeCredential(ctx context.Context, user string) ([]byte, error)
+	// HMACSecret returns the hmac-secret of the credential for the given
+	// salt. The authenticator asks for a touch.
+	HMACSecret(ctx context.Context, credential, salt []byte) ([]byte, error)
+}
+
+// Device is an authenticator enrolled for a store.
+type Device struct {
+	Name       string `json:"name"`
+	Credential []byte `json:"credential"`
+	Salt       []byte `json:"salt"`
+	// Key is the key of the store, sealed with the hmac-secret.
+	Key []byte `json:"key"`
+}
+
+// Keyring contains the devices enrolled for a store.
+type Keyring struct {
+	Devices []Device `json:"devices"`
+}
+
+// ParseKeyring parses the content of a KeyringFile.
+func ParseKeyring(buf []byte) (*Keyring, error) {
+	k := &Keyring{}
+	if err := json.Unmarshal(buf, k); err != nil {
+		return nil, fmt.Errorf("failed to parse FIDO2 keyring: %w", err)
+	}
+	return k, nil
+}
+
+// Bytes returns the content of a KeyringFile.
+func (k *Keyring) Bytes() ([]byte, error) {
+	buf, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, '\n'), nil
+}
+
+// Unlock returns the key of the store. It tries the enrolled devices in
+// order until one of them is available.
+func (k *Keyring) Unlock(ctx context.Context, a Authenticator) ([]byte, error) {
+	if len(k.Devices) < 1 {
+		return nil, ErrNotEnrolled
+	}
+
+	for _, d := range k.Devices {
+		secret, err := a.HMACSecret(ctx, d.Credential, d.Salt)
+		if err != nil {
+			debug.Log("FIDO2 device %q not available: %s", d.Name, err)
+			continue
+		}
+		key, err := Open(secret, d.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap the key with %q: %w", d.Name, err)
+		}
+		return key, nil
+	}
+
+	return nil, ErrUnlock
+}
+
+// Enroll adds a new device that can unwrap the given key.
+func (k *Keyring) Enroll(ctx context.Context, a Authenticator, name string, key []byte) error {
+	cred, err := a.MakeCredential(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to create FIDO2 credential: %w", err)
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	secret, err := a.HMACSecret(ctx, cred, salt)
+	if err != nil {
+		return fmt.Errorf("failed to get hmac-secret: %w", err)
+	}
+
+	wrapped, err := Seal(secret, key)
+	if err != nil {
+		return err
+	}
+
+	k.Devices = append(k.Devices, Device{
+		Name:       name,
+		Credential: cred,
+		Salt:       salt,
+		Key:        wrapped,
+	})
+	return nil
+}
+
+// NewKey returns a new random key for a store.
+func NewKey() ([]byte, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// IsSealed returns true if data was sealed with Seal.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+// Seal encrypts and authenticates the plaintext with the key.
+func Seal(key, plaintext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(magic)+aead.NonceSize(), len(magic)+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	copy(out, magic)
+	nonce := out[len(magic):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(out, nonce, plaintext, []byte(magic)), nil
+}
+
+// Open decrypts data sealed with Seal.
+func Open(key, data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return nil, fmt.Errorf("not sealed")
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[len(magic):]
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("sealed data too short")
+	}
+
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(magic))
+}
@@ -0,0 +1,116 @@
+package fido2
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuthenticator derives the hmac-secret from a device secret like a real
+// authenticator does.
+type fakeAuthenticator struct {
+	secret  string
+	creds   int
+	touches int
+}
+
+func (f *fakeAuthenticator) MakeCredential(ctx context.Context, user string) ([]byte, error) {
+	f.creds++
+	return []byte(fmt.Sprintf("%s-%d", f.secret, f.creds)), nil
+}
+
+func (f *fakeAuthenticator) HMACSecret(ctx context.Context, credential, salt []byte) ([]byte, error) {
+	f.touches++
+	if len(credential) <= len(f.secret) || string(credential[:len(f.secret)]) != f.secret {
+		return nil, fmt.Errorf("no credentials")
+	}
+	mac := hmac.New(sha256.New, credential)
+	_, _ = mac.Write(salt)
+	return mac.Sum(nil), nil
+}
+
+func TestSeal(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	sealed, err := Seal(key, []byte("secret"))
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "secret")
+
+	plain, err := Open(key, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plain))
+
+	other, err := NewKey()
+	require.NoError(t, err)
+	_, err = Open(other, sealed)
+	assert.Error(t, err)
+
+	_, err = Open(key, []byte("secret"))
+	assert.Error(t, err)
+	assert.False(t, IsSealed([]byte("secret")))
+}
+
+func TestKeyring(t *testing.T) {
+	ctx := context.Background()
+	primary := &fakeAuthenticator{secret: "primary"}
+	backup := &fakeAuthenticator{secret: "backup"}
+
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	kr := &Keyring{}
+	_, err = kr.Unlock(ctx, primary)
+	assert.ErrorIs(t, err, ErrNotEnrolled)
+
+	require.NoError(t, kr.Enroll(ctx, primary, "primary", key))
+	require.NoError(t, kr.Enroll(ctx, backup, "backup", key))
+
+	buf, err := kr.Bytes()
+	require.NoError(t, err)
+	kr, err = ParseKeyring(buf)
+	require.NoError(t, err)
+	require.Len(t, kr.Devices, 2)
+	assert.NotEqual(t, key, kr.Devices[0].Key)
+
+	for _, a := range []*fakeAuthenticator{primary, backup} {
+		got, err := kr.Unlock(ctx, a)
+		require.NoError(t, err)
+		assert.Equal(t, key, got)
+	}
+
+	_, err = kr.Unlock(ctx, &fakeAuthenticator{secret: "other"})
+	assert.ErrorIs(t, err, ErrUnlock)
+}
+
+func TestParseOutput(t *testing.T) {
+	dev, err := parseDevice([]byte("/dev/hidraw4: vendor=0x1050, product=0x0407 (Yubico YubiKey OTP+FIDO+CCID)\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/hidraw4", dev)
+	_, err = parseDevice(nil)
+	assert.Error(t, err)
+
+	cred, err := parseCredential([]byte("Y2Ro\ngopass\npacked\nYXV0aA==\nY3JlZA==\nc2ln\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "cred", string(cred))
+	_, err = parseCredential([]byte("Y2Ro\ngopass\n"))
+	assert.Error(t, err)
+
+	secret, err := parseHMACSecret([]byte("Y2Ro\ngopass\nYXV0aA==\nc2ln\nc2VjcmV0\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(secret))
+}
+
+func TestGetAuthenticator(t *testing.T) {
+	ctx := context.Background()
+	assert.IsType(t, &CLI{}, GetAuthenticator(ctx))
+
+	a := &fakeAuthenticator{}
+	assert.Equal(t, a, GetAuthenticator(WithAuthenticator(ctx, a)))
+}
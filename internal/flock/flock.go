@@ -0,0 +1,78 @@
+// Package flock implements advisory file locks that are shared between
+// processes. They only protect against other processes that use the same
+// lock file, e.g. concurrent gopass invocations.
+package flock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrLocked is returned by TryAcquire if the lock is held by someone else.
+var ErrLocked = errors.New("locked by another process")
+
+// pollInterval is the time to wait between attempts to acquire a lock.
+var pollInterval = 50 * time.Millisecond
+
+// Lock is an acquired file lock.
+type Lock struct {
+	fh *os.File
+}
+
+// TryAcquire tries to take an exclusive lock on the file at path, creating
+// it if necessary. It does not wait and returns ErrLocked if the lock is
+// already held.
+func TryAcquire(path string) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create lock dir: %w", err)
+	}
+
+	fh, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := lock(fh); err != nil {
+		_ = fh.Close()
+		return nil, err
+	}
+
+	return &Lock{fh: fh}, nil
+}
+
+// Acquire waits until it holds an exclusive lock on the file at path or the
+// context is done.
+func Acquire(ctx context.Context, path string) (*Lock, error) {
+	for {
+		l, err := TryAcquire(path)
+		if !errors.Is(err, ErrLocked) {
+			return l, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s: %w", ctx.Err(), ErrLocked)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Release releases the lock. The lock file is kept, removing it could
+// break the mutual exclusion of processes that already opened it.
+func (l *Lock) Release() error {
+	if l == nil || l.fh == nil {
+		return nil
+	}
+
+	err := unlock(l.fh)
+	if cerr := l.fh.Close(); err == nil {
+		err = cerr
+	}
+	l.fh = nil
+
+	return err
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+// +build !windows
+
+package flock
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func lock(fh *os.File) error {
+	err := syscall.Flock(int(fh.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrLocked
+	}
+
+	return err
+}
+
+func unlock(fh *os.File) error {
+	return syscall.Flock(int(fh.Fd()), syscall.LOCK_UN)
+}
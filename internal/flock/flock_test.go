@@ -0,0 +1,39 @@
+package flock
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "locks", "store.lock")
+
+	l, err := TryAcquire(fn)
+	require.NoError(t, err)
+
+	// a second lock on the same file must fail, even from the same process
+	_, err = TryAcquire(fn)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = Acquire(ctx, fn)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	// waiters get the lock once it is released
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, l.Release())
+	}()
+	l2, err := Acquire(context.Background(), fn)
+	require.NoError(t, err)
+	assert.NoError(t, l2.Release())
+
+	// releasing twice is fine
+	assert.NoError(t, l2.Release())
+}
@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package flock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func lock(fh *os.File) error {
+	ol := new(windows.Overlapped)
+	err := windows.LockFileEx(windows.Handle(fh.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return ErrLocked
+	}
+
+	return err
+}
+
+func unlock(fh *os.File) error {
+	ol := new(windows.Overlapped)
+
+	return windows.UnlockFileEx(windows.Handle(fh.Fd()), 0, 1, 0, ol)
+}
@@ -0,0 +1,294 @@
+// Package githooks generates and implements git hooks that enforce the
+// policies of a shared password store at the repository boundary.
+//
+// The pre-commit hook makes sure that every staged secret is encrypted for
+// exactly the recipients listed in the recipients file that applies to it.
+// The pre-push hook additionally verifies the signatures of all commits that
+// are about to be pushed.
+package githooks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/diff"
+	"github.com/gopasspw/gopass/internal/recipients"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Hook names.
+const (
+	PreCommit = "pre-commit"
+	PrePush   = "pre-push"
+)
+
+// marker identifies hooks generated by gopass.
+const marker = "# generated by gopass git install-hooks"
+
+// zeroSHA is used by git for refs that don't exist.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// Names returns the names of all hooks.
+func Names() []string {
+	return []string{PreCommit, PrePush}
+}
+
+// Script returns the hook script that invokes the given gopass binary.
+func Script(binary, hook, store string) []byte {
+	return []byte(fmt.Sprintf("#!/bin/sh\n%s\nexec %s git hook --store %s %s \"$@\"\n",
+		marker, shellQuote(binary), shellQuote(store), hook))
+}
+
+// Install writes all hooks into the hooks directory of the repository at dir.
+// Existing hooks not generated by gopass are only replaced if force is set.
+func Install(ctx context.Context, dir, binary, store string, force bool) ([]string, error) {
+	buf, err := git(ctx, dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return nil, err
+	}
+	hookDir := strings.TrimSpace(string(buf))
+	if !filepath.IsAbs(hookDir) {
+		hookDir = filepath.Join(dir, hookDir)
+	}
+	if err := os.MkdirAll(hookDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", hookDir, err)
+	}
+
+	var written []string
+	for _, hook := range Names() {
+		fn := filepath.Join(hookDir, hook)
+		if old, err := os.ReadFile(fn); err == nil && !bytes.Contains(old, []byte(marker)) && !force {
+			return written, fmt.Errorf("%s already exists. Use --force to replace it", fn)
+		}
+		if err := os.WriteFile(fn, Script(binary, hook, store), 0o755); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", fn, err)
+		}
+		written = append(written, fn)
+	}
+
+	return written, nil
+}
+
+// Violation is a secret that is not encrypted for the approved recipients.
+type Violation struct {
+	Name    string
+	Extra   []string
+	Missing []string
+	// Invalid is set if the recipients of the secret can't be read, e.g.
+	// because it's not encrypted at all.
+	Invalid string
+}
+
+func (v Violation) String() string {
+	var parts []string
+	if v.Invalid != "" {
+		parts = append(parts, "invalid: "+v.Invalid)
+	}
+	if len(v.Extra) > 0 {
+		parts = append(parts, "not approved: "+strings.Join(v.Extra, ", "))
+	}
+	if len(v.Missing) > 0 {
+		parts = append(parts, "missing: "+strings.Join(v.Missing, ", "))
+	}
+	return v.Name + " (" + strings.Join(parts, "; ") + ")"
+}
+
+// CheckStaged checks all secrets staged for the next commit.
+func CheckStaged(ctx context.Context, dir string, crypto backend.Crypto) ([]Violation, error) {
+	buf, err := git(ctx, dir, "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return nil, err
+	}
+	idfs, err := git(ctx, dir, "ls-files", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	return checkRecipients(ctx, dir, crypto, ":", split(buf), split(idfs))
+}
+
+// CheckPush reads the refs to be pushed in the format git passes them to the
+// pre-push hook and checks the signatures of all new commits and the
+// recipients of all secrets changed by them.
+func CheckPush(ctx context.Context, dir string, crypto backend.Crypto, refs io.Reader) ([]string, []Violation, error) {
+	var unsigned []string
+	var violations []Violation
+
+	sc := bufio.NewScanner(refs)
+	for sc.Scan() {
+		// <local ref> <local sha> <remote ref> <remote sha>
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 4 || fields[1] == zeroSHA {
+			continue
+		}
+		local, remote := fields[1], fields[3]
+
+		revs := []string{local, "--not", "--remotes"}
+		if remote != zeroSHA {
+			revs = []string{remote + ".." + local}
+		}
+
+		buf, err := git(ctx, dir, append([]string{"rev-list"}, revs...)...)
+		if err != nil {
+			return nil, nil, err
+		}
+		commits := strings.Fields(string(buf))
+		for _, c := range commits {
+			if _, err := git(ctx, dir, "verify-commit", c); err != nil {
+				debug.Log("commit %s is not signed: %s", c, err)
+				unsigned = append(unsigned, c)
+			}
+		}
+		if len(commits) < 1 {
+			continue
+		}
+
+		base := commits[len(commits)-1] + "^"
+		if remote != zeroSHA {
+			base = remote
+		}
+		buf, err = git(ctx, dir, "diff", "--name-only", "--diff-filter=ACMR", "-z", base, local)
+		if err != nil {
+			// the first commit of a repository has no parent
+			buf, err = git(ctx, dir, "ls-tree", "-r", "--name-only", "-z", local)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		idfs, err := git(ctx, dir, "ls-tree", "-r", "--name-only", "-z", local)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		vs, err := checkRecipients(ctx, dir, crypto, local+":", split(buf), split(idfs))
+		if err != nil {
+			return nil, nil, err
+		}
+		violations = append(violations, vs...)
+	}
+
+	return unsigned, violations, sc.Err()
+}
+
+// checkRecipients compares the recipients of the given files in rev (e.g.
+// ":" for the index) with the recipients file that applies to them. The
+// recipients file is read from rev, too, so this makes sure that secrets and
+// recipients are consistent. It doesn't prevent changing both together.
+func checkRecipients(ctx context.Context, dir string, crypto backend.Crypto, rev string, files, tree []string) ([]Violation, error) {
+	ext := "." + crypto.Ext()
+	idFiles := make(map[string]bool, len(tree))
+	for _, f := range tree {
+		if path.Base(f) == crypto.IDFile() {
+			idFiles[f] = true
+		}
+	}
+
+	var violations []Violation
+	for _, f := range files {
+		if !strings.HasSuffix(f, ext) || strings.HasPrefix(path.Base(f), ".") {
+			continue
+		}
+
+		idf := lookupIDFile(idFiles, f, crypto.IDFile())
+		if idf == "" {
+			violations = append(violations, Violation{Name: f, Missing: []string{"no " + crypto.IDFile()}})
+			continue
+		}
+		buf, err := git(ctx, dir, "show", rev+idf)
+		if err != nil {
+			return nil, err
+		}
+		want := fingerprints(ctx, crypto, recipients.Unmarshal(buf))
+
+		ciphertext, err := git(ctx, dir, "show", rev+f)
+		if err != nil {
+			return nil, err
+		}
+		have, err := crypto.RecipientIDs(ctx, ciphertext)
+		if errors.Is(err, backend.ErrHiddenRecipients) {
+			debug.Log("recipients of %s are hidden", f)
+			continue
+		}
+		if err != nil {
+			debug.Log("failed to read recipients of %s: %s", f, err)
+			violations = append(violations, Violation{
+				Name:    strings.TrimSuffix(f, ext),
+				Invalid: err.Error(),
+			})
+			continue
+		}
+		have = fingerprints(ctx, crypto, have)
+
+		extra, missing := diff.List(want, have)
+		if len(extra) > 0 || len(missing) > 0 {
+			violations = append(violations, Violation{
+				Name:    strings.TrimSuffix(f, ext),
+				Extra:   extra,
+				Missing: missing,
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// lookupIDFile walks up from the file until it finds a recipients file.
+func lookupIDFile(idFiles map[string]bool, fn, name string) string {
+	dir := path.Dir(fn)
+	for {
+		idf := path.Join(dir, name)
+		if idFiles[idf] {
+			return idf
+		}
+		if dir == "." || dir == "/" {
+			return ""
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+func fingerprints(ctx context.Context, crypto backend.Crypto, in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, r := range in {
+		out = append(out, crypto.Fingerprint(ctx, r))
+	}
+	return out
+}
+
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = stderr
+
+	debug.Log("git %+v", args)
+	buf, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return buf, nil
+}
+
+func split(buf []byte) []string {
+	var res []string
+	for _, f := range strings.Split(string(buf), "\x00") {
+		if f != "" {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
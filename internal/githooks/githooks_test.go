@@ -0,0 +1,162 @@
+//go:build !windows
+// +build !windows
+
+package githooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unreadableCrypto fails to read the recipients of secrets containing
+// "plaintext" and reports hidden recipients for secrets containing "hidden".
+type unreadableCrypto struct {
+	*plain.Mocker
+}
+
+func (u unreadableCrypto) RecipientIDs(ctx context.Context, ciphertext []byte) ([]string, error) {
+	switch {
+	case strings.Contains(string(ciphertext), "plaintext"):
+		return nil, fmt.Errorf("no encrypted packet found")
+	case strings.Contains(string(ciphertext), "hidden"):
+		return nil, backend.ErrHiddenRecipients
+	default:
+		return u.Mocker.RecipientIDs(ctx, ciphertext)
+	}
+}
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.org")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("HOME", dir)
+
+	_, err := git(context.Background(), dir, "init", "-q")
+	require.NoError(t, err)
+
+	return dir
+}
+
+func writeAndAdd(t *testing.T, dir, fn, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, fn)), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fn), []byte(content), 0o644))
+	_, err := git(context.Background(), dir, "add", fn)
+	require.NoError(t, err)
+}
+
+func TestInstall(t *testing.T) {
+	ctx := context.Background()
+	dir := gitRepo(t)
+
+	written, err := Install(ctx, dir, "/usr/bin/gopass", "work", false)
+	require.NoError(t, err)
+	assert.Len(t, written, 2)
+
+	buf, err := os.ReadFile(filepath.Join(dir, ".git", "hooks", PrePush))
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "exec '/usr/bin/gopass' git hook --store 'work' pre-push")
+
+	// our own hooks can be replaced
+	_, err = Install(ctx, dir, "/usr/bin/gopass", "work", false)
+	require.NoError(t, err)
+
+	// others only with force
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "hooks", PreCommit), []byte("#!/bin/sh\n"), 0o755))
+	_, err = Install(ctx, dir, "/usr/bin/gopass", "work", false)
+	assert.Error(t, err)
+	_, err = Install(ctx, dir, "/usr/bin/gopass", "work", true)
+	assert.NoError(t, err)
+}
+
+func TestCheckStaged(t *testing.T) {
+	ctx := context.Background()
+	dir := gitRepo(t)
+	crypto := plain.New()
+
+	// the plain backend reports 0xDEADBEEF and 0xFEEDBEEF for any secret
+	writeAndAdd(t, dir, crypto.IDFile(), "0xDEADBEEF\n0xFEEDBEEF\n")
+	writeAndAdd(t, dir, "foo."+crypto.Ext(), "secret")
+
+	vs, err := CheckStaged(ctx, dir, crypto)
+	require.NoError(t, err)
+	assert.Empty(t, vs)
+
+	writeAndAdd(t, dir, filepath.Join("team", crypto.IDFile()), "0xDEADBEEF\n0xCAFEBABE\n")
+	writeAndAdd(t, dir, "team/bar."+crypto.Ext(), "secret")
+
+	vs, err = CheckStaged(ctx, dir, crypto)
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{{
+		Name:    "team/bar",
+		Extra:   []string{"0xFEEDBEEF"},
+		Missing: []string{"0xCAFEBABE"},
+	}}, vs)
+	assert.Equal(t, "team/bar (not approved: 0xFEEDBEEF; missing: 0xCAFEBABE)", vs[0].String())
+}
+
+func TestCheckStagedUnreadable(t *testing.T) {
+	ctx := context.Background()
+	dir := gitRepo(t)
+	crypto := unreadableCrypto{Mocker: plain.New()}
+
+	writeAndAdd(t, dir, crypto.IDFile(), "0xDEADBEEF\n0xFEEDBEEF\n")
+	writeAndAdd(t, dir, "hidden."+crypto.Ext(), "hidden")
+	writeAndAdd(t, dir, "leak."+crypto.Ext(), "plaintext")
+
+	vs, err := CheckStaged(ctx, dir, crypto)
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{{
+		Name:    "leak",
+		Invalid: "no encrypted packet found",
+	}}, vs)
+	assert.Equal(t, "leak (invalid: no encrypted packet found)", vs[0].String())
+}
+
+func TestCheckPush(t *testing.T) {
+	ctx := context.Background()
+	dir := gitRepo(t)
+	crypto := plain.New()
+
+	writeAndAdd(t, dir, crypto.IDFile(), "0xDEADBEEF\n0xFEEDBEEF\n")
+	writeAndAdd(t, dir, "foo."+crypto.Ext(), "secret")
+	_, err := git(ctx, dir, "commit", "-q", "--no-gpg-sign", "-m", "unsigned")
+	require.NoError(t, err)
+
+	buf, err := git(ctx, dir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	sha := strings.TrimSpace(string(buf))
+
+	refs := "refs/heads/master " + sha + " refs/heads/master " + zeroSHA + "\n"
+	unsigned, vs, err := CheckPush(ctx, dir, crypto, strings.NewReader(refs))
+	require.NoError(t, err)
+	assert.Equal(t, []string{sha}, unsigned)
+	assert.Empty(t, vs)
+
+	// deleting a ref is fine
+	refs = "(delete) " + zeroSHA + " refs/heads/old " + sha + "\n"
+	unsigned, vs, err = CheckPush(ctx, dir, crypto, strings.NewReader(refs))
+	require.NoError(t, err)
+	assert.Empty(t, unsigned)
+	assert.Empty(t, vs)
+}
@@ -0,0 +1,176 @@
+// Package i18n translates the user facing messages of gopass. Translations
+// are kept in message catalogs in the format of the gotext tool
+// (locales/<lang>/messages.gotext.json). The message id is the English format
+// string used in the code, so messages without a translation are printed as
+// before.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+type contextKey int
+
+const ctxKeyLanguage contextKey = iota
+
+//go:embed locales/*/messages.gotext.json
+var locales embed.FS
+
+// messages is the format of a gotext catalog file.
+type messages struct {
+	Language string `json:"language"`
+	Messages []struct {
+		ID          string `json:"id"`
+		Translation string `json:"translation"`
+	} `json:"messages"`
+}
+
+var (
+	loadOnce sync.Once
+	cat      *catalog.Builder
+	// known contains the translated ids of each language. Other messages are
+	// formatted with fmt, which doesn't localize numbers.
+	known     map[language.Tag]map[string]bool
+	supported []language.Tag
+	matcher   language.Matcher
+)
+
+func load() {
+	cat = catalog.NewBuilder(catalog.Fallback(language.English))
+	known = map[language.Tag]map[string]bool{}
+	supported = []language.Tag{language.English}
+
+	dirs, err := locales.ReadDir("locales")
+	if err != nil {
+		debug.Log("failed to read message catalogs: %s", err)
+	}
+	for _, d := range dirs {
+		buf, err := locales.ReadFile(path.Join("locales", d.Name(), "messages.gotext.json"))
+		if err != nil {
+			debug.Log("failed to read message catalog %s: %s", d.Name(), err)
+			continue
+		}
+		var m messages
+		if err := json.Unmarshal(buf, &m); err != nil {
+			debug.Log("failed to parse message catalog %s: %s", d.Name(), err)
+			continue
+		}
+		tag := language.Make(m.Language)
+		ids := make(map[string]bool, len(m.Messages))
+		for _, msg := range m.Messages {
+			if msg.Translation == "" {
+				continue
+			}
+			if err := cat.SetString(tag, msg.ID, msg.Translation); err != nil {
+				debug.Log("invalid translation of %q to %s: %s", msg.ID, tag, err)
+				continue
+			}
+			ids[msg.ID] = true
+		}
+		known[tag] = ids
+		supported = append(supported, tag)
+	}
+
+	matcher = language.NewMatcher(supported)
+}
+
+// Languages returns the languages gopass has translations for.
+func Languages() []language.Tag {
+	loadOnce.Do(load)
+	return supported
+}
+
+// Detect returns the language of the user. GOPASS_LANG takes precedence over
+// the POSIX locale variables LC_ALL, LC_MESSAGES and LANG.
+func Detect() language.Tag {
+	loadOnce.Do(load)
+	for _, env := range []string{"GOPASS_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		tag, ok := parseLocale(v)
+		if !ok {
+			return language.English
+		}
+		_, idx, conf := matcher.Match(tag)
+		if conf == language.No {
+			return language.English
+		}
+		return supported[idx]
+	}
+	return language.English
+}
+
+// parseLocale parses POSIX locales like de_DE.UTF-8 or de_AT@euro. The C and
+// POSIX locales mean untranslated messages.
+func parseLocale(s string) (language.Tag, bool) {
+	s, _, _ = strings.Cut(s, ".")
+	s, _, _ = strings.Cut(s, "@")
+	if s == "" || s == "C" || s == "POSIX" {
+		return language.English, false
+	}
+	tag, err := language.Parse(strings.ReplaceAll(s, "_", "-"))
+	if err != nil {
+		debug.Log("unknown locale %q: %s", s, err)
+		return language.English, false
+	}
+	return tag, true
+}
+
+// WithLanguage returns a context with the language for messages set.
+func WithLanguage(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, ctxKeyLanguage, tag)
+}
+
+// GetLanguage returns the language for messages or the detected one.
+func GetLanguage(ctx context.Context) language.Tag {
+	if tag, ok := ctx.Value(ctxKeyLanguage).(language.Tag); ok {
+		return tag
+	}
+	return defaultLanguage()
+}
+
+var (
+	detectOnce sync.Once
+	detected   language.Tag
+)
+
+func defaultLanguage() language.Tag {
+	detectOnce.Do(func() {
+		detected = Detect()
+	})
+	return detected
+}
+
+// Sprintf formats the translation of format.
+func Sprintf(ctx context.Context, format string, args ...any) string {
+	tag := GetLanguage(ctx)
+	loadOnce.Do(load)
+	if !known[tag][format] {
+		return fmt.Sprintf(format, args...)
+	}
+	return message.NewPrinter(tag, message.Catalog(cat)).Sprintf(format, args...)
+}
+
+// T returns the translation of a message without arguments.
+func T(ctx context.Context, msg string) string {
+	tag := GetLanguage(ctx)
+	loadOnce.Do(load)
+	if !known[tag][msg] {
+		return msg
+	}
+	return message.NewPrinter(tag, message.Catalog(cat)).Sprintf(msg)
+}
@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestParseLocale(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		tag language.Tag
+		ok  bool
+	}{
+		{in: "de_DE.UTF-8", tag: language.MustParse("de-DE"), ok: true},
+		{in: "de_AT@euro", tag: language.MustParse("de-AT"), ok: true},
+		{in: "en", tag: language.English, ok: true},
+		{in: "C", tag: language.English},
+		{in: "POSIX", tag: language.English},
+		{in: "C.UTF-8", tag: language.English},
+		{in: "", tag: language.English},
+	} {
+		tag, ok := parseLocale(tc.in)
+		assert.Equal(t, tc.ok, ok, tc.in)
+		assert.Equal(t, tc.tag, tag, tc.in)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, env := range []string{"GOPASS_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		t.Setenv(env, "")
+	}
+	assert.Equal(t, language.English, Detect())
+
+	t.Setenv("LANG", "de_DE.UTF-8")
+	base, _ := Detect().Base()
+	assert.Equal(t, "de", base.String())
+
+	t.Setenv("LC_ALL", "C")
+	assert.Equal(t, language.English, Detect())
+
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	assert.Equal(t, language.English, Detect())
+
+	t.Setenv("GOPASS_LANG", "de")
+	base, _ = Detect().Base()
+	assert.Equal(t, "de", base.String())
+}
+
+func TestTranslate(t *testing.T) {
+	ctx := WithLanguage(context.Background(), language.German)
+
+	assert.Equal(t, "Passwort eingeben", Sprintf(ctx, "Enter %s", "Passwort"))
+	assert.Equal(t, "not translated: 1000", Sprintf(ctx, "not translated: %d", 1000))
+	assert.Equal(t, "Passphrase zum Entsperren von gopass", T(ctx, "passphrase to unlock gopass"))
+	assert.Equal(t, "not translated", T(ctx, "not translated"))
+
+	ctx = WithLanguage(context.Background(), language.English)
+	assert.Equal(t, "Enter password", Sprintf(ctx, "Enter %s", "password"))
+	assert.Equal(t, "passphrase to unlock gopass", T(ctx, "passphrase to unlock gopass"))
+}
+
+var verbs = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogs(t *testing.T) {
+	dirs, err := locales.ReadDir("locales")
+	require.NoError(t, err)
+
+	for _, d := range dirs {
+		buf, err := locales.ReadFile(path.Join("locales", d.Name(), "messages.gotext.json"))
+		require.NoError(t, err)
+
+		var m messages
+		require.NoError(t, json.Unmarshal(buf, &m), d.Name())
+		assert.Equal(t, d.Name(), m.Language)
+
+		for _, msg := range m.Messages {
+			want := verbs.FindAllString(msg.ID, -1)
+			got := verbs.FindAllString(msg.Translation, -1)
+			sort.Strings(want)
+			sort.Strings(got)
+			assert.Equal(t, want, got, "translation of %q to %s", msg.ID, d.Name())
+		}
+	}
+}
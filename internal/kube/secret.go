@@ -0,0 +1,138 @@
+// Package kube renders secrets as Kubernetes Secret manifests.
+package kube
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// TypeOpaque is the Kubernetes Secret type for arbitrary data.
+	TypeOpaque = "Opaque"
+	// TypeTLS is the Kubernetes Secret type for a certificate and its key.
+	TypeTLS = "kubernetes.io/tls"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+)
+
+var (
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+	validDataKey     = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+)
+
+// Metadata is the metadata of a Kubernetes object.
+type Metadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+// Secret is a Kubernetes Secret manifest.
+type Secret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   Metadata          `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	Data       map[string]string `yaml:"data"`
+}
+
+// NewSecret converts a gopass secret into a Kubernetes Secret. If the secret
+// contains a PEM encoded certificate and private key it becomes a TLS secret.
+// Otherwise the password, all keys and the body become the data of an Opaque
+// secret.
+func NewSecret(name, namespace string, sec gopass.Secret) (*Secret, error) {
+	kname := Name(name)
+	if kname == "" || len(kname) > 253 {
+		return nil, fmt.Errorf("can not derive a valid Kubernetes name from %q", name)
+	}
+
+	ks := &Secret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata: Metadata{
+			Name:      kname,
+			Namespace: namespace,
+			Labels: map[string]string{
+				managedByLabel: "gopass",
+			},
+		},
+		Type: TypeOpaque,
+		Data: map[string]string{},
+	}
+
+	if crt, key := splitTLS(sec.Bytes()); crt != nil && key != nil {
+		ks.Type = TypeTLS
+		ks.Data["tls.crt"] = base64.StdEncoding.EncodeToString(crt)
+		ks.Data["tls.key"] = base64.StdEncoding.EncodeToString(key)
+		return ks, nil
+	}
+
+	if pw := sec.Password(); pw != "" {
+		ks.Data["password"] = base64.StdEncoding.EncodeToString([]byte(pw))
+	}
+	for _, k := range sec.Keys() {
+		if !validDataKey.MatchString(k) {
+			return nil, fmt.Errorf("key %q of %s is not a valid Kubernetes data key", k, name)
+		}
+		v, _ := sec.Get(k)
+		ks.Data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+	}
+	if body := sec.Body(); strings.TrimSpace(body) != "" {
+		if _, found := ks.Data["body"]; found {
+			return nil, fmt.Errorf("%s has both a body and a key named body", name)
+		}
+		ks.Data["body"] = base64.StdEncoding.EncodeToString([]byte(body))
+	}
+
+	return ks, nil
+}
+
+// Name turns a secret name into a valid Kubernetes object name, e.g.
+// "prod/DB_Credentials" becomes "prod-db-credentials".
+func Name(name string) string {
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, ".-")
+}
+
+// Render returns the YAML documents for all secrets.
+func Render(secs []*Secret) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	for _, s := range secs {
+		if err := enc.Encode(s); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// splitTLS returns all PEM encoded certificates and the first private key in
+// buf.
+func splitTLS(buf []byte) ([]byte, []byte) {
+	var crt, key []byte
+	for {
+		var block *pem.Block
+		block, buf = pem.Decode(buf)
+		if block == nil {
+			return crt, key
+		}
+		switch {
+		case block.Type == "CERTIFICATE":
+			crt = append(crt, pem.EncodeToMemory(block)...)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY") && key == nil:
+			key = pem.EncodeToMemory(block)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package kube
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestName(t *testing.T) {
+	for in, want := range map[string]string{
+		"foo":                 "foo",
+		"prod/DB_Credentials": "prod-db-credentials",
+		"example.org":         "example.org",
+		"/.foo bar.":          "foo-bar",
+		"__":                  "",
+	} {
+		assert.Equal(t, want, Name(in), in)
+	}
+}
+
+func TestNewSecretOpaque(t *testing.T) {
+	sec := secrets.NewKVWithData("s3cret", map[string][]string{"user": {"admin"}}, "some notes", false)
+
+	ks, err := NewSecret("db/prod", "apps", sec)
+	require.NoError(t, err)
+	assert.Equal(t, TypeOpaque, ks.Type)
+	assert.Equal(t, "db-prod", ks.Metadata.Name)
+	assert.Equal(t, "apps", ks.Metadata.Namespace)
+	assert.Equal(t, map[string]string{
+		"password": base64.StdEncoding.EncodeToString([]byte("s3cret")),
+		"user":     base64.StdEncoding.EncodeToString([]byte("admin")),
+		"body":     base64.StdEncoding.EncodeToString([]byte("some notes")),
+	}, ks.Data)
+
+	_, err = NewSecret("foo", "", secrets.NewKVWithData("", map[string][]string{"in valid": {"x"}}, "", false))
+	assert.Error(t, err)
+
+	_, err = NewSecret("__", "", sec)
+	assert.Error(t, err)
+}
+
+func TestNewSecretTLS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	kder, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	crt := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	kpem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder})
+
+	ks, err := NewSecret("example.com", "", secrets.NewKVWithData("", nil, string(crt)+string(kpem), false))
+	require.NoError(t, err)
+	assert.Equal(t, TypeTLS, ks.Type)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(crt), ks.Data["tls.crt"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString(kpem), ks.Data["tls.key"])
+
+	// a certificate without key is no TLS secret
+	ks, err = NewSecret("example.com", "", secrets.NewKVWithData("", nil, string(crt), false))
+	require.NoError(t, err)
+	assert.Equal(t, TypeOpaque, ks.Type)
+}
+
+func TestRender(t *testing.T) {
+	ks, err := NewSecret("foo", "default", secrets.NewKVWithData("bar", nil, "", false))
+	require.NoError(t, err)
+
+	buf, err := Render([]*Secret{ks, ks})
+	require.NoError(t, err)
+
+	doc := `apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+  labels:
+    app.kubernetes.io/managed-by: gopass
+type: Opaque
+data:
+  password: YmFy
+`
+	assert.Equal(t, doc+"---\n"+doc, string(buf))
+}
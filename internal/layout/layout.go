@@ -0,0 +1,131 @@
+// Package layout implements shareable definitions of the initial content of
+// a new store. A layout lists the recipients, the folder skeleton, the
+// templates, the store options and whether the gopass git hooks should be
+// installed, so every store of a team starts out the same way.
+package layout
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// KeepFile is created in the folders of the skeleton, so they are tracked by
+// git even before they contain any secret.
+const KeepFile = ".gopass-keep"
+
+// Layout is the definition of a new store.
+type Layout struct {
+	// Recipients are added to the ones given on the command line.
+	Recipients []string `yaml:"recipients,omitempty"`
+	// Folders are created in the new store.
+	Folders []string `yaml:"folders,omitempty"`
+	// Templates maps folders to the content of their template. Use "." for
+	// the top level folder of the store.
+	Templates map[string]string `yaml:"templates,omitempty"`
+	// BreakGlass are the folders that require a recorded reason before
+	// their secrets are decrypted.
+	BreakGlass []string `yaml:"breakglass,omitempty"`
+	// Options are store options, see gopass config --store.
+	Options map[string]string `yaml:"options,omitempty"`
+	// GitHooks installs the gopass git hooks that enforce the recipients
+	// and commit signatures.
+	GitHooks bool `yaml:"githooks,omitempty"`
+}
+
+// Dir returns the directory that contains the named layouts.
+func Dir() string {
+	return filepath.Join(config.Directory(), "layouts")
+}
+
+// Load reads the layout from the given file, or the named layout from Dir.
+func Load(name string) (*Layout, error) {
+	fn := name
+	if !strings.ContainsRune(name, filepath.Separator) && !strings.HasSuffix(name, ".yml") {
+		fn = filepath.Join(Dir(), name+".yml")
+	}
+
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("layout %q not found at %s. Available layouts: %s", name, fn, strings.Join(Names(), ", "))
+		}
+		return nil, fmt.Errorf("failed to read layout %q: %w", name, err)
+	}
+
+	l, err := Parse(buf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid layout %s: %w", fn, err)
+	}
+	return l, nil
+}
+
+// Parse decodes and validates a layout.
+func Parse(buf []byte) (*Layout, error) {
+	l := &Layout{}
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(l); err != nil {
+		return nil, err
+	}
+
+	for _, f := range l.Folders {
+		if err := checkFolder(f); err != nil {
+			return nil, err
+		}
+	}
+	for f := range l.Templates {
+		if err := checkFolder(f); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range l.BreakGlass {
+		if err := checkFolder(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// Names returns the names of the layouts in Dir.
+func Names() []string {
+	fns, err := filepath.Glob(filepath.Join(Dir(), "*.yml"))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(fns))
+	for _, fn := range fns {
+		names = append(names, strings.TrimSuffix(filepath.Base(fn), ".yml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Files returns the files of the folder skeleton, relative to the store.
+func (l *Layout) Files() []string {
+	files := make([]string, 0, len(l.Folders))
+	for _, f := range l.Folders {
+		files = append(files, path.Join(f, KeepFile))
+	}
+	sort.Strings(files)
+	return files
+}
+
+// checkFolder makes sure f stays inside the store.
+func checkFolder(f string) error {
+	if f == "." {
+		return nil
+	}
+	if f == "" || path.IsAbs(f) || path.Clean(f) != strings.TrimSuffix(f, "/") || strings.HasPrefix(path.Clean(f), "..") {
+		return fmt.Errorf("invalid folder %q", f)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	l, err := Parse([]byte(`recipients:
+  - 0xDEADBEEF
+folders:
+  - prod
+  - staging/
+templates:
+  .: "{{ .Content }}"
+breakglass:
+  - prod
+options:
+  hiddenrecipients: "true"
+githooks: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0xDEADBEEF"}, l.Recipients)
+	assert.Equal(t, []string{"prod/" + KeepFile, "staging/" + KeepFile}, l.Files())
+	assert.Equal(t, "{{ .Content }}", l.Templates["."])
+	assert.Equal(t, []string{"prod"}, l.BreakGlass)
+	assert.Equal(t, "true", l.Options["hiddenrecipients"])
+	assert.True(t, l.GitHooks)
+
+	for _, in := range []string{
+		"folders: [../escape]",
+		"folders: [/abs]",
+		"folders: [a/../../b]",
+		"templates: {../x: foo}",
+		"breakglass: [../x]",
+		"unknown: true",
+	} {
+		_, err := Parse([]byte(in))
+		assert.Error(t, err, in)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	td := t.TempDir()
+	t.Setenv("GOPASS_CONFIG", filepath.Join(td, "config.yml"))
+
+	require.NoError(t, os.MkdirAll(Dir(), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(Dir(), "team-default.yml"), []byte("folders: [prod]\n"), 0o600))
+	assert.Equal(t, []string{"team-default"}, Names())
+
+	l, err := Load("team-default")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod"}, l.Folders)
+
+	l, err = Load(filepath.Join(Dir(), "team-default.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod"}, l.Folders)
+
+	_, err = Load("nope")
+	assert.Error(t, err)
+}
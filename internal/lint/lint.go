@@ -0,0 +1,210 @@
+// Package lint checks the names and content of secrets against the naming
+// conventions of a team. The conventions are stored in the store itself (see
+// PolicyFile), so every member of the team checks against the same policy.
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyFile is the name of the policy file at the top level of a store.
+const PolicyFile = ".gopass-lint.yml"
+
+// Names of the rules.
+const (
+	RuleCase      = "case"
+	RuleSpaces    = "spaces"
+	RuleDuplicate = "duplicate"
+	RuleFolder    = "folder"
+	RuleUsername  = "username"
+)
+
+// UsernameKeys are the keys of a secret that contain its username.
+var UsernameKeys = []string{"username", "user", "login", "email"}
+
+// Policy contains the naming conventions of a store.
+type Policy struct {
+	// Folders are the approved top level folders. Secrets outside of them
+	// are reported. Any folder is approved if empty.
+	Folders []string `yaml:"folders,omitempty"`
+	// Usernames are the folders whose secrets must contain a username. Use
+	// "." for all secrets of the store.
+	Usernames []string `yaml:"usernames,omitempty"`
+	// MixedCase allows upper case letters in names. By default names must be
+	// lower case.
+	MixedCase bool `yaml:"mixedcase,omitempty"`
+}
+
+// Problem is a violation of the policy.
+type Problem struct {
+	Name    string
+	Rule    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s (%s)", p.Name, p.Message, p.Rule)
+}
+
+// Parse decodes and validates a policy.
+func Parse(buf []byte) (*Policy, error) {
+	p := &Policy{}
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(p); err != nil {
+		return nil, err
+	}
+
+	for _, f := range append(append([]string{}, p.Folders...), p.Usernames...) {
+		if f == "" || path.IsAbs(f) || strings.HasPrefix(path.Clean(f), "..") {
+			return nil, fmt.Errorf("invalid folder %q", f)
+		}
+	}
+	return p, nil
+}
+
+// Names checks the names of all secrets of a store. The names must be
+// relative to the store.
+func (p *Policy) Names(names []string) []Problem {
+	var problems []Problem
+	for _, name := range names {
+		problems = append(problems, p.name(name)...)
+	}
+	problems = append(problems, duplicates(names)...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Name < problems[j].Name
+	})
+	return problems
+}
+
+func (p *Policy) name(name string) []Problem {
+	var problems []Problem
+
+	if !p.MixedCase && strings.ToLower(name) != name {
+		problems = append(problems, Problem{Name: name, Rule: RuleCase, Message: "contains upper case letters"})
+	}
+	for _, seg := range strings.Split(name, "/") {
+		if strings.IndexFunc(seg, unicode.IsSpace) >= 0 {
+			problems = append(problems, Problem{Name: name, Rule: RuleSpaces, Message: fmt.Sprintf("%q contains spaces", seg)})
+			break
+		}
+	}
+
+	if len(p.Folders) > 0 && !inFolders(name, p.Folders) {
+		problems = append(problems, Problem{Name: name, Rule: RuleFolder, Message: fmt.Sprintf("is outside of the approved folders %s", strings.Join(p.Folders, ", "))})
+	}
+
+	return problems
+}
+
+// NeedsUsername returns true if the secret must contain a username.
+func (p *Policy) NeedsUsername(name string) bool {
+	return inFolders(name, p.Usernames)
+}
+
+// HasUsername returns true if the secret has one of the UsernameKeys or
+// follows the convention to name secrets after the username below a folder
+// named after the domain, e.g. websites/github.com/jane.
+func HasUsername(name string, keys []string) bool {
+	for _, k := range keys {
+		for _, uk := range UsernameKeys {
+			if strings.EqualFold(k, uk) {
+				return true
+			}
+		}
+	}
+	return strings.Contains(path.Base(path.Dir(name)), ".")
+}
+
+func inFolders(name string, folders []string) bool {
+	for _, f := range folders {
+		f = strings.Trim(path.Clean(f), "/")
+		if f == "." || strings.HasPrefix(name, f+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicates reports folders and secrets that are likely the same entry, e.g.
+// github.com and GitHub, in the same folder.
+func duplicates(names []string) []Problem {
+	// parent folder -> normalized name -> names of the entries
+	entries := make(map[string]map[string][]string)
+	for _, name := range names {
+		segs := strings.Split(name, "/")
+		for i, seg := range segs {
+			parent := strings.Join(segs[:i], "/")
+			if entries[parent] == nil {
+				entries[parent] = make(map[string][]string)
+			}
+			key := normalize(seg)
+			if !contains(entries[parent][key], seg) {
+				entries[parent][key] = append(entries[parent][key], seg)
+			}
+		}
+	}
+
+	var problems []Problem
+	for parent, keys := range entries {
+		for _, segs := range keys {
+			if len(segs) < 2 {
+				continue
+			}
+			sort.Strings(segs)
+			for _, seg := range segs[1:] {
+				problems = append(problems, Problem{
+					Name:    path.Join(parent, seg),
+					Rule:    RuleDuplicate,
+					Message: fmt.Sprintf("looks like a duplicate of %s", path.Join(parent, segs[0])),
+				})
+			}
+		}
+	}
+	return problems
+}
+
+// normalize returns the part of a name that identifies the entry, e.g.
+// "github" for "www.GitHub.com" and "git-hub".
+func normalize(seg string) string {
+	seg = strings.TrimPrefix(strings.ToLower(seg), "www.")
+	if i := strings.LastIndex(seg, "."); i > 0 && isTLD(seg[i+1:]) {
+		seg = seg[:i]
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, seg)
+}
+
+// isTLD returns true if s looks like a top level domain.
+func isTLD(s string) bool {
+	if len(s) < 2 || len(s) > 6 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
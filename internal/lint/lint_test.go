@@ -0,0 +1,95 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	p, err := Parse([]byte(`folders:
+  - web
+  - infra/
+usernames:
+  - web
+mixedcase: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "infra/"}, p.Folders)
+	assert.Equal(t, []string{"web"}, p.Usernames)
+	assert.True(t, p.MixedCase)
+
+	for _, in := range []string{
+		"folders: [../escape]",
+		"usernames: [/abs]",
+		"unknown: true",
+	} {
+		_, err := Parse([]byte(in))
+		assert.Error(t, err, in)
+	}
+}
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+
+	p := &Policy{Folders: []string{"web", "infra/"}}
+	problems := p.Names([]string{
+		"web/github.com/jane",
+		"web/GitHub/jane",
+		"web/git-hub",
+		"infra/db root",
+		"infra/db",
+		"misc/foo",
+		"toplevel",
+	})
+
+	rules := make(map[string][]string)
+	for _, pr := range problems {
+		rules[pr.Name] = append(rules[pr.Name], pr.Rule)
+	}
+	assert.Equal(t, map[string][]string{
+		"web/GitHub/jane": {RuleCase},
+		"web/git-hub":     {RuleDuplicate},
+		"web/github.com":  {RuleDuplicate},
+		"infra/db root":   {RuleSpaces},
+		"misc/foo":        {RuleFolder},
+		"toplevel":        {RuleFolder},
+	}, rules)
+
+	// everything is fine without a policy and proper names
+	assert.Empty(t, (&Policy{}).Names([]string{"web/github.com/jane", "web/gitlab.com/jane", "foo"}))
+	assert.Empty(t, (&Policy{MixedCase: true}).Names([]string{"Web/GitHub"}))
+}
+
+func TestUsername(t *testing.T) {
+	t.Parallel()
+
+	p := &Policy{Usernames: []string{"web"}}
+	assert.True(t, p.NeedsUsername("web/foo"))
+	assert.False(t, p.NeedsUsername("infra/foo"))
+	assert.False(t, p.NeedsUsername("web"))
+	assert.True(t, (&Policy{Usernames: []string{"."}}).NeedsUsername("foo"))
+	assert.False(t, (&Policy{}).NeedsUsername("foo"))
+
+	assert.True(t, HasUsername("web/foo", []string{"Login"}))
+	assert.True(t, HasUsername("web/github.com/jane", nil))
+	assert.False(t, HasUsername("web/github", []string{"url"}))
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"github":         "github",
+		"www.GitHub.com": "github",
+		"git-hub":        "github",
+		"git_hub.io":     "github",
+		"mail.google":    "mail",
+		"backup.2023":    "backup2023",
+	} {
+		assert.Equal(t, want, normalize(in), in)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build android
+// +build android
+
+package notify
+
+import (
+	"context"
+	"os"
+	"os/exec"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Notify displays a notification with termux-notification. It's part of the
+// Termux:API add-on and posts the notification through an am broadcast to
+// the Termux:API app.
+func Notify(ctx context.Context, subj, msg string) error {
+	if os.Getenv("GOPASS_NO_NOTIFY") != "" || !ctxutil.IsNotifications(ctx) {
+		debug.Log("Notifications disabled")
+		return nil
+	}
+	bin, err := exec.LookPath("termux-notification")
+	if err != nil {
+		debug.Log("termux-notification not found. Install the Termux:API add-on and the termux-api package: %s", err)
+		return err
+	}
+
+	// reusing the id replaces the previous notification
+	return exec.CommandContext(ctx, bin,
+		"--id", "gopass",
+		"--title", subj,
+		"--content", msg,
+	).Run()
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-//to test cmd.exec correctly we use the same functionality as go itself see exec_test.go
+// to test cmd.exec correctly we use the same functionality as go itself see exec_test.go
 func TestDarwinNotify(t *testing.T) {
 	ctx := context.Background()
 	_ = os.Setenv("GOPASS_NO_NOTIFY", "true")
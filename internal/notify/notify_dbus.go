@@ -1,5 +1,5 @@
-//go:build linux
-// +build linux
+//go:build (linux && !android) || openbsd
+// +build linux,!android openbsd
 
 package notify
 
@@ -1,5 +1,5 @@
-//go:build !linux && !windows && !darwin
-// +build !linux,!windows,!darwin
+//go:build !linux && !windows && !darwin && !openbsd
+// +build !linux,!windows,!darwin,!openbsd
 
 package notify
 
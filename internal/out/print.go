@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/fatih/color"
+	"github.com/gopasspw/gopass/internal/i18n"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -40,13 +41,21 @@ func newline(ctx context.Context) string {
 	return ""
 }
 
+// translate returns the translation of arg if it's a string.
+func translate(ctx context.Context, arg any) any {
+	if s, ok := arg.(string); ok {
+		return i18n.T(ctx, s)
+	}
+	return arg
+}
+
 // Print prints the given string.
 func Print(ctx context.Context, arg any) {
 	if ctxutil.IsHidden(ctx) {
 		return
 	}
 	debug.LogN(1, "%s", arg)
-	fmt.Fprintf(Stdout, Prefix(ctx)+"%s"+newline(ctx), arg)
+	fmt.Fprintf(Stdout, Prefix(ctx)+"%s"+newline(ctx), translate(ctx, arg))
 }
 
 // Printf formats and prints the given string.
@@ -55,7 +64,7 @@ func Printf(ctx context.Context, format string, args ...any) {
 		return
 	}
 	debug.LogN(1, format, args...)
-	fmt.Fprintf(Stdout, Prefix(ctx)+format+newline(ctx), args...)
+	fmt.Fprint(Stdout, Prefix(ctx)+i18n.Sprintf(ctx, format, args...)+newline(ctx))
 }
 
 // Notice prints the string with an exclamation mark.
@@ -64,7 +73,7 @@ func Notice(ctx context.Context, arg any) {
 		return
 	}
 	debug.LogN(1, "NOTICE: %s", arg)
-	fmt.Fprintf(Stdout, Prefix(ctx)+"⚠ %s"+newline(ctx), arg)
+	fmt.Fprintf(Stdout, Prefix(ctx)+"⚠ %s"+newline(ctx), translate(ctx, arg))
 }
 
 // Noticef prints the string with an exclamation mark in front.
@@ -73,7 +82,7 @@ func Noticef(ctx context.Context, format string, args ...any) {
 		return
 	}
 	debug.LogN(1, "NOTICE: "+format, args...)
-	fmt.Fprintf(Stdout, Prefix(ctx)+"⚠ "+format+newline(ctx), args...)
+	fmt.Fprint(Stdout, Prefix(ctx)+"⚠ "+i18n.Sprintf(ctx, format, args...)+newline(ctx))
 }
 
 // Error prints the string with a red cross in front.
@@ -82,7 +91,7 @@ func Error(ctx context.Context, arg any) {
 		return
 	}
 	debug.LogN(1, "ERROR: %s", arg)
-	fmt.Fprint(Stderr, color.RedString(Prefix(ctx)+"❌ %s"+newline(ctx), arg))
+	fmt.Fprint(Stderr, color.RedString(Prefix(ctx)+"❌ %s"+newline(ctx), translate(ctx, arg)))
 }
 
 // Errorf prints the string in red to stderr.
@@ -91,7 +100,7 @@ func Errorf(ctx context.Context, format string, args ...any) {
 		return
 	}
 	debug.LogN(1, "ERROR: "+format, args...)
-	fmt.Fprint(Stderr, color.RedString(Prefix(ctx)+"❌ "+format+newline(ctx), args...))
+	fmt.Fprint(Stderr, color.RedString("%s", Prefix(ctx)+"❌ "+i18n.Sprintf(ctx, format, args...)+newline(ctx)))
 }
 
 // OK prints the string with a green checkmark in front.
@@ -100,7 +109,7 @@ func OK(ctx context.Context, arg any) {
 		return
 	}
 	debug.LogN(1, "OK: %s", arg)
-	fmt.Fprintf(Stdout, Prefix(ctx)+"✅ %s"+newline(ctx), arg)
+	fmt.Fprintf(Stdout, Prefix(ctx)+"✅ %s"+newline(ctx), translate(ctx, arg))
 }
 
 // OKf prints the string in with an OK checkmark in front.
@@ -109,7 +118,7 @@ func OKf(ctx context.Context, format string, args ...any) {
 		return
 	}
 	debug.LogN(1, "OK: "+format, args...)
-	fmt.Fprintf(Stdout, Prefix(ctx)+"✅ "+format+newline(ctx), args...)
+	fmt.Fprint(Stdout, Prefix(ctx)+"✅ "+i18n.Sprintf(ctx, format, args...)+newline(ctx))
 }
 
 // Warning prints the string with a warning sign in front.
@@ -118,7 +127,7 @@ func Warning(ctx context.Context, arg any) {
 		return
 	}
 	debug.LogN(1, "WARNING: %s", arg)
-	fmt.Fprint(Stderr, color.YellowString(Prefix(ctx)+"⚠ %s"+newline(ctx), arg))
+	fmt.Fprint(Stderr, color.YellowString(Prefix(ctx)+"⚠ %s"+newline(ctx), translate(ctx, arg)))
 }
 
 // Warningf prints the string in yellow to stderr and prepends a warning sign.
@@ -127,5 +136,5 @@ func Warningf(ctx context.Context, format string, args ...any) {
 		return
 	}
 	debug.LogN(1, "WARNING: "+format, args...)
-	fmt.Fprint(Stderr, color.YellowString(Prefix(ctx)+"⚠ "+format+newline(ctx), args...))
+	fmt.Fprint(Stderr, color.YellowString("%s", Prefix(ctx)+"⚠ "+i18n.Sprintf(ctx, format, args...)+newline(ctx)))
 }
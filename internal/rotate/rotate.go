@@ -0,0 +1,113 @@
+// Package rotate runs rotation plugins that change a credential at the
+// provider, e.g. a database or an API.
+//
+// A rotator is an executable named gopass-rotate-<name> in the PATH. The
+// name comes from the (shared) secret, so it can only select a rotator and
+// never a path or arguments. The rotator receives a JSON encoded Request on
+// stdin. On success it must exit with
+// status zero. It may print a JSON encoded Response to stdout, e.g. if the
+// provider generated the credential itself.
+package rotate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Prefix is prepended to the rotator name to find the executable.
+const Prefix = "gopass-rotate-"
+
+// Request is passed to the rotator.
+type Request struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+	// Password is the current password.
+	Password string `json:"password"`
+	// NewPassword is the password generated by gopass.
+	NewPassword string `json:"new_password"`
+	// Values contains the other key-value pairs of the secret.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// Response can be returned by the rotator. Empty fields are ignored.
+type Response struct {
+	// Password replaces the password generated by gopass.
+	Password string `json:"password,omitempty"`
+	// Values are set on the secret, e.g. a new key id.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// Run invokes the rotator with the given name, e.g. "postgres". Any output on
+// stderr is passed through.
+func Run(ctx context.Context, name string, req Request) (Response, error) {
+	bin, err := lookPath(name)
+	if err != nil {
+		return Response{}, err
+	}
+
+	in, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = buf
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "GOPASS_SECRET="+req.Name)
+
+	debug.Log("running rotator %s for %s", bin, req.Name)
+	if err := cmd.Run(); err != nil {
+		return Response{}, fmt.Errorf("rotator %s failed: %w", name, err)
+	}
+
+	var resp Response
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return resp, nil
+	}
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		return Response{}, fmt.Errorf("failed to decode response of rotator %s: %w", name, err)
+	}
+
+	return resp, nil
+}
+
+// lookPath finds the executable of a rotator in the PATH. Only plain names
+// are accepted, anything that looks like a path or contains arguments is
+// rejected.
+func lookPath(name string) (string, error) {
+	if !validName(name) {
+		return "", fmt.Errorf("invalid rotator %q. Only letters, digits, '-' and '_' are allowed", name)
+	}
+
+	bin, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return "", fmt.Errorf("rotator %s not found. Install %s%s in your PATH", name, Prefix, name)
+	}
+
+	return bin, nil
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z':
+		case c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9':
+		case c == '-' || c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+//go:build !windows
+// +build !windows
+
+package rotate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRotator(t *testing.T, dir, name, script string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, Prefix+name), []byte("#!/bin/sh\n"+script), 0o700))
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx := context.Background()
+	req := Request{
+		Name:        "db/prod",
+		Password:    "old",
+		NewPassword: "new",
+		Values:      map[string]string{"user": "admin"},
+	}
+
+	t.Run("empty response", func(t *testing.T) {
+		writeRotator(t, dir, "silent", "cat > "+filepath.Join(dir, "req")+"\n")
+
+		resp, err := Run(ctx, "silent", req)
+		require.NoError(t, err)
+		assert.Equal(t, Response{}, resp)
+
+		buf, err := os.ReadFile(filepath.Join(dir, "req"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"db/prod","password":"old","new_password":"new","values":{"user":"admin"}}`, string(buf))
+	})
+
+	t.Run("response", func(t *testing.T) {
+		writeRotator(t, dir, "api", `echo "{\"password\":\"from provider\",\"values\":{\"key-id\":\"$GOPASS_SECRET\"}}"`+"\n")
+
+		resp, err := Run(ctx, "api", req)
+		require.NoError(t, err)
+		assert.Equal(t, "from provider", resp.Password)
+		assert.Equal(t, map[string]string{"key-id": "db/prod"}, resp.Values)
+	})
+
+	t.Run("paths and arguments", func(t *testing.T) {
+		writeRotator(t, dir, "evil", "touch "+filepath.Join(dir, "pwned")+"\n")
+
+		for _, spec := range []string{
+			filepath.Join(dir, Prefix+"evil"),
+			"../" + Prefix + "evil",
+			"evil --flag",
+			"/bin/sh -c 'touch " + filepath.Join(dir, "pwned") + "'",
+		} {
+			_, err := Run(ctx, spec, req)
+			assert.Error(t, err, spec)
+		}
+		assert.NoFileExists(t, filepath.Join(dir, "pwned"))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		writeRotator(t, dir, "broken", "exit 3\n")
+
+		_, err := Run(ctx, "broken", req)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		writeRotator(t, dir, "chatty", "echo done\n")
+
+		_, err := Run(ctx, "chatty", req)
+		assert.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := Run(ctx, "missing", req)
+		assert.Error(t, err)
+
+		_, err = Run(ctx, "", req)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,354 @@
+// Package rpc implements a JSON-RPC 2.0 server on a pair of streams. It's
+// meant for editor plugins that browse and insert secrets without invoking
+// gopass for every request.
+//
+// Messages are newline delimited JSON objects. Supported methods are list,
+// get and set. Changes to the store are announced with changed
+// notifications.
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/tree"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	ErrParse          = -32700
+	ErrInvalidRequest = -32600
+	ErrMethodNotFound = -32601
+	ErrInvalidParams  = -32602
+	ErrServer         = -32000
+)
+
+// Store is the subset of the root store used by the server.
+type Store interface {
+	List(ctx context.Context, maxDepth int) ([]string, error)
+	Exists(ctx context.Context, name string) bool
+	Get(ctx context.Context, name string) (gopass.Secret, error)
+	Set(ctx context.Context, name string, sec gopass.Byter) error
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// Change is the payload of a changed notification.
+type Change struct {
+	Name string `json:"name"`
+	// Op is one of added, removed or updated.
+	Op string `json:"op"`
+}
+
+// Secret is the result of a get request without a key.
+type Secret struct {
+	Password string            `json:"password"`
+	Values   map[string]string `json:"values,omitempty"`
+	Body     string            `json:"body,omitempty"`
+}
+
+// Server serves requests for a store.
+type Server struct {
+	store Store
+	// Poll is the interval in which the store is checked for added or
+	// removed secrets. Polling is disabled if it's not positive.
+	Poll time.Duration
+
+	mu    sync.Mutex
+	enc   *json.Encoder
+	known map[string]bool
+}
+
+// New creates a new server.
+func New(store Store) *Server {
+	return &Server{
+		store: store,
+		Poll:  5 * time.Second,
+	}
+}
+
+// Serve handles requests read from r and writes responses and notifications
+// to w until r is closed or the context is canceled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.enc = json.NewEncoder(w)
+
+	names, err := s.store.List(ctx, tree.INF)
+	if err != nil {
+		return fmt.Errorf("failed to list store: %w", err)
+	}
+	s.known = toSet(names)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if s.Poll > 0 {
+		go s.watch(ctx)
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			s.send(response{ID: json.RawMessage("null"), Error: &Error{Code: ErrParse, Message: err.Error()}})
+			continue
+		}
+
+		result, rerr := s.handle(ctx, req)
+		if req.ID == nil {
+			// notifications don't get a response
+			continue
+		}
+		s.send(response{ID: req.ID, Result: result, Error: rerr})
+	}
+
+	return sc.Err()
+}
+
+func (s *Server) handle(ctx context.Context, req request) (any, *Error) {
+	debug.Log("rpc request: %s", req.Method)
+
+	if req.JSONRPC != "2.0" {
+		return nil, &Error{Code: ErrInvalidRequest, Message: "jsonrpc must be 2.0"}
+	}
+
+	switch req.Method {
+	case "list":
+		return s.list(ctx, req.Params)
+	case "get":
+		return s.get(ctx, req.Params)
+	case "set":
+		return s.set(ctx, req.Params)
+	default:
+		return nil, &Error{Code: ErrMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
+
+func (s *Server) list(ctx context.Context, raw json.RawMessage) (any, *Error) {
+	var params struct {
+		Prefix string `json:"prefix"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	names, err := s.store.List(ctx, tree.INF)
+	if err != nil {
+		return nil, &Error{Code: ErrServer, Message: err.Error()}
+	}
+
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, params.Prefix) {
+			res = append(res, name)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *Server) get(ctx context.Context, raw json.RawMessage) (any, *Error) {
+	var params struct {
+		Name string `json:"name"`
+		Key  string `json:"key"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if params.Name == "" {
+		return nil, &Error{Code: ErrInvalidParams, Message: "name is required"}
+	}
+
+	sec, err := s.store.Get(ctx, params.Name)
+	if err != nil {
+		return nil, &Error{Code: ErrServer, Message: err.Error()}
+	}
+
+	if params.Key != "" {
+		v, found := sec.Get(params.Key)
+		if !found {
+			return nil, &Error{Code: ErrServer, Message: fmt.Sprintf("key %q not found", params.Key)}
+		}
+		return v, nil
+	}
+
+	res := Secret{
+		Password: sec.Password(),
+		Body:     sec.Body(),
+	}
+	if keys := sec.Keys(); len(keys) > 0 {
+		res.Values = make(map[string]string, len(keys))
+		for _, k := range keys {
+			res.Values[k], _ = sec.Get(k)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *Server) set(ctx context.Context, raw json.RawMessage) (any, *Error) {
+	var params struct {
+		Name     string  `json:"name"`
+		Password *string `json:"password"`
+		Key      string  `json:"key"`
+		Value    *string `json:"value"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if params.Name == "" {
+		return nil, &Error{Code: ErrInvalidParams, Message: "name is required"}
+	}
+	if params.Password == nil && (params.Key == "" || params.Value == nil) {
+		return nil, &Error{Code: ErrInvalidParams, Message: "password or key and value are required"}
+	}
+
+	op := "added"
+	var sec gopass.Secret = secrets.New()
+	if s.store.Exists(ctx, params.Name) {
+		var err error
+		sec, err = s.store.Get(ctx, params.Name)
+		if err != nil {
+			return nil, &Error{Code: ErrServer, Message: err.Error()}
+		}
+		op = "updated"
+	}
+
+	if params.Password != nil {
+		sec.SetPassword(*params.Password)
+	}
+	if params.Key != "" && params.Value != nil {
+		if err := sec.Set(params.Key, *params.Value); err != nil {
+			return nil, &Error{Code: ErrServer, Message: err.Error()}
+		}
+	}
+
+	if err := s.store.Set(ctx, params.Name, sec); err != nil {
+		return nil, &Error{Code: ErrServer, Message: err.Error()}
+	}
+
+	s.mu.Lock()
+	s.known[params.Name] = true
+	s.mu.Unlock()
+	s.notify(Change{Name: params.Name, Op: op})
+
+	return true, nil
+}
+
+// watch periodically checks the store for secrets added or removed by other
+// processes, e.g. a git pull.
+func (s *Server) watch(ctx context.Context) {
+	ticker := time.NewTicker(s.Poll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkChanges(ctx)
+		}
+	}
+}
+
+func (s *Server) checkChanges(ctx context.Context) {
+	names, err := s.store.List(ctx, tree.INF)
+	if err != nil {
+		debug.Log("failed to list store: %s", err)
+		return
+	}
+	current := toSet(names)
+
+	s.mu.Lock()
+	var changes []Change
+	for name := range current {
+		if !s.known[name] {
+			changes = append(changes, Change{Name: name, Op: "added"})
+		}
+	}
+	for name := range s.known {
+		if !current[name] {
+			changes = append(changes, Change{Name: name, Op: "removed"})
+		}
+	}
+	s.known = current
+	s.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	for _, c := range changes {
+		s.notify(c)
+	}
+}
+
+func (s *Server) notify(c Change) {
+	s.send(response{Method: "changed", Params: c})
+}
+
+func (s *Server) send(resp response) {
+	resp.JSONRPC = "2.0"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.enc.Encode(resp); err != nil {
+		debug.Log("failed to write response: %s", err)
+	}
+}
+
+func decodeParams(raw json.RawMessage, v any) *Error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &Error{Code: ErrInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func toSet(names []string) map[string]bool {
+	m := make(map[string]bool, len(names))
+	for _, n := range names {
+		m[n] = true
+	}
+
+	return m
+}
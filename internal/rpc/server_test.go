@@ -0,0 +1,186 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets/secparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memStore struct {
+	sync.Mutex
+	data map[string][]byte
+}
+
+func (m *memStore) List(context.Context, int) ([]string, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	names := make([]string, 0, len(m.data))
+	for k := range m.data {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (m *memStore) Exists(_ context.Context, name string) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	_, found := m.data[name]
+	return found
+}
+
+func (m *memStore) Get(_ context.Context, name string) (gopass.Secret, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	buf, found := m.data[name]
+	if !found {
+		return nil, fmt.Errorf("not found")
+	}
+	return secparse.Parse(buf)
+}
+
+func (m *memStore) Set(_ context.Context, name string, sec gopass.Byter) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.data[name] = sec.Bytes()
+	return nil
+}
+
+func serve(t *testing.T, s *Server, reqs ...string) []map[string]any {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	require.NoError(t, s.Serve(context.Background(), strings.NewReader(strings.Join(reqs, "\n")), out))
+
+	var resps []map[string]any
+	dec := json.NewDecoder(out)
+	for dec.More() {
+		var r map[string]any
+		require.NoError(t, dec.Decode(&r))
+		resps = append(resps, r)
+	}
+	return resps
+}
+
+func newTestServer() (*Server, *memStore) {
+	ms := &memStore{data: map[string][]byte{
+		"web/example.org": secrets.NewKVWithData("s3cret", map[string][]string{"user": {"alice"}}, "notes", false).Bytes(),
+		"db/prod":         secrets.NewKVWithData("dbpw", nil, "", false).Bytes(),
+	}}
+	s := New(ms)
+	s.Poll = 0
+
+	return s, ms
+}
+
+func TestList(t *testing.T) {
+	s, _ := newTestServer()
+
+	resps := serve(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"list","params":{"prefix":"web/"}}`,
+	)
+	require.Len(t, resps, 2)
+	assert.Equal(t, float64(1), resps[0]["id"])
+	assert.Equal(t, []any{"db/prod", "web/example.org"}, resps[0]["result"])
+	assert.Equal(t, []any{"web/example.org"}, resps[1]["result"])
+}
+
+func TestGet(t *testing.T) {
+	s, _ := newTestServer()
+
+	resps := serve(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"get","params":{"name":"web/example.org"}}`,
+		`{"jsonrpc":"2.0","id":"two","method":"get","params":{"name":"web/example.org","key":"user"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"get","params":{"name":"nope"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"get","params":{}}`,
+	)
+	require.Len(t, resps, 4)
+	assert.Equal(t, map[string]any{
+		"password": "s3cret",
+		"values":   map[string]any{"user": "alice"},
+		"body":     "notes",
+	}, resps[0]["result"])
+	assert.Equal(t, "two", resps[1]["id"])
+	assert.Equal(t, "alice", resps[1]["result"])
+	assert.Equal(t, float64(ErrServer), resps[2]["error"].(map[string]any)["code"])
+	assert.Equal(t, float64(ErrInvalidParams), resps[3]["error"].(map[string]any)["code"])
+}
+
+func TestSet(t *testing.T) {
+	s, ms := newTestServer()
+
+	resps := serve(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"set","params":{"name":"web/new","password":"pw"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"set","params":{"name":"web/example.org","key":"user","value":"bob"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"set","params":{"name":"web/new"}}`,
+	)
+	require.Len(t, resps, 5)
+
+	assert.Equal(t, "changed", resps[0]["method"])
+	assert.Equal(t, map[string]any{"name": "web/new", "op": "added"}, resps[0]["params"])
+	assert.Equal(t, true, resps[1]["result"])
+	assert.Equal(t, map[string]any{"name": "web/example.org", "op": "updated"}, resps[2]["params"])
+	assert.Equal(t, true, resps[3]["result"])
+	assert.Equal(t, float64(ErrInvalidParams), resps[4]["error"].(map[string]any)["code"])
+
+	sec, err := ms.Get(context.Background(), "web/example.org")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", sec.Password())
+	v, _ := sec.Get("user")
+	assert.Equal(t, "bob", v)
+
+	sec, err = ms.Get(context.Background(), "web/new")
+	require.NoError(t, err)
+	assert.Equal(t, "pw", sec.Password())
+}
+
+func TestInvalidRequests(t *testing.T) {
+	s, _ := newTestServer()
+
+	resps := serve(t, s,
+		`not json`,
+		`{"jsonrpc":"1.0","id":1,"method":"list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"delete"}`,
+		`{"jsonrpc":"2.0","method":"list"}`,
+	)
+	require.Len(t, resps, 3)
+	assert.Nil(t, resps[0]["id"])
+	assert.Equal(t, float64(ErrParse), resps[0]["error"].(map[string]any)["code"])
+	assert.Equal(t, float64(ErrInvalidRequest), resps[1]["error"].(map[string]any)["code"])
+	assert.Equal(t, float64(ErrMethodNotFound), resps[2]["error"].(map[string]any)["code"])
+}
+
+func TestCheckChanges(t *testing.T) {
+	s, ms := newTestServer()
+	ctx := context.Background()
+
+	out := &bytes.Buffer{}
+	require.NoError(t, s.Serve(ctx, strings.NewReader(""), out))
+
+	ms.data["web/added"] = []byte("foo")
+	delete(ms.data, "db/prod")
+	s.checkChanges(ctx)
+
+	assert.Equal(t, `{"jsonrpc":"2.0","method":"changed","params":{"name":"db/prod","op":"removed"}}`+"\n"+
+		`{"jsonrpc":"2.0","method":"changed","params":{"name":"web/added","op":"added"}}`+"\n", out.String())
+
+	out.Reset()
+	s.checkChanges(ctx)
+	assert.Equal(t, "", out.String())
+}
@@ -0,0 +1,210 @@
+// Package scan finds stored secrets in files and diffs. The values are only
+// kept as keyed hashes, so the index can be built before scanning and no
+// plaintext is compared or printed.
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MinLength is the length of the shortest value that is looked for. Shorter
+// values would match all over the place.
+const MinLength = 6
+
+// Finding is a line containing a stored secret.
+type Finding struct {
+	File   string
+	Line   int
+	Secret string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d contains %s", f.File, f.Line, f.Secret)
+}
+
+// Index contains the hashes of the values to look for. It must not be used
+// concurrently.
+type Index struct {
+	mac     hash.Hash
+	hashes  map[[sha256.Size]byte]string
+	lengths map[int]bool
+}
+
+// New returns an empty index.
+func New() (*Index, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to create hash key: %w", err)
+	}
+	return &Index{
+		mac:     hmac.New(sha256.New, key),
+		hashes:  map[[sha256.Size]byte]string{},
+		lengths: map[int]bool{},
+	}, nil
+}
+
+// Add adds the value of the named secret to the index. It returns false if
+// the value is too short to be looked for.
+func (ix *Index) Add(name, value string) bool {
+	value = strings.TrimSpace(value)
+	if len(value) < MinLength || strings.ContainsRune(value, '\n') {
+		return false
+	}
+	ix.hashes[ix.hash([]byte(value))] = name
+	ix.lengths[len(value)] = true
+	return true
+}
+
+// Len returns the number of values in the index.
+func (ix *Index) Len() int {
+	return len(ix.hashes)
+}
+
+func (ix *Index) hash(buf []byte) [sha256.Size]byte {
+	ix.mac.Reset()
+	_, _ = ix.mac.Write(buf)
+	var sum [sha256.Size]byte
+	ix.mac.Sum(sum[:0])
+	return sum
+}
+
+// Line returns the secrets contained in line, sorted by name.
+func (ix *Index) Line(line string) []string {
+	found := map[string]bool{}
+	buf := []byte(line)
+	for l := range ix.lengths {
+		for i := 0; i+l <= len(buf); i++ {
+			if name, ok := ix.hashes[ix.hash(buf[i:i+l])]; ok {
+				found[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Reader scans every line read from r. fn is used as the file name of the
+// findings.
+func (ix *Index) Reader(r io.Reader, fn string) ([]Finding, error) {
+	var findings []Finding
+	rd := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := rd.ReadString('\n')
+		for _, name := range ix.Line(line) {
+			findings = append(findings, Finding{File: fn, Line: n, Secret: name})
+		}
+		if err == io.EOF {
+			return findings, nil
+		}
+		if err != nil {
+			return findings, err
+		}
+	}
+}
+
+// Diff scans the added lines of a unified diff, e.g. the output of git diff.
+// The findings refer to the new version of the files.
+func (ix *Index) Diff(r io.Reader) ([]Finding, error) {
+	var findings []Finding
+	var fn string
+	var n int
+	rd := bufio.NewReader(r)
+	for {
+		line, err := rd.ReadString('\n')
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			fn = strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(line, "+++ ")), "b/")
+		case strings.HasPrefix(line, "@@ "):
+			n = hunkStart(line)
+		case strings.HasPrefix(line, "+"):
+			for _, name := range ix.Line(line[1:]) {
+				findings = append(findings, Finding{File: fn, Line: n, Secret: name})
+			}
+			n++
+		case strings.HasPrefix(line, " "):
+			n++
+		}
+		if err == io.EOF {
+			return findings, nil
+		}
+		if err != nil {
+			return findings, err
+		}
+	}
+}
+
+// hunkStart returns the first line of the new file in a hunk header like
+// "@@ -1,3 +4,5 @@".
+func hunkStart(line string) int {
+	for _, f := range strings.Fields(line) {
+		if !strings.HasPrefix(f, "+") {
+			continue
+		}
+		start, _, _ := strings.Cut(f[1:], ",")
+		n, err := strconv.Atoi(start)
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+	return 0
+}
+
+// Path scans the file or all files below the directory p. Git directories
+// and binary files are skipped.
+func (ix *Index) Path(p string) ([]Finding, error) {
+	var findings []Finding
+	err := filepath.WalkDir(p, func(fn string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		f, err := ix.file(fn)
+		findings = append(findings, f...)
+		return err
+	})
+	return findings, err
+}
+
+func (ix *Index) file(fn string) ([]Finding, error) {
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = fh.Close()
+	}()
+
+	rd := bufio.NewReader(fh)
+	head, _ := rd.Peek(8000)
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+	return ix.Reader(rd, fn)
+}
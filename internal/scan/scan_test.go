@@ -0,0 +1,87 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex(t *testing.T) {
+	ix, err := New()
+	require.NoError(t, err)
+
+	assert.True(t, ix.Add("db/prod", "s3cr3t-pw"))
+	assert.True(t, ix.Add("api/token", " abcdef123456\n"))
+	assert.False(t, ix.Add("short", "12345"))
+	assert.False(t, ix.Add("multi", "line1\nline2"))
+	assert.Equal(t, 2, ix.Len())
+
+	assert.Equal(t, []string{"db/prod"}, ix.Line(`password = "s3cr3t-pw"`))
+	assert.Equal(t, []string{"api/token", "db/prod"}, ix.Line("s3cr3t-pw abcdef123456"))
+	assert.Empty(t, ix.Line("s3cr3t-p"))
+	assert.Empty(t, ix.Line(""))
+}
+
+func TestReader(t *testing.T) {
+	ix, err := New()
+	require.NoError(t, err)
+	ix.Add("db/prod", "s3cr3t-pw")
+
+	f, err := ix.Reader(strings.NewReader("foo\nbar\nDB_PASS=s3cr3t-pw"), "config.env")
+	require.NoError(t, err)
+	assert.Equal(t, []Finding{{File: "config.env", Line: 3, Secret: "db/prod"}}, f)
+	assert.Equal(t, "config.env:3 contains db/prod", f[0].String())
+}
+
+func TestDiff(t *testing.T) {
+	ix, err := New()
+	require.NoError(t, err)
+	ix.Add("db/prod", "s3cr3t-pw")
+
+	diff := `diff --git a/app.yml b/app.yml
+index 1111111..2222222 100644
+--- a/app.yml
++++ b/app.yml
+@@ -10,3 +10,4 @@ db:
+   host: localhost
+-  password: s3cr3t-pw
++  password: ${DB_PASS}
++  fallback: s3cr3t-pw
+   port: 5432
+diff --git a/new.txt b/new.txt
+new file mode 100644
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++s3cr3t-pw
+`
+	f, err := ix.Diff(strings.NewReader(diff))
+	require.NoError(t, err)
+	assert.Equal(t, []Finding{
+		{File: "app.yml", Line: 12, Secret: "db/prod"},
+		{File: "new.txt", Line: 1, Secret: "db/prod"},
+	}, f)
+}
+
+func TestPath(t *testing.T) {
+	td := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(td, "src", ".git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "src", "main.go"), []byte("const pw = \"s3cr3t-pw\"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "src", ".git", "COMMIT_EDITMSG"), []byte("s3cr3t-pw\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(td, "src", "blob.bin"), []byte("\x00s3cr3t-pw"), 0o644))
+
+	ix, err := New()
+	require.NoError(t, err)
+	ix.Add("db/prod", "s3cr3t-pw")
+
+	f, err := ix.Path(td)
+	require.NoError(t, err)
+	assert.Equal(t, []Finding{{File: filepath.Join(td, "src", "main.go"), Line: 1, Secret: "db/prod"}}, f)
+
+	_, err = ix.Path(filepath.Join(td, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+// Package shamir implements Shamir's Secret Sharing over GF(2^8).
+//
+// Every byte of the secret is shared with its own random polynomial. A share
+// contains the value of each polynomial at the x coordinate of the share,
+// followed by the x coordinate itself.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidShares is returned if the shares can't be combined.
+var ErrInvalidShares = errors.New("invalid shares")
+
+// Split divides the secret into n shares. Any threshold of them are needed to
+// recover the secret.
+func Split(secret []byte, n, threshold int) ([][]byte, error) {
+	if len(secret) < 1 {
+		return nil, fmt.Errorf("can not split an empty secret")
+	}
+	if threshold < 2 || threshold > n {
+		return nil, fmt.Errorf("threshold must be between 2 and %d", n)
+	}
+	if n > 255 {
+		return nil, fmt.Errorf("can not create more than 255 shares")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+
+	coeffs := make([]byte, threshold)
+	for j, b := range secret {
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, fmt.Errorf("failed to read random coefficients: %w", err)
+		}
+		coeffs[0] = b
+
+		for i := range shares {
+			shares[i][j] = evaluate(coeffs, byte(i+1))
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine recovers the secret from at least threshold shares. Passing fewer
+// shares doesn't fail but returns garbage.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("need at least two shares: %w", ErrInvalidShares)
+	}
+
+	l := len(shares[0])
+	if l < 2 {
+		return nil, fmt.Errorf("share too short: %w", ErrInvalidShares)
+	}
+
+	xs := make([]byte, len(shares))
+	seen := make(map[byte]bool, len(shares))
+	for i, s := range shares {
+		if len(s) != l {
+			return nil, fmt.Errorf("shares differ in length: %w", ErrInvalidShares)
+		}
+		x := s[l-1]
+		if x == 0 || seen[x] {
+			return nil, fmt.Errorf("duplicate or invalid share: %w", ErrInvalidShares)
+		}
+		seen[x] = true
+		xs[i] = x
+	}
+
+	secret := make([]byte, l-1)
+	ys := make([]byte, len(shares))
+	for j := range secret {
+		for i, s := range shares {
+			ys[i] = s[j]
+		}
+		secret[j] = interpolate(xs, ys)
+	}
+
+	return secret, nil
+}
+
+// evaluate returns the value of the polynomial at x using Horner's method.
+func evaluate(coeffs []byte, x byte) byte {
+	var y byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coeffs[i]
+	}
+
+	return y
+}
+
+// interpolate returns the value of the Lagrange polynomial at zero.
+func interpolate(xs, ys []byte) byte {
+	var res byte
+	for i := range xs {
+		basis := byte(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			// x_j / (x_j - x_i), subtraction is xor in GF(2^8)
+			basis = mul(basis, div(xs[j], xs[i]^xs[j]))
+		}
+		res ^= mul(ys[i], basis)
+	}
+
+	return res
+}
+
+var (
+	expTable [510]byte
+	logTable [256]byte
+)
+
+func init() {
+	// 3 generates the multiplicative group of GF(2^8) with the AES polynomial.
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		expTable[i+255] = x
+		logTable[x] = byte(i)
+		x ^= xtime(x)
+	}
+}
+
+// xtime multiplies by 2 modulo x^8 + x^4 + x^3 + x + 1.
+func xtime(b byte) byte {
+	if b&0x80 != 0 {
+		return b<<1 ^ 0x1b
+	}
+
+	return b << 1
+}
+
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+func div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+
+	return expTable[int(logTable[a])+255-int(logTable[b])]
+}
@@ -0,0 +1,68 @@
+package shamir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+
+	shares, err := Split(secret, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	for _, tc := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		parts := make([][]byte, 0, len(tc))
+		for _, i := range tc {
+			parts = append(parts, shares[i])
+		}
+		got, err := Combine(parts)
+		require.NoError(t, err)
+		assert.Equal(t, secret, got, "%v", tc)
+	}
+
+	got, err := Combine(shares[:2])
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, got)
+}
+
+func TestSplitInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		n, threshold int
+	}{
+		{3, 1},
+		{3, 4},
+		{256, 2},
+	} {
+		_, err := Split([]byte("foo"), tc.n, tc.threshold)
+		assert.Error(t, err, "%+v", tc)
+	}
+
+	_, err := Split(nil, 3, 2)
+	assert.Error(t, err)
+}
+
+func TestCombineInvalid(t *testing.T) {
+	shares, err := Split([]byte("foo"), 3, 2)
+	require.NoError(t, err)
+
+	_, err = Combine(shares[:1])
+	assert.ErrorIs(t, err, ErrInvalidShares)
+
+	_, err = Combine([][]byte{shares[0], shares[0]})
+	assert.ErrorIs(t, err, ErrInvalidShares)
+
+	_, err = Combine([][]byte{shares[0], shares[1][:2]})
+	assert.ErrorIs(t, err, ErrInvalidShares)
+}
+
+func TestMulDiv(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			assert.Equal(t, byte(a), div(mul(byte(a), byte(b)), byte(b)))
+		}
+	}
+}
@@ -19,6 +19,12 @@ var (
 	ErrGitNotInit = fmt.Errorf("git is not initialized")
 	// ErrGitNoRemote is returned if git has no origin remote.
 	ErrGitNoRemote = fmt.Errorf("git has no remote origin")
+	// ErrGitHistoryRewritten is returned if the remote history was purged and
+	// the local clone still contains the purged commits.
+	ErrGitHistoryRewritten = fmt.Errorf("git history was rewritten on the remote, re-clone the store")
+	// ErrGitConflict is returned if pulling from the remote left merge
+	// conflicts that must be resolved manually.
+	ErrGitConflict = fmt.Errorf("git pull left merge conflicts, resolve them in the store")
 	// ErrGitNothingToCommit is returned if there are no staged changes.
 	ErrGitNothingToCommit = fmt.Errorf("git has nothing to commit")
 	// ErrEmptySecret is returned if a secret exists but has no content.
@@ -33,4 +39,11 @@ var (
 	ErrNoKey = fmt.Errorf("key not found in entry")
 	// ErrYAMLValueUnsupported is returned is the user tries to unmarshal an nested struct.
 	ErrYAMLValueUnsupported = fmt.Errorf("can not unmarshal nested YAML value")
+	// ErrInvalidName is returned if a name can't be used for a secret.
+	ErrInvalidName = fmt.Errorf("invalid secret name")
+	// ErrBreakGlassReason is returned if a break-glass secret is accessed
+	// without a reason.
+	ErrBreakGlassReason = fmt.Errorf("a reason is required to access")
+	// ErrRecipientNotFound is returned if a recipient to remove is not in the store.
+	ErrRecipientNotFound = fmt.Errorf("recipient not in store")
 )
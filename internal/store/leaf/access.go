@@ -0,0 +1,80 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Access describes who can read a secret.
+type Access struct {
+	// Name is the full name of the secret, including the mount point.
+	Name string
+	// Folder is the folder containing the recipients file that applies to
+	// the secret, relative to the store. It's empty for the store root.
+	Folder string
+	// Recipients are the fingerprints listed in the recipients file.
+	Recipients []string
+	// Readers are the fingerprints the secret is actually encrypted for.
+	// They are unknown if Hidden is true.
+	Readers []string
+	// Hidden is true if the ciphertext doesn't reveal its recipients.
+	Hidden bool
+}
+
+// Access returns who is supposed to and who actually can read each secret
+// matching the given prefix.
+func (s *Store) Access(ctx context.Context, prefix string) ([]Access, error) {
+	names, err := s.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entries: %w", err)
+	}
+	sort.Strings(names)
+
+	res := make([]Access, 0, len(names))
+	for _, fullName := range names {
+		name := strings.TrimPrefix(fullName, s.alias+Sep)
+
+		idf := s.idFile(ctx, name)
+		folder := filepath.ToSlash(filepath.Dir(idf))
+		if folder == "." {
+			folder = ""
+		}
+
+		recps, err := s.getRecipients(ctx, idf)
+		if err != nil {
+			return nil, err
+		}
+
+		a := Access{
+			Name:       fullName,
+			Folder:     folder,
+			Recipients: fingerprints(ctx, s.crypto, recps),
+		}
+
+		ciphertext, err := s.storage.Get(ctx, s.passfile(name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get raw secret %s: %w", name, err)
+		}
+		readers, err := s.crypto.RecipientIDs(ctx, ciphertext)
+		switch {
+		case errors.Is(err, backend.ErrHiddenRecipients):
+			debug.Log("recipients of %s are hidden", name)
+			a.Hidden = true
+		case err != nil:
+			return nil, fmt.Errorf("failed to read recipient IDs of %s: %w", name, err)
+		default:
+			a.Readers = fingerprints(ctx, s.crypto, readers)
+		}
+
+		res = append(res, a)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,50 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccess(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithExportKeys(ctx, false)
+
+	tempdir := t.TempDir()
+	s := &Store{
+		alias:   "sub",
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+	require.NoError(t, s.saveRecipients(ctx, []string{"john.doe"}, "test"))
+	require.NoError(t, s.storage.Set(ctx, "team/"+s.crypto.IDFile(), []byte("0xDEADBEEF\n")))
+
+	for _, e := range []string{"foo/bar", "team/db"} {
+		sec := &secrets.Plain{}
+		sec.SetPassword("bar")
+		require.NoError(t, s.Set(ctx, e, sec))
+	}
+
+	acc, err := s.Access(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []Access{
+		{
+			Name:       "sub/foo/bar",
+			Recipients: []string{"john.doe"},
+			Readers:    []string{"0xDEADBEEF", "0xFEEDBEEF"},
+		},
+		{
+			Name:       "sub/team/db",
+			Folder:     "team",
+			Recipients: []string{"0xDEADBEEF"},
+			Readers:    []string{"0xDEADBEEF", "0xFEEDBEEF"},
+		},
+	}, acc)
+}
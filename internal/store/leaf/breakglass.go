@@ -0,0 +1,167 @@
+package leaf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+// BreakGlassFile lists the folders of a store, one per line, that require a
+// reason to be recorded before their secrets are decrypted. It's part of the
+// store, so the list is the same for everyone using it.
+const BreakGlassFile = ".gopass-breakglass"
+
+// breakGlassDir holds the access records. Dot dirs are never listed as
+// secrets.
+const breakGlassDir = ".breakglass"
+
+// BreakGlassFolders returns the folders listed in the BreakGlassFile of the
+// store. Empty lines and lines starting with # are ignored.
+func (s *Store) BreakGlassFolders(ctx context.Context) []string {
+	if s.storage == nil || !s.storage.Exists(ctx, BreakGlassFile) {
+		return nil
+	}
+	buf, err := s.storage.Get(ctx, BreakGlassFile)
+	if err != nil {
+		debug.Log("failed to read %s: %s", BreakGlassFile, err)
+		return nil
+	}
+
+	var folders []string
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		folders = append(folders, line)
+	}
+	return folders
+}
+
+// IsBreakGlass returns true if the secret is below one of the folders that
+// require a reason to be recorded before it is decrypted.
+func (s *Store) IsBreakGlass(ctx context.Context, name string) bool {
+	name = strings.ToLower(strings.Trim(name, "/")) + "/"
+	for _, folder := range s.BreakGlassFolders(ctx) {
+		folder = strings.ToLower(strings.Trim(folder, "/"))
+		if folder == "" || strings.HasPrefix(name, folder+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBreakGlass records the access to a break-glass secret before it is
+// decrypted. The reason is taken from the context, GOPASS_BREAKGLASS_REASON
+// or asked for once per invocation. Maintenance operations that never reveal
+// the content, e.g. re-encryption, are exempt.
+func (s *Store) checkBreakGlass(ctx context.Context, name string) error {
+	if isBreakGlassExempt(ctx) || !s.IsBreakGlass(ctx, name) {
+		return nil
+	}
+
+	s.breakGlassMu.Lock()
+	defer s.breakGlassMu.Unlock()
+
+	if s.breakGlassRecorded[name] {
+		return nil
+	}
+
+	reason := GetBreakGlassReason(ctx)
+	if reason == "" {
+		reason = os.Getenv("GOPASS_BREAKGLASS_REASON")
+	}
+	if reason == "" {
+		reason = s.breakGlassReason
+	}
+	if reason == "" && ctxutil.IsInteractive(ctx) {
+		var err error
+		reason, err = termio.AskForString(ctx, fmt.Sprintf("Access to %s is recorded. Please enter a reason", name), "")
+		if err != nil {
+			return fmt.Errorf("failed to read reason: %w", err)
+		}
+	}
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("%w %s. Use --reason or set GOPASS_BREAKGLASS_REASON", store.ErrBreakGlassReason, name)
+	}
+
+	if err := s.recordAccess(ctx, name, reason); err != nil {
+		return fmt.Errorf("failed to record access to %s: %w", name, err)
+	}
+	out.Noticef(ctx, "Access to %s has been recorded", name)
+
+	s.breakGlassReason = reason
+	if s.breakGlassRecorded == nil {
+		s.breakGlassRecorded = make(map[string]bool, 1)
+	}
+	s.breakGlassRecorded[name] = true
+
+	return nil
+}
+
+// recordAccess commits a signed record of the access to the given secret and
+// the reason for it. It fails if the record can not be signed or committed.
+func (s *Store) recordAccess(ctx context.Context, name, reason string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	signer, ok := s.crypto.(backend.Signer)
+	if !ok {
+		return fmt.Errorf("crypto backend %s can not sign access records", s.crypto.Name())
+	}
+
+	now := time.Now().UTC()
+	reason = strings.Join(strings.Fields(reason), " ")
+	record := []byte(fmt.Sprintf("secret: %s\ntime: %s\nreason: %s\n", name, now.Format(time.RFC3339), reason))
+
+	sig, err := signer.Sign(ctx, record)
+	if err != nil {
+		return fmt.Errorf("failed to sign access record: %w", err)
+	}
+
+	p := path.Join(breakGlassDir, name, now.Format("20060102T150405.000000000Z"))
+	for fn, content := range map[string][]byte{p + ".log": record, p + ".sig": sig} {
+		if err := s.storage.Set(ctx, fn, content); err != nil {
+			return fmt.Errorf("failed to write access record: %w", err)
+		}
+		if err := s.storage.Add(ctx, fn); err != nil {
+			if errors.Is(err, store.ErrGitNotInit) {
+				continue
+			}
+			return fmt.Errorf("failed to add %q to git: %w", fn, err)
+		}
+	}
+
+	if err := s.storage.Commit(ctx, fmt.Sprintf("Break-glass access to %s: %s", name, reason)); err != nil {
+		if !errors.Is(err, store.ErrGitNotInit) {
+			return fmt.Errorf("failed to commit access record: %w", err)
+		}
+		debug.Log("access record not committed - git not initialized")
+	}
+
+	if err := s.storage.Push(ctx, "", ""); err != nil {
+		if errors.Is(err, store.ErrGitNotInit) || errors.Is(err, store.ErrGitNoRemote) {
+			return nil
+		}
+		return fmt.Errorf("failed to push access record: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package leaf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBreakGlass(t *testing.T) {
+	ctx := context.Background()
+
+	tempdir := t.TempDir()
+	s := &Store{
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+	assert.False(t, s.IsBreakGlass(ctx, "prod/db"))
+
+	require.NoError(t, s.storage.Set(ctx, BreakGlassFile, []byte("# emergency only\nprod\n\ninfra/root/\n")))
+	assert.Equal(t, []string{"prod", "infra/root/"}, s.BreakGlassFolders(ctx))
+
+	for name, want := range map[string]bool{
+		"prod":          true,
+		"prod/db":       true,
+		"Prod/db":       true,
+		"production/db": false,
+		"infra/root/pw": true,
+		"infra/rootpw":  false,
+		"dev/db":        false,
+	} {
+		assert.Equal(t, want, s.IsBreakGlass(ctx, name), name)
+	}
+
+	require.NoError(t, s.storage.Set(ctx, BreakGlassFile, []byte("/\n")))
+	assert.True(t, s.IsBreakGlass(ctx, "dev/db"))
+}
+
+func TestCheckBreakGlass(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, false)
+	t.Setenv("GOPASS_BREAKGLASS_REASON", "")
+
+	tempdir := t.TempDir()
+	s := &Store{
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+	require.NoError(t, s.storage.Set(ctx, BreakGlassFile, []byte("prod\n")))
+	records := func(name string) int {
+		lst, err := filepath.Glob(filepath.Join(tempdir, breakGlassDir, name, "*.log"))
+		require.NoError(t, err)
+		return len(lst)
+	}
+
+	assert.NoError(t, s.checkBreakGlass(ctx, "dev/db"))
+	assert.ErrorIs(t, s.checkBreakGlass(ctx, "prod/db"), store.ErrBreakGlassReason)
+	assert.NoError(t, s.checkBreakGlass(withBreakGlassExempt(ctx), "prod/db"))
+	assert.Equal(t, 0, records("prod/db"))
+
+	t.Setenv("GOPASS_BREAKGLASS_REASON", "INC-1")
+	assert.NoError(t, s.checkBreakGlass(ctx, "prod/db"))
+	assert.Equal(t, 1, records("prod/db"))
+	// only recorded once per invocation
+	assert.NoError(t, s.checkBreakGlass(ctx, "prod/db"))
+	assert.Equal(t, 1, records("prod/db"))
+
+	// the reason is reused for other secrets
+	t.Setenv("GOPASS_BREAKGLASS_REASON", "")
+	assert.NoError(t, s.checkBreakGlass(ctx, "prod/web"))
+	assert.Equal(t, 1, records("prod/web"))
+}
+
+func TestRecordAccess(t *testing.T) {
+	ctx := context.Background()
+
+	tempdir := t.TempDir()
+	s := &Store{
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+
+	require.NoError(t, s.recordAccess(ctx, "prod/db", "incident\n42"))
+
+	lst, err := filepath.Glob(filepath.Join(tempdir, breakGlassDir, "prod", "db", "*.log"))
+	require.NoError(t, err)
+	require.Len(t, lst, 1)
+
+	buf, err := os.ReadFile(lst[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "secret: prod/db\n")
+	assert.Contains(t, string(buf), "reason: incident 42\n")
+
+	sig, err := os.ReadFile(strings.TrimSuffix(lst[0], ".log") + ".sig")
+	require.NoError(t, err)
+	id, err := plain.New().Verify(ctx, buf, sig)
+	require.NoError(t, err)
+	assert.Equal(t, "0xDEADBEEF", id)
+
+	// records are not secrets
+	names, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
@@ -15,6 +15,9 @@ const (
 	ctxKeyCheckRecipients
 	ctxKeyFsckDecrypt
 	ctxKeyNoGitOps
+	ctxKeyLocked
+	ctxKeyBreakGlassReason
+	ctxKeyBreakGlassExempt
 )
 
 // WithFsckCheck returns a context with the flag for fscks check set.
@@ -127,6 +130,32 @@ func IsNoGitOps(ctx context.Context) bool {
 	return is(ctx, ctxKeyNoGitOps, false)
 }
 
+// WithBreakGlassReason returns a context with the reason for accessing
+// break-glass secrets set.
+func WithBreakGlassReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, ctxKeyBreakGlassReason, reason)
+}
+
+// GetBreakGlassReason returns the reason for accessing break-glass secrets or
+// an empty string.
+func GetBreakGlassReason(ctx context.Context) string {
+	sv, ok := ctx.Value(ctxKeyBreakGlassReason).(string)
+	if !ok {
+		return ""
+	}
+	return sv
+}
+
+// withBreakGlassExempt returns a context for maintenance operations that
+// decrypt secrets without revealing them, e.g. re-encryption.
+func withBreakGlassExempt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyBreakGlassExempt, true)
+}
+
+func isBreakGlassExempt(ctx context.Context) bool {
+	return is(ctx, ctxKeyBreakGlassExempt, false)
+}
+
 // hasBool is a helper function for checking if a bool has been set in
 // the provided context.
 func hasBool(ctx context.Context, key contextKey) bool {
@@ -21,6 +21,15 @@ import (
 // different set of crypto and storage backends. Please note that it
 // will happily convert to the same set of backends if requested.
 func (s *Store) Convert(ctx context.Context, cryptoBe backend.CryptoBackend, storageBe backend.StorageBackend, move bool) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// secrets are copied encrypted into the new store, they are never
+	// revealed.
+	ctx = withBreakGlassExempt(ctx)
 
 	// create temp path
 	tmpPath := s.path + "-autoconvert"
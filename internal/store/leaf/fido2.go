@@ -0,0 +1,192 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// HasFIDO2 returns true if the secrets of this store are protected by a
+// FIDO2 authenticator.
+func (s *Store) HasFIDO2(ctx context.Context) bool {
+	return s.storage.Exists(ctx, fido2.KeyringFile)
+}
+
+// EnrollFIDO2 adds the FIDO2 authenticator a that can unlock the secrets of
+// this store. The first authenticator creates the key of the store and
+// protects all existing secrets with it. Any further authenticators need one
+// that's already enrolled to unlock the key first. Since the other recipients
+// of the store can't read the secrets anymore once they're protected, the
+// first enrollment is refused for shared stores unless forced.
+func (s *Store) EnrollFIDO2(ctx context.Context, a fido2.Authenticator, name string) error {
+	var kr *fido2.Keyring
+	var key []byte
+	var err error
+	if !s.HasFIDO2(ctx) && !ctxutil.IsForce(ctx) {
+		if others := s.otherRecipients(ctx); len(others) > 0 {
+			return fmt.Errorf("the store is shared with %s who would lose access to its secrets. Use --force to enroll anyway", strings.Join(others, ", "))
+		}
+	}
+	if s.HasFIDO2(ctx) {
+		kr, err = s.fido2Keyring(ctx)
+		if err == nil {
+			key, err = s.fido2Key(ctx)
+		}
+	} else {
+		kr = &fido2.Keyring{}
+		key, err = fido2.NewKey()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := kr.Enroll(ctx, a, name, key); err != nil {
+		return err
+	}
+
+	buf, err := kr.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := s.storage.Set(ctx, fido2.KeyringFile, buf); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fido2.KeyringFile, err)
+	}
+	if err := s.storage.Add(ctx, fido2.KeyringFile); err != nil && !errors.Is(err, store.ErrGitNotInit) {
+		return fmt.Errorf("failed to add %s to git: %w", fido2.KeyringFile, err)
+	}
+
+	s.fido2Mu.Lock()
+	s.fido2Cache = key
+	s.fido2Mu.Unlock()
+
+	if len(kr.Devices) > 1 {
+		if err := s.storage.Commit(ctx, "Enrolled FIDO2 authenticator "+name); err != nil {
+			if errors.Is(err, store.ErrGitNotInit) || errors.Is(err, store.ErrGitNothingToCommit) {
+				return nil
+			}
+			return fmt.Errorf("failed to commit changes to git: %w", err)
+		}
+		return s.reencryptGitPush(ctx)
+	}
+
+	// protect the existing secrets with the new key. This commits the
+	// keyring as well.
+	return s.reencrypt(ctxutil.WithCommitMessage(ctx, "Protected store with FIDO2 authenticator "+name))
+}
+
+func (s *Store) fido2Keyring(ctx context.Context) (*fido2.Keyring, error) {
+	buf, err := s.storage.Get(ctx, fido2.KeyringFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", fido2.KeyringFile, err)
+	}
+	return fido2.ParseKeyring(buf)
+}
+
+// fido2Key returns the key of the store. Unwrapping it requires a touch, so
+// it's only done once.
+func (s *Store) fido2Key(ctx context.Context) ([]byte, error) {
+	s.fido2Mu.Lock()
+	defer s.fido2Mu.Unlock()
+
+	if s.fido2Cache != nil {
+		return s.fido2Cache, nil
+	}
+
+	kr, err := s.fido2Keyring(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := kr.Unlock(ctx, fido2.GetAuthenticator(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock %s: %w", s.alias, err)
+	}
+	debug.Log("unlocked FIDO2 key of %q", s.alias)
+
+	s.fido2Cache = key
+	return key, nil
+}
+
+// sealFIDO2 protects the content with the key of the store, if the store
+// uses FIDO2.
+func (s *Store) sealFIDO2(ctx context.Context, content []byte) ([]byte, error) {
+	if !s.HasFIDO2(ctx) {
+		return content, nil
+	}
+
+	key, err := s.fido2Key(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fido2.Seal(key, content)
+}
+
+// openFIDO2 removes the FIDO2 protection from the decrypted content. Content
+// that isn't protected, e.g. because it was written before the store was
+// enrolled, is returned as is.
+func (s *Store) openFIDO2(ctx context.Context, content []byte) ([]byte, error) {
+	if !fido2.IsSealed(content) {
+		return content, nil
+	}
+
+	key, err := s.fido2Key(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fido2.Open(key, content)
+}
+
+// unlockFIDO2 asks for the touch before the crypto backend is asked to
+// decrypt anything.
+func (s *Store) unlockFIDO2(ctx context.Context) error {
+	if !s.HasFIDO2(ctx) {
+		return nil
+	}
+	_, err := s.fido2Key(ctx)
+	return err
+}
+
+// otherRecipients returns the recipients of any folder of the store that
+// aren't one of our own identities.
+func (s *Store) otherRecipients(ctx context.Context) []string {
+	ids, err := s.crypto.ListIdentities(ctx)
+	if err != nil {
+		debug.Log("failed to list identities: %s", err)
+	}
+
+	seen := make(map[string]bool)
+	var others []string
+	for _, rs := range s.RecipientsTree(ctx) {
+		for _, r := range rs {
+			if seen[r] {
+				continue
+			}
+			seen[r] = true
+			if s.isOwnRecipient(ctx, ids, r) {
+				continue
+			}
+			others = append(others, r)
+		}
+	}
+	sort.Strings(others)
+	return others
+}
+
+// isOwnRecipient returns true if r is one of the given identities or any
+// other private key we have.
+func (s *Store) isOwnRecipient(ctx context.Context, ids []string, r string) bool {
+	for _, id := range ids {
+		if r == id || gpg.SameKey(r, id) {
+			return true
+		}
+	}
+	kl, err := s.crypto.FindIdentities(ctx, r)
+	return err == nil && len(kl) > 0
+}
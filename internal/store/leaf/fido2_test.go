@@ -0,0 +1,108 @@
+package leaf
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/fido2"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fido2Mocker struct {
+	secret  string
+	touches int
+}
+
+func (f *fido2Mocker) MakeCredential(ctx context.Context, user string) ([]byte, error) {
+	return []byte(f.secret + "-" + user), nil
+}
+
+func (f *fido2Mocker) HMACSecret(ctx context.Context, credential, salt []byte) ([]byte, error) {
+	f.touches++
+	if len(credential) <= len(f.secret) || string(credential[:len(f.secret)]) != f.secret {
+		return nil, fmt.Errorf("no credentials")
+	}
+	mac := hmac.New(sha256.New, credential)
+	_, _ = mac.Write(salt)
+	return mac.Sum(nil), nil
+}
+
+func TestFIDO2(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := createSubStore(t.TempDir())
+	require.NoError(t, err)
+
+	sec := &secrets.Plain{}
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "old", sec))
+	assert.False(t, s.HasFIDO2(ctx))
+
+	primary := &fido2Mocker{secret: "primary"}
+	require.NoError(t, s.EnrollFIDO2(ctx, primary, "primary"))
+	assert.True(t, s.HasFIDO2(ctx))
+
+	// existing secrets are protected as well.
+	for _, name := range []string{"old", "new"} {
+		if name == "new" {
+			require.NoError(t, s.Set(ctx, name, sec))
+		}
+		buf, err := s.storage.Get(ctx, s.passfile(name))
+		require.NoError(t, err)
+		assert.True(t, fido2.IsSealed(buf), name)
+	}
+
+	// the keyring is not a secret.
+	names, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.NotContains(t, names, fido2.KeyringFile)
+	assert.Contains(t, names, "old")
+
+	// a fresh instance needs a touch, but only one.
+	s.fido2Cache = nil
+	primary.touches = 0
+	ctx = fido2.WithAuthenticator(ctx, primary)
+	for _, name := range []string{"old", "new"} {
+		got, err := s.Get(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, "foo", got.Password())
+	}
+	assert.Equal(t, 1, primary.touches)
+
+	// enrolling a backup needs the primary to unlock.
+	s.fido2Cache = nil
+	backup := &fido2Mocker{secret: "backup"}
+	require.NoError(t, s.EnrollFIDO2(ctx, backup, "backup"))
+
+	s.fido2Cache = nil
+	got, err := s.Get(fido2.WithAuthenticator(ctx, backup), "old")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got.Password())
+
+	s.fido2Cache = nil
+	_, err = s.Get(fido2.WithAuthenticator(ctx, &fido2Mocker{secret: "other"}), "old")
+	assert.ErrorIs(t, err, store.ErrDecrypt)
+}
+
+func TestFIDO2SharedStore(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := createSubStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, s.AddRecipient(ctx, "0xBADC0FFEE"))
+
+	err = s.EnrollFIDO2(ctx, &fido2Mocker{secret: "primary"}, "primary")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "0xBADC0FFEE")
+	assert.False(t, s.HasFIDO2(ctx))
+
+	require.NoError(t, s.EnrollFIDO2(ctxutil.WithForce(ctx, true), &fido2Mocker{secret: "primary"}, "primary"))
+	assert.True(t, s.HasFIDO2(ctx))
+}
@@ -18,7 +18,15 @@ import (
 
 // Fsck checks all entries matching the given prefix.
 func (s *Store) Fsck(ctx context.Context, path string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	ctx = out.AddPrefix(ctx, "["+s.alias+"] ")
+	// fsck only checks the content of secrets, it never reveals it.
+	ctx = withBreakGlassExempt(ctx)
 	debug.Log("Checking %s", path)
 
 	// first let the storage backend check itself
@@ -33,6 +41,11 @@ func (s *Store) Fsck(ctx context.Context, path string) error {
 		return fmt.Errorf("storage backend compaction failed: %w", err)
 	}
 
+	out.Printf(ctx, "Checking recipients")
+	if err := s.resolveAllRecipients(ctx); err != nil {
+		return err
+	}
+
 	pcb := ctxutil.GetProgressCallback(ctx)
 
 	// then we'll make sure all the secrets are readable by us and every
@@ -44,6 +57,9 @@ func (s *Store) Fsck(ctx context.Context, path string) error {
 	}
 
 	sort.Strings(names)
+	for _, c := range nameCollisions(names) {
+		out.Warningf(ctx, "Secrets only differ by case or unicode normalization and clash on some systems: %s", strings.Join(c, ", "))
+	}
 	for _, name := range names {
 		pcb()
 		if strings.HasPrefix(name, s.alias+"/") {
@@ -93,6 +109,11 @@ func (s *Store) fsckCheckEntry(ctx context.Context, name string) error {
 	}
 
 	itemRecps, err := s.crypto.RecipientIDs(ctx, ciphertext)
+	if errors.Is(err, backend.ErrHiddenRecipients) {
+		// nothing to compare, the recipients file is all we have
+		debug.Log("skipping recipient check for %s: %s", name, err)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to read recipient IDs from raw secret: %w", err)
 	}
@@ -114,7 +135,13 @@ func (s *Store) fsckCheckEntry(ctx context.Context, name string) error {
 		out.Errorf(ctx, "Extra recipients on %s: %+v\nRun fsck with the --decrypt flag to re-encrypt it automatically, or edit this secret yourself.", name, extra)
 	}
 
-	if IsFsckDecrypt(ctx) && (len(missing) > 0 || len(extra) > 0) {
+	// secrets written before the recipients were hidden still reveal them
+	revealed := s.cfg.HiddenRecipients && len(itemRecps) > 0
+	if revealed {
+		out.Warningf(ctx, "Secret %s reveals its recipients\nRun fsck with the --decrypt flag to re-encrypt it automatically, or edit this secret yourself.", name)
+	}
+
+	if IsFsckDecrypt(ctx) && (len(missing) > 0 || len(extra) > 0 || revealed) {
 		out.Printf(ctx, "Re-encrypting automatically %s to fix the recipients.", name)
 		sec, err := s.Get(ctx, name)
 		if err != nil {
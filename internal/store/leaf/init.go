@@ -21,6 +21,12 @@ func (s *Store) IsInitialized(ctx context.Context) bool {
 
 // Init tries to initialize a new password store location matching the object.
 func (s *Store) Init(ctx context.Context, path string, ids ...string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if s.IsInitialized(ctx) {
 		return fmt.Errorf(`found already initialized store at %q.
 You can add secondary stores with 'gopass init --path <path to secondary store> --store <mount name>'`, path)
@@ -12,6 +12,15 @@ import (
 
 // Link creates a symlink.
 func (s *Store) Link(ctx context.Context, from, to string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := s.validateName(to); err != nil {
+		return err
+	}
 	if !s.Exists(ctx, from) {
 		return fmt.Errorf("source %q does not exists", from)
 	}
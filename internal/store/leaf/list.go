@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
@@ -37,3 +38,40 @@ func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
 	}
 	return out, nil
 }
+
+// ListStream is like List but sends the entries to the returned channel as
+// soon as they are found. The channel is closed when all entries have been
+// sent or the context is canceled. Unlike List the entries are not sorted.
+func (s *Store) ListStream(ctx context.Context, prefix string) (<-chan store.Entry, error) {
+	ch := make(chan store.Entry, 64)
+	if s.storage == nil || s.crypto == nil {
+		close(ch)
+		return ch, nil
+	}
+
+	cExt := "." + s.crypto.Ext()
+	go func() {
+		defer close(ch)
+
+		err := s.storage.Walk(ctx, prefix, func(path string) error {
+			if !strings.HasSuffix(path, cExt) {
+				return nil
+			}
+			path = strings.TrimSuffix(path, cExt)
+			if s.alias != "" {
+				path = s.alias + Sep + path
+			}
+			select {
+			case ch <- store.Entry{Name: path}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			ch <- store.Entry{Err: err}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,65 @@
+package leaf
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/flock"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+// lockTimeout is the maximum time to wait for another gopass process to
+// release the lock of a store.
+var lockTimeout = 2 * time.Minute
+
+// lockFile returns the path of the lock file of this store. It's kept outside
+// of the store so it never ends up in git.
+func (s *Store) lockFile() string {
+	sum := sha256.Sum256([]byte(fsutil.CleanPath(s.path)))
+	return filepath.Join(appdir.UserCache(), "locks", fmt.Sprintf("%x.lock", sum[:16]))
+}
+
+// Acquire takes the exclusive lock of this store so concurrent gopass
+// processes can't interleave changes to the store or its git repository.
+// The returned context marks the lock as held, so nested operations using
+// it don't try to take it again. The returned function releases the lock.
+func (s *Store) Acquire(ctx context.Context) (context.Context, func(), error) {
+	fn := s.lockFile()
+	held, _ := ctx.Value(ctxKeyLocked).(map[string]struct{})
+	if _, found := held[fn]; found {
+		return ctx, func() {}, nil
+	}
+
+	l, err := flock.TryAcquire(fn)
+	if errors.Is(err, flock.ErrLocked) {
+		out.Noticef(ctx, "Waiting for another gopass process to finish changing the store at %s", s.path)
+		tctx, cancel := context.WithTimeout(ctx, lockTimeout)
+		defer cancel()
+		l, err = flock.Acquire(tctx, fn)
+	}
+	if err != nil {
+		return ctx, nil, fmt.Errorf("failed to lock store at %s: %w", s.path, err)
+	}
+	debug.Log("locked %s (%s)", s.path, fn)
+
+	// copy the set, contexts are shared
+	locked := make(map[string]struct{}, len(held)+1)
+	for k := range held {
+		locked[k] = struct{}{}
+	}
+	locked[fn] = struct{}{}
+
+	return context.WithValue(ctx, ctxKeyLocked, locked), func() {
+		if err := l.Release(); err != nil {
+			debug.Log("failed to unlock %s: %s", s.path, err)
+		}
+		debug.Log("unlocked %s", s.path)
+	}, nil
+}
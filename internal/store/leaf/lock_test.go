@@ -0,0 +1,45 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/flock"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+
+	s, err := createSubStore(t.TempDir())
+	require.NoError(t, err)
+
+	oldTimeout := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	defer func() {
+		lockTimeout = oldTimeout
+	}()
+
+	lctx, unlock, err := s.Acquire(ctx)
+	require.NoError(t, err)
+
+	// nested operations re-use the lock
+	_, nested, err := s.Acquire(lctx)
+	require.NoError(t, err)
+	nested()
+	sec := &secrets.Plain{}
+	sec.SetPassword("foo")
+	assert.NoError(t, s.Set(lctx, "foo", sec))
+
+	// everyone else has to wait
+	_, _, err = s.Acquire(ctx)
+	assert.ErrorIs(t, err, flock.ErrLocked)
+	assert.Error(t, s.Set(ctx, "bar", sec))
+
+	unlock()
+	assert.NoError(t, s.Set(ctx, "bar", sec))
+}
@@ -0,0 +1,222 @@
+package leaf
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/recipients"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+// MigrateResult summarizes a migration.
+type MigrateResult struct {
+	From     string
+	To       string
+	Migrated int
+	// Resumed is the number of secrets that were already encrypted with the
+	// new backend by an earlier, interrupted run.
+	Resumed int
+}
+
+// Migrate re-encrypts all secrets of the store with the crypto backend to
+// for the given recipients. The new ciphertexts are written next to the old
+// ones first, so an interrupted migration is resumed by running it again.
+// Only after every new ciphertext decrypts to the same content as the old
+// one, the old files are removed and everything is committed at once.
+func (s *Store) Migrate(ctx context.Context, to backend.Crypto, rs []string) (MigrateResult, error) {
+	res := MigrateResult{To: to.Name()}
+	if s.crypto == nil {
+		return res, fmt.Errorf("store has no crypto backend")
+	}
+	from := s.crypto
+	res.From = from.Name()
+
+	if from.Name() == to.Name() {
+		return res, fmt.Errorf("store already uses %s", from.Name())
+	}
+	if from.Ext() == to.Ext() || from.IDFile() == to.IDFile() {
+		return res, fmt.Errorf("%s and %s use the same file names", from.Name(), to.Name())
+	}
+	if len(rs) < 1 {
+		return res, fmt.Errorf("no recipients for %s", to.Name())
+	}
+
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return res, err
+	}
+	defer unlock()
+
+	for _, idf := range s.idFiles(ctx) {
+		if idf != from.IDFile() {
+			return res, fmt.Errorf("folder specific recipients (%s) can not be migrated", idf)
+		}
+	}
+
+	entries, err := s.List(ctx, "")
+	if err != nil {
+		return res, fmt.Errorf("failed to list store: %w", err)
+	}
+	for i, e := range entries {
+		entries[i] = strings.TrimPrefix(e, s.alias+Sep)
+	}
+
+	s.TryUnlock(ctx)
+
+	// encrypt everything with the new backend, keeping the old files.
+	out.Printf(ctx, "Encrypting %d secrets with %s ...", len(entries), to.Name())
+	sums := make(map[string][sha256.Size]byte, len(entries))
+	bar := termio.NewProgressBar(int64(len(entries)))
+	bar.Hidden = !ctxutil.IsTerminal(ctx) || ctxutil.IsHidden(ctx)
+	for _, e := range entries {
+		bar.Inc()
+		if s.storage.Exists(ctx, migratedFile(e, to)) {
+			debug.Log("%s was migrated before", e)
+			res.Resumed++
+			continue
+		}
+		content, err := s.migrateEntry(ctx, from, to, e, rs)
+		if err != nil {
+			bar.Done()
+			return res, err
+		}
+		sums[e] = sha256.Sum256(content)
+	}
+	bar.Done()
+
+	// verify that the new ciphertexts decrypt to the same content.
+	out.Printf(ctx, "Verifying %d secrets ...", len(entries))
+	for _, e := range entries {
+		if err := s.verifyMigrated(ctx, from, to, e, rs, sums); err != nil {
+			return res, err
+		}
+	}
+
+	if err := s.finishMigration(ctx, from, to, entries, rs); err != nil {
+		return res, err
+	}
+	s.crypto = to
+	res.Migrated = len(entries)
+
+	return res, s.reencryptGitPush(ctx)
+}
+
+func migratedFile(name string, to backend.Crypto) string {
+	return strings.TrimPrefix(name+"."+to.Ext(), "/")
+}
+
+// migrateEntry encrypts the content of the entry with the new backend and
+// returns the plaintext.
+func (s *Store) migrateEntry(ctx context.Context, from, to backend.Crypto, name string, rs []string) ([]byte, error) {
+	ciphertext, err := s.storage.Get(ctx, s.passfile(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	content, err := from.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", name, err)
+	}
+	ciphertext, err = to.Encrypt(ctx, content, rs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s with %s: %w", name, to.Name(), err)
+	}
+	if err := s.storage.Set(ctx, migratedFile(name, to), ciphertext); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return content, nil
+}
+
+// verifyMigrated checks the new ciphertext of the entry. Entries from an
+// earlier run that fail the check, e.g. because that run was interrupted
+// while writing them, are migrated again.
+func (s *Store) verifyMigrated(ctx context.Context, from, to backend.Crypto, name string, rs []string, sums map[string][sha256.Size]byte) error {
+	want, found := sums[name]
+	if !found {
+		ciphertext, err := s.storage.Get(ctx, s.passfile(name))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		content, err := from.Decrypt(ctx, ciphertext)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", name, err)
+		}
+		want = sha256.Sum256(content)
+	}
+
+	ciphertext, err := s.storage.Get(ctx, migratedFile(name, to))
+	if err == nil {
+		var content []byte
+		content, err = to.Decrypt(ctx, ciphertext)
+		if err == nil && sha256.Sum256(content) == want {
+			return nil
+		}
+	}
+	if found {
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s with %s. Make sure your own key is one of the recipients: %w", name, to.Name(), err)
+		}
+		return fmt.Errorf("%s changed its content when encrypted with %s", name, to.Name())
+	}
+
+	debug.Log("migrated file of %s is invalid (%v), migrating again", name, err)
+	content, err := s.migrateEntry(ctx, from, to, name, rs)
+	if err != nil {
+		return err
+	}
+	sums[name] = sha256.Sum256(content)
+	return s.verifyMigrated(ctx, from, to, name, rs, sums)
+}
+
+// finishMigration replaces the old files with the new ones and commits the
+// result. The recipients files are replaced last, so a store that still
+// contains old secrets is still detected as using the old backend.
+func (s *Store) finishMigration(ctx context.Context, from, to backend.Crypto, entries, rs []string) error {
+	add := func(fn string) error {
+		if err := s.storage.Add(ctx, fn); err != nil && !errors.Is(err, store.ErrGitNotInit) {
+			return fmt.Errorf("failed to add %q to git: %w", fn, err)
+		}
+		return nil
+	}
+
+	for _, e := range entries {
+		if err := add(migratedFile(e, to)); err != nil {
+			return err
+		}
+		if err := s.storage.Delete(ctx, s.passfile(e)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", s.passfile(e), err)
+		}
+		if err := add(s.passfile(e)); err != nil {
+			return err
+		}
+	}
+
+	if err := s.storage.Set(ctx, to.IDFile(), recipients.Marshal(rs)); err != nil {
+		return fmt.Errorf("failed to write recipients file: %w", err)
+	}
+	if err := add(to.IDFile()); err != nil {
+		return err
+	}
+	if err := s.storage.Delete(ctx, from.IDFile()); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", from.IDFile(), err)
+	}
+	if err := add(from.IDFile()); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Migrated %d secrets from %s to %s", len(entries), from.Name(), to.Name())
+	if err := s.storage.Commit(ctx, msg); err != nil {
+		if !errors.Is(err, store.ErrGitNotInit) && !errors.Is(err, store.ErrGitNothingToCommit) {
+			return fmt.Errorf("failed to commit changes to git: %w", err)
+		}
+	}
+
+	return nil
+}
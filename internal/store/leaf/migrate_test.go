@@ -0,0 +1,131 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reverseMocker is a second fake crypto backend that stores the reversed
+// plaintext.
+type reverseMocker struct {
+	*plain.Mocker
+}
+
+func (r *reverseMocker) Name() string   { return "reverse" }
+func (r *reverseMocker) Ext() string    { return "rev" }
+func (r *reverseMocker) IDFile() string { return ".rev-id" }
+
+func (r *reverseMocker) Encrypt(ctx context.Context, plaintext []byte, recipients []string) ([]byte, error) {
+	return reverse(plaintext), nil
+}
+
+func (r *reverseMocker) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return reverse(ciphertext), nil
+}
+
+func reverse(in []byte) []byte {
+	out := make([]byte, len(in))
+	for i, b := range in {
+		out[len(in)-1-i] = b
+	}
+	return out
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	newStore := func(t *testing.T) (*Store, []string) {
+		t.Helper()
+
+		tempdir := t.TempDir()
+		_, entries, err := createStore(tempdir, nil, []string{"foo/bar", "foo/baz", "zab"})
+		require.NoError(t, err)
+
+		s := &Store{
+			path:    tempdir,
+			crypto:  plain.New(),
+			storage: fs.New(tempdir),
+		}
+		for _, e := range entries {
+			sec := secrets.New()
+			sec.SetPassword("pw-" + e)
+			require.NoError(t, s.Set(ctx, e, sec))
+		}
+		return s, entries
+	}
+
+	check := func(t *testing.T, s *Store, entries []string) {
+		t.Helper()
+
+		assert.Equal(t, "reverse", s.Crypto().Name())
+		for _, e := range entries {
+			assert.NoFileExists(t, filepath.Join(s.path, e+"."+plain.Ext))
+			sec, err := s.Get(ctx, e)
+			require.NoError(t, err)
+			assert.Equal(t, "pw-"+e, sec.Password())
+		}
+		assert.NoFileExists(t, filepath.Join(s.path, plain.IDFile))
+		buf, err := os.ReadFile(filepath.Join(s.path, ".rev-id"))
+		require.NoError(t, err)
+		assert.Equal(t, "0xDEADBEEF\n", string(buf))
+	}
+
+	t.Run("migrate", func(t *testing.T) {
+		s, entries := newStore(t)
+
+		res, err := s.Migrate(ctx, &reverseMocker{plain.New()}, []string{"0xDEADBEEF"})
+		require.NoError(t, err)
+		assert.Equal(t, MigrateResult{From: "plain", To: "reverse", Migrated: 3}, res)
+		check(t, s, entries)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		s, entries := newStore(t)
+
+		// an earlier run migrated foo/bar and was interrupted writing foo/baz.
+		require.NoError(t, os.WriteFile(filepath.Join(s.path, "foo", "bar.rev"), reverse([]byte("pw-foo/bar\n")), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(s.path, "foo", "baz.rev"), []byte("trunc"), 0o600))
+
+		res, err := s.Migrate(ctx, &reverseMocker{plain.New()}, []string{"0xDEADBEEF"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Resumed)
+		assert.Equal(t, 3, res.Migrated)
+		check(t, s, entries)
+	})
+
+	t.Run("same backend", func(t *testing.T) {
+		s, _ := newStore(t)
+
+		_, err := s.Migrate(ctx, plain.New(), []string{"0xDEADBEEF"})
+		assert.Error(t, err)
+	})
+
+	t.Run("folder recipients", func(t *testing.T) {
+		s, entries := newStore(t)
+		require.NoError(t, os.WriteFile(filepath.Join(s.path, "foo", plain.IDFile), []byte("0xFEEDBEEF"), 0o600))
+
+		_, err := s.Migrate(ctx, &reverseMocker{plain.New()}, []string{"0xDEADBEEF"})
+		require.Error(t, err)
+		for _, e := range entries {
+			assert.FileExists(t, filepath.Join(s.path, e+"."+plain.Ext))
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/backend"
+)
+
+// mirrorer is implemented by storage backends that can replicate to
+// additional remotes.
+type mirrorer interface {
+	PushMirror(ctx context.Context, remote, branch string) error
+}
+
+// MirrorResult is the outcome of pushing to a single mirror.
+type MirrorResult struct {
+	Remote string
+	Err    error
+}
+
+// Mirrors returns the names of the git remotes this store is replicated to.
+func (s *Store) Mirrors() []string {
+	return strings.Fields(s.cfg.Mirrors)
+}
+
+// PushMirrors pushes the store to all of its mirrors. A failing mirror does
+// not stop the others. The caller must hold the store lock.
+func (s *Store) PushMirrors(ctx context.Context) []MirrorResult {
+	mirrors := s.Mirrors()
+	res := make([]MirrorResult, 0, len(mirrors))
+
+	m, ok := s.storage.(mirrorer)
+	for _, remote := range mirrors {
+		r := MirrorResult{Remote: remote}
+		if ok {
+			r.Err = m.PushMirror(ctx, remote, "")
+		} else {
+			r.Err = fmt.Errorf("storage backend %s can not push to mirrors: %w", s.storage.Name(), backend.ErrNotSupported)
+		}
+		res = append(res, r)
+	}
+
+	return res
+}
@@ -16,6 +16,12 @@ import (
 // supported. Each entry has to be decoded and encoded for the destination
 // to make sure it's encrypted for the right set of recipients.
 func (s *Store) Copy(ctx context.Context, from, to string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// recursive copy?
 	if s.IsDir(ctx, from) {
 		return fmt.Errorf("recursive operations are not supported")
@@ -36,6 +42,12 @@ func (s *Store) Copy(ctx context.Context, from, to string) error {
 // for the destination store with the right set of recipients and remove it
 // from the old location afterwards.
 func (s *Store) Move(ctx context.Context, from, to string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// recursive move?
 	if s.IsDir(ctx, from) {
 		return fmt.Errorf("recursive operations are not supported")
@@ -67,6 +79,16 @@ func (s *Store) Prune(ctx context.Context, tree string) error {
 // delete will either delete one file or an directory tree depending on the
 // recurse flag.
 func (s *Store) delete(ctx context.Context, name string, recurse bool) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer s.invalidateNames()
+
+	if !recurse {
+		name = s.resolveName(ctx, name)
+	}
 	path := s.passfile(name)
 
 	if recurse {
@@ -282,6 +282,21 @@ func TestPrune(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Trailing slash",
+			tf: func(s *Store) func(t *testing.T) {
+				return func(t *testing.T) {
+					sec := &secrets.Plain{}
+					sec.SetPassword("bar")
+					assert.NoError(t, s.Set(ctx, "foo/bar/baz", sec))
+					// e.g. from tab completion
+					assert.NoError(t, s.Prune(ctx, "foo/bar/"))
+
+					_, err := s.Get(ctx, "foo/bar/baz")
+					assert.Error(t, err)
+				}
+			},
+		},
 	} {
 		// common setup
 		tempdir, err := os.MkdirTemp("", "gopass-")
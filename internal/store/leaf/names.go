@@ -0,0 +1,142 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldName returns the form of name used to compare names in case
+// insensitive stores. Names that only differ by case or unicode
+// normalization (e.g. NFD on macOS and NFC on Linux) have the same form.
+func foldName(name string) string {
+	return strings.ToLower(norm.NFC.String(name))
+}
+
+// resolveName returns the name of the secret name refers to. Names are used
+// as they are unless the store is configured to be case insensitive. Then an
+// existing secret that only differs by case or normalization is used
+// instead and new names are NFC normalized. Ambiguous names are returned
+// unchanged.
+func (s *Store) resolveName(ctx context.Context, name string) string {
+	if !s.cfg.CaseInsensitive || s.storage == nil || s.crypto == nil {
+		return name
+	}
+	if s.storage.Exists(ctx, s.passfile(name)) {
+		return name
+	}
+
+	index, err := s.nameIndex(ctx)
+	if err != nil {
+		debug.Log("failed to look up %q: %s", name, err)
+		return name
+	}
+
+	matches := index[foldName(name)]
+	switch len(matches) {
+	case 0:
+		return norm.NFC.String(name)
+	case 1:
+		debug.Log("resolved %q to %q", name, matches[0])
+		return matches[0]
+	default:
+		debug.Log("%q is ambiguous: %q", name, matches)
+		return name
+	}
+}
+
+// nameIndex returns the names of all secrets by their folded form. The index
+// is built once and kept until a secret is written or removed.
+func (s *Store) nameIndex(ctx context.Context) (map[string][]string, error) {
+	s.namesMu.Lock()
+	defer s.namesMu.Unlock()
+
+	cExt := "." + s.crypto.Ext()
+	if s.namesIndex != nil && s.namesExt == cExt {
+		return s.namesIndex, nil
+	}
+
+	index := make(map[string][]string)
+	if err := s.storage.Walk(ctx, "", func(p string) error {
+		if !strings.HasSuffix(p, cExt) {
+			return nil
+		}
+		c := strings.TrimSuffix(p, cExt)
+		index[foldName(c)] = append(index[foldName(c)], c)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	s.namesIndex = index
+	s.namesExt = cExt
+	return index, nil
+}
+
+// invalidateNames drops the index of nameIndex. It must be called whenever
+// secrets are added or removed.
+func (s *Store) invalidateNames() {
+	s.namesMu.Lock()
+	defer s.namesMu.Unlock()
+
+	s.namesIndex = nil
+}
+
+// nameCollisions returns groups of names that only differ by case or
+// unicode normalization. They are indistinguishable on case insensitive
+// file systems, e.g. on macOS, and can't be checked out there.
+func nameCollisions(names []string) [][]string {
+	groups := make(map[string][]string, len(names))
+	for _, name := range names {
+		k := foldName(name)
+		groups[k] = append(groups[k], name)
+	}
+
+	res := make([][]string, 0)
+	for _, g := range groups {
+		if len(g) < 2 {
+			continue
+		}
+		sort.Strings(g)
+		res = append(res, g)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][0] < res[j][0]
+	})
+	return res
+}
+
+// maxPathLen is the maximum length of the full path of a secret. Windows
+// doesn't support longer paths unless long path support was enabled.
+func maxPathLen() int {
+	if runtime.GOOS == "windows" {
+		return 260
+	}
+	return 4096
+}
+
+// validateName checks that name is a valid secret name and that the file
+// for it can be created inside this store.
+func (s *Store) validateName(name string) error {
+	if err := store.ValidateName(name); err != nil {
+		return err
+	}
+
+	p := s.passfile(name)
+	if l := len(path.Base(p)); l > store.MaxElemLen {
+		return fmt.Errorf("%w: %q is longer than %d bytes", store.ErrInvalidName, path.Base(p), store.MaxElemLen)
+	}
+	if fn := filepath.Join(s.path, p); len(fn) > maxPathLen() {
+		return fmt.Errorf("%w: the path %q is longer than %d bytes", store.ErrInvalidName, fn, maxPathLen())
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package leaf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cafeNFC = "caf\u00e9"
+	cafeNFD = "cafe\u0301"
+)
+
+func TestNameCollisions(t *testing.T) {
+	assert.Equal(t, foldName(cafeNFC), foldName(cafeNFD))
+	assert.Equal(t, "web/github", foldName("Web/GitHub"))
+
+	assert.Equal(t, [][]string{
+		{"Web/GitHub", "web/github"},
+		{cafeNFD, cafeNFC},
+	}, nameCollisions([]string{"web/github", cafeNFC, "web/gitlab", "Web/GitHub", cafeNFD}))
+	assert.Empty(t, nameCollisions([]string{"foo", "bar"}))
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithExportKeys(ctx, false)
+
+	td := t.TempDir()
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+	s := &Store{
+		alias:   "",
+		path:    td,
+		crypto:  plain.New(),
+		storage: fs.New(td),
+	}
+	require.NoError(t, s.saveRecipients(ctx, []string{"john.doe"}, "test"))
+
+	sec := secrets.New()
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "Web/GitHub", sec))
+	require.NoError(t, s.Set(ctx, cafeNFD, sec))
+
+	// names are used as they are by default
+	assert.False(t, s.Exists(ctx, "web/github"))
+	_, err := s.Get(ctx, "web/github")
+	assert.Error(t, err)
+
+	s.SetConfig(config.StoreConfig{CaseInsensitive: true})
+	assert.True(t, s.Exists(ctx, "web/github"))
+	got, err := s.Get(ctx, "WEB/github")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got.Password())
+	assert.True(t, s.Exists(ctx, cafeNFC))
+
+	// updates go to the existing secret
+	sec.SetPassword("bar")
+	require.NoError(t, s.Set(ctx, "web/github", sec))
+	lst, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Web/GitHub", cafeNFD}, lst)
+
+	// new names are normalized
+	require.NoError(t, s.Set(ctx, "te\u0301l", sec))
+	assert.True(t, s.storage.Exists(ctx, s.passfile("t\u00e9l")))
+
+	require.NoError(t, s.Delete(ctx, "web/GITHUB"))
+	assert.False(t, s.Exists(ctx, "Web/GitHub"))
+}
+
+type walkCounter struct {
+	backend.Storage
+	walks int
+}
+
+func (w *walkCounter) Walk(ctx context.Context, prefix string, fn func(string) error) error {
+	w.walks++
+	return w.Storage.Walk(ctx, prefix, fn)
+}
+
+func TestCaseInsensitiveIndex(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithExportKeys(ctx, false)
+
+	td := t.TempDir()
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+	wc := &walkCounter{Storage: fs.New(td)}
+	s := &Store{
+		alias:   "",
+		path:    td,
+		crypto:  plain.New(),
+		storage: wc,
+		cfg:     config.StoreConfig{CaseInsensitive: true},
+	}
+	require.NoError(t, s.saveRecipients(ctx, []string{"john.doe"}, "test"))
+
+	sec := secrets.New()
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "Web/GitHub", sec))
+
+	// misses walk the store once
+	wc.walks = 0
+	for _, name := range []string{"web/github", "foo", "bar", "WEB/GITHUB"} {
+		s.Exists(ctx, name)
+	}
+	assert.Equal(t, 1, wc.walks)
+
+	// new secrets are found
+	require.NoError(t, s.Set(ctx, "Web/GitLab", sec))
+	assert.True(t, s.Exists(ctx, "web/gitlab"))
+
+	// removed ones aren't
+	require.NoError(t, s.Delete(ctx, "web/gitlab"))
+	assert.False(t, s.Exists(ctx, "web/gitlab"))
+}
+
+func TestLegacyNames(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithExportKeys(ctx, false)
+
+	td := t.TempDir()
+	t.Setenv("GOPASS_HOMEDIR", t.TempDir())
+	s := &Store{
+		alias:   "",
+		path:    td,
+		crypto:  plain.New(),
+		storage: fs.New(td),
+	}
+	require.NoError(t, s.saveRecipients(ctx, []string{"john.doe"}, "test"))
+
+	// secrets with names that can't be created anymore must still be
+	// readable and removable.
+	name := "legacy/na\tme"
+	require.ErrorIs(t, store.ValidateName(name), store.ErrInvalidName)
+	buf, err := s.crypto.Encrypt(ctx, []byte("foo\n"), []string{"john.doe"})
+	require.NoError(t, err)
+	require.NoError(t, s.storage.Set(ctx, s.passfile(name), buf))
+
+	sec, err := s.Get(ctx, name)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", sec.Password())
+
+	require.NoError(t, s.Delete(ctx, name))
+	assert.False(t, s.Exists(ctx, name))
+}
+
+func TestValidateName(t *testing.T) {
+	s := &Store{
+		path:   "/tmp/store",
+		crypto: plain.New(),
+	}
+
+	assert.NoError(t, s.validateName("foo/bar"))
+	assert.ErrorIs(t, s.validateName("../foo"), store.ErrInvalidName)
+	// the extension must fit as well
+	assert.ErrorIs(t, s.validateName(strings.Repeat("a", store.MaxElemLen-1)), store.ErrInvalidName)
+	assert.ErrorIs(t, s.validateName(strings.Repeat("a/", 4096)+"a"), store.ErrInvalidName)
+}
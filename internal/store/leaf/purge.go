@@ -0,0 +1,73 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// purger is implemented by storage backends that can rewrite their history.
+type purger interface {
+	Purge(ctx context.Context, paths ...string) (string, error)
+	ForcePush(ctx context.Context, remote, branch string) error
+}
+
+// Purge removes deleted secrets from every revision of the store. The secrets
+// must not exist in the current revision.
+func (s *Store) Purge(ctx context.Context, names ...string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	p, ok := s.storage.(purger)
+	if !ok {
+		return fmt.Errorf("storage backend %s can not purge history: %w", s.storage.Name(), backend.ErrNotSupported)
+	}
+
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		if err := s.validateName(name); err != nil {
+			return err
+		}
+		if s.Exists(ctx, name) {
+			return fmt.Errorf("%s still exists. Delete it first", name)
+		}
+		paths = append(paths, s.passfile(name))
+	}
+
+	head, err := p.Purge(ctx, paths...)
+	if err != nil {
+		return err
+	}
+	debug.Log("purged %v, former HEAD was %s", paths, head)
+
+	return nil
+}
+
+// ForcePush replaces the history of the remote and of all mirrors with the
+// local one, e.g. after purging secrets.
+func (s *Store) ForcePush(ctx context.Context) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	p, ok := s.storage.(purger)
+	if !ok {
+		return fmt.Errorf("storage backend %s can not force push: %w", s.storage.Name(), backend.ErrNotSupported)
+	}
+
+	err = p.ForcePush(ctx, "", "")
+	for _, remote := range s.Mirrors() {
+		if merr := p.ForcePush(ctx, remote, ""); merr != nil {
+			return fmt.Errorf("failed to push to mirror %s: %w", remote, merr)
+		}
+	}
+
+	return err
+}
@@ -36,12 +36,27 @@ func (s *Store) GetRevision(ctx context.Context, name, revision string) (gopass.
 		return nil, fmt.Errorf("failed to get ciphertext of %q@%q: %w", name, revision, err)
 	}
 
+	if err := s.checkBreakGlass(ctx, name); err != nil {
+		return nil, err
+	}
+
+	if err := s.unlockFIDO2(ctx); err != nil {
+		debug.Log("Decryption failed: %s", err)
+		return nil, store.ErrDecrypt
+	}
+
 	content, err := s.crypto.Decrypt(ctx, ciphertext)
 	if err != nil {
 		debug.Log("Decryption failed: %s", err)
 		return nil, store.ErrDecrypt
 	}
 
+	content, err = s.openFIDO2(ctx, content)
+	if err != nil {
+		debug.Log("Decryption failed: %s", err)
+		return nil, store.ErrDecrypt
+	}
+
 	sec, err := secparse.Parse(content)
 	if err != nil {
 		debug.Log("Failed to parse YAML: %s", err)
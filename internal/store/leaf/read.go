@@ -3,6 +3,7 @@ package leaf
 import (
 	"context"
 
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
@@ -14,6 +15,8 @@ import (
 
 // Get returns the plaintext of a single key.
 func (s *Store) Get(ctx context.Context, name string) (gopass.Secret, error) {
+	ctx = s.withConfig(ctx)
+	name = s.resolveName(ctx, name)
 	p := s.passfile(name)
 
 	ciphertext, err := s.storage.Get(ctx, p)
@@ -22,12 +25,33 @@ func (s *Store) Get(ctx context.Context, name string) (gopass.Secret, error) {
 		return nil, store.ErrNotFound
 	}
 
+	if err := s.checkBreakGlass(ctx, name); err != nil {
+		return nil, err
+	}
+
+	// if the recipients are hidden the crypto backend has to guess which
+	// key to use. Limit that to the keys this secret should be encrypted for.
+	if rs, err := s.GetRecipients(ctx, name); err == nil {
+		ctx = gpg.WithTrySecretKeys(ctx, rs)
+	}
+
+	if err := s.unlockFIDO2(ctx); err != nil {
+		out.Errorf(ctx, "Decryption failed: %s", err)
+		return nil, store.ErrDecrypt
+	}
+
 	content, err := s.crypto.Decrypt(ctx, ciphertext)
 	if err != nil {
 		out.Errorf(ctx, "Decryption failed: %s\n%s", err, string(content))
 		return nil, store.ErrDecrypt
 	}
 
+	content, err = s.openFIDO2(ctx, content)
+	if err != nil {
+		out.Errorf(ctx, "Decryption failed: %s", err)
+		return nil, store.ErrDecrypt
+	}
+
 	if !ctxutil.IsShowParsing(ctx) {
 		return secrets.ParsePlain(content), nil
 	}
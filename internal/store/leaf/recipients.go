@@ -8,11 +8,13 @@ import (
 	"strings"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/recipients"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/termio"
 )
 
 const (
@@ -58,6 +60,12 @@ func (s *Store) RecipientsTree(ctx context.Context) map[string][]string {
 
 // AddRecipient adds a new recipient to the list.
 func (s *Store) AddRecipient(ctx context.Context, id string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	rs, err := s.GetRecipients(ctx, "")
 	if err != nil {
 		return fmt.Errorf("failed to read recipient list: %w", err)
@@ -65,7 +73,7 @@ func (s *Store) AddRecipient(ctx context.Context, id string) error {
 
 	debug.Log("new recipient: %q - existing: %+v", id, rs)
 	for _, k := range rs {
-		if k == id {
+		if k == id || gpg.SameKey(k, id) {
 			return fmt.Errorf("recipient already in store")
 		}
 	}
@@ -82,6 +90,12 @@ func (s *Store) AddRecipient(ctx context.Context, id string) error {
 
 // SaveRecipients persists the current recipients on disk.
 func (s *Store) SaveRecipients(ctx context.Context) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	rs, err := s.GetRecipients(ctx, "")
 	if err != nil {
 		return fmt.Errorf("failed to get recipients: %w", err)
@@ -91,6 +105,12 @@ func (s *Store) SaveRecipients(ctx context.Context) error {
 
 // SetRecipients will update the stored recipients and the associated checksum.
 func (s *Store) SetRecipients(ctx context.Context, rs []string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	return s.saveRecipients(ctx, rs, "Set Recipients")
 }
 
@@ -98,6 +118,12 @@ func (s *Store) SetRecipients(ctx context.Context, rs []string) error {
 // but if this key is not available on this machine we
 // just try to remove it literally.
 func (s *Store) RemoveRecipient(ctx context.Context, id string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	keys, err := s.crypto.FindRecipients(ctx, id)
 	if err != nil {
 		out.Printf(ctx, "Warning: Failed to get GPG Key Info for %s: %s", id, err)
@@ -117,7 +143,7 @@ RECIPIENTS:
 		// if the key is available locally we can also match the id against
 		// the fingerprint
 		for _, key := range keys {
-			if strings.HasSuffix(key, k) {
+			if gpg.SameKey(key, k) {
 				continue RECIPIENTS
 			}
 		}
@@ -125,7 +151,7 @@ RECIPIENTS:
 	}
 
 	if len(rs) == len(nk) {
-		return fmt.Errorf("recipient not in store")
+		return store.ErrRecipientNotFound
 	}
 
 	if err := s.saveRecipients(ctx, nk, "Removed Recipient "+id); err != nil {
@@ -141,7 +167,7 @@ func (s *Store) ensureOurKeyID(ctx context.Context, rs []string) []string {
 		return rs
 	}
 	for _, r := range rs {
-		if r == ourID {
+		if r == ourID || gpg.SameKey(r, ourID) {
 			return rs
 		}
 	}
@@ -152,7 +178,18 @@ func (s *Store) ensureOurKeyID(ctx context.Context, rs []string) []string {
 // OurKeyID returns the key fingprint this user can use to access the store
 // (if any).
 func (s *Store) OurKeyID(ctx context.Context) string {
-	for _, r := range s.Recipients(ctx) {
+	rs := s.Recipients(ctx)
+	// try to resolve all recipients at once first. This avoids spawning
+	// one gpg process per recipient for the common case.
+	if len(rs) > 1 {
+		if kl, err := s.crypto.FindIdentities(ctx, rs...); err == nil {
+			if len(kl) < 1 {
+				return ""
+			}
+			return kl[0]
+		}
+	}
+	for _, r := range rs {
 		kl, err := s.crypto.FindIdentities(ctx, r)
 		if err != nil || len(kl) < 1 {
 			continue
@@ -184,6 +221,12 @@ type keyExporter interface {
 // ExportMissingPublicKeys will export any possibly missing public keys to the
 // stores .public-keys directory.
 func (s *Store) ExportMissingPublicKeys(ctx context.Context, rs []string) (bool, error) {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
 	exp, ok := s.crypto.(keyExporter)
 	if !ok {
 		debug.Log("not exporting public keys for %T", s.crypto)
@@ -273,3 +316,288 @@ func (s *Store) saveRecipients(ctx context.Context, rs []string, msg string) err
 
 	return nil
 }
+
+// resolveAllRecipients resolves the ambiguous recipients of every recipients
+// file of the store.
+func (s *Store) resolveAllRecipients(ctx context.Context) error {
+	for _, idf := range s.idFiles(ctx) {
+		if err := s.resolveRecipients(ctx, idf); err != nil {
+			return fmt.Errorf("failed to resolve recipients for %q: %w", idf, err)
+		}
+	}
+	return nil
+}
+
+// resolveRecipients makes sure that every email address in the recipients
+// file idf matches exactly one key. Otherwise the crypto backend would
+// silently pick one. Ambiguous addresses are resolved by preferring keys that
+// are valid according to the trust model of the store or asking the user. The
+// selected key is written back to the recipients file.
+func (s *Store) resolveRecipients(ctx context.Context, idf string) error {
+	ctx = s.withConfig(ctx)
+	rs, err := s.getRecipients(ctx, idf)
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+	for i, r := range rs {
+		if !strings.Contains(r, "@") {
+			continue
+		}
+
+		id, err := s.resolveRecipient(ctx, r)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			continue
+		}
+
+		fp := s.crypto.Fingerprint(ctx, id)
+		out.Noticef(ctx, "Replacing ambiguous recipient %s with %s in %s", r, fp, idf)
+		rs[i] = fp
+		changed = append(changed, r)
+	}
+
+	if len(changed) < 1 {
+		return nil
+	}
+
+	return s.writeRecipients(ctx, idf, rs, "Resolved ambiguous recipients "+strings.Join(changed, ", "))
+}
+
+// resolveRecipient returns the key to use for an ambiguous recipient or an
+// empty string if the recipient is not ambiguous.
+func (s *Store) resolveRecipient(ctx context.Context, r string) (string, error) {
+	candidates, err := s.crypto.FindRecipients(gpg.WithAlwaysTrust(ctx, true), r)
+	if err != nil || len(candidates) < 2 {
+		return "", nil
+	}
+	debug.Log("recipient %s is ambiguous: %+v", r, candidates)
+
+	// prefer keys that are valid (e.g. not expired or revoked) according to
+	// the trust model of the store
+	if valid, err := s.crypto.FindRecipients(ctx, r); err == nil && len(valid) > 0 {
+		if len(valid) == 1 {
+			return valid[0], nil
+		}
+		candidates = valid
+	}
+
+	if !ctxutil.IsInteractive(ctx) && !termio.HasPrompter(ctx) {
+		return "", fmt.Errorf("recipient %s matches multiple keys (%s). Please replace it with the fingerprint of the right one", r, strings.Join(candidates, ", "))
+	}
+
+	choices := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		choices = append(choices, s.crypto.FormatKey(ctx, c, ""))
+	}
+	sel, err := termio.GetPrompter(ctx).Select(ctx, fmt.Sprintf("Recipient %s matches multiple keys. Which one should be used?", r), choices)
+	if err != nil {
+		return "", fmt.Errorf("failed to select key for %s: %w", r, err)
+	}
+	if sel < 0 || sel >= len(candidates) {
+		return "", fmt.Errorf("invalid selection")
+	}
+
+	return candidates[sel], nil
+}
+
+// writeRecipients writes the recipients file and commits it.
+func (s *Store) writeRecipients(ctx context.Context, idf string, rs []string, msg string) error {
+	if err := s.storage.Set(ctx, idf, recipients.Marshal(rs)); err != nil {
+		return fmt.Errorf("failed to write recipients file: %w", err)
+	}
+
+	if err := s.storage.Add(ctx, idf); err != nil {
+		if !errors.Is(err, store.ErrGitNotInit) {
+			return fmt.Errorf("failed to add file %q to git: %w", idf, err)
+		}
+	}
+
+	if err := s.storage.Commit(ctx, msg); err != nil {
+		if !errors.Is(err, store.ErrGitNotInit) && !errors.Is(err, store.ErrGitNothingToCommit) {
+			return fmt.Errorf("failed to commit changes to git: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// MigrateRecipients replaces any recipient in any recipients file of this
+// store that is not a full fingerprint (e.g. a short key ID or an email) with
+// the fingerprint of the key it resolves to. Recipients that can not be
+// resolved unambiguously are left alone. It returns the number of replaced
+// recipients.
+func (s *Store) MigrateRecipients(ctx context.Context) (int, error) {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	idfs := append([]string{s.idFile(ctx, "")}, s.idFiles(ctx)...)
+	seen := make(map[string]bool, len(idfs))
+
+	var migrated int
+	for _, idf := range idfs {
+		if seen[idf] {
+			continue
+		}
+		seen[idf] = true
+
+		rs, err := s.getRecipients(ctx, idf)
+		if err != nil {
+			return migrated, err
+		}
+
+		var changed bool
+		for i, r := range rs {
+			fp := s.canonicalRecipient(ctx, r)
+			if fp == "" || fp == r {
+				continue
+			}
+			debug.Log("migrating recipient %s to %s in %s", r, fp, idf)
+			out.Noticef(ctx, "Replacing recipient %s with %s in %s", r, fp, idf)
+			rs[i] = fp
+			changed = true
+			migrated++
+		}
+
+		if !changed {
+			continue
+		}
+		if err := s.writeRecipients(ctx, idf, rs, "Migrated recipients to fingerprints"); err != nil {
+			return migrated, err
+		}
+	}
+
+	return migrated, nil
+}
+
+// DeauthorizeRecipient removes the recipient from all recipients files of this
+// store and re-encrypts every secret it could read. It returns the names of
+// these secrets since they should be rotated.
+func (s *Store) DeauthorizeRecipient(ctx context.Context, id string) ([]string, error) {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	keys, err := s.crypto.FindRecipients(ctx, id)
+	if err != nil {
+		debug.Log("failed to get key info for %s: %s", id, err)
+	}
+	matches := func(r string) bool {
+		if r == id || gpg.SameKey(r, id) {
+			return true
+		}
+		for _, key := range keys {
+			if gpg.SameKey(key, r) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// collect the secrets the recipient can read before anything is changed
+	acc, err := s.Access(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	var exposed []string
+	isExposed := make(map[string]bool, len(acc))
+	for _, a := range acc {
+		readers := a.Readers
+		if a.Hidden {
+			readers = a.Recipients
+		}
+		for _, r := range readers {
+			if matches(r) {
+				exposed = append(exposed, a.Name)
+				isExposed[a.Name] = true
+				break
+			}
+		}
+	}
+
+	idfs := append([]string{s.idFile(ctx, "")}, s.idFiles(ctx)...)
+	changed := make(map[string]bool, len(idfs))
+	for _, idf := range idfs {
+		if _, seen := changed[idf]; seen {
+			continue
+		}
+		changed[idf] = false
+
+		rs, err := s.getRecipients(ctx, idf)
+		if err != nil {
+			return nil, err
+		}
+
+		nk := make([]string, 0, len(rs))
+		for _, r := range rs {
+			if !matches(r) {
+				nk = append(nk, r)
+			}
+		}
+		if len(nk) == len(rs) {
+			continue
+		}
+		if len(nk) < 1 {
+			return nil, fmt.Errorf("can not remove the only recipient of %s", idf)
+		}
+
+		debug.Log("removing %s from %s", id, idf)
+		if err := s.writeRecipients(ctx, idf, nk, "Deauthorized Recipient "+id); err != nil {
+			return nil, err
+		}
+		changed[idf] = true
+	}
+
+	// re-encrypt everything below a changed recipients file and anything
+	// else that is still readable by the recipient
+	entries := make([]string, 0, len(acc))
+	for _, a := range acc {
+		name := strings.TrimPrefix(a.Name, s.alias+Sep)
+		if changed[s.idFile(ctx, name)] || isExposed[a.Name] {
+			entries = append(entries, name)
+		}
+	}
+	if len(entries) < 1 {
+		return nil, store.ErrRecipientNotFound
+	}
+
+	out.Printf(ctx, "Reencrypting %d secrets. This may take some time ...", len(entries))
+	if err := s.reencryptEntries(ctxutil.WithCommitMessage(ctx, "Deauthorized Recipient "+id), entries); err != nil {
+		return nil, err
+	}
+
+	return exposed, nil
+}
+
+// canonicalRecipient returns the fingerprint of the only key matching r or an
+// empty string if there is no such key.
+func (s *Store) canonicalRecipient(ctx context.Context, r string) string {
+	if gpg.IsFingerprint(r) {
+		return r
+	}
+
+	kl, err := s.crypto.FindRecipients(ctx, r)
+	if err != nil {
+		debug.Log("failed to look up recipient %s: %s", r, err)
+		return ""
+	}
+
+	switch len(kl) {
+	case 0:
+		out.Warningf(ctx, "Recipient %s not found. Can not migrate it.", r)
+		return ""
+	case 1:
+		return s.crypto.Fingerprint(ctx, kl[0])
+	default:
+		out.Warningf(ctx, "Recipient %s matches multiple keys (%s). Please replace it manually.", r, strings.Join(kl, ", "))
+		return ""
+	}
+}
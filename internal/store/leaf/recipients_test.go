@@ -11,10 +11,15 @@ import (
 	"testing"
 
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
 	plain "github.com/gopasspw/gopass/internal/backend/crypto/plain"
 	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/backend/storage/gitfs"
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -244,3 +249,277 @@ func TestListRecipients(t *testing.T) {
 
 	assert.Equal(t, "0xDEADBEEF", s.OurKeyID(ctx))
 }
+
+type ambiguousMocker struct {
+	*plain.Mocker
+	keys  []string
+	valid []string
+}
+
+func (a *ambiguousMocker) FindRecipients(ctx context.Context, keys ...string) ([]string, error) {
+	if len(keys) != 1 || !strings.Contains(keys[0], "@") {
+		return a.Mocker.FindRecipients(ctx, keys...)
+	}
+	if !gpg.IsAlwaysTrust(ctx) {
+		return a.valid, nil
+	}
+	return a.keys, nil
+}
+
+type selectPrompter struct {
+	choices []string
+	sel     int
+}
+
+func (p *selectPrompter) Name() string { return "test" }
+func (p *selectPrompter) String(_ context.Context, _, def string) (string, error) {
+	return def, nil
+}
+func (p *selectPrompter) Bool(_ context.Context, _ string, def bool) (bool, error) {
+	return def, nil
+}
+func (p *selectPrompter) Password(context.Context, string) (string, error) {
+	return "", termio.ErrNoInteraction
+}
+func (p *selectPrompter) Select(_ context.Context, _ string, choices []string) (int, error) {
+	p.choices = choices
+	return p.sel, nil
+}
+
+func TestResolveRecipients(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	for _, tc := range []struct {
+		name   string
+		keys   []string
+		valid  []string
+		trust  string
+		prompt *selectPrompter
+		want   string
+		err    bool
+	}{
+		{
+			name:  "single match",
+			keys:  []string{"AAAA"},
+			valid: []string{"AAAA"},
+			want:  "john@example.com",
+		},
+		{
+			name:  "only one valid key",
+			keys:  []string{"AAAA", "BBBB"},
+			valid: []string{"BBBB"},
+			want:  "BBBB",
+		},
+		{
+			name:  "store trusts all keys",
+			keys:  []string{"AAAA", "BBBB"},
+			valid: []string{"BBBB"},
+			trust: gpg.TrustAlways,
+			err:   true,
+		},
+		{
+			name:  "ambiguous in batch mode",
+			keys:  []string{"AAAA", "BBBB"},
+			valid: []string{"AAAA", "BBBB"},
+			err:   true,
+		},
+		{
+			name:   "ambiguous with prompt",
+			keys:   []string{"AAAA", "BBBB", "CCCC"},
+			valid:  []string{"AAAA", "CCCC"},
+			prompt: &selectPrompter{sel: 1},
+			want:   "CCCC",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tempdir := t.TempDir()
+			s := &Store{
+				alias:   "",
+				path:    tempdir,
+				crypto:  &ambiguousMocker{Mocker: plain.New(), keys: tc.keys, valid: tc.valid},
+				storage: fs.New(tempdir),
+				cfg:     config.StoreConfig{TrustModel: tc.trust},
+			}
+			require.NoError(t, s.storage.Set(ctx, s.idFile(ctx, ""), []byte("0xDEADBEEF\njohn@example.com\n")))
+
+			ctx := ctxutil.WithInteractive(ctx, false)
+			if tc.prompt != nil {
+				ctx = termio.WithPrompter(ctx, tc.prompt)
+			}
+
+			err := s.resolveRecipients(ctx, s.idFile(ctx, "foo"))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			rs, err := s.GetRecipients(ctx, "foo")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"0xDEADBEEF", tc.want}, rs)
+
+			if tc.prompt != nil {
+				assert.Equal(t, tc.valid, tc.prompt.choices)
+			}
+		})
+	}
+}
+
+type fingerprintMocker struct {
+	*plain.Mocker
+	keys map[string][]string
+}
+
+func (f *fingerprintMocker) FindRecipients(ctx context.Context, keys ...string) ([]string, error) {
+	var res []string
+	for _, k := range keys {
+		res = append(res, f.keys[k]...)
+	}
+	return res, nil
+}
+
+func TestMigrateRecipients(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	tempdir := t.TempDir()
+	fp1 := "25FF1614B8F87B52FFFF99B962AF4031C82E0039"
+	fp2 := "A5B6C7D8E9F0A1B2C3D4E5F6A7B8C9D0C82E0039"
+	fp3 := "A5B6C7D8E9F0A1B2C3D4E5F6A7B8C9D0DEADBEEF"
+	s := &Store{
+		alias: "",
+		path:  tempdir,
+		crypto: &fingerprintMocker{
+			Mocker: plain.New(),
+			keys: map[string][]string{
+				"0x62AF4031C82E0039": {fp1},
+				"0xC82E0039":         {fp1, fp2},
+				"jane@example.org":   {fp3},
+			},
+		},
+		storage: fs.New(tempdir),
+	}
+	idf := s.idFile(ctx, "")
+	require.NoError(t, s.storage.Set(ctx, idf, []byte("0x62AF4031C82E0039\n"+fp2+"\n")))
+	require.NoError(t, s.storage.Set(ctx, filepath.Join("foo", idf), []byte("0xC82E0039\njane@example.org\nunknown\n")))
+	require.NoError(t, s.storage.Set(ctx, s.passfile("foo/bar"), []byte("bar")))
+
+	n, err := s.MigrateRecipients(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	rs, err := s.GetRecipients(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{fp1, fp2}, rs)
+
+	rs, err = s.GetRecipients(ctx, "foo/bar")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0xC82E0039", fp3, "unknown"}, rs)
+
+	n, err = s.MigrateRecipients(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestDeauthorizeRecipient(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+	ctx = ctxutil.WithExportKeys(ctx, false)
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	tempdir := t.TempDir()
+	s := &Store{
+		alias:   "",
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+	idf := s.idFile(ctx, "")
+	require.NoError(t, s.storage.Set(ctx, idf, []byte("0xDEADBEEF\n0xFEEDBEEF\n")))
+	require.NoError(t, s.storage.Set(ctx, filepath.Join("team", idf), []byte("0xFEEDBEEF\n0xCAFEBABE\n")))
+	for _, name := range []string{"foo", "team/bar"} {
+		require.NoError(t, s.storage.Set(ctx, s.passfile(name), []byte("secret")))
+	}
+
+	// the plain backend claims every secret is readable by 0xFEEDBEEF
+	exposed, err := s.DeauthorizeRecipient(ctx, "0xFEEDBEEF")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "team/bar"}, exposed)
+
+	rs, err := s.GetRecipients(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0xDEADBEEF"}, rs)
+
+	rs, err = s.GetRecipients(ctx, "team/bar")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0xCAFEBABE"}, rs)
+
+	// the only recipient of a folder can not be removed
+	_, err = s.DeauthorizeRecipient(ctx, "0xCAFEBABE")
+	assert.Error(t, err)
+
+	_, err = s.DeauthorizeRecipient(ctx, "0x12345678")
+	assert.ErrorIs(t, err, store.ErrRecipientNotFound)
+}
+
+func TestDeauthorizeRecipientMount(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+	ctx = ctxutil.WithExportKeys(ctx, false)
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	tempdir := t.TempDir()
+	g, err := gitfs.Init(ctxutil.WithGitInit(ctx, true), tempdir, "gopass", "gopass@example.org")
+	require.NoError(t, err)
+
+	be := &batchMocker{Mocker: plain.New()}
+	s := &Store{
+		alias:   "work",
+		path:    tempdir,
+		crypto:  be,
+		storage: g,
+	}
+	idf := s.idFile(ctx, "")
+	require.NoError(t, s.storage.Set(ctx, idf, []byte("0xDEADBEEF\n0xFEEDBEEF\n")))
+	// a folder named like the mount point must not be confused with it
+	for _, name := range []string{"foo", "work/bar"} {
+		require.NoError(t, s.storage.Set(ctx, s.passfile(name), []byte("secret")))
+	}
+	require.NoError(t, g.Add(ctx, "."))
+	require.NoError(t, g.Commit(ctx, "add secrets"))
+
+	exposed, err := s.DeauthorizeRecipient(ctx, "0xFEEDBEEF")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work/foo", "work/work/bar"}, exposed)
+	assert.Equal(t, 1, be.calls)
+
+	// the re-encrypted secrets were committed.
+	assert.False(t, g.HasStagedChanges(ctx))
+	assert.Empty(t, g.ListUntrackedFiles(ctx))
+
+	rs, err := s.GetRecipients(ctx, "work/bar")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0xDEADBEEF"}, rs)
+}
@@ -9,13 +9,19 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/gopasspw/gopass/internal/backend"
 	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/internal/set"
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
 	"github.com/gopasspw/gopass/pkg/termio"
 )
 
+// reencryptBatchSize limits the number of secrets that are held in memory
+// and passed to the crypto backend at once.
+const reencryptBatchSize = 100
+
 // nolint:ifshort
 // reencrypt will re-encrypt all entries for the current recipients.
 func (s *Store) reencrypt(ctx context.Context) error {
@@ -24,12 +30,35 @@ func (s *Store) reencrypt(ctx context.Context) error {
 		return fmt.Errorf("failed to list store: %w", err)
 	}
 
+	// List includes the mount point, the storage backend is relative to it.
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimPrefix(e, s.alias+Sep))
+	}
+
+	return s.reencryptEntries(ctx, names)
+}
+
+// reencryptEntries will re-encrypt the given entries for their current
+// recipients. The entries must be relative to the store, i.e. not include
+// the mount point.
+func (s *Store) reencryptEntries(ctx context.Context, entries []string) error {
+	ctx = s.withConfig(ctx)
+	// the content is written back encrypted, it's never revealed.
+	ctx = withBreakGlassExempt(ctx)
+
+	s.TryUnlock(ctx)
+
 	// Most gnupg setups don't work well with concurrency > 1, but
 	// for other backends - e.g. age - this could very well be > 1.
 	conc := s.crypto.Concurrency()
 
-	// save original value of auto push
-	{
+	be, batch := s.crypto.(backend.BatchEncrypter)
+	if batch {
+		if err := s.reencryptBatch(ctxutil.WithGitCommit(ctx, false), be, entries); err != nil {
+			return err
+		}
+	} else {
 		// shadow ctx in this block only
 		ctx := ctxutil.WithGitCommit(ctx, false)
 
@@ -77,7 +106,6 @@ func (s *Store) reencrypt(ctx context.Context) error {
 				bar.Inc()
 			}
 
-			e = strings.TrimPrefix(e, s.alias)
 			jobs <- e
 		}
 		// We close the channel, so the workers will terminate
@@ -87,9 +115,10 @@ func (s *Store) reencrypt(ctx context.Context) error {
 		bar.Done()
 	}
 
-	// if we were working concurrently, we couldn't git add during the process
-	// to avoid a race condition on git .index.lock file, so we do it now.
-	if conc > 1 {
+	// if we were working concurrently or in batches, we couldn't git add during
+	// the process to avoid a race condition on git .index.lock file, so we do
+	// it now.
+	if conc > 1 || batch {
 		for _, name := range entries {
 			p := s.passfile(name)
 			if err := s.storage.Add(ctx, p); err != nil {
@@ -117,6 +146,93 @@ func (s *Store) reencrypt(ctx context.Context) error {
 	return s.reencryptGitPush(ctx)
 }
 
+// reencryptBatch re-encrypts all entries using as few invocations of the
+// crypto backend as possible. Entries sharing the same recipients file are
+// encrypted together.
+func (s *Store) reencryptBatch(ctx context.Context, be backend.BatchEncrypter, entries []string) error {
+	bar := termio.NewProgressBar(int64(len(entries)))
+	bar.Hidden = !ctxutil.IsTerminal(ctx) || ctxutil.IsHidden(ctx)
+	out.Printf(ctx, "Starting reencrypt")
+
+	groups := make(map[string][]string, 1)
+	order := make([]string, 0, 1)
+	for _, e := range entries {
+		idf := s.idFile(ctx, e)
+		if _, found := groups[idf]; !found {
+			order = append(order, idf)
+		}
+		groups[idf] = append(groups[idf], e)
+	}
+
+	ourID := s.OurKeyID(ctx)
+	for _, idf := range order {
+		names := groups[idf]
+		if err := s.resolveRecipients(ctx, idf); err != nil {
+			return fmt.Errorf("failed to resolve recipients for %q: %w", idf, err)
+		}
+		recipients, err := s.useableKeys(ctx, names[0])
+		if err != nil {
+			return fmt.Errorf("failed to list useable keys for %q: %w", idf, err)
+		}
+		if ourID != "" && !set.Map(recipients)[ourID] {
+			recipients = append(recipients, ourID)
+		}
+
+		for len(names) > 0 {
+			n := reencryptBatchSize
+			if n > len(names) {
+				n = len(names)
+			}
+			if err := s.reencryptChunk(ctx, be, names[:n], recipients, bar); err != nil {
+				return err
+			}
+			names = names[n:]
+		}
+	}
+	bar.Done()
+
+	return nil
+}
+
+func (s *Store) reencryptChunk(ctx context.Context, be backend.BatchEncrypter, names, recipients []string, bar *termio.ProgressBar) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context canceled")
+	}
+
+	plaintexts := make(map[string][]byte, len(names))
+	for _, name := range names {
+		content, err := s.Get(ctx, name)
+		if err != nil {
+			out.Errorf(ctx, "Failed to get current value for %s: %s", name, err)
+			continue
+		}
+		plaintext, err := s.sealFIDO2(ctx, content.Bytes())
+		if err != nil {
+			return fmt.Errorf("failed to protect %q: %w", name, err)
+		}
+		plaintexts[name] = plaintext
+	}
+
+	ciphertexts, err := be.EncryptBatch(ctx, plaintexts, recipients)
+	if err != nil {
+		debug.Log("Failed to encrypt batch: %s", err)
+		return store.ErrEncrypt
+	}
+
+	for _, name := range names {
+		bar.Inc()
+		ct, found := ciphertexts[name]
+		if !found {
+			continue
+		}
+		if err := s.storage.Set(ctx, s.passfile(name), ct); err != nil {
+			return fmt.Errorf("failed to write secret: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (s *Store) reencryptGitPush(ctx context.Context) error {
 	if err := s.storage.Push(ctx, "", ""); err != nil {
 		if errors.Is(err, store.ErrGitNotInit) {
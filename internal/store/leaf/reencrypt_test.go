@@ -0,0 +1,118 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/backend/storage/gitfs"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchMocker struct {
+	*plain.Mocker
+	calls int
+}
+
+func (b *batchMocker) EncryptBatch(ctx context.Context, plaintexts map[string][]byte, recipients []string) (map[string][]byte, error) {
+	b.calls++
+	res := make(map[string][]byte, len(plaintexts))
+	for k, v := range plaintexts {
+		res[k] = v
+	}
+	return res, nil
+}
+
+func TestReencryptBatch(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	tempdir := t.TempDir()
+	_, entries, err := createStore(tempdir, nil, []string{"foo/bar", "foo/baz", "zab"})
+	require.NoError(t, err)
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	be := &batchMocker{Mocker: plain.New()}
+	s := &Store{
+		alias:   "",
+		path:    tempdir,
+		crypto:  be,
+		storage: fs.New(tempdir),
+	}
+
+	for _, e := range entries {
+		sec := secrets.New()
+		sec.SetPassword(e)
+		require.NoError(t, s.Set(ctx, e, sec))
+	}
+
+	require.NoError(t, s.reencrypt(ctx))
+	assert.Equal(t, 1, be.calls)
+
+	for _, e := range entries {
+		sec, err := s.Get(ctx, e)
+		require.NoError(t, err)
+		assert.Equal(t, e, sec.Password())
+	}
+}
+
+func TestReencryptBatchMount(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gopass")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gopass@example.org")
+	t.Setenv("GIT_COMMITTER_NAME", "gopass")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gopass@example.org")
+
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	tempdir := t.TempDir()
+	_, entries, err := createStore(tempdir, nil, []string{"foo/bar", "zab"})
+	require.NoError(t, err)
+
+	obuf := &bytes.Buffer{}
+	out.Stdout = obuf
+	defer func() {
+		out.Stdout = os.Stdout
+	}()
+
+	g, err := gitfs.Init(ctxutil.WithGitInit(ctx, true), tempdir, "gopass", "gopass@example.org")
+	require.NoError(t, err)
+
+	be := &batchMocker{Mocker: plain.New()}
+	s := &Store{
+		alias:   "work",
+		path:    tempdir,
+		crypto:  be,
+		storage: g,
+	}
+
+	for _, e := range entries {
+		sec := secrets.New()
+		sec.SetPassword(e)
+		require.NoError(t, s.Set(ctx, e, sec))
+	}
+
+	require.NoError(t, s.reencrypt(ctxutil.WithCommitMessage(ctx, "reencrypt")))
+	assert.Equal(t, 1, be.calls)
+
+	// everything was added and committed.
+	assert.False(t, g.HasStagedChanges(ctx))
+
+	for _, e := range entries {
+		sec, err := s.Get(ctx, e)
+		require.NoError(t, err)
+		assert.Equal(t, e, sec.Password())
+	}
+}
@@ -0,0 +1,238 @@
+package leaf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	// sealDir holds the seal of a store. Dot dirs are never listed as
+	// secrets and are not covered by the seal themselves.
+	sealDir      = ".seal"
+	sealManifest = sealDir + "/manifest"
+	sealSig      = sealDir + "/manifest.sig"
+	sealVersion  = "1"
+)
+
+// ErrNotSealed is returned by VerifySeal if the store has no seal.
+var ErrNotSealed = errors.New("store is not sealed")
+
+// SealReport is the result of verifying a seal.
+type SealReport struct {
+	Signer   string
+	Time     time.Time
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// Changed returns true if the store was modified since it was sealed.
+func (r SealReport) Changed() bool {
+	return len(r.Added)+len(r.Removed)+len(r.Modified) > 0
+}
+
+// Seal commits a signed manifest with the hashes of the ciphertext of all
+// secrets, the recipients and the public keys of the store.
+func (s *Store) Seal(ctx context.Context) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	signer, ok := s.crypto.(backend.Signer)
+	if !ok {
+		return fmt.Errorf("crypto backend %s can not sign seals", s.crypto.Name())
+	}
+
+	hashes, err := s.sealHashes(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	manifest := formatSealManifest(now, hashes)
+	sig, err := signer.Sign(ctx, manifest)
+	if err != nil {
+		return fmt.Errorf("failed to sign seal: %w", err)
+	}
+
+	for fn, content := range map[string][]byte{sealManifest: manifest, sealSig: sig} {
+		if err := s.storage.Set(ctx, fn, content); err != nil {
+			return fmt.Errorf("failed to write seal: %w", err)
+		}
+		if err := s.storage.Add(ctx, fn); err != nil {
+			if errors.Is(err, store.ErrGitNotInit) {
+				continue
+			}
+			return fmt.Errorf("failed to add %q to git: %w", fn, err)
+		}
+	}
+
+	if err := s.storage.Commit(ctx, fmt.Sprintf("Sealed %d entries", len(hashes))); err != nil {
+		switch {
+		case errors.Is(err, store.ErrGitNotInit):
+			debug.Log("seal not committed - git not initialized")
+		case errors.Is(err, store.ErrGitNothingToCommit):
+			debug.Log("seal not committed - nothing to commit")
+		default:
+			return fmt.Errorf("failed to commit seal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// VerifySeal checks the signature of the seal and compares the sealed hashes
+// with the current content of the store.
+func (s *Store) VerifySeal(ctx context.Context) (SealReport, error) {
+	r := SealReport{}
+
+	signer, ok := s.crypto.(backend.Signer)
+	if !ok {
+		return r, fmt.Errorf("crypto backend %s can not verify seals", s.crypto.Name())
+	}
+
+	if !s.storage.Exists(ctx, sealManifest) {
+		return r, ErrNotSealed
+	}
+	manifest, err := s.storage.Get(ctx, sealManifest)
+	if err != nil {
+		return r, fmt.Errorf("failed to read seal: %w", err)
+	}
+	sig, err := s.storage.Get(ctx, sealSig)
+	if err != nil {
+		return r, fmt.Errorf("failed to read seal signature: %w", err)
+	}
+
+	r.Signer, err = signer.Verify(ctx, manifest, sig)
+	if err != nil {
+		return r, fmt.Errorf("invalid seal signature: %w", err)
+	}
+
+	var sealed map[string]string
+	r.Time, sealed, err = parseSealManifest(manifest)
+	if err != nil {
+		return r, err
+	}
+
+	current, err := s.sealHashes(ctx)
+	if err != nil {
+		return r, err
+	}
+
+	for fn, sum := range current {
+		ssum, found := sealed[fn]
+		switch {
+		case !found:
+			r.Added = append(r.Added, fn)
+		case ssum != sum:
+			r.Modified = append(r.Modified, fn)
+		}
+	}
+	for fn := range sealed {
+		if _, found := current[fn]; !found {
+			r.Removed = append(r.Removed, fn)
+		}
+	}
+	sort.Strings(r.Added)
+	sort.Strings(r.Removed)
+	sort.Strings(r.Modified)
+
+	return r, nil
+}
+
+// sealHashes returns the SHA256 of every file of the store that is not in a
+// dot dir, plus the public keys.
+func (s *Store) sealHashes(ctx context.Context) (map[string]string, error) {
+	files, err := s.storage.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store: %w", err)
+	}
+	keys, err := s.storage.List(ctx, keyDir+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public keys: %w", err)
+	}
+
+	hashes := make(map[string]string, len(files)+len(keys))
+	for _, fn := range append(files, keys...) {
+		buf, err := s.storage.Get(ctx, fn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", fn, err)
+		}
+		hashes[path.Clean(fn)] = fmt.Sprintf("%x", sha256.Sum256(buf))
+	}
+
+	return hashes, nil
+}
+
+func formatSealManifest(ts time.Time, hashes map[string]string) []byte {
+	names := make([]string, 0, len(hashes))
+	for fn := range hashes {
+		names = append(names, fn)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "gopass-seal: %s\n", sealVersion)
+	fmt.Fprintf(buf, "time: %s\n\n", ts.Format(time.RFC3339))
+	for _, fn := range names {
+		fmt.Fprintf(buf, "%s  %s\n", hashes[fn], fn)
+	}
+
+	return buf.Bytes()
+}
+
+func parseSealManifest(buf []byte) (time.Time, map[string]string, error) {
+	var ts time.Time
+	hashes := make(map[string]string, 64)
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	header := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if header {
+			if line == "" {
+				header = false
+				continue
+			}
+			key, value, _ := strings.Cut(line, ": ")
+			switch key {
+			case "gopass-seal":
+				if value != sealVersion {
+					return ts, nil, fmt.Errorf("unsupported seal version %q", value)
+				}
+			case "time":
+				t, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					return ts, nil, fmt.Errorf("invalid seal time: %w", err)
+				}
+				ts = t
+			}
+			continue
+		}
+
+		sum, fn, found := strings.Cut(line, "  ")
+		if !found {
+			return ts, nil, fmt.Errorf("invalid seal entry %q", line)
+		}
+		hashes[fn] = sum
+	}
+	if header {
+		return ts, nil, fmt.Errorf("invalid seal manifest")
+	}
+
+	return ts, hashes, scanner.Err()
+}
@@ -0,0 +1,93 @@
+package leaf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeal(t *testing.T) {
+	ctx := context.Background()
+
+	tempdir := t.TempDir()
+	s := &Store{
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+
+	for fn, content := range map[string]string{
+		".gpg-id":               "0xDEADBEEF\n",
+		"db/prod.txt":           "secret",
+		"db/dev.txt":            "other",
+		".public-keys/DEADBEEF": "key",
+	} {
+		require.NoError(t, s.storage.Set(ctx, fn, []byte(content)))
+	}
+
+	_, err := s.VerifySeal(ctx)
+	assert.ErrorIs(t, err, ErrNotSealed)
+
+	require.NoError(t, s.Seal(ctx))
+
+	r, err := s.VerifySeal(ctx)
+	require.NoError(t, err)
+	assert.False(t, r.Changed())
+	assert.NotEmpty(t, r.Signer)
+	assert.WithinDuration(t, time.Now(), r.Time, time.Minute)
+
+	// break-glass records and other dot dirs are not covered.
+	require.NoError(t, s.storage.Set(ctx, ".breakglass/db/prod/record.log", []byte("log")))
+	require.NoError(t, s.storage.Set(ctx, "db/prod.txt", []byte("modified")))
+	require.NoError(t, s.storage.Set(ctx, "db/new.txt", []byte("new")))
+	require.NoError(t, s.storage.Delete(ctx, "db/dev.txt"))
+	require.NoError(t, s.storage.Set(ctx, ".public-keys/DEADBEEF", []byte("other key")))
+
+	r, err = s.VerifySeal(ctx)
+	require.NoError(t, err)
+	assert.True(t, r.Changed())
+	assert.Equal(t, []string{"db/new.txt"}, r.Added)
+	assert.Equal(t, []string{"db/dev.txt"}, r.Removed)
+	assert.Equal(t, []string{".public-keys/DEADBEEF", "db/prod.txt"}, r.Modified)
+
+	// a tampered manifest fails the signature check.
+	fn := filepath.Join(tempdir, sealManifest)
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(fn, append(buf, []byte("0000  db/evil.txt\n")...), 0o600))
+	_, err = s.VerifySeal(ctx)
+	assert.Error(t, err)
+}
+
+func TestParseSealManifest(t *testing.T) {
+	ts := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	hashes := map[string]string{
+		"b.txt": "bbbb",
+		"a.txt": "aaaa",
+	}
+
+	buf := formatSealManifest(ts, hashes)
+	assert.Equal(t, "gopass-seal: 1\ntime: 2022-01-02T03:04:05Z\n\naaaa  a.txt\nbbbb  b.txt\n", string(buf))
+
+	gotTS, got, err := parseSealManifest(buf)
+	require.NoError(t, err)
+	assert.Equal(t, ts, gotTS)
+	assert.Equal(t, hashes, got)
+
+	for _, in := range []string{
+		"",
+		"gopass-seal: 2\n\n",
+		"gopass-seal: 1\ntime: yesterday\n\n",
+		"gopass-seal: 1\n\ninvalid\n",
+	} {
+		_, _, err := parseSealManifest([]byte(in))
+		assert.Error(t, err, in)
+	}
+}
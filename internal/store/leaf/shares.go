@@ -0,0 +1,99 @@
+package leaf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// shareExt is the extension of shares. It differs from the extension of
+// secrets so shares are never listed, re-encrypted or shown like secrets.
+const shareExt = ".share"
+
+func shareDir(name string) string {
+	return strings.TrimPrefix(name, "/") + ".shares/"
+}
+
+// SetShares writes the shares of a split secret. Each share is encrypted for
+// its recipient only, regardless of the recipients of the store.
+func (s *Store) SetShares(ctx context.Context, name string, shares map[string][]byte) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ctx = s.withConfig(ctx)
+
+	for id, share := range shares {
+		ciphertext, err := s.crypto.Encrypt(ctx, share, []string{id})
+		if err != nil {
+			debug.Log("Failed to encrypt share for %s: %s", id, err)
+			return store.ErrEncrypt
+		}
+
+		p := shareDir(name) + id + shareExt
+		if err := s.storage.Set(ctx, p, ciphertext); err != nil {
+			return fmt.Errorf("failed to write share: %w", err)
+		}
+		if err := s.storage.Add(ctx, p); err != nil {
+			if errors.Is(err, store.ErrGitNotInit) {
+				continue
+			}
+			return fmt.Errorf("failed to add %q to git: %w", p, err)
+		}
+	}
+
+	if !ctxutil.IsGitCommit(ctx) {
+		return nil
+	}
+
+	return s.gitCommitAndPush(ctx, name)
+}
+
+// Shares returns the recipients holding a share of the given secret.
+func (s *Store) Shares(ctx context.Context, name string) ([]string, error) {
+	lst, err := s.storage.List(ctx, shareDir(name))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(lst))
+	for _, p := range lst {
+		if !strings.HasSuffix(p, shareExt) {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(p, shareDir(name)), shareExt)
+		if strings.Contains(id, "/") {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// GetShare decrypts the share of the given recipient.
+func (s *Store) GetShare(ctx context.Context, name, id string) ([]byte, error) {
+	ctx = s.withConfig(ctx)
+	p := shareDir(name) + id + shareExt
+
+	ciphertext, err := s.storage.Get(ctx, p)
+	if err != nil {
+		debug.Log("File %s not found: %s", p, err)
+		return nil, store.ErrNotFound
+	}
+
+	share, err := s.crypto.Decrypt(ctx, ciphertext)
+	if err != nil {
+		debug.Log("Failed to decrypt share %s: %s", p, err)
+		return nil, store.ErrDecrypt
+	}
+
+	return share, nil
+}
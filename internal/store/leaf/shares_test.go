@@ -0,0 +1,53 @@
+package leaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShares(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithExportKeys(ctx, false)
+
+	tempdir := t.TempDir()
+	s := &Store{
+		alias:   "sub",
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+	require.NoError(t, s.saveRecipients(ctx, []string{"john.doe"}, "test"))
+
+	sec := &secrets.Plain{}
+	sec.SetPassword("bar")
+	require.NoError(t, s.Set(ctx, "foo/bar", sec))
+
+	require.NoError(t, s.SetShares(ctx, "foo/baz", map[string][]byte{
+		"0xDEADBEEF": []byte("one"),
+		"0xFEEDBEEF": []byte("two"),
+	}))
+
+	ids, err := s.Shares(ctx, "foo/baz")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0xDEADBEEF", "0xFEEDBEEF"}, ids)
+
+	share, err := s.GetShare(ctx, "foo/baz", "0xFEEDBEEF")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("two"), share)
+
+	_, err = s.GetShare(ctx, "foo/baz", "0xCAFEBABE")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	// shares are not secrets
+	lst, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sub/foo/bar"}, lst)
+}
@@ -0,0 +1,68 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend"
+)
+
+// snapshotter is implemented by storage backends that can mark and restore
+// revisions of the whole store.
+type snapshotter interface {
+	CreateSnapshot(ctx context.Context, name, msg string) error
+	Snapshots(ctx context.Context) ([]backend.Snapshot, error)
+	Rollback(ctx context.Context, name, msg string) error
+}
+
+func (s *Store) snapshotter() (snapshotter, error) {
+	sn, ok := s.storage.(snapshotter)
+	if !ok {
+		return nil, fmt.Errorf("storage backend %s does not support snapshots: %w", s.storage.Name(), backend.ErrNotSupported)
+	}
+	return sn, nil
+}
+
+// CreateSnapshot marks the current state of the store, so it can be rolled
+// back to it with Rollback.
+func (s *Store) CreateSnapshot(ctx context.Context, name, msg string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	sn, err := s.snapshotter()
+	if err != nil {
+		return err
+	}
+
+	return sn.CreateSnapshot(ctx, name, msg)
+}
+
+// Snapshots returns the snapshots of the store, newest first.
+func (s *Store) Snapshots(ctx context.Context) ([]backend.Snapshot, error) {
+	sn, err := s.snapshotter()
+	if err != nil {
+		return nil, err
+	}
+
+	return sn.Snapshots(ctx)
+}
+
+// Rollback restores all secrets of the store to the state of the snapshot.
+// The rollback is committed but not pushed.
+func (s *Store) Rollback(ctx context.Context, name string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	sn, err := s.snapshotter()
+	if err != nil {
+		return err
+	}
+
+	return sn.Rollback(ctx, name, fmt.Sprintf("Rollback to snapshot %s", name))
+}
@@ -0,0 +1,25 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend"
+)
+
+// syncStatuser is implemented by storage backends that can compare the local
+// state with a remote.
+type syncStatuser interface {
+	SyncStatus(ctx context.Context, fetch bool) (backend.SyncStatus, error)
+}
+
+// SyncStatus returns the sync state of this store. With fetch the remote is
+// queried first.
+func (s *Store) SyncStatus(ctx context.Context, fetch bool) (backend.SyncStatus, error) {
+	ss, ok := s.storage.(syncStatuser)
+	if !ok {
+		return backend.SyncStatus{}, fmt.Errorf("storage backend %s has no remote: %w", s.storage.Name(), backend.ErrNotSupported)
+	}
+
+	return ss.SyncStatus(ctx, fetch)
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/set"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -17,6 +19,19 @@ type Store struct {
 	path    string
 	crypto  backend.Crypto
 	storage backend.Storage
+	cfg     config.StoreConfig
+
+	fido2Mu    sync.Mutex
+	fido2Cache []byte
+
+	breakGlassMu       sync.Mutex
+	breakGlassReason   string
+	breakGlassRecorded map[string]bool
+
+	// folded names of all secrets, see resolveName.
+	namesMu    sync.Mutex
+	namesExt   string
+	namesIndex map[string][]string
 }
 
 // Init initializes this sub store.
@@ -68,6 +83,16 @@ func New(ctx context.Context, alias, path string) (*Store, error) {
 	return s, nil
 }
 
+// SetConfig sets the store specific options.
+func (s *Store) SetConfig(cfg config.StoreConfig) {
+	s.cfg = cfg
+}
+
+// withConfig returns a context with the store specific options set.
+func (s *Store) withConfig(ctx context.Context) context.Context {
+	return s.cfg.WithContext(ctx)
+}
+
 // idFile returns the path to the recipient list for this store
 // it walks up from the given filename until it finds a directory containing
 // a gpg id file or it leaves the scope of storage.
@@ -137,7 +162,7 @@ func (s *Store) IsDir(ctx context.Context, name string) bool {
 
 // Exists checks the existence of a single entry.
 func (s *Store) Exists(ctx context.Context, name string) bool {
-	return s.storage.Exists(ctx, s.passfile(name))
+	return s.storage.Exists(ctx, s.passfile(s.resolveName(ctx, name)))
 }
 
 func (s *Store) useableKeys(ctx context.Context, name string) ([]string, error) {
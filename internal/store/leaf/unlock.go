@@ -0,0 +1,59 @@
+package leaf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Unlock asks for the passphrases of our identities that can decrypt this
+// store, if the crypto backend supports that. Bulk operations call it
+// before they start, so the user is asked once and not for every secret.
+func (s *Store) Unlock(ctx context.Context) error {
+	u, ok := s.crypto.(backend.Unlocker)
+	if !ok {
+		return nil
+	}
+
+	ids, err := s.Identities(ctx)
+	if err != nil {
+		return err
+	}
+	if len(ids) < 1 {
+		return nil
+	}
+
+	return u.Unlock(ctx, ids...)
+}
+
+// TryUnlock calls Unlock and only warns if it fails. That isn't fatal, the
+// crypto backend asks again for each secret.
+func (s *Store) TryUnlock(ctx context.Context) {
+	if err := s.Unlock(ctx); err != nil {
+		out.Warningf(ctx, "Failed to unlock your keys: %s. You might be asked for your passphrase repeatedly.", err)
+	}
+}
+
+// Identities returns those of our identities that are recipients of this
+// store.
+func (s *Store) Identities(ctx context.Context) ([]string, error) {
+	rs, err := s.GetRecipients(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipients: %w", err)
+	}
+	if len(rs) < 1 {
+		return nil, nil
+	}
+	ids, err := s.crypto.FindIdentities(ctx, rs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find identities: %w", err)
+	}
+	if len(ids) < 1 {
+		debug.Log("none of our identities is a recipient of %q", s.alias)
+	}
+
+	return ids, nil
+}
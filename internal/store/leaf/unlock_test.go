@@ -0,0 +1,78 @@
+package leaf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/out"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type unlockMocker struct {
+	*plain.Mocker
+	ids []string
+	err error
+}
+
+func (u *unlockMocker) Unlock(ctx context.Context, ids ...string) error {
+	u.ids = append(u.ids, ids...)
+	return u.err
+}
+
+func TestUnlock(t *testing.T) {
+	ctx := context.Background()
+
+	tempdir := t.TempDir()
+	_, _, err := createStore(tempdir, []string{"DEADBEEF", "0xCAFEBABE"}, nil)
+	require.NoError(t, err)
+
+	s := &Store{
+		path:    tempdir,
+		crypto:  plain.New(),
+		storage: fs.New(tempdir),
+	}
+	// not supported by the backend
+	require.NoError(t, s.Unlock(ctx))
+
+	be := &unlockMocker{Mocker: plain.New()}
+	s.crypto = be
+	require.NoError(t, s.Unlock(ctx))
+	assert.Equal(t, []string{"0xDEADBEEF"}, be.ids)
+
+	// none of our keys is a recipient
+	be.ids = nil
+	_, _, err = createStore(tempdir, []string{"0xCAFEBABE"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.Unlock(ctx))
+	assert.Empty(t, be.ids)
+}
+
+func TestTryUnlock(t *testing.T) {
+	ctx := context.Background()
+
+	buf := &bytes.Buffer{}
+	out.Stderr = buf
+	defer func() {
+		out.Stderr = os.Stderr
+	}()
+
+	tempdir := t.TempDir()
+	_, _, err := createStore(tempdir, []string{"0xDEADBEEF"}, nil)
+	require.NoError(t, err)
+
+	be := &unlockMocker{Mocker: plain.New(), err: fmt.Errorf("no pinentry")}
+	s := &Store{
+		path:    tempdir,
+		crypto:  be,
+		storage: fs.New(tempdir),
+	}
+	s.TryUnlock(ctx)
+	assert.Equal(t, []string{"0xDEADBEEF"}, be.ids)
+	assert.Contains(t, buf.String(), "Failed to unlock your keys: no pinentry")
+}
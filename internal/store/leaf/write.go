@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/queue"
@@ -16,10 +15,19 @@ import (
 
 // Set encodes and writes the cipertext of one entry to disk.
 func (s *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
-	if strings.Contains(name, "//") {
-		return fmt.Errorf("invalid secret name: %s", name)
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer s.invalidateNames()
+
+	if err := s.validateName(name); err != nil {
+		return err
 	}
 
+	ctx = s.withConfig(ctx)
+	name = s.resolveName(ctx, name)
 	p := s.passfile(name)
 
 	recipients, err := s.useableKeys(ctx, name)
@@ -30,7 +38,12 @@ func (s *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
 	// make sure the encryptor can decrypt later
 	recipients = s.ensureOurKeyID(ctx, recipients)
 
-	ciphertext, err := s.crypto.Encrypt(ctx, sec.Bytes(), recipients)
+	plaintext, err := s.sealFIDO2(ctx, sec.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to protect %q: %w", p, err)
+	}
+
+	ciphertext, err := s.crypto.Encrypt(ctx, plaintext, recipients)
 	if err != nil {
 		debug.Log("Failed encrypt secret: %s", err)
 		return store.ErrEncrypt
@@ -68,6 +81,12 @@ func (s *Store) Set(ctx context.Context, name string, sec gopass.Byter) error {
 }
 
 func (s *Store) gitCommitAndPush(ctx context.Context, name string) error {
+	ctx, unlock, err := s.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if err := s.storage.Commit(ctx, fmt.Sprintf("Save secret to %s: %s", name, ctxutil.GetCommitMessage(ctx))); err != nil {
 		switch {
 		case errors.Is(err, store.ErrGitNotInit):
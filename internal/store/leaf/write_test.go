@@ -3,9 +3,14 @@ package leaf
 import (
 	"context"
 	"os"
-	"runtime"
 	"testing"
 
+	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/gopasspw/gopass/internal/backend/storage/fs"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/store"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/gopass/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,10 +32,53 @@ func TestSet(t *testing.T) {
 	sec.SetPassword("foo")
 	sec.WriteString("bar")
 	require.NoError(t, s.Set(ctx, "zab/zab", sec))
-	if runtime.GOOS != "windows" {
-		assert.Error(t, s.Set(ctx, "../../../../../etc/passwd", sec))
-	} else {
-		assert.NoError(t, s.Set(ctx, "../../../../../etc/passwd", sec))
-	}
+	assert.ErrorIs(t, s.Set(ctx, "../../../../../etc/passwd", sec), store.ErrInvalidName)
+	assert.ErrorIs(t, s.Set(ctx, "foo//bar", sec), store.ErrInvalidName)
 	assert.NoError(t, s.Set(ctx, "zab", sec))
 }
+
+type hiddenMocker struct {
+	*plain.Mocker
+	hidden  bool
+	tryKeys []string
+}
+
+func (h *hiddenMocker) Encrypt(ctx context.Context, plaintext []byte, recipients []string) ([]byte, error) {
+	h.hidden = gpg.IsThrowKeyIDs(ctx)
+	return h.Mocker.Encrypt(ctx, plaintext, recipients)
+}
+
+func (h *hiddenMocker) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	h.tryKeys = gpg.GetTrySecretKeys(ctx)
+	return h.Mocker.Decrypt(ctx, ciphertext)
+}
+
+func TestSetHiddenRecipients(t *testing.T) {
+	ctx := context.Background()
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	tempdir := t.TempDir()
+	genRecs, _, err := createStore(tempdir, nil, nil)
+	require.NoError(t, err)
+
+	be := &hiddenMocker{Mocker: plain.New()}
+	s := &Store{
+		alias:   "",
+		path:    tempdir,
+		crypto:  be,
+		storage: fs.New(tempdir),
+	}
+
+	sec := secrets.New()
+	sec.SetPassword("foo")
+	require.NoError(t, s.Set(ctx, "foo", sec))
+	assert.False(t, be.hidden)
+
+	s.SetConfig(config.StoreConfig{HiddenRecipients: true})
+	require.NoError(t, s.Set(ctx, "foo", sec))
+	assert.True(t, be.hidden)
+
+	_, err = s.Get(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, genRecs, be.tryKeys)
+}
@@ -83,6 +83,20 @@ func (m *InMem) List(ctx context.Context, prefix string) ([]string, error) {
 	return keys, nil
 }
 
+// Walk calls fn for all values.
+func (m *InMem) Walk(ctx context.Context, prefix string, fn func(string) error) error {
+	keys, err := m.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := fn(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // IsDir returns true if the entry is a directory.
 func (m *InMem) IsDir(ctx context.Context, name string) bool {
 	m.Lock()
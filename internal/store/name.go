@@ -0,0 +1,70 @@
+package store
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxElemLen is the maximum length of a single path element in bytes
+// supported by common file systems.
+const MaxElemLen = 255
+
+// windowsReserved are device names that can't be used as file names on
+// Windows, regardless of their extension.
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// ValidateName checks that name can be used as the name of a secret on this
+// platform. Names are relative to the store, use / as separator and must not
+// leave the store. The returned errors wrap ErrInvalidName.
+func ValidateName(name string) error {
+	return validateName(runtime.GOOS, name)
+}
+
+func validateName(goos, name string) error {
+	n := strings.TrimPrefix(name, "/")
+	if n == "" {
+		return fmt.Errorf("%w: the name is empty", ErrInvalidName)
+	}
+	if !utf8.ValidString(n) {
+		return fmt.Errorf("%w: %q is not valid UTF-8", ErrInvalidName, name)
+	}
+
+	for _, elem := range strings.Split(n, "/") {
+		switch elem {
+		case "":
+			return fmt.Errorf("%w: %q contains an empty path element", ErrInvalidName, name)
+		case ".", "..":
+			return fmt.Errorf("%w: %q must not contain %q", ErrInvalidName, name, elem)
+		}
+		if len(elem) > MaxElemLen {
+			return fmt.Errorf("%w: %q is longer than %d bytes", ErrInvalidName, elem, MaxElemLen)
+		}
+		for _, r := range elem {
+			if r < 0x20 || r == 0x7f {
+				return fmt.Errorf("%w: %q contains control characters", ErrInvalidName, name)
+			}
+			if goos == "windows" && strings.ContainsRune(`<>:"\|?*`, r) {
+				return fmt.Errorf("%w: %q contains %q which is not allowed on Windows", ErrInvalidName, name, r)
+			}
+		}
+		if goos != "windows" {
+			continue
+		}
+		if strings.HasSuffix(elem, ".") || strings.HasSuffix(elem, " ") {
+			return fmt.Errorf("%w: %q must not end with a dot or space on Windows", ErrInvalidName, elem)
+		}
+		if base := strings.SplitN(elem, ".", 2)[0]; windowsReserved[strings.ToUpper(strings.TrimRight(base, " "))] {
+			return fmt.Errorf("%w: %q is a reserved name on Windows", ErrInvalidName, elem)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateName(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		linux   bool
+		windows bool
+	}{
+		{"foo", true, true},
+		{"/foo/bar", true, true},
+		{"web/github.com", true, true},
+		{"café/bär baz", true, true},
+		{"", false, false},
+		{"/", false, false},
+		{"foo//bar", false, false},
+		{"foo/", false, false},
+		{"../foo", false, false},
+		{"foo/../../bar", false, false},
+		{"./foo", false, false},
+		{"foo\x00bar", false, false},
+		{"foo\nbar", false, false},
+		{"\xff", false, false},
+		{strings.Repeat("a", 256), false, false},
+		{"foo:bar", true, false},
+		{`foo\bar`, true, false},
+		{`..\..\bar`, true, false},
+		{"foo?", true, false},
+		{"foo.", true, false},
+		{"foo ", true, false},
+		{"con", true, false},
+		{"dev/LPT1.txt", true, false},
+		{"console", true, true},
+	} {
+		err := validateName("linux", tc.name)
+		if tc.linux {
+			assert.NoError(t, err, tc.name)
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidName, tc.name)
+		}
+
+		err = validateName("windows", tc.name)
+		if tc.windows {
+			assert.NoError(t, err, tc.name)
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidName, tc.name)
+		}
+	}
+}
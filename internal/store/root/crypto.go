@@ -16,3 +16,30 @@ func (r *Store) Crypto(ctx context.Context, name string) backend.Crypto {
 	}
 	return sub.Crypto()
 }
+
+// Unlock asks for the passphrases needed to decrypt the secrets of all
+// mounted stores upfront. See leaf.Store.Unlock.
+func (r *Store) Unlock(ctx context.Context) error {
+	for _, mp := range append([]string{""}, r.MountPoints()...) {
+		sub, err := r.GetSubStore(mp)
+		if err != nil || !sub.Valid() {
+			continue
+		}
+		if err := sub.Unlock(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TryUnlock unlocks all mounted stores upfront, but only warns if that fails.
+// See leaf.Store.TryUnlock.
+func (r *Store) TryUnlock(ctx context.Context) {
+	for _, mp := range append([]string{""}, r.MountPoints()...) {
+		sub, err := r.GetSubStore(mp)
+		if err != nil || !sub.Valid() {
+			continue
+		}
+		sub.TryUnlock(ctx)
+	}
+}
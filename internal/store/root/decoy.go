@@ -0,0 +1,114 @@
+package root
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+)
+
+// ErrBadPassphrase is returned if the passphrase unlocks neither the root
+// store nor the decoy store.
+var ErrBadPassphrase = errors.New("bad passphrase")
+
+// resolveDecoy returns the decoy store if the passphrase of one of its
+// identities was entered, or nil if the root store should be used. Which
+// store is used depends on the identity of the user only, gopass asks for
+// the passphrase in the same way for both stores.
+func (r *Store) resolveDecoy(ctx context.Context, real *leaf.Store) (*leaf.Store, error) {
+	path := fsutil.CleanPath(r.cfg.Decoy)
+	sc := r.cfg.StoreConfig("")
+	decoy, err := leaf.New(sc.WithContext(ctx), "", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the decoy store at %q: %w", r.cfg.Decoy, err)
+	}
+	decoy.SetConfig(sc)
+
+	u, ok := real.Crypto().(backend.PassphraseUnlocker)
+	if !ok {
+		debug.Log("crypto backend %s doesn't support decoy stores", real.Crypto().Name())
+		return nil, nil
+	}
+
+	realIDs, err := real.Identities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	decoyIDs, err := decoy.Identities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	useDecoy, err := selectDecoy(ctx, u, realIDs, decoyIDs)
+	if err != nil || !useDecoy {
+		return nil, err
+	}
+
+	debug.Log("using the decoy store at %s", path)
+	return decoy, nil
+}
+
+// selectDecoy returns true if the decoy identities are unlocked, or if the
+// user enters the passphrase of one of them. The identities of the root
+// store take precedence.
+func selectDecoy(ctx context.Context, u backend.PassphraseUnlocker, realIDs, decoyIDs []string) (bool, error) {
+	decoyIDs = without(decoyIDs, realIDs)
+	if len(realIDs) < 1 || len(decoyIDs) < 1 {
+		debug.Log("decoy store needs distinct identities, ignoring it (real: %v, decoy: %v)", realIDs, decoyIDs)
+		return false, nil
+	}
+
+	for _, id := range realIDs {
+		if u.IsUnlocked(ctx, id) {
+			return false, nil
+		}
+	}
+	for _, id := range decoyIDs {
+		if u.IsUnlocked(ctx, id) {
+			return true, nil
+		}
+	}
+
+	if !ctxutil.IsInteractive(ctx) || ctxutil.IsAlwaysYes(ctx) {
+		return false, nil
+	}
+
+	pw, err := termio.AskForPassword(ctx, "passphrase to unlock gopass", false)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range realIDs {
+		if err := u.UnlockWithPassphrase(ctx, id, []byte(pw)); err == nil {
+			return false, nil
+		}
+	}
+	for _, id := range decoyIDs {
+		if err := u.UnlockWithPassphrase(ctx, id, []byte(pw)); err == nil {
+			return true, nil
+		}
+	}
+
+	return false, ErrBadPassphrase
+}
+
+// without returns the elements of a that are not in b.
+func without(a, b []string) []string {
+	skip := make(map[string]bool, len(b))
+	for _, s := range b {
+		skip[s] = true
+	}
+	res := make([]string, 0, len(a))
+	for _, s := range a {
+		if !skip[s] {
+			res = append(res, s)
+		}
+	}
+	return res
+}
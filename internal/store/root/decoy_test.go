@@ -0,0 +1,140 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/termio"
+	"github.com/gopasspw/gopass/tests/gptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUnlocker struct {
+	unlocked    map[string]bool
+	passphrases map[string]string
+}
+
+func (f *fakeUnlocker) IsUnlocked(ctx context.Context, id string) bool {
+	return f.unlocked[id]
+}
+
+func (f *fakeUnlocker) UnlockWithPassphrase(ctx context.Context, id string, passphrase []byte) error {
+	if f.passphrases[id] != string(passphrase) {
+		return fmt.Errorf("bad passphrase")
+	}
+	f.unlocked[id] = true
+	return nil
+}
+
+func TestSelectDecoy(t *testing.T) {
+	t.Parallel()
+
+	prompt := func(pw string) context.Context {
+		return termio.WithPassPromptFunc(context.Background(), func(context.Context, string) (string, error) {
+			return pw, nil
+		})
+	}
+
+	for _, tc := range []struct {
+		name     string
+		ctx      context.Context
+		unlocked map[string]bool
+		real     []string
+		decoy    []string
+		want     bool
+		err      error
+	}{
+		{
+			name:  "real passphrase",
+			ctx:   prompt("real"),
+			real:  []string{"r"},
+			decoy: []string{"d"},
+		},
+		{
+			name:  "decoy passphrase",
+			ctx:   prompt("decoy"),
+			real:  []string{"r"},
+			decoy: []string{"d"},
+			want:  true,
+		},
+		{
+			name:  "bad passphrase",
+			ctx:   prompt("foo"),
+			real:  []string{"r"},
+			decoy: []string{"d"},
+			err:   ErrBadPassphrase,
+		},
+		{
+			name:     "real unlocked",
+			ctx:      prompt("decoy"),
+			unlocked: map[string]bool{"r": true, "d": true},
+			real:     []string{"r"},
+			decoy:    []string{"d"},
+		},
+		{
+			name:     "decoy unlocked",
+			ctx:      prompt("real"),
+			unlocked: map[string]bool{"d": true},
+			real:     []string{"r"},
+			decoy:    []string{"d"},
+			want:     true,
+		},
+		{
+			name:  "shared identity",
+			ctx:   prompt("decoy"),
+			real:  []string{"r"},
+			decoy: []string{"r"},
+		},
+		{
+			name:  "not interactive",
+			ctx:   ctxutil.WithInteractive(prompt("decoy"), false),
+			real:  []string{"r"},
+			decoy: []string{"d"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.unlocked == nil {
+				tc.unlocked = map[string]bool{}
+			}
+			u := &fakeUnlocker{
+				unlocked:    tc.unlocked,
+				passphrases: map[string]string{"r": "real", "d": "decoy"},
+			}
+
+			got, err := selectDecoy(tc.ctx, u, tc.real, tc.decoy)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDecoyUnsupported(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+
+	cfg := config.New()
+	cfg.Path = u.StoreDir("")
+	cfg.Decoy = u.StoreDir("decoy")
+	rs := New(cfg)
+
+	// the plain backend can't tell identities apart, so the root store
+	// is used.
+	require.NoError(t, rs.initialize(ctx))
+	assert.Equal(t, u.StoreDir(""), rs.store.Path())
+}
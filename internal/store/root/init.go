@@ -34,10 +34,13 @@ func (r *Store) Init(ctx context.Context, alias, path string, ids ...string) err
 		ctx = backend.WithStorageBackend(ctx, backend.GitFS)
 	}
 
+	sc := r.cfg.StoreConfig(alias)
+	ctx = sc.WithContext(ctx)
 	sub, err := leaf.New(ctx, alias, path)
 	if err != nil {
 		return fmt.Errorf("failed to instantiate new sub store: %w", err)
 	}
+	sub.SetConfig(sc)
 	if !r.store.IsInitialized(ctx) && alias == "" {
 		r.store = sub
 	}
@@ -67,15 +70,30 @@ func (r *Store) initialize(ctx context.Context) error {
 	// create the base store
 	path := fsutil.CleanPath(r.cfg.Path)
 	debug.Log("initialize - %s", path)
-	s, err := leaf.New(ctx, "", path)
+	sc := r.cfg.StoreConfig("")
+	s, err := leaf.New(sc.WithContext(ctx), "", path)
 	if err != nil {
 		return fmt.Errorf("failed to initialize the root store at %q: %w", r.cfg.Path, err)
 	}
+	s.SetConfig(sc)
 	debug.Log("Root Store initialized at %s", path)
+
+	mounts := r.cfg.Mounts
+	if r.cfg.Decoy != "" {
+		decoy, err := r.resolveDecoy(ctx, s)
+		if err != nil {
+			return err
+		}
+		if decoy != nil {
+			// the mounts would give away the real store.
+			s = decoy
+			mounts = nil
+		}
+	}
 	r.store = s
 
 	// initialize all mounts
-	for alias, path := range r.cfg.Mounts {
+	for alias, path := range mounts {
 		path := fsutil.CleanPath(path)
 		if err := r.addMount(ctx, alias, path); err != nil {
 			out.Errorf(ctx, "Failed to initialize mount %s (%s). Ignoring: %s", alias, path, err)
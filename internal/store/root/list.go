@@ -20,6 +20,74 @@ func (r *Store) List(ctx context.Context, maxDepth int) ([]string, error) {
 	return t.List(maxDepth), nil
 }
 
+// ListStream sends the names of all secrets below prefix to the returned
+// channel as soon as they are found, instead of listing all stores first.
+// Entries of the root store come first, followed by the ones of each mount.
+// The channel is closed when all entries have been sent or the context is
+// canceled. The entries are not sorted.
+func (r *Store) ListStream(ctx context.Context, prefix string) (<-chan store.Entry, error) {
+	if r.store == nil {
+		return nil, fmt.Errorf("store not initialized")
+	}
+
+	mps := r.MountPoints()
+	sort.Sort(store.ByPathLen(mps))
+
+	ch := make(chan store.Entry, 64)
+	go func() {
+		defer close(ch)
+
+		for _, alias := range append([]string{""}, mps...) {
+			sub := r.store
+			if alias != "" {
+				sub = r.mounts[alias]
+			}
+			subPrefix, ok := streamPrefix(alias, prefix)
+			if sub == nil || !ok {
+				continue
+			}
+
+			entries, err := sub.ListStream(ctx, subPrefix)
+			if err != nil {
+				ch <- store.Entry{Err: err}
+				return
+			}
+			for e := range entries {
+				// skip entries hidden by a (nested) mount
+				if e.Err == nil && (!strings.HasPrefix(e.Name, prefix) || r.MountPoint(e.Name) != alias) {
+					continue
+				}
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					return
+				}
+				if e.Err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// streamPrefix returns the prefix to list in the store mounted at alias to
+// find all entries below prefix. It returns false if the store can't contain
+// any.
+func streamPrefix(alias, prefix string) (string, bool) {
+	switch {
+	case alias == "":
+		return prefix, true
+	case strings.HasPrefix(prefix, alias+"/"):
+		return strings.TrimPrefix(prefix, alias+"/"), true
+	case strings.HasPrefix(alias+"/", prefix):
+		return "", true
+	default:
+		return "", false
+	}
+}
+
 // Tree returns the tree representation of the entries.
 func (r *Store) Tree(ctx context.Context) (*tree.Root, error) {
 	root := tree.New("gopass")
@@ -0,0 +1,21 @@
+package root
+
+import (
+	"context"
+
+	"github.com/gopasspw/gopass/internal/backend"
+	"github.com/gopasspw/gopass/internal/store/leaf"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Migrate re-encrypts all secrets of the given mount with a different crypto
+// backend, see leaf.Store.Migrate.
+func (r *Store) Migrate(ctx context.Context, name string, to backend.Crypto, rs []string) (leaf.MigrateResult, error) {
+	sub, err := r.GetSubStore(name)
+	if err != nil {
+		return leaf.MigrateResult{}, err
+	}
+
+	debug.Log("migrating %s to %s for %v", name, to.Name(), rs)
+	return sub.Migrate(ctx, to, rs)
+}
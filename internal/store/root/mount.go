@@ -55,10 +55,14 @@ func (r *Store) addMount(ctx context.Context, alias, path string, keys ...string
 
 func (r *Store) initSub(ctx context.Context, alias, path string, keys []string) (*leaf.Store, error) {
 	// init regular sub store
+	// the crypto backend must use the keyring configured for this store
+	sc := r.cfg.StoreConfig(alias)
+	ctx = sc.WithContext(ctx)
 	s, err := leaf.New(ctx, alias, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize store %q at %q: %w", alias, path, err)
 	}
+	s.SetConfig(sc)
 
 	if s.IsInitialized(ctx) {
 		return s, nil
@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/gopasspw/gopass/internal/store"
 	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/gopass"
 )
 
 // Copy will copy one entry to another location. Multi-store copies are
@@ -156,6 +158,73 @@ func (r *Store) moveFromTo(ctx context.Context, subFrom *leaf.Store, from, to, f
 	return nil
 }
 
+// MoveMany moves each of the given secrets (source to destination) and
+// commits the changes to each affected store once, with the given message.
+// All sources are read before anything is written, so the destination of one
+// secret can be the source of another.
+func (r *Store) MoveMany(ctx context.Context, renames map[string]string, msg string) error {
+	ctx = ctxutil.WithGitCommit(ctx, false)
+
+	srcs := make([]string, 0, len(renames))
+	for src := range renames {
+		srcs = append(srcs, src)
+	}
+	sort.Strings(srcs)
+
+	contents := make(map[string]gopass.Secret, len(srcs))
+	for _, src := range srcs {
+		sec, err := r.Get(ctx, src)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		contents[src] = sec
+	}
+
+	touched := make(map[string]*leaf.Store, 2)
+	dsts := make(map[string]bool, len(srcs))
+	for _, src := range srcs {
+		dst := renames[src]
+		debug.Log("Moving %q => %q", src, dst)
+		if err := r.Set(ctxutil.WithCommitMessage(ctx, msg), dst, contents[src]); err != nil {
+			return fmt.Errorf("failed to save secret %q: %w", dst, err)
+		}
+		dsts[dst] = true
+		sub, _ := r.getStore(dst)
+		touched[sub.Alias()] = sub
+	}
+
+	for _, src := range srcs {
+		sub, _ := r.getStore(src)
+		touched[sub.Alias()] = sub
+		if dsts[src] {
+			// overwritten by another secret.
+			continue
+		}
+		if err := r.Delete(ctx, src); err != nil {
+			return fmt.Errorf("failed to delete secret %q: %w", src, err)
+		}
+	}
+
+	for _, sub := range touched {
+		if err := sub.Storage().Commit(ctx, msg); err != nil {
+			if errors.Is(err, store.ErrGitNotInit) || errors.Is(err, store.ErrGitNothingToCommit) {
+				debug.Log("not committing to %q: %s", sub.Alias(), err)
+				continue
+			}
+			return fmt.Errorf("failed to commit changes to git (%s): %w", sub.Alias(), err)
+		}
+		if err := sub.Storage().Push(ctx, "", ""); err != nil {
+			if errors.Is(err, store.ErrGitNotInit) || errors.Is(err, store.ErrGitNoRemote) {
+				debug.Log("not pushing %q: %s", sub.Alias(), err)
+				continue
+			}
+			return fmt.Errorf("failed to push change to git remote: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // Delete will remove an single entry from the store.
 func (r *Store) Delete(ctx context.Context, name string) error {
 	store, sn := r.getStore(name)
@@ -143,6 +143,49 @@ func TestMove(t *testing.T) {
 	}, entries)
 }
 
+func TestMoveMany(t *testing.T) {
+	u := gptest.NewUnitTester(t)
+	u.Entries = []string{
+		"a",
+		"b",
+		"c",
+	}
+	require.NoError(t, u.InitStore(""))
+	defer u.Remove()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithAlwaysYes(ctx, true)
+	ctx = ctxutil.WithHidden(ctx, true)
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+	assert.NoError(t, rs.Delete(ctx, "foo"))
+
+	want := map[string]string{}
+	for _, name := range []string{"a", "b", "c"} {
+		sec, err := rs.Get(ctx, name)
+		require.NoError(t, err)
+		want[name] = sec.Password()
+	}
+
+	// a and b swap places, c is moved into a folder.
+	require.NoError(t, rs.MoveMany(ctx, map[string]string{
+		"a": "b",
+		"b": "a",
+		"c": "dir/c",
+	}, "Rename"))
+
+	entries, err := rs.List(ctx, tree.INF)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "dir/c"}, entries)
+
+	for name, src := range map[string]string{"a": "b", "b": "a", "dir/c": "c"} {
+		sec, err := rs.Get(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, want[src], sec.Password(), name)
+	}
+}
+
 func TestCopy(t *testing.T) {
 	u := gptest.NewUnitTester(t)
 	u.Entries = []string{
@@ -38,15 +38,54 @@ func (r *Store) RCSRemoveRemote(ctx context.Context, name, remote string) error
 // RCSPull performs a git pull.
 func (r *Store) RCSPull(ctx context.Context, name, origin, remote string) error {
 	store, _ := r.getStore(name)
+	ctx, unlock, err := store.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	return store.Storage().Pull(ctx, origin, remote)
 }
 
 // RCSPush performs a git push.
 func (r *Store) RCSPush(ctx context.Context, name, origin, remote string) error {
 	store, _ := r.getStore(name)
+	ctx, unlock, err := store.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	return store.Storage().Push(ctx, origin, remote)
 }
 
+// Purge removes the given deleted secrets from the history of the store they
+// belong to.
+func (r *Store) Purge(ctx context.Context, names ...string) error {
+	byStore := make(map[string][]string, 1)
+	for _, name := range names {
+		mp := r.MountPoint(name)
+		_, sn := r.getStore(name)
+		byStore[mp] = append(byStore[mp], sn)
+	}
+
+	for mp, names := range byStore {
+		store, _ := r.getStore(mp)
+		if err := store.Purge(ctx, names...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ForcePush replaces the history of the remote of the given store with the
+// local one.
+func (r *Store) ForcePush(ctx context.Context, name string) error {
+	store, _ := r.getStore(name)
+	return store.ForcePush(ctx)
+}
+
 // ListRevisions will list all revisions for the named entity.
 func (r *Store) ListRevisions(ctx context.Context, name string) ([]backend.Revision, error) {
 	store, name := r.getStore(name)
@@ -31,6 +31,20 @@ func (r *Store) RemoveRecipient(ctx context.Context, store, rec string) error {
 	return sub.RemoveRecipient(ctx, rec)
 }
 
+// MigrateRecipients replaces all recipients of the given store that aren't full
+// fingerprints with the fingerprint of their key.
+func (r *Store) MigrateRecipients(ctx context.Context, store string) (int, error) {
+	sub, _ := r.getStore(store)
+	return sub.MigrateRecipients(ctx)
+}
+
+// DeauthorizeRecipient removes a recipient from all recipients files of the
+// given store. It returns the secrets the recipient could read.
+func (r *Store) DeauthorizeRecipient(ctx context.Context, store, rec string) ([]string, error) {
+	sub, _ := r.getStore(store)
+	return sub.DeauthorizeRecipient(ctx, rec)
+}
+
 func (r *Store) addRecipient(ctx context.Context, prefix string, root *tree.Root, recp string, pretty bool) error {
 	sub, _ := r.getStore(prefix)
 	key := fmt.Sprintf("%s (missing public key)", recp)
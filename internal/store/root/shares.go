@@ -0,0 +1,24 @@
+package root
+
+import (
+	"context"
+)
+
+// SetShares writes the shares of a split secret, each one encrypted for its
+// recipient only.
+func (r *Store) SetShares(ctx context.Context, name string, shares map[string][]byte) error {
+	sub, name := r.getStore(name)
+	return sub.SetShares(ctx, name, shares)
+}
+
+// Shares returns the recipients holding a share of the given secret.
+func (r *Store) Shares(ctx context.Context, name string) ([]string, error) {
+	sub, name := r.getStore(name)
+	return sub.Shares(ctx, name)
+}
+
+// GetShare decrypts the share of the given recipient.
+func (r *Store) GetShare(ctx context.Context, name, id string) ([]byte, error) {
+	sub, name := r.getStore(name)
+	return sub.GetShare(ctx, name, id)
+}
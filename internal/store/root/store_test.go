@@ -132,3 +132,59 @@ func createRootStore(ctx context.Context, u *gptest.Unit) (*Store, error) {
 	}
 	return s, nil
 }
+
+func TestListStream(t *testing.T) {
+	ctx := context.Background()
+	ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+
+	u := gptest.NewUnitTester(t)
+	defer u.Remove()
+
+	rs, err := createRootStore(ctx, u)
+	require.NoError(t, err)
+
+	assert.NoError(t, u.InitStore("sub2"))
+	assert.NoError(t, u.InitStore("sub3"))
+	assert.NoError(t, rs.AddMount(ctx, "sub2", u.StoreDir("sub2")))
+	assert.NoError(t, rs.AddMount(ctx, "sub2/sub3", u.StoreDir("sub3")))
+
+	collect := func(prefix string) []string {
+		ch, err := rs.ListStream(ctx, prefix)
+		require.NoError(t, err)
+		lst := []string{}
+		for e := range ch {
+			require.NoError(t, e.Err)
+			lst = append(lst, e.Name)
+		}
+		sort.Strings(lst)
+		return lst
+	}
+
+	// streaming must find the same entries as the tree
+	st, err := rs.Tree(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, st.List(tree.INF), collect(""))
+
+	ents := make([]string, 0, 2*len(u.Entries))
+	for _, k := range u.Entries {
+		ents = append(ents, path.Join("sub2", k), path.Join("sub2", "sub3", k))
+	}
+	sort.Strings(ents)
+	assert.Equal(t, ents, collect("sub2/"))
+
+	ents = ents[:0]
+	for _, k := range u.Entries {
+		ents = append(ents, path.Join("sub2", "sub3", k))
+	}
+	sort.Strings(ents)
+	assert.Equal(t, ents, collect("sub2/sub3/"))
+
+	// canceling stops the listing
+	cctx, cancel := context.WithCancel(ctx)
+	ch, err := rs.ListStream(cctx, "")
+	require.NoError(t, err)
+	<-ch
+	cancel()
+	for range ch {
+	}
+}
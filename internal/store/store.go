@@ -14,3 +14,10 @@ type ImportCallback func(context.Context, string, []string) bool
 // FsckCallback is a callback to ask the user to confirm certain fsck
 // corrective actions.
 type FsckCallback func(context.Context, string) bool
+
+// Entry is a single secret returned by a streaming listing. If listing
+// fails midway the last entry only carries the error.
+type Entry struct {
+	Name string
+	Err  error
+}
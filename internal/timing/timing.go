@@ -0,0 +1,161 @@
+// Package timing records how long gopass spends in potentially slow
+// operations, e.g. invoking gpg or git, and reports it by category. It's
+// used by the --profile flag to diagnose slow commands.
+package timing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+type contextKey int
+
+const (
+	ctxKeyRecorder contextKey = iota
+)
+
+// Categories of operations recorded by gopass.
+const (
+	Keys    = "keys"
+	Decrypt = "decrypt"
+	Encrypt = "encrypt"
+	Git     = "git"
+	Render  = "render"
+)
+
+// order is the order of the well known categories in the report.
+var order = []string{Keys, Decrypt, Encrypt, Git, Render}
+
+// Stat summarizes all recorded operations of one category.
+type Stat struct {
+	Count int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Recorder collects the duration of operations by category. It's safe for
+// concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	start time.Time
+	stats map[string]*Stat
+}
+
+// New creates a new recorder. The total time of the report is measured
+// from this point.
+func New() *Recorder {
+	return &Recorder{
+		start: time.Now(),
+		stats: make(map[string]*Stat, len(order)),
+	}
+}
+
+// WithRecorder adds the given recorder to the context.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, ctxKeyRecorder, r)
+}
+
+// GetRecorder returns the recorder from the context or nil if
+// there is none.
+func GetRecorder(ctx context.Context) *Recorder {
+	r, ok := ctx.Value(ctxKeyRecorder).(*Recorder)
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+// Start starts timing an operation of the given category. The returned
+// func must be called when the operation is done. Without a recorder in the
+// context this does nothing.
+//
+//	defer timing.Start(ctx, timing.Git)()
+func Start(ctx context.Context, category string) func() {
+	r := GetRecorder(ctx)
+	if r == nil {
+		return func() {}
+	}
+
+	t0 := time.Now()
+	return func() {
+		r.Add(category, time.Since(t0))
+	}
+}
+
+// Add records one operation of the given category.
+func (r *Recorder) Add(category string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st, found := r.stats[category]
+	if !found {
+		st = &Stat{}
+		r.stats[category] = st
+	}
+	st.Count++
+	st.Total += d
+	if d > st.Max {
+		st.Max = d
+	}
+}
+
+// Stats returns a copy of the stats recorded so far.
+func (r *Recorder) Stats() map[string]Stat {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make(map[string]Stat, len(r.stats))
+	for k, v := range r.stats {
+		res[k] = *v
+	}
+	return res
+}
+
+// Report writes a table of the recorded stats to w. Well known categories
+// are always listed, others only if they were recorded.
+func (r *Recorder) Report(w io.Writer) {
+	stats := r.Stats()
+
+	cats := append([]string{}, order...)
+	extra := make([]string, 0, len(stats))
+	for k := range stats {
+		if !contains(order, k) {
+			extra = append(extra, k)
+		}
+	}
+	sort.Strings(extra)
+	cats = append(cats, extra...)
+
+	fmt.Fprintf(w, "Timings (total %s):\n", round(time.Since(r.start)))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cat := range cats {
+		st := stats[cat]
+		fmt.Fprintf(tw, "  %s\t%d calls\t%s\tmax %s\n", cat, st.Count, round(st.Total), round(st.Max))
+	}
+	_ = tw.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	switch {
+	case d > time.Second:
+		return d.Round(time.Millisecond)
+	case d > time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package timing
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartWithoutRecorder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Nil(t, GetRecorder(ctx))
+	// must not panic
+	Start(ctx, Git)()
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	r := New()
+	ctx := WithRecorder(context.Background(), r)
+	require.Equal(t, r, GetRecorder(ctx))
+
+	r.Add(Git, 2*time.Millisecond)
+	r.Add(Git, 5*time.Millisecond)
+	r.Add(Decrypt, time.Second)
+	Start(ctx, "otp")()
+
+	stats := r.Stats()
+	assert.Equal(t, Stat{Count: 2, Total: 7 * time.Millisecond, Max: 5 * time.Millisecond}, stats[Git])
+	assert.Equal(t, Stat{Count: 1, Total: time.Second, Max: time.Second}, stats[Decrypt])
+	assert.Equal(t, 1, stats["otp"].Count)
+	_, found := stats[Keys]
+	assert.False(t, found)
+
+	buf := &bytes.Buffer{}
+	r.Report(buf)
+	assert.Contains(t, buf.String(), "Timings (total ")
+	assert.Regexp(t, `(?m)^  keys +0 calls +0s +max 0s$`, buf.String())
+	assert.Regexp(t, `(?m)^  git +2 calls +7ms +max 5ms$`, buf.String())
+	assert.Regexp(t, `(?m)^  decrypt +1 calls +1s +max 1s$`, buf.String())
+	assert.Regexp(t, `(?m)^  otp +1 calls `, buf.String())
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/sha1"
 	"fmt"
 	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/gopasspw/gopass/internal/pwschemes/argon2i"
@@ -30,6 +31,7 @@ const (
 	FuncGetPassword = "getpw"
 	FuncGetValue    = "getval"
 	FuncGetValues   = "getvals"
+	FuncSecret      = "secret"
 	FuncArgon2i     = "argon2i"
 	FuncArgon2id    = "argon2id"
 	FuncBcrypt      = "bcrypt"
@@ -189,12 +191,41 @@ func getValues(ctx context.Context, kv kvstore) func(...string) ([]string, error
 	}
 }
 
+// secretFunc returns the password or the value of the given key. Unlike the
+// other getters it fails if the secret or the key does not exist so
+// rendering a config file never silently inserts an error message.
+func secretFunc(ctx context.Context, kv kvstore) func(...string) (string, error) {
+	return func(s ...string) (string, error) {
+		if len(s) < 1 || len(s) > 2 {
+			return "", fmt.Errorf("usage: secret NAME [KEY]")
+		}
+		if kv == nil {
+			return "", fmt.Errorf("KV is nil")
+		}
+		sec, err := kv.Get(ctx, s[0])
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret %q: %w", s[0], err)
+		}
+		if len(s) < 2 {
+			return sec.Password(), nil
+		}
+		if sv, found := sec.Get(s[1]); found {
+			return sv, nil
+		}
+		if strings.ToLower(s[1]) == "password" {
+			return sec.Password(), nil
+		}
+		return "", fmt.Errorf("key %q not found in secret %q", s[1], s[0])
+	}
+}
+
 func funcMap(ctx context.Context, kv kvstore) template.FuncMap {
 	return template.FuncMap{
 		FuncGet:         get(ctx, kv),
 		FuncGetPassword: getPassword(ctx, kv),
 		FuncGetValue:    getValue(ctx, kv),
 		FuncGetValues:   getValues(ctx, kv),
+		FuncSecret:      secretFunc(ctx, kv),
 		FuncMd5sum:      md5sum(),
 		FuncSha1sum:     sha1sum(),
 		FuncMd5Crypt:    md5cryptFunc(),
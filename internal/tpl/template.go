@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"text/template"
 
+	"github.com/gopasspw/gopass/internal/timing"
 	"github.com/gopasspw/gopass/pkg/gopass"
 )
 
@@ -22,6 +23,7 @@ type payload struct {
 
 // Execute executes the given template.
 func Execute(ctx context.Context, tpl, name string, content []byte, s kvstore) ([]byte, error) {
+	defer timing.Start(ctx, timing.Render)()
 	funcs := funcMap(ctx, s)
 	pl := payload{
 		Dir:     filepath.Dir(name),
@@ -86,6 +86,33 @@ func TestVars(t *testing.T) {
 			Content:  []byte("foobar"),
 			Output:   "barvalue",
 		},
+		{
+			Template: `{{secret "testdir"}}`,
+			Name:     "testdir",
+			Output:   "barfoo",
+		},
+		{
+			Template: `{{secret "testdir" "password"}}`,
+			Name:     "testdir",
+			Output:   "barfoo",
+		},
+		{
+			Template: `{{secret "testdir" "barkey"}}`,
+			Name:     "testdir",
+			Output:   "barvalue",
+		},
+		{
+			Template:   `{{secret "testdir" "nokey"}}`,
+			Name:       "testdir",
+			Output:     "",
+			ShouldFail: true,
+		},
+		{
+			Template:   `{{secret}}`,
+			Name:       "testdir",
+			Output:     "",
+			ShouldFail: true,
+		},
 		{
 			Template: `md5{{(print .Content .Name) | md5sum}}`,
 			Name:     "testdir",
@@ -10,6 +10,8 @@ type Node struct {
 	Type     string
 	Template bool
 	Mount    bool
+	Expired  bool
+	Status   string
 	Path     string
 	Subtree  *Tree
 }
@@ -93,6 +95,13 @@ func (n *Node) format(prefix string, last bool, maxDepth, curDepth int) string {
 	if n.Template {
 		_, _ = out.WriteString(" " + colTpl("(template)"))
 	}
+	// mark expired secrets
+	if n.Expired {
+		_, _ = out.WriteString(" " + colExp("(expired)"))
+	}
+	if n.Status != "" {
+		_, _ = out.WriteString(" [" + n.Status + "]")
+	}
 	// finish this output
 	_, _ = out.WriteString("\n")
 
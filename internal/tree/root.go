@@ -19,6 +19,7 @@ var (
 	colMount = color.New(color.FgCyan, color.Bold).SprintfFunc()
 	colDir   = color.New(color.FgBlue, color.Bold).SprintfFunc()
 	colTpl   = color.New(color.FgGreen, color.Bold).SprintfFunc()
+	colExp   = color.New(color.FgRed, color.Bold).SprintfFunc()
 	// sep is intentionally NOT platform-agnostic. This is used for the CLI output
 	// and should always be a regular slash.
 	sep = "/"
@@ -148,6 +149,50 @@ func (r *Root) FindFolder(path string) (*Root, error) {
 	return &Root{Name: r.Name, Subtree: t, Prefix: prefix}, nil
 }
 
+// SetExpired marks the secret at path as expired. The path must include the
+// prefix of this tree, if any.
+func (r *Root) SetExpired(path string) error {
+	node, err := r.node(path)
+	if err != nil {
+		return err
+	}
+	node.Expired = true
+	return nil
+}
+
+// SetStatus annotates the node at path, e.g. a mount with its sync status.
+// The path must include the prefix of this tree, if any.
+func (r *Root) SetStatus(path, status string) error {
+	node, err := r.node(path)
+	if err != nil {
+		return err
+	}
+	node.Status = status
+	return nil
+}
+
+func (r *Root) node(path string) (*Node, error) {
+	if r.Prefix != "" {
+		path = strings.TrimPrefix(path, r.Prefix+sep)
+	}
+	t := r.Subtree
+	p := strings.Split(path, "/")
+	for i, e := range p {
+		_, node := t.find(e)
+		if node == nil {
+			return nil, fmt.Errorf("not found")
+		}
+		if i == len(p)-1 {
+			return node, nil
+		}
+		if node.Subtree == nil {
+			return nil, fmt.Errorf("not found")
+		}
+		t = node.Subtree
+	}
+	return nil, fmt.Errorf("not found")
+}
+
 // SetName changes the name of this tree.
 func (r *Root) SetName(n string) {
 	r.Name = n
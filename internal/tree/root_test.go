@@ -76,3 +76,45 @@ func TestMountShadow(t *testing.T) {
 	_, err := r.FindFolder("mnt/m1")
 	assert.Error(t, err)
 }
+
+func TestSetExpired(t *testing.T) {
+	color.NoColor = true
+
+	r := New("gopass")
+	r.AddFile("foo/bar/baz", "")
+	r.AddFile("foo/bar/zab", "")
+	r.AddFile("foo/qux", "")
+
+	assert.NoError(t, r.SetExpired("foo/bar/zab"))
+	assert.Error(t, r.SetExpired("foo/bar/missing"))
+	assert.Error(t, r.SetExpired("foo/qux/baz"))
+
+	f, err := r.FindFolder("foo")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetExpired("foo/qux"))
+
+	assert.Equal(t, `gopass
+└── foo/
+    ├── bar/
+    │   ├── baz
+    │   └── zab (expired)
+    └── qux (expired)
+`, r.Format(INF))
+}
+
+func TestSetStatus(t *testing.T) {
+	color.NoColor = true
+
+	r := New("gopass")
+	r.AddFile("foo/bar", "")
+	assert.NoError(t, r.AddMount("work", "/tmp/work"))
+
+	assert.NoError(t, r.SetStatus("work", "up to date"))
+	assert.Error(t, r.SetStatus("missing", "up to date"))
+
+	assert.Equal(t, `gopass
+├── foo/
+│   └── bar
+└── work (/tmp/work) [up to date]
+`, r.Format(INF))
+}
@@ -0,0 +1,166 @@
+// Package usage records when each secret was last shown or copied. The log
+// only stays on this machine. Every entry is encrypted separately, so
+// recording an access only needs the public key. Reading the log decrypts
+// all entries and compacts them into one.
+package usage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/appdir"
+	"github.com/gopasspw/gopass/pkg/fsutil"
+)
+
+const day = 24 * time.Hour
+
+// Crypto is the part of the crypto backend needed for the log.
+type Crypto interface {
+	Encrypt(ctx context.Context, plaintext []byte, recipients []string) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// Log maps secret names to the time they were last used.
+type Log map[string]time.Time
+
+// Path returns the location of the log.
+func Path() string {
+	if p := os.Getenv("GOPASS_USAGE_LOG"); p != "" {
+		return p
+	}
+	return filepath.Join(appdir.UserData(), "usage.log")
+}
+
+// Record appends the use of the secret name at t to the log at path.
+func Record(ctx context.Context, path string, c Crypto, recipients []string, name string, t time.Time) error {
+	line, err := encode(ctx, c, recipients, Log{name: t.UTC()})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create log dir: %w", err)
+	}
+	fh, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer fh.Close() //nolint:errcheck
+
+	if _, err := fh.Write(line); err != nil {
+		return fmt.Errorf("failed to write usage log: %w", err)
+	}
+	return nil
+}
+
+// Read decrypts the log at path. A missing log is empty.
+func Read(ctx context.Context, path string, c Crypto) (Log, error) {
+	l := Log{}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(buf))
+	s.Buffer(make([]byte, 0, 64*1024), len(buf)+1)
+	for n := 1; s.Scan(); n++ {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) < 1 {
+			continue
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: failed to decode entry: %w", n, err)
+		}
+		plaintext, err := c.Decrypt(ctx, ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: failed to decrypt entry: %w", n, err)
+		}
+		var e Log
+		if err := json.Unmarshal(plaintext, &e); err != nil {
+			return nil, fmt.Errorf("line %d: failed to parse entry: %w", n, err)
+		}
+		l.merge(e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+
+	return l, nil
+}
+
+// Compact replaces the log at path with a single entry containing l.
+func Compact(ctx context.Context, path string, c Crypto, recipients []string, l Log) error {
+	line, err := encode(ctx, c, recipients, l)
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, line, 0o600)
+}
+
+// Unused returns the names that haven't been used since cutoff, sorted.
+func (l Log) Unused(names []string, cutoff time.Time) []string {
+	unused := make([]string, 0, len(names))
+	for _, name := range names {
+		if t, found := l[name]; !found || t.Before(cutoff) {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// merge keeps the latest use of every secret.
+func (l Log) merge(o Log) {
+	for name, t := range o {
+		if t.After(l[name]) {
+			l[name] = t
+		}
+	}
+}
+
+func encode(ctx context.Context, c Crypto, recipients []string, l Log) ([]byte, error) {
+	buf, err := json.Marshal(l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode entry: %w", err)
+	}
+	ciphertext, err := c.Encrypt(ctx, buf, recipients)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt entry: %w", err)
+	}
+	return []byte(base64.StdEncoding.EncodeToString(ciphertext) + "\n"), nil
+}
+
+// ParseAge parses ages like 1y, 6w or 90d. Go durations (e.g. 12h) are
+// accepted as well.
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	units := map[string]time.Duration{
+		"d": day,
+		"w": 7 * day,
+		"y": 365 * day,
+	}
+	for suffix, unit := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) && n >= 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+
+	return 0, fmt.Errorf("invalid age %q. Use e.g. 1y, 6w or 90d", s)
+}
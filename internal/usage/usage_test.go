@@ -0,0 +1,74 @@
+package usage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordRead(t *testing.T) {
+	ctx := context.Background()
+	c := plain.New()
+	fn := filepath.Join(t.TempDir(), "sub", "usage.log")
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, Record(ctx, fn, c, nil, "foo/bar", now.Add(-400*day)))
+	require.NoError(t, Record(ctx, fn, c, nil, "foo/baz", now.Add(-10*day)))
+	require.NoError(t, Record(ctx, fn, c, nil, "foo/bar", now.Add(-500*day)))
+
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	assert.NotContains(t, string(buf), "foo/bar")
+
+	l, err := Read(ctx, fn, c)
+	require.NoError(t, err)
+	assert.Equal(t, Log{
+		"foo/bar": now.Add(-400 * day),
+		"foo/baz": now.Add(-10 * day),
+	}, l)
+
+	unused := l.Unused([]string{"foo/qux", "foo/baz", "foo/bar"}, now.Add(-365*day))
+	assert.Equal(t, []string{"foo/bar", "foo/qux"}, unused)
+
+	require.NoError(t, Compact(ctx, fn, c, nil, l))
+	buf, err = os.ReadFile(fn)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(buf), "\n"))
+
+	l2, err := Read(ctx, fn, c)
+	require.NoError(t, err)
+	assert.Equal(t, l, l2)
+}
+
+func TestReadMissing(t *testing.T) {
+	l, err := Read(context.Background(), filepath.Join(t.TempDir(), "usage.log"), plain.New())
+	require.NoError(t, err)
+	assert.Empty(t, l)
+}
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]time.Duration{
+		"1y":  365 * day,
+		"6w":  42 * day,
+		"90d": 90 * day,
+		"12h": 12 * time.Hour,
+	} {
+		got, err := ParseAge(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "y", "-1y", "soon"} {
+		_, err := ParseAge(in)
+		assert.Error(t, err, in)
+	}
+}
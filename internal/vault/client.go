@@ -0,0 +1,208 @@
+// Package vault implements a minimal client for the KV version 2 secrets
+// engine of HashiCorp Vault.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Client talks to a single KV v2 mount of a Vault server.
+type Client struct {
+	addr      string
+	mount     string
+	token     string
+	namespace string
+	hc        *http.Client
+}
+
+// Config holds the connection settings. Empty values are taken from the
+// environment variables the vault CLI uses.
+type Config struct {
+	Addr      string // VAULT_ADDR
+	Token     string // VAULT_TOKEN
+	Namespace string // VAULT_NAMESPACE
+	RoleID    string // VAULT_ROLE_ID
+	SecretID  string // VAULT_SECRET_ID
+}
+
+// New returns a client for the given KV v2 mount. If no token is configured
+// it logs in using AppRole.
+func New(ctx context.Context, mount string, cfg Config) (*Client, error) {
+	fromEnv(&cfg.Addr, "VAULT_ADDR")
+	fromEnv(&cfg.Token, "VAULT_TOKEN")
+	fromEnv(&cfg.Namespace, "VAULT_NAMESPACE")
+	fromEnv(&cfg.RoleID, "VAULT_ROLE_ID")
+	fromEnv(&cfg.SecretID, "VAULT_SECRET_ID")
+
+	if cfg.Addr == "" {
+		return nil, fmt.Errorf("no Vault address. Please set VAULT_ADDR")
+	}
+	mount = strings.Trim(mount, "/")
+	if mount == "" {
+		return nil, fmt.Errorf("no mount given")
+	}
+
+	c := &Client{
+		addr:      strings.TrimSuffix(cfg.Addr, "/"),
+		mount:     mount,
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		hc:        &http.Client{Timeout: 30 * time.Second},
+	}
+	if c.token != "" {
+		return c, nil
+	}
+
+	if cfg.RoleID == "" {
+		return nil, fmt.Errorf("no credentials. Please set VAULT_TOKEN or VAULT_ROLE_ID and VAULT_SECRET_ID")
+	}
+	if err := c.loginAppRole(ctx, cfg.RoleID, cfg.SecretID); err != nil {
+		return nil, fmt.Errorf("AppRole login failed: %w", err)
+	}
+
+	return c, nil
+}
+
+func fromEnv(v *string, key string) {
+	if *v == "" {
+		*v = os.Getenv(key)
+	}
+}
+
+func (c *Client) loginAppRole(ctx context.Context, roleID, secretID string) error {
+	var resp struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	req := map[string]string{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	}
+	if err := c.do(ctx, http.MethodPost, "auth/approle/login", req, &resp); err != nil {
+		return err
+	}
+	if resp.Auth.ClientToken == "" {
+		return fmt.Errorf("no token in response")
+	}
+	c.token = resp.Auth.ClientToken
+
+	return nil
+}
+
+// List returns the names of all secrets below dir, relative to dir.
+func (c *Client) List(ctx context.Context, dir string) ([]string, error) {
+	dir = strings.Trim(dir, "/")
+
+	var resp struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	err := c.do(ctx, "LIST", path.Join(c.mount, "metadata", dir), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, k := range resp.Data.Keys {
+		if !strings.HasSuffix(k, "/") {
+			names = append(names, k)
+			continue
+		}
+		sub, err := c.List(ctx, path.Join(dir, k))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sub {
+			names = append(names, path.Join(k, s))
+		}
+	}
+
+	return names, nil
+}
+
+// Read returns the data of the latest version of a secret.
+func (c *Client) Read(ctx context.Context, name string) (map[string]any, error) {
+	var resp struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, path.Join(c.mount, "data", name), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data.Data, nil
+}
+
+// Write creates a new version of a secret.
+func (c *Client) Write(ctx context.Context, name string, data map[string]any) error {
+	req := map[string]any{
+		"data": data,
+	}
+
+	return c.do(ctx, http.MethodPost, path.Join(c.mount, "data", name), req, nil)
+}
+
+// do sends a request to the Vault API and decodes the JSON response into
+// resp, if given.
+func (c *Client) do(ctx context.Context, method, p string, body, resp any) error {
+	var rd io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+"/v1/"+p, rd)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	debug.Log("%s %s", method, req.URL)
+	res, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		var verr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&verr)
+		if len(verr.Errors) > 0 {
+			return fmt.Errorf("%s %s: %s: %s", method, p, res.Status, strings.Join(verr.Errors, ", "))
+		}
+		return fmt.Errorf("%s %s: %s", method, p, res.Status)
+	}
+
+	if resp == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(resp)
+}
@@ -0,0 +1,189 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault is a minimal in-memory KV v2 server mounted at secret/.
+type fakeVault struct {
+	sync.Mutex
+	token string
+	data  map[string]map[string]any
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	t.Helper()
+
+	fv := &fakeVault{
+		token: "s.test",
+		data:  map[string]map[string]any{},
+	}
+	srv := httptest.NewServer(fv)
+	t.Cleanup(srv.Close)
+
+	return fv, srv
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	p := strings.TrimPrefix(r.URL.Path, "/v1/")
+	if p == "auth/approle/login" {
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["role_id"] != "role" || req["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"` + f.token + `"}}`))
+		return
+	}
+
+	if r.Header.Get("X-Vault-Token") != f.token {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+		return
+	}
+
+	switch {
+	case r.Method == "LIST" && strings.HasPrefix(p, "secret/metadata"):
+		dir := strings.Trim(strings.TrimPrefix(p, "secret/metadata"), "/")
+		if dir != "" {
+			dir += "/"
+		}
+		keys := map[string]bool{}
+		for name := range f.data {
+			if !strings.HasPrefix(name, dir) {
+				continue
+			}
+			rest := strings.TrimPrefix(name, dir)
+			if i := strings.Index(rest, "/"); i >= 0 {
+				rest = rest[:i+1]
+			}
+			keys[rest] = true
+		}
+		if len(keys) < 1 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		resp := struct {
+			Data struct {
+				Keys []string `json:"keys"`
+			} `json:"data"`
+		}{}
+		for k := range keys {
+			resp.Data.Keys = append(resp.Data.Keys, k)
+		}
+		sort.Strings(resp.Data.Keys)
+		_ = json.NewEncoder(w).Encode(resp)
+	case r.Method == http.MethodGet && strings.HasPrefix(p, "secret/data/"):
+		d, found := f.data[strings.TrimPrefix(p, "secret/data/")]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": d}})
+	case r.Method == http.MethodPost && strings.HasPrefix(p, "secret/data/"):
+		var req struct {
+			Data map[string]any `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.data[strings.TrimPrefix(p, "secret/data/")] = req.Data
+		_, _ = w.Write([]byte(`{"data":{"version":1}}`))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	fv, srv := newFakeVault(t)
+	fv.data["app/db"] = map[string]any{"password": "s3cret"}
+	fv.data["app/api/token"] = map[string]any{"password": "t0ken", "port": float64(8080)}
+	fv.data["other"] = map[string]any{"password": "foo"}
+
+	c, err := New(ctx, "secret/", Config{Addr: srv.URL, Token: fv.token})
+	require.NoError(t, err)
+
+	names, err := c.List(ctx, "app")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api/token", "db"}, names)
+
+	names, err = c.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app/api/token", "app/db", "other"}, names)
+
+	data, err := c.Read(ctx, "app/api/token")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"password": "t0ken", "port": float64(8080)}, data)
+
+	require.NoError(t, c.Write(ctx, "new/entry", map[string]any{"password": "bar"}))
+	assert.Equal(t, map[string]any{"password": "bar"}, fv.data["new/entry"])
+
+	_, err = c.Read(ctx, "nope")
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	fv, srv := newFakeVault(t)
+	fv.data["foo"] = map[string]any{"password": "bar"}
+
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_ROLE_ID", "")
+	t.Setenv("VAULT_SECRET_ID", "")
+
+	t.Run("no address", func(t *testing.T) {
+		_, err := New(ctx, "secret", Config{Token: "foo"})
+		assert.Error(t, err)
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		_, err := New(ctx, "secret", Config{Addr: srv.URL})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		c, err := New(ctx, "secret", Config{Addr: srv.URL, Token: "invalid"})
+		require.NoError(t, err)
+		_, err = c.Read(ctx, "foo")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "permission denied")
+	})
+
+	t.Run("approle", func(t *testing.T) {
+		t.Setenv("VAULT_ADDR", srv.URL)
+		t.Setenv("VAULT_ROLE_ID", "role")
+		t.Setenv("VAULT_SECRET_ID", "secret")
+
+		c, err := New(ctx, "secret", Config{})
+		require.NoError(t, err)
+		data, err := c.Read(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "bar", data["password"])
+	})
+
+	t.Run("approle invalid", func(t *testing.T) {
+		_, err := New(ctx, "secret", Config{Addr: srv.URL, RoleID: "role", SecretID: "wrong"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid role or secret ID")
+	})
+}
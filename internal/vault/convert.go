@@ -0,0 +1,82 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gopasspw/gopass/pkg/gopass"
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+)
+
+const (
+	// PasswordKey holds the password of a gopass secret in Vault.
+	PasswordKey = "password"
+	// BodyKey holds the body of a gopass secret in Vault.
+	BodyKey = "body"
+)
+
+// FromSecret converts a gopass secret into Vault secret data. The password
+// and body are stored under PasswordKey and BodyKey, all other keys with
+// their (first) value.
+func FromSecret(sec gopass.Secret) map[string]any {
+	data := make(map[string]any, len(sec.Keys())+2)
+	for _, k := range sec.Keys() {
+		v, _ := sec.Get(k)
+		data[k] = v
+	}
+	if pw := sec.Password(); pw != "" {
+		data[PasswordKey] = pw
+	}
+	if body := sec.Body(); body != "" {
+		data[BodyKey] = body
+	}
+
+	return data
+}
+
+// ToSecret converts Vault secret data into a gopass secret. It's the inverse
+// of FromSecret. Values that aren't strings are stored as JSON.
+func ToSecret(data map[string]any) (gopass.Secret, error) {
+	sec := secrets.NewKV()
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, err := toString(data[k])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", k, err)
+		}
+		switch k {
+		case PasswordKey:
+			sec.SetPassword(v)
+		case BodyKey:
+			_, _ = sec.Write([]byte(v))
+		default:
+			if err := sec.Set(k, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return sec, nil
+}
+
+func toString(v any) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case nil:
+		return "", nil
+	default:
+		buf, err := json.Marshal(x)
+		if err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+}
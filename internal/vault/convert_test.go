@@ -0,0 +1,32 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/gopass/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	sec := secrets.NewKVWithData("s3cret", map[string][]string{"user": {"admin"}}, "some notes\n", false)
+
+	data := FromSecret(sec)
+	assert.Equal(t, map[string]any{
+		"password": "s3cret",
+		"user":     "admin",
+		"body":     "some notes\n",
+	}, data)
+
+	sec2, err := ToSecret(data)
+	require.NoError(t, err)
+	assert.Equal(t, string(sec.Bytes()), string(sec2.Bytes()))
+
+	sec3, err := ToSecret(map[string]any{"port": float64(8080), "tags": []any{"a", "b"}, "empty": nil})
+	require.NoError(t, err)
+	assert.Equal(t, "", sec3.Password())
+	v, _ := sec3.Get("port")
+	assert.Equal(t, "8080", v)
+	v, _ = sec3.Get("tags")
+	assert.Equal(t, `["a","b"]`, v)
+}
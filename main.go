@@ -7,30 +7,36 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	rdebug "runtime/debug"
-	"runtime/pprof"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/fatih/color"
 	ap "github.com/gopasspw/gopass/internal/action"
 	"github.com/gopasspw/gopass/internal/action/pwgen"
+	"github.com/gopasspw/gopass/internal/backend"
 	_ "github.com/gopasspw/gopass/internal/backend/crypto"
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
 	_ "github.com/gopasspw/gopass/internal/backend/storage"
 	"github.com/gopasspw/gopass/internal/config"
+	"github.com/gopasspw/gopass/internal/i18n"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/queue"
 	"github.com/gopasspw/gopass/internal/store/leaf"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
-	"github.com/gopasspw/gopass/pkg/debug"
+	"github.com/gopasspw/gopass/pkg/fsutil"
 	"github.com/gopasspw/gopass/pkg/protect"
 	"github.com/gopasspw/gopass/pkg/termio"
 	colorable "github.com/mattn/go-colorable"
@@ -52,7 +58,8 @@ func main() {
 	// Example: https://go.dev/play/p/8214zCX6hVq.
 	defer writeCPUProfile()()
 
-	if err := protect.Pledge("stdio rpath wpath cpath tty proc exec"); err != nil {
+	// the command isn't known yet, Before drops the promises it doesn't need.
+	if err := protect.Pledge("stdio rpath wpath cpath tty proc exec unix inet dns unveil"); err != nil {
 		panic(err)
 	}
 	ctx := context.Background()
@@ -79,6 +86,15 @@ func main() {
 	sv := getVersion()
 	cli.VersionPrinter = makeVersionPrinter(os.Stdout, sv)
 
+	// ssh-add invokes SSH_ASKPASS without any gopass arguments
+	if os.Getenv(ap.SSHAskPassEnv) != "" {
+		os.Args = []string{os.Args[0], "ssh", "askpass"}
+	}
+	// ansible invokes vault password client scripts as `<name>-client --vault-id <id>`
+	if strings.HasSuffix(filepath.Base(os.Args[0]), ap.AnsibleClientSuffix) {
+		os.Args = append([]string{os.Args[0], "ansible", "vault-client"}, os.Args[1:]...)
+	}
+
 	// run the app
 	q := queue.New(ctx)
 	ctx = queue.WithQueue(ctx, q)
@@ -123,22 +139,124 @@ func setupApp(ctx context.Context, sv semver.Version) (context.Context, *cli.App
 		action.Complete(c)
 	}
 
-	app.Flags = ap.ShowFlags()
+	app.Flags = append(ap.ShowFlags(), &cli.BoolFlag{
+		Name:  "demo",
+		Usage: "Try gopass with a temporary, unencrypted store containing example secrets",
+	}, &cli.BoolFlag{
+		Name:  "profile",
+		Usage: "Report how long key lookups, decryption, git and rendering took",
+	}, &cli.StringFlag{
+		Name:  "profile-dir",
+		Usage: "Write CPU and heap profiles to this directory. Implies --profile",
+	}, &cli.BoolFlag{
+		Name:  "porcelain",
+		Usage: "Print errors as JSON to stderr",
+	})
+	var cleanupDemo, stopProfiling func()
+	app.Before = func(c *cli.Context) error {
+		if encryptionDisabled() && !c.Bool("demo") {
+			if err := checkPlaintextStores(cfg); err != nil {
+				return ap.ExitError(ap.ExitConfig, err, "%s", err)
+			}
+		}
+		if c.Bool("profile") || c.IsSet("profile-dir") {
+			ctx, stop, err := startProfiling(c.Context, c.String("profile-dir"), os.Stderr)
+			if err != nil {
+				return ap.ExitError(ap.ExitUnknown, err, "failed to start profiling: %s", err)
+			}
+			c.Context = ctx
+			stopProfiling = stop
+		}
+		if c.Bool("demo") {
+			ctx, cleanup, err := action.InitDemo(c.Context)
+			if err != nil {
+				return ap.ExitError(ap.ExitUnknown, err, "failed to initialize demo: %s", err)
+			}
+			out.Warning(ctx, "Demo mode: Using a temporary store WITHOUT ENCRYPTION. All changes will be lost.")
+			c.Context = ctx
+			cleanupDemo = cleanup
+		}
+		if err := action.Unveil(c.Context, commandName(c)); err != nil {
+			return ap.ExitError(ap.ExitUnknown, err, "failed to restrict file system access: %s", err)
+		}
+		if err := action.Pledge(c.Context, commandName(c), c.Args().Tail()); err != nil {
+			return ap.ExitError(ap.ExitUnknown, err, "failed to restrict system calls: %s", err)
+		}
+		return nil
+	}
+	app.After = func(c *cli.Context) error {
+		if cleanupDemo != nil {
+			cleanupDemo()
+			cleanupDemo = nil
+		}
+		if stopProfiling != nil {
+			stopProfiling()
+			stopProfiling = nil
+		}
+		return nil
+	}
+	// failing commands exit before After is run
+	handleExitErr := exitErrHandler(os.Stderr)
+	app.ExitErrHandler = func(c *cli.Context, err error) {
+		if err != nil && stopProfiling != nil {
+			stopProfiling()
+			stopProfiling = nil
+		}
+		if err != nil && cleanupDemo != nil {
+			cleanupDemo()
+			cleanupDemo = nil
+		}
+		handleExitErr(c, err)
+	}
 	app.Action = func(c *cli.Context) error {
 		if err := action.IsInitialized(c); err != nil {
 			return err
 		}
 
 		if c.Args().Present() {
-			return action.Show(c)
+			if found, err := action.RunExtension(c); found {
+				return err
+			}
+			return action.AuditLogged("show", action.Show)(c)
 		}
 		return action.REPL(c)
 	}
 
 	app.Commands = getCommands(action, app)
+	translateCommands(ctx, app.Commands)
+	app.Usage = i18n.T(ctx, app.Usage)
+	for _, f := range app.Flags {
+		translateFlag(ctx, f)
+	}
 	return ctx, app
 }
 
+// translateCommands replaces the help texts of the commands and their flags
+// with their translation, if there is one.
+func translateCommands(ctx context.Context, cmds []*cli.Command) {
+	for _, cmd := range cmds {
+		cmd.Usage = i18n.T(ctx, cmd.Usage)
+		cmd.Description = i18n.T(ctx, cmd.Description)
+		for _, f := range cmd.Flags {
+			translateFlag(ctx, f)
+		}
+		translateCommands(ctx, cmd.Subcommands)
+	}
+}
+
+func translateFlag(ctx context.Context, f cli.Flag) {
+	switch f := f.(type) {
+	case *cli.BoolFlag:
+		f.Usage = i18n.T(ctx, f.Usage)
+	case *cli.StringFlag:
+		f.Usage = i18n.T(ctx, f.Usage)
+	case *cli.IntFlag:
+		f.Usage = i18n.T(ctx, f.Usage)
+	case *cli.StringSliceFlag:
+		f.Usage = i18n.T(ctx, f.Usage)
+	}
+}
+
 func getCommands(action *ap.Action, app *cli.App) []*cli.Command {
 	cmds := []*cli.Command{
 		{
@@ -177,6 +295,39 @@ func getCommands(action *ap.Action, app *cli.App) []*cli.Command {
 	return cmds
 }
 
+// exitErrHandler returns the handler for errors returned by the commands.
+// With --porcelain the error is written as JSON to w, so scripts don't have
+// to parse the message.
+func exitErrHandler(w io.Writer) cli.ExitErrHandlerFunc {
+	return func(c *cli.Context, err error) {
+		if err == nil {
+			return
+		}
+		if c == nil || !c.Bool("porcelain") {
+			cli.HandleExitCoder(err)
+			return
+		}
+		perr := ap.NewPorcelainError(err)
+		if err := json.NewEncoder(w).Encode(perr); err != nil {
+			fmt.Fprintln(w, perr.Message)
+		}
+		cli.OsExiter(perr.Code)
+	}
+}
+
+// commandName returns the name of the command that is going to run. Anything
+// that isn't a command or an extension is the name of a secret for the default
+// command.
+func commandName(c *cli.Context) string {
+	if cmd := c.App.Command(c.Args().First()); cmd != nil {
+		return cmd.Name
+	}
+	if _, found := ap.LookupExtension(c.Args().First()); found {
+		return ap.ExtensionPrefix + c.Args().First()
+	}
+	return "show"
+}
+
 func parseBuildInfo() (string, string, string) {
 	bi, ok := rdebug.ReadBuildInfo()
 	if !ok {
@@ -241,12 +392,46 @@ func (e errorWriter) Write(p []byte) (int, error) {
 	return e.out.Write([]byte("\n" + color.RedString("Error: %s", p)))
 }
 
+// encryptionDisabled returns true if GOPASS_DISABLE_ENCRYPTION forces the
+// plaintext backend for all stores.
+func encryptionDisabled() bool {
+	disabled, _ := strconv.ParseBool(os.Getenv("GOPASS_DISABLE_ENCRYPTION"))
+	return disabled
+}
+
+// checkPlaintextStores makes sure that GOPASS_DISABLE_ENCRYPTION is only used
+// with stores that don't exist yet or already use the plaintext backend, so
+// secrets are never written unencrypted into a real store.
+func checkPlaintextStores(cfg *config.Config) error {
+	paths := map[string]string{"<root>": cfg.Path}
+	for mp, p := range cfg.Mounts {
+		paths[mp] = p
+	}
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := fsutil.CleanPath(paths[name])
+		if entries, err := os.ReadDir(p); err != nil || len(entries) < 1 {
+			continue
+		}
+		if !fsutil.IsFile(filepath.Join(p, plain.IDFile)) {
+			return fmt.Errorf("GOPASS_DISABLE_ENCRYPTION is set, but the store %s at %s is encrypted. Refusing to write plaintext secrets into it", name, p)
+		}
+	}
+
+	return nil
+}
+
 func initContext(ctx context.Context, cfg *config.Config) context.Context {
 	// initialize from config, may be overridden by env vars
 	ctx = cfg.WithContext(ctx)
 
-	// always trust
-	ctx = gpg.WithAlwaysTrust(ctx, true)
+	// always trust by default, stores can configure another trust model
+	ctx = gpg.WithTrustModel(ctx, gpg.TrustAlways)
 
 	// check recipients conflicts with always trust, make sure it's not enabled
 	// when always trust is
@@ -261,6 +446,17 @@ func initContext(ctx context.Context, cfg *config.Config) context.Context {
 		ctx = ctxutil.WithInteractive(ctx, false)
 	}
 
+	// use the plaintext mock backend. This is meant for tests and demos only,
+	// never use it with real secrets. See checkPlaintextStores.
+	if encryptionDisabled() {
+		ctx = backend.WithCryptoBackend(ctx, backend.Plain)
+	}
+
+	// use a different prompter, e.g. zenity, if requested
+	if p := termio.PrompterFromEnv(); p != nil {
+		ctx = termio.WithPrompter(ctx, p)
+	}
+
 	// reading from stdin?
 	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
 		ctx = ctxutil.WithInteractive(ctx, false)
@@ -283,20 +479,11 @@ func writeCPUProfile() func() {
 		return func() {}
 	}
 
-	f, err := os.Create(cp)
+	stop, err := startCPUProfile(cp)
 	if err != nil {
-		log.Fatalf("could not create CPU profile at %s: %s", cp, err)
-	}
-
-	if err := pprof.StartCPUProfile(f); err != nil {
-		log.Fatalf("could not start CPU profile: %s", err)
-	}
-
-	return func() {
-		pprof.StopCPUProfile()
-		f.Close()
-		debug.Log("wrote CPU profile to %s", cp)
+		log.Fatal(err)
 	}
+	return stop
 }
 
 func writeMemProfile() {
@@ -304,15 +491,7 @@ func writeMemProfile() {
 	if mp == "" {
 		return
 	}
-	f, err := os.Create(mp)
-	if err != nil {
-		log.Fatalf("could not write mem profile to %s: %s", mp, err)
-	}
-	defer f.Close()
-
-	runtime.GC() // get up-to-date statistics
-	if err := pprof.WriteHeapProfile(f); err != nil {
-		log.Fatalf("could not write heap profile: %s", err)
+	if err := writeHeapProfile(mp); err != nil {
+		log.Fatal(err)
 	}
-	debug.Log("wrote heap profile to %s", mp)
 }
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/gopasspw/gopass/internal/action"
 	"github.com/gopasspw/gopass/internal/backend"
 	"github.com/gopasspw/gopass/internal/backend/crypto/gpg"
+	"github.com/gopasspw/gopass/internal/backend/crypto/plain"
 	"github.com/gopasspw/gopass/internal/config"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/internal/set"
@@ -55,19 +57,32 @@ var commandsWithError = set.Map([]string{
 	".alias.add",
 	".alias.remove",
 	".alias.delete",
+	".ansible.vault-client",
 	".audit",
+	".audit.recipients",
+	".bundle.apply",
+	".bundle.create",
 	".cat",
+	".ci-export",
 	".clone",
+	".combine",
+	".cloud.sync.aws",
 	".convert",
 	".copy",
 	".create",
 	".delete",
+	".diff",
+	".doctor",
+	".dotenv",
 	".edit",
 	".env",
+	".fido2.enroll",
 	".find",
 	".fscopy",
 	".fsmove",
 	".generate",
+	".git.hook",
+	".git.install-hooks",
 	".git.push",
 	".git.pull",
 	".git.status",
@@ -75,23 +90,46 @@ var commandsWithError = set.Map([]string{
 	".git.remote.remove",
 	".grep",
 	".history",
+	".import.dotenv",
 	".init",
 	".insert",
+	".keys.show",
+	".kubectl.apply",
 	".link",
 	".merge",
+	".migrate",
 	".mounts.add",
+	".mounts.init",
 	".mounts.remove",
 	".move",
 	".otp",
+	".otp.import",
 	".process",
 	".recipients.add",
+	".recipients.deauthorize",
 	".recipients.remove",
+	".recovery.add",
+	".recovery.use",
+	".render",
+	".rotate",
+	".scan",
 	".show",
+	".snapshot.create",
+	".snapshot.rollback",
+	".split",
+	".ssh.add",
+	".ssh.askpass",
+	".ssh.known-hosts",
 	".sum",
 	".templates.edit",
 	".templates.remove",
 	".templates.show",
+	".trust",
+	".vault.export",
+	".vault.import",
+	".watch",
 	".unclip",
+	".unset",
 })
 
 func TestGetCommands(t *testing.T) {
@@ -126,7 +164,7 @@ func TestGetCommands(t *testing.T) {
 	c.Context = ctx
 
 	commands := getCommands(act, app)
-	assert.Equal(t, 39, len(commands))
+	assert.Equal(t, 70, len(commands))
 
 	prefix := ""
 	testCommands(t, c, commands, prefix)
@@ -166,3 +204,86 @@ func TestInitContext(t *testing.T) {
 	ctx = initContext(ctx, cfg)
 	assert.Equal(t, true, gpg.IsAlwaysTrust(ctx))
 }
+
+func TestCheckPlaintextStores(t *testing.T) {
+	td := t.TempDir()
+	cfg := config.New()
+	cfg.Path = filepath.Join(td, "root")
+	cfg.Mounts["work"] = filepath.Join(td, "work")
+
+	// stores that don't exist yet are fine
+	assert.NoError(t, checkPlaintextStores(cfg))
+
+	assert.NoError(t, os.MkdirAll(cfg.Path, 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(cfg.Path, plain.IDFile), []byte("0xDEADBEEF\n"), 0o600))
+	assert.NoError(t, checkPlaintextStores(cfg))
+
+	assert.NoError(t, os.MkdirAll(cfg.Mounts["work"], 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(cfg.Mounts["work"], ".gpg-id"), []byte("0xDEADBEEF\n"), 0o600))
+	err := checkPlaintextStores(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "work")
+}
+
+func TestDemoCleanupOnError(t *testing.T) {
+	td := t.TempDir()
+	// InitDemo changes these, restore them after the test.
+	for _, k := range []string{"GOPASS_HOMEDIR", "GOPASS_CONFIG", "GOPASS_DISABLE_ENCRYPTION", "GNUPGHOME"} {
+		t.Setenv(k, os.Getenv(k))
+	}
+	t.Setenv("TMPDIR", td)
+
+	// a real exit skips app.After, so the demo dir must be gone by then.
+	var code int
+	var demos []string
+	cli.OsExiter = func(rc int) {
+		code = rc
+		demos, _ = filepath.Glob(filepath.Join(td, "gopass-demo-*"))
+	}
+	defer func() {
+		cli.OsExiter = os.Exit
+	}()
+
+	buf := &bytes.Buffer{}
+	out.Stdout = buf
+	out.Stderr = buf
+	defer func() {
+		out.Stdout = os.Stdout
+		out.Stderr = os.Stderr
+	}()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, false)
+	ctx, app := setupApp(ctx, semver.Version{})
+	app.ErrWriter = buf
+	app.Writer = buf
+	_ = app.RunContext(ctx, []string{"gopass", "--demo", "show", "does/not/exist"})
+	assert.NotEqual(t, 0, code)
+	assert.Empty(t, demos)
+}
+
+func TestExitErrHandler(t *testing.T) {
+	var code int
+	cli.OsExiter = func(rc int) { code = rc }
+	defer func() {
+		cli.OsExiter = os.Exit
+	}()
+
+	buf := &bytes.Buffer{}
+	fs := flag.NewFlagSet("default", flag.ContinueOnError)
+	fs.Bool("porcelain", true, "")
+	c := cli.NewContext(cli.NewApp(), fs, nil)
+
+	exitErrHandler(buf)(c, nil)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", buf.String())
+
+	exitErrHandler(buf)(c, action.ExitError(action.ExitNotFound, nil, "entry %s not found", "foo"))
+	assert.Equal(t, action.ExitNotFound, code)
+	assert.JSONEq(t, `{"code":10,"error":"not-found","message":"entry foo not found"}`, buf.String())
+
+	buf.Reset()
+	exitErrHandler(buf)(c, fmt.Errorf("flag provided but not defined"))
+	assert.Equal(t, action.ExitUnknown, code)
+	assert.JSONEq(t, `{"code":1,"error":"unknown","message":"flag provided but not defined"}`, buf.String())
+}
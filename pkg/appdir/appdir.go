@@ -5,6 +5,7 @@ package appdir
 
 import (
 	"os"
+	"runtime"
 
 	"github.com/gopasspw/gopass/pkg/debug"
 )
@@ -14,11 +15,19 @@ var (
 	Name = "gopass"
 )
 
+// termuxHome is the home dir of Termux on Android.
+const termuxHome = "/data/data/com.termux/files/home"
+
 // UserHome returns the users home dir.
 func UserHome() string {
 	if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
 		return hd
 	}
+	// Go falls back to the shared storage which any app can read. HOME is
+	// missing if gopass is started by another app, e.g. through Termux:Tasker.
+	if runtime.GOOS == "android" && os.Getenv("HOME") == "" {
+		return termuxHome
+	}
 
 	uhd, err := os.UserHomeDir()
 	if err != nil {
@@ -16,7 +16,7 @@ func UserConfig() string {
 
 	base := os.Getenv("XDG_CONFIG_HOME")
 	if base == "" {
-		base = filepath.Join(os.Getenv("HOME"), ".config")
+		base = filepath.Join(UserHome(), ".config")
 	}
 
 	return filepath.Join(base, Name)
@@ -30,7 +30,7 @@ func UserCache() string {
 
 	base := os.Getenv("XDG_CACHE_HOME")
 	if base == "" {
-		base = filepath.Join(os.Getenv("HOME"), ".cache")
+		base = filepath.Join(UserHome(), ".cache")
 	}
 
 	return filepath.Join(base, Name)
@@ -44,7 +44,7 @@ func UserData() string {
 
 	base := os.Getenv("XDG_DATA_HOME")
 	if base == "" {
-		base = filepath.Join(os.Getenv("HOME"), ".local", "share")
+		base = filepath.Join(UserHome(), ".local", "share")
 	}
 
 	return filepath.Join(base, Name)
@@ -2,6 +2,7 @@ package clipboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -14,7 +15,7 @@ import (
 var (
 	// Helpers can be overridden at compile time, e.g. go build \
 	// -ldflags=='-X github.com/gopasspw/gopass/pkg/clipboard.Helpers=termux-api'.
-	Helpers = "xsel or xclip"
+	Helpers = "xsel, xclip or wl-clipboard"
 	// ErrNotSupported is returned when the clipboard is not accessible.
 	ErrNotSupported = fmt.Errorf("WARNING: No clipboard available. Install " + Helpers + " or use -f to print to console")
 )
@@ -22,7 +23,7 @@ var (
 // CopyTo copies the given data to the clipboard and enqueues automatic
 // clearing of the clipboard.
 func CopyTo(ctx context.Context, name string, content []byte, timeout int) error {
-	if clipboard.Unsupported {
+	if clipboard.Unsupported && !useWlClipboard() {
 		out.Printf(ctx, "%s", ErrNotSupported)
 		_ = notify.Notify(ctx, "gopass - clipboard", fmt.Sprintf("%s", ErrNotSupported))
 		return nil
@@ -32,6 +33,9 @@ func CopyTo(ctx context.Context, name string, content []byte, timeout int) error
 		_ = notify.Notify(ctx, "gopass - clipboard", "failed to write to clipboard")
 		return fmt.Errorf("failed to write to clipboard: %w", err)
 	}
+	if errors.Is(Check(), ErrX11Only) {
+		out.Warningf(ctx, "Copied with X11 tools only, Wayland applications won't see it. Install wl-clipboard.")
+	}
 
 	if timeout < 1 {
 		timeout = 45
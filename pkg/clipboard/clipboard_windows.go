@@ -8,11 +8,15 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"syscall"
 
 	"github.com/gopasspw/gopass/internal/pwschemes/argon2id"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 )
 
+// detachedProcess makes the child process independent of our console.
+const detachedProcess = 0x00000008
+
 // clear will spwan a copy of gopass that waits in a detached background
 // process group until the timeout is expired. It will then compare the contents
 // of the clipboard and erase it if it still contains the data gopass copied
@@ -23,7 +27,11 @@ func clear(ctx context.Context, content []byte, timeout int) error {
 		return err
 	}
 
-	cmd := exec.CommandContext(ctx, os.Args[0], "unclip", "--timeout", strconv.Itoa(timeout))
+	// not bound to ctx, the process must outlive this one
+	cmd := exec.Command(os.Args[0], "unclip", "--timeout", strconv.Itoa(timeout))
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP,
+	}
 	cmd.Env = append(os.Environ(), "GOPASS_UNCLIP_CHECKSUM="+hash)
 	if !ctxutil.IsNotifications(ctx) {
 		cmd.Env = append(cmd.Env, "GOPASS_NO_NOTIFY=true")
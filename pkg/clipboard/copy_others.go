@@ -1,14 +1,12 @@
-//go:build !darwin
-// +build !darwin
+//go:build !darwin && !windows
+// +build !darwin,!windows
 
 package clipboard
 
 import (
 	"context"
-
-	"github.com/atotto/clipboard"
 )
 
 func copyToClipboard(ctx context.Context, content []byte) error {
-	return clipboard.WriteAll(string(content))
+	return writeClipboard(ctx, content)
 }
@@ -0,0 +1,137 @@
+//go:build windows
+// +build windows
+
+package clipboard
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/atotto/clipboard"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	cfUnicodeText = 13
+	gmemMoveable  = 0x0002
+)
+
+var (
+	user32                  = syscall.NewLazyDLL("user32.dll")
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	openClipboard           = user32.NewProc("OpenClipboard")
+	closeClipboard          = user32.NewProc("CloseClipboard")
+	emptyClipboard          = user32.NewProc("EmptyClipboard")
+	setClipboardData        = user32.NewProc("SetClipboardData")
+	registerClipboardFormat = user32.NewProc("RegisterClipboardFormatW")
+	globalAlloc             = kernel32.NewProc("GlobalAlloc")
+	globalFree              = kernel32.NewProc("GlobalFree")
+	globalLock              = kernel32.NewProc("GlobalLock")
+	globalUnlock            = kernel32.NewProc("GlobalUnlock")
+	moveMemory              = kernel32.NewProc("RtlMoveMemory")
+
+	// historyFormats tell the clipboard history (Win+V) and the cloud
+	// clipboard not to record the content. See
+	// https://learn.microsoft.com/en-us/windows/win32/dataxchg/clipboard-formats#cloud-clipboard-and-clipboard-history-formats
+	historyFormats = []string{
+		"ExcludeClipboardContentFromMonitorProcessing",
+		"CanIncludeInClipboardHistory",
+		"CanUploadToCloudClipboard",
+	}
+)
+
+func copyToClipboard(ctx context.Context, content []byte) error {
+	if err := copyWithoutHistory(string(content)); err != nil {
+		debug.Log("failed to copy without history: %s", err)
+		return clipboard.WriteAll(string(content))
+	}
+	return nil
+}
+
+// copyWithoutHistory writes text to the clipboard and marks it as excluded
+// from the clipboard history.
+func copyWithoutHistory(text string) error {
+	buf, err := syscall.UTF16FromString(text)
+	if err != nil {
+		return err
+	}
+
+	// the clipboard is owned by the thread that opened it
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := open(); err != nil {
+		return err
+	}
+	defer closeClipboard.Call() // nolint:errcheck
+
+	if r, _, err := emptyClipboard.Call(); r == 0 {
+		return fmt.Errorf("failed to empty clipboard: %w", err)
+	}
+
+	if err := setData(cfUnicodeText, unsafe.Pointer(&buf[0]), uintptr(len(buf)*2)); err != nil {
+		return err
+	}
+
+	// all formats expect a DWORD of zero, the first one ignores the content
+	var zero uint32
+	for _, name := range historyFormats {
+		n, err := syscall.UTF16PtrFromString(name)
+		if err != nil {
+			return err
+		}
+		f, _, err := registerClipboardFormat.Call(uintptr(unsafe.Pointer(n)))
+		if f == 0 {
+			return fmt.Errorf("failed to register clipboard format %s: %w", name, err)
+		}
+		if err := setData(f, unsafe.Pointer(&zero), unsafe.Sizeof(zero)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// open opens the clipboard. It might be held by another process for a
+// short time so we try a few times.
+func open() error {
+	var err error
+	for i := 0; i < 10; i++ {
+		var r uintptr
+		r, _, err = openClipboard.Call(0)
+		if r != 0 {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return fmt.Errorf("failed to open clipboard: %w", err)
+}
+
+// setData copies size bytes from data to global memory and hands it over to
+// the clipboard.
+func setData(format uintptr, data unsafe.Pointer, size uintptr) error {
+	h, _, err := globalAlloc.Call(gmemMoveable, size)
+	if h == 0 {
+		return fmt.Errorf("failed to allocate memory: %w", err)
+	}
+
+	p, _, err := globalLock.Call(h)
+	if p == 0 {
+		_, _, _ = globalFree.Call(h)
+		return fmt.Errorf("failed to lock memory: %w", err)
+	}
+	_, _, _ = moveMemory.Call(p, uintptr(data), size)
+	_, _, _ = globalUnlock.Call(h)
+
+	// on success the clipboard owns the memory
+	if r, _, err := setClipboardData.Call(format, h); r == 0 {
+		_, _, _ = globalFree.Call(h)
+		return fmt.Errorf("failed to set clipboard data: %w", err)
+	}
+
+	return nil
+}
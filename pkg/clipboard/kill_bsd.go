@@ -0,0 +1,49 @@
+//go:build freebsd || openbsd || netbsd || dragonfly
+// +build freebsd openbsd netbsd dragonfly
+
+package clipboard
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+// killPrecedessors will kill any previous "gopass unclip" invocations to avoid
+// erasing the clipboard prematurely in case the the same content is copied to
+// the clipboard repeatedly. The BSDs don't mount /proc by default, so pgrep
+// is used to find them.
+func killPrecedessors() error {
+	buf, err := exec.Command("pgrep", "-U", strconv.Itoa(os.Getuid()), "-f", unclipPattern(os.Args[0])).Output()
+	if err != nil {
+		// pgrep exits with 1 if nothing matched
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && ee.ExitCode() == 1 {
+			return nil
+		}
+		return err
+	}
+
+	return parsePids(buf, killProc)
+}
+
+// unclipPattern matches the command line of "gopass unclip" invocations.
+func unclipPattern(bin string) string {
+	return "^" + regexp.QuoteMeta(bin) + " unclip( |$)"
+}
+
+func parsePids(buf []byte, killFn func(int)) error {
+	s := bufio.NewScanner(bytes.NewReader(buf))
+	for s.Scan() {
+		pid, err := strconv.Atoi(s.Text())
+		if err != nil {
+			continue
+		}
+		killFn(pid)
+	}
+	return s.Err()
+}
@@ -1,5 +1,5 @@
-//go:build !darwin && !linux && !solaris && !windows && !freebsd
-// +build !darwin,!linux,!solaris,!windows,!freebsd
+//go:build !darwin && !linux && !solaris && !windows && !freebsd && !openbsd && !netbsd && !dragonfly
+// +build !darwin,!linux,!solaris,!windows,!freebsd,!openbsd,!netbsd,!dragonfly
 
 package clipboard
 
@@ -1,5 +1,5 @@
-//go:build darwin || (freebsd && amd64) || linux || solaris || windows || (freebsd && arm) || (freebsd && arm64)
-// +build darwin freebsd,amd64 linux solaris windows freebsd,arm freebsd,arm64
+//go:build darwin || linux || solaris || windows
+// +build darwin linux solaris windows
 
 package clipboard
 
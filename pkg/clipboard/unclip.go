@@ -12,11 +12,11 @@ import (
 
 // Clear will attempt to erase the clipboard.
 func Clear(ctx context.Context, checksum string, force bool) error {
-	if clipboard.Unsupported {
+	if clipboard.Unsupported && !useWlClipboard() {
 		return ErrNotSupported
 	}
 
-	cur, err := clipboard.ReadAll()
+	cur, err := readClipboard(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to read clipboard: %w", err)
 	}
@@ -30,7 +30,7 @@ func Clear(ctx context.Context, checksum string, force bool) error {
 		return nil
 	}
 
-	if err := clipboard.WriteAll(""); err != nil {
+	if err := writeClipboard(ctx, nil); err != nil {
 		_ = notify.Notify(ctx, "gopass - clipboard", "Failed to clear clipboard")
 		return fmt.Errorf("failed to write clipboard: %w", err)
 	}
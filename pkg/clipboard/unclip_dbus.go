@@ -0,0 +1,79 @@
+//go:build (linux && !android) || openbsd
+// +build linux,!android openbsd
+
+package clipboard
+
+import (
+	"context"
+	"strings"
+
+	"github.com/godbus/dbus"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// clearClipboardHistory purges the history of any known clipboard manager.
+// None of them can tell us which entries contain the secret, so the whole
+// history is cleared.
+func clearClipboardHistory(ctx context.Context) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+
+	if err := clearKlipper(conn); err != nil {
+		return err
+	}
+
+	return clearGPaste(conn)
+}
+
+// clearKlipper clears the history of KDE's klipper.
+func clearKlipper(conn *dbus.Conn) error {
+	obj := conn.Object("org.kde.klipper", "/klipper")
+	call := obj.Call("org.kde.klipper.klipper.clearClipboardHistory", 0)
+	if call.Err != nil && !isNotRunning(call.Err) {
+		return call.Err
+	}
+
+	return nil
+}
+
+// clearGPaste empties the current history of GNOME's GPaste. Newer versions
+// use the GPaste2 interface, older ones only provide GPaste1.
+func clearGPaste(conn *dbus.Conn) error {
+	obj := conn.Object("org.gnome.GPaste", "/org/gnome/GPaste")
+
+	var name string
+	err := obj.Call("org.gnome.GPaste2.GetHistoryName", 0).Store(&name)
+	if err == nil {
+		return obj.Call("org.gnome.GPaste2.EmptyHistory", 0, name).Err
+	}
+	if isNotRunning(err) {
+		return nil
+	}
+	debug.Log("GPaste2 not available, trying GPaste1: %s", err)
+
+	call := obj.Call("org.gnome.GPaste1.Empty", 0)
+	if call.Err != nil && !isNotRunning(call.Err) {
+		return call.Err
+	}
+
+	return nil
+}
+
+// isNotRunning returns true if the error indicates that the clipboard manager
+// is not available on the session bus, i.e. there is nothing to clear.
+func isNotRunning(err error) bool {
+	msg := err.Error()
+	for _, prefix := range []string{
+		"The name org.kde.klipper was not provided",
+		"The name org.gnome.GPaste was not provided",
+		"The name is not activatable",
+	} {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
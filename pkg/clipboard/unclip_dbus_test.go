@@ -0,0 +1,25 @@
+//go:build (linux && !android) || openbsd
+// +build linux,!android openbsd
+
+package clipboard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNotRunning(t *testing.T) {
+	for _, tc := range []struct {
+		err  string
+		want bool
+	}{
+		{"The name org.kde.klipper was not provided by any .service files", true},
+		{"The name org.gnome.GPaste was not provided by any .service files", true},
+		{"The name is not activatable", true},
+		{"No such method 'GetHistoryName'", false},
+	} {
+		assert.Equal(t, tc.want, isNotRunning(fmt.Errorf("%s", tc.err)), tc.err)
+	}
+}
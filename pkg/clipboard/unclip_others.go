@@ -1,5 +1,5 @@
-//go:build !linux
-// +build !linux
+//go:build (!linux && !openbsd) || android
+// +build !linux,!openbsd android
 
 package clipboard
 
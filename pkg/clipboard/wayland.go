@@ -0,0 +1,105 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// Wayland applications can't access the X11 clipboard. X11 tools like xclip
+// still "work" in a Wayland session, but only X11 (XWayland) applications
+// will see what they copied. So wl-clipboard must be used there.
+
+// ErrX11Only is returned by Check if gopass runs in a Wayland session but
+// only X11 clipboard tools are available.
+var ErrX11Only = fmt.Errorf("no Wayland clipboard tool found, only X11 applications can access the clipboard")
+
+// lookPath is exec.LookPath, it's replaced in tests.
+var lookPath = exec.LookPath
+
+// isWayland returns true if gopass runs in a Wayland session.
+func isWayland() bool {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return false
+	}
+	return os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("XDG_SESSION_TYPE") == "wayland"
+}
+
+// hasWlClipboard returns true if wl-copy and wl-paste are installed.
+func hasWlClipboard() bool {
+	for _, bin := range []string{"wl-copy", "wl-paste"} {
+		if _, err := lookPath(bin); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// useWlClipboard returns true if the clipboard must be accessed with
+// wl-clipboard.
+func useWlClipboard() bool {
+	return isWayland() && hasWlClipboard()
+}
+
+// Check returns an error if the clipboard is not available or if copied
+// secrets would not be visible to all applications.
+func Check() error {
+	if useWlClipboard() {
+		return nil
+	}
+	if clipboard.Unsupported {
+		return ErrNotSupported
+	}
+	if isWayland() {
+		return ErrX11Only
+	}
+	return nil
+}
+
+// readClipboard returns the current content of the clipboard.
+func readClipboard(ctx context.Context) (string, error) {
+	if !useWlClipboard() {
+		return clipboard.ReadAll()
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "wl-paste", "--no-newline")
+	cmd.Stderr = stderr
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	buf, err := cmd.Output()
+	if err != nil {
+		// wl-paste fails if the clipboard is empty
+		if strings.Contains(stderr.String(), "Nothing is copied") {
+			return "", nil
+		}
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(buf), nil
+}
+
+// writeClipboard replaces the content of the clipboard. Empty content
+// clears the clipboard.
+func writeClipboard(ctx context.Context, content []byte) error {
+	if !useWlClipboard() {
+		return clipboard.WriteAll(string(content))
+	}
+
+	args := []string{}
+	if len(content) < 1 {
+		args = append(args, "--clear")
+	}
+	// wl-copy forks to serve the content and returns right away. The fork
+	// inherits stdout and stderr, so they must not be captured. Otherwise
+	// Run would wait until the content is replaced.
+	cmd := exec.CommandContext(ctx, "wl-copy", args...)
+	cmd.Stdin = bytes.NewReader(content)
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+	return cmd.Run()
+}
@@ -0,0 +1,59 @@
+package clipboard
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/atotto/clipboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("no Wayland")
+	}
+
+	ou := clipboard.Unsupported
+	defer func() {
+		clipboard.Unsupported = ou
+		lookPath = exec.LookPath
+	}()
+
+	wlClipboard := func(found bool) {
+		lookPath = func(bin string) (string, error) {
+			if found {
+				return "/usr/bin/" + bin, nil
+			}
+			return "", fmt.Errorf("not found")
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		display     string
+		session     string
+		wl          bool
+		unsupported bool
+		err         error
+	}{
+		{name: "x11", session: "x11"},
+		{name: "x11 without tools", session: "x11", unsupported: true, err: ErrNotSupported},
+		{name: "wayland", display: "wayland-0", wl: true, unsupported: true},
+		{name: "wayland session", session: "wayland", wl: true},
+		{name: "wayland with x11 tools", display: "wayland-0", err: ErrX11Only},
+		{name: "wayland without tools", display: "wayland-0", unsupported: true, err: ErrNotSupported},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("WAYLAND_DISPLAY", tc.display)
+			t.Setenv("XDG_SESSION_TYPE", tc.session)
+			wlClipboard(tc.wl)
+			clipboard.Unsupported = tc.unsupported
+
+			assert.Equal(t, tc.display != "" || tc.session == "wayland", isWayland())
+			assert.Equal(t, tc.err, Check())
+		})
+	}
+}
@@ -0,0 +1,22 @@
+//go:build android
+// +build android
+
+package fsutil
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// ignoreChmodError returns true if a failed chmod can be ignored. The shared
+// storage on Android (e.g. ~/storage/shared in Termux) doesn't support file
+// permissions and always rejects chmod.
+func ignoreChmodError(err error) bool {
+	if !errors.Is(err, fs.ErrPermission) {
+		return false
+	}
+	debug.Log("file permissions are not supported here: %s", err)
+	return true
+}
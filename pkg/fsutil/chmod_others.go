@@ -0,0 +1,8 @@
+//go:build !android
+// +build !android
+
+package fsutil
+
+func ignoreChmodError(err error) bool {
+	return false
+}
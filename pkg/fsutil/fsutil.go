@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gopasspw/gopass/pkg/appdir"
 	"github.com/gopasspw/gopass/pkg/debug"
 )
 
@@ -28,8 +29,11 @@ func CleanPath(path string) string {
 	// http://stackoverflow.com/questions/17609732/expand-tilde-to-home-directory
 	// TODO(GH-2083): We should consider if we really want to rewrite ~
 	if len(path) > 1 && path[:2] == "~/" {
-		usr, _ := user.Current()
-		dir := usr.HomeDir
+		// user.Current fails if there is no /etc/passwd, e.g. on Android
+		dir := appdir.UserHome()
+		if usr, err := user.Current(); err == nil {
+			dir = usr.HomeDir
+		}
 		if hd := os.Getenv("GOPASS_HOMEDIR"); hd != "" {
 			dir = hd
 		}
@@ -41,6 +45,50 @@ func CleanPath(path string) string {
 	return filepath.Clean(path)
 }
 
+// WriteFileAtomic writes data to a temporary file in the same directory as
+// path, syncs it to disk and renames it to path. Readers (and crashes) will
+// see either the old or the new content but never a partially written file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	fh, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmp := fh.Name()
+	defer func() {
+		// no-op if the rename succeeded
+		_ = os.Remove(tmp)
+	}()
+
+	if _, err := fh.Write(data); err != nil {
+		_ = fh.Close()
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := fh.Sync(); err != nil {
+		_ = fh.Close()
+		return fmt.Errorf("failed to sync %s: %w", tmp, err)
+	}
+	if err := fh.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", tmp, err)
+	}
+	// unlike os.WriteFile chmod doesn't apply the umask
+	if err := os.Chmod(tmp, perm&^os.FileMode(Umask())); err != nil && !ignoreChmodError(err) {
+		return fmt.Errorf("failed to set permissions of %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", tmp, path, err)
+	}
+
+	// persist the rename. Not all platforms support syncing directories.
+	if dh, err := os.Open(dir); err == nil {
+		if err := dh.Sync(); err != nil {
+			debug.Log("failed to sync dir %s: %s", dir, err)
+		}
+		_ = dh.Close()
+	}
+	return nil
+}
+
 // IsDir checks if a certain path exists and is a directory.
 // https://stackoverflow.com/questions/10510691/how-to-check-whether-a-file-or-directory-denoted-by-a-path-exists-in-golang
 func IsDir(path string) bool {
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -140,3 +141,36 @@ func TestIsEmptyDir(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, false, isEmpty)
 }
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Setenv("GOPASS_UMASK", "022")
+
+	td := t.TempDir()
+	fn := filepath.Join(td, "foo.gpg")
+
+	require.NoError(t, WriteFileAtomic(fn, []byte("foo"), 0644))
+	require.NoError(t, WriteFileAtomic(fn, []byte("bar"), 0644))
+
+	buf, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(buf))
+
+	// no temp files are left behind
+	files, err := os.ReadDir(td)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(fn)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), fi.Mode().Perm())
+
+		t.Setenv("GOPASS_UMASK", "077")
+		require.NoError(t, WriteFileAtomic(fn, []byte("baz"), 0644))
+		fi, err = os.Stat(fn)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	}
+
+	assert.Error(t, WriteFileAtomic(filepath.Join(td, "missing", "foo"), []byte("foo"), 0644))
+}
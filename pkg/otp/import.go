@@ -0,0 +1,62 @@
+package otp
+
+import (
+	"fmt"
+	"image"
+	// register the formats most likely used for screenshots of QR codes.
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// Import returns the otpauth:// URLs contained in src. It can either be an
+// otpauth:// URL, an otpauth-migration:// URI or the path to an image of a QR
+// code containing one of them.
+func Import(src string) ([]string, error) {
+	if !strings.Contains(src, "://") {
+		fh, err := os.Open(src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", src, err)
+		}
+		defer fh.Close()
+
+		src, err = DecodeQR(fh)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(src, "otpauth://"):
+		return []string{src}, nil
+	case strings.HasPrefix(src, MigrationScheme+"://"):
+		return ParseMigrationURI(src)
+	default:
+		return nil, fmt.Errorf("not an OTP URL: %q", src)
+	}
+}
+
+// DecodeQR reads the image from r and returns the content of the QR code in it.
+func DecodeQR(r io.Reader) (string, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	res, err := qrcode.NewQRCodeReader().Decode(bmp, nil)
+	if err != nil {
+		return "", fmt.Errorf("no QR code found: %w", err)
+	}
+
+	return res.GetText(), nil
+}
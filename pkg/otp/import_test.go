@@ -0,0 +1,40 @@
+package otp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport(t *testing.T) {
+	urls, err := Import(totpURL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{totpURL}, urls)
+
+	_, err = Import("https://example.com")
+	assert.Error(t, err)
+
+	_, err = Import(filepath.Join(t.TempDir(), "missing.png"))
+	assert.Error(t, err)
+
+	t.Run("QR code", func(t *testing.T) {
+		fn := filepath.Join(t.TempDir(), "qr.png")
+		require.NoError(t, qrcode.WriteFile(totpURL, qrcode.Medium, 256, fn))
+
+		urls, err := Import(fn)
+		require.NoError(t, err)
+		assert.Equal(t, []string{totpURL}, urls)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		fn := filepath.Join(t.TempDir(), "qr.png")
+		require.NoError(t, os.WriteFile(fn, []byte("foo"), 0600))
+
+		_, err := Import(fn)
+		assert.Error(t, err)
+	})
+}
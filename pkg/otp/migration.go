@@ -0,0 +1,216 @@
+package otp
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MigrationScheme is the URL scheme used by Google Authenticator to export
+// accounts.
+const MigrationScheme = "otpauth-migration"
+
+// ParseMigrationURI decodes an otpauth-migration:// URI as exported by Google
+// Authenticator and returns one otpauth:// URL for each account it contains.
+//
+// The data parameter holds a base64 encoded protobuf message:
+//
+//	message MigrationPayload {
+//	  repeated OtpParameters otp_parameters = 1;
+//	  ...
+//	}
+//	message OtpParameters {
+//	  bytes secret = 1;
+//	  string name = 2;
+//	  string issuer = 3;
+//	  Algorithm algorithm = 4;  // 1: SHA1, 2: SHA256, 3: SHA512, 4: MD5
+//	  DigitCount digits = 5;    // 1: six, 2: eight
+//	  OtpType type = 6;         // 1: HOTP, 2: TOTP
+//	  int64 counter = 7;
+//	}
+func ParseMigrationURI(uri string) ([]string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URI: %w", err)
+	}
+	if u.Scheme != MigrationScheme {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	data := u.Query().Get("data")
+	if data == "" {
+		return nil, fmt.Errorf("URI has no data")
+	}
+	// some tools don't escape the data, i.e. '+' would turn into ' '
+	data = strings.ReplaceAll(data, " ", "+")
+	buf, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data: %w", err)
+	}
+
+	var urls []string
+	err = walkFields(buf, func(num int, val []byte, _ uint64) error {
+		if num != 1 {
+			return nil
+		}
+		p, err := parseOTPParameters(val)
+		if err != nil {
+			return err
+		}
+		urls = append(urls, p.URL())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	if len(urls) < 1 {
+		return nil, fmt.Errorf("no accounts found")
+	}
+
+	return urls, nil
+}
+
+type otpParameters struct {
+	secret    []byte
+	name      string
+	issuer    string
+	algorithm uint64
+	digits    uint64
+	typ       uint64
+	counter   uint64
+}
+
+func parseOTPParameters(buf []byte) (otpParameters, error) {
+	p := otpParameters{}
+	err := walkFields(buf, func(num int, val []byte, v uint64) error {
+		switch num {
+		case 1:
+			p.secret = val
+		case 2:
+			p.name = string(val)
+		case 3:
+			p.issuer = string(val)
+		case 4:
+			p.algorithm = v
+		case 5:
+			p.digits = v
+		case 6:
+			p.typ = v
+		case 7:
+			p.counter = v
+		}
+		return nil
+	})
+	if err != nil {
+		return p, err
+	}
+	if len(p.secret) < 1 {
+		return p, fmt.Errorf("account %q has no secret", p.name)
+	}
+
+	return p, nil
+}
+
+// URL returns the otpauth:// URL for these parameters.
+func (p otpParameters) URL() string {
+	typ := "totp"
+	if p.typ == 1 {
+		typ = "hotp"
+	}
+
+	v := url.Values{}
+	v.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(p.secret))
+	if p.issuer != "" {
+		v.Set("issuer", p.issuer)
+	}
+	switch p.algorithm {
+	case 2:
+		v.Set("algorithm", "SHA256")
+	case 3:
+		v.Set("algorithm", "SHA512")
+	case 4:
+		v.Set("algorithm", "MD5")
+	}
+	if p.digits == 2 {
+		v.Set("digits", "8")
+	}
+	if typ == "hotp" {
+		v.Set("counter", strconv.FormatUint(p.counter, 10))
+	}
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     typ,
+		Path:     "/" + p.name,
+		RawQuery: v.Encode(),
+	}
+
+	return u.String()
+}
+
+// walkFields calls fn for each field of the protobuf message in buf. Length
+// delimited fields are passed as val, varints as v. Other wire types are
+// skipped.
+func walkFields(buf []byte, fn func(num int, val []byte, v uint64) error) error {
+	for len(buf) > 0 {
+		key, n := varint(buf)
+		if n < 1 {
+			return fmt.Errorf("invalid field key")
+		}
+		buf = buf[n:]
+
+		num := int(key >> 3)
+		switch key & 7 {
+		case 0: // varint
+			v, n := varint(buf)
+			if n < 1 {
+				return fmt.Errorf("invalid varint in field %d", num)
+			}
+			buf = buf[n:]
+			if err := fn(num, nil, v); err != nil {
+				return err
+			}
+		case 1: // 64-bit
+			if len(buf) < 8 {
+				return fmt.Errorf("truncated field %d", num)
+			}
+			buf = buf[8:]
+		case 2: // length delimited
+			l, n := varint(buf)
+			if n < 1 || uint64(len(buf)-n) < l {
+				return fmt.Errorf("truncated field %d", num)
+			}
+			val := buf[n : n+int(l)]
+			buf = buf[n+int(l):]
+			if err := fn(num, val, 0); err != nil {
+				return err
+			}
+		case 5: // 32-bit
+			if len(buf) < 4 {
+				return fmt.Errorf("truncated field %d", num)
+			}
+			buf = buf[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", key&7, num)
+		}
+	}
+
+	return nil
+}
+
+// varint decodes a protobuf varint and returns it with the number of bytes
+// read. n is 0 if buf doesn't contain a valid varint.
+func varint(buf []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(buf) && i < 10; i++ {
+		v |= uint64(buf[i]&0x7f) << (7 * i)
+		if buf[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
+}
@@ -0,0 +1,40 @@
+package otp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMigrationURI(t *testing.T) {
+	// one TOTP account with the secret "Hello!\xde\xad\xbe\xef"
+	uri := "otpauth-migration://offline?data=CjEKCkhlbGxvId6tvu8SGEV4YW1wbGU6YWxpY2VAZ29vZ2xlLmNvbRoHRXhhbXBsZSABKAEwAhABGAEgACgA"
+	urls, err := ParseMigrationURI(uri)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"otpauth://totp/Example:alice@google.com?issuer=Example&secret=JBSWY3DPEHPK3PXP",
+	}, urls)
+
+	for _, tc := range []string{
+		"otpauth://totp/foo?secret=JBSWY3DPEHPK3PXP",
+		"otpauth-migration://offline",
+		"otpauth-migration://offline?data=%%%",
+		"otpauth-migration://offline?data=CjEK",
+	} {
+		_, err := ParseMigrationURI(tc)
+		assert.Error(t, err, tc)
+	}
+}
+
+func TestOTPParametersURL(t *testing.T) {
+	p := otpParameters{
+		secret:    []byte("Hello!"),
+		name:      "foo",
+		algorithm: 2,
+		digits:    2,
+		typ:       1,
+		counter:   42,
+	}
+	assert.Equal(t, "otpauth://hotp/foo?algorithm=SHA256&counter=42&digits=8&secret=JBSWY3DPEE", p.URL())
+}
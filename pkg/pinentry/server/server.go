@@ -0,0 +1,216 @@
+// Package server implements the server side of the pinentry Assuan protocol
+// so gopass can act as a minimal pinentry program for gpg-agent.
+//
+// See https://www.gnupg.org/documentation/manuals/assuan/ and
+// pinentry/pinentry.c for the protocol details.
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	// errCanceled is GPG_ERR_CANCELED with source pinentry.
+	errCanceled = "ERR 83886179 Operation cancelled"
+	// errNotConfirmed is GPG_ERR_NOT_CONFIRMED with source pinentry.
+	errNotConfirmed = "ERR 83886194 Not confirmed"
+	// errUnknownCmd is GPG_ERR_ASS_UNKNOWN_CMD.
+	errUnknownCmd = "ERR 536871187 Unknown IPC command"
+
+	version = "0.1.0"
+)
+
+// ErrCanceled must be returned by a Prompter if the user canceled the input.
+var ErrCanceled = errors.New("operation cancelled")
+
+// Request contains the settings received from gpg-agent before a prompt
+// is requested.
+type Request struct {
+	Title       string
+	Description string
+	Prompt      string
+	Error       string
+	Repeat      string
+	OK          string
+	Cancel      string
+	TTYName     string
+	TTYType     string
+	KeyInfo     string
+}
+
+// Prompter asks the user for input. GetPin must return the PIN entered by
+// the user, Confirm must return true if the user confirmed the request.
+type Prompter interface {
+	GetPin(ctx context.Context, req Request) (string, error)
+	Confirm(ctx context.Context, req Request) (bool, error)
+}
+
+// Serve reads Assuan commands from r and writes the responses to w until
+// the client sends BYE or closes the connection.
+func Serve(ctx context.Context, r io.Reader, w io.Writer, p Prompter) error {
+	s := &session{
+		w: bufio.NewWriter(w),
+		p: p,
+	}
+
+	if err := s.reply("OK Pleased to meet you"); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		cmd, arg, _ := strings.Cut(scanner.Text(), " ")
+		done, err := s.handle(ctx, strings.ToUpper(cmd), arg)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+type session struct {
+	w   *bufio.Writer
+	p   Prompter
+	req Request
+}
+
+func (s *session) reply(lines ...string) error {
+	for _, l := range lines {
+		if _, err := s.w.WriteString(l + "\n"); err != nil {
+			return err
+		}
+	}
+	return s.w.Flush()
+}
+
+func (s *session) data(v string) error {
+	return s.reply("D "+escape(v), "OK")
+}
+
+func (s *session) handle(ctx context.Context, cmd, arg string) (bool, error) {
+	switch cmd {
+	case "":
+		return false, nil
+	case "BYE":
+		return true, s.reply("OK closing connection")
+	case "RESET":
+		s.req = Request{}
+	case "OPTION":
+		s.option(arg)
+	case "SETTITLE":
+		s.req.Title = unescape(arg)
+	case "SETDESC":
+		s.req.Description = unescape(arg)
+	case "SETPROMPT":
+		s.req.Prompt = unescape(arg)
+	case "SETERROR":
+		s.req.Error = unescape(arg)
+	case "SETREPEAT":
+		s.req.Repeat = unescape(arg)
+	case "SETOK":
+		s.req.OK = unescape(arg)
+	case "SETCANCEL":
+		s.req.Cancel = unescape(arg)
+	case "SETKEYINFO":
+		s.req.KeyInfo = unescape(arg)
+	case "SETNOTOK", "SETQUALITYBAR", "SETQUALITYBAR_TT", "SETGENPIN", "SETGENPIN_TT", "SETTIMEOUT", "SETREPEATERROR":
+		// accepted, but not supported
+	case "GETINFO":
+		return false, s.getInfo(arg)
+	case "GETPIN":
+		return false, s.getPin(ctx)
+	case "CONFIRM", "MESSAGE":
+		return false, s.confirm(ctx, cmd == "MESSAGE")
+	default:
+		debug.Log("unknown command: %s", cmd)
+		return false, s.reply(errUnknownCmd)
+	}
+
+	return false, s.reply("OK")
+}
+
+func (s *session) option(arg string) {
+	k, v, _ := strings.Cut(arg, "=")
+	switch strings.TrimPrefix(k, "--") {
+	case "ttyname":
+		s.req.TTYName = v
+	case "ttytype":
+		s.req.TTYType = v
+	}
+}
+
+func (s *session) getInfo(arg string) error {
+	switch arg {
+	case "pid":
+		return s.data(fmt.Sprintf("%d", os.Getpid()))
+	case "version":
+		return s.data(version)
+	case "flavor":
+		return s.data("gopass")
+	case "ttyinfo":
+		return s.data(fmt.Sprintf("%s %s -", s.req.TTYName, s.req.TTYType))
+	default:
+		return s.reply("OK")
+	}
+}
+
+func (s *session) getPin(ctx context.Context) error {
+	pin, err := s.p.GetPin(ctx, s.req)
+	// errors must not be shown again on the next attempt
+	s.req.Error = ""
+	if err != nil {
+		debug.Log("failed to get pin: %s", err)
+		return s.reply(errCanceled)
+	}
+	if pin == "" {
+		return s.reply("OK")
+	}
+	return s.data(pin)
+}
+
+func (s *session) confirm(ctx context.Context, oneButton bool) error {
+	ok, err := s.p.Confirm(ctx, s.req)
+	if err != nil {
+		debug.Log("failed to confirm: %s", err)
+		return s.reply(errCanceled)
+	}
+	if !ok && !oneButton {
+		return s.reply(errNotConfirmed)
+	}
+	return s.reply("OK")
+}
+
+// escape percent-encodes the characters that must not appear in Assuan
+// data lines.
+func escape(in string) string {
+	r := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	return r.Replace(in)
+}
+
+// unescape decodes percent-encoded Assuan parameters.
+func unescape(in string) string {
+	out, err := url.PathUnescape(in)
+	if err != nil {
+		return in
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePrompter struct {
+	pin  string
+	ok   bool
+	err  error
+	last Request
+}
+
+func (f *fakePrompter) GetPin(_ context.Context, req Request) (string, error) {
+	f.last = req
+	return f.pin, f.err
+}
+
+func (f *fakePrompter) Confirm(_ context.Context, req Request) (bool, error) {
+	f.last = req
+	return f.ok, f.err
+}
+
+func TestServe(t *testing.T) {
+	ctx := context.Background()
+
+	in := strings.Join([]string{
+		"OPTION ttyname=/dev/pts/1",
+		"SETDESC Please enter the passphrase%0Afor key 0xDEADBEEF",
+		"SETPROMPT Passphrase:",
+		"GETINFO flavor",
+		"GETPIN",
+		"CONFIRM",
+		"FOOBAR",
+		"BYE",
+		"GETPIN",
+	}, "\n")
+
+	p := &fakePrompter{pin: "secret%\n", ok: false}
+	buf := &bytes.Buffer{}
+	require.NoError(t, Serve(ctx, strings.NewReader(in), buf, p))
+
+	assert.Equal(t, strings.Join([]string{
+		"OK Pleased to meet you",
+		"OK",
+		"OK",
+		"OK",
+		"D gopass",
+		"OK",
+		"D secret%25%0A",
+		"OK",
+		errNotConfirmed,
+		errUnknownCmd,
+		"OK closing connection",
+		"",
+	}, "\n"), buf.String())
+
+	assert.Equal(t, "/dev/pts/1", p.last.TTYName)
+	assert.Equal(t, "Please enter the passphrase\nfor key 0xDEADBEEF", p.last.Description)
+	assert.Equal(t, "Passphrase:", p.last.Prompt)
+}
+
+func TestServeCanceled(t *testing.T) {
+	ctx := context.Background()
+
+	p := &fakePrompter{err: ErrCanceled}
+	buf := &bytes.Buffer{}
+	require.NoError(t, Serve(ctx, strings.NewReader("GETPIN\n"), buf, p))
+	assert.Equal(t, "OK Pleased to meet you\n"+errCanceled+"\n", buf.String())
+}
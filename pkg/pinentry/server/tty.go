@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/termio"
+	"golang.org/x/term"
+)
+
+// TTY is a Prompter that reads the PIN from the terminal gpg-agent told us
+// about (OPTION ttyname). If no terminal is available it falls back to the
+// prompter configured in the context (e.g. zenity), if any.
+type TTY struct{}
+
+// GetPin implements Prompter.
+func (t TTY) GetPin(ctx context.Context, req Request) (string, error) {
+	fh, err := openTTY(req.TTYName)
+	if err != nil {
+		if !termio.HasPrompter(ctx) {
+			return "", err
+		}
+		return termio.GetPrompter(ctx).Password(ctx, req.text())
+	}
+	defer fh.Close()
+
+	if req.Error != "" {
+		fmt.Fprintf(fh, "Error: %s\n", req.Error)
+	}
+	if req.Description != "" {
+		fmt.Fprintln(fh, req.Description)
+	}
+
+	pin, err := readPassword(fh, req.prompt())
+	if err != nil {
+		return "", err
+	}
+	if req.Repeat == "" {
+		return pin, nil
+	}
+
+	again, err := readPassword(fh, req.Repeat)
+	if err != nil {
+		return "", err
+	}
+	if pin != again {
+		fmt.Fprintln(fh, "Error: the entered PINs do not match")
+		return "", ErrCanceled
+	}
+	return pin, nil
+}
+
+// Confirm implements Prompter.
+func (t TTY) Confirm(ctx context.Context, req Request) (bool, error) {
+	fh, err := openTTY(req.TTYName)
+	if err != nil {
+		if !termio.HasPrompter(ctx) {
+			return false, err
+		}
+		return termio.GetPrompter(ctx).Bool(ctx, req.text(), false)
+	}
+	defer fh.Close()
+
+	fmt.Fprintf(fh, "%s [y/N]: ", req.Description)
+	line, err := bufio.NewReader(fh).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(line)), "y"), nil
+}
+
+func (r Request) prompt() string {
+	if r.Prompt != "" {
+		return r.Prompt
+	}
+	return "PIN:"
+}
+
+func (r Request) text() string {
+	if r.Description == "" {
+		return r.prompt()
+	}
+	return r.Description + "\n" + r.prompt()
+}
+
+func openTTY(name string) (*os.File, error) {
+	if name == "" {
+		name = os.Getenv("GPG_TTY")
+	}
+	if name == "" {
+		return nil, errors.New("no terminal available")
+	}
+	return os.OpenFile(name, os.O_RDWR, 0)
+}
+
+func readPassword(fh *os.File, prompt string) (string, error) {
+	fmt.Fprintf(fh, "%s ", prompt)
+	buf, err := term.ReadPassword(int(fh.Fd()))
+	fmt.Fprintln(fh)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
@@ -10,3 +10,13 @@ var ProtectEnabled = false
 func Pledge(s string) error {
 	return nil
 }
+
+// Unveil on any other system than OpenBSD doesn't do anything.
+func Unveil(path, perms string) error {
+	return nil
+}
+
+// UnveilBlock on any other system than OpenBSD doesn't do anything.
+func UnveilBlock() error {
+	return nil
+}
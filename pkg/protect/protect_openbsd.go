@@ -13,3 +13,14 @@ var ProtectEnabled = true
 func Pledge(s string) error {
 	return unix.PledgePromises(s)
 }
+
+// Unveil on OpenBSD makes path accessible with the given permissions and
+// hides everything that isn't unveiled: http://man.openbsd.org/unveil
+func Unveil(path, perms string) error {
+	return unix.Unveil(path, perms)
+}
+
+// UnveilBlock on OpenBSD prevents further calls to Unveil.
+func UnveilBlock() error {
+	return unix.UnveilBlock()
+}
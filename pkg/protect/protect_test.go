@@ -9,3 +9,12 @@ import (
 func TestProtect(t *testing.T) {
 	assert.NoError(t, Pledge(""))
 }
+
+func TestUnveil(t *testing.T) {
+	if ProtectEnabled {
+		t.Skip("would restrict the test binary")
+	}
+
+	assert.NoError(t, Unveil("/", "r"))
+	assert.NoError(t, UnveilBlock())
+}
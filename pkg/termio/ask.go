@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/gopasspw/gopass/internal/i18n"
 	"github.com/gopasspw/gopass/internal/out"
 	"github.com/gopasspw/gopass/pkg/ctxutil"
 )
@@ -28,10 +29,15 @@ const (
 // AskForString asks for a string once, using the default if the
 // answer is empty. Errors are only returned on I/O errors.
 func AskForString(ctx context.Context, text, def string) (string, error) {
-	if ctxutil.IsAlwaysYes(ctx) || !ctxutil.IsInteractive(ctx) {
+	if ctxutil.IsAlwaysYes(ctx) {
 		return def, nil
 	}
 
+	return GetPrompter(ctx).String(ctx, i18n.T(ctx, text), def)
+}
+
+// askForString reads a single line from the terminal.
+func askForString(ctx context.Context, text, def string) (string, error) {
 	// check for context cancelation
 	select {
 	case <-ctx.Done():
@@ -59,33 +65,7 @@ func AskForBool(ctx context.Context, text string, def bool) (bool, error) {
 		return def, nil
 	}
 
-	choices := "y/N/q"
-	if def {
-		choices = "Y/n/q"
-	}
-
-	str, err := AskForString(ctx, text, choices)
-	if err != nil {
-		return false, fmt.Errorf("failed to read user input: %w", err)
-	}
-	switch str {
-	case "Y/n/q":
-		return true, nil
-	case "y/N/q":
-		return false, nil
-	}
-
-	str = strings.ToLower(string(str[0]))
-	switch str {
-	case "y":
-		return true, nil
-	case "n":
-		return false, nil
-	case "q":
-		return false, ErrAborted
-	default:
-		return false, fmt.Errorf("unknown answer: %s", str)
-	}
+	return GetPrompter(ctx).Bool(ctx, i18n.T(ctx, text), def)
 }
 
 // AskForInt asks for an valid interger once. If the input
@@ -137,7 +117,7 @@ func AskForKeyImport(ctx context.Context, key string, names []string) bool {
 		return false
 	}
 
-	ok, err := AskForBool(ctx, fmt.Sprintf("Do you want to import the public key %q (Names: %+v) into your keyring?", key, names), false)
+	ok, err := AskForBool(ctx, i18n.Sprintf(ctx, "Do you want to import the public key %q (Names: %+v) into your keyring?", key, names), false)
 	if err != nil {
 		return false
 	}
@@ -160,7 +140,7 @@ func AskForPassword(ctx context.Context, name string, repeat bool) (string, erro
 		default:
 		}
 
-		pass, err := askFn(ctx, fmt.Sprintf("Enter %s", name))
+		pass, err := askFn(ctx, i18n.Sprintf(ctx, "Enter %s", i18n.T(ctx, name)))
 		if !repeat {
 			return pass, err
 		}
@@ -168,7 +148,7 @@ func AskForPassword(ctx context.Context, name string, repeat bool) (string, erro
 			return "", err
 		}
 
-		passAgain, err := askFn(ctx, fmt.Sprintf("Retype %s", name))
+		passAgain, err := askFn(ctx, i18n.Sprintf(ctx, "Retype %s", i18n.T(ctx, name)))
 		if err != nil {
 			return "", err
 		}
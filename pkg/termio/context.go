@@ -6,6 +6,7 @@ type contextKey int
 
 const (
 	ctxKeyPassPromptFunc contextKey = iota
+	ctxKeyPrompter
 )
 
 // PassPromptFunc is a password prompt function.
@@ -28,7 +29,28 @@ func HasPassPromptFunc(ctx context.Context) bool {
 func GetPassPromptFunc(ctx context.Context) PassPromptFunc {
 	ppf, ok := ctx.Value(ctxKeyPassPromptFunc).(PassPromptFunc)
 	if !ok || ppf == nil {
-		return promptPass
+		return GetPrompter(ctx).Password
 	}
 	return ppf
 }
+
+// WithPrompter returns a context with the prompter set.
+func WithPrompter(ctx context.Context, p Prompter) context.Context {
+	return context.WithValue(ctx, ctxKeyPrompter, p)
+}
+
+// HasPrompter returns true if a prompter has been set in this context.
+func HasPrompter(ctx context.Context) bool {
+	p, ok := ctx.Value(ctxKeyPrompter).(Prompter)
+	return ok && p != nil
+}
+
+// GetPrompter returns the prompter or the default (terminal) one.
+// Note: will never return nil.
+func GetPrompter(ctx context.Context) Prompter {
+	p, ok := ctx.Value(ctxKeyPrompter).(Prompter)
+	if !ok || p == nil {
+		return terminalPrompter{}
+	}
+	return p
+}
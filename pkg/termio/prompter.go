@@ -0,0 +1,243 @@
+package termio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+// ErrNoInteraction is returned by prompters that can not ask the user for
+// input, e.g. the batch prompter.
+var ErrNoInteraction = errors.New("user interaction not possible")
+
+// Prompter is a source of user input. The default implementation uses the
+// terminal but it can be replaced by a GUI dialog or be suppressed entirely
+// to make gopass scriptable.
+type Prompter interface {
+	Name() string
+	String(ctx context.Context, text, def string) (string, error)
+	Bool(ctx context.Context, text string, def bool) (bool, error)
+	Password(ctx context.Context, prompt string) (string, error)
+	Select(ctx context.Context, text string, choices []string) (int, error)
+}
+
+// NewPrompter returns the prompter with the given name. Valid names are
+// terminal, zenity and batch.
+func NewPrompter(name string) (Prompter, error) {
+	switch strings.ToLower(name) {
+	case "", "terminal", "tty":
+		return terminalPrompter{}, nil
+	case "zenity":
+		bin, err := exec.LookPath("zenity")
+		if err != nil {
+			return nil, fmt.Errorf("zenity not found: %w", err)
+		}
+		return zenityPrompter{binary: bin}, nil
+	case "batch", "none":
+		return batchPrompter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown prompter %q", name)
+	}
+}
+
+// PrompterFromEnv returns the prompter selected by GOPASS_PROMPTER or nil
+// if none (or an invalid one) was requested.
+func PrompterFromEnv() Prompter {
+	name := os.Getenv("GOPASS_PROMPTER")
+	if name == "" {
+		return nil
+	}
+	p, err := NewPrompter(name)
+	if err != nil {
+		debug.Log("failed to initialize prompter %q: %s", name, err)
+		return nil
+	}
+	return p
+}
+
+// terminalPrompter reads input from the terminal (or redirected stdin).
+type terminalPrompter struct{}
+
+func (terminalPrompter) Name() string {
+	return "terminal"
+}
+
+func (terminalPrompter) String(ctx context.Context, text, def string) (string, error) {
+	if !ctxutil.IsInteractive(ctx) {
+		return def, nil
+	}
+	return askForString(ctx, text, def)
+}
+
+func (p terminalPrompter) Bool(ctx context.Context, text string, def bool) (bool, error) {
+	choices := "y/N/q"
+	if def {
+		choices = "Y/n/q"
+	}
+
+	str, err := p.String(ctx, text, choices)
+	if err != nil {
+		return false, fmt.Errorf("failed to read user input: %w", err)
+	}
+
+	return parseBool(str)
+}
+
+func (terminalPrompter) Password(ctx context.Context, prompt string) (string, error) {
+	return promptPass(ctx, prompt)
+}
+
+func (p terminalPrompter) Select(ctx context.Context, text string, choices []string) (int, error) {
+	if !ctxutil.IsInteractive(ctx) {
+		return 0, nil
+	}
+	for i, c := range choices {
+		fmt.Fprintf(Stderr, "[%d] %s\n", i, c)
+	}
+	for i := 0; i < maxTries; i++ {
+		str, err := p.String(ctx, text+" (q to abort)", "0")
+		if err != nil {
+			return 0, err
+		}
+		if str == "q" {
+			return 0, ErrAborted
+		}
+		iv, err := strconv.Atoi(str)
+		if err == nil && iv >= 0 && iv < len(choices) {
+			return iv, nil
+		}
+	}
+	return 0, fmt.Errorf("no valid user input")
+}
+
+// batchPrompter never asks. It answers every question with its default and
+// refuses to provide passwords.
+type batchPrompter struct{}
+
+func (batchPrompter) Name() string {
+	return "batch"
+}
+
+func (batchPrompter) String(_ context.Context, _, def string) (string, error) {
+	return def, nil
+}
+
+func (batchPrompter) Bool(_ context.Context, _ string, def bool) (bool, error) {
+	return def, nil
+}
+
+func (batchPrompter) Password(context.Context, string) (string, error) {
+	return "", ErrNoInteraction
+}
+
+func (batchPrompter) Select(context.Context, string, []string) (int, error) {
+	return 0, ErrNoInteraction
+}
+
+// zenityPrompter uses zenity(1) to display GTK dialogs. Useful when gopass is
+// invoked without a terminal, e.g. from a window manager key binding.
+type zenityPrompter struct {
+	binary string
+}
+
+func (zenityPrompter) Name() string {
+	return "zenity"
+}
+
+func (z zenityPrompter) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, z.binary, append([]string{"--title", "gopass"}, args...)...)
+	buf := &bytes.Buffer{}
+	cmd.Stdout = buf
+	debug.Log("%s %+v", cmd.Path, cmd.Args)
+
+	if err := cmd.Run(); err != nil {
+		// zenity exits with 1 if the user pressed cancel.
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && ee.ExitCode() == 1 {
+			return "", ErrAborted
+		}
+		return "", fmt.Errorf("failed to run zenity: %w", err)
+	}
+
+	return strings.TrimRight(buf.String(), "\r\n"), nil
+}
+
+func (z zenityPrompter) String(ctx context.Context, text, def string) (string, error) {
+	str, err := z.run(ctx, "--entry", "--text", text, "--entry-text", def)
+	if err != nil {
+		return "", err
+	}
+	if str == "" {
+		return def, nil
+	}
+	return str, nil
+}
+
+func (z zenityPrompter) Bool(ctx context.Context, text string, def bool) (bool, error) {
+	args := []string{"--question", "--text", text}
+	if !def {
+		args = append(args, "--default-cancel")
+	}
+
+	_, err := z.run(ctx, args...)
+	if errors.Is(err, ErrAborted) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (z zenityPrompter) Password(ctx context.Context, prompt string) (string, error) {
+	return z.run(ctx, "--password", "--text", prompt)
+}
+
+func (z zenityPrompter) Select(ctx context.Context, text string, choices []string) (int, error) {
+	args := []string{"--list", "--text", text, "--column", "#", "--column", "Choice", "--hide-column", "1", "--print-column", "1"}
+	for i, c := range choices {
+		args = append(args, strconv.Itoa(i), c)
+	}
+
+	str, err := z.run(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	iv, err := strconv.Atoi(str)
+	if err != nil || iv < 0 || iv >= len(choices) {
+		return 0, ErrAborted
+	}
+
+	return iv, nil
+}
+
+func parseBool(str string) (bool, error) {
+	switch str {
+	case "Y/n/q":
+		return true, nil
+	case "y/N/q":
+		return false, nil
+	}
+
+	str = strings.ToLower(string(str[0]))
+	switch str {
+	case "y":
+		return true, nil
+	case "n":
+		return false, nil
+	case "q":
+		return false, ErrAborted
+	default:
+		return false, fmt.Errorf("unknown answer: %s", str)
+	}
+}
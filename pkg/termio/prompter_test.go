@@ -0,0 +1,76 @@
+package termio
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopasspw/gopass/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPrompter(t *testing.T) {
+	for _, name := range []string{"", "terminal", "batch", "none"} {
+		p, err := NewPrompter(name)
+		require.NoError(t, err, name)
+		assert.NotNil(t, p, name)
+	}
+
+	_, err := NewPrompter("foobar")
+	assert.Error(t, err)
+}
+
+func TestPrompterContext(t *testing.T) {
+	ctx := context.Background()
+
+	assert.False(t, HasPrompter(ctx))
+	assert.Equal(t, "terminal", GetPrompter(ctx).Name())
+
+	ctx = WithPrompter(ctx, batchPrompter{})
+	assert.True(t, HasPrompter(ctx))
+	assert.Equal(t, "batch", GetPrompter(ctx).Name())
+}
+
+func TestBatchPrompter(t *testing.T) {
+	ctx := context.Background()
+	ctx = WithPrompter(ctx, batchPrompter{})
+
+	sv, err := AskForString(ctx, "test", "foobar")
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", sv)
+
+	bv, err := AskForBool(ctx, "test", true)
+	assert.NoError(t, err)
+	assert.True(t, bv)
+
+	_, err = AskForPassword(ctx, "test", false)
+	assert.ErrorIs(t, err, ErrNoInteraction)
+
+	_, err = GetPrompter(ctx).Select(ctx, "test", []string{"foo", "bar"})
+	assert.ErrorIs(t, err, ErrNoInteraction)
+}
+
+func TestTerminalPrompterSelect(t *testing.T) {
+	buf := &bytes.Buffer{}
+	Stderr = buf
+	defer func() {
+		Stderr = os.Stderr
+		Stdin = os.Stdin
+	}()
+
+	ctx := context.Background()
+	ctx = ctxutil.WithInteractive(ctx, true)
+
+	Stdin = strings.NewReader("5\n1\n")
+	iv, err := terminalPrompter{}.Select(ctx, "pick", []string{"foo", "bar"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, iv)
+	assert.Contains(t, buf.String(), "[1] bar")
+
+	Stdin = strings.NewReader("q\n")
+	_, err = terminalPrompter{}.Select(ctx, "pick", []string{"foo", "bar"})
+	assert.ErrorIs(t, err, ErrAborted)
+}
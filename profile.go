@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/gopasspw/gopass/internal/timing"
+	"github.com/gopasspw/gopass/pkg/debug"
+)
+
+const (
+	cpuProfileName  = "cpu.pprof"
+	heapProfileName = "heap.pprof"
+)
+
+// startProfiling records the timings of the current command. If dir is not
+// empty it also writes CPU and heap profiles into dir. The returned func
+// stops profiling and writes the report to w.
+func startProfiling(ctx context.Context, dir string, w io.Writer) (context.Context, func(), error) {
+	rec := timing.New()
+	ctx = timing.WithRecorder(ctx, rec)
+
+	stopCPU := func() {}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return ctx, nil, fmt.Errorf("failed to create profile dir %s: %w", dir, err)
+		}
+		stop, err := startCPUProfile(filepath.Join(dir, cpuProfileName))
+		if err != nil {
+			return ctx, nil, err
+		}
+		stopCPU = stop
+	}
+
+	return ctx, func() {
+		stopCPU()
+		rec.Report(w)
+		if dir == "" {
+			return
+		}
+		if err := writeHeapProfile(filepath.Join(dir, heapProfileName)); err != nil {
+			fmt.Fprintf(w, "%s\n", err)
+			return
+		}
+		fmt.Fprintf(w, "Wrote CPU and heap profiles to %s. Inspect them with 'go tool pprof'.\n", dir)
+	}, nil
+}
+
+func startCPUProfile(fn string) (func(), error) {
+	f, err := os.Create(fn)
+	if err != nil {
+		return nil, fmt.Errorf("could not create CPU profile at %s: %w", fn, err)
+	}
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("could not start CPU profile: %w", err)
+	}
+
+	return func() {
+		pprof.StopCPUProfile()
+		_ = f.Close()
+		debug.Log("wrote CPU profile to %s", fn)
+	}, nil
+}
+
+func writeHeapProfile(fn string) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return fmt.Errorf("could not write mem profile to %s: %w", fn, err)
+	}
+	defer f.Close()
+
+	runtime.GC() // get up-to-date statistics
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("could not write heap profile: %w", err)
+	}
+	debug.Log("wrote heap profile to %s", fn)
+	return nil
+}
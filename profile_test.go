@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopasspw/gopass/internal/timing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartProfiling(t *testing.T) {
+	t.Run("timings only", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ctx, stop, err := startProfiling(context.Background(), "", buf)
+		require.NoError(t, err)
+		require.NotNil(t, timing.GetRecorder(ctx))
+
+		timing.Start(ctx, timing.Git)()
+		stop()
+		assert.Contains(t, buf.String(), "Timings (total ")
+		assert.Regexp(t, `(?m)^  git +1 calls `, buf.String())
+		assert.NotContains(t, buf.String(), "profiles")
+	})
+
+	t.Run("with pprof", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "profile")
+		buf := &bytes.Buffer{}
+		_, stop, err := startProfiling(context.Background(), dir, buf)
+		require.NoError(t, err)
+
+		stop()
+		assert.Contains(t, buf.String(), "Wrote CPU and heap profiles to "+dir)
+		assert.FileExists(t, filepath.Join(dir, cpuProfileName))
+		assert.FileExists(t, filepath.Join(dir, heapProfileName))
+	})
+}
@@ -18,6 +18,7 @@ func TestBaseConfig(t *testing.T) {
 autoimport: true
 cliptimeout: 45
 exportkeys: false
+keycache: false
 nopager: false
 notifications: true
 parsing: true
@@ -71,6 +72,7 @@ func TestMountConfig(t *testing.T) {
 autoimport: true
 cliptimeout: 45
 exportkeys: false
+keycache: false
 nopager: false
 notifications: true
 parsing: true
@@ -16,7 +16,8 @@ autoimport: true
 cliptimeout: 45
 exportkeys: true
 notifications: true
-parsing: true`
+parsing: true
+version: 1`
 )
 
 var (
@@ -20,6 +20,7 @@ import (
 const (
 	gopassConfig = `
 exportkeys: false
+version: 1
 `
 	keyID = "BE73F104"
 )